@@ -0,0 +1,12 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+/*
+Every route here required authentication and restrict by tenant
+*/
+type ApiKeyController interface {
+	Create(ctx *fiber.Ctx) error
+	GetApiKeys(ctx *fiber.Ctx) error
+	Revoke(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ApiKeyControllerImpl struct {
+	Service service.ApiKeyService
+}
+
+func NewApiKeyControllerImpl(service service.ApiKeyService) ApiKeyController {
+	return &ApiKeyControllerImpl{Service: service}
+}
+
+// Create implements ApiKeyController.
+func (controller *ApiKeyControllerImpl) Create(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	var body struct {
+		Name      string              `json:"name"`
+		Scopes    []model.ApiKeyScope `json:"scopes"`
+		ExpiresAt int64               `json:"expires_at"` // Epoch seconds, 0 never expire
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.Name == "" || len(body.Scopes) == 0 {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	var expiresAt *time.Time
+	if body.ExpiresAt != 0 {
+		expiry := time.Unix(body.ExpiresAt, 0)
+		expiresAt = &expiry
+	}
+
+	created, err := controller.Service.Create(tenantId, body.Name, body.Scopes, expiresAt, userId)
+	if err != nil {
+		return apiKeyError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, created))
+}
+
+// GetApiKeys implements ApiKeyController.
+func (controller *ApiKeyControllerImpl) GetApiKeys(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	apiKeys, err := controller.Service.GetApiKeys(tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"api_keys": apiKeys,
+		}))
+}
+
+// Revoke implements ApiKeyController.
+func (controller *ApiKeyControllerImpl) Revoke(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	var body struct {
+		ApiKeyId int `json:"api_key_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.ApiKeyId == 0 {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	err = controller.Service.Revoke(tenantId, body.ApiKeyId, userId)
+	if err != nil {
+		return apiKeyError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "API key revoked",
+		}))
+}
+
+func apiKeyError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrApiKeyAccessDenied):
+		return ctx.Status(fiber.StatusForbidden).
+			JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+	case errors.Is(err, repository.ErrApiKeyNotFound):
+		return ctx.Status(fiber.StatusNotFound).
+			JSON(common.NewWebResponseError(404, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusBadRequest).
+		JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.ApiKeyRepositoryMock) {
+		apiKeyRepo := repository.NewApiKeyRepositoryMock(&mock.Mock{}).(*repository.ApiKeyRepositoryMock)
+		apiKeyController := NewApiKeyControllerImpl(service.NewApiKeyServiceImpl(apiKeyRepo))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 5)
+			return ctx.Next()
+		})
+		app.Get("/api_keys/:tenantId", apiKeyController.GetApiKeys)
+		app.Post("/api_keys/:tenantId", apiKeyController.Create)
+		app.Delete("/api_keys/:tenantId", apiKeyController.Revoke)
+		return app, apiKeyRepo
+	}
+
+	send := func(app *fiber.App, method string, path string, body string) *http.Response {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		app, apiKeyRepo := newApp()
+		apiKeyRepo.Mock.On("Create", mock.MatchedBy(func(apiKey *model.ApiKey) bool {
+			return apiKey.Name == "Shop"
+		}), 5).Return(&model.ApiKey{Id: 3, TenantId: TENANT_ID, Name: "Shop"}, nil)
+		apiKeyRepo.Mock.On("Create", mock.MatchedBy(func(apiKey *model.ApiKey) bool {
+			return apiKey.Name == "Denied"
+		}), 5).Return(nil, repository.ErrApiKeyAccessDenied)
+
+		assert.Equal(t, http.StatusBadRequest, send(app, "POST", "/api_keys/1", `{"name":"Shop"}`).StatusCode)
+		assert.Equal(t, http.StatusBadRequest, send(app, "POST", "/api_keys/1", `{"name":"Shop","scopes":["admin"]}`).StatusCode)
+		assert.Equal(t, http.StatusOK, send(app, "POST", "/api_keys/1", `{"name":"Shop","scopes":["read:sales"]}`).StatusCode)
+		assert.Equal(t, http.StatusForbidden, send(app, "POST", "/api_keys/1", `{"name":"Denied","scopes":["read:sales"]}`).StatusCode)
+	})
+
+	t.Run("GetApiKeys", func(t *testing.T) {
+		app, apiKeyRepo := newApp()
+		apiKeyRepo.Mock.On("GetByTenant", TENANT_ID).Return([]model.ApiKey{{Id: 3, TenantId: TENANT_ID, KeyHash: "secret"}}, nil)
+
+		response := send(app, "GET", "/api_keys/1", "")
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		app, apiKeyRepo := newApp()
+		apiKeyRepo.Mock.On("Revoke", TENANT_ID, 3, 5).Return(nil)
+		apiKeyRepo.Mock.On("Revoke", TENANT_ID, 4, 5).Return(repository.ErrApiKeyNotFound)
+
+		assert.Equal(t, http.StatusBadRequest, send(app, "DELETE", "/api_keys/1", `{}`).StatusCode)
+		assert.Equal(t, http.StatusOK, send(app, "DELETE", "/api_keys/1", `{"api_key_id":3}`).StatusCode)
+		assert.Equal(t, http.StatusNotFound, send(app, "DELETE", "/api_keys/1", `{"api_key_id":4}`).StatusCode)
+	})
+}
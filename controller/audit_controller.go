@@ -0,0 +1,10 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+/*
+Every route here required authentication and restrict by tenant
+*/
+type AuditController interface {
+	Search(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditControllerImpl struct {
+	Service service.AuditService
+}
+
+func NewAuditControllerImpl(service service.AuditService) AuditController {
+	return &AuditControllerImpl{Service: service}
+}
+
+// Search implements AuditController.
+func (controller *AuditControllerImpl) Search(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	sub := ctx.Locals("sub").(int)
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	userId, err := strconv.Atoi(ctx.Query("user_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check user_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	entityId, err := strconv.Atoi(ctx.Query("entity_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check entity_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	startDate, ok := auditDateQuery(ctx, "start_date")
+	if !ok {
+		return nil
+	}
+	endDate, ok := auditDateQuery(ctx, "end_date")
+	if !ok {
+		return nil
+	}
+
+	filter := &model.AuditEventFilter{
+		UserId:     userId,
+		EntityType: ctx.Query("entity_type", ""),
+		EntityId:   entityId,
+		Method:     strings.ToUpper(ctx.Query("method", "")),
+		StartDate:  startDate,
+		EndDate:    endDate,
+	}
+
+	events, count, err := controller.Service.Search(tenantId, filter, limit, page, sub)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuditOwnerOnly) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+		}
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":         page,
+			"limit":        limit,
+			"count":        count,
+			"audit_events": events,
+		}))
+}
+
+// Query epoch seconds (same as query.DateFilter), nil when not given. The error response is already sent when not ok
+func auditDateQuery(ctx *fiber.Ctx, name string) (*time.Time, bool) {
+	if ctx.Query(name, "") == "" {
+		return nil, true
+	}
+
+	epoch, err := strconv.ParseInt(ctx.Query(name), 10, 64)
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Please check "+name+" URL parameter"))
+		return nil, false
+	}
+
+	date := time.Unix(epoch, 0)
+	return &date, true
+}
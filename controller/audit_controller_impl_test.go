@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	auditRepo := repository.NewAuditRepositoryMock(&mock.Mock{}).(*repository.AuditRepositoryMock)
+	auditController := NewAuditControllerImpl(service.NewAuditServiceImpl(auditRepo))
+
+	app := fiber.New()
+	app.Use(func(ctx *fiber.Ctx) error {
+		ctx.Locals("sub", 5)
+		return ctx.Next()
+	})
+	app.Get("/audit_events/:tenantId", auditController.Search)
+
+	auditRepo.Mock.On("Search", TENANT_ID, mock.MatchedBy(func(filter *model.AuditEventFilter) bool {
+		return filter.UserId == 7 && filter.EntityType == "store" && filter.EntityId == 3 && filter.Method == "PUT" &&
+			filter.StartDate != nil && filter.StartDate.Unix() == 1700000000 && filter.EndDate == nil
+	}), 10, 0, 5).Return([]*model.AuditEvent{{Id: 1}}, 1, nil)
+	auditRepo.Mock.On("Search", TENANT_ID, mock.MatchedBy(func(filter *model.AuditEventFilter) bool {
+		return filter.UserId == 0
+	}), 10, 0, 5).Return(nil, 0, repository.ErrAuditOwnerOnly)
+
+	cases := []struct {
+		name   string
+		url    string
+		status int
+	}{
+		{"Filtered", "/audit_events/1?user_id=7&entity_type=store&entity_id=3&method=put&start_date=1700000000", http.StatusOK},
+		{"NotOwner", "/audit_events/1", http.StatusForbidden},
+		{"BadUserId", "/audit_events/1?user_id=abc", http.StatusBadRequest},
+		{"BadDate", "/audit_events/1?end_date=yesterday", http.StatusBadRequest},
+		{"BadLimit", "/audit_events/1?limit=0", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			response, err := app.Test(httptest.NewRequest("GET", c.url, nil), testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, c.status, response.StatusCode)
+		})
+	}
+}
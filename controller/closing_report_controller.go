@@ -0,0 +1,25 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type ClosingReportController interface {
+	/*
+		Print the X-report of a store (business day so far)
+	*/
+	GenerateXReport(ctx *fiber.Ctx) error
+
+	/*
+		Close the business day of a store with a Z-report
+	*/
+	GenerateZReport(ctx *fiber.Ctx) error
+
+	/*
+		Reprint 1 stored report
+	*/
+	FindById(ctx *fiber.Ctx) error
+
+	/*
+		Get the stored reports of a store
+	*/
+	Get(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ClosingReportControllerImpl struct {
+	Service service.ClosingReportService
+}
+
+func NewClosingReportControllerImpl(service service.ClosingReportService) ClosingReportController {
+	return &ClosingReportControllerImpl{Service: service}
+}
+
+// generate parse the request of X and Z report, both only need the store
+func (controller *ClosingReportControllerImpl) generate(
+	ctx *fiber.Ctx,
+	generateFunc func(tenantId, storeId, userId int) (*model.ClosingReport, error),
+) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body struct {
+		StoreId int `json:"store_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	report, err := generateFunc(tenantId, body.StoreId, userId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
+			"closing_report": report,
+		}))
+}
+
+// GenerateXReport implements ClosingReportController.
+func (controller *ClosingReportControllerImpl) GenerateXReport(ctx *fiber.Ctx) error {
+	return controller.generate(ctx, controller.Service.GenerateXReport)
+}
+
+// GenerateZReport implements ClosingReportController.
+func (controller *ClosingReportControllerImpl) GenerateZReport(ctx *fiber.Ctx) error {
+	return controller.generate(ctx, controller.Service.GenerateZReport)
+}
+
+// FindById implements ClosingReportController.
+func (controller *ClosingReportControllerImpl) FindById(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	closingReportId, err := strconv.Atoi(ctx.Query("closing_report_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check closing_report_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	report, err := controller.Service.FindById(closingReportId, tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"closing_report": report,
+		}))
+}
+
+// Get implements ClosingReportController.
+func (controller *ClosingReportControllerImpl) Get(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	reportType := model.ClosingReportType(ctx.Query("type", ""))
+
+	reports, count, err := controller.Service.Get(tenantId, storeId, reportType, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":            page,
+			"limit":           limit,
+			"count":           count,
+			"closing_reports": reports,
+		}))
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClosingReportControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const STORE_ID = 1
+	const USER_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.ClosingReportRepositoryMock) {
+		closingReportRepo := repository.NewClosingReportRepositoryMock(&mock.Mock{}).(*repository.ClosingReportRepositoryMock)
+		closingReportController := NewClosingReportControllerImpl(service.NewClosingReportServiceImpl(closingReportRepo))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", USER_ID)
+			return ctx.Next()
+		})
+		app.Get("/closing_reports/details/:tenantId", closingReportController.FindById)
+		app.Get("/closing_reports/:tenantId", closingReportController.Get)
+		app.Post("/closing_reports/x/:tenantId", closingReportController.GenerateXReport)
+		app.Post("/closing_reports/z/:tenantId", closingReportController.GenerateZReport)
+		return app, closingReportRepo
+	}
+
+	t.Run("GenerateZReport", func(t *testing.T) {
+		t.Run("NormalClose", func(t *testing.T) {
+			app, closingReportRepo := newApp()
+			zNumber := 1
+			closingReportRepo.Mock.On("Generate", TENANT_ID, STORE_ID, USER_ID, model.ClosingReportTypeZ, mock.AnythingOfType("time.Time")).
+				Return(&model.ClosingReport{Id: 1, Type: model.ClosingReportTypeZ, ZNumber: &zNumber, TransactionCount: 5}, nil)
+
+			body := strings.NewReader(fmt.Sprintf(`{"store_id":%d}`, STORE_ID))
+			request := httptest.NewRequest("POST", fmt.Sprintf("/closing_reports/z/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					ClosingReport *model.ClosingReport `json:"closing_report"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 1, *responseBody.Data.ClosingReport.ZNumber)
+			assert.Equal(t, 5, responseBody.Data.ClosingReport.TransactionCount)
+		})
+
+		t.Run("AlreadyClosed", func(t *testing.T) {
+			app, closingReportRepo := newApp()
+			closingReportRepo.Mock.On("Generate", TENANT_ID, STORE_ID, USER_ID, model.ClosingReportTypeZ, mock.AnythingOfType("time.Time")).
+				Return(nil, errors.New("already closed"))
+
+			body := strings.NewReader(fmt.Sprintf(`{"store_id":%d}`, STORE_ID))
+			request := httptest.NewRequest("POST", fmt.Sprintf("/closing_reports/z/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("GenerateXReport", func(t *testing.T) {
+		app, closingReportRepo := newApp()
+		closingReportRepo.Mock.On("Generate", TENANT_ID, STORE_ID, USER_ID, model.ClosingReportTypeX, mock.AnythingOfType("time.Time")).
+			Return(&model.ClosingReport{Id: 2, Type: model.ClosingReportTypeX}, nil)
+
+		body := strings.NewReader(fmt.Sprintf(`{"store_id":%d}`, STORE_ID))
+		request := httptest.NewRequest("POST", fmt.Sprintf("/closing_reports/x/%d", TENANT_ID), body)
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		closingReportRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, closingReportRepo := newApp()
+			closingReportRepo.Mock.On("Get", TENANT_ID, STORE_ID, model.ClosingReportTypeZ, 10, 0).
+				Return([]*model.ClosingReport{{Id: 1}}, 1, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/closing_reports/%d?store_id=%d&type=Z", TENANT_ID, STORE_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+		})
+
+		t.Run("InvalidStoreId", func(t *testing.T) {
+			app, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/closing_reports/%d?store_id=abc", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("FindById", func(t *testing.T) {
+		app, closingReportRepo := newApp()
+		closingReportRepo.Mock.On("FindById", 1, TENANT_ID).Return(&model.ClosingReport{Id: 1}, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/closing_reports/details/%d?closing_report_id=1", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+}
@@ -0,0 +1,25 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type CustomerController interface {
+	/*
+		Get the list of customer, search by phone number
+	*/
+	Get(ctx *fiber.Ctx) error
+
+	/*
+		Create new customer
+	*/
+	Create(ctx *fiber.Ctx) error
+
+	/*
+		Edit customer properties (name, phone, email, notes)
+	*/
+	Edit(ctx *fiber.Ctx) error
+
+	/*
+		Customer with lifetime spend, visit count and paginated invoices
+	*/
+	GetDetail(ctx *fiber.Ctx) error
+}
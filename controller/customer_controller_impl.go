@@ -0,0 +1,164 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CustomerControllerImpl struct {
+	Service service.CustomerService
+}
+
+func NewCustomerControllerImpl(service service.CustomerService) CustomerController {
+	return &CustomerControllerImpl{Service: service}
+}
+
+// Get implements CustomerController.
+func (controller *CustomerControllerImpl) Get(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	paramLimit := ctx.Query("limit", "10") // default 10
+	paramPage := ctx.Query("page", "1")    // default 1
+	phoneQuery := ctx.Query("phone_query", "")
+
+	limit, err := strconv.Atoi(paramLimit)
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(paramPage)
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	customers, count, err := controller.Service.Get(tenantId, limit, page, phoneQuery)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":                   page,
+			"limit":                  limit,
+			"count":                  count,
+			"customers":              customers,
+			"requested_by_tenant_id": tenantId,
+		}))
+}
+
+// Create implements CustomerController.
+func (controller *CustomerControllerImpl) Create(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		Name  string `json:"name"`
+		Phone string `json:"phone"`
+		Email string `json:"email"`
+		Notes string `json:"notes"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	createdCustomer, err := controller.Service.Create(&model.Customer{
+		Name:     body.Name,
+		Phone:    body.Phone,
+		Email:    body.Email,
+		Notes:    body.Notes,
+		TenantId: tenantId,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
+			"created_customer": createdCustomer,
+		}))
+}
+
+// Edit implements CustomerController.
+func (controller *CustomerControllerImpl) Edit(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		CustomerId int    `json:"customer_id"`
+		Name       string `json:"name"`
+		Phone      string `json:"phone"`
+		Email      string `json:"email"`
+		Notes      string `json:"notes"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	editedCustomer, err := controller.Service.Edit(&model.Customer{
+		Id:       body.CustomerId,
+		Name:     body.Name,
+		Phone:    body.Phone,
+		Email:    body.Email,
+		Notes:    body.Notes,
+		TenantId: tenantId,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"edited_customer": editedCustomer,
+		}))
+}
+
+// GetDetail implements CustomerController.
+func (controller *CustomerControllerImpl) GetDetail(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	customerId, err := strconv.Atoi(ctx.Query("customer_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check customer_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	detail, err := controller.Service.GetDetail(customerId, tenantId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":     page,
+			"limit":    limit,
+			"customer": detail.Customer,
+			"stats":    detail.Stats,
+			"invoices": detail.Invoices,
+			"count":    detail.TotalCount,
+		}))
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	/*
+		Repository is mocked, the service is the real implementation.
+		ProtectedRoute & RestrictByTenant are tested at middleware package,
+		here only simulate the "sub" that is given by ProtectedRoute
+	*/
+	newApp := func() (*fiber.App, *repository.CustomerRepositoryMock, *repository.OrderItemRepositoryMock) {
+		customerRepo := repository.NewCustomerRepositoryMock(&mock.Mock{}).(*repository.CustomerRepositoryMock)
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		customerController := NewCustomerControllerImpl(service.NewCustomerServiceImpl(customerRepo, orderItemRepo))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 1)
+			return ctx.Next()
+		})
+		app.Get("/customers/details/:tenantId", customerController.GetDetail)
+		app.Get("/customers/:tenantId", customerController.Get)
+		app.Post("/customers/:tenantId", customerController.Create)
+		app.Put("/customers/:tenantId", customerController.Edit)
+		return app, customerRepo, orderItemRepo
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, customerRepo, _ := newApp()
+			customerRepo.Mock.On("Get", TENANT_ID, 10, 0, "0812").
+				Return([]*model.Customer{{Id: 1, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID}}, 1, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/customers/%d?phone_query=0812", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Count     int               `json:"count"`
+					Customers []*model.Customer `json:"customers"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 1, responseBody.Data.Count)
+			assert.Equal(t, "Budi", responseBody.Data.Customers[0].Name)
+		})
+
+		t.Run("InvalidLimit", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/customers/%d?limit=abc", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("NormalCreate", func(t *testing.T) {
+			app, customerRepo, _ := newApp()
+			customerRepo.Mock.On("Create", mock.MatchedBy(func(c *model.Customer) bool {
+				return c.TenantId == TENANT_ID && c.Phone == "081234567890"
+			})).Return(&model.Customer{Id: 1, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID}, nil)
+
+			body := strings.NewReader(`{"name": "Budi", "phone": "0812-3456-7890"}`)
+			request := httptest.NewRequest("POST", fmt.Sprintf("/customers/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusCreated, response.StatusCode)
+		})
+
+		t.Run("InvalidPhone", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			body := strings.NewReader(`{"name": "Budi", "phone": "not a phone"}`)
+			request := httptest.NewRequest("POST", fmt.Sprintf("/customers/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+
+		t.Run("MalformedBody", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			body := strings.NewReader(`{"name": 1}`)
+			request := httptest.NewRequest("POST", fmt.Sprintf("/customers/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		t.Run("NotFound", func(t *testing.T) {
+			app, customerRepo, _ := newApp()
+			customerRepo.Mock.On("Edit", mock.Anything).Return(nil, errors.New("No customer found with tenant_id=1 and id=99"))
+
+			body := strings.NewReader(`{"customer_id": 99, "name": "Budi", "phone": "081234567890"}`)
+			request := httptest.NewRequest("PUT", fmt.Sprintf("/customers/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("GetDetail", func(t *testing.T) {
+		t.Run("NormalGetDetail", func(t *testing.T) {
+			app, customerRepo, orderItemRepo := newApp()
+			customerId := 7
+			customerRepo.Mock.On("FindById", customerId, TENANT_ID).
+				Return(&model.Customer{Id: customerId, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID}, nil)
+			customerRepo.Mock.On("GetStats", customerId, TENANT_ID).
+				Return(&model.CustomerStats{LifetimeSpend: 50000, VisitCount: 1}, nil)
+			orderItemRepo.Mock.On("Get", TENANT_ID, 0, customerId, 10, 0, mock.Anything, (*query.DateFilter)(nil)).
+				Return([]*model.OrderItem{{Id: 1, TotalAmount: 50000, CustomerId: &customerId}}, 1, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/customers/details/%d?customer_id=%d", TENANT_ID, customerId), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Stats    *model.CustomerStats `json:"stats"`
+					Invoices []*model.OrderItem   `json:"invoices"`
+					Count    int                  `json:"count"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 50000, responseBody.Data.Stats.LifetimeSpend)
+			assert.Equal(t, 1, responseBody.Data.Stats.VisitCount)
+			assert.Len(t, responseBody.Data.Invoices, 1)
+			assert.Equal(t, 1, responseBody.Data.Count)
+		})
+
+		t.Run("MissingCustomerId", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/customers/details/%d", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+}
@@ -0,0 +1,11 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type ExportController interface {
+	/*
+		Download a list or a report as CSV, XLSX or PDF,
+		the file is streamed while the rows are read
+	*/
+	Export(ctx *fiber.Ctx) error
+}
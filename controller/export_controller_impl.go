@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"bufio"
+	common "cashier-api/helper"
+	"cashier-api/helper/export"
+	"cashier-api/helper/query"
+	"cashier-api/service"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+The server WriteTimeout (see main.go) is too short for an export read page by page,
+the deadline of the connection is pushed back on every write instead
+*/
+const exportWriteTimeout = 30 * time.Second
+
+type deadlineWriter struct {
+	conn   net.Conn
+	writer io.Writer
+}
+
+func (w *deadlineWriter) Write(p []byte) (int, error) {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		return 0, err
+	}
+	return w.writer.Write(p)
+}
+
+type ExportControllerImpl struct {
+	Service service.ExportService
+}
+
+func NewExportControllerImpl(service service.ExportService) ExportController {
+	return &ExportControllerImpl{Service: service}
+}
+
+// Export implements ExportController.
+func (controller *ExportControllerImpl) Export(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// start_date and end_date are unix second, both are optional
+	var dateFilter *query.DateFilter
+	for _, param := range []string{"start_date", "end_date"} {
+		value := ctx.Query(param)
+		if value == "" {
+			continue
+		}
+		unix, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, fmt.Sprintf("Please check %s URL parameter", param))
+			return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		}
+		if dateFilter == nil {
+			dateFilter = &query.DateFilter{Column: "created_at"}
+		}
+		if param == "start_date" {
+			dateFilter.StartDate = &unix
+		} else {
+			dateFilter.EndDate = &unix
+		}
+	}
+
+	exp, err := controller.Service.Prepare(&service.ExportRequest{
+		TenantId:   tenantId,
+		StoreId:    storeId,
+		Resource:   service.ExportResource(ctx.Query("resource")),
+		Format:     export.Format(ctx.Query("format", string(export.FormatCSV))),
+		DateFilter: dateFilter,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	ctx.Set("Content-Type", exp.ContentType)
+	ctx.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, exp.FileName))
+	conn := ctx.Context().Conn()
+	ctx.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Status is already sent, the error could only be logged
+		if err := exp.Write(&deadlineWriter{conn: conn, writer: w}); err != nil {
+			log.Errorf("export %s of tenant %d failed: %v", exp.FileName, tenantId, err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"bytes"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.OrderItemRepositoryMock, *repository.CategoryRepositoryMock) {
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		categoryRepo := repository.NewCategoryRepositoryMock(&mock.Mock{}).(*repository.CategoryRepositoryMock)
+		exportService := service.NewExportServiceImpl(
+			service.NewOrderItemServiceImpl(orderItemRepo),
+			service.NewReportServiceImpl(repository.NewReportRepositoryMock(&mock.Mock{})), orderItemRepo,
+			repository.NewStoreStockRepositoryMock(&mock.Mock{}), nil, categoryRepo,
+			repository.NewTenantRepositoryMock(&mock.Mock{}),
+		)
+		exportController := NewExportControllerImpl(exportService)
+
+		app := fiber.New()
+		app.Get("/exports/:tenantId", exportController.Export)
+		return app, orderItemRepo, categoryRepo
+	}
+
+	t.Run("Export", func(t *testing.T) {
+		t.Run("NormalExport", func(t *testing.T) {
+			app, orderItemRepo, categoryRepo := newApp()
+			orderItemRepo.Mock.On("GetTenantAndStoreName", TENANT_ID, 0).Return("Tenant", "All Stores", nil)
+			categoryRepo.Mock.On("Get", TENANT_ID, 0, mock.Anything, "").
+				Return([]*model.Category{{Id: 1, CategoryName: "Drinks"}}, 1, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/exports/%d?resource=categories&format=csv", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, `attachment; filename="categories.csv"`, response.Header.Get("Content-Disposition"))
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(byteBody), "1,Drinks,")
+		})
+
+		t.Run("InvalidFormat", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/exports/%d?resource=categories&format=docx", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+
+		t.Run("InvalidDate", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/exports/%d?resource=order_items&start_date=yesterday", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("DeadlineWriter", func(t *testing.T) {
+		conn := &deadlineConn{}
+		var buffer bytes.Buffer
+		writer := &deadlineWriter{conn: conn, writer: &buffer}
+
+		// Every page written push back the deadline, a long export is not cut by the server WriteTimeout
+		_, err := writer.Write([]byte("page 1\n"))
+		require.NoError(t, err)
+		first := conn.deadline
+		assert.WithinDuration(t, time.Now().Add(exportWriteTimeout), first, time.Second)
+
+		time.Sleep(10 * time.Millisecond)
+		_, err = writer.Write([]byte("page 2\n"))
+		require.NoError(t, err)
+		assert.True(t, conn.deadline.After(first))
+		assert.Equal(t, "page 1\npage 2\n", buffer.String())
+	})
+}
+
+type deadlineConn struct {
+	net.Conn
+	deadline time.Time
+}
+
+func (conn *deadlineConn) SetWriteDeadline(deadline time.Time) error {
+	conn.deadline = deadline
+	return nil
+}
@@ -0,0 +1,35 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type GiftCardController interface {
+	/*
+		Issue new gift card (inactive until activated)
+	*/
+	Issue(ctx *fiber.Ctx) error
+
+	/*
+		Activate (sell) gift card at a store
+	*/
+	Activate(ctx *fiber.Ctx) error
+
+	/*
+		Balance enquiry by code
+	*/
+	GetBalance(ctx *fiber.Ctx) error
+
+	/*
+		Issue store credit from a refund
+	*/
+	IssueStoreCredit(ctx *fiber.Ctx) error
+
+	/*
+		Card with paginated movements
+	*/
+	GetStatement(ctx *fiber.Ctx) error
+
+	/*
+		Outstanding balance grouped by card type
+	*/
+	GetLiability(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type GiftCardControllerImpl struct {
+	Service service.GiftCardService
+}
+
+func NewGiftCardControllerImpl(service service.GiftCardService) GiftCardController {
+	return &GiftCardControllerImpl{Service: service}
+}
+
+// Issue implements GiftCardController.
+func (controller *GiftCardControllerImpl) Issue(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		Code           string `json:"code"` // optional, generated when empty
+		InitialBalance int    `json:"initial_balance"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	issuedGiftCard, err := controller.Service.Issue(&model.GiftCard{
+		TenantId:       tenantId,
+		Code:           body.Code,
+		InitialBalance: body.InitialBalance,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
+			"issued_gift_card": issuedGiftCard,
+		}))
+}
+
+// Activate implements GiftCardController.
+func (controller *GiftCardControllerImpl) Activate(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body struct {
+		Code    string `json:"code"`
+		StoreId int    `json:"store_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	activatedGiftCard, err := controller.Service.Activate(body.Code, tenantId, body.StoreId, userId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"activated_gift_card": activatedGiftCard,
+		}))
+}
+
+// GetBalance implements GiftCardController.
+func (controller *GiftCardControllerImpl) GetBalance(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	giftCard, err := controller.Service.GetBalance(ctx.Query("code", ""), tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"code":    giftCard.Code,
+			"type":    giftCard.Type,
+			"status":  giftCard.Status,
+			"balance": giftCard.Balance,
+		}))
+}
+
+// IssueStoreCredit implements GiftCardController.
+func (controller *GiftCardControllerImpl) IssueStoreCredit(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body struct {
+		Amount      int    `json:"amount"`
+		CustomerId  *int   `json:"customer_id"`   // optional
+		OrderItemId *int   `json:"order_item_id"` // optional, the refunded invoice
+		Note        string `json:"note"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	storeCredit, err := controller.Service.IssueStoreCredit(&model.GiftCard{
+		TenantId:       tenantId,
+		InitialBalance: body.Amount,
+		CustomerId:     body.CustomerId,
+	}, body.OrderItemId, userId, body.Note)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
+			"issued_store_credit": storeCredit,
+		}))
+}
+
+// GetStatement implements GiftCardController.
+func (controller *GiftCardControllerImpl) GetStatement(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	statement, err := controller.Service.GetStatement(ctx.Query("code", ""), tenantId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":      page,
+			"limit":     limit,
+			"gift_card": statement.GiftCard,
+			"movements": statement.Movements,
+			"count":     statement.TotalCount,
+		}))
+}
+
+// GetLiability implements GiftCardController.
+func (controller *GiftCardControllerImpl) GetLiability(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	liabilities, err := controller.Service.GetLiability(tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	total := 0
+	for _, liability := range liabilities {
+		total += liability.OutstandingBalance
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"liabilities":       liabilities,
+			"total_outstanding": total,
+		}))
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiftCardControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const USER_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.GiftCardRepositoryMock) {
+		giftCardRepo := repository.NewGiftCardRepositoryMock(&mock.Mock{}).(*repository.GiftCardRepositoryMock)
+		giftCardController := NewGiftCardControllerImpl(service.NewGiftCardServiceImpl(giftCardRepo))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", USER_ID)
+			return ctx.Next()
+		})
+		app.Get("/gift_cards/balance/:tenantId", giftCardController.GetBalance)
+		app.Get("/gift_cards/movements/:tenantId", giftCardController.GetStatement)
+		app.Get("/gift_cards/liability/:tenantId", giftCardController.GetLiability)
+		app.Post("/gift_cards/store_credit/:tenantId", giftCardController.IssueStoreCredit)
+		app.Post("/gift_cards/:tenantId", giftCardController.Issue)
+		app.Put("/gift_cards/activate/:tenantId", giftCardController.Activate)
+		return app, giftCardRepo
+	}
+
+	sendJSON := func(app *fiber.App, method, url, body string) *http.Response {
+		request := httptest.NewRequest(method, url, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("Issue", func(t *testing.T) {
+		app, giftCardRepo := newApp()
+		giftCardRepo.Mock.On("Create", mock.Anything).
+			Return(&model.GiftCard{Id: 1, Code: "ABCD2345EFGH6789", InitialBalance: 100_000}, nil)
+
+		response := sendJSON(app, "POST", fmt.Sprintf("/gift_cards/%d", TENANT_ID), `{"initial_balance":100000}`)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+		response = sendJSON(app, "POST", fmt.Sprintf("/gift_cards/%d", TENANT_ID), `{"initial_balance":0}`)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("Activate", func(t *testing.T) {
+		app, giftCardRepo := newApp()
+		giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).Return(&model.GiftCard{Id: 1}, nil)
+		giftCardRepo.Mock.On("Activate", 1, TENANT_ID, 2, USER_ID).
+			Return(&model.GiftCard{Id: 1, Status: model.GiftCardStatusActive}, nil)
+
+		response := sendJSON(app, "PUT", fmt.Sprintf("/gift_cards/activate/%d", TENANT_ID), `{"code":"ABCD-2345-EFGH-6789","store_id":2}`)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("GetBalance", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, giftCardRepo := newApp()
+			giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).
+				Return(&model.GiftCard{Id: 1, Code: "ABCD2345EFGH6789", Status: model.GiftCardStatusActive, Balance: 40_000}, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/gift_cards/balance/%d?code=ABCD2345EFGH6789", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Balance int    `json:"balance"`
+					Status  string `json:"status"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 40_000, responseBody.Data.Balance)
+			assert.Equal(t, "ACTIVE", responseBody.Data.Status)
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			app, giftCardRepo := newApp()
+			giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).Return(nil, errors.New("No gift card found"))
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/gift_cards/balance/%d?code=ABCD2345EFGH6789", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("IssueStoreCredit", func(t *testing.T) {
+		app, giftCardRepo := newApp()
+		orderItemId := 5
+		giftCardRepo.Mock.On("IssueStoreCredit", mock.Anything, &orderItemId, USER_ID, "Refund").
+			Return(&model.GiftCard{Id: 1, Type: model.GiftCardTypeStoreCredit, Balance: 20_000}, nil)
+
+		response := sendJSON(app, "POST", fmt.Sprintf("/gift_cards/store_credit/%d", TENANT_ID), `{"amount":20000,"order_item_id":5,"note":"Refund"}`)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+		response = sendJSON(app, "POST", fmt.Sprintf("/gift_cards/store_credit/%d", TENANT_ID), `{`)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("GetStatement", func(t *testing.T) {
+		app, giftCardRepo := newApp()
+		giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).Return(&model.GiftCard{Id: 1}, nil)
+		giftCardRepo.Mock.On("GetMovements", 1, TENANT_ID, 10, 0).
+			Return([]*model.GiftCardMovement{{Id: 1, Type: model.GiftCardMovementActivate, Amount: 100_000}}, 1, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/gift_cards/movements/%d?code=ABCD2345EFGH6789", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		request = httptest.NewRequest("GET", fmt.Sprintf("/gift_cards/movements/%d?code=ABCD2345EFGH6789&page=abc", TENANT_ID), nil)
+		response, err = app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("GetLiability", func(t *testing.T) {
+		app, giftCardRepo := newApp()
+		giftCardRepo.Mock.On("GetLiability", TENANT_ID).Return([]*model.GiftCardLiability{
+			{Type: model.GiftCardTypeGiftCard, CardCount: 2, OutstandingBalance: 140_000},
+			{Type: model.GiftCardTypeStoreCredit, CardCount: 1, OutstandingBalance: 20_000},
+		}, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/gift_cards/liability/%d", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var responseBody struct {
+			Data struct {
+				TotalOutstanding int `json:"total_outstanding"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+		assert.Equal(t, 160_000, responseBody.Data.TotalOutstanding)
+	})
+}
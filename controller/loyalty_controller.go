@@ -0,0 +1,20 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type LoyaltyController interface {
+	/*
+		Get the tenant loyalty setting
+	*/
+	GetSetting(ctx *fiber.Ctx) error
+
+	/*
+		Create or replace the tenant loyalty setting
+	*/
+	SaveSetting(ctx *fiber.Ctx) error
+
+	/*
+		Customer points balance with paginated ledger
+	*/
+	GetStatement(ctx *fiber.Ctx) error
+}
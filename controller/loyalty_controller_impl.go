@@ -0,0 +1,112 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LoyaltyControllerImpl struct {
+	Service service.LoyaltyService
+}
+
+func NewLoyaltyControllerImpl(service service.LoyaltyService) LoyaltyController {
+	return &LoyaltyControllerImpl{Service: service}
+}
+
+// GetSetting implements LoyaltyController.
+func (controller *LoyaltyControllerImpl) GetSetting(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	setting, err := controller.Service.GetSetting(tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"setting": setting,
+		}))
+}
+
+// SaveSetting implements LoyaltyController.
+func (controller *LoyaltyControllerImpl) SaveSetting(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		EarnAmount   int    `json:"earn_amount"`
+		EarnPoints   int    `json:"earn_points"`
+		Rounding     string `json:"rounding"`
+		RedeemValue  int    `json:"redeem_value"`
+		ExpiryMonths int    `json:"expiry_months"`
+		IsActive     bool   `json:"is_active"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	savedSetting, err := controller.Service.SaveSetting(&model.LoyaltySetting{
+		TenantId:     tenantId,
+		EarnAmount:   body.EarnAmount,
+		EarnPoints:   body.EarnPoints,
+		Rounding:     model.LoyaltyRounding(body.Rounding),
+		RedeemValue:  body.RedeemValue,
+		ExpiryMonths: body.ExpiryMonths,
+		IsActive:     body.IsActive,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"saved_setting": savedSetting,
+		}))
+}
+
+// GetStatement implements LoyaltyController.
+func (controller *LoyaltyControllerImpl) GetStatement(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	customerId, err := strconv.Atoi(ctx.Query("customer_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check customer_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	statement, err := controller.Service.GetStatement(customerId, tenantId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":        page,
+			"limit":       limit,
+			"customer_id": statement.CustomerId,
+			"balance":     statement.Balance,
+			"entries":     statement.Entries,
+			"count":       statement.TotalCount,
+		}))
+}
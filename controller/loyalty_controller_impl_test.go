@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const CUSTOMER_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.LoyaltyRepositoryMock, *repository.CustomerRepositoryMock) {
+		loyaltyRepo := repository.NewLoyaltyRepositoryMock(&mock.Mock{}).(*repository.LoyaltyRepositoryMock)
+		customerRepo := repository.NewCustomerRepositoryMock(&mock.Mock{}).(*repository.CustomerRepositoryMock)
+		loyaltyController := NewLoyaltyControllerImpl(service.NewLoyaltyServiceImpl(loyaltyRepo, customerRepo))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 1)
+			return ctx.Next()
+		})
+		app.Get("/loyalty/settings/:tenantId", loyaltyController.GetSetting)
+		app.Put("/loyalty/settings/:tenantId", loyaltyController.SaveSetting)
+		app.Get("/loyalty/statements/:tenantId", loyaltyController.GetStatement)
+		return app, loyaltyRepo, customerRepo
+	}
+
+	t.Run("GetSetting", func(t *testing.T) {
+		app, loyaltyRepo, _ := newApp()
+		loyaltyRepo.Mock.On("GetSetting", TENANT_ID).
+			Return(&model.LoyaltySetting{TenantId: TENANT_ID, EarnAmount: 10_000, EarnPoints: 1, IsActive: true}, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/loyalty/settings/%d", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var responseBody struct {
+			Data struct {
+				Setting *model.LoyaltySetting `json:"setting"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+		assert.True(t, responseBody.Data.Setting.IsActive)
+		assert.Equal(t, 10_000, responseBody.Data.Setting.EarnAmount)
+	})
+
+	t.Run("SaveSetting", func(t *testing.T) {
+		t.Run("NormalSave", func(t *testing.T) {
+			app, loyaltyRepo, _ := newApp()
+			loyaltyRepo.Mock.On("SaveSetting", mock.MatchedBy(func(setting *model.LoyaltySetting) bool {
+				return setting.TenantId == TENANT_ID && setting.Rounding == model.LoyaltyRoundingCeil
+			})).Return(&model.LoyaltySetting{Id: 1, TenantId: TENANT_ID, Rounding: model.LoyaltyRoundingCeil}, nil)
+
+			body := strings.NewReader(`{"earn_amount":10000,"earn_points":1,"rounding":"CEIL","redeem_value":100,"expiry_months":12,"is_active":true}`)
+			request := httptest.NewRequest("PUT", fmt.Sprintf("/loyalty/settings/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+		})
+
+		t.Run("InvalidRounding", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			body := strings.NewReader(`{"earn_amount":10000,"earn_points":1,"rounding":"HALF"}`)
+			request := httptest.NewRequest("PUT", fmt.Sprintf("/loyalty/settings/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+
+		t.Run("MalformedBody", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			request := httptest.NewRequest("PUT", fmt.Sprintf("/loyalty/settings/%d", TENANT_ID), strings.NewReader(`{`))
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("GetStatement", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, loyaltyRepo, customerRepo := newApp()
+			customerRepo.Mock.On("FindById", CUSTOMER_ID, TENANT_ID).Return(&model.Customer{Id: CUSTOMER_ID}, nil)
+			loyaltyRepo.Mock.On("GetBalance", CUSTOMER_ID, TENANT_ID).Return(15, nil)
+			loyaltyRepo.Mock.On("GetLedger", CUSTOMER_ID, TENANT_ID, 10, 0).
+				Return([]*model.LoyaltyLedger{{Id: 1, Type: model.LoyaltyLedgerEarn, Points: 15, RemainingPoints: 15}}, 1, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/loyalty/statements/%d?customer_id=%d", TENANT_ID, CUSTOMER_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Balance int                    `json:"balance"`
+					Count   int                    `json:"count"`
+					Entries []*model.LoyaltyLedger `json:"entries"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 15, responseBody.Data.Balance)
+			assert.Equal(t, 1, responseBody.Data.Count)
+			assert.Len(t, responseBody.Data.Entries, 1)
+		})
+
+		t.Run("MissingCustomerId", func(t *testing.T) {
+			app, _, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/loyalty/statements/%d", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+}
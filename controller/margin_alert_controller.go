@@ -0,0 +1,25 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type MarginAlertController interface {
+	/*
+		Get the tenant margin alert setting
+	*/
+	GetSetting(ctx *fiber.Ctx) error
+
+	/*
+		Create or replace the tenant margin alert setting
+	*/
+	SaveSetting(ctx *fiber.Ctx) error
+
+	/*
+		Paginated store stock priced below the warehouse base price
+	*/
+	GetBelowCostStocks(ctx *fiber.Ctx) error
+
+	/*
+		Items below the tenant margin threshold with the offending invoices
+	*/
+	GetLowMarginItems(ctx *fiber.Ctx) error
+}
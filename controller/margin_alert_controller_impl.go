@@ -0,0 +1,128 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MarginAlertControllerImpl struct {
+	Service service.MarginAlertService
+}
+
+func NewMarginAlertControllerImpl(service service.MarginAlertService) MarginAlertController {
+	return &MarginAlertControllerImpl{Service: service}
+}
+
+// GetSetting implements MarginAlertController.
+func (controller *MarginAlertControllerImpl) GetSetting(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	setting, err := controller.Service.GetSetting(tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"setting": setting,
+		}))
+}
+
+// SaveSetting implements MarginAlertController.
+func (controller *MarginAlertControllerImpl) SaveSetting(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		MinMarginPercent float64 `json:"min_margin_percent"`
+		WindowDays       int     `json:"window_days"`
+		IsActive         bool    `json:"is_active"`
+		Recipients       string  `json:"recipients"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	savedSetting, err := controller.Service.SaveSetting(&model.MarginAlertSetting{
+		TenantId:         tenantId,
+		MinMarginPercent: body.MinMarginPercent,
+		WindowDays:       body.WindowDays,
+		IsActive:         body.IsActive,
+		Recipients:       body.Recipients,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"saved_setting": savedSetting,
+		}))
+}
+
+// GetBelowCostStocks implements MarginAlertController.
+func (controller *MarginAlertControllerImpl) GetBelowCostStocks(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	stocks, count, err := controller.Service.GetBelowCostStocks(tenantId, storeId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":   page,
+			"limit":  limit,
+			"count":  count,
+			"stocks": stocks,
+		}))
+}
+
+// GetLowMarginItems implements MarginAlertController.
+func (controller *MarginAlertControllerImpl) GetLowMarginItems(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	report, err := controller.Service.GetLowMarginItems(tenantId, storeId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"report": report,
+		}))
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarginAlertControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.MarginAlertRepositoryMock) {
+		marginAlertRepo := repository.NewMarginAlertRepositoryMock(&mock.Mock{}).(*repository.MarginAlertRepositoryMock)
+		marginAlertController := NewMarginAlertControllerImpl(service.NewMarginAlertServiceImpl(marginAlertRepo, nil))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 1)
+			return ctx.Next()
+		})
+		app.Get("/margin_alerts/settings/:tenantId", marginAlertController.GetSetting)
+		app.Put("/margin_alerts/settings/:tenantId", marginAlertController.SaveSetting)
+		app.Get("/margin_alerts/below_cost/:tenantId", marginAlertController.GetBelowCostStocks)
+		app.Get("/margin_alerts/low_margin/:tenantId", marginAlertController.GetLowMarginItems)
+		return app, marginAlertRepo
+	}
+
+	t.Run("GetSetting", func(t *testing.T) {
+		app, marginAlertRepo := newApp()
+		marginAlertRepo.Mock.On("GetSetting", TENANT_ID).
+			Return(&model.MarginAlertSetting{TenantId: TENANT_ID, MinMarginPercent: 15, WindowDays: 30}, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/margin_alerts/settings/%d", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var responseBody struct {
+			Data struct {
+				Setting *model.MarginAlertSetting `json:"setting"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+		assert.Equal(t, 15.0, responseBody.Data.Setting.MinMarginPercent)
+	})
+
+	t.Run("SaveSetting", func(t *testing.T) {
+		t.Run("NormalSave", func(t *testing.T) {
+			app, marginAlertRepo := newApp()
+			marginAlertRepo.Mock.On("SaveSetting", mock.MatchedBy(func(setting *model.MarginAlertSetting) bool {
+				return setting.TenantId == TENANT_ID && setting.MinMarginPercent == 12.5 && setting.IsActive
+			})).Return(&model.MarginAlertSetting{Id: 1, TenantId: TENANT_ID, MinMarginPercent: 12.5}, nil)
+
+			body := strings.NewReader(`{"min_margin_percent":12.5,"window_days":30,"is_active":true,"recipients":"owner@example.com"}`)
+			request := httptest.NewRequest("PUT", fmt.Sprintf("/margin_alerts/settings/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+		})
+
+		t.Run("ActiveWithoutRecipient", func(t *testing.T) {
+			app, marginAlertRepo := newApp()
+
+			body := strings.NewReader(`{"min_margin_percent":10,"window_days":30,"is_active":true}`)
+			request := httptest.NewRequest("PUT", fmt.Sprintf("/margin_alerts/settings/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+			marginAlertRepo.Mock.AssertNotCalled(t, "SaveSetting", mock.Anything)
+		})
+	})
+
+	t.Run("GetBelowCostStocks", func(t *testing.T) {
+		app, marginAlertRepo := newApp()
+		marginAlertRepo.Mock.On("GetBelowCostStocks", TENANT_ID, 2, 10, 0).Return([]*repository.BelowCostStockRow{
+			{StoreId: 2, ItemId: 1, Price: 900, BasePrice: 1000, UnitLoss: 100},
+		}, 1, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/margin_alerts/below_cost/%d?store_id=2", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var responseBody struct {
+			Data struct {
+				Count  int                             `json:"count"`
+				Stocks []*repository.BelowCostStockRow `json:"stocks"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+		assert.Equal(t, 1, responseBody.Data.Count)
+		require.Len(t, responseBody.Data.Stocks, 1)
+		assert.Equal(t, 100, responseBody.Data.Stocks[0].UnitLoss)
+	})
+
+	t.Run("GetLowMarginItems", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, marginAlertRepo := newApp()
+			marginAlertRepo.Mock.On("GetSetting", TENANT_ID).
+				Return(&model.MarginAlertSetting{TenantId: TENANT_ID, MinMarginPercent: 10, WindowDays: 7}, nil)
+			marginAlertRepo.Mock.On("GetLowMarginItems", TENANT_ID, 0, mock.Anything, 10.0).Return([]*repository.LowMarginItemRow{
+				{ItemId: 1, TotalRevenue: 1_000, TotalProfit: 50},
+			}, nil)
+			marginAlertRepo.Mock.On("GetLowMarginInvoices", TENANT_ID, 0, mock.Anything, 10.0, []int{1}, mock.Anything).
+				Return([]*repository.LowMarginInvoiceRow{{OrderItemId: 99, ItemId: 1, LineCount: 1}}, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/margin_alerts/low_margin/%d", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Report *repository.LowMarginReport `json:"report"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 7, responseBody.Data.Report.WindowDays)
+			require.Len(t, responseBody.Data.Report.Items, 1)
+			assert.Equal(t, 99, responseBody.Data.Report.Items[0].Invoices[0].OrderItemId)
+		})
+
+		t.Run("InvalidStoreId", func(t *testing.T) {
+			app, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/margin_alerts/low_margin/%d?store_id=abc", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+}
@@ -4,11 +4,25 @@ import "github.com/gofiber/fiber/v2"
 
 type OrderItemController interface {
 	/*
-		When cashier app press the button, then
-		this will called
+		Place an order that is paid later (café), the order start as PLACED
 	*/
 	PlaceOrderItem(ctx *fiber.Ctx) error
 
+	/*
+		Move a placed order into IN_PREPARATION, READY, COMPLETED or CANCELLED
+	*/
+	UpdateOrderStatus(ctx *fiber.Ctx) error
+
+	/*
+		List open orders of a store, oldest first
+	*/
+	GetOpenOrders(ctx *fiber.Ctx) error
+
+	/*
+		Every status change of an order with its user
+	*/
+	GetStatusHistory(ctx *fiber.Ctx) error
+
 	/*
 		Always minus page by 1 because PostgreSQL start index from 0
 	*/
@@ -30,6 +44,21 @@ type OrderItemController interface {
 	*/
 	GetSalesReport(ctx *fiber.Ctx) error
 
+	/*
+		Sales report per hour / day / week / month for dashboard chart
+	*/
+	GetSalesSeries(ctx *fiber.Ctx) error
+
+	/*
+		Sales breakdown per category with share of total revenue
+	*/
+	GetCategoryReport(ctx *fiber.Ctx) error
+
+	/*
+		Side by side sales of every store with share of total
+	*/
+	GetStoreComparison(ctx *fiber.Ctx) error
+
 	/*
 		Generate and stream an Excel (.xlsx) profit report
 	*/
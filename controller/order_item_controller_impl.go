@@ -26,6 +26,7 @@ func NewOrderItemControllerImpl(service service.OrderItemService) OrderItemContr
 type OrderItemControllerGetRequest struct {
 	TenantId   int                  `json:"tenant_id" binding:"required,gt=0"`
 	StoreId    int                  `json:"store_id" binding:"required,gt=0"`
+	CustomerId int                  `json:"customer_id"`
 	Limit      int                  `json:"limit" binding:"required,gte=1,lte=100"`
 	Page       int                  `json:"page" binding:"required,gte=1"`
 	Filters    []*query.QueryFilter `json:"filters"`
@@ -59,7 +60,7 @@ func (controller *OrderItemControllerImpl) Get(ctx *fiber.Ctx) error {
 			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
 	}
 
-	orderItems, count, err := controller.Service.Get(body.TenantId, body.StoreId, body.Limit, body.Page, body.Filters, body.DateFilter)
+	orderItems, count, err := controller.Service.Get(body.TenantId, body.StoreId, body.CustomerId, body.Limit, body.Page, body.Filters, body.DateFilter)
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).
 			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
@@ -77,7 +78,136 @@ func (controller *OrderItemControllerImpl) Get(ctx *fiber.Ctx) error {
 
 // PlaceOrderItem implements OrderItemController.
 func (controller *OrderItemControllerImpl) PlaceOrderItem(ctx *fiber.Ctx) error {
-	panic("unimplemented")
+	// Expected body is the same as Transactions, purchased_price could be 0 (paid when COMPLETED)
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body repository.CreateTransactionParams
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	// History is recorded with the signed in user
+	body.UserId = userId
+	body.TenantId = tenantId
+
+	transactionReturnData, err := controller.Service.PlaceOrderItem(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, transactionReturnData))
+}
+
+// UpdateOrderStatus implements OrderItemController.
+func (controller *OrderItemControllerImpl) UpdateOrderStatus(ctx *fiber.Ctx) error {
+	// Expected body
+	/*
+		{
+			"order_item_id": 1,
+			"status": "IN_PREPARATION" | "READY" | "COMPLETED" | "CANCELLED",
+			"purchased_price": 30_000 // Optional, only when COMPLETED
+		}
+	*/
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body repository.UpdateOrderStatusParams
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	body.UserId = userId
+	body.TenantId = tenantId
+
+	orderItem, err := controller.Service.UpdateOrderStatus(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"order_item": orderItem,
+		}))
+}
+
+// GetOpenOrders implements OrderItemController.
+func (controller *OrderItemControllerImpl) GetOpenOrders(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	orderItems, count, err := controller.Service.GetOpenOrders(tenantId, storeId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":        page,
+			"limit":       limit,
+			"count":       count,
+			"order_items": orderItems,
+		}))
+}
+
+// GetStatusHistory implements OrderItemController.
+func (controller *OrderItemControllerImpl) GetStatusHistory(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	rawOrderItemId := ctx.Query("order_item_id", "")
+	orderItemId, err := strconv.Atoi(rawOrderItemId)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Error while get order_item_id, given error_item_id = %s", rawOrderItemId)
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, errorMsg))
+	}
+
+	history, err := controller.Service.GetStatusHistory(orderItemId, tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"requested_order_item_id": orderItemId,
+			"status_history":          history,
+		}))
 }
 
 // FindById implements OrderItemController.
@@ -133,6 +263,13 @@ func (controller *OrderItemControllerImpl) Transactions(ctx *fiber.Ctx) error {
 				"user_id":   USER_ID,
 				"tenant_id": TENANT_ID,
 				"store_id":  STORE_ID,
+
+				// Optional
+				"customer_id":     CUSTOMER_ID,
+				"redeem_points":   20,
+				"points_discount": 2_000, // redeem_points * loyalty redeem_value
+				"gift_card_code":   "ABCD2345EFGH6789",
+				"gift_card_amount": 5_000, // purchased_price + gift_card_amount >= total_amount - points_discount
 			}
 	*/
 	var body repository.CreateTransactionParams
@@ -225,10 +362,95 @@ func (controller *OrderItemControllerImpl) GetSalesReport(ctx *fiber.Ctx) error
 		JSON(common.NewWebResponse(200, common.StatusSuccess, salesReport))
 }
 
+// GetCategoryReport implements OrderItemController.
+func (controller *OrderItemControllerImpl) GetCategoryReport(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		StoreId    int               `json:"store_id"` // 0 means all store
+		DateFilter *query.DateFilter `json:"date_filter"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	categories, err := controller.Service.GetCategoryReport(tenantId, body.StoreId, body.DateFilter)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"categories": categories,
+		}))
+}
+
+// GetStoreComparison implements OrderItemController.
+func (controller *OrderItemControllerImpl) GetStoreComparison(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		DateFilter *query.DateFilter `json:"date_filter"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	stores, err := controller.Service.GetStoreComparison(tenantId, body.DateFilter)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"stores": stores,
+		}))
+}
+
+// GetSalesSeries implements OrderItemController.
+func (controller *OrderItemControllerImpl) GetSalesSeries(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		StoreId    int                    `json:"store_id"` // 0 means all store
+		Bucket     repository.SalesBucket `json:"bucket"`   // hour, day, week, month
+		DateFilter *query.DateFilter      `json:"date_filter"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	salesSeries, err := controller.Service.GetSalesSeries(tenantId, body.StoreId, body.Bucket, body.DateFilter)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, salesSeries))
+}
+
 // DeleteInvoice implements [OrderItemController].
 func (controller *OrderItemControllerImpl) DeleteInvoice(ctx *fiber.Ctx) error {
 	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
 
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
 	var body struct {
 		OrderItemId int `json:"order_item_id"`
 	}
@@ -238,7 +460,7 @@ func (controller *OrderItemControllerImpl) DeleteInvoice(ctx *fiber.Ctx) error {
 			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
 	}
 
-	err = controller.Service.DeleteInvoice(body.OrderItemId, tenantId)
+	err = controller.Service.DeleteInvoice(body.OrderItemId, tenantId, userId)
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).
 			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"cashier-api/helper/client"
+	"cashier-api/helper/mail"
 	"cashier-api/helper/query"
 	"cashier-api/middleware"
 	"cashier-api/model"
@@ -44,14 +45,14 @@ func TestOrderItemControllerImpl(t *testing.T) {
 
 	//IMPLEMENTATION//
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	userService := service.NewUserServiceImpl(userRepository, repository.NewSessionRepositoryImpl(gormClient), repository.NewUserTokenRepositoryImpl(gormClient), repository.NewTwoFactorRepositoryImpl(gormClient), repository.NewSignInAttemptRepositoryImpl(gormClient), mail.NewMemoryMailer())
 	userController := NewUserControllerImpl(userService)
 
 	//ROUTE//
 	app.Post("/users/sign_in", userController.SignInWithEmailAndPassword)
 
 	// These 2 protection are required
-	app.Use(middleware.ProtectedRoute)
+	app.Use(middleware.ProtectedRoute(gormClient))
 	tenantRestriction := middleware.RestrictByTenant(gormClient) // User only allowed to access associated tenant
 
 	orderItemServiceMock := service.NewOrderItemServiceMock(&mock.Mock{}).(*service.OrderItemServiceMock)
@@ -163,7 +164,7 @@ func TestOrderItemControllerImpl(t *testing.T) {
 			requestBody := strings.NewReader(string(byteBody))
 
 			orderItemServiceMock.Mock = &mock.Mock{}
-			orderItemServiceMock.Mock.On("Get", body.TenantId, body.StoreId, body.Limit, body.Page, body.Filters, body.DateFilter).
+			orderItemServiceMock.Mock.On("Get", body.TenantId, body.StoreId, body.CustomerId, body.Limit, body.Page, body.Filters, body.DateFilter).
 				Return(expectedResponse.OrderItems, len(expectedResponse.OrderItems), nil)
 
 			request = httptest.NewRequest("POST", fmt.Sprintf("/order_items/search/%d", createdTestTenant.Id), requestBody)
@@ -488,4 +489,77 @@ func TestOrderItemControllerImpl(t *testing.T) {
 			Execute()
 		require.NoError(t, err, "If this fail, then immediately delete the data from TestOrderItemControllerImpl (3)")
 	})
+
+	t.Run("OrderLifecycle", func(t *testing.T) {
+		// Register route only for this scope
+		app.Post("/order_items/place/:tenantId", tenantRestriction, orderItemController.PlaceOrderItem)
+		app.Put("/order_items/status/:tenantId", tenantRestriction, orderItemController.UpdateOrderStatus)
+		app.Get("/order_items/open/:tenantId", tenantRestriction, orderItemController.GetOpenOrders)
+
+		t.Run("PlaceOrderItem", func(t *testing.T) {
+			orderItemServiceMock.Mock = &mock.Mock{}
+			orderItemServiceMock.Mock.On("PlaceOrderItem", mock.MatchedBy(func(params *repository.CreateTransactionParams) bool {
+				// User and tenant are taken from the session, not from the body
+				return params.UserId == createdTestUser.Id && params.TenantId == createdTestTenant.Id
+			})).Return(&repository.TransactionDataReturn{CreatedOrderItemId: 1}, nil)
+
+			byteBody, err := json.Marshal(fiber.Map{
+				"user_id":   createdTestUser.Id + 1,
+				"tenant_id": createdTestTenant.Id + 1,
+				"store_id":  STORE_ID,
+			})
+			require.NoError(t, err)
+
+			request = httptest.NewRequest("POST", fmt.Sprintf("/order_items/place/%d", createdTestTenant.Id), strings.NewReader(string(byteBody)))
+			request.Header.Set("Content-Type", "application/json")
+			request.AddCookie(enterprisePOSCookie)
+			response, err = app.Test(request, testTimeout)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			orderItemServiceMock.Mock.AssertExpectations(t)
+		})
+
+		t.Run("UpdateOrderStatus", func(t *testing.T) {
+			orderItemServiceMock.Mock = &mock.Mock{}
+			orderItemServiceMock.Mock.On("UpdateOrderStatus", &repository.UpdateOrderStatusParams{
+				OrderItemId: 1,
+				TenantId:    createdTestTenant.Id,
+				UserId:      createdTestUser.Id,
+				Status:      model.OrderStatusReady,
+			}).Return(&model.OrderItem{Id: 1, Status: model.OrderStatusReady}, nil)
+
+			byteBody, err := json.Marshal(fiber.Map{
+				"order_item_id": 1,
+				"status":        model.OrderStatusReady,
+			})
+			require.NoError(t, err)
+
+			request = httptest.NewRequest("PUT", fmt.Sprintf("/order_items/status/%d", createdTestTenant.Id), strings.NewReader(string(byteBody)))
+			request.Header.Set("Content-Type", "application/json")
+			request.AddCookie(enterprisePOSCookie)
+			response, err = app.Test(request, testTimeout)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			orderItemServiceMock.Mock.AssertExpectations(t)
+		})
+
+		t.Run("GetOpenOrders", func(t *testing.T) {
+			orderItemServiceMock.Mock = &mock.Mock{}
+			orderItemServiceMock.Mock.On("GetOpenOrders", createdTestTenant.Id, STORE_ID, 10, 1).
+				Return([]*model.OrderItem{{Id: 1, Status: model.OrderStatusPlaced}}, 1, nil)
+
+			request = httptest.NewRequest("GET", fmt.Sprintf("/order_items/open/%d?store_id=%d", createdTestTenant.Id, STORE_ID), nil)
+			request.AddCookie(enterprisePOSCookie)
+			response, err = app.Test(request, testTimeout)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			orderItemServiceMock.Mock.AssertExpectations(t)
+
+			request = httptest.NewRequest("GET", fmt.Sprintf("/order_items/open/%d", createdTestTenant.Id), nil)
+			request.AddCookie(enterprisePOSCookie)
+			response, err = app.Test(request, testTimeout)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
 }
@@ -0,0 +1,35 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type ParkedOrderController interface {
+	/*
+		Get the parked orders of a store
+	*/
+	Get(ctx *fiber.Ctx) error
+
+	/*
+		Resume 1 parked order with its lines
+	*/
+	FindById(ctx *fiber.Ctx) error
+
+	/*
+		Park a new basket
+	*/
+	Park(ctx *fiber.Ctx) error
+
+	/*
+		Replace customer, notes and lines of a parked order
+	*/
+	Edit(ctx *fiber.Ctx) error
+
+	/*
+		Cancel a parked order
+	*/
+	Cancel(ctx *fiber.Ctx) error
+
+	/*
+		Convert a parked order into a transaction
+	*/
+	Convert(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ParkedOrderControllerImpl struct {
+	Service service.ParkedOrderService
+}
+
+func NewParkedOrderControllerImpl(service service.ParkedOrderService) ParkedOrderController {
+	return &ParkedOrderControllerImpl{Service: service}
+}
+
+type ParkedOrderControllerRequest struct {
+	ParkedOrderId int                      `json:"parked_order_id"`
+	StoreId       int                      `json:"store_id"`
+	CustomerId    *int                     `json:"customer_id"`
+	Notes         string                   `json:"notes"`
+	Version       int                      `json:"version"`
+	Lines         []*model.ParkedOrderLine `json:"lines"`
+}
+
+// Get implements ParkedOrderController.
+func (controller *ParkedOrderControllerImpl) Get(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	parkedOrders, count, err := controller.Service.Get(tenantId, storeId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":          page,
+			"limit":         limit,
+			"count":         count,
+			"parked_orders": parkedOrders,
+		}))
+}
+
+// FindById implements ParkedOrderController.
+func (controller *ParkedOrderControllerImpl) FindById(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	parkedOrderId, err := strconv.Atoi(ctx.Query("parked_order_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check parked_order_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	parkedOrder, err := controller.Service.FindById(parkedOrderId, tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"parked_order": parkedOrder,
+		}))
+}
+
+// Park implements ParkedOrderController.
+func (controller *ParkedOrderControllerImpl) Park(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body ParkedOrderControllerRequest
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	parkedOrder, err := controller.Service.Park(&model.ParkedOrder{
+		TenantId:       tenantId,
+		StoreId:        body.StoreId,
+		CustomerId:     body.CustomerId,
+		Notes:          body.Notes,
+		ParkedByUserId: userId,
+		Lines:          body.Lines,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
+			"parked_order": parkedOrder,
+		}))
+}
+
+// Edit implements ParkedOrderController.
+func (controller *ParkedOrderControllerImpl) Edit(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body ParkedOrderControllerRequest
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	editedParkedOrder, err := controller.Service.Edit(&model.ParkedOrder{
+		Id:         body.ParkedOrderId,
+		TenantId:   tenantId,
+		CustomerId: body.CustomerId,
+		Notes:      body.Notes,
+		Version:    body.Version,
+		Lines:      body.Lines,
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"edited_parked_order": editedParkedOrder,
+		}))
+}
+
+// Cancel implements ParkedOrderController.
+func (controller *ParkedOrderControllerImpl) Cancel(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		ParkedOrderId int `json:"parked_order_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	err = controller.Service.Cancel(body.ParkedOrderId, tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Convert implements ParkedOrderController.
+func (controller *ParkedOrderControllerImpl) Convert(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body struct {
+		ParkedOrderId int `json:"parked_order_id"`
+		service.ParkedOrderPayment
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	transactionReturnData, err := controller.Service.Convert(body.ParkedOrderId, tenantId, userId, &body.ParkedOrderPayment)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, transactionReturnData))
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParkedOrderControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const STORE_ID = 1
+	const USER_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.ParkedOrderRepositoryMock, *repository.OrderItemRepositoryMock) {
+		parkedOrderRepo := repository.NewParkedOrderRepositoryMock(&mock.Mock{}).(*repository.ParkedOrderRepositoryMock)
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		parkedOrderService := service.NewParkedOrderServiceImpl(parkedOrderRepo, service.NewOrderItemServiceImpl(orderItemRepo))
+		parkedOrderController := NewParkedOrderControllerImpl(parkedOrderService)
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", USER_ID)
+			return ctx.Next()
+		})
+		app.Get("/parked_orders/details/:tenantId", parkedOrderController.FindById)
+		app.Get("/parked_orders/:tenantId", parkedOrderController.Get)
+		app.Post("/parked_orders/convert/:tenantId", parkedOrderController.Convert)
+		app.Post("/parked_orders/:tenantId", parkedOrderController.Park)
+		app.Put("/parked_orders/:tenantId", parkedOrderController.Edit)
+		app.Delete("/parked_orders/:tenantId", parkedOrderController.Cancel)
+		return app, parkedOrderRepo, orderItemRepo
+	}
+
+	sendJSON := func(app *fiber.App, method, url, body string) *http.Response {
+		request := httptest.NewRequest(method, url, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		return response
+	}
+
+	const linesJSON = `[{"item_id":1,"quantity":2,"store_price_snapshot":10000,"discount_amount":0,"item_name_snapshot":"Item A"}]`
+
+	t.Run("Park", func(t *testing.T) {
+		app, parkedOrderRepo, _ := newApp()
+		parkedOrderRepo.Mock.On("Create", mock.MatchedBy(func(parkedOrder *model.ParkedOrder) bool {
+			return parkedOrder.ParkedByUserId == USER_ID && parkedOrder.TenantId == TENANT_ID && len(parkedOrder.Lines) == 1
+		})).Return(&model.ParkedOrder{Id: 1, Version: 1}, nil)
+
+		response := sendJSON(app, "POST", fmt.Sprintf("/parked_orders/%d", TENANT_ID),
+			fmt.Sprintf(`{"store_id":%d,"notes":"Back soon","lines":%s}`, STORE_ID, linesJSON))
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+		response = sendJSON(app, "POST", fmt.Sprintf("/parked_orders/%d", TENANT_ID), fmt.Sprintf(`{"store_id":%d,"lines":[]}`, STORE_ID))
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		app, parkedOrderRepo, _ := newApp()
+		parkedOrderRepo.Mock.On("Get", TENANT_ID, STORE_ID, 10, 0).Return([]*model.ParkedOrder{{Id: 1}, {Id: 2}}, 2, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/parked_orders/%d?store_id=%d", TENANT_ID, STORE_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var responseBody struct {
+			Data struct {
+				Count        int                  `json:"count"`
+				ParkedOrders []*model.ParkedOrder `json:"parked_orders"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+		assert.Equal(t, 2, responseBody.Data.Count)
+		assert.Len(t, responseBody.Data.ParkedOrders, 2)
+
+		request = httptest.NewRequest("GET", fmt.Sprintf("/parked_orders/%d", TENANT_ID), nil)
+		response, err = app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("FindById", func(t *testing.T) {
+		app, parkedOrderRepo, _ := newApp()
+		parkedOrderRepo.Mock.On("FindById", 1, TENANT_ID).Return(&model.ParkedOrder{Id: 1, Version: 4}, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/parked_orders/details/%d?parked_order_id=1", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		app, parkedOrderRepo, _ := newApp()
+		parkedOrderRepo.Mock.On("Edit", mock.MatchedBy(func(parkedOrder *model.ParkedOrder) bool {
+			return parkedOrder.Id == 1 && parkedOrder.Version == 4
+		})).Return(&model.ParkedOrder{Id: 1, Version: 5}, nil)
+
+		response := sendJSON(app, "PUT", fmt.Sprintf("/parked_orders/%d", TENANT_ID),
+			fmt.Sprintf(`{"parked_order_id":1,"version":4,"lines":%s}`, linesJSON))
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		app, parkedOrderRepo, _ := newApp()
+		parkedOrderRepo.Mock.On("Cancel", 1, TENANT_ID).Return(nil)
+
+		response := sendJSON(app, "DELETE", fmt.Sprintf("/parked_orders/%d", TENANT_ID), `{"parked_order_id":1}`)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		app, parkedOrderRepo, orderItemRepo := newApp()
+		parkedOrderRepo.Mock.On("FindById", 1, TENANT_ID).Return(&model.ParkedOrder{
+			Id:      1,
+			StoreId: STORE_ID,
+			Status:  model.ParkedOrderStatusParked,
+			Version: 4,
+			Lines:   []*model.ParkedOrderLine{{ItemId: 1, Quantity: 2, StorePriceSnapshot: 10_000, ItemNameSnapshot: "Item A"}},
+		}, nil)
+		orderItemRepo.Mock.On("Transactions", mock.MatchedBy(func(params *repository.CreateTransactionParams) bool {
+			return params.ParkedOrderId == 1 && params.ParkedOrderVersion == 4 && params.UserId == USER_ID && params.PurchasedPrice == 20_000
+		})).Return(&repository.TransactionDataReturn{CreatedOrderItemId: 9}, nil)
+
+		response := sendJSON(app, "POST", fmt.Sprintf("/parked_orders/convert/%d", TENANT_ID), `{"parked_order_id":1,"version":4,"purchased_price":20000}`)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		response = sendJSON(app, "POST", fmt.Sprintf("/parked_orders/convert/%d", TENANT_ID), `{"parked_order_id":1,"version":4,"purchased_price":100}`)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
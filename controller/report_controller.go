@@ -0,0 +1,35 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type ReportController interface {
+	/*
+		Top items of the period ranked by quantity, revenue or profit
+	*/
+	GetTopSellers(ctx *fiber.Ctx) error
+
+	/*
+		Items with stock but no sales in the last N days
+	*/
+	GetSlowMovers(ctx *fiber.Ctx) error
+
+	/*
+		A / B / C classification of items by revenue contribution
+	*/
+	GetABCAnalysis(ctx *fiber.Ctx) error
+
+	/*
+		Sales, voids and refunds per user of the tenant
+	*/
+	GetCashierPerformance(ctx *fiber.Ctx) error
+
+	/*
+		Stock value at cost and retail per location and category
+	*/
+	GetInventoryValuation(ctx *fiber.Ctx) error
+
+	/*
+		Suggested order quantity per location from the sales forecast
+	*/
+	GetReorderSuggestions(ctx *fiber.Ctx) error
+}
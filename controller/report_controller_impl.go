@@ -0,0 +1,219 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/helper/query"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportControllerImpl struct {
+	Service service.ReportService
+}
+
+func NewReportControllerImpl(service service.ReportService) ReportController {
+	return &ReportControllerImpl{Service: service}
+}
+
+type ReportControllerRequest struct {
+	StoreId    int                          `json:"store_id"`    // 0 means all store
+	CategoryId int                          `json:"category_id"` // 0 means all category
+	Metric     repository.ItemRankingMetric `json:"metric"`      // quantity (default), revenue, profit
+	Limit      int                          `json:"limit"`
+	DateFilter *query.DateFilter            `json:"date_filter"`
+}
+
+// GetTopSellers implements ReportController.
+func (controller *ReportControllerImpl) GetTopSellers(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	body := ReportControllerRequest{Limit: 10}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	items, err := controller.Service.GetTopSellers(tenantId, body.StoreId, body.CategoryId, body.Metric, body.DateFilter, body.Limit)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"metric": body.Metric,
+			"items":  items,
+		}))
+}
+
+// GetSlowMovers implements ReportController.
+func (controller *ReportControllerImpl) GetSlowMovers(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	categoryId, err := strconv.Atoi(ctx.Query("category_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check category_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	days, err := strconv.Atoi(ctx.Query("days", "30"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check days URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	items, count, err := controller.Service.GetSlowMovers(tenantId, storeId, categoryId, days, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":  page,
+			"limit": limit,
+			"count": count,
+			"days":  days,
+			"items": items,
+		}))
+}
+
+// GetABCAnalysis implements ReportController.
+func (controller *ReportControllerImpl) GetABCAnalysis(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body ReportControllerRequest
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	analysis, err := controller.Service.GetABCAnalysis(tenantId, body.StoreId, body.CategoryId, body.DateFilter)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, analysis))
+}
+
+// GetCashierPerformance implements ReportController.
+func (controller *ReportControllerImpl) GetCashierPerformance(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body ReportControllerRequest
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	users, err := controller.Service.GetCashierPerformance(tenantId, body.StoreId, body.DateFilter)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"users": users,
+		}))
+}
+
+// GetInventoryValuation implements ReportController.
+func (controller *ReportControllerImpl) GetInventoryValuation(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	// as_of is unix second, optional
+	var asOf *int64
+	if value := ctx.Query("as_of"); value != "" {
+		unix, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check as_of URL parameter")
+			return ctx.Status(fiber.StatusBadRequest).JSON(response)
+		}
+		asOf = &unix
+	}
+
+	valuation, err := controller.Service.GetInventoryValuation(tenantId, storeId, asOf)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"valuation": valuation,
+		}))
+}
+
+// GetReorderSuggestions implements ReportController.
+func (controller *ReportControllerImpl) GetReorderSuggestions(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	storeId, err := strconv.Atoi(ctx.Query("store_id", "0"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check store_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	historyDays, err := strconv.Atoi(ctx.Query("history_days", "56"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check history_days URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	leadTimeDays, err := strconv.Atoi(ctx.Query("lead_time_days", "7"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check lead_time_days URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	serviceLevel, err := strconv.Atoi(ctx.Query("service_level", "95"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check service_level URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	suggestions, err := controller.Service.GetReorderSuggestions(tenantId, storeId, historyDays, leadTimeDays, serviceLevel)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"suggestions": suggestions,
+		}))
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.ReportRepositoryMock) {
+		reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+		reportController := NewReportControllerImpl(service.NewReportServiceImpl(reportRepo))
+
+		app := fiber.New()
+		app.Post("/reports/top_sellers/:tenantId", reportController.GetTopSellers)
+		app.Get("/reports/slow_movers/:tenantId", reportController.GetSlowMovers)
+		app.Post("/reports/abc/:tenantId", reportController.GetABCAnalysis)
+		app.Post("/reports/cashier_performance/:tenantId", reportController.GetCashierPerformance)
+		app.Get("/reports/inventory_valuation/:tenantId", reportController.GetInventoryValuation)
+		app.Get("/reports/reorder_suggestions/:tenantId", reportController.GetReorderSuggestions)
+		return app, reportRepo
+	}
+
+	t.Run("GetTopSellers", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, reportRepo := newApp()
+			reportRepo.Mock.On("GetItemRanking", TENANT_ID, 1, 0, repository.ItemRankingByProfit, mock.AnythingOfType("*query.DateFilter"), 5).
+				Return([]*repository.ItemRankingRow{{ItemId: 1, TotalProfit: 5_000}}, nil)
+
+			body := strings.NewReader(`{"store_id":1,"metric":"profit","limit":5,"date_filter":{"start_date":0,"end_date":100}}`)
+			request := httptest.NewRequest("POST", fmt.Sprintf("/reports/top_sellers/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			reportRepo.Mock.AssertExpectations(t)
+		})
+
+		t.Run("InvalidMetric", func(t *testing.T) {
+			app, _ := newApp()
+
+			body := strings.NewReader(`{"metric":"margin"}`)
+			request := httptest.NewRequest("POST", fmt.Sprintf("/reports/top_sellers/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("GetSlowMovers", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, reportRepo := newApp()
+			reportRepo.Mock.On("GetSlowMovers", TENANT_ID, 1, 0, mock.Anything, 10, 0).
+				Return([]*repository.SlowMoverRow{{ItemId: 1, Stocks: 5}}, 1, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/reports/slow_movers/%d?store_id=1&days=60", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Count int `json:"count"`
+					Days  int `json:"days"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 1, responseBody.Data.Count)
+			assert.Equal(t, 60, responseBody.Data.Days)
+		})
+
+		t.Run("InvalidDays", func(t *testing.T) {
+			app, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/reports/slow_movers/%d?days=abc", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("GetABCAnalysis", func(t *testing.T) {
+		app, reportRepo := newApp()
+		reportRepo.Mock.On("GetItemRanking", TENANT_ID, 0, 2, repository.ItemRankingByRevenue, (*query.DateFilter)(nil), 0).
+			Return([]*repository.ItemRankingRow{{ItemId: 1, TotalRevenue: 100}}, nil)
+
+		body := strings.NewReader(`{"category_id":2}`)
+		request := httptest.NewRequest("POST", fmt.Sprintf("/reports/abc/%d", TENANT_ID), body)
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var responseBody struct {
+			Data *repository.ABCAnalysis `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+		assert.Equal(t, 1, responseBody.Data.CountA)
+	})
+
+	t.Run("GetCashierPerformance", func(t *testing.T) {
+		app, reportRepo := newApp()
+		reportRepo.Mock.On("GetCashierPerformance", TENANT_ID, 1, mock.AnythingOfType("*query.DateFilter")).
+			Return([]*repository.CashierPerformanceRow{{UserId: 7, TransactionCount: 2, TotalRevenue: 30_000}}, nil)
+
+		body := strings.NewReader(`{"store_id":1,"date_filter":{"start_date":0,"end_date":100}}`)
+		request := httptest.NewRequest("POST", fmt.Sprintf("/reports/cashier_performance/%d", TENANT_ID), body)
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var responseBody struct {
+			Data struct {
+				Users []*repository.CashierPerformanceRow `json:"users"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+		require.Len(t, responseBody.Data.Users, 1)
+		assert.Equal(t, 15_000.0, responseBody.Data.Users[0].AverageBasket)
+	})
+
+	t.Run("GetInventoryValuation", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, reportRepo := newApp()
+			reportRepo.Mock.On("GetInventoryValuation", TENANT_ID, 1).Return([]*repository.InventoryValuationRow{
+				{StoreId: 1, LocationName: "Store", CategoryName: "Drinks", TotalStocks: 3, CostValue: 3_000, RetailValue: 6_000},
+			}, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/reports/inventory_valuation/%d?store_id=1", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Valuation *repository.InventoryValuation `json:"valuation"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 3_000, responseBody.Data.Valuation.TotalPotentialProfit)
+		})
+
+		t.Run("PastAsOf", func(t *testing.T) {
+			app, reportRepo := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/reports/inventory_valuation/%d?as_of=%d", TENANT_ID, time.Now().AddDate(0, 0, -1).Unix()), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+			reportRepo.Mock.AssertNotCalled(t, "GetInventoryValuation", mock.Anything, mock.Anything)
+		})
+
+		t.Run("InvalidStoreId", func(t *testing.T) {
+			app, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/reports/inventory_valuation/%d?store_id=abc", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("GetReorderSuggestions", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			app, reportRepo := newApp()
+			reportRepo.Mock.On("GetTenantTimezone", TENANT_ID).Return("UTC", nil)
+			reportRepo.Mock.On("GetReorderStocks", TENANT_ID, 1).Return([]*repository.ReorderStockRow{}, nil)
+			reportRepo.Mock.On("GetDailyItemSales", TENANT_ID, 1, "UTC", mock.Anything, mock.Anything).
+				Return([]*repository.DailyItemSalesRow{}, nil)
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/reports/reorder_suggestions/%d?store_id=1&lead_time_days=3", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					Suggestions *repository.ReorderSuggestions `json:"suggestions"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 3, responseBody.Data.Suggestions.LeadTimeDays)
+			assert.Equal(t, 95, responseBody.Data.Suggestions.ServiceLevel)
+			assert.Len(t, responseBody.Data.Suggestions.Locations, 0)
+		})
+
+		t.Run("InvalidServiceLevel", func(t *testing.T) {
+			app, _ := newApp()
+
+			request := httptest.NewRequest("GET", fmt.Sprintf("/reports/reorder_suggestions/%d?service_level=50", TENANT_ID), nil)
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+}
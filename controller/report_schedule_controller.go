@@ -0,0 +1,35 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type ReportScheduleController interface {
+	/*
+		Get the report schedules of the tenant
+	*/
+	Get(ctx *fiber.Ctx) error
+
+	/*
+		Return 1 schedule
+	*/
+	FindById(ctx *fiber.Ctx) error
+
+	/*
+		Create a new schedule
+	*/
+	Create(ctx *fiber.Ctx) error
+
+	/*
+		Edit every setting of a schedule
+	*/
+	Edit(ctx *fiber.Ctx) error
+
+	/*
+		Delete a schedule and its run history
+	*/
+	Delete(ctx *fiber.Ctx) error
+
+	/*
+		Run history of a schedule
+	*/
+	GetRuns(ctx *fiber.Ctx) error
+}
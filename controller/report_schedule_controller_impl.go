@@ -0,0 +1,229 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/service"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportScheduleControllerImpl struct {
+	Service service.ReportScheduleService
+}
+
+func NewReportScheduleControllerImpl(service service.ReportScheduleService) ReportScheduleController {
+	return &ReportScheduleControllerImpl{Service: service}
+}
+
+type ReportScheduleControllerRequest struct {
+	ReportScheduleId int                           `json:"report_schedule_id"`
+	StoreId          int                           `json:"store_id"`
+	Name             string                        `json:"name"`
+	Resource         string                        `json:"resource"`
+	Format           string                        `json:"format"`
+	Frequency        model.ReportScheduleFrequency `json:"frequency"`
+	TimeOfDay        string                        `json:"time_of_day"`
+	DayOfWeek        int                           `json:"day_of_week"`
+	DayOfMonth       int                           `json:"day_of_month"`
+	Channel          model.ReportScheduleChannel   `json:"channel"`
+	Recipients       string                        `json:"recipients"`
+	WebhookUrl       string                        `json:"webhook_url"`
+	WebhookSecret    *string                       `json:"webhook_secret"` // Write only, omitted keep the current one, "" remove it
+	IsActive         *bool                         `json:"is_active"`      // Default true
+}
+
+func (body *ReportScheduleControllerRequest) toModel(tenantId int) *model.ReportSchedule {
+	isActive := true
+	if body.IsActive != nil {
+		isActive = *body.IsActive
+	}
+
+	return &model.ReportSchedule{
+		Id:            body.ReportScheduleId,
+		TenantId:      tenantId,
+		StoreId:       body.StoreId,
+		Name:          body.Name,
+		Resource:      body.Resource,
+		Format:        body.Format,
+		Frequency:     body.Frequency,
+		TimeOfDay:     body.TimeOfDay,
+		DayOfWeek:     body.DayOfWeek,
+		DayOfMonth:    body.DayOfMonth,
+		Channel:       body.Channel,
+		Recipients:    body.Recipients,
+		WebhookUrl:    body.WebhookUrl,
+		WebhookSecret: body.WebhookSecret,
+		IsActive:      isActive,
+	}
+}
+
+// Get implements ReportScheduleController.
+func (controller *ReportScheduleControllerImpl) Get(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	schedules, count, err := controller.Service.Get(tenantId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":             page,
+			"limit":            limit,
+			"count":            count,
+			"report_schedules": schedules,
+		}))
+}
+
+// FindById implements ReportScheduleController.
+func (controller *ReportScheduleControllerImpl) FindById(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	scheduleId, err := strconv.Atoi(ctx.Query("report_schedule_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check report_schedule_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	schedule, err := controller.Service.FindById(scheduleId, tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"report_schedule": schedule,
+		}))
+}
+
+// Create implements ReportScheduleController.
+func (controller *ReportScheduleControllerImpl) Create(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	userId, ok := ctx.Locals("sub").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	var body ReportScheduleControllerRequest
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	schedule := body.toModel(tenantId)
+	schedule.Id = 0
+	schedule.CreatedByUserId = userId
+
+	created, err := controller.Service.Create(schedule)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
+			"report_schedule": created,
+		}))
+}
+
+// Edit implements ReportScheduleController.
+func (controller *ReportScheduleControllerImpl) Edit(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body ReportScheduleControllerRequest
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	edited, err := controller.Service.Edit(body.toModel(tenantId))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"report_schedule": edited,
+		}))
+}
+
+// Delete implements ReportScheduleController.
+func (controller *ReportScheduleControllerImpl) Delete(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		ReportScheduleId int `json:"report_schedule_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	err = controller.Service.Delete(body.ReportScheduleId, tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// GetRuns implements ReportScheduleController.
+func (controller *ReportScheduleControllerImpl) GetRuns(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	scheduleId, err := strconv.Atoi(ctx.Query("report_schedule_id", ""))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check report_schedule_id URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	runs, count, err := controller.Service.GetRuns(scheduleId, tenantId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":  page,
+			"limit": limit,
+			"count": count,
+			"runs":  runs,
+		}))
+}
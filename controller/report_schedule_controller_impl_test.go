@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"cashier-api/helper/mail"
+	"cashier-api/helper/webhook"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportScheduleControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const USER_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.ReportScheduleRepositoryMock) {
+		scheduleRepo := repository.NewReportScheduleRepositoryMock(&mock.Mock{}).(*repository.ReportScheduleRepositoryMock)
+		scheduleService := service.NewReportScheduleServiceImpl(
+			scheduleRepo, service.NewExportServiceMock(&mock.Mock{}), mail.NewLogMailer(), webhook.NewHTTPSender(time.Second),
+		)
+		scheduleController := NewReportScheduleControllerImpl(scheduleService)
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", USER_ID)
+			return ctx.Next()
+		})
+		app.Get("/report_schedules/runs/:tenantId", scheduleController.GetRuns)
+		app.Get("/report_schedules/details/:tenantId", scheduleController.FindById)
+		app.Get("/report_schedules/:tenantId", scheduleController.Get)
+		app.Post("/report_schedules/:tenantId", scheduleController.Create)
+		app.Put("/report_schedules/:tenantId", scheduleController.Edit)
+		app.Delete("/report_schedules/:tenantId", scheduleController.Delete)
+		return app, scheduleRepo
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("NormalCreate", func(t *testing.T) {
+			app, scheduleRepo := newApp()
+			secret := "s3cret-value"
+			scheduleRepo.Mock.On("Create", mock.MatchedBy(func(schedule *model.ReportSchedule) bool {
+				return schedule.TenantId == TENANT_ID && schedule.CreatedByUserId == USER_ID && schedule.IsActive
+			})).Return(&model.ReportSchedule{Id: 1, Name: "Daily sales", WebhookSecret: &secret}, nil)
+
+			body := strings.NewReader(`{"name":"Daily sales","resource":"sales_report","format":"pdf","frequency":"DAILY",` +
+				`"time_of_day":"07:00","channel":"EMAIL","recipients":"owner@example.com"}`)
+			request := httptest.NewRequest("POST", fmt.Sprintf("/report_schedules/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+			byteBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			var responseBody struct {
+				Data struct {
+					ReportSchedule *model.ReportSchedule `json:"report_schedule"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(byteBody, &responseBody))
+			assert.Equal(t, 1, responseBody.Data.ReportSchedule.Id)
+			// Write only
+			assert.NotContains(t, string(byteBody), secret)
+			assert.NotContains(t, string(byteBody), "webhook_secret")
+		})
+
+		t.Run("InvalidSchedule", func(t *testing.T) {
+			app, _ := newApp()
+
+			body := strings.NewReader(`{"name":"Daily sales","resource":"sales_report","format":"pdf","frequency":"HOURLY"}`)
+			request := httptest.NewRequest("POST", fmt.Sprintf("/report_schedules/%d", TENANT_ID), body)
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+
+	t.Run("GetRuns", func(t *testing.T) {
+		app, scheduleRepo := newApp()
+		scheduleRepo.Mock.On("GetRuns", 3, TENANT_ID, 10, 0).
+			Return([]*model.ReportScheduleRun{{Id: 1, Status: model.ReportScheduleRunRetrying, LastError: "timeout"}}, 1, nil)
+
+		request := httptest.NewRequest("GET", fmt.Sprintf("/report_schedules/runs/%d?report_schedule_id=3", TENANT_ID), nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(byteBody), `"last_error":"timeout"`)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		app, scheduleRepo := newApp()
+		scheduleRepo.Mock.On("Delete", 3, TENANT_ID).Return(nil)
+
+		request := httptest.NewRequest("DELETE", fmt.Sprintf("/report_schedules/%d", TENANT_ID), strings.NewReader(`{"report_schedule_id":3}`))
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+}
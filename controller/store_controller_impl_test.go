@@ -4,6 +4,7 @@ import (
 	common "cashier-api/helper"
 	"cashier-api/helper/client"
 	constant "cashier-api/helper/constant/cookie"
+	"cashier-api/helper/mail"
 	"cashier-api/middleware"
 	"cashier-api/model"
 	"cashier-api/repository"
@@ -43,11 +44,11 @@ func TestStoreControllerImpl(t *testing.T) {
 	gormClient := client.CreateGormClient()
 
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	userService := service.NewUserServiceImpl(userRepository, repository.NewSessionRepositoryImpl(gormClient), repository.NewUserTokenRepositoryImpl(gormClient), repository.NewTwoFactorRepositoryImpl(gormClient), repository.NewSignInAttemptRepositoryImpl(gormClient), mail.NewMemoryMailer())
 	userController := NewUserControllerImpl(userService)
 	app.Post("/users/sign_in", userController.SignInWithEmailAndPassword)
 
-	app.Use(middleware.ProtectedRoute)
+	app.Use(middleware.ProtectedRoute(gormClient))
 
 	storeRepository := repository.NewStoreRepositoryImpl(gormClient)
 	storeService := service.NewStoreServiceImpl(storeRepository)
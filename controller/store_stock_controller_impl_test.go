@@ -2,6 +2,7 @@ package controller
 
 import (
 	"cashier-api/helper/client"
+	"cashier-api/helper/mail"
 	"cashier-api/middleware"
 	"cashier-api/model"
 	"cashier-api/repository"
@@ -40,7 +41,7 @@ func TestStoreStockControllerImpl(t *testing.T) {
 
 	//IMPLEMENTATION//
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	userService := service.NewUserServiceImpl(userRepository, repository.NewSessionRepositoryImpl(gormClient), repository.NewUserTokenRepositoryImpl(gormClient), repository.NewTwoFactorRepositoryImpl(gormClient), repository.NewSignInAttemptRepositoryImpl(gormClient), mail.NewMemoryMailer())
 	userController := NewUserControllerImpl(userService)
 
 	storeRepository := repository.NewStoreRepositoryImpl(gormClient)
@@ -57,7 +58,7 @@ func TestStoreStockControllerImpl(t *testing.T) {
 	app.Post("/users/sign_in", userController.SignInWithEmailAndPassword)
 
 	// These 2 protection are required
-	app.Use(middleware.ProtectedRoute)
+	app.Use(middleware.ProtectedRoute(gormClient))
 	tenantRestriction := middleware.RestrictByTenant(gormClient) // User only allowed to access associated tenant
 
 	app.Get("/stores/:tenantId", tenantRestriction, storeController.GetAll)
@@ -11,4 +11,13 @@ type TenantController interface {
 	RemoveUserFromTenant(*fiber.Ctx) error
 	AddUserToTenant(*fiber.Ctx) error
 	GetTenantMembers(*fiber.Ctx) error
+	SetTimezone(*fiber.Ctx) error
+	SetTwoFactorPolicy(*fiber.Ctx) error
+	SetMemberRole(*fiber.Ctx) error
+	RequestOwnershipTransfer(*fiber.Ctx) error
+	GetOwnershipTransfer(*fiber.Ctx) error
+	AcceptOwnershipTransfer(*fiber.Ctx) error
+	CancelOwnershipTransfer(*fiber.Ctx) error
+	Archive(*fiber.Ctx) error
+	Restore(*fiber.Ctx) error
 }
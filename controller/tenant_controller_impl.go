@@ -3,7 +3,9 @@ package controller
 import (
 	common "cashier-api/helper"
 	"cashier-api/model"
+	"cashier-api/repository"
 	"cashier-api/service"
+	"errors"
 	"strconv"
 	"strings"
 
@@ -218,3 +220,245 @@ func (controller *TenantControllerImpl) GetTenantMembers(ctx *fiber.Ctx) error {
 			"members":          members,
 		}))
 }
+
+// SetTimezone implements TenantController.
+func (controller *TenantControllerImpl) SetTimezone(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		Timezone string `json:"timezone"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	sub := ctx.Locals("sub")
+	userId, ok := sub.(int)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Unexpected behavior ! could not get the id"))
+	}
+
+	err = controller.Service.SetTimezone(tenantId, body.Timezone, userId)
+	if err != nil {
+		if err.Error() == "[TenantService:SetTimezone]" {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Forbidden action ! Only tenant owner could change the timezone"))
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"requested_tenant": tenantId,
+			"timezone":         body.Timezone,
+		}))
+}
+
+// SetTwoFactorPolicy implements TenantController.
+func (controller *TenantControllerImpl) SetTwoFactorPolicy(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		RequireTwoFactor bool `json:"require_two_factor"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	userId := ctx.Locals("sub").(int)
+	err = controller.Service.SetTwoFactorPolicy(tenantId, body.RequireTwoFactor, userId)
+	if err != nil {
+		if err.Error() == "[TenantService:SetTwoFactorPolicy]" {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Forbidden action ! Only tenant owner could change the two factor policy"))
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"requested_tenant":   tenantId,
+			"require_two_factor": body.RequireTwoFactor,
+		}))
+}
+
+// SetMemberRole implements TenantController.
+func (controller *TenantControllerImpl) SetMemberRole(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		UserId int              `json:"user_id"`
+		Role   model.TenantRole `json:"role"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	userId := ctx.Locals("sub").(int)
+	err = controller.Service.SetMemberRole(tenantId, body.UserId, body.Role, userId)
+	if err != nil {
+		if err.Error() == "[TenantService:SetMemberRole]" {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Forbidden action ! Only tenant owner could change the member role"))
+		}
+
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"requested_tenant": tenantId,
+			"user_id":          body.UserId,
+			"role":             body.Role,
+		}))
+}
+
+// RequestOwnershipTransfer implements TenantController.
+func (controller *TenantControllerImpl) RequestOwnershipTransfer(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		UserId int `json:"user_id"` // The new owner
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.UserId == 0 {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	userId := ctx.Locals("sub").(int)
+	transfer, err := controller.Service.RequestOwnershipTransfer(tenantId, body.UserId, userId)
+	if err != nil {
+		return tenantError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, transfer))
+}
+
+// GetOwnershipTransfer implements TenantController.
+func (controller *TenantControllerImpl) GetOwnershipTransfer(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	transfer, err := controller.Service.GetOwnershipTransfer(tenantId)
+	if err != nil {
+		return tenantError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, transfer))
+}
+
+// AcceptOwnershipTransfer implements TenantController.
+func (controller *TenantControllerImpl) AcceptOwnershipTransfer(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	transfer, err := controller.Service.AcceptOwnershipTransfer(tenantId, userId)
+	if err != nil {
+		return tenantError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, transfer))
+}
+
+// CancelOwnershipTransfer implements TenantController.
+func (controller *TenantControllerImpl) CancelOwnershipTransfer(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	err := controller.Service.CancelOwnershipTransfer(tenantId, userId)
+	if err != nil {
+		return tenantError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Ownership transfer cancelled",
+		}))
+}
+
+// Archive implements TenantController.
+func (controller *TenantControllerImpl) Archive(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	var body struct {
+		ConfirmName string `json:"confirm_name"` // Must be the tenant name
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.ConfirmName == "" {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	userId := ctx.Locals("sub").(int)
+	tenant, err := controller.Service.ArchiveTenant(tenantId, body.ConfirmName, userId)
+	if err != nil {
+		return tenantError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"requested_tenant": tenantId,
+			"archived_at":      tenant.ArchivedAt,
+			"deletes_at":       tenant.DeletesAt(),
+		}))
+}
+
+// Restore implements TenantController.
+func (controller *TenantControllerImpl) Restore(ctx *fiber.Ctx) error {
+	// Not restricted by tenant, the archived tenant refuse writes
+	tenantId, err := strconv.Atoi(ctx.Params("tenantId"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! params tenant id is not int"))
+	}
+
+	userId := ctx.Locals("sub").(int)
+	err = controller.Service.RestoreTenant(tenantId, userId)
+	if err != nil {
+		return tenantError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"requested_tenant": tenantId,
+			"message":          "Tenant restored",
+		}))
+}
+
+func tenantError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTenantOwnerOnly):
+		return ctx.Status(fiber.StatusForbidden).
+			JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+	case errors.Is(err, repository.ErrOwnershipTransferNotFound), errors.Is(err, repository.ErrTenantNotArchived):
+		return ctx.Status(fiber.StatusNotFound).
+			JSON(common.NewWebResponseError(404, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusBadRequest).
+		JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+}
@@ -5,6 +5,7 @@ import (
 	common "cashier-api/helper"
 	"cashier-api/helper/client"
 	constant "cashier-api/helper/constant/cookie"
+	"cashier-api/helper/mail"
 	"cashier-api/middleware"
 	"cashier-api/model"
 	"cashier-api/repository"
@@ -37,7 +38,7 @@ func TestTenantControllerImpl(t *testing.T) {
 
 	// To create new user, User endpoint are needed
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	userService := service.NewUserServiceImpl(userRepository, repository.NewSessionRepositoryImpl(gormClient), repository.NewUserTokenRepositoryImpl(gormClient), repository.NewTwoFactorRepositoryImpl(gormClient), repository.NewSignInAttemptRepositoryImpl(gormClient), mail.NewMemoryMailer())
 	userController := NewUserControllerImpl(userService)
 
 	tenantRepo := repository.NewTenantRepositoryImpl(gormClient)
@@ -46,11 +47,11 @@ func TestTenantControllerImpl(t *testing.T) {
 
 	app := fiber.New()
 	app.Post("/users/sign_up", userController.SignUpWithEmailAndPassword)
-	app.Get("/tenants/:userId", middleware.ProtectedRoute, tenantController.GetTenantWithUser)
-	app.Get("/tenants/members/:tenantId", middleware.ProtectedRoute, tenantController.GetTenantMembers)
-	app.Post("/tenants/new", middleware.ProtectedRoute, tenantController.NewTenant)
-	app.Post("/tenants/add_user", middleware.ProtectedRoute, tenantController.AddUserToTenant)
-	app.Delete("/tenants/remove_user", middleware.ProtectedRoute, tenantController.RemoveUserFromTenant)
+	app.Get("/tenants/:userId", middleware.ProtectedRoute(gormClient), tenantController.GetTenantWithUser)
+	app.Get("/tenants/members/:tenantId", middleware.ProtectedRoute(gormClient), tenantController.GetTenantMembers)
+	app.Post("/tenants/new", middleware.ProtectedRoute(gormClient), tenantController.NewTenant)
+	app.Post("/tenants/add_user", middleware.ProtectedRoute(gormClient), tenantController.AddUserToTenant)
+	app.Delete("/tenants/remove_user", middleware.ProtectedRoute(gormClient), tenantController.RemoveUserFromTenant)
 
 	type RequestBodyStructure struct {
 		UserId      int `json:"user_id"` // To be add user
@@ -0,0 +1,14 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+/*
+Every route here required authentication, Accept is the only one without restrict by tenant
+*/
+type TenantInvitationController interface {
+	Invite(*fiber.Ctx) error
+	GetPending(*fiber.Ctx) error
+	Resend(*fiber.Ctx) error
+	Revoke(*fiber.Ctx) error
+	Accept(*fiber.Ctx) error
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TenantInvitationControllerImpl struct {
+	Service service.TenantInvitationService
+}
+
+func NewTenantInvitationControllerImpl(service service.TenantInvitationService) TenantInvitationController {
+	return &TenantInvitationControllerImpl{Service: service}
+}
+
+// Invite implements TenantInvitationController.
+func (controller *TenantInvitationControllerImpl) Invite(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	var body struct {
+		Email string           `json:"email"`
+		Role  model.TenantRole `json:"role"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.Email == "" || body.Role == "" {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	invitation, err := controller.Service.Invite(tenantId, body.Email, body.Role, userId)
+	if err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, invitation))
+}
+
+// GetPending implements TenantInvitationController.
+func (controller *TenantInvitationControllerImpl) GetPending(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	invitations, count, err := controller.Service.GetPending(tenantId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":        page,
+			"limit":       limit,
+			"count":       count,
+			"invitations": invitations,
+		}))
+}
+
+// Resend implements TenantInvitationController.
+func (controller *TenantInvitationControllerImpl) Resend(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	invitationId, ok := invitationIdBody(ctx)
+	if !ok {
+		return nil
+	}
+
+	invitation, err := controller.Service.Resend(tenantId, invitationId, userId)
+	if err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, invitation))
+}
+
+// Revoke implements TenantInvitationController.
+func (controller *TenantInvitationControllerImpl) Revoke(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	invitationId, ok := invitationIdBody(ctx)
+	if !ok {
+		return nil
+	}
+
+	err := controller.Service.Revoke(tenantId, invitationId, userId)
+	if err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Invitation revoked",
+		}))
+}
+
+// Accept implements TenantInvitationController.
+func (controller *TenantInvitationControllerImpl) Accept(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.Token == "" {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	userMtmTenant, err := controller.Service.Accept(body.Token, userId)
+	if err != nil {
+		return invitationError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"added_user_id":    userMtmTenant.UserId,
+			"target_tenant_id": userMtmTenant.TenantId,
+			"role":             userMtmTenant.Role,
+		}))
+}
+
+// Body invitation_id, the error response is already sent when not ok
+func invitationIdBody(ctx *fiber.Ctx) (int, bool) {
+	var body struct {
+		InvitationId int `json:"invitation_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+		return 0, false
+	}
+	if body.InvitationId == 0 {
+		ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+		return 0, false
+	}
+
+	return body.InvitationId, true
+}
+
+func invitationError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrInvitationAccessDenied), errors.Is(err, repository.ErrInvitationEmailMismatch):
+		return ctx.Status(fiber.StatusForbidden).
+			JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+	case errors.Is(err, repository.ErrInvitationNotFound):
+		return ctx.Status(fiber.StatusNotFound).
+			JSON(common.NewWebResponseError(404, common.StatusError, err.Error()))
+	case errors.Is(err, repository.ErrInvitationAlreadyMember):
+		return ctx.Status(fiber.StatusConflict).
+			JSON(common.NewWebResponseError(409, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusBadRequest).
+		JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+}
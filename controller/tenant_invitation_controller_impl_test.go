@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"cashier-api/helper/mail"
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantInvitationControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.TenantInvitationRepositoryMock) {
+		invitationRepo := repository.NewTenantInvitationRepositoryMock(&mock.Mock{}).(*repository.TenantInvitationRepositoryMock)
+		invitationController := NewTenantInvitationControllerImpl(service.NewTenantInvitationServiceImpl(invitationRepo, mail.NewMemoryMailer()))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 5)
+			return ctx.Next()
+		})
+		app.Post("/tenant_invitations/accept", invitationController.Accept)
+		app.Post("/tenant_invitations/:tenantId", invitationController.Invite)
+		app.Delete("/tenant_invitations/:tenantId", invitationController.Revoke)
+		return app, invitationRepo
+	}
+
+	send := func(app *fiber.App, method string, path string, body string) *http.Response {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("Invite", func(t *testing.T) {
+		app, invitationRepo := newApp()
+		invitationRepo.Mock.On("Create", mock.MatchedBy(func(invitation *model.TenantInvitation) bool {
+			return invitation.Role == model.TenantRoleManager
+		}), 5).Return(nil, repository.ErrInvitationAccessDenied)
+		invitationRepo.Mock.On("Create", mock.MatchedBy(func(invitation *model.TenantInvitation) bool {
+			return invitation.Email == "member@example.com"
+		}), 5).Return(nil, repository.ErrInvitationAlreadyMember)
+
+		assert.Equal(t, http.StatusBadRequest, send(app, "POST", "/tenant_invitations/1", `{"email":"new@example.com"}`).StatusCode)
+		assert.Equal(t, http.StatusForbidden, send(app, "POST", "/tenant_invitations/1", `{"email":"new@example.com","role":"MANAGER"}`).StatusCode)
+		assert.Equal(t, http.StatusConflict, send(app, "POST", "/tenant_invitations/1", `{"email":"member@example.com","role":"CASHIER"}`).StatusCode)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		app, invitationRepo := newApp()
+		invitationRepo.Mock.On("Revoke", TENANT_ID, 3, 5).Return(nil)
+		invitationRepo.Mock.On("Revoke", TENANT_ID, 4, 5).Return(repository.ErrInvitationNotFound)
+
+		assert.Equal(t, http.StatusBadRequest, send(app, "DELETE", "/tenant_invitations/1", `{}`).StatusCode)
+		assert.Equal(t, http.StatusOK, send(app, "DELETE", "/tenant_invitations/1", `{"invitation_id":3}`).StatusCode)
+		assert.Equal(t, http.StatusNotFound, send(app, "DELETE", "/tenant_invitations/1", `{"invitation_id":4}`).StatusCode)
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		app, invitationRepo := newApp()
+		invitationRepo.Mock.On("Accept", token.Hash("invite-token"), 5, mock.AnythingOfType("time.Time")).
+			Return(&model.UserMtmTenant{UserId: 5, TenantId: TENANT_ID, Role: model.TenantRoleCashier}, nil)
+		invitationRepo.Mock.On("Accept", token.Hash("other-email"), 5, mock.AnythingOfType("time.Time")).
+			Return(nil, repository.ErrInvitationEmailMismatch)
+		invitationRepo.Mock.On("Accept", token.Hash("expired"), 5, mock.AnythingOfType("time.Time")).
+			Return(nil, repository.ErrInvitationInvalid)
+
+		assert.Equal(t, http.StatusBadRequest, send(app, "POST", "/tenant_invitations/accept", `{}`).StatusCode)
+		assert.Equal(t, http.StatusOK, send(app, "POST", "/tenant_invitations/accept", `{"token":"invite-token"}`).StatusCode)
+		assert.Equal(t, http.StatusForbidden, send(app, "POST", "/tenant_invitations/accept", `{"token":"other-email"}`).StatusCode)
+		assert.Equal(t, http.StatusBadRequest, send(app, "POST", "/tenant_invitations/accept", `{"token":"expired"}`).StatusCode)
+	})
+}
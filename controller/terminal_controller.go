@@ -0,0 +1,35 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type TerminalController interface {
+	/*
+		Register a terminal to a store, return the device token once. Owner or manager only
+	*/
+	Register(ctx *fiber.Ctx) error
+
+	/*
+		Every terminal of the tenant
+	*/
+	GetTerminals(ctx *fiber.Ctx) error
+
+	/*
+		Revoke a terminal, its sessions are signed out. Owner or manager only
+	*/
+	Revoke(ctx *fiber.Ctx) error
+
+	/*
+		Set the PIN of the signed in member for the tenant
+	*/
+	SetPin(ctx *fiber.Ctx) error
+
+	/*
+		Public, with the device token. Members who can sign in on the terminal
+	*/
+	GetMembers(ctx *fiber.Ctx) error
+
+	/*
+		Public, with the device token. Sign in a member with his PIN
+	*/
+	SignInWithPin(ctx *fiber.Ctx) error
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The device token given once at the terminal registration
+const TerminalTokenHeader = "X-Terminal-Token"
+
+type TerminalControllerImpl struct {
+	Service service.TerminalService
+}
+
+func NewTerminalControllerImpl(service service.TerminalService) TerminalController {
+	return &TerminalControllerImpl{Service: service}
+}
+
+// Register implements TerminalController.
+func (controller *TerminalControllerImpl) Register(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	var body struct {
+		StoreId int    `json:"store_id"`
+		Name    string `json:"name"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.StoreId == 0 || body.Name == "" {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	registration, err := controller.Service.Register(tenantId, body.StoreId, body.Name, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrTerminalAccessDenied) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+		}
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, registration))
+}
+
+// GetTerminals implements TerminalController.
+func (controller *TerminalControllerImpl) GetTerminals(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+
+	terminals, err := controller.Service.GetTerminals(tenantId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"terminals": terminals,
+		}))
+}
+
+// Revoke implements TerminalController.
+func (controller *TerminalControllerImpl) Revoke(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	var body struct {
+		TerminalId int `json:"terminal_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.TerminalId == 0 {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	err = controller.Service.Revoke(tenantId, body.TerminalId, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrTerminalAccessDenied) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+		}
+		if errors.Is(err, repository.ErrTerminalNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(common.NewWebResponseError(404, common.StatusError, err.Error()))
+		}
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Terminal revoked",
+		}))
+}
+
+// SetPin implements TerminalController.
+func (controller *TerminalControllerImpl) SetPin(ctx *fiber.Ctx) error {
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	userId := ctx.Locals("sub").(int)
+
+	var body struct {
+		Pin string `json:"pin"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	err = controller.Service.SetPin(tenantId, userId, body.Pin)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "PIN saved",
+		}))
+}
+
+// GetMembers implements TerminalController.
+func (controller *TerminalControllerImpl) GetMembers(ctx *fiber.Ctx) error {
+	deviceToken := ctx.Get(TerminalTokenHeader)
+	if deviceToken == "" {
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Terminal device token is required"))
+	}
+
+	terminal, members, err := controller.Service.GetMembers(deviceToken)
+	if err != nil {
+		return terminalError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"terminal": terminal,
+			"members":  members,
+		}))
+}
+
+// SignInWithPin implements TerminalController.
+func (controller *TerminalControllerImpl) SignInWithPin(ctx *fiber.Ctx) error {
+	deviceToken := ctx.Get(TerminalTokenHeader)
+	if deviceToken == "" {
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Terminal device token is required"))
+	}
+
+	var body struct {
+		UserId int    `json:"user_id"`
+		Pin    string `json:"pin"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.UserId == 0 || body.Pin == "" {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	user, tokens, err := controller.Service.SignInWithPin(deviceToken, body.UserId, body.Pin, sessionDevice(ctx, ""))
+	if err != nil {
+		return terminalError(ctx, err)
+	}
+
+	setAuthCookies(ctx, tokens)
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"user":             user,
+			"token":            tokens.AccessToken,
+			"token_expires_at": tokens.AccessTokenExpiresAt,
+		}))
+}
+
+func terminalError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTerminalInvalid), errors.Is(err, service.ErrPinInvalid):
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(common.NewWebResponseError(401, common.StatusError, err.Error()))
+	case errors.Is(err, service.ErrPinLocked):
+		return ctx.Status(fiber.StatusTooManyRequests).
+			JSON(common.NewWebResponseError(429, common.StatusError, err.Error()))
+	}
+	return ctx.Status(fiber.StatusBadRequest).
+		JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+}
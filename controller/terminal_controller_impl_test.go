@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalControllerImpl(t *testing.T) {
+	const TENANT_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.TerminalRepositoryMock) {
+		terminalRepo := repository.NewTerminalRepositoryMock(&mock.Mock{}).(*repository.TerminalRepositoryMock)
+		userRepo := repository.NewUserRepositoryMock(&mock.Mock{})
+		sessionRepo := repository.NewSessionRepositoryMock(&mock.Mock{})
+		terminalController := NewTerminalControllerImpl(service.NewTerminalServiceImpl(terminalRepo, userRepo, sessionRepo))
+
+		app := fiber.New()
+		app.Post("/terminals/sign_in", terminalController.SignInWithPin)
+		app.Post("/terminals/:tenantId", func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 5)
+			return ctx.Next()
+		}, terminalController.Register)
+		return app, terminalRepo
+	}
+
+	signIn := func(app *fiber.App, deviceToken string, body string) *http.Response {
+		request := httptest.NewRequest("POST", "/terminals/sign_in", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		if deviceToken != "" {
+			request.Header.Set(TerminalTokenHeader, deviceToken)
+		}
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("SignInWithPin", func(t *testing.T) {
+		app, terminalRepo := newApp()
+		lockedUntil := time.Now().Add(time.Minute)
+		terminalRepo.Mock.On("FindByToken", token.Hash("device-token")).Return(&model.Terminal{Id: 3, TenantId: TENANT_ID}, nil)
+		terminalRepo.Mock.On("FindByToken", token.Hash("unknown")).Return(nil, repository.ErrTerminalInvalid)
+		terminalRepo.Mock.On("FindPin", TENANT_ID, 5).Return(&model.TenantMemberPin{Id: 8, LockedUntil: &lockedUntil}, nil)
+		terminalRepo.Mock.On("FindPin", TENANT_ID, 6).Return(nil, repository.ErrPinNotSet)
+
+		assert.Equal(t, http.StatusUnauthorized, signIn(app, "", `{"user_id":5,"pin":"1234"}`).StatusCode)
+		assert.Equal(t, http.StatusBadRequest, signIn(app, "device-token", `{"user_id":5}`).StatusCode)
+		assert.Equal(t, http.StatusUnauthorized, signIn(app, "unknown", `{"user_id":5,"pin":"1234"}`).StatusCode)
+		assert.Equal(t, http.StatusUnauthorized, signIn(app, "device-token", `{"user_id":6,"pin":"1234"}`).StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, signIn(app, "device-token", `{"user_id":5,"pin":"1234"}`).StatusCode)
+	})
+
+	t.Run("RegisterNotManager", func(t *testing.T) {
+		app, terminalRepo := newApp()
+		terminalRepo.Mock.On("Create", mock.AnythingOfType("*model.Terminal"), 5).Return(nil, repository.ErrTerminalAccessDenied)
+
+		request := httptest.NewRequest("POST", "/terminals/1", strings.NewReader(`{"store_id":2,"name":"Till 1"}`))
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	})
+}
@@ -0,0 +1,33 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+/*
+Every route here required authentication, the user manage his own 2FA
+*/
+type TwoFactorController interface {
+	/*
+		Whether 2FA is enabled and the remaining recovery codes
+	*/
+	GetStatus(ctx *fiber.Ctx) error
+
+	/*
+		Start the enrolment, return the secret and the provisioning URI for the QR code
+	*/
+	Enroll(ctx *fiber.Ctx) error
+
+	/*
+		Confirm the enrolment with the first code, return the recovery codes once
+	*/
+	Confirm(ctx *fiber.Ctx) error
+
+	/*
+		Replace the recovery codes, return the new ones once
+	*/
+	RegenerateRecoveryCodes(ctx *fiber.Ctx) error
+
+	/*
+		Disable 2FA with a code or recovery code
+	*/
+	Disable(ctx *fiber.Ctx) error
+}
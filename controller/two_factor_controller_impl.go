@@ -0,0 +1,141 @@
+package controller
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/service"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TwoFactorControllerImpl struct {
+	Service service.TwoFactorService
+}
+
+func NewTwoFactorControllerImpl(service service.TwoFactorService) TwoFactorController {
+	return &TwoFactorControllerImpl{Service: service}
+}
+
+// GetStatus implements TwoFactorController.
+func (controller *TwoFactorControllerImpl) GetStatus(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+
+	status, err := controller.Service.GetStatus(userId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"two_factor": status,
+		}))
+}
+
+// Enroll implements TwoFactorController.
+func (controller *TwoFactorControllerImpl) Enroll(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+
+	enrolment, err := controller.Service.Enroll(userId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"enrolment": enrolment,
+		}))
+}
+
+// Confirm implements TwoFactorController.
+func (controller *TwoFactorControllerImpl) Confirm(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+	sessionId, _ := ctx.Locals("sid").(int)
+
+	code, ok := twoFactorCode(ctx)
+	if !ok {
+		return nil
+	}
+
+	recoveryCodes, err := controller.Service.Confirm(userId, sessionId, code)
+	if err != nil {
+		return twoFactorError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message":        "Two factor authentication enabled, other devices are signed out",
+			"recovery_codes": recoveryCodes,
+		}))
+}
+
+// RegenerateRecoveryCodes implements TwoFactorController.
+func (controller *TwoFactorControllerImpl) RegenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+
+	code, ok := twoFactorCode(ctx)
+	if !ok {
+		return nil
+	}
+
+	recoveryCodes, err := controller.Service.RegenerateRecoveryCodes(userId, code)
+	if err != nil {
+		return twoFactorError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"recovery_codes": recoveryCodes,
+		}))
+}
+
+// Disable implements TwoFactorController.
+func (controller *TwoFactorControllerImpl) Disable(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+
+	code, ok := twoFactorCode(ctx)
+	if !ok {
+		return nil
+	}
+
+	err := controller.Service.Disable(userId, code)
+	if err != nil {
+		return twoFactorError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Two factor authentication disabled",
+		}))
+}
+
+// Read the body code, the 400 response is already sent when not ok
+func twoFactorCode(ctx *fiber.Ctx) (string, bool) {
+	var body struct {
+		Code string `json:"code"`
+	}
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+		return "", false
+	}
+	if body.Code == "" {
+		ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+		return "", false
+	}
+
+	return body.Code, true
+}
+
+func twoFactorError(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrTwoFactorCodeInvalid) {
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(common.NewWebResponseError(401, common.StatusError, err.Error()))
+	}
+	return ctx.Status(fiber.StatusBadRequest).
+		JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+}
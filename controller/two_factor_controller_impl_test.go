@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"cashier-api/helper/totp"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorControllerImpl(t *testing.T) {
+	const USER_ID = 1
+	testTimeout := int((time.Second * 5).Milliseconds())
+
+	newApp := func() (*fiber.App, *repository.UserRepositoryMock, *repository.TwoFactorRepositoryMock, *repository.SessionRepositoryMock) {
+		userRepo := repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		twoFactorRepo := repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		sessionRepo := repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		twoFactorController := NewTwoFactorControllerImpl(service.NewTwoFactorServiceImpl(userRepo, twoFactorRepo, sessionRepo))
+
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", USER_ID)
+			ctx.Locals("sid", 9)
+			return ctx.Next()
+		})
+		app.Get("/users/two_factor", twoFactorController.GetStatus)
+		app.Post("/users/two_factor/enroll", twoFactorController.Enroll)
+		app.Post("/users/two_factor/confirm", twoFactorController.Confirm)
+		app.Delete("/users/two_factor", twoFactorController.Disable)
+		return app, userRepo, twoFactorRepo, sessionRepo
+	}
+
+	t.Run("EnrollAndConfirm", func(t *testing.T) {
+		app, userRepo, twoFactorRepo, sessionRepo := newApp()
+		userRepo.Mock.On("FindById", USER_ID).Return(&model.User{Id: USER_ID, Email: "owner@example.com"}, nil).Once()
+		twoFactorRepo.Mock.On("SaveSecret", USER_ID, mock.AnythingOfType("string")).Return(nil).Once()
+
+		request := httptest.NewRequest("POST", "/users/two_factor/enroll", nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var enrollBody struct {
+			Data struct {
+				Enrolment model.TwoFactorEnrolment `json:"enrolment"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &enrollBody))
+		secret := enrollBody.Data.Enrolment.Secret
+		require.NotEqual(t, "", secret)
+
+		userRepo.Mock.On("FindById", USER_ID).Return(&model.User{Id: USER_ID, TotpSecret: secret}, nil)
+
+		// Wrong code
+		request = httptest.NewRequest("POST", "/users/two_factor/confirm", strings.NewReader(`{"code":"abcdef"}`))
+		request.Header.Set("Content-Type", "application/json")
+		response, err = app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		twoFactorRepo.Mock.On("Enable", USER_ID, mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]string")).Return(nil).Once()
+		sessionRepo.Mock.On("RevokeAllOfUser", USER_ID, 9, model.SessionRevokeTwoFactor).Return(0, nil).Once()
+
+		request = httptest.NewRequest("POST", "/users/two_factor/confirm", strings.NewReader(fmt.Sprintf(`{"code":"%s"}`, code)))
+		request.Header.Set("Content-Type", "application/json")
+		response, err = app.Test(request, testTimeout)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+
+		byteBody, err = io.ReadAll(response.Body)
+		require.NoError(t, err)
+		var confirmBody struct {
+			Data struct {
+				RecoveryCodes []string `json:"recovery_codes"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(byteBody, &confirmBody))
+		assert.Len(t, confirmBody.Data.RecoveryCodes, model.RecoveryCodeCount)
+	})
+
+	t.Run("DisableEmptyCode", func(t *testing.T) {
+		app, userRepo, _, _ := newApp()
+
+		request := httptest.NewRequest("DELETE", "/users/two_factor", strings.NewReader(`{"code":""}`))
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		userRepo.Mock.AssertNotCalled(t, "FindById", USER_ID)
+	})
+}
@@ -5,5 +5,17 @@ import "github.com/gofiber/fiber/v2"
 type UserController interface {
 	SignUpWithEmailAndPassword(ctx *fiber.Ctx) error
 	SignInWithEmailAndPassword(ctx *fiber.Ctx) error
+	SignInWithTwoFactor(ctx *fiber.Ctx) error
 	SignOut(ctx *fiber.Ctx) error
+	Refresh(ctx *fiber.Ctx) error
+	GetSessions(ctx *fiber.Ctx) error
+	RevokeSession(ctx *fiber.Ctx) error
+	RevokeAllSessions(ctx *fiber.Ctx) error
+	ForgotPassword(ctx *fiber.Ctx) error
+	ResetPassword(ctx *fiber.Ctx) error
+	ChangePassword(ctx *fiber.Ctx) error
+	SendEmailVerification(ctx *fiber.Ctx) error
+	VerifyEmail(ctx *fiber.Ctx) error
+	GetSignInAttempts(ctx *fiber.Ctx) error
+	UnlockSignIn(ctx *fiber.Ctx) error
 }
@@ -3,10 +3,16 @@ package controller
 import (
 	common "cashier-api/helper"
 	constant "cashier-api/helper/constant/cookie"
+	"cashier-api/model"
+	"cashier-api/repository"
 	"cashier-api/service"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type UserControllerImpl struct {
@@ -21,9 +27,10 @@ func NewUserControllerImpl(service service.UserService) UserController {
 
 func (controller *UserControllerImpl) SignUpWithEmailAndPassword(ctx *fiber.Ctx) error {
 	var body struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-		Name     string `json:"name"`
+		Email      string `json:"email"`
+		Password   string `json:"password"`
+		Name       string `json:"name"`
+		DeviceName string `json:"device_name"`
 	}
 
 	err := ctx.BodyParser(&body)
@@ -45,36 +52,28 @@ func (controller *UserControllerImpl) SignUpWithEmailAndPassword(ctx *fiber.Ctx)
 	}
 
 	// We automatically signed in user
-	newCreatedUser, tokenString, err := controller.Service.SignInWithEmailAndPassword(newCreatedUser.Email, body.Password)
+	signedInUser, tokens, err := controller.Service.SignInWithEmailAndPassword(newCreatedUser.Email, body.Password, sessionDevice(ctx, body.DeviceName))
+	if errors.Is(err, service.ErrEmailNotVerified) {
+		return ctx.Status(fiber.StatusCreated).
+			JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
+				"user":    newCreatedUser,
+				"message": "Account created. Please open the link sent to your email before signing in",
+			}))
+	}
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).
 			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
 	}
-	if tokenString == "" {
-		return ctx.Status(fiber.StatusBadRequest).
-			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! Could not signed in user"))
-	}
 
 	// Apply to user cookie
-	// Create cookie
-	oneMonthFromNow := 24 * time.Hour * 30
-	cookie := &fiber.Cookie{
-		Name:     constant.EnterprisePOS,
-		Value:    tokenString,
-		Expires:  time.Now().Add(oneMonthFromNow),
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "None",
-		Path:     "/",
-	}
-
-	// Set cookie
-	ctx.Cookie(cookie)
+	setAuthCookies(ctx, tokens)
 
 	return ctx.Status(fiber.StatusCreated).
 		JSON(common.NewWebResponse(201, common.StatusSuccess, fiber.Map{
-			"user":  newCreatedUser,
-			"token": tokenString,
+			"user":             signedInUser,
+			"token":            tokens.AccessToken,
+			"token_expires_at": tokens.AccessTokenExpiresAt,
+			"refresh_token":    tokens.RefreshToken,
 		}))
 }
 
@@ -83,8 +82,9 @@ func (controller *UserControllerImpl) SignUpWithEmailAndPassword(ctx *fiber.Ctx)
 */
 func (controller *UserControllerImpl) SignInWithEmailAndPassword(ctx *fiber.Ctx) error {
 	var body struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email      string `json:"email"`
+		Password   string `json:"password"`
+		DeviceName string `json:"device_name"`
 	}
 
 	err := ctx.BodyParser(&body)
@@ -106,69 +106,522 @@ func (controller *UserControllerImpl) SignInWithEmailAndPassword(ctx *fiber.Ctx)
 			JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{"message": "Already created"}))
 	}
 
-	user, tokenString, err := controller.Service.SignInWithEmailAndPassword(body.Email, body.Password)
+	user, tokens, err := controller.Service.SignInWithEmailAndPassword(body.Email, body.Password, sessionDevice(ctx, body.DeviceName))
 	if err != nil {
 		if err.Error() == "No user with this credentials" {
 			return ctx.Status(fiber.StatusUnauthorized).
 				JSON(common.NewWebResponseError(401, common.StatusError, err.Error()))
 		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+		}
+		var signInBlocked *service.SignInBlockedError
+		if errors.As(err, &signInBlocked) {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(signInBlocked.RetryAt).Seconds())+1))
+			return ctx.Status(fiber.StatusTooManyRequests).
+				JSON(common.NewWebResponseError(429, common.StatusError, err.Error()))
+		}
+		// Password is right, the client ask the TOTP code and call /users/sign_in/two_factor
+		var twoFactorRequired *service.TwoFactorRequiredError
+		if errors.As(err, &twoFactorRequired) {
+			return ctx.Status(fiber.StatusOK).
+				JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+					"two_factor_required":         true,
+					"two_factor_token":            twoFactorRequired.Challenge.Token,
+					"two_factor_token_expires_at": twoFactorRequired.Challenge.ExpiresAt,
+				}))
+		}
 		return ctx.Status(fiber.StatusBadRequest).
 			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
 	}
-	if tokenString == "" {
+
+	// Apply to user cookie
+	setAuthCookies(ctx, tokens)
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"user":             user,
+			"token":            tokens.AccessToken,
+			"token_expires_at": tokens.AccessTokenExpiresAt,
+			"refresh_token":    tokens.RefreshToken,
+		}))
+}
+
+/*
+200, 400, 401
+
+	The refresh token is read from its cookie, or the body for client without cookie
+*/
+func (controller *UserControllerImpl) SignInWithTwoFactor(ctx *fiber.Ctx) error {
+	var body struct {
+		TwoFactorToken string `json:"two_factor_token"`
+		Code           string `json:"code"`
+		DeviceName     string `json:"device_name"`
+	}
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).
-			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! Could not signed in user"))
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
 	}
 
-	// Apply to user cookie
-	// Create cookie
-	oneMonthFromNow := 24 * time.Hour * 30
-	cookie := &fiber.Cookie{
-		Name:     constant.EnterprisePOS,
-		Value:    tokenString,
-		Expires:  time.Now().Add(oneMonthFromNow),
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "None",
-		Path:     "/",
+	if body.TwoFactorToken == "" || body.Code == "" {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	user, tokens, err := controller.Service.SignInWithTwoFactor(body.TwoFactorToken, body.Code, sessionDevice(ctx, body.DeviceName))
+	if err != nil {
+		if errors.Is(err, service.ErrTwoFactorCodeInvalid) || errors.Is(err, service.ErrTwoFactorChallengeInvalid) {
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(common.NewWebResponseError(401, common.StatusError, err.Error()))
+		}
+		var signInBlocked *service.SignInBlockedError
+		if errors.As(err, &signInBlocked) {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(signInBlocked.RetryAt).Seconds())+1))
+			return ctx.Status(fiber.StatusTooManyRequests).
+				JSON(common.NewWebResponseError(429, common.StatusError, err.Error()))
+		}
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	setAuthCookies(ctx, tokens)
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"user":             user,
+			"token":            tokens.AccessToken,
+			"token_expires_at": tokens.AccessTokenExpiresAt,
+			"refresh_token":    tokens.RefreshToken,
+		}))
+}
+
+func (controller *UserControllerImpl) Refresh(ctx *fiber.Ctx) error {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
 	}
 
-	// Set cookie
-	ctx.Cookie(cookie)
+	refreshToken := ctx.Cookies(constant.EnterprisePOSRefresh)
+	if refreshToken == "" {
+		err := ctx.BodyParser(&body)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+		}
+		refreshToken = body.RefreshToken
+	}
+
+	user, tokens, err := controller.Service.Refresh(refreshToken)
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenInvalid) || errors.Is(err, repository.ErrRefreshTokenReused) {
+			clearAuthCookies(ctx)
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(common.NewWebResponseError(401, common.StatusError, err.Error()))
+		}
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	setAuthCookies(ctx, tokens)
 
 	return ctx.Status(fiber.StatusOK).
 		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
-			"user":  user,
-			"token": tokenString,
+			"user":             user,
+			"token":            tokens.AccessToken,
+			"token_expires_at": tokens.AccessTokenExpiresAt,
+			"refresh_token":    tokens.RefreshToken,
 		}))
 }
 
-// SignOut implements UserController.
+/*
+SignOut implements UserController.
+
+	Revoke the session of the refresh token, or the sid of the access token.
+	The cookies are cleared even when the session could not be found
+*/
 func (controller *UserControllerImpl) SignOut(ctx *fiber.Ctx) error {
-	enterprisePosCookie := ctx.Cookies(constant.EnterprisePOS, "")
-	if enterprisePosCookie == "" {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	refreshToken := ctx.Cookies(constant.EnterprisePOSRefresh)
+	if refreshToken == "" && len(ctx.Body()) > 0 {
+		err := ctx.BodyParser(&body)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+		}
+		refreshToken = body.RefreshToken
+	}
+
+	sessionId := 0
+	if refreshToken == "" {
+		sessionId = sessionIdFromAccessToken(ctx)
+	}
+
+	if refreshToken == "" && sessionId == 0 {
+		clearAuthCookies(ctx)
 		return ctx.Status(fiber.StatusOK).
 			JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
 				"message": "Required cookie not available / already signed out",
 			}))
-	} else {
-		// Clear the credential jwt
-		// ctx.ClearCookie(constant.EnterprisePOS)
-
-		ctx.Cookie(&fiber.Cookie{
-			Name:     constant.EnterprisePOS,
-			Value:    "",
-			Expires:  time.Unix(0, 0),
-			MaxAge:   -1,
-			HTTPOnly: true,
-			Secure:   true,
-			SameSite: "None",
-			Path:     "/",
-		})
+	}
 
-		return ctx.Status(fiber.StatusOK).
-			JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
-				"message": "Signed out successfully",
-			}))
+	err := controller.Service.SignOut(refreshToken, sessionId)
+	clearAuthCookies(ctx)
+	if err != nil && !errors.Is(err, repository.ErrRefreshTokenInvalid) {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
 	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Signed out successfully",
+		}))
+}
+
+// GetSessions implements UserController.
+func (controller *UserControllerImpl) GetSessions(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+	sessionId, _ := ctx.Locals("sid").(int)
+
+	sessions, err := controller.Service.GetSessions(userId, sessionId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"sessions": sessions,
+		}))
+}
+
+/*
+RevokeSession implements UserController.
+
+	Revoking the current session is the same as sign out, the cookies are cleared
+*/
+func (controller *UserControllerImpl) RevokeSession(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+	currentSessionId, _ := ctx.Locals("sid").(int)
+
+	var body struct {
+		SessionId int `json:"session_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	err = controller.Service.RevokeSession(userId, body.SessionId)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(common.NewWebResponseError(404, common.StatusError, err.Error()))
+		}
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+	if body.SessionId == currentSessionId {
+		clearAuthCookies(ctx)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Session revoked",
+		}))
+}
+
+// RevokeAllSessions implements UserController.
+func (controller *UserControllerImpl) RevokeAllSessions(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+	currentSessionId, _ := ctx.Locals("sid").(int)
+	includeCurrent := ctx.QueryBool("include_current", false)
+
+	revoked, err := controller.Service.RevokeAllSessions(userId, currentSessionId, includeCurrent)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+	if includeCurrent {
+		clearAuthCookies(ctx)
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"revoked": revoked,
+		}))
+}
+
+/*
+ForgotPassword implements UserController.
+
+	The answer is the same whether the email is registered or not
+*/
+func (controller *UserControllerImpl) ForgotPassword(ctx *fiber.Ctx) error {
+	var body struct {
+		Email string `json:"email"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	err = controller.Service.RequestPasswordReset(body.Email)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "If the email is registered, a reset link has been sent",
+		}))
+}
+
+// ResetPassword implements UserController.
+func (controller *UserControllerImpl) ResetPassword(ctx *fiber.Ctx) error {
+	var body struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	err = controller.Service.ResetPassword(body.Token, body.Password)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+	clearAuthCookies(ctx)
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Password has been reset, please sign in again",
+		}))
+}
+
+// ChangePassword implements UserController.
+func (controller *UserControllerImpl) ChangePassword(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+	sessionId, _ := ctx.Locals("sid").(int)
+
+	var body struct {
+		OldPassword string `json:"old_password"`
+		NewPassword string `json:"new_password"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.OldPassword == "" || body.NewPassword == "" {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	err = controller.Service.ChangePassword(userId, sessionId, body.OldPassword, body.NewPassword)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Password changed, other devices are signed out",
+		}))
+}
+
+// SendEmailVerification implements UserController.
+func (controller *UserControllerImpl) SendEmailVerification(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+
+	err := controller.Service.SendEmailVerification(userId)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Verification link has been sent",
+		}))
+}
+
+// VerifyEmail implements UserController.
+func (controller *UserControllerImpl) VerifyEmail(ctx *fiber.Ctx) error {
+	var body struct {
+		Token string `json:"token"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+
+	err = controller.Service.VerifyEmail(body.Token)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Email verified",
+		}))
+}
+
+// Device of the sign in, the name is given by the client (e.g. "Cashier tablet 2")
+func (controller *UserControllerImpl) GetSignInAttempts(ctx *fiber.Ctx) error {
+	userId := ctx.Locals("sub").(int)
+
+	limit, err := strconv.Atoi(ctx.Query("limit", "10"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check limit URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page", "1"))
+	if err != nil {
+		response := common.NewWebResponseError(fiber.StatusBadRequest, common.StatusError, "Please check page URL parameter")
+		return ctx.Status(fiber.StatusBadRequest).JSON(response)
+	}
+
+	attempts, count, err := controller.Service.GetSignInAttempts(userId, limit, page)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"page":             page,
+			"limit":            limit,
+			"count":            count,
+			"sign_in_attempts": attempts,
+		}))
+}
+
+func (controller *UserControllerImpl) UnlockSignIn(ctx *fiber.Ctx) error {
+	// It's guaranteed to be not "", because restrict by tenant already did check first
+	tenantId, _ := strconv.Atoi(ctx.Params("tenantId"))
+	sub := ctx.Locals("sub").(int)
+
+	var body struct {
+		UserId int `json:"user_id"`
+	}
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Something gone wrong ! The request body is malformed"))
+	}
+	if body.UserId == 0 {
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, "Do not leave empty input ! Please check the inputted data"))
+	}
+
+	err = controller.Service.UnlockSignIn(tenantId, body.UserId, sub)
+	if err != nil {
+		if errors.Is(err, repository.ErrUnlockAccessDenied) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, err.Error()))
+		}
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(common.NewWebResponseError(400, common.StatusError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(common.NewWebResponse(200, common.StatusSuccess, fiber.Map{
+			"message": "Sign in unlocked",
+		}))
+}
+
+func sessionDevice(ctx *fiber.Ctx, deviceName string) model.SessionDevice {
+	return model.SessionDevice{
+		DeviceName: deviceName,
+		UserAgent:  ctx.Get(fiber.HeaderUserAgent),
+		IpAddress:  ctx.IP(),
+	}
+}
+
+// The sid claim of the access token (cookie or Bearer), 0 when missing or not valid
+func sessionIdFromAccessToken(ctx *fiber.Ctx) int {
+	tokenString := ctx.Cookies(constant.EnterprisePOS)
+	if tokenString == "" {
+		tokenString = strings.TrimPrefix(ctx.Get("Authorization"), "Bearer ")
+	}
+	if tokenString == "" {
+		return 0
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := common.ClaimJWT(tokenString, &claims)
+	if err != nil || !token.Valid {
+		return 0
+	}
+	sid, ok := claims["sid"].(float64)
+	if !ok {
+		return 0
+	}
+	return int(sid)
+}
+
+// Access token cookie live as long as the token, the refresh token cookie only go to /api/v1/users.
+// No refresh token (PIN sign in) clear the cookie of the previous user
+func setAuthCookies(ctx *fiber.Ctx, tokens *model.AuthTokens) {
+	ctx.Cookie(&fiber.Cookie{
+		Name:     constant.EnterprisePOS,
+		Value:    tokens.AccessToken,
+		Expires:  tokens.AccessTokenExpiresAt,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+		Path:     "/",
+	})
+
+	refreshCookie := &fiber.Cookie{
+		Name:     constant.EnterprisePOSRefresh,
+		Value:    tokens.RefreshToken,
+		Expires:  tokens.RefreshTokenExpiresAt,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+		Path:     constant.EnterprisePOSRefreshPath,
+	}
+	if tokens.RefreshToken == "" {
+		refreshCookie.Expires = time.Unix(0, 0)
+		refreshCookie.MaxAge = -1
+	}
+	ctx.Cookie(refreshCookie)
+}
+
+func clearAuthCookies(ctx *fiber.Ctx) {
+	ctx.Cookie(&fiber.Cookie{
+		Name:     constant.EnterprisePOS,
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+		Path:     "/",
+	})
+	ctx.Cookie(&fiber.Cookie{
+		Name:     constant.EnterprisePOSRefresh,
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "None",
+		Path:     constant.EnterprisePOSRefreshPath,
+	})
 }
@@ -3,6 +3,7 @@ package controller
 import (
 	"cashier-api/helper/client"
 	constant "cashier-api/helper/constant/cookie"
+	"cashier-api/helper/mail"
 	"cashier-api/repository"
 	"cashier-api/service"
 	"io"
@@ -26,11 +27,12 @@ func TestUserControllerImpl(t *testing.T) {
 	supabaseClient := client.CreateSupabaseClient()
 	gormClient := client.CreateGormClient()
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	userService := service.NewUserServiceImpl(userRepository, repository.NewSessionRepositoryImpl(gormClient), repository.NewUserTokenRepositoryImpl(gormClient), repository.NewTwoFactorRepositoryImpl(gormClient), repository.NewSignInAttemptRepositoryImpl(gormClient), mail.NewMemoryMailer())
 	userController := NewUserControllerImpl(userService)
 	app := fiber.New()
 	app.Post("/users/sign_up", userController.SignUpWithEmailAndPassword)
 	app.Post("/users/sign_in", userController.SignInWithEmailAndPassword)
+	app.Post("/users/refresh", userController.Refresh)
 	app.Delete("users/sign_out", userController.SignOut)
 
 	t.Run("SignUp", func(t *testing.T) {
@@ -313,4 +315,71 @@ func TestUserControllerImpl(t *testing.T) {
 			require.Nil(t, err, "If this failed, then delete data at DB. error at TestUserControllerImpl_SignOut1")
 		})
 	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		body := strings.NewReader(`{
+			"email": "testusercontroller_refresh@gmail.com",
+			"password": "12345678",
+			"name": "Test User"
+		}`)
+		request := httptest.NewRequest("POST", "/users/sign_up", body)
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, int(time.Second*5))
+		require.Nil(t, err)
+		require.Equal(t, 201, response.StatusCode)
+
+		var refreshCookie *http.Cookie
+		for _, c := range response.Cookies() {
+			if c.Name == constant.EnterprisePOSRefresh {
+				refreshCookie = c
+			}
+		}
+		require.NotNil(t, refreshCookie)
+
+		t.Run("Rotated", func(t *testing.T) {
+			request = httptest.NewRequest("POST", "/users/refresh", nil)
+			request.AddCookie(refreshCookie)
+			response, err = app.Test(request, int(time.Second*5))
+			require.Nil(t, err)
+			assert.Equal(t, 200, response.StatusCode)
+
+			rotated := false
+			for _, c := range response.Cookies() {
+				if c.Name == constant.EnterprisePOSRefresh {
+					rotated = true
+					assert.NotEqual(t, refreshCookie.Value, c.Value)
+				}
+			}
+			assert.True(t, rotated)
+		})
+
+		t.Run("ReuseRevokeSession", func(t *testing.T) {
+			// The same refresh token again, the session is revoked
+			request = httptest.NewRequest("POST", "/users/refresh", nil)
+			request.AddCookie(refreshCookie)
+			response, err = app.Test(request, int(time.Second*5))
+			require.Nil(t, err)
+			assert.Equal(t, 401, response.StatusCode)
+
+			bytes, err := io.ReadAll(response.Body)
+			require.Nil(t, err)
+			assert.Contains(t, string(bytes), repository.ErrRefreshTokenReused.Error())
+		})
+
+		t.Run("BodyToken", func(t *testing.T) {
+			request = httptest.NewRequest("POST", "/users/refresh", strings.NewReader(`{"refresh_token": "unknown"}`))
+			request.Header.Set("Content-Type", "application/json")
+			response, err = app.Test(request, int(time.Second*5))
+			require.Nil(t, err)
+			assert.Equal(t, 401, response.StatusCode)
+		})
+
+		_, _, err = supabaseClient.From(repository.UserTable).
+			Delete("", "").
+			Eq("email", "testusercontroller_refresh@gmail.com").
+			Eq("name", "Test User").
+			Execute()
+
+		require.Nil(t, err, "If this failed, then delete data at DB. error at TestUserControllerImpl_Refresh")
+	})
 }
@@ -4,6 +4,7 @@ import (
 	common "cashier-api/helper"
 	"cashier-api/helper/client"
 	constant "cashier-api/helper/constant/cookie"
+	"cashier-api/helper/mail"
 	"cashier-api/middleware"
 	"cashier-api/model"
 	"cashier-api/repository"
@@ -48,13 +49,13 @@ func TestWarehouseControllerImpl(t *testing.T) {
 
 	// user
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	userService := service.NewUserServiceImpl(userRepository, repository.NewSessionRepositoryImpl(gormClient), repository.NewUserTokenRepositoryImpl(gormClient), repository.NewTwoFactorRepositoryImpl(gormClient), repository.NewSignInAttemptRepositoryImpl(gormClient), mail.NewMemoryMailer())
 	userController := NewUserControllerImpl(userService)
 
 	app.Post("/users/sign_in", userController.SignInWithEmailAndPassword)
 
 	// warehouse
-	app.Use(middleware.ProtectedRoute)                           // Must login
+	app.Use(middleware.ProtectedRoute(gormClient))               // Must login
 	tenantRestriction := middleware.RestrictByTenant(gormClient) // User only allowed to access associated tenant
 	app.Get("/warehouses/:tenantId", tenantRestriction, warehouseController.Get)
 	app.Get("/warehouses/active/:tenantId", tenantRestriction, warehouseController.GetActiveItem)
@@ -3,3 +3,8 @@ package constant
 type EnterprisePOSCookie = string
 
 const EnterprisePOS EnterprisePOSCookie = "_enterprise_pos"
+
+// Only sent to /api/v1/users, where the refresh and sign out endpoint are
+const EnterprisePOSRefresh EnterprisePOSCookie = "_enterprise_pos_refresh"
+
+const EnterprisePOSRefreshPath string = "/api/v1/users"
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []Column
+}
+
+func newCSVWriter(w io.Writer, title Title, columns []Column) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+
+	for _, line := range title.lines() {
+		if err := writer.Write(line[:]); err != nil {
+			return nil, err
+		}
+	}
+	// Empty line between the title block and the table
+	if err := writer.Write([]string{""}); err != nil {
+		return nil, err
+	}
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	if err := writer.Write(headers); err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{writer: writer, columns: columns}, nil
+}
+
+// WriteRow implements Writer.
+func (writer *csvWriter) WriteRow(values ...any) error {
+	if err := checkRowLength(values, writer.columns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value, writer.columns[i].Type, false)
+	}
+
+	// csv.Writer flush by itself when its buffer is full
+	return writer.writer.Write(record)
+}
+
+// Close implements Writer.
+func (writer *csvWriter) Close() error {
+	writer.writer.Flush()
+	return writer.writer.Error()
+}
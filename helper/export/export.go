@@ -0,0 +1,201 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatPDF  Format = "pdf"
+)
+
+func (format Format) IsValid() bool {
+	switch format {
+	case FormatCSV, FormatXLSX, FormatPDF:
+		return true
+	}
+	return false
+}
+
+func (format Format) ContentType() string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// FileName append the extension of the format, e.g. "order_items" -> "order_items.csv"
+func (format Format) FileName(name string) string {
+	return name + "." + string(format)
+}
+
+type ColumnType int
+
+const (
+	ColumnText     ColumnType = iota
+	ColumnInteger             // quantity, count, id
+	ColumnCurrency            // Rp without decimal
+	ColumnPercent             // 0 - 100 with 2 decimal
+	ColumnDateTime            // time.Time or *time.Time
+)
+
+/*
+Column of an export, Width is in character (0 use the default width of the type)
+*/
+type Column struct {
+	Header string
+	Type   ColumnType
+	Width  float64
+}
+
+func (column Column) width() float64 {
+	if column.Width > 0 {
+		return column.Width
+	}
+
+	switch column.Type {
+	case ColumnInteger, ColumnPercent:
+		return 12
+	case ColumnCurrency:
+		return 18
+	case ColumnDateTime:
+		return 20
+	}
+	return 30
+}
+
+/*
+Title block printed above the header of every format
+*/
+type Title struct {
+	Name        string // e.g. "Order Items"
+	Tenant      string
+	Store       string
+	Period      string // optional, e.g. "2026-01-01 - 2026-01-31"
+	GeneratedAt time.Time
+}
+
+func (title Title) lines() [][2]string {
+	lines := [][2]string{
+		{"Report", title.Name},
+		{"Tenant", title.Tenant},
+		{"Store", title.Store},
+	}
+	if title.Period != "" {
+		lines = append(lines, [2]string{"Period", title.Period})
+	}
+
+	return append(lines, [2]string{"Generated At", title.GeneratedAt.Format("02 Jan 2006 15:04:05")})
+}
+
+/*
+Writer stream the rows of 1 export into the underlying io.Writer,
+rows are not kept in memory. Close must be called to finish the file
+*/
+type Writer interface {
+	/*
+		Values follow the order of the columns
+	*/
+	WriteRow(values ...any) error
+	Close() error
+}
+
+func NewWriter(w io.Writer, format Format, title Title, columns []Column) (Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("export need at least 1 column")
+	}
+
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, title, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, title, columns)
+	case FormatPDF:
+		return newPDFWriter(w, title, columns)
+	}
+	return nil, fmt.Errorf("Invalid export format: %s. Allowed: csv, xlsx, pdf", format)
+}
+
+func checkRowLength(values []any, columns []Column) error {
+	if len(values) != len(columns) {
+		return fmt.Errorf("export row has %d values, expected %d", len(values), len(columns))
+	}
+	return nil
+}
+
+/*
+formatValue return the text of the value, display = true group the thousands
+of currency (PDF), otherwise the number stay parseable (CSV)
+*/
+func formatValue(value any, columnType ColumnType, display bool) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return formatValue(*v, columnType, display)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return formatValue(*v, columnType, display)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	case string:
+		return v
+	case int:
+		if columnType == ColumnCurrency && display {
+			return groupThousands(int64(v))
+		}
+		return strconv.Itoa(v)
+	case int64:
+		if columnType == ColumnCurrency && display {
+			return groupThousands(v)
+		}
+		return strconv.FormatInt(v, 10)
+	case float64:
+		if columnType == ColumnCurrency && display {
+			return groupThousands(int64(v))
+		}
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case bool:
+		if v {
+			return "Yes"
+		}
+		return "No"
+	}
+	return fmt.Sprint(value)
+}
+
+// 1234567 -> "1,234,567", same as Excel #,##0
+func groupThousands(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	digits := strconv.FormatInt(n, 10)
+	var builder strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteRune(digit)
+	}
+	return sign + builder.String()
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExport(t *testing.T) {
+	generatedAt := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+	title := Title{Name: "Order Items", Tenant: "Tenant", Store: "All Stores", GeneratedAt: generatedAt}
+	columns := []Column{
+		{Header: "Id", Type: ColumnInteger},
+		{Header: "Name", Type: ColumnText},
+		{Header: "Total (Rp)", Type: ColumnCurrency},
+		{Header: "Share (%)", Type: ColumnPercent},
+		{Header: "Created At", Type: ColumnDateTime},
+	}
+
+	write := func(t *testing.T, format Format, rows int) []byte {
+		var buffer bytes.Buffer
+		writer, err := NewWriter(&buffer, format, title, columns)
+		require.NoError(t, err)
+
+		for i := 0; i < rows; i++ {
+			require.NoError(t, writer.WriteRow(i+1, "Café (hot)", 1_250_000, 12.5, generatedAt))
+		}
+		require.NoError(t, writer.Close())
+		return buffer.Bytes()
+	}
+
+	t.Run("Format", func(t *testing.T) {
+		assert.True(t, FormatPDF.IsValid())
+		assert.False(t, Format("docx").IsValid())
+		assert.Equal(t, "order_items.xlsx", FormatXLSX.FileName("order_items"))
+		assert.Equal(t, "application/pdf", FormatPDF.ContentType())
+
+		_, err := NewWriter(&bytes.Buffer{}, "docx", title, columns)
+		assert.Error(t, err)
+		_, err = NewWriter(&bytes.Buffer{}, FormatCSV, title, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("FormatValue", func(t *testing.T) {
+		assert.Equal(t, "1,250,000", formatValue(1_250_000, ColumnCurrency, true))
+		assert.Equal(t, "-1,000", formatValue(-1000, ColumnCurrency, true))
+		assert.Equal(t, "999", formatValue(999, ColumnCurrency, true))
+		assert.Equal(t, "1250000", formatValue(1_250_000, ColumnCurrency, false))
+		assert.Equal(t, "12.50", formatValue(12.5, ColumnPercent, true))
+		assert.Equal(t, "", formatValue((*int)(nil), ColumnInteger, true))
+		assert.Equal(t, "2026-01-31 10:00:00", formatValue(&generatedAt, ColumnDateTime, true))
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		reader := csv.NewReader(bytes.NewReader(write(t, FormatCSV, 2)))
+		reader.FieldsPerRecord = -1 // title block has 2 fields
+		records, err := reader.ReadAll()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Report", "Order Items"}, records[0])
+		// Title block, header then 2 rows, csv.Reader skip the empty line
+		header := len(title.lines())
+		assert.Equal(t, []string{"Id", "Name", "Total (Rp)", "Share (%)", "Created At"}, records[header])
+		assert.Equal(t, []string{"1", "Café (hot)", "1250000", "12.50", "2026-01-31 10:00:00"}, records[header+1])
+		assert.Len(t, records, header+3)
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		f, err := excelize.OpenReader(bytes.NewReader(write(t, FormatXLSX, 3)))
+		require.NoError(t, err)
+		defer f.Close()
+
+		rows, err := f.GetRows("Report")
+		require.NoError(t, err)
+		assert.Equal(t, "Order Items", rows[0][1])
+
+		header := len(title.lines()) + 1
+		assert.Equal(t, "Total (Rp)", rows[header][2])
+		assert.Equal(t, "1,250,000", rows[header+1][2]) // #,##0
+		assert.Len(t, rows, header+4)
+	})
+
+	t.Run("PDF", func(t *testing.T) {
+		pdf := write(t, FormatPDF, 100)
+
+		assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+		assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+		assert.Contains(t, string(pdf), `Caf\351 \(hot\)`)
+		assert.Contains(t, string(pdf), "(1,250,000)")
+
+		// 100 rows does not fit into 1 page
+		assert.Contains(t, string(pdf), "/Count 3")
+		assert.Equal(t, 3, strings.Count(string(pdf), "/Type /Page /Parent"))
+	})
+
+	t.Run("WrongRowLength", func(t *testing.T) {
+		for _, format := range []Format{FormatCSV, FormatXLSX, FormatPDF} {
+			writer, err := NewWriter(&bytes.Buffer{}, format, title, columns)
+			require.NoError(t, err)
+			assert.Error(t, writer.WriteRow(1, "only 2"))
+			require.NoError(t, writer.Close())
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+Minimal PDF 1.4 writer, enough for a table of text:
+
+	A4 landscape, built-in Helvetica (no font embedding, WinAnsi only, other character become "?")
+	Each page is written as soon as it's full, only the current page is kept in memory.
+	Pages object (2) is written last because its kids are only known at the end
+*/
+const (
+	pdfPageWidth  = 842.0
+	pdfPageHeight = 595.0
+	pdfMargin     = 36.0
+	pdfFontSize   = 8.0
+	pdfTitleSize  = 12.0
+	pdfRowHeight  = 13.0
+
+	pdfCatalogObject  = 1
+	pdfPagesObject    = 2
+	pdfFontObject     = 3
+	pdfBoldFontObject = 4
+)
+
+type pdfWriter struct {
+	out     *bufio.Writer
+	written int
+	offsets map[int]int
+	nextId  int
+	pageIds []int
+
+	columns []Column
+	widths  []float64 // in point
+	title   Title
+
+	page    *bytes.Buffer // content stream of the current page
+	cursorY float64
+}
+
+func newPDFWriter(w io.Writer, title Title, columns []Column) (*pdfWriter, error) {
+	writer := &pdfWriter{
+		out:     bufio.NewWriter(w),
+		offsets: make(map[int]int),
+		nextId:  pdfBoldFontObject + 1,
+		columns: columns,
+		title:   title,
+	}
+
+	// Scale the column width to the usable width of the page
+	total := 0.0
+	for _, column := range columns {
+		total += column.width()
+	}
+	usable := pdfPageWidth - 2*pdfMargin
+	writer.widths = make([]float64, len(columns))
+	for i, column := range columns {
+		writer.widths[i] = column.width() / total * usable
+	}
+
+	// Binary comment tell transfer tools the file is binary
+	if err := writer.raw("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return nil, err
+	}
+	if err := writer.object(pdfCatalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObject)); err != nil {
+		return nil, err
+	}
+	if err := writer.object(pdfFontObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"); err != nil {
+		return nil, err
+	}
+	if err := writer.object(pdfBoldFontObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"); err != nil {
+		return nil, err
+	}
+
+	writer.newPage()
+	return writer, nil
+}
+
+func (writer *pdfWriter) raw(s string) error {
+	n, err := writer.out.WriteString(s)
+	writer.written += n
+	return err
+}
+
+func (writer *pdfWriter) object(id int, body string) error {
+	writer.offsets[id] = writer.written
+	return writer.raw(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", id, body))
+}
+
+// newPage start the content of a page, title block only at the first page
+func (writer *pdfWriter) newPage() {
+	writer.page = &bytes.Buffer{}
+	writer.cursorY = pdfPageHeight - pdfMargin
+
+	if len(writer.pageIds) == 0 {
+		writer.text(pdfMargin, writer.cursorY-pdfTitleSize, pdfTitleSize, true, writer.title.Name)
+		writer.cursorY -= pdfTitleSize + 8
+
+		for _, line := range writer.title.lines()[1:] {
+			writer.text(pdfMargin, writer.cursorY-pdfFontSize, pdfFontSize, true, line[0])
+			writer.text(pdfMargin+70, writer.cursorY-pdfFontSize, pdfFontSize, false, line[1])
+			writer.cursorY -= pdfRowHeight
+		}
+		writer.cursorY -= pdfRowHeight / 2
+	}
+
+	// Header is repeated at every page
+	headers := make([]string, len(writer.columns))
+	for i, column := range writer.columns {
+		headers[i] = column.Header
+	}
+	writer.row(headers, true)
+
+	// Line under the header
+	fmt.Fprintf(writer.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n",
+		pdfMargin, writer.cursorY+3, pdfPageWidth-pdfMargin, writer.cursorY+3)
+	writer.cursorY -= 2
+}
+
+func (writer *pdfWriter) row(values []string, bold bool) {
+	x := pdfMargin
+	y := writer.cursorY - pdfFontSize
+	for i, value := range values {
+		width := writer.widths[i]
+		value = fitText(value, width-4, pdfFontSize)
+
+		textX := x + 2
+		// Number is aligned to the right
+		if !bold && writer.columns[i].Type != ColumnText && writer.columns[i].Type != ColumnDateTime {
+			textX = x + width - 2 - textWidth(value, pdfFontSize)
+		}
+		writer.text(textX, y, pdfFontSize, bold, value)
+		x += width
+	}
+	writer.cursorY -= pdfRowHeight
+}
+
+func (writer *pdfWriter) text(x, y, size float64, bold bool, value string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(writer.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDFText(value))
+}
+
+// flushPage write the content and the page object of the current page
+func (writer *pdfWriter) flushPage() error {
+	pageNumber := fmt.Sprintf("Page %d", len(writer.pageIds)+1)
+	writer.text(pdfPageWidth-pdfMargin-textWidth(pageNumber, pdfFontSize), pdfMargin/2, pdfFontSize, false, pageNumber)
+
+	contentId := writer.nextId
+	pageId := writer.nextId + 1
+	writer.nextId += 2
+
+	content := writer.page.String()
+	if err := writer.object(contentId, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content)); err != nil {
+		return err
+	}
+	err := writer.object(pageId, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObject, pdfPageWidth, pdfPageHeight, pdfFontObject, pdfBoldFontObject, contentId,
+	))
+	if err != nil {
+		return err
+	}
+
+	writer.pageIds = append(writer.pageIds, pageId)
+	writer.page = nil
+	return nil
+}
+
+// WriteRow implements Writer.
+func (writer *pdfWriter) WriteRow(values ...any) error {
+	if err := checkRowLength(values, writer.columns); err != nil {
+		return err
+	}
+
+	if writer.cursorY-pdfRowHeight < pdfMargin {
+		if err := writer.flushPage(); err != nil {
+			return err
+		}
+		writer.newPage()
+	}
+
+	texts := make([]string, len(values))
+	for i, value := range values {
+		texts[i] = formatValue(value, writer.columns[i].Type, true)
+	}
+	writer.row(texts, false)
+	return nil
+}
+
+// Close implements Writer.
+func (writer *pdfWriter) Close() error {
+	if err := writer.flushPage(); err != nil {
+		return err
+	}
+
+	kids := make([]string, len(writer.pageIds))
+	for i, id := range writer.pageIds {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	err := writer.object(pdfPagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	if err != nil {
+		return err
+	}
+
+	// Cross reference table, every object from 1 to the last one
+	xrefOffset := writer.written
+	var xref strings.Builder
+	fmt.Fprintf(&xref, "xref\n0 %d\n0000000000 65535 f \n", writer.nextId)
+	for id := 1; id < writer.nextId; id++ {
+		fmt.Fprintf(&xref, "%010d 00000 n \n", writer.offsets[id])
+	}
+	fmt.Fprintf(&xref, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", writer.nextId, pdfCatalogObject, xrefOffset)
+	if err := writer.raw(xref.String()); err != nil {
+		return err
+	}
+
+	return writer.out.Flush()
+}
+
+// Average Helvetica glyph is about half of the font size, digit is 0.556
+func textWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * size * 0.556
+}
+
+func fitText(s string, width, size float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// escapePDFText encode into WinAnsi (Latin-1 subset) and escape the string delimiter
+func escapePDFText(s string) string {
+	var builder strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r >= 32 && r < 127:
+			builder.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&builder, "\\%03o", r)
+		default:
+			builder.WriteByte('?')
+		}
+	}
+	return builder.String()
+}
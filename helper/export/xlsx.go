@@ -0,0 +1,235 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/*
+XlsxStyles is the common look of every workbook, so hand-written sheet
+(e.g. profit export with its charts) look the same as the generic export
+*/
+type XlsxStyles struct {
+	Title    int
+	Label    int
+	Header   int
+	Text     int
+	Integer  int
+	Currency int // #,##0
+	Percent  int // 0.00
+
+	// Bold currency, the column a hand-written table highlight (e.g. profit)
+	Emphasis int // #,##0
+
+	// Total row at the end of a hand-written table
+	Total        int // #,##0
+	TotalPercent int // 0.00
+}
+
+func NewXlsxStyles(f *excelize.File) (*XlsxStyles, error) {
+	cellBorder := []excelize.Border{
+		{Type: "left", Color: "CCCCCC", Style: 1},
+		{Type: "right", Color: "CCCCCC", Style: 1},
+		{Type: "top", Color: "CCCCCC", Style: 1},
+		{Type: "bottom", Color: "CCCCCC", Style: 1},
+	}
+
+	totalFill := excelize.Fill{Type: "pattern", Color: []string{"D9E1F2"}, Pattern: 1}
+	totalBorder := []excelize.Border{
+		{Type: "left", Color: "000000", Style: 2},
+		{Type: "right", Color: "000000", Style: 2},
+		{Type: "top", Color: "000000", Style: 2},
+		{Type: "bottom", Color: "000000", Style: 2},
+	}
+
+	definitions := []*excelize.Style{
+		// Title
+		{
+			Font:      &excelize.Font{Bold: true, Size: 14, Color: "1F3864"},
+			Alignment: &excelize.Alignment{Horizontal: "left"},
+		},
+		// Label
+		{
+			Font:      &excelize.Font{Bold: true},
+			Fill:      excelize.Fill{Type: "pattern", Color: []string{"EBF0FA"}, Pattern: 1},
+			Alignment: &excelize.Alignment{Horizontal: "left"},
+		},
+		// Header: bold + center
+		{
+			Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+			Fill:      excelize.Fill{Type: "pattern", Color: []string{"4472C4"}, Pattern: 1},
+			Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+			Border: []excelize.Border{
+				{Type: "left", Color: "000000", Style: 1},
+				{Type: "right", Color: "000000", Style: 1},
+				{Type: "top", Color: "000000", Style: 1},
+				{Type: "bottom", Color: "000000", Style: 1},
+			},
+		},
+		// Text
+		{Border: cellBorder},
+		// Integer
+		{NumFmt: 1, Border: cellBorder}, // 0
+		// Currency (IDR, no decimal)
+		{NumFmt: 3, Border: cellBorder}, // #,##0
+		// Percent
+		{NumFmt: 2, Border: cellBorder}, // 0.00
+		// Emphasis
+		{Font: &excelize.Font{Bold: true}, NumFmt: 3, Border: cellBorder}, // #,##0
+		// Total
+		{Font: &excelize.Font{Bold: true}, NumFmt: 3, Fill: totalFill, Border: totalBorder},
+		// Total percent
+		{Font: &excelize.Font{Bold: true}, NumFmt: 2, Fill: totalFill, Border: totalBorder},
+	}
+
+	ids := make([]int, len(definitions))
+	for i, definition := range definitions {
+		id, err := f.NewStyle(definition)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	return &XlsxStyles{
+		Title:    ids[0],
+		Label:    ids[1],
+		Header:   ids[2],
+		Text:     ids[3],
+		Integer:  ids[4],
+		Currency: ids[5],
+		Percent:  ids[6],
+
+		Emphasis: ids[7],
+
+		Total:        ids[8],
+		TotalPercent: ids[9],
+	}, nil
+}
+
+// Style of the data cell of the column
+func (styles *XlsxStyles) Column(columnType ColumnType) int {
+	switch columnType {
+	case ColumnInteger:
+		return styles.Integer
+	case ColumnCurrency:
+		return styles.Currency
+	case ColumnPercent:
+		return styles.Percent
+	}
+	return styles.Text
+}
+
+type xlsxWriter struct {
+	w       io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	styles  *XlsxStyles
+	columns []Column
+	row     int
+}
+
+func newXLSXWriter(w io.Writer, title Title, columns []Column) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sheet := "Report"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	styles, err := NewXlsxStyles(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// Stream writer keep the rows in a temporary file, not in memory
+	stream, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// Width must be set before the first row
+	for i, column := range columns {
+		if err := stream.SetColWidth(i+1, i+1, column.width()); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	writer := &xlsxWriter{w: w, file: f, stream: stream, styles: styles, columns: columns}
+
+	for i, line := range title.lines() {
+		style := styles.Label
+		if i == 0 {
+			style = styles.Title
+		}
+		err := writer.setRow([]any{
+			excelize.Cell{StyleID: style, Value: line[0]},
+			excelize.Cell{Value: line[1]},
+		})
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	writer.row++ // Empty line between the title block and the table
+
+	headers := make([]any, len(columns))
+	for i, column := range columns {
+		headers[i] = excelize.Cell{StyleID: styles.Header, Value: column.Header}
+	}
+	if err := writer.setRow(headers); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (writer *xlsxWriter) setRow(cells []any) error {
+	writer.row++
+	cell, err := excelize.CoordinatesToCellName(1, writer.row)
+	if err != nil {
+		return err
+	}
+	return writer.stream.SetRow(cell, cells)
+}
+
+// WriteRow implements Writer.
+func (writer *xlsxWriter) WriteRow(values ...any) error {
+	if err := checkRowLength(values, writer.columns); err != nil {
+		return err
+	}
+
+	cells := make([]any, len(values))
+	for i, value := range values {
+		columnType := writer.columns[i].Type
+		switch v := value.(type) {
+		case *int:
+			value = formatValue(v, columnType, false)
+			if v != nil {
+				value = *v
+			}
+		case time.Time, *time.Time, nil, bool:
+			// Keep the same text as CSV
+			value = formatValue(value, columnType, false)
+		}
+		cells[i] = excelize.Cell{StyleID: writer.styles.Column(columnType), Value: value}
+	}
+
+	return writer.setRow(cells)
+}
+
+// Close implements Writer.
+func (writer *xlsxWriter) Close() error {
+	defer writer.file.Close()
+
+	if err := writer.stream.Flush(); err != nil {
+		return err
+	}
+	return writer.file.Write(writer.w)
+}
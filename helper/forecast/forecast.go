@@ -0,0 +1,134 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Smoothing factor of the level, higher follow the recent days faster
+const DefaultAlpha = 0.3
+
+// Below 2 weeks of history every weekday weight the same
+const minSeasonalDays = 14
+
+type Result struct {
+	Daily          []float64 `json:"daily"`            // Daily[0] is the day after the history
+	Level          float64   `json:"level"`            // Deseasonalized daily demand
+	SeasonalIndex  []float64 `json:"seasonal_index"`   // By time.Weekday, 1 is an average day
+	ResidualStdDev float64   `json:"residual_std_dev"` // Of the 1 day ahead error, for the safety stock
+}
+
+/*
+Forecast the next horizon days of a daily quantity series (oldest first, firstDay is the weekday of history[0])
+
+	Simple exponential smoothing on the deseasonalized series,
+	the season is the day of the week: mean of the weekday / mean of every day.
+	A weekday that never sold is forecasted 0
+*/
+func Forecast(history []float64, firstDay time.Weekday, alpha float64, horizon int) *Result {
+	result := &Result{
+		Daily:         make([]float64, horizon),
+		SeasonalIndex: seasonalIndex(history, firstDay),
+	}
+	if len(history) == 0 {
+		return result
+	}
+
+	weekday := func(day int) time.Weekday {
+		return time.Weekday((int(firstDay) + day) % 7)
+	}
+
+	// Start from the mean of the first week
+	var sum float64
+	var count int
+	for day := 0; day < len(history) && day < 7; day++ {
+		if index := result.SeasonalIndex[weekday(day)]; index > 0 {
+			sum += history[day] / index
+			count++
+		}
+	}
+	if count == 0 {
+		return result
+	}
+	level := sum / float64(count)
+
+	var squaredErrors float64
+	var errorCount int
+	for day, quantity := range history {
+		index := result.SeasonalIndex[weekday(day)]
+		// The first week built the starting level, its error would be too optimistic
+		if day >= 7 || len(history) <= 7 {
+			residual := quantity - level*index
+			squaredErrors += residual * residual
+			errorCount++
+		}
+		if index > 0 {
+			level = alpha*(quantity/index) + (1-alpha)*level
+		}
+	}
+
+	result.Level = level
+	if errorCount > 0 {
+		result.ResidualStdDev = math.Sqrt(squaredErrors / float64(errorCount))
+	}
+	for day := range result.Daily {
+		result.Daily[day] = level * result.SeasonalIndex[weekday(len(history)+day)]
+	}
+
+	return result
+}
+
+func seasonalIndex(history []float64, firstDay time.Weekday) []float64 {
+	index := []float64{1, 1, 1, 1, 1, 1, 1}
+	if len(history) < minSeasonalDays {
+		return index
+	}
+
+	var sums, counts [7]float64
+	var total float64
+	for day, quantity := range history {
+		weekday := (int(firstDay) + day) % 7
+		sums[weekday] += quantity
+		counts[weekday]++
+		total += quantity
+	}
+
+	mean := total / float64(len(history))
+	if mean == 0 {
+		return index
+	}
+	for weekday := range index {
+		index[weekday] = sums[weekday] / counts[weekday] / mean
+	}
+	return index
+}
+
+// Sum of the first days of the forecast, days above the horizon are ignored
+func (result *Result) Sum(days int) float64 {
+	var sum float64
+	for day := 0; day < days && day < len(result.Daily); day++ {
+		sum += result.Daily[day]
+	}
+	return sum
+}
+
+// SafetyStock cover the forecast error during the lead time at the given z-score
+func (result *Result) SafetyStock(z float64, leadTimeDays int) float64 {
+	return z * result.ResidualStdDev * math.Sqrt(float64(leadTimeDays))
+}
+
+// ZScore of the allowed service level (% of the lead time without stock out)
+func ZScore(serviceLevel int) (float64, error) {
+	switch serviceLevel {
+	case 90:
+		return 1.28, nil
+	case 95:
+		return 1.65, nil
+	case 98:
+		return 2.05, nil
+	case 99:
+		return 2.33, nil
+	}
+	return 0, fmt.Errorf("Invalid service level: %d. Allowed: 90, 95, 98, 99", serviceLevel)
+}
@@ -0,0 +1,69 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecast(t *testing.T) {
+	t.Run("Flat", func(t *testing.T) {
+		history := []float64{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4}
+		result := Forecast(history, time.Monday, DefaultAlpha, 7)
+
+		require.Len(t, result.Daily, 7)
+		for _, quantity := range result.Daily {
+			assert.InDelta(t, 4.0, quantity, 0.0001)
+		}
+		assert.InDelta(t, 28.0, result.Sum(7), 0.0001)
+		assert.InDelta(t, 0.0, result.ResidualStdDev, 0.0001)
+		assert.Zero(t, result.SafetyStock(1.65, 7))
+	})
+
+	t.Run("WeekdaySeasonality", func(t *testing.T) {
+		// 3 weeks starting Monday, only sold on Saturday and Sunday
+		history := make([]float64, 21)
+		for day := range history {
+			if weekday := time.Weekday((int(time.Monday) + day) % 7); weekday == time.Saturday || weekday == time.Sunday {
+				history[day] = 7
+			}
+		}
+		result := Forecast(history, time.Monday, DefaultAlpha, 7)
+
+		// Next day is a Monday
+		assert.InDelta(t, 0.0, result.Daily[0], 0.0001)
+		assert.InDelta(t, 7.0, result.Daily[5], 0.0001)
+		assert.InDelta(t, 7.0, result.Daily[6], 0.0001)
+		assert.InDelta(t, 3.5, result.SeasonalIndex[time.Saturday], 0.0001)
+		assert.Zero(t, result.SeasonalIndex[time.Monday])
+	})
+
+	t.Run("FollowTrend", func(t *testing.T) {
+		history := []float64{1, 1, 1, 1, 1, 1, 1, 10, 10, 10, 10, 10, 10, 10}
+		result := Forecast(history, time.Sunday, DefaultAlpha, 1)
+
+		assert.Greater(t, result.Daily[0], 9.0)
+		assert.Less(t, result.Daily[0], 10.0)
+		assert.Greater(t, result.ResidualStdDev, 0.0)
+		assert.Greater(t, result.SafetyStock(1.65, 4), result.SafetyStock(1.65, 1))
+	})
+
+	t.Run("NoHistory", func(t *testing.T) {
+		result := Forecast(nil, time.Monday, DefaultAlpha, 3)
+		assert.Equal(t, []float64{0, 0, 0}, result.Daily)
+
+		result = Forecast([]float64{0, 0, 0}, time.Monday, DefaultAlpha, 3)
+		assert.Equal(t, []float64{0, 0, 0}, result.Daily)
+	})
+
+	t.Run("ZScore", func(t *testing.T) {
+		z, err := ZScore(95)
+		require.NoError(t, err)
+		assert.Equal(t, 1.65, z)
+
+		_, err = ZScore(50)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,29 @@
+package job
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+Every run fn in the background every interval, until the process exit.
+
+	There is no distributed lock, when the API is scaled to many instances
+	fn have to be safe to run concurrently (idempotent at the DB level)
+*/
+func Every(interval time.Duration, name string, fn func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			start := time.Now()
+			if err := fn(); err != nil {
+				log.Errorf("[JOB] %s failed after %s, reason: %s", name, time.Since(start), err.Error())
+				continue
+			}
+			log.Debugf("[JOB] %s done in %s", name, time.Since(start))
+		}
+	}()
+}
@@ -0,0 +1,23 @@
+package job
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	var called atomic.Int32
+	Every(time.Millisecond*5, "test", func() error {
+		// Failing run should not stop the next one
+		if called.Add(1) == 1 {
+			return errors.New("first run failed")
+		}
+		return nil
+	})
+
+	assert.Eventually(t, func() bool { return called.Load() >= 2 }, time.Second, time.Millisecond*5)
+}
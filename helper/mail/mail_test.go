@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+startSMTPStandIn accept 1 session of plain SMTP (no TLS, no AUTH)
+and send the received DATA into the channel
+*/
+func startSMTPStandIn(t *testing.T) (host, port string, received chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received = make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ready")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250 localhost")
+			case "DATA":
+				text.PrintfLine("354 end with <CRLF>.<CRLF>")
+				data, _ := text.ReadDotBytes()
+				received <- string(data)
+				text.PrintfLine("250 queued")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				return
+			default:
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	host, port, _ = net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func TestMail(t *testing.T) {
+	message := &Message{
+		To:      []string{"owner@example.com"},
+		Subject: "Daily sales – 2026-01-31",
+		Body:    "Report attached",
+		Attachments: []*Attachment{
+			{FileName: "sales_report.csv", ContentType: "text/csv", Data: []byte("Report,Sales\n")},
+		},
+	}
+
+	t.Run("SMTP", func(t *testing.T) {
+		host, port, received := startSMTPStandIn(t)
+		mailer := NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "reports@example.com"})
+		require.NoError(t, mailer.Send(message))
+
+		select {
+		case data := <-received:
+			parsed, err := netmail.ReadMessage(bufio.NewReader(strings.NewReader(data)))
+			require.NoError(t, err)
+			assert.Equal(t, "owner@example.com", parsed.Header.Get("To"))
+			assert.Contains(t, parsed.Header.Get("Content-Type"), "multipart/mixed")
+
+			decoder := new(mime.WordDecoder)
+			subject, err := decoder.DecodeHeader(parsed.Header.Get("Subject"))
+			require.NoError(t, err)
+			assert.Equal(t, message.Subject, subject)
+
+			body, err := io.ReadAll(parsed.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), `filename=sales_report.csv`)
+		case <-time.After(time.Second * 5):
+			t.Fatal("SMTP stand-in did not receive the message")
+		}
+	})
+
+	t.Run("NoRecipient", func(t *testing.T) {
+		_, err := build("reports@example.com", &Message{Subject: "x"}, time.Now())
+		assert.Error(t, err)
+	})
+
+	t.Run("LogMailer", func(t *testing.T) {
+		assert.NoError(t, NewLogMailer().Send(message))
+		assert.False(t, IsConfigured(NewLogMailer()))
+		assert.True(t, IsConfigured(NewSMTPMailer(SMTPConfig{Host: "localhost", Port: "1025"})))
+	})
+
+	t.Run("MemoryMailer", func(t *testing.T) {
+		mailer := NewMemoryMailer()
+		assert.Nil(t, mailer.Last())
+
+		require.NoError(t, mailer.Send(message))
+		mailer.Err = io.ErrClosedPipe
+		assert.ErrorIs(t, mailer.Send(&Message{To: []string{"b@example.com"}}), io.ErrClosedPipe)
+
+		assert.Len(t, mailer.Messages(), 2)
+		assert.Equal(t, []string{"b@example.com"}, mailer.Last().To)
+	})
+}
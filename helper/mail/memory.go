@@ -0,0 +1,45 @@
+package mail
+
+import "sync"
+
+/*
+MemoryMailer keep every sent message in memory, for tests.
+Safe for concurrent use
+*/
+type MemoryMailer struct {
+	mutex    sync.Mutex
+	messages []*Message
+	Err      error // Returned by Send when set, the message is still kept
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send implements Mailer.
+func (mailer *MemoryMailer) Send(message *Message) error {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+
+	mailer.messages = append(mailer.messages, message)
+	return mailer.Err
+}
+
+// Messages sent so far, oldest first
+func (mailer *MemoryMailer) Messages() []*Message {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+
+	return append([]*Message(nil), mailer.messages...)
+}
+
+// Last sent message, nil when nothing was sent
+func (mailer *MemoryMailer) Last() *Message {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+
+	if len(mailer.messages) == 0 {
+		return nil
+	}
+	return mailer.messages[len(mailer.messages)-1]
+}
@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"errors"
+	"net"
+	"net/smtp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // Empty means no AUTH (e.g. local stand-in)
+	Password string
+	From     string
+}
+
+/*
+SMTPMailer use net/smtp, STARTTLS is used when the server offer it.
+PLAIN auth is refused by net/smtp without TLS, except to localhost
+*/
+type SMTPMailer struct {
+	Config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) Mailer {
+	return &SMTPMailer{Config: config}
+}
+
+// Send implements Mailer.
+func (mailer *SMTPMailer) Send(message *Message) error {
+	body, err := build(mailer.Config.From, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if mailer.Config.Username != "" {
+		auth = smtp.PlainAuth("", mailer.Config.Username, mailer.Config.Password, mailer.Config.Host)
+	}
+
+	address := net.JoinHostPort(mailer.Config.Host, mailer.Config.Port)
+	return smtp.SendMail(address, auth, mailer.Config.From, message.To, body)
+}
+
+// ErrNotConfigured is returned by a sender that must not pretend the email was sent
+var ErrNotConfigured = errors.New("SMTP_HOST is not set, email is not sent")
+
+// LogMailer only log the email, for local development
+type LogMailer struct{}
+
+func NewLogMailer() Mailer {
+	return &LogMailer{}
+}
+
+// Send implements Mailer.
+func (mailer *LogMailer) Send(message *Message) error {
+	log.Infof("[MAIL] to %v, subject %q, %d attachment(s) (SMTP_HOST is not set, not sent)",
+		message.To, message.Subject, len(message.Attachments))
+	return nil
+}
+
+// IsConfigured is false for LogMailer, nothing is sent by it
+func IsConfigured(mailer Mailer) bool {
+	_, logOnly := mailer.(*LogMailer)
+	return !logOnly
+}
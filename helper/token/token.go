@@ -0,0 +1,29 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+/*
+New return a random URL safe token of size bytes (base64 without padding).
+32 bytes is enough for any secret handed to a client
+*/
+func New(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+/*
+Hash is the SHA-256 hex of the token, the only form stored at the DB.
+The token is random, so no salt or slow hash is needed
+*/
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
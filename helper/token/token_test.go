@@ -0,0 +1,23 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken(t *testing.T) {
+	first, err := New(32)
+	require.NoError(t, err)
+	second, err := New(32)
+	require.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+	assert.NotContains(t, first, "=")
+
+	assert.Equal(t, Hash(first), Hash(first))
+	assert.NotEqual(t, Hash(first), Hash(second))
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", Hash("test"))
+}
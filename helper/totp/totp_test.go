@@ -0,0 +1,79 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTotp(t *testing.T) {
+	// RFC 6238 appendix B, SHA1 secret "12345678901234567890", last 6 digits of the 8 digits vectors
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+
+	t.Run("RFCVectors", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+		for unix, expected := range vectors {
+			code, err := Code(secret, Step(time.Unix(unix, 0)))
+			require.NoError(t, err)
+			assert.Equal(t, expected, code, "at %d", unix)
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		now := time.Unix(1234567890, 0)
+		code, err := Code(secret, Step(now))
+		require.NoError(t, err)
+
+		step, ok := Validate(secret, code, now, 0)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now), step)
+
+		// Phone clock 1 period late or early
+		_, ok = Validate(secret, code, now.Add(time.Second*Period), 0)
+		assert.True(t, ok)
+		_, ok = Validate(secret, code, now.Add(-time.Second*Period), 0)
+		assert.True(t, ok)
+		_, ok = Validate(secret, code, now.Add(time.Second*Period*2), 0)
+		assert.False(t, ok)
+
+		// Already used
+		_, ok = Validate(secret, code, now, step)
+		assert.False(t, ok)
+
+		_, ok = Validate(secret, "12345", now, 0)
+		assert.False(t, ok)
+		_, ok = Validate("not base32 !", code, now, 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("GenerateSecret", func(t *testing.T) {
+		first, err := GenerateSecret()
+		require.NoError(t, err)
+		second, err := GenerateSecret()
+		require.NoError(t, err)
+		assert.Len(t, first, 32)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("ProvisioningURI", func(t *testing.T) {
+		uri := ProvisioningURI("Cashier API", "owner@example.com", "JBSWY3DPEHPK3PXP")
+		assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Cashier%20API:owner@example.com?"))
+
+		parsed, err := url.Parse(uri)
+		require.NoError(t, err)
+		assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+		assert.Equal(t, "Cashier API", parsed.Query().Get("issuer"))
+		assert.Equal(t, "6", parsed.Query().Get("digits"))
+	})
+}
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"os"
+	"strings"
+)
+
+/*
+TrustedProxies read TRUSTED_PROXIES, a comma separated list of IP or CIDR
+of the reverse proxy in front of the API (e.g. "10.0.0.0/8,127.0.0.1").
+
+	Only a request coming from them could set the client IP with X-Forwarded-For,
+	empty means ctx.IP() is always the remote address.
+*/
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
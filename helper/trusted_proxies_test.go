@@ -0,0 +1,15 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", " 10.0.0.0/8, ,127.0.0.1 ")
+	assert.Equal(t, []string{"10.0.0.0/8", "127.0.0.1"}, TrustedProxies())
+
+	t.Setenv("TRUSTED_PROXIES", "")
+	assert.Empty(t, TrustedProxies())
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// ErrPrivateAddress is returned for a webhook pointing to the network of the API itself
+var ErrPrivateAddress = errors.New("webhook address must be public")
+
+// Carrier grade NAT, also used by some cloud metadata (e.g. 100.100.100.200)
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+/*
+IsPublicIP is false for loopback, private, link local (cloud metadata 169.254.169.254),
+shared, multicast and unspecified address
+*/
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+/*
+CheckUrl refuse a webhook url that is not http(s), or whose host is not public.
+A host name is resolved again when the webhook is sent, see NewHTTPSender
+*/
+func CheckUrl(rawUrl string) error {
+	webhookUrl, err := url.Parse(rawUrl)
+	if err != nil || (webhookUrl.Scheme != "https" && webhookUrl.Scheme != "http") || webhookUrl.Hostname() == "" {
+		return fmt.Errorf("Invalid webhook_url: %s", rawUrl)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(webhookUrl.Hostname(), "."))
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("%w: %s", ErrPrivateAddress, rawUrl)
+		}
+		return nil
+	}
+
+	// Names that never leave the host or the cloud network (e.g. metadata.google.internal)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") ||
+		strings.HasSuffix(host, ".internal") || strings.HasSuffix(host, ".local") {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, rawUrl)
+	}
+
+	return nil
+}
+
+// publicOnly is the dialer control, checked with the resolved address right before connecting
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
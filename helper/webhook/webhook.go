@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+)
+
+/*
+Delivery is posted as the raw file body, metadata are in the headers:
+
+	Content-Type, Content-Disposition (file name), X-Webhook-Event and
+	X-Webhook-Signature (hex HMAC-SHA256 of the body, only when a secret is set)
+*/
+type Delivery struct {
+	Url         string
+	Secret      string
+	Event       string
+	FileName    string
+	ContentType string
+	Body        []byte
+}
+
+type Sender interface {
+	Send(delivery *Delivery) error
+}
+
+type HTTPSender struct {
+	Client *http.Client
+}
+
+/*
+NewHTTPSender only connect to public address, every connection (redirect included)
+is checked after the host is resolved, so a name could not point back to the API network
+*/
+func NewHTTPSender(timeout time.Duration) Sender {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicOnly}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+	}
+	return &HTTPSender{Client: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// Sign return the value of X-Webhook-Signature, so the receiver could check it the same way
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send implements Sender. Any status other than 2xx is an error
+func (sender *HTTPSender) Send(delivery *Delivery) error {
+	request, err := http.NewRequest(http.MethodPost, delivery.Url, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", delivery.ContentType)
+	request.Header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": delivery.FileName}))
+	request.Header.Set("X-Webhook-Event", delivery.Event)
+	if delivery.Secret != "" {
+		request.Header.Set("X-Webhook-Signature", Sign(delivery.Secret, delivery.Body))
+	}
+
+	response, err := sender.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded %s", delivery.Url, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSender(t *testing.T) {
+	delivery := &Delivery{
+		Secret:      "s3cret",
+		Event:       "report_schedule.delivered",
+		FileName:    "sales_report.csv",
+		ContentType: "text/csv",
+		Body:        []byte("Report,Sales\n"),
+	}
+
+	t.Run("Delivered", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, delivery.Body, body)
+			assert.Equal(t, "text/csv", r.Header.Get("Content-Type"))
+			assert.Equal(t, "attachment; filename=sales_report.csv", r.Header.Get("Content-Disposition"))
+			assert.Equal(t, Sign("s3cret", body), r.Header.Get("X-Webhook-Signature"))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		delivery.Url = server.URL
+		// Test server listen on loopback, refused by NewHTTPSender
+		require.NoError(t, (&HTTPSender{Client: server.Client()}).Send(delivery))
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		delivery.Url = server.URL
+		assert.Error(t, (&HTTPSender{Client: server.Client()}).Send(delivery))
+	})
+
+	t.Run("PrivateAddressRefused", func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		delivery.Url = server.URL
+		err := NewHTTPSender(time.Second * 5).Send(delivery)
+		assert.ErrorIs(t, err, ErrPrivateAddress)
+		assert.False(t, called)
+	})
+}
+
+func TestCheckUrl(t *testing.T) {
+	for _, rawUrl := range []string{"https://example.com/hook", "http://203.0.113.7:8080/hook"} {
+		assert.NoError(t, CheckUrl(rawUrl), rawUrl)
+	}
+
+	for _, rawUrl := range []string{
+		"ftp://example.com/hook",
+		"https:///hook",
+	} {
+		assert.Error(t, CheckUrl(rawUrl), rawUrl)
+	}
+
+	for _, rawUrl := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8000/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://metadata.google.internal/computeMetadata/v1",
+		"http://100.100.100.200/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fd00:ec2::254]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		assert.ErrorIs(t, CheckUrl(rawUrl), ErrPrivateAddress, rawUrl)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"cashier-api/exception"
 	common "cashier-api/helper"
 	"cashier-api/helper/client"
+	"cashier-api/helper/job"
+	"cashier-api/helper/mail"
+	"cashier-api/helper/webhook"
 	"cashier-api/middleware"
 	"cashier-api/repository"
 	"cashier-api/service"
@@ -37,9 +40,12 @@ func main() {
 	app := fiber.New(fiber.Config{
 		IdleTimeout:             time.Second * 5,
 		ReadTimeout:             time.Second * 5,
-		WriteTimeout:            time.Second * 5,
+		WriteTimeout:            time.Second * 5, // An export stream push back its own deadline, see controller.exportWriteTimeout
 		Prefork:                 false,
 		EnableTrustedProxyCheck: true,
+		TrustedProxies:          common.TrustedProxies(), // ctx.IP() is the client behind them (throttle, audit trail)
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableIPValidation:      true,
 		ErrorHandler:            exception.ErrorHandler,
 	})
 
@@ -50,7 +56,7 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000, https://enterprisepos.vercel.app",
 		AllowCredentials: true,
-		AllowHeaders:     "Origin, Content-Type, Cookie, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Cookie, Authorization, X-Terminal-Token",
 		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS",
 	}))
 	app.Use(middleware.RequestDebug())
@@ -71,15 +77,58 @@ func main() {
 
 	// public
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	sessionRepository := repository.NewSessionRepositoryImpl(gormClient)
+	userTokenRepository := repository.NewUserTokenRepositoryImpl(gormClient)
+	twoFactorRepository := repository.NewTwoFactorRepositoryImpl(gormClient)
+	signInAttemptRepository := repository.NewSignInAttemptRepositoryImpl(gormClient)
+	userService := service.NewUserServiceImpl(
+		userRepository, sessionRepository, userTokenRepository, twoFactorRepository, signInAttemptRepository, mail.NewMailerFromEnv(),
+	)
 	userController := controller.NewUserControllerImpl(userService)
 
 	apiV1.Post("/users/sign_up", userController.SignUpWithEmailAndPassword)
 	apiV1.Post("/users/sign_in", userController.SignInWithEmailAndPassword)
+	apiV1.Post("/users/sign_in/two_factor", userController.SignInWithTwoFactor)
+	apiV1.Post("/users/refresh", userController.Refresh)
 	apiV1.Delete("/users/sign_out", userController.SignOut)
+	apiV1.Post("/users/password/forgot", userController.ForgotPassword)
+	apiV1.Post("/users/password/reset", userController.ResetPassword)
+	apiV1.Post("/users/email/verify", userController.VerifyEmail)
+
+	terminalRepository := repository.NewTerminalRepositoryImpl(gormClient)
+	terminalService := service.NewTerminalServiceImpl(terminalRepository, userRepository, sessionRepository)
+	terminalController := controller.NewTerminalControllerImpl(terminalService)
+
+	// With the X-Terminal-Token header
+	apiV1.Get("/terminals/members", terminalController.GetMembers)
+	apiV1.Post("/terminals/sign_in", terminalController.SignInWithPin)
 
 	// protected only login user
-	apiV1.Use(middleware.ProtectedRoute)
+	apiV1.Use(middleware.ProtectedRoute(gormClient))
+
+	auditRepository := repository.NewAuditRepositoryImpl(gormClient)
+	auditService := service.NewAuditServiceImpl(auditRepository)
+	auditController := controller.NewAuditControllerImpl(auditService)
+
+	// Every POST/PUT/DELETE from here is recorded
+	apiV1.Use(middleware.AuditTrail(auditService))
+
+	apiV1.Get("/users/sessions", userController.GetSessions)
+	apiV1.Delete("/users/sessions", userController.RevokeSession)
+	apiV1.Delete("/users/sessions/all", userController.RevokeAllSessions)
+	apiV1.Put("/users/password", userController.ChangePassword)
+	apiV1.Post("/users/email/send_verification", userController.SendEmailVerification)
+	// GET /users/sign_in_attempts?limit=10&page=1
+	apiV1.Get("/users/sign_in_attempts", userController.GetSignInAttempts)
+
+	twoFactorService := service.NewTwoFactorServiceImpl(userRepository, twoFactorRepository, sessionRepository)
+	twoFactorController := controller.NewTwoFactorControllerImpl(twoFactorService)
+
+	apiV1.Get("/users/two_factor", twoFactorController.GetStatus)
+	apiV1.Post("/users/two_factor/enroll", twoFactorController.Enroll)
+	apiV1.Post("/users/two_factor/confirm", twoFactorController.Confirm)
+	apiV1.Post("/users/two_factor/recovery_codes", twoFactorController.RegenerateRecoveryCodes)
+	apiV1.Delete("/users/two_factor", twoFactorController.Disable)
 
 	tenantRepository := repository.NewTenantRepositoryImpl(gormClient)
 	tenantService := service.NewTenantServiceImpl(tenantRepository)
@@ -90,9 +139,49 @@ func main() {
 	apiV1.Post("/tenants/new", tenantController.NewTenant)
 	apiV1.Post("/tenants/add_user", tenantController.AddUserToTenant)
 	apiV1.Delete("/tenants/remove_user", tenantController.RemoveUserFromTenant)
+	// Not restricted by tenant, the archived tenant refuse writes
+	apiV1.Post("/tenants/restore/:tenantId", tenantController.Restore)
+
+	tenantInvitationRepository := repository.NewTenantInvitationRepositoryImpl(gormClient)
+	tenantInvitationService := service.NewTenantInvitationServiceImpl(tenantInvitationRepository, mail.NewMailerFromEnv())
+	tenantInvitationController := controller.NewTenantInvitationControllerImpl(tenantInvitationService)
+
+	// Not restricted by tenant, the user is not a member yet
+	apiV1.Post("/tenant_invitations/accept", tenantInvitationController.Accept)
 
 	// restrict by tenantId
 	tenantRestriction := middleware.RestrictByTenant(gormClient)
+	apiV1.Put("/tenants/timezone/:tenantId", tenantRestriction, tenantController.SetTimezone)
+	apiV1.Put("/tenants/two_factor_policy/:tenantId", tenantRestriction, tenantController.SetTwoFactorPolicy)
+	apiV1.Put("/tenants/member_role/:tenantId", tenantRestriction, tenantController.SetMemberRole)
+	apiV1.Get("/tenants/ownership_transfer/:tenantId", tenantRestriction, tenantController.GetOwnershipTransfer)
+	apiV1.Post("/tenants/ownership_transfer/accept/:tenantId", tenantRestriction, tenantController.AcceptOwnershipTransfer)
+	apiV1.Post("/tenants/ownership_transfer/:tenantId", tenantRestriction, tenantController.RequestOwnershipTransfer)
+	apiV1.Delete("/tenants/ownership_transfer/:tenantId", tenantRestriction, tenantController.CancelOwnershipTransfer)
+	apiV1.Put("/tenants/archive/:tenantId", tenantRestriction, tenantController.Archive)
+	apiV1.Put("/users/unlock_sign_in/:tenantId", tenantRestriction, userController.UnlockSignIn)
+
+	// GET /audit_events/:tenantId?limit=10&page=1&user_id=99&entity_type=store&entity_id=99&method=PUT&start_date=1700000000&end_date=1700086400
+	apiV1.Get("/audit_events/:tenantId", tenantRestriction, auditController.Search)
+
+	// GET /tenant_invitations/:tenantId?limit=10&page=1
+	apiV1.Get("/tenant_invitations/:tenantId", tenantRestriction, tenantInvitationController.GetPending)
+	apiV1.Post("/tenant_invitations/:tenantId", tenantRestriction, tenantInvitationController.Invite)
+	apiV1.Put("/tenant_invitations/resend/:tenantId", tenantRestriction, tenantInvitationController.Resend)
+	apiV1.Delete("/tenant_invitations/:tenantId", tenantRestriction, tenantInvitationController.Revoke)
+
+	apiKeyRepository := repository.NewApiKeyRepositoryImpl(gormClient)
+	apiKeyService := service.NewApiKeyServiceImpl(apiKeyRepository)
+	apiKeyController := controller.NewApiKeyControllerImpl(apiKeyService)
+
+	apiV1.Get("/api_keys/:tenantId", tenantRestriction, apiKeyController.GetApiKeys)
+	apiV1.Post("/api_keys/:tenantId", tenantRestriction, apiKeyController.Create)
+	apiV1.Delete("/api_keys/:tenantId", tenantRestriction, apiKeyController.Revoke)
+
+	apiV1.Get("/terminals/:tenantId", tenantRestriction, terminalController.GetTerminals)
+	apiV1.Post("/terminals/:tenantId", tenantRestriction, terminalController.Register)
+	apiV1.Delete("/terminals/:tenantId", tenantRestriction, terminalController.Revoke)
+	apiV1.Put("/terminals/pin/:tenantId", tenantRestriction, terminalController.SetPin)
 
 	warehouseRepository := repository.NewWarehouseRepositoryImpl(gormClient)
 	warehouseService := service.NewWarehouseServiceImpl(warehouseRepository)
@@ -151,17 +240,193 @@ func main() {
 	apiV1.Get("/order_items/details/:tenantId", tenantRestriction, orderItemController.FindById)
 	apiV1.Post("/order_items/search/:tenantId", tenantRestriction, orderItemController.Get)
 	apiV1.Post("/order_items/transactions/:tenantId", tenantRestriction, orderItemController.Transactions)
+	// GET /order_items/open/:tenantId?store_id=99&limit=10&page=1
+	// GET /order_items/status_history/:tenantId?order_item_id=99
+	apiV1.Get("/order_items/open/:tenantId", tenantRestriction, orderItemController.GetOpenOrders)
+	apiV1.Get("/order_items/status_history/:tenantId", tenantRestriction, orderItemController.GetStatusHistory)
+	apiV1.Post("/order_items/place/:tenantId", tenantRestriction, orderItemController.PlaceOrderItem)
+	apiV1.Put("/order_items/status/:tenantId", tenantRestriction, orderItemController.UpdateOrderStatus)
 	apiV1.Post("/order_items/sales_report/:tenantId", tenantRestriction, orderItemController.GetSalesReport)
+	apiV1.Post("/order_items/sales_series/:tenantId", tenantRestriction, orderItemController.GetSalesSeries)
 	apiV1.Post("/order_items/export_profit/:tenantId", tenantRestriction, orderItemController.ExportProfitExcel)
+	apiV1.Post("/order_items/category_report/:tenantId", tenantRestriction, orderItemController.GetCategoryReport)
+	apiV1.Post("/order_items/store_comparison/:tenantId", tenantRestriction, orderItemController.GetStoreComparison)
 	apiV1.Delete("/order_items/:tenantId", tenantRestriction, orderItemController.DeleteInvoice)
 
+	customerRepository := repository.NewCustomerRepositoryImpl(gormClient)
+	customerService := service.NewCustomerServiceImpl(customerRepository, orderItemRepository)
+	customerController := controller.NewCustomerControllerImpl(customerService)
+
+	// GET /customers/:tenantId?limit=10&page=1&phone_query=0812
+	// GET /customers/details/:tenantId?customer_id=99&limit=10&page=1
+	apiV1.Get("/customers/details/:tenantId", tenantRestriction, customerController.GetDetail)
+	apiV1.Get("/customers/:tenantId", tenantRestriction, customerController.Get)
+	apiV1.Post("/customers/:tenantId", tenantRestriction, customerController.Create)
+	apiV1.Put("/customers/:tenantId", tenantRestriction, customerController.Edit)
+
+	loyaltyRepository := repository.NewLoyaltyRepositoryImpl(gormClient)
+	loyaltyService := service.NewLoyaltyServiceImpl(loyaltyRepository, customerRepository)
+	loyaltyController := controller.NewLoyaltyControllerImpl(loyaltyService)
+
+	// GET /loyalty/statements/:tenantId?customer_id=99&limit=10&page=1
+	apiV1.Get("/loyalty/settings/:tenantId", tenantRestriction, loyaltyController.GetSetting)
+	apiV1.Put("/loyalty/settings/:tenantId", tenantRestriction, loyaltyController.SaveSetting)
+	apiV1.Get("/loyalty/statements/:tenantId", tenantRestriction, loyaltyController.GetStatement)
+
+	giftCardRepository := repository.NewGiftCardRepositoryImpl(gormClient)
+	giftCardService := service.NewGiftCardServiceImpl(giftCardRepository)
+	giftCardController := controller.NewGiftCardControllerImpl(giftCardService)
+
+	// GET /gift_cards/balance/:tenantId?code=ABCD2345EFGH6789
+	// GET /gift_cards/movements/:tenantId?code=ABCD2345EFGH6789&limit=10&page=1
+	apiV1.Get("/gift_cards/balance/:tenantId", tenantRestriction, giftCardController.GetBalance)
+	apiV1.Get("/gift_cards/movements/:tenantId", tenantRestriction, giftCardController.GetStatement)
+	apiV1.Get("/gift_cards/liability/:tenantId", tenantRestriction, giftCardController.GetLiability)
+	apiV1.Post("/gift_cards/store_credit/:tenantId", tenantRestriction, giftCardController.IssueStoreCredit)
+	apiV1.Post("/gift_cards/:tenantId", tenantRestriction, giftCardController.Issue)
+	apiV1.Put("/gift_cards/activate/:tenantId", tenantRestriction, giftCardController.Activate)
+
+	parkedOrderRepository := repository.NewParkedOrderRepositoryImpl(gormClient)
+	parkedOrderService := service.NewParkedOrderServiceImpl(parkedOrderRepository, orderItemService)
+	parkedOrderController := controller.NewParkedOrderControllerImpl(parkedOrderService)
+
+	// GET /parked_orders/:tenantId?store_id=99&limit=10&page=1
+	// GET /parked_orders/details/:tenantId?parked_order_id=99
+	apiV1.Get("/parked_orders/details/:tenantId", tenantRestriction, parkedOrderController.FindById)
+	apiV1.Get("/parked_orders/:tenantId", tenantRestriction, parkedOrderController.Get)
+	apiV1.Post("/parked_orders/convert/:tenantId", tenantRestriction, parkedOrderController.Convert)
+	apiV1.Post("/parked_orders/:tenantId", tenantRestriction, parkedOrderController.Park)
+	apiV1.Put("/parked_orders/:tenantId", tenantRestriction, parkedOrderController.Edit)
+	apiV1.Delete("/parked_orders/:tenantId", tenantRestriction, parkedOrderController.Cancel)
+
+	reportRepository := repository.NewReportRepositoryImpl(gormClient)
+	reportService := service.NewReportServiceImpl(reportRepository)
+	reportController := controller.NewReportControllerImpl(reportService)
+
+	// GET /reports/slow_movers/:tenantId?store_id=99&category_id=99&days=30&limit=10&page=1
+	// GET /reports/inventory_valuation/:tenantId?store_id=99
+	// GET /reports/reorder_suggestions/:tenantId?store_id=99&history_days=56&lead_time_days=7&service_level=95
+	apiV1.Post("/reports/top_sellers/:tenantId", tenantRestriction, reportController.GetTopSellers)
+	apiV1.Get("/reports/slow_movers/:tenantId", tenantRestriction, reportController.GetSlowMovers)
+	apiV1.Post("/reports/abc/:tenantId", tenantRestriction, reportController.GetABCAnalysis)
+	apiV1.Post("/reports/cashier_performance/:tenantId", tenantRestriction, reportController.GetCashierPerformance)
+	apiV1.Get("/reports/inventory_valuation/:tenantId", tenantRestriction, reportController.GetInventoryValuation)
+	apiV1.Get("/reports/reorder_suggestions/:tenantId", tenantRestriction, reportController.GetReorderSuggestions)
+
+	marginAlertRepository := repository.NewMarginAlertRepositoryImpl(gormClient)
+	marginAlertService := service.NewMarginAlertServiceImpl(marginAlertRepository, mail.NewMailerFromEnv())
+	marginAlertController := controller.NewMarginAlertControllerImpl(marginAlertService)
+
+	// GET /margin_alerts/below_cost/:tenantId?store_id=99&limit=10&page=1
+	// GET /margin_alerts/low_margin/:tenantId?store_id=99
+	apiV1.Get("/margin_alerts/settings/:tenantId", tenantRestriction, marginAlertController.GetSetting)
+	apiV1.Put("/margin_alerts/settings/:tenantId", tenantRestriction, marginAlertController.SaveSetting)
+	apiV1.Get("/margin_alerts/below_cost/:tenantId", tenantRestriction, marginAlertController.GetBelowCostStocks)
+	apiV1.Get("/margin_alerts/low_margin/:tenantId", tenantRestriction, marginAlertController.GetLowMarginItems)
+
+	closingReportRepository := repository.NewClosingReportRepositoryImpl(gormClient)
+	closingReportService := service.NewClosingReportServiceImpl(closingReportRepository)
+	closingReportController := controller.NewClosingReportControllerImpl(closingReportService)
+
+	// GET /closing_reports/:tenantId?store_id=99&type=Z&limit=10&page=1
+	// GET /closing_reports/details/:tenantId?closing_report_id=99
+	apiV1.Get("/closing_reports/details/:tenantId", tenantRestriction, closingReportController.FindById)
+	apiV1.Get("/closing_reports/:tenantId", tenantRestriction, closingReportController.Get)
+	apiV1.Post("/closing_reports/x/:tenantId", tenantRestriction, closingReportController.GenerateXReport)
+	apiV1.Post("/closing_reports/z/:tenantId", tenantRestriction, closingReportController.GenerateZReport)
+
+	exportService := service.NewExportServiceImpl(
+		orderItemService, reportService, orderItemRepository, storeStockRepository, warehouseRepository, categoryRepository, tenantRepository,
+	)
+	exportController := controller.NewExportControllerImpl(exportService)
+
+	// GET /exports/:tenantId?resource=order_items&format=csv&store_id=99&start_date=1700000000&end_date=1800000000
+	apiV1.Get("/exports/:tenantId", tenantRestriction, exportController.Export)
+
+	reportScheduleRepository := repository.NewReportScheduleRepositoryImpl(gormClient)
+	reportScheduleService := service.NewReportScheduleServiceImpl(
+		reportScheduleRepository, exportService, mail.NewMailerFromEnv(), webhook.NewHTTPSender(time.Second*30),
+	)
+	reportScheduleController := controller.NewReportScheduleControllerImpl(reportScheduleService)
+
+	// GET /report_schedules/:tenantId?limit=10&page=1
+	// GET /report_schedules/details/:tenantId?report_schedule_id=99
+	// GET /report_schedules/runs/:tenantId?report_schedule_id=99&limit=10&page=1
+	apiV1.Get("/report_schedules/runs/:tenantId", tenantRestriction, reportScheduleController.GetRuns)
+	apiV1.Get("/report_schedules/details/:tenantId", tenantRestriction, reportScheduleController.FindById)
+	apiV1.Get("/report_schedules/:tenantId", tenantRestriction, reportScheduleController.Get)
+	apiV1.Post("/report_schedules/:tenantId", tenantRestriction, reportScheduleController.Create)
+	apiV1.Put("/report_schedules/:tenantId", tenantRestriction, reportScheduleController.Edit)
+	apiV1.Delete("/report_schedules/:tenantId", tenantRestriction, reportScheduleController.Delete)
+
+	// PIN sign in on a shared terminal only reach these, for the terminal store (store_id is required)
+	// GET /terminal/store_stocks/load_cashier_data/:tenantId?store_id=99
+	// GET /terminal/order_items/open/:tenantId?store_id=99&limit=10&page=1
+	// GET /terminal/parked_orders/:tenantId?store_id=99&limit=10&page=1
+	terminalRoute := middleware.TerminalRoute(gormClient)
+	apiV1.Get("/terminal/store_stocks/load_cashier_data/:tenantId", terminalRoute, storeStockController.LoadCashierData)
+	apiV1.Post("/terminal/order_items/place/:tenantId", terminalRoute, orderItemController.PlaceOrderItem)
+	apiV1.Get("/terminal/order_items/open/:tenantId", terminalRoute, orderItemController.GetOpenOrders)
+	apiV1.Get("/terminal/parked_orders/:tenantId", terminalRoute, parkedOrderController.Get)
+	apiV1.Post("/terminal/parked_orders/:tenantId", terminalRoute, parkedOrderController.Park)
+
+	// API key (Authorization: Bearer epk_...) only reach these, each route require 1 scope of the key
+	// see middleware.IntegrationRouteScopes
+	// GET /integration/order_items/details/:tenantId?order_item_id=99
+	// GET /integration/closing_reports/:tenantId?limit=10&page=1
+	// GET /integration/store_stocks/:tenantId?limit=10&page=1
+	// GET /integration/customers/:tenantId?limit=10&page=1
+	integration := apiV1.Group("/integration")
+	apiKeyRoute := middleware.ApiKeyRoute()
+	integration.Get("/order_items/details/:tenantId", apiKeyRoute, orderItemController.FindById)
+	integration.Post("/order_items/search/:tenantId", apiKeyRoute, orderItemController.Get)
+	integration.Post("/order_items/transactions/:tenantId", apiKeyRoute, orderItemController.Transactions)
+	integration.Get("/closing_reports/details/:tenantId", apiKeyRoute, closingReportController.FindById)
+	integration.Get("/closing_reports/:tenantId", apiKeyRoute, closingReportController.Get)
+	integration.Post("/order_items/place/:tenantId", apiKeyRoute, orderItemController.PlaceOrderItem)
+	integration.Get("/store_stocks/:tenantId", apiKeyRoute, storeStockController.Get)
+	integration.Get("/store_stocks/v2/:tenantId", apiKeyRoute, storeStockController.GetV2)
+	integration.Get("/warehouses/:tenantId", apiKeyRoute, warehouseController.Get)
+	integration.Put("/store_stocks/edit/:tenantId", apiKeyRoute, storeStockController.Edit)
+	integration.Get("/categories/:tenantId", apiKeyRoute, categoryController.Get)
+	integration.Get("/customers/details/:tenantId", apiKeyRoute, customerController.GetDetail)
+	integration.Get("/customers/:tenantId", apiKeyRoute, customerController.Get)
+	integration.Post("/customers/:tenantId", apiKeyRoute, customerController.Create)
+	integration.Put("/customers/:tenantId", apiKeyRoute, customerController.Edit)
+
+	// 04 Background jobs
+	job.Every(time.Hour, "loyalty.ExpirePoints", func() error {
+		_, err := loyaltyService.ExpirePoints()
+		return err
+	})
+	job.Every(time.Minute*15, "parkedOrder.ExpireParkedOrders", func() error {
+		_, err := parkedOrderService.ExpireParkedOrders()
+		return err
+	})
+	job.Every(time.Minute, "reportSchedule.RunDue", func() error {
+		_, err := reportScheduleService.RunDue(time.Now())
+		return err
+	})
+	job.Every(time.Hour, "marginAlert.SendAlerts", func() error {
+		_, err := marginAlertService.SendAlerts(time.Now())
+		return err
+	})
+	job.Every(time.Hour*24, "user.DeleteExpiredRefreshTokens", func() error {
+		_, err := userService.DeleteExpiredRefreshTokens(time.Now())
+		return err
+	})
+	job.Every(time.Hour, "tenant.DeleteArchivedTenants", func() error {
+		_, err := tenantService.DeleteArchivedTenants(time.Now())
+		return err
+	})
+
 	// Handle route not found (404)
 	app.All("*", func(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusNotFound).
 			JSON(common.NewWebResponseError(fiber.StatusNotFound, common.StatusError, "Route for "+string(ctx.Request().RequestURI())+" not found"))
 	})
 
-	// 04 Application started listening here
+	// 05 Application started listening here
 	url := "localhost:8000"
 	if os.Getenv("MODE") == "prod" {
 		url = ":8000"
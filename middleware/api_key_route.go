@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	common "cashier-api/helper"
+	"cashier-api/model"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// Routes reachable with an API key, mounted again under this prefix with ApiKeyRoute
+const IntegrationPathPrefix = "/api/v1/integration/"
+
+/*
+Scope required by each integration route, by method and route path after /api/v1/integration.
+
+	A route creating a sale or moving stock always require a write scope. A route missing here is refused
+*/
+var IntegrationRouteScopes = map[string]model.ApiKeyScope{
+	"GET /order_items/details/:tenantId":       model.ApiKeyScopeReadSales,
+	"POST /order_items/search/:tenantId":       model.ApiKeyScopeReadSales,
+	"GET /closing_reports/details/:tenantId":   model.ApiKeyScopeReadSales,
+	"GET /closing_reports/:tenantId":           model.ApiKeyScopeReadSales,
+	"POST /order_items/transactions/:tenantId": model.ApiKeyScopeWriteSales,
+	"POST /order_items/place/:tenantId":        model.ApiKeyScopeWriteSales,
+	"GET /store_stocks/:tenantId":              model.ApiKeyScopeReadStock,
+	"GET /store_stocks/v2/:tenantId":           model.ApiKeyScopeReadStock,
+	"GET /warehouses/:tenantId":                model.ApiKeyScopeReadStock,
+	"PUT /store_stocks/edit/:tenantId":         model.ApiKeyScopeWriteStock,
+	"GET /categories/:tenantId":                model.ApiKeyScopeReadCatalog,
+	"GET /customers/details/:tenantId":         model.ApiKeyScopeReadCustomers,
+	"GET /customers/:tenantId":                 model.ApiKeyScopeReadCustomers,
+	"POST /customers/:tenantId":                model.ApiKeyScopeWriteCustomers,
+	"PUT /customers/:tenantId":                 model.ApiKeyScopeWriteCustomers,
+}
+
+/*
+Only an API key of the tenant at the route, with the scope of the route (see IntegrationRouteScopes).
+
+	Nothing is read from the database, ProtectedRoute already loaded the key.
+	Always put this middleware after protected_route middleware, instead of restrict_by_tenant
+*/
+func ApiKeyRoute() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		apiKeyId, ok := ctx.Locals("api_key_id").(int)
+		if !ok {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Only an API key can access this route"))
+		}
+
+		tenantId, err := strconv.Atoi(ctx.Params("tenantId"))
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(common.NewWebResponseError(400, common.StatusError, "TenantId is not int"))
+		}
+
+		if keyTenantId, _ := ctx.Locals("api_key_tenant_id").(int); keyTenantId != tenantId {
+			log.Warnf("Forbidden action detected. Api key %d of tenant %d, requesting for tenant %d", apiKeyId, keyTenantId, tenantId)
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Access denied to tenant. The API key belong to another tenant"))
+		}
+
+		route := strings.TrimPrefix(ctx.Route().Path, strings.TrimSuffix(IntegrationPathPrefix, "/"))
+		scope, ok := IntegrationRouteScopes[ctx.Method()+" "+route]
+		if !ok {
+			log.Errorf("Integration route %s %s has no scope, refused", ctx.Method(), route)
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "This route can not be accessed with an API key"))
+		}
+
+		scopes, _ := ctx.Locals("api_key_scopes").(string)
+		if !(&model.ApiKey{Scopes: scopes}).HasScope(scope) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, fmt.Sprintf("The API key does not have the %s scope", scope)))
+		}
+
+		// Same as restrict_by_tenant, an archived tenant is read only
+		if tenantActive, _ := ctx.Locals("api_key_tenant_active").(bool); !tenantActive && !isReadMethod(ctx.Method()) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "This tenant is archived and read only. The owner can restore it before it is deleted"))
+		}
+
+		acceptAuditTenant(ctx, tenantId)
+		return ctx.Next()
+	}
+}
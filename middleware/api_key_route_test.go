@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyRoute(t *testing.T) {
+	testTimeout := int((time.Second * 3).Milliseconds())
+
+	newApp := func(locals map[string]interface{}) *fiber.App {
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			for key, value := range locals {
+				ctx.Locals(key, value)
+			}
+			return ctx.Next()
+		})
+		ok := func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		}
+		integration := app.Group("/api/v1/integration")
+		for route := range IntegrationRouteScopes {
+			method, path, _ := strings.Cut(route, " ")
+			integration.Add(method, path, ApiKeyRoute(), ok)
+		}
+		integration.Delete("/customers/:tenantId", ApiKeyRoute(), ok)
+		return app
+	}
+
+	apiKeyLocals := func(tenantActive bool) map[string]interface{} {
+		return map[string]interface{}{
+			"sub":                   1,
+			"api_key_id":            3,
+			"api_key_tenant_id":     1,
+			"api_key_scopes":        "read:stock,write:stock",
+			"api_key_tenant_active": tenantActive,
+		}
+	}
+
+	cases := []struct {
+		name   string
+		locals map[string]interface{}
+		method string
+		url    string
+		status int
+	}{
+		{"UserSession", map[string]interface{}{"sub": 1}, "GET", "/api/v1/integration/store_stocks/1", http.StatusForbidden},
+		{"Allowed", apiKeyLocals(true), "GET", "/api/v1/integration/store_stocks/1", http.StatusOK},
+		{"AnotherTenant", apiKeyLocals(true), "GET", "/api/v1/integration/store_stocks/2", http.StatusForbidden},
+		{"Write", apiKeyLocals(true), "PUT", "/api/v1/integration/store_stocks/edit/1", http.StatusOK},
+		{"ArchivedRead", apiKeyLocals(false), "GET", "/api/v1/integration/store_stocks/1", http.StatusOK},
+		{"ArchivedWrite", apiKeyLocals(false), "PUT", "/api/v1/integration/store_stocks/edit/1", http.StatusForbidden},
+		{"MissingScope", map[string]interface{}{
+			"sub": 1, "api_key_id": 3, "api_key_tenant_id": 1, "api_key_scopes": "read:stock", "api_key_tenant_active": true,
+		}, "PUT", "/api/v1/integration/store_stocks/edit/1", http.StatusForbidden},
+		// Transactions create a sale and move stock
+		{"ReadOnlyTransactions", map[string]interface{}{
+			"sub": 1, "api_key_id": 3, "api_key_tenant_id": 1, "api_key_scopes": "read:sales", "api_key_tenant_active": true,
+		}, "POST", "/api/v1/integration/order_items/transactions/1", http.StatusForbidden},
+		{"WriteTransactions", map[string]interface{}{
+			"sub": 1, "api_key_id": 3, "api_key_tenant_id": 1, "api_key_scopes": "read:sales,write:sales", "api_key_tenant_active": true,
+		}, "POST", "/api/v1/integration/order_items/transactions/1", http.StatusOK},
+		{"RouteWithoutScope", map[string]interface{}{
+			"sub": 1, "api_key_id": 3, "api_key_tenant_id": 1, "api_key_scopes": "write:customers", "api_key_tenant_active": true,
+		}, "DELETE", "/api/v1/integration/customers/1", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			request := httptest.NewRequest(c.method, c.url, nil)
+			response, err := newApp(c.locals).Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, c.status, response.StatusCode)
+		})
+	}
+}
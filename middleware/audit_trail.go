@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"cashier-api/model"
+	"cashier-api/service"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const apiV1PathPrefix = "/api/v1/"
+
+/*
+Record every POST/PUT/DELETE in the audit log, once the handler answered.
+
+	The event get a tenant only when restrict_by_tenant (or terminal_route, api_key_route) accepted the request,
+	see acceptAuditTenant. Only then the entity of the route resource is snapshotted before and after the handler,
+	see AuditService.Entity. The request is never refused because of the audit, a failure is only logged.
+	Always put this middleware after protected_route middleware
+*/
+func AuditTrail(auditService service.AuditService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userId, ok := ctx.Locals("sub").(int)
+		if !ok || isReadMethod(ctx.Method()) {
+			return ctx.Next()
+		}
+
+		audit := &pendingAudit{
+			service: auditService,
+			event: &model.AuditEvent{
+				UserId:    userId,
+				Method:    ctx.Method(),
+				IpAddress: ctx.IP(),
+				RequestId: auditRequestId(ctx),
+			},
+		}
+		if terminalId, ok := ctx.Locals("terminal_id").(int); ok {
+			audit.event.TerminalId = &terminalId
+		}
+		if apiKeyId, ok := ctx.Locals("api_key_id").(int); ok {
+			audit.event.ApiKeyId = &apiKeyId
+		}
+		audit.entity = auditService.Entity(auditResource(ctx.Path()))
+		if audit.entity != nil {
+			audit.entityId = auditEntityId(ctx, audit.entity)
+		}
+		ctx.Locals("audit", audit)
+
+		err := ctx.Next()
+
+		event := audit.event
+		event.Route = ctx.Route().Path
+		event.StatusCode = ctx.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			event.StatusCode = fiberErr.Code
+		} else if err != nil {
+			event.StatusCode = fiber.StatusInternalServerError
+		}
+
+		if event.TenantId != nil && audit.entity != nil {
+			event.EntityType = audit.entity.Type
+			if audit.entityId == 0 && event.Succeeded() {
+				// Created by the request, the response data is the entity
+				audit.entityId = auditResponseId(ctx, audit.entity)
+			}
+			if audit.entityId != 0 {
+				event.EntityId = &audit.entityId
+				if event.Succeeded() {
+					event.After = auditService.Snapshot(audit.entity, *event.TenantId, audit.entityId)
+				}
+			}
+		}
+
+		if recordErr := auditService.Record(event); recordErr != nil {
+			log.Errorf("Could not record the audit of %s %s by user %d, reason: %s", event.Method, event.Route, userId, recordErr.Error())
+		}
+
+		return err
+	}
+}
+
+// The audit of the request being handled, at ctx.Locals("audit")
+type pendingAudit struct {
+	service  service.AuditService
+	event    *model.AuditEvent
+	entity   *model.AuditEntity
+	entityId int
+}
+
+/*
+Called by the middlewares checking the tenant, once the user is allowed to it.
+
+	Before it the tenant of the URL is not trusted: an outsider request is recorded without tenant,
+	and the row of another tenant is never read
+*/
+func acceptAuditTenant(ctx *fiber.Ctx, tenantId int) {
+	audit, ok := ctx.Locals("audit").(*pendingAudit)
+	if !ok {
+		return
+	}
+
+	audit.event.TenantId = &tenantId
+	if audit.entity != nil && audit.entityId != 0 {
+		audit.event.Before = audit.service.Snapshot(audit.entity, tenantId, audit.entityId)
+	}
+}
+
+// Reuse the request id of request_debug middleware or the client, a new one otherwise
+func auditRequestId(ctx *fiber.Ctx) string {
+	requestId := string(ctx.Response().Header.Peek("X-Request-ID"))
+	if requestId == "" {
+		requestId = ctx.Get("X-Request-ID")
+	}
+	if requestId == "" {
+		requestId = uuid.New().String()
+	}
+	ctx.Set("X-Request-ID", requestId)
+	return requestId
+}
+
+// The first path segment after /api/v1, the terminal and integration routes share the resource of their normal route
+func auditResource(path string) string {
+	for _, prefix := range []string{TerminalPathPrefix, IntegrationPathPrefix, apiV1PathPrefix} {
+		if strings.HasPrefix(path, prefix) {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	resource, _, _ := strings.Cut(path, "/")
+	return resource
+}
+
+// The first id field of the entity found at the JSON body or the query, 0 when not found
+func auditEntityId(ctx *fiber.Ctx, entity *model.AuditEntity) int {
+	var body map[string]interface{}
+	if len(ctx.Body()) > 0 {
+		// Not a JSON object is checked by the handler, here only the query is left
+		_ = json.Unmarshal(ctx.Body(), &body)
+	}
+
+	for _, field := range entity.IdFields {
+		var value interface{} = body
+		for _, key := range strings.Split(field, ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				value = nil
+				break
+			}
+			value = object[key]
+		}
+		if id, ok := value.(float64); ok && id > 0 && id == float64(int(id)) {
+			return int(id)
+		}
+
+		if id, err := strconv.Atoi(ctx.Query(field)); err == nil && id > 0 {
+			return id
+		}
+	}
+
+	return 0
+}
+
+// The primary key of the entity at the response data, 0 when it is not an object with it
+func auditResponseId(ctx *fiber.Ctx, entity *model.AuditEntity) int {
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(ctx.Response().Body(), &response); err != nil {
+		return 0
+	}
+
+	id, ok := response.Data[entity.PrimaryKey].(float64)
+	if !ok || id <= 0 || id != float64(int(id)) {
+		return 0
+	}
+	return int(id)
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"cashier-api/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditTrail(t *testing.T) {
+	testTimeout := int((time.Second * 3).Milliseconds())
+
+	newApp := func(config ...fiber.Config) (*fiber.App, *repository.AuditRepositoryMock, *[]*model.AuditEvent) {
+		auditRepo := repository.NewAuditRepositoryMock(&mock.Mock{}).(*repository.AuditRepositoryMock)
+		recorded := make([]*model.AuditEvent, 0)
+		auditRepo.Mock.On("Create", mock.AnythingOfType("*model.AuditEvent")).Run(func(args mock.Arguments) {
+			recorded = append(recorded, args.Get(0).(*model.AuditEvent))
+		}).Return(nil)
+
+		app := fiber.New(config...)
+		apiV1 := app.Group("/api/v1")
+		apiV1.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 5)
+			return ctx.Next()
+		})
+		apiV1.Use(AuditTrail(service.NewAuditServiceImpl(auditRepo)))
+		// Stand-in of restrict_by_tenant, the user is a member of tenant 1 only
+		tenantRestriction := func(ctx *fiber.Ctx) error {
+			if ctx.Params("tenantId") != "1" {
+				return ctx.SendStatus(fiber.StatusForbidden)
+			}
+			acceptAuditTenant(ctx, 1)
+			return ctx.Next()
+		}
+		apiV1.Get("/stores/:tenantId", tenantRestriction, func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+		apiV1.Post("/stores/:tenantId", tenantRestriction, func(ctx *fiber.Ctx) error {
+			return ctx.JSON(fiber.Map{"code": 200, "data": fiber.Map{"id": 9, "name": "Toko"}})
+		})
+		apiV1.Put("/stores/set_activate/:tenantId", tenantRestriction, func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+		apiV1.Delete("/categories/:tenantId", tenantRestriction, func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusForbidden)
+		})
+		apiV1.Put("/warehouses/edit/:tenantId", tenantRestriction, func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+		apiV1.Post("/warehouses/create_item/:tenantId", tenantRestriction, func(ctx *fiber.Ctx) error {
+			return ctx.JSON(fiber.Map{"code": 200, "data": fiber.Map{"item_id": 8, "item_name": "Kopi"}})
+		})
+		apiV1.Put("/users/password", func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+		return app, auditRepo, &recorded
+	}
+
+	send := func(app *fiber.App, method string, path string, body string) *http.Response {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Request-ID", "request-1")
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		return response
+	}
+
+	t.Run("BeforeAndAfter", func(t *testing.T) {
+		app, auditRepo, recorded := newApp()
+		auditRepo.Mock.On("Snapshot", mock.Anything, 1, 3).Return(model.AuditValue(`{"is_active":true}`), nil).Once()
+		auditRepo.Mock.On("Snapshot", mock.Anything, 1, 3).Return(model.AuditValue(`{"is_active":false}`), nil).Once()
+
+		assert.Equal(t, http.StatusOK, send(app, "PUT", "/api/v1/stores/set_activate/1", `{"store_id":3,"set_into":false}`).StatusCode)
+		require.Len(t, *recorded, 1)
+		event := (*recorded)[0]
+		assert.Equal(t, 5, event.UserId)
+		assert.Equal(t, 1, *event.TenantId)
+		assert.Equal(t, "PUT", event.Method)
+		assert.Equal(t, "/api/v1/stores/set_activate/:tenantId", event.Route)
+		assert.Equal(t, http.StatusOK, event.StatusCode)
+		assert.Equal(t, "store", event.EntityType)
+		assert.Equal(t, 3, *event.EntityId)
+		assert.Equal(t, model.AuditValue(`{"is_active":true}`), event.Before)
+		assert.Equal(t, model.AuditValue(`{"is_active":false}`), event.After)
+		assert.Equal(t, "request-1", event.RequestId)
+	})
+
+	t.Run("Created", func(t *testing.T) {
+		app, auditRepo, recorded := newApp()
+		auditRepo.Mock.On("Snapshot", mock.Anything, 1, 9).Return(model.AuditValue(`{"id":9}`), nil).Once()
+
+		assert.Equal(t, http.StatusOK, send(app, "POST", "/api/v1/stores/1", `{"name":"Toko"}`).StatusCode)
+		require.Len(t, *recorded, 1)
+		assert.Equal(t, 9, *(*recorded)[0].EntityId)
+		assert.Equal(t, model.AuditValue(""), (*recorded)[0].Before)
+		assert.Equal(t, model.AuditValue(`{"id":9}`), (*recorded)[0].After)
+	})
+
+	t.Run("WarehouseItem", func(t *testing.T) {
+		app, auditRepo, recorded := newApp()
+		isItem := mock.MatchedBy(func(entity *model.AuditEntity) bool {
+			return entity.Table == "warehouse" && entity.PrimaryKey == "item_id"
+		})
+		auditRepo.Mock.On("Snapshot", isItem, 1, 7).Return(model.AuditValue(`{"item_id":7,"base_price":1000}`), nil).Once()
+		auditRepo.Mock.On("Snapshot", isItem, 1, 7).Return(model.AuditValue(`{"item_id":7,"base_price":1200}`), nil).Once()
+		auditRepo.Mock.On("Snapshot", isItem, 1, 8).Return(model.AuditValue(`{"item_id":8}`), nil).Once()
+
+		assert.Equal(t, http.StatusOK, send(app, "PUT", "/api/v1/warehouses/edit/1", `{"quantity":0,"item":{"item_id":7,"base_price":1200}}`).StatusCode)
+		assert.Equal(t, http.StatusOK, send(app, "POST", "/api/v1/warehouses/create_item/1", `{"items":[{"item_name":"Kopi"}]}`).StatusCode)
+		require.Len(t, *recorded, 2)
+		assert.Equal(t, "item", (*recorded)[0].EntityType)
+		assert.Equal(t, 7, *(*recorded)[0].EntityId)
+		assert.Equal(t, model.AuditValue(`{"item_id":7,"base_price":1000}`), (*recorded)[0].Before)
+		assert.Equal(t, model.AuditValue(`{"item_id":7,"base_price":1200}`), (*recorded)[0].After)
+		// Created, the response data primary key is item_id
+		assert.Equal(t, 8, *(*recorded)[1].EntityId)
+		assert.Equal(t, model.AuditValue(`{"item_id":8}`), (*recorded)[1].After)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		app, auditRepo, recorded := newApp()
+		auditRepo.Mock.On("Snapshot", mock.Anything, 1, 4).Return(model.AuditValue(`{"id":4}`), nil).Once()
+
+		assert.Equal(t, http.StatusForbidden, send(app, "DELETE", "/api/v1/categories/1?category_id=4", "").StatusCode)
+		require.Len(t, *recorded, 1)
+		assert.Equal(t, http.StatusForbidden, (*recorded)[0].StatusCode)
+		assert.Equal(t, model.AuditValue(""), (*recorded)[0].After)
+		auditRepo.Mock.AssertNumberOfCalls(t, "Snapshot", 1)
+	})
+
+	t.Run("Outsider", func(t *testing.T) {
+		app, auditRepo, recorded := newApp()
+
+		// Another tenant row is never read, and its audit log is not written
+		assert.Equal(t, http.StatusForbidden, send(app, "PUT", "/api/v1/stores/set_activate/2", `{"store_id":3,"set_into":false}`).StatusCode)
+		require.Len(t, *recorded, 1)
+		assert.Nil(t, (*recorded)[0].TenantId)
+		assert.Equal(t, http.StatusForbidden, (*recorded)[0].StatusCode)
+		assert.Equal(t, "", (*recorded)[0].EntityType)
+		assert.Equal(t, model.AuditValue(""), (*recorded)[0].Before)
+		auditRepo.Mock.AssertNotCalled(t, "Snapshot", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("WithoutTenant", func(t *testing.T) {
+		app, _, recorded := newApp()
+
+		assert.Equal(t, http.StatusOK, send(app, "PUT", "/api/v1/users/password", `{"password":"secret"}`).StatusCode)
+		require.Len(t, *recorded, 1)
+		assert.Nil(t, (*recorded)[0].TenantId)
+		assert.Equal(t, "", (*recorded)[0].EntityType)
+	})
+
+	t.Run("ClientBehindProxy", func(t *testing.T) {
+		// Same as main.go, app.Test() request come from 0.0.0.0
+		behindProxy := func(trustedProxy string) string {
+			app, _, recorded := newApp(fiber.Config{
+				EnableTrustedProxyCheck: true,
+				TrustedProxies:          []string{trustedProxy},
+				ProxyHeader:             fiber.HeaderXForwardedFor,
+				EnableIPValidation:      true,
+			})
+
+			request := httptest.NewRequest("PUT", "/api/v1/users/password", strings.NewReader(`{"password":"secret"}`))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set(fiber.HeaderXForwardedFor, "203.0.113.7, 10.0.0.1")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			require.Len(t, *recorded, 1)
+			return (*recorded)[0].IpAddress
+		}
+
+		assert.Equal(t, "203.0.113.7", behindProxy("0.0.0.0"))
+		// Untrusted remote could not choose its IP
+		assert.Equal(t, "0.0.0.0", behindProxy("10.0.0.1"))
+	})
+
+	t.Run("ReadNotRecorded", func(t *testing.T) {
+		app, _, recorded := newApp()
+
+		assert.Equal(t, http.StatusOK, send(app, "GET", "/api/v1/stores/1", "").StatusCode)
+		assert.Empty(t, *recorded)
+	})
+
+	t.Run("Resource", func(t *testing.T) {
+		assert.Equal(t, "stores", auditResource("/api/v1/stores/set_activate/1"))
+		assert.Equal(t, "order_items", auditResource("/api/v1/terminal/order_items/place/1"))
+		assert.Equal(t, "customers", auditResource("/api/v1/integration/customers/1"))
+	})
+}
@@ -3,15 +3,36 @@ package middleware
 import (
 	common "cashier-api/helper"
 	constant "cashier-api/helper/constant/cookie"
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
-func ProtectedRoute(ctx *fiber.Ctx) error {
+// last_seen_at of the session is written at most once per interval, not on every request
+const sessionTouchInterval = time.Minute
+
+/*
+Sign in is required, the access token session must be active (not revoked, not expired).
+Store sub (user id) and sid (session id) at ctx.Locals.
+
+	A terminal session (PIN sign in) only pass under TerminalPathPrefix, with terminal_id at ctx.Locals.
+	An API key (Bearer starting with model.ApiKeyTokenPrefix) only pass under IntegrationPathPrefix, see apiKeyRoute
+*/
+func ProtectedRoute(client *gorm.DB) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return protectedRoute(ctx, client)
+	}
+}
+
+func protectedRoute(ctx *fiber.Ctx, client *gorm.DB) error {
 	var tokenString string
 
 	// Check user cookie, if user cookie not available immediately return unauthorized
@@ -30,6 +51,9 @@ func ProtectedRoute(ctx *fiber.Ctx) error {
 			return ctx.Status(fiber.StatusUnauthorized).
 				JSON(common.NewWebResponseError(401, common.StatusError, "Sign in to access this route"))
 		}
+		if strings.HasPrefix(tokenString, model.ApiKeyTokenPrefix) {
+			return apiKeyRoute(ctx, client, tokenString)
+		}
 	}
 
 	// Check user jwt token validity
@@ -60,8 +84,52 @@ func ProtectedRoute(ctx *fiber.Ctx) error {
 			JSON(common.NewWebResponseError(400, common.StatusError, "Unexpected behavior ! JWT body contain invalid value (2)"))
 	}
 
+	// Token issued before the session were introduced, or without one
+	sid, ok := claims["sid"].(float64)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Session not found, Please try sign in again."))
+	}
+
+	// Signed out / reused refresh token revoke the session before the access token expire
+	now := time.Now()
+	var activeSession struct {
+		TerminalId *int
+	}
+	err = client.Model(&model.UserSession{}).
+		Select("terminal_id").
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", int(sid), int(sub), now).
+		Take(&activeSession).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(common.NewWebResponseError(401, common.StatusError, "Session is over, Please try sign in again."))
+	}
+	if err != nil {
+		log.Errorf("Could not check session %d, reason: %s", int(sid), err.Error())
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(common.NewWebResponseError(500, common.StatusError, "Something gone wrong ! Could not check the session"))
+	}
+
+	// PIN sign in on a shared terminal, TerminalRoute check the tenant and the store
+	if activeSession.TerminalId != nil {
+		if !strings.HasPrefix(ctx.Path(), TerminalPathPrefix) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "PIN sign in can only access the terminal sales routes"))
+		}
+		ctx.Locals("terminal_id", *activeSession.TerminalId)
+	}
+
+	err = client.Model(&model.UserSession{}).
+		Where("id = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", int(sid), now.Add(-sessionTouchInterval)).
+		UpdateColumn("last_seen_at", now).Error
+	if err != nil {
+		// Not worth to refuse the request
+		log.Warnf("Could not update last seen of session %d, reason: %s", int(sid), err.Error())
+	}
+
 	// Store valuable data to send to next handler
 	ctx.Locals("sub", int(sub))
+	ctx.Locals("sid", int(sid))
 
 	log.Debugf("Accessing protected route from sub/id: %d", int(sub))
 	log.Debugf("Current user will logged in until: %f", exp)
@@ -69,3 +137,54 @@ func ProtectedRoute(ctx *fiber.Ctx) error {
 	// If ok then go to next handler
 	return ctx.Next()
 }
+
+/*
+The API key must be active, its tenant and scopes are checked by ApiKeyRoute without any membership lookup.
+Store sub (the member who created the key), api_key_id, api_key_tenant_id, api_key_scopes and api_key_tenant_active at ctx.Locals
+*/
+func apiKeyRoute(ctx *fiber.Ctx, client *gorm.DB, key string) error {
+	now := time.Now()
+	var apiKey struct {
+		Id              int
+		TenantId        int
+		Scopes          string
+		CreatedByUserId int
+		TenantIsActive  bool
+	}
+	err := client.Table("api_key AS k").
+		Select("k.id, k.tenant_id, k.scopes, k.created_by_user_id, t.is_active AS tenant_is_active").
+		Joins("JOIN tenant t ON t.id = k.tenant_id").
+		Where("k.key_hash = ? AND k.revoked_at IS NULL AND (k.expires_at IS NULL OR k.expires_at > ?)", token.Hash(key), now).
+		Take(&apiKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(common.NewWebResponseError(401, common.StatusError, "API key is invalid, expired or revoked"))
+	}
+	if err != nil {
+		log.Errorf("Could not check api key, reason: %s", err.Error())
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(common.NewWebResponseError(500, common.StatusError, "Something gone wrong ! Could not check the API key"))
+	}
+
+	if !strings.HasPrefix(ctx.Path(), IntegrationPathPrefix) {
+		return ctx.Status(fiber.StatusForbidden).
+			JSON(common.NewWebResponseError(403, common.StatusError, "API key can only access the integration routes"))
+	}
+
+	err = client.Model(&model.ApiKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", apiKey.Id, now.Add(-sessionTouchInterval)).
+		UpdateColumn("last_used_at", now).Error
+	if err != nil {
+		// Not worth to refuse the request
+		log.Warnf("Could not update last used of api key %d, reason: %s", apiKey.Id, err.Error())
+	}
+
+	ctx.Locals("sub", apiKey.CreatedByUserId)
+	ctx.Locals("api_key_id", apiKey.Id)
+	ctx.Locals("api_key_tenant_id", apiKey.TenantId)
+	ctx.Locals("api_key_scopes", apiKey.Scopes)
+	ctx.Locals("api_key_tenant_active", apiKey.TenantIsActive)
+
+	log.Debugf("Accessing integration route with api key %d of tenant %d", apiKey.Id, apiKey.TenantId)
+	return ctx.Next()
+}
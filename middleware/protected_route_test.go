@@ -5,6 +5,9 @@ import (
 	common "cashier-api/helper"
 	"cashier-api/helper/client"
 	constant "cashier-api/helper/constant/cookie"
+	"cashier-api/helper/mail"
+	"cashier-api/helper/token"
+	"cashier-api/model"
 	"cashier-api/repository"
 	"cashier-api/service"
 	"fmt"
@@ -18,6 +21,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,12 +36,13 @@ func TestProtectedRoute(t *testing.T) {
 
 	// To create new user, User endpoint are needed
 	userRepository := repository.NewUserRepositoryImpl(gormClient)
-	userService := service.NewUserServiceImpl(userRepository)
+	userService := service.NewUserServiceImpl(userRepository, repository.NewSessionRepositoryImpl(gormClient), repository.NewUserTokenRepositoryImpl(gormClient), repository.NewTwoFactorRepositoryImpl(gormClient), repository.NewSignInAttemptRepositoryImpl(gormClient), mail.NewMemoryMailer())
 	userController := controller.NewUserControllerImpl(userService)
 
 	app := fiber.New()
 	app.Post("/users/sign_up", userController.SignUpWithEmailAndPassword)
-	app.Get("/test", ProtectedRoute, func(ctx *fiber.Ctx) error {
+	app.Delete("/users/sign_out", userController.SignOut)
+	app.Get("/test", ProtectedRoute(gormClient), func(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ok"})
 	})
 
@@ -128,4 +133,109 @@ func TestProtectedRoute(t *testing.T) {
 			Execute()
 		require.Nil(t, err)
 	})
+
+	t.Run("RevokedSession", func(t *testing.T) {
+		body := strings.NewReader(`{
+				"email": "revokedsessionuser@gmail.com",
+				"password": "12345678",
+				"name": "RevokedSession User"
+		}`)
+		request := httptest.NewRequest("POST", "/users/sign_up", body)
+		request.Header.Set("Content-Type", "application/json")
+		response, err := app.Test(request, int(time.Second*5))
+		require.NoError(t, err)
+		require.Equal(t, 201, response.StatusCode)
+
+		var enterprisePOSCookie *http.Cookie = nil
+		for _, c := range response.Cookies() {
+			if c.Name == constant.EnterprisePOS {
+				enterprisePOSCookie = c
+			}
+		}
+		require.NotNil(t, enterprisePOSCookie)
+
+		claims := jwt.MapClaims{}
+		_, err = common.ClaimJWT(enterprisePOSCookie.Value, &claims)
+		require.NoError(t, err)
+		userId, ok := claims["sub"].(float64)
+		require.True(t, ok)
+
+		request = httptest.NewRequest("GET", "/test", nil)
+		request.AddCookie(enterprisePOSCookie)
+		response, err = app.Test(request, int(time.Second*5))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+
+		// Sign out revoke the session, the access token is not expired yet but refused
+		request = httptest.NewRequest("DELETE", "/users/sign_out", nil)
+		request.AddCookie(enterprisePOSCookie)
+		response, err = app.Test(request, int(time.Second*5))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+
+		request = httptest.NewRequest("GET", "/test", nil)
+		request.AddCookie(enterprisePOSCookie)
+		response, err = app.Test(request, int(time.Second*5))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+
+		_, _, err = supabaseClient.From("user").
+			Delete("", "").
+			Eq("id", fmt.Sprint(userId)).
+			Execute()
+		require.Nil(t, err)
+	})
+}
+
+func TestProtectedRouteApiKey(t *testing.T) {
+	gormClient := client.CreateGormClient()
+	tx := gormClient.Begin()
+	defer tx.Rollback()
+
+	owner := &model.User{Name: "ApiKey Owner", Email: strings.ReplaceAll(uuid.NewString(), "-", "") + "@test.com"}
+	require.NoError(t, tx.Create(owner).Error)
+	tenant := &model.Tenant{Name: "ApiKey Tenant", OwnerUserId: owner.Id, IsActive: true}
+	require.NoError(t, tx.Create(tenant).Error)
+
+	app := fiber.New()
+	app.Get(IntegrationPathPrefix+"test", ProtectedRoute(tx), func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+	newManagerKey := func() (int, string) {
+		manager := &model.User{Name: "ApiKey Manager", Email: strings.ReplaceAll(uuid.NewString(), "-", "") + "@test.com"}
+		require.NoError(t, tx.Create(manager).Error)
+		require.NoError(t, tx.Create(&model.UserMtmTenant{UserId: manager.Id, TenantId: tenant.Id, Role: model.TenantRoleManager}).Error)
+
+		key := model.ApiKeyTokenPrefix + strings.ReplaceAll(uuid.NewString(), "-", "")
+		require.NoError(t, tx.Create(&model.ApiKey{
+			TenantId:        tenant.Id,
+			Name:            "Integration",
+			Prefix:          key[:8],
+			KeyHash:         token.Hash(key),
+			Scopes:          string(model.ApiKeyScopeReadStock),
+			CreatedByUserId: manager.Id,
+		}).Error)
+		return manager.Id, key
+	}
+	send := func(key string) int {
+		request := httptest.NewRequest("GET", IntegrationPathPrefix+"test", nil)
+		request.Header.Set("Authorization", "Bearer "+key)
+		response, err := app.Test(request, int(time.Second*5))
+		require.NoError(t, err)
+		return response.StatusCode
+	}
+	tenantRepo := repository.NewTenantRepositoryImpl(tx)
+
+	// The key act as its creator, it is revoked once the creator is removed from the tenant
+	removedId, removedKey := newManagerKey()
+	assert.Equal(t, http.StatusOK, send(removedKey))
+	_, err := tenantRepo.RemoveUserFromTenant(&model.UserMtmTenant{UserId: removedId, TenantId: tenant.Id}, owner.Id)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, send(removedKey))
+
+	// Same once the creator could no longer manage the keys
+	demotedId, demotedKey := newManagerKey()
+	assert.Equal(t, http.StatusOK, send(demotedKey))
+	require.NoError(t, tenantRepo.SetMemberRole(tenant.Id, owner.Id, demotedId, model.TenantRoleCashier))
+	assert.Equal(t, http.StatusUnauthorized, send(demotedKey))
 }
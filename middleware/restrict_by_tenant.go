@@ -30,9 +30,9 @@ func RestrictByTenant(client *gorm.DB) fiber.Handler {
 				JSON(common.NewWebResponseError(400, common.StatusError, "Missing tenant_id at the parameter"))
 		}
 
-		// Here we don't need the int tenantId, but if there is no error then the next handler is guaranteed to be int
+		// If there is no error then the next handler is guaranteed to be int
 		// Try to see example for warehouse.CreateItem
-		_, err := strconv.Atoi(paramTenantId)
+		tenantId, err := strconv.Atoi(paramTenantId)
 		if err != nil {
 			return ctx.Status(fiber.StatusBadRequest).
 				JSON(common.NewWebResponseError(400, common.StatusError, "TenantId is not int"))
@@ -40,8 +40,20 @@ func RestrictByTenant(client *gorm.DB) fiber.Handler {
 
 		// Check if relation exists in user_mtm_tenant
 		// supabase returns error when no rows found
-		var exist model.UserMtmTenant
-		err = client.Select("user_id, tenant_id").Where("user_id", userId).Where("tenant_id", paramTenantId).Take(&exist).Error
+		var membership struct {
+			Role             model.TenantRole
+			OwnerUserId      int
+			RequireTwoFactor bool
+			TwoFactorEnabled bool
+			IsActive         bool
+		}
+		err = client.Table("user_mtm_tenant AS umt").
+			Select(`umt.role, t.owner_user_id, t.require_two_factor, t.is_active,
+				u.totp_enabled_at IS NOT NULL AS two_factor_enabled`).
+			Joins("JOIN tenant t ON t.id = umt.tenant_id").
+			Joins(`JOIN "user" u ON u.id = umt.user_id`).
+			Where("umt.user_id = ? AND umt.tenant_id = ?", userId, paramTenantId).
+			Take(&membership).Error
 
 		if err != nil {
 			log.Warnf("Forbidden action detected. Current user is not associate with requested tenant. From userId: %d, requesting for tenantId: %s", userId, paramTenantId)
@@ -49,7 +61,26 @@ func RestrictByTenant(client *gorm.DB) fiber.Handler {
 				JSON(common.NewWebResponseError(403, common.StatusError, "Access denied to tenant. Current user is not associate with requested tenant."))
 		}
 
+		// Tenant policy, the owner and managers must enroll first (/users/two_factor is not restricted by tenant)
+		isPrivileged := membership.OwnerUserId == userId || membership.Role == model.TenantRoleManager
+		if membership.RequireTwoFactor && isPrivileged && !membership.TwoFactorEnabled {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "This tenant require two factor authentication for owner and managers. Please enable it first"))
+		}
+
+		// Archived tenant is read only until restored or deleted, GET /exports still give its data.
+		// The POST used to read (search, reports) are refused as well
+		if !membership.IsActive && !isReadMethod(ctx.Method()) {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "This tenant is archived and read only. The owner can restore it before it is deleted"))
+		}
+
 		// ✅ Authorized
+		acceptAuditTenant(ctx, tenantId)
 		return ctx.Next()
 	}
 }
+
+func isReadMethod(method string) bool {
+	return method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions
+}
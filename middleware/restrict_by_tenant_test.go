@@ -118,6 +118,46 @@ func TestRestrictByTenant(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
+	t.Run("TwoFactorPolicy", func(t *testing.T) {
+		require.NoError(t, gormClient.Model(dummyTenant).Update("require_two_factor", true).Error)
+		defer gormClient.Model(dummyTenant).Update("require_two_factor", false)
+
+		// Owner without 2FA is refused
+		req := httptest.NewRequest("GET", fmt.Sprintf("/test/%d", dummyTenant.Id), nil)
+		resp, err := newApp().Test(req, testTimeout)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		now := time.Now()
+		require.NoError(t, gormClient.Model(dummyUser).UpdateColumns(map[string]interface{}{"totp_secret": "JBSWY3DPEHPK3PXP", "totp_enabled_at": now}).Error)
+		defer gormClient.Model(dummyUser).UpdateColumns(map[string]interface{}{"totp_secret": "", "totp_enabled_at": nil})
+
+		req = httptest.NewRequest("GET", fmt.Sprintf("/test/%d", dummyTenant.Id), nil)
+		resp, err = newApp().Test(req, testTimeout)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("ArchivedTenantReadOnly", func(t *testing.T) {
+		require.NoError(t, gormClient.Model(dummyTenant).Update("is_active", false).Error)
+		defer gormClient.Model(dummyTenant).Update("is_active", true)
+
+		app := newApp()
+		app.Post("/test/:tenantId", RestrictByTenant(gormClient), func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", fmt.Sprintf("/test/%d", dummyTenant.Id), nil)
+		resp, err := app.Test(req, testTimeout)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		req = httptest.NewRequest("POST", fmt.Sprintf("/test/%d", dummyTenant.Id), nil)
+		resp, err = app.Test(req, testTimeout)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
 	t.Cleanup(func() {
 		gormClient.Where("user_id", dummyUser.Id).Where("tenant_id", dummyTenant.Id).
 			Delete(&model.UserMtmTenant{})
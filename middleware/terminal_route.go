@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	common "cashier-api/helper"
+	"encoding/json"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Sales routes reachable with a PIN sign in, mounted again under this prefix with TerminalRoute
+const TerminalPathPrefix = "/api/v1/terminal/"
+
+/*
+Only a session signed in with a PIN on a terminal, for the terminal tenant and store.
+
+	The store is store_id of the query, or of the JSON body (both the same when both given). It is required.
+	Always put this middleware after protected_route middleware, instead of restrict_by_tenant
+*/
+func TerminalRoute(client *gorm.DB) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userId, _ := ctx.Locals("sub").(int)
+		terminalId, ok := ctx.Locals("terminal_id").(int)
+		if !ok {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Only a PIN sign in on a terminal can access this route"))
+		}
+
+		tenantId, err := strconv.Atoi(ctx.Params("tenantId"))
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(common.NewWebResponseError(400, common.StatusError, "TenantId is not int"))
+		}
+
+		storeId, ok := terminalStoreId(ctx)
+		if !ok {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(common.NewWebResponseError(400, common.StatusError, "Please check store_id, it is required on terminal routes"))
+		}
+
+		// Still a member, and the terminal is not revoked
+		var terminal struct {
+			TenantId       int
+			StoreId        int
+			TenantIsActive bool
+		}
+		err = client.Table("terminal AS t").
+			Select("t.tenant_id, t.store_id, tn.is_active AS tenant_is_active").
+			Joins("JOIN user_mtm_tenant umt ON umt.tenant_id = t.tenant_id AND umt.user_id = ?", userId).
+			Joins("JOIN tenant tn ON tn.id = t.tenant_id").
+			Where("t.id = ? AND t.revoked_at IS NULL", terminalId).
+			Take(&terminal).Error
+		if err != nil {
+			log.Warnf("Terminal %d refused for user %d, reason: %s", terminalId, userId, err.Error())
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Terminal was revoked or the user is not a member anymore"))
+		}
+
+		if terminal.TenantId != tenantId || terminal.StoreId != storeId {
+			log.Warnf("Forbidden action detected. Terminal %d of tenant %d store %d, requesting for tenant %d store %d", terminalId, terminal.TenantId, terminal.StoreId, tenantId, storeId)
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "Access denied. The terminal is registered to another store"))
+		}
+
+		if !terminal.TenantIsActive {
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(common.NewWebResponseError(403, common.StatusError, "This tenant is archived, the terminal can not be used"))
+		}
+
+		acceptAuditTenant(ctx, tenantId)
+		return ctx.Next()
+	}
+}
+
+/*
+store_id of the query, else of the JSON body.
+
+	Not ok when both are given and differ, the handlers use the one of the body
+*/
+func terminalStoreId(ctx *fiber.Ctx) (int, bool) {
+	var body struct {
+		StoreId int `json:"store_id"`
+	}
+	hasBody := len(ctx.Body()) > 0 && json.Unmarshal(ctx.Body(), &body) == nil
+
+	if paramStoreId := ctx.Query("store_id"); paramStoreId != "" {
+		storeId, err := strconv.Atoi(paramStoreId)
+		if hasBody && body.StoreId != 0 && body.StoreId != storeId {
+			return 0, false
+		}
+		return storeId, err == nil && storeId > 0
+	}
+
+	if !hasBody {
+		return 0, false
+	}
+	return body.StoreId, body.StoreId > 0
+}
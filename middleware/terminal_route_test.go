@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalRoute(t *testing.T) {
+	testTimeout := int((time.Second * 3).Milliseconds())
+
+	t.Run("RefuseUserSession", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			ctx.Locals("sub", 1)
+			return ctx.Next()
+		})
+		app.Get("/api/v1/terminal/order_items/open/:tenantId", TerminalRoute(nil), func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+
+		request := httptest.NewRequest("GET", "/api/v1/terminal/order_items/open/1?store_id=1", nil)
+		response, err := app.Test(request, testTimeout)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	})
+
+	t.Run("StoreId", func(t *testing.T) {
+		app := fiber.New()
+		app.All("/", func(ctx *fiber.Ctx) error {
+			storeId, ok := terminalStoreId(ctx)
+			if !ok {
+				return ctx.SendStatus(fiber.StatusBadRequest)
+			}
+			return ctx.JSON(storeId)
+		})
+
+		cases := []struct {
+			name   string
+			url    string
+			body   string
+			status int
+		}{
+			{"Query", "/?store_id=3", "", http.StatusOK},
+			{"Body", "/", `{"store_id":3,"items":[]}`, http.StatusOK},
+			{"Missing", "/", `{"items":[]}`, http.StatusBadRequest},
+			{"NotInt", "/?store_id=abc", "", http.StatusBadRequest},
+			{"NoBody", "/", "", http.StatusBadRequest},
+			{"SameQueryAndBody", "/?store_id=3", `{"store_id":3,"items":[]}`, http.StatusOK},
+			// The handler would use the body store, not the terminal one
+			{"QueryAndBodyDiffer", "/?store_id=3", `{"store_id":4,"items":[]}`, http.StatusBadRequest},
+		}
+		for _, c := range cases {
+			request := httptest.NewRequest("POST", c.url, strings.NewReader(c.body))
+			request.Header.Set("Content-Type", "application/json")
+			response, err := app.Test(request, testTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, c.status, response.StatusCode, c.name)
+		}
+	})
+}
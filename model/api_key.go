@@ -0,0 +1,115 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// What an API key can reach, each integration route require 1 scope
+type ApiKeyScope string
+
+const (
+	ApiKeyScopeReadSales      ApiKeyScope = "read:sales"
+	ApiKeyScopeWriteSales     ApiKeyScope = "write:sales"
+	ApiKeyScopeReadStock      ApiKeyScope = "read:stock"
+	ApiKeyScopeWriteStock     ApiKeyScope = "write:stock"
+	ApiKeyScopeReadCatalog    ApiKeyScope = "read:catalog"
+	ApiKeyScopeReadCustomers  ApiKeyScope = "read:customers"
+	ApiKeyScopeWriteCustomers ApiKeyScope = "write:customers"
+)
+
+var ApiKeyScopes = []ApiKeyScope{
+	ApiKeyScopeReadSales, ApiKeyScopeWriteSales,
+	ApiKeyScopeReadStock, ApiKeyScopeWriteStock,
+	ApiKeyScopeReadCatalog,
+	ApiKeyScopeReadCustomers, ApiKeyScopeWriteCustomers,
+}
+
+func (scope ApiKeyScope) IsValid() bool {
+	for _, known := range ApiKeyScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Every key start with it, so it is told apart from a JWT at the Authorization header
+const ApiKeyTokenPrefix = "epk_"
+
+/*
+ApiKey of a tenant for machine to machine integrations, only the hash of the key is stored.
+
+	Prefix is the start of the key, shown to tell the keys apart. The key act as the member who created it,
+	only on the integration routes of its scopes and only for its tenant
+*/
+type ApiKey struct {
+	Id              int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId        int        `json:"tenant_id"            gorm:"column:tenant_id"`
+	Name            string     `json:"name"                 gorm:"column:name"`
+	Prefix          string     `json:"prefix"               gorm:"column:prefix"`
+	KeyHash         string     `json:"-"                    gorm:"column:key_hash"`
+	Scopes          string     `json:"scopes"               gorm:"column:scopes"` // Separated by comma
+	CreatedByUserId int        `json:"created_by_user_id"   gorm:"column:created_by_user_id"`
+	ExpiresAt       *time.Time `json:"expires_at"           gorm:"column:expires_at"` // nil never expire
+	LastUsedAt      *time.Time `json:"last_used_at"         gorm:"column:last_used_at"`
+	RevokedAt       *time.Time `json:"revoked_at"           gorm:"column:revoked_at"`
+	CreatedAt       *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (ApiKey) TableName() string {
+	return "api_key"
+}
+
+// ApiKeyCreated is returned once by the creation, the key is never shown again
+type ApiKeyCreated struct {
+	*ApiKey
+	Key string `json:"key"`
+}
+
+// ScopeList split Scopes, empty entries are ignored
+func (apiKey *ApiKey) ScopeList() []ApiKeyScope {
+	scopes := make([]ApiKeyScope, 0)
+	for _, scope := range strings.Split(apiKey.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, ApiKeyScope(scope))
+		}
+	}
+	return scopes
+}
+
+func (apiKey *ApiKey) HasScope(scope ApiKeyScope) bool {
+	for _, granted := range apiKey.ScopeList() {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive is not revoked and not expired at now
+func (apiKey *ApiKey) IsActive(now time.Time) bool {
+	return apiKey.RevokedAt == nil && (apiKey.ExpiresAt == nil || now.Before(*apiKey.ExpiresAt))
+}
+
+// JoinApiKeyScopes validate the scopes and join them for ApiKey.Scopes, duplicates are removed
+func JoinApiKeyScopes(scopes []ApiKeyScope) (string, error) {
+	joined := make([]string, 0, len(scopes))
+	seen := make(map[ApiKeyScope]bool)
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return "", fmt.Errorf("Invalid scope: %s", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			joined = append(joined, string(scope))
+		}
+	}
+	if len(joined) == 0 {
+		return "", errors.New("At least 1 scope is Required !")
+	}
+
+	return strings.Join(joined, ","), nil
+}
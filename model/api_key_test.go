@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKey(t *testing.T) {
+	t.Run("JoinScopes", func(t *testing.T) {
+		scopes, err := JoinApiKeyScopes([]ApiKeyScope{ApiKeyScopeReadSales, ApiKeyScopeWriteStock, ApiKeyScopeReadSales})
+		require.Nil(t, err)
+		assert.Equal(t, "read:sales,write:stock", scopes)
+
+		_, err = JoinApiKeyScopes([]ApiKeyScope{ApiKeyScopeReadSales, "admin"})
+		assert.NotNil(t, err)
+		_, err = JoinApiKeyScopes(nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("HasScope", func(t *testing.T) {
+		apiKey := &ApiKey{Scopes: "read:sales, write:stock,"}
+		assert.Equal(t, []ApiKeyScope{ApiKeyScopeReadSales, ApiKeyScopeWriteStock}, apiKey.ScopeList())
+		assert.True(t, apiKey.HasScope(ApiKeyScopeWriteStock))
+		// write does not grant read
+		assert.False(t, apiKey.HasScope(ApiKeyScopeReadStock))
+		assert.Empty(t, (&ApiKey{}).ScopeList())
+	})
+
+	t.Run("IsActive", func(t *testing.T) {
+		now := time.Now()
+		later := now.Add(time.Hour)
+		assert.True(t, (&ApiKey{}).IsActive(now))
+		assert.True(t, (&ApiKey{ExpiresAt: &later}).IsActive(now))
+		assert.False(t, (&ApiKey{ExpiresAt: &later}).IsActive(later))
+		assert.False(t, (&ApiKey{RevokedAt: &now}).IsActive(now))
+	})
+
+	assert.Equal(t, "api_key", ApiKey{}.TableName())
+}
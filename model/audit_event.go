@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+/*
+AuditValue is a JSON document stored as text, the row of an entity before or after a change.
+
+	Written as is in the JSON response, "" is written as null
+*/
+type AuditValue string
+
+func (value AuditValue) MarshalJSON() ([]byte, error) {
+	if value == "" {
+		return []byte("null"), nil
+	}
+	return []byte(value), nil
+}
+
+/*
+AuditEvent is 1 POST/PUT/DELETE request under /api/v1, never updated nor deleted.
+
+	TenantId is nil for the routes without tenant. EntityType, EntityId, Before and After are filled
+	when the route resource is a known AuditEntity, After only when the request succeeded
+*/
+type AuditEvent struct {
+	Id         int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId   *int       `json:"tenant_id"            gorm:"column:tenant_id"`
+	UserId     int        `json:"user_id"              gorm:"column:user_id"`
+	TerminalId *int       `json:"terminal_id"          gorm:"column:terminal_id"` // PIN sign in on a shared terminal
+	ApiKeyId   *int       `json:"api_key_id"           gorm:"column:api_key_id"`
+	Method     string     `json:"method"               gorm:"column:method"`
+	Route      string     `json:"route"                gorm:"column:route"` // The route path, e.g. /api/v1/stores/:tenantId
+	StatusCode int        `json:"status_code"          gorm:"column:status_code"`
+	EntityType string     `json:"entity_type"          gorm:"column:entity_type"`
+	EntityId   *int       `json:"entity_id"            gorm:"column:entity_id"`
+	Before     AuditValue `json:"before"               gorm:"column:before_value"`
+	After      AuditValue `json:"after"                gorm:"column:after_value"`
+	IpAddress  string     `json:"ip_address"           gorm:"column:ip_address"`
+	RequestId  string     `json:"request_id"           gorm:"column:request_id"`
+	CreatedAt  *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_event"
+}
+
+// Succeeded is a request answered without error, only then the entity may have changed
+func (event *AuditEvent) Succeeded() bool {
+	return event.StatusCode >= 200 && event.StatusCode < 400
+}
+
+/*
+AuditEntity tell how to snapshot the entity changed by the routes of a resource.
+
+	The entity id is the first IdFields found at the JSON body (a dot for a nested field) or the query
+*/
+type AuditEntity struct {
+	Type       string
+	Table      string // Should have the PrimaryKey and tenant_id columns
+	PrimaryKey string // Column, also the id field of the created entity at the response data
+	IdFields   []string
+}
+
+// AuditEventFilter of the audit log search, zero value fields are not filtered
+type AuditEventFilter struct {
+	UserId     int
+	EntityType string
+	EntityId   int
+	Method     string
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
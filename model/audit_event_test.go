@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEvent(t *testing.T) {
+	t.Run("Values", func(t *testing.T) {
+		marshaled, err := json.Marshal(&AuditEvent{Before: `{"id":3,"name":"Toko"}`})
+		require.Nil(t, err)
+
+		var event map[string]interface{}
+		require.Nil(t, json.Unmarshal(marshaled, &event))
+		assert.Equal(t, map[string]interface{}{"id": float64(3), "name": "Toko"}, event["before"])
+		assert.Nil(t, event["after"])
+	})
+
+	t.Run("Succeeded", func(t *testing.T) {
+		assert.True(t, (&AuditEvent{StatusCode: 200}).Succeeded())
+		assert.False(t, (&AuditEvent{StatusCode: 403}).Succeeded())
+		assert.False(t, (&AuditEvent{StatusCode: 500}).Succeeded())
+	})
+
+	assert.Equal(t, "audit_event", AuditEvent{}.TableName())
+}
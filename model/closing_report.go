@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+type ClosingReportType string
+
+const (
+	ClosingReportTypeX ClosingReportType = "X" // Business day so far, does not close anything
+	ClosingReportTypeZ ClosingReportType = "Z" // Close the business day, numbered per store
+)
+
+func (reportType ClosingReportType) IsValid() bool {
+	return reportType == ClosingReportTypeX || reportType == ClosingReportTypeZ
+}
+
+/*
+ClosingReport (append only, never updated)
+
+	Cover the sales of a store from PeriodStart (inclusive) to PeriodEnd (exclusive).
+	The period start at the end of the last Z-report, or at the start of the business day
+	for the first one. BusinessDate is PeriodEnd at the tenant timezone (YYYY-MM-DD).
+	Amounts are the same as SalesReport, sales are counted by the time they were COMPLETED
+	and voids (of a COMPLETED sale only) by the time they were voided,
+	refunds are store credit issued at the store
+*/
+type ClosingReport struct {
+	Id           int               `json:"id,omitempty"          gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId     int               `json:"tenant_id"             gorm:"column:tenant_id"`
+	StoreId      int               `json:"store_id"              gorm:"column:store_id"`
+	Type         ClosingReportType `json:"type"                  gorm:"column:type"`
+	ZNumber      *int              `json:"z_number"              gorm:"column:z_number"` // nil for X-report
+	BusinessDate string            `json:"business_date"         gorm:"column:business_date"`
+	Timezone     string            `json:"timezone"              gorm:"column:timezone"`
+	PeriodStart  time.Time         `json:"period_start"          gorm:"column:period_start"`
+	PeriodEnd    time.Time         `json:"period_end"            gorm:"column:period_end"`
+
+	TransactionCount int `json:"transaction_count" gorm:"column:transaction_count"`
+	TotalQuantity    int `json:"total_quantity"    gorm:"column:total_quantity"`
+	Subtotal         int `json:"subtotal"          gorm:"column:subtotal"`
+	DiscountAmount   int `json:"discount_amount"   gorm:"column:discount_amount"`
+	PointsDiscount   int `json:"points_discount"   gorm:"column:points_discount"` // Part of discount_amount paid by loyalty points
+	TotalAmount      int `json:"total_amount"      gorm:"column:total_amount"`
+	TaxAmount        int `json:"tax_amount"        gorm:"column:tax_amount"` // Included in TotalAmount
+	Profit           int `json:"profit"            gorm:"column:profit"`
+
+	// Payment method, CashAmount + GiftCardAmount = TotalAmount
+	CashAmount     int `json:"cash_amount"      gorm:"column:cash_amount"`
+	CashTendered   int `json:"cash_tendered"    gorm:"column:cash_tendered"` // purchased_price, include the change
+	GiftCardAmount int `json:"gift_card_amount" gorm:"column:gift_card_amount"`
+
+	VoidCount    int `json:"void_count"    gorm:"column:void_count"`
+	VoidAmount   int `json:"void_amount"   gorm:"column:void_amount"`
+	RefundCount  int `json:"refund_count"  gorm:"column:refund_count"`
+	RefundAmount int `json:"refund_amount" gorm:"column:refund_amount"`
+
+	FirstInvoiceId *int `json:"first_invoice_id" gorm:"column:first_invoice_id"` // nil when there is no sale
+	LastInvoiceId  *int `json:"last_invoice_id"  gorm:"column:last_invoice_id"`
+
+	UserId    int        `json:"user_id"              gorm:"column:user_id"` // Who printed the report
+	CreatedAt *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (ClosingReport) TableName() string {
+	return "closing_report"
+}
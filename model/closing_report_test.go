@@ -0,0 +1,19 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosingReport(t *testing.T) {
+	assert.True(t, ClosingReportTypeX.IsValid())
+	assert.True(t, ClosingReportTypeZ.IsValid())
+	assert.False(t, ClosingReportType("Y").IsValid())
+	assert.False(t, ClosingReportType("").IsValid())
+
+	report := ClosingReport{TenantId: 1, StoreId: 1, Type: ClosingReportTypeX, BusinessDate: "2026-01-31"}
+	assert.Equal(t, "closing_report", report.TableName())
+	assert.Nil(t, report.ZNumber)
+	assert.Nil(t, report.FirstInvoiceId)
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+/*
+Customer (per tenant directory)
+
+	Phone is stored normalized (digits with optional leading '+'),
+	the pair (tenant_id, phone) is unique at the DB.
+
+	order_item.customer_id reference this table, sales without customer
+	keep customer_id as NULL (anonymous sale)
+*/
+type Customer struct {
+	Id        int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	Name      string     `json:"name"                 gorm:"column:name"`
+	Phone     string     `json:"phone"                gorm:"column:phone"`
+	Email     string     `json:"email"                gorm:"column:email"`
+	Notes     string     `json:"notes"                gorm:"column:notes"`
+	TenantId  int        `json:"tenant_id"            gorm:"column:tenant_id"`
+	CreatedAt *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (Customer) TableName() string {
+	return "customer"
+}
+
+/*
+Aggregated purchase history of 1 customer,
+voided (soft deleted) invoices are not counted
+*/
+type CustomerStats struct {
+	LifetimeSpend int        `json:"lifetime_spend" gorm:"column:lifetime_spend"`
+	VisitCount    int        `json:"visit_count"    gorm:"column:visit_count"`
+	LastVisitAt   *time.Time `json:"last_visit_at"  gorm:"column:last_visit_at"`
+}
+
+/*
+Customer detail page, invoices are paginated
+*/
+type CustomerDetail struct {
+	Customer   *Customer      `json:"customer"`
+	Stats      *CustomerStats `json:"stats"`
+	Invoices   []*OrderItem   `json:"invoices"`
+	TotalCount int            `json:"total_count"` // count of all invoices
+}
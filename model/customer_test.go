@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomer(t *testing.T) {
+	now := time.Now()
+	customer := Customer{
+		Id:        1,
+		Name:      "Customer Model Test",
+		Phone:     "+6281234567890",
+		Email:     "customer@example.com",
+		Notes:     "Prefer less sugar",
+		TenantId:  1,
+		CreatedAt: &now,
+	}
+
+	assert.Equal(t, 1, customer.Id)
+	assert.Equal(t, "Customer Model Test", customer.Name)
+	assert.Equal(t, "+6281234567890", customer.Phone)
+	assert.Equal(t, "customer@example.com", customer.Email)
+	assert.Equal(t, "Prefer less sugar", customer.Notes)
+	assert.Equal(t, 1, customer.TenantId)
+	assert.Nil(t, customer.UpdatedAt)
+	assert.Equal(t, "customer", customer.TableName())
+	assert.WithinDuration(t, now, *customer.CreatedAt, time.Second)
+}
+
+func TestCustomerStats(t *testing.T) {
+	stats := CustomerStats{LifetimeSpend: 150000, VisitCount: 3}
+
+	assert.Equal(t, 150000, stats.LifetimeSpend)
+	assert.Equal(t, 3, stats.VisitCount)
+	assert.Nil(t, stats.LastVisitAt)
+}
@@ -0,0 +1,99 @@
+package model
+
+import "time"
+
+type GiftCardType string
+
+const (
+	GiftCardTypeGiftCard    GiftCardType = "GIFT_CARD"
+	GiftCardTypeStoreCredit GiftCardType = "STORE_CREDIT"
+)
+
+type GiftCardStatus string
+
+const (
+	GiftCardStatusInactive GiftCardStatus = "INACTIVE" // issued, not sold yet
+	GiftCardStatusActive   GiftCardStatus = "ACTIVE"
+)
+
+/*
+GiftCard (gift card and store credit share the same table)
+
+	Code is stored normalized (upper case, without separator),
+	the pair (tenant_id, code) is unique at the DB.
+
+	Gift card is issued INACTIVE, the balance is 0 until it's
+	activated (sold) at a store. Store credit is issued ACTIVE
+	from a refund and may belong to a customer.
+
+	Balance is the outstanding liability, every change of it
+	is recorded at gift_card_movement.
+*/
+type GiftCard struct {
+	Id               int            `json:"id,omitempty"          gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId         int            `json:"tenant_id"             gorm:"column:tenant_id"`
+	Code             string         `json:"code"                  gorm:"column:code"`
+	Type             GiftCardType   `json:"type"                  gorm:"column:type"`
+	Status           GiftCardStatus `json:"status"                gorm:"column:status"`
+	InitialBalance   int            `json:"initial_balance"       gorm:"column:initial_balance"`
+	Balance          int            `json:"balance"               gorm:"column:balance"`
+	CustomerId       *int           `json:"customer_id"           gorm:"column:customer_id"`
+	ActivatedStoreId *int           `json:"activated_store_id"    gorm:"column:activated_store_id"`
+	ActivatedAt      *time.Time     `json:"activated_at"          gorm:"column:activated_at"`
+	CreatedAt        *time.Time     `json:"created_at,omitempty"  gorm:"column:created_at;<-:create"`
+	UpdatedAt        *time.Time     `json:"updated_at,omitempty"  gorm:"column:updated_at"`
+}
+
+func (GiftCard) TableName() string {
+	return "gift_card"
+}
+
+type GiftCardMovementType string
+
+const (
+	GiftCardMovementActivate GiftCardMovementType = "ACTIVATE" // gift card sold
+	GiftCardMovementIssue    GiftCardMovementType = "ISSUE"    // store credit from refund
+	GiftCardMovementRedeem   GiftCardMovementType = "REDEEM"   // used as payment
+	GiftCardMovementReversal GiftCardMovementType = "REVERSAL" // invoice voided
+)
+
+/*
+GiftCardMovement (append only)
+
+	Amount is signed, BalanceAfter is the card balance after this movement
+*/
+type GiftCardMovement struct {
+	Id           int                  `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId     int                  `json:"tenant_id"            gorm:"column:tenant_id"`
+	GiftCardId   int                  `json:"gift_card_id"         gorm:"column:gift_card_id"`
+	Type         GiftCardMovementType `json:"type"                 gorm:"column:type"`
+	Amount       int                  `json:"amount"               gorm:"column:amount"`
+	BalanceAfter int                  `json:"balance_after"        gorm:"column:balance_after"`
+	OrderItemId  *int                 `json:"order_item_id"        gorm:"column:order_item_id"`
+	StoreId      *int                 `json:"store_id"             gorm:"column:store_id"`
+	UserId       int                  `json:"user_id"              gorm:"column:user_id"`
+	Note         string               `json:"note"                 gorm:"column:note"`
+	CreatedAt    *time.Time           `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (GiftCardMovement) TableName() string {
+	return "gift_card_movement"
+}
+
+/*
+Outstanding liability per card type, only ACTIVE card is counted
+*/
+type GiftCardLiability struct {
+	Type               GiftCardType `json:"type"                gorm:"column:type"`
+	CardCount          int          `json:"card_count"          gorm:"column:card_count"`
+	OutstandingBalance int          `json:"outstanding_balance" gorm:"column:outstanding_balance"`
+}
+
+/*
+Movements of 1 card, movements are paginated
+*/
+type GiftCardStatement struct {
+	GiftCard   *GiftCard           `json:"gift_card"`
+	Movements  []*GiftCardMovement `json:"movements"`
+	TotalCount int                 `json:"total_count"` // count of all movements
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGiftCard(t *testing.T) {
+	now := time.Now()
+	storeId := 1
+	giftCard := GiftCard{
+		Id:               1,
+		TenantId:         1,
+		Code:             "ABCD2345EFGH6789",
+		Type:             GiftCardTypeGiftCard,
+		Status:           GiftCardStatusActive,
+		InitialBalance:   100_000,
+		Balance:          60_000,
+		ActivatedStoreId: &storeId,
+		ActivatedAt:      &now,
+	}
+
+	assert.Equal(t, "ABCD2345EFGH6789", giftCard.Code)
+	assert.Equal(t, GiftCardStatusActive, giftCard.Status)
+	assert.Equal(t, 60_000, giftCard.Balance)
+	assert.Nil(t, giftCard.CustomerId)
+	assert.Equal(t, "gift_card", giftCard.TableName())
+}
+
+func TestGiftCardMovement(t *testing.T) {
+	orderItemId := 1
+	movement := GiftCardMovement{
+		Id:           1,
+		TenantId:     1,
+		GiftCardId:   1,
+		Type:         GiftCardMovementRedeem,
+		Amount:       -40_000,
+		BalanceAfter: 60_000,
+		OrderItemId:  &orderItemId,
+		UserId:       1,
+	}
+
+	assert.Equal(t, GiftCardMovementRedeem, movement.Type)
+	assert.Equal(t, -40_000, movement.Amount)
+	assert.Equal(t, 60_000, movement.BalanceAfter)
+	assert.Equal(t, "gift_card_movement", movement.TableName())
+}
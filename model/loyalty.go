@@ -0,0 +1,114 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+type LoyaltyRounding string
+
+const (
+	LoyaltyRoundingFloor LoyaltyRounding = "FLOOR"
+	LoyaltyRoundingRound LoyaltyRounding = "ROUND"
+	LoyaltyRoundingCeil  LoyaltyRounding = "CEIL"
+)
+
+/*
+LoyaltySetting (1 row per tenant)
+
+	EarnPoints is given for every EarnAmount spent,
+	example EarnAmount = 10_000, EarnPoints = 1 -> spend 25_000 get 2.5 points,
+	then Rounding decide 2 (FLOOR), 3 (ROUND) or 3 (CEIL)
+
+	RedeemValue is the discount value of 1 point
+
+	ExpiryMonths = 0 means points never expire
+*/
+type LoyaltySetting struct {
+	Id           int             `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId     int             `json:"tenant_id"            gorm:"column:tenant_id"`
+	EarnAmount   int             `json:"earn_amount"          gorm:"column:earn_amount"`
+	EarnPoints   int             `json:"earn_points"          gorm:"column:earn_points"`
+	Rounding     LoyaltyRounding `json:"rounding"             gorm:"column:rounding"`
+	RedeemValue  int             `json:"redeem_value"         gorm:"column:redeem_value"`
+	ExpiryMonths int             `json:"expiry_months"        gorm:"column:expiry_months"`
+	IsActive     bool            `json:"is_active"            gorm:"column:is_active"`
+	CreatedAt    *time.Time      `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+	UpdatedAt    *time.Time      `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (LoyaltySetting) TableName() string {
+	return "loyalty_setting"
+}
+
+// PointsFor return the earned points from the spent amount
+func (setting *LoyaltySetting) PointsFor(amount int) int {
+	if setting.EarnAmount <= 0 || setting.EarnPoints <= 0 || amount <= 0 {
+		return 0
+	}
+
+	raw := float64(amount) * float64(setting.EarnPoints) / float64(setting.EarnAmount)
+	switch setting.Rounding {
+	case LoyaltyRoundingCeil:
+		return int(math.Ceil(raw))
+	case LoyaltyRoundingRound:
+		return int(math.Round(raw))
+	default:
+		return int(math.Floor(raw))
+	}
+}
+
+// ExpiresAt return nil when points never expire
+func (setting *LoyaltySetting) ExpiresAt(from time.Time) *time.Time {
+	if setting.ExpiryMonths <= 0 {
+		return nil
+	}
+
+	expiresAt := from.AddDate(0, setting.ExpiryMonths, 0)
+	return &expiresAt
+}
+
+type LoyaltyLedgerType string
+
+const (
+	LoyaltyLedgerEarn     LoyaltyLedgerType = "EARN"
+	LoyaltyLedgerRedeem   LoyaltyLedgerType = "REDEEM"
+	LoyaltyLedgerExpire   LoyaltyLedgerType = "EXPIRE"
+	LoyaltyLedgerReversal LoyaltyLedgerType = "REVERSAL"
+)
+
+/*
+LoyaltyLedger (append only)
+
+	Points is signed, credit (+) or debit (-).
+
+	Credit row keep RemainingPoints, debit consume it from the
+	credit that expire first (FIFO). The available balance is
+	the sum of RemainingPoints that is not expired yet, so the
+	balance is always correct even before the expiry job run.
+*/
+type LoyaltyLedger struct {
+	Id              int               `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId        int               `json:"tenant_id"            gorm:"column:tenant_id"`
+	CustomerId      int               `json:"customer_id"          gorm:"column:customer_id"`
+	OrderItemId     *int              `json:"order_item_id"        gorm:"column:order_item_id"`
+	Type            LoyaltyLedgerType `json:"type"                 gorm:"column:type"`
+	Points          int               `json:"points"               gorm:"column:points"`
+	RemainingPoints int               `json:"remaining_points"     gorm:"column:remaining_points"`
+	ExpiresAt       *time.Time        `json:"expires_at"           gorm:"column:expires_at"`
+	CreatedAt       *time.Time        `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (LoyaltyLedger) TableName() string {
+	return "loyalty_ledger"
+}
+
+/*
+Points ledger of 1 customer, entries are paginated
+*/
+type LoyaltyStatement struct {
+	CustomerId int              `json:"customer_id"`
+	Balance    int              `json:"balance"`
+	Entries    []*LoyaltyLedger `json:"entries"`
+	TotalCount int              `json:"total_count"` // count of all entries
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoyaltySetting(t *testing.T) {
+	t.Run("PointsFor", func(t *testing.T) {
+		setting := &LoyaltySetting{EarnAmount: 10_000, EarnPoints: 1}
+
+		// 25_000 spent = 2.5 points
+		setting.Rounding = LoyaltyRoundingFloor
+		assert.Equal(t, 2, setting.PointsFor(25_000))
+
+		setting.Rounding = LoyaltyRoundingRound
+		assert.Equal(t, 3, setting.PointsFor(25_000))
+		assert.Equal(t, 2, setting.PointsFor(24_000))
+
+		setting.Rounding = LoyaltyRoundingCeil
+		assert.Equal(t, 3, setting.PointsFor(20_001))
+
+		// Unknown rounding fall back to FLOOR
+		setting.Rounding = ""
+		assert.Equal(t, 2, setting.PointsFor(29_999))
+
+		// Nothing earned
+		assert.Equal(t, 0, setting.PointsFor(0))
+		assert.Equal(t, 0, (&LoyaltySetting{EarnAmount: 0, EarnPoints: 1}).PointsFor(10_000))
+	})
+
+	t.Run("ExpiresAt", func(t *testing.T) {
+		now := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
+
+		assert.Nil(t, (&LoyaltySetting{ExpiryMonths: 0}).ExpiresAt(now))
+
+		expiresAt := (&LoyaltySetting{ExpiryMonths: 12}).ExpiresAt(now)
+		assert.NotNil(t, expiresAt)
+		assert.Equal(t, 2026, expiresAt.Year())
+	})
+
+	assert.Equal(t, "loyalty_setting", LoyaltySetting{}.TableName())
+}
+
+func TestLoyaltyLedger(t *testing.T) {
+	now := time.Now()
+	orderItemId := 1
+	ledger := LoyaltyLedger{
+		Id:              1,
+		TenantId:        1,
+		CustomerId:      1,
+		OrderItemId:     &orderItemId,
+		Type:            LoyaltyLedgerEarn,
+		Points:          10,
+		RemainingPoints: 10,
+		CreatedAt:       &now,
+	}
+
+	assert.Equal(t, 1, ledger.Id)
+	assert.Equal(t, LoyaltyLedgerEarn, ledger.Type)
+	assert.Equal(t, 10, ledger.Points)
+	assert.Equal(t, 10, ledger.RemainingPoints)
+	assert.Equal(t, 1, *ledger.OrderItemId)
+	assert.Nil(t, ledger.ExpiresAt)
+	assert.Equal(t, "loyalty_ledger", ledger.TableName())
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+/*
+MarginAlertSetting (1 row per tenant)
+
+	An item is flagged when its realised margin of the last WindowDays
+	is below MinMarginPercent, example 10 flag every item earning less than 10% of the revenue.
+	0 only flag item sold at a loss, negative value allow some loss
+
+	IsActive only enable the daily alert email to Recipients,
+	the margin alert endpoints always work
+*/
+type MarginAlertSetting struct {
+	Id               int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId         int        `json:"tenant_id"            gorm:"column:tenant_id"`
+	MinMarginPercent float64    `json:"min_margin_percent"   gorm:"column:min_margin_percent"`
+	WindowDays       int        `json:"window_days"          gorm:"column:window_days"`
+	IsActive         bool       `json:"is_active"            gorm:"column:is_active"`
+	Recipients       string     `json:"recipients"           gorm:"column:recipients"` // Separated by comma
+	LastAlertedAt    *time.Time `json:"last_alerted_at"      gorm:"column:last_alerted_at;<-:update"`
+	CreatedAt        *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (MarginAlertSetting) TableName() string {
+	return "margin_alert_setting"
+}
+
+// Default when the tenant never configured it
+const (
+	MarginAlertDefaultMinMarginPercent = 0.0
+	MarginAlertDefaultWindowDays       = 30
+)
+
+func (setting *MarginAlertSetting) Validate() error {
+	if setting.MinMarginPercent < -100 || setting.MinMarginPercent >= 100 {
+		return fmt.Errorf("Min margin percent should be between -100 and 100 (exclusive). Given %v", setting.MinMarginPercent)
+	}
+	if setting.WindowDays < 1 || setting.WindowDays > 365 {
+		return fmt.Errorf("Window days should be between 1 and 365. Given %d", setting.WindowDays)
+	}
+
+	recipients := setting.RecipientList()
+	if setting.IsActive && len(recipients) == 0 {
+		return errors.New("At least 1 recipient is Required to activate the alert !")
+	}
+	return validateRecipients(recipients)
+}
+
+// RecipientList split Recipients, empty entries are ignored
+func (setting *MarginAlertSetting) RecipientList() []string {
+	return splitRecipients(setting.Recipients)
+}
+
+// Since is the start of the window ending at now
+func (setting *MarginAlertSetting) Since(now time.Time) time.Time {
+	return now.AddDate(0, 0, -setting.WindowDays)
+}
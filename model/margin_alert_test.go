@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarginAlertSetting(t *testing.T) {
+	newSetting := func() *MarginAlertSetting {
+		return &MarginAlertSetting{
+			MinMarginPercent: 10,
+			WindowDays:       30,
+			IsActive:         true,
+			Recipients:       "owner@example.com, ,finance@example.com",
+		}
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, newSetting().Validate())
+		assert.Equal(t, []string{"owner@example.com", "finance@example.com"}, newSetting().RecipientList())
+
+		// Inactive does not need recipient
+		inactive := newSetting()
+		inactive.IsActive, inactive.Recipients = false, ""
+		assert.NoError(t, inactive.Validate())
+
+		invalids := []func(setting *MarginAlertSetting){
+			func(setting *MarginAlertSetting) { setting.MinMarginPercent = 100 },
+			func(setting *MarginAlertSetting) { setting.MinMarginPercent = -101 },
+			func(setting *MarginAlertSetting) { setting.WindowDays = 0 },
+			func(setting *MarginAlertSetting) { setting.WindowDays = 366 },
+			func(setting *MarginAlertSetting) { setting.Recipients = "" },
+			func(setting *MarginAlertSetting) { setting.Recipients = "not an email" },
+		}
+		for i, invalid := range invalids {
+			setting := newSetting()
+			invalid(setting)
+			assert.Error(t, setting.Validate(), i)
+		}
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		now := time.Date(2026, 3, 31, 10, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), newSetting().Since(now))
+	})
+
+	assert.Equal(t, "margin_alert_setting", MarginAlertSetting{}.TableName())
+}
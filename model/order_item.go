@@ -16,7 +16,14 @@ type OrderItem struct {
 	Subtotal       int            `json:"subtotal" gorm:"column:subtotal"`
 	StoreId        int            `json:"store_id" gorm:"column:store_id"`
 	TenantId       int            `json:"tenant_id" gorm:"column:tenant_id"`
-	DeletedAt      gorm.DeletedAt `json:"-"` // Soft delete
+	CustomerId     *int           `json:"customer_id" gorm:"column:customer_id"`             // nil means anonymous sale
+	PointsDiscount int            `json:"points_discount" gorm:"column:points_discount"`     // Part of discount_amount paid by loyalty points
+	GiftCardAmount int            `json:"gift_card_amount" gorm:"column:gift_card_amount"`   // Part of total_amount paid by gift card / store credit
+	Status         OrderStatus    `json:"status" gorm:"column:status;default:COMPLETED"`     // Placed order is paid when COMPLETED
+	CompletedAt    *time.Time     `json:"completed_at" gorm:"column:completed_at;<-:update"` // Time of the sale, reports are bucketed by it
+	UserId         int            `json:"user_id" gorm:"column:user_id;<-:false"`            // Cashier, written by transactions()
+	VoidedByUserId *int           `json:"-" gorm:"column:voided_by_user_id;<-:update"`       // Who soft deleted the invoice
+	DeletedAt      gorm.DeletedAt `json:"-"`                                                 // Soft delete
 }
 
 func (orderItem *OrderItem) TableName() string {
@@ -24,16 +31,20 @@ func (orderItem *OrderItem) TableName() string {
 }
 
 type OrderItemWithStore struct {
-	Id             int       `json:"id"`
-	PurchasedPrice int       `json:"purchased_price"`
-	CreatedAt      time.Time `json:"created_at"`
-	TotalQuantity  int       `json:"total_quantity"`
-	TotalAmount    int       `json:"total_amount"`
-	DiscountAmount int       `json:"discount_amount"`
-	Subtotal       int       `json:"subtotal"`
-	StoreId        int       `json:"store_id"`
-	TenantId       int       `json:"tenant_id"`
-	StoreName      string    `json:"store_name"` // Joined field
+	Id             int         `json:"id"`
+	PurchasedPrice int         `json:"purchased_price"`
+	CreatedAt      time.Time   `json:"created_at"`
+	TotalQuantity  int         `json:"total_quantity"`
+	TotalAmount    int         `json:"total_amount"`
+	DiscountAmount int         `json:"discount_amount"`
+	Subtotal       int         `json:"subtotal"`
+	StoreId        int         `json:"store_id"`
+	TenantId       int         `json:"tenant_id"`
+	CustomerId     *int        `json:"customer_id"`
+	PointsDiscount int         `json:"points_discount"`
+	GiftCardAmount int         `json:"gift_card_amount"`
+	Status         OrderStatus `json:"status"`
+	StoreName      string      `json:"store_name"` // Joined field
 }
 
 /*
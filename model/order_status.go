@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+type OrderStatus string
+
+const (
+	OrderStatusPlaced        OrderStatus = "PLACED"
+	OrderStatusInPreparation OrderStatus = "IN_PREPARATION"
+	OrderStatusReady         OrderStatus = "READY"
+	OrderStatusCompleted     OrderStatus = "COMPLETED"
+	OrderStatusCancelled     OrderStatus = "CANCELLED"
+)
+
+/*
+Allowed next status of an order
+
+	PLACED -> IN_PREPARATION -> READY -> COMPLETED
+	Every open status could be CANCELLED, COMPLETED and CANCELLED are final.
+	Sale from transactions (pay first) is COMPLETED right away
+*/
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPlaced:        {OrderStatusInPreparation, OrderStatusCancelled},
+	OrderStatusInPreparation: {OrderStatusReady, OrderStatusCancelled},
+	OrderStatusReady:         {OrderStatusCompleted, OrderStatusCancelled},
+}
+
+func (status OrderStatus) IsValid() bool {
+	switch status {
+	case OrderStatusPlaced, OrderStatusInPreparation, OrderStatusReady, OrderStatusCompleted, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Open order is still waiting to be handed over to the customer
+func (status OrderStatus) IsOpen() bool {
+	_, ok := orderStatusTransitions[status]
+	return ok
+}
+
+func (status OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Every open status, used to list open orders of a store
+func OpenOrderStatuses() []OrderStatus {
+	return []OrderStatus{OrderStatusPlaced, OrderStatusInPreparation, OrderStatusReady}
+}
+
+/*
+One row per status change of an order, FromStatus is empty when the order is placed
+*/
+type OrderStatusHistory struct {
+	Id          int         `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId    int         `json:"tenant_id"            gorm:"column:tenant_id"`
+	OrderItemId int         `json:"order_item_id"        gorm:"column:order_item_id"`
+	FromStatus  OrderStatus `json:"from_status"          gorm:"column:from_status"`
+	ToStatus    OrderStatus `json:"to_status"            gorm:"column:to_status"`
+	UserId      int         `json:"user_id"              gorm:"column:user_id"`
+	CreatedAt   *time.Time  `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
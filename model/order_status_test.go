@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatus(t *testing.T) {
+	t.Run("Transitions", func(t *testing.T) {
+		assert.True(t, OrderStatusPlaced.CanTransitionTo(OrderStatusInPreparation))
+		assert.True(t, OrderStatusInPreparation.CanTransitionTo(OrderStatusReady))
+		assert.True(t, OrderStatusReady.CanTransitionTo(OrderStatusCompleted))
+		assert.True(t, OrderStatusReady.CanTransitionTo(OrderStatusCancelled))
+
+		assert.False(t, OrderStatusPlaced.CanTransitionTo(OrderStatusCompleted))
+		assert.False(t, OrderStatusReady.CanTransitionTo(OrderStatusPlaced))
+		assert.False(t, OrderStatusCompleted.CanTransitionTo(OrderStatusCancelled))
+		assert.False(t, OrderStatusCancelled.CanTransitionTo(OrderStatusPlaced))
+	})
+
+	t.Run("IsOpen", func(t *testing.T) {
+		for _, status := range OpenOrderStatuses() {
+			assert.True(t, status.IsOpen())
+		}
+		assert.False(t, OrderStatusCompleted.IsOpen())
+		assert.False(t, OrderStatusCancelled.IsOpen())
+	})
+
+	t.Run("IsValid", func(t *testing.T) {
+		assert.True(t, OrderStatusInPreparation.IsValid())
+		assert.False(t, OrderStatus("SERVED").IsValid())
+		assert.False(t, OrderStatus("").IsValid())
+	})
+
+	history := OrderStatusHistory{OrderItemId: 1, ToStatus: OrderStatusPlaced, UserId: 1}
+	assert.Equal(t, "order_status_history", history.TableName())
+	assert.Empty(t, history.FromStatus)
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+type ParkedOrderStatus string
+
+const (
+	ParkedOrderStatusParked    ParkedOrderStatus = "PARKED"
+	ParkedOrderStatusConverted ParkedOrderStatus = "CONVERTED"
+	ParkedOrderStatusCancelled ParkedOrderStatus = "CANCELLED"
+	ParkedOrderStatusExpired   ParkedOrderStatus = "EXPIRED"
+)
+
+/*
+ParkedOrder (draft basket of a store)
+
+	Any till of the same store could resume it. Version is increased
+	on every edit, an edit / conversion with an old version is rejected
+	so 2 tills never overwrite each other.
+
+	Only PARKED could be edited, CONVERTED keep the created order_item id
+*/
+type ParkedOrder struct {
+	Id             int                `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId       int                `json:"tenant_id"            gorm:"column:tenant_id"`
+	StoreId        int                `json:"store_id"             gorm:"column:store_id"`
+	CustomerId     *int               `json:"customer_id"          gorm:"column:customer_id"`
+	Notes          string             `json:"notes"                gorm:"column:notes"`
+	Status         ParkedOrderStatus  `json:"status"               gorm:"column:status"`
+	Version        int                `json:"version"              gorm:"column:version"`
+	ParkedByUserId int                `json:"parked_by_user_id"    gorm:"column:parked_by_user_id"`
+	OrderItemId    *int               `json:"order_item_id"        gorm:"column:order_item_id"`
+	ExpiresAt      time.Time          `json:"expires_at"           gorm:"column:expires_at"`
+	CreatedAt      *time.Time         `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+	UpdatedAt      *time.Time         `json:"updated_at,omitempty" gorm:"column:updated_at"`
+	Lines          []*ParkedOrderLine `json:"lines"                gorm:"foreignKey:ParkedOrderId"`
+}
+
+func (ParkedOrder) TableName() string {
+	return "parked_order"
+}
+
+/*
+Line of parked order, price and name are snapshot when parked,
+DiscountAmount is per unit and TaxAmount is of the whole line (same as purchased_item_list)
+*/
+type ParkedOrderLine struct {
+	Id                 int    `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	ParkedOrderId      int    `json:"parked_order_id"      gorm:"column:parked_order_id"`
+	ItemId             int    `json:"item_id"              gorm:"column:item_id"`
+	Quantity           int    `json:"quantity"             gorm:"column:quantity"`
+	StorePriceSnapshot int    `json:"store_price_snapshot" gorm:"column:store_price_snapshot"`
+	DiscountAmount     int    `json:"discount_amount"      gorm:"column:discount_amount"`
+	TaxAmount          int    `json:"tax_amount"           gorm:"column:tax_amount"` // Included in the line total
+	ItemNameSnapshot   string `json:"item_name_snapshot"   gorm:"column:item_name_snapshot"`
+}
+
+func (ParkedOrderLine) TableName() string {
+	return "parked_order_line"
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParkedOrder(t *testing.T) {
+	parkedOrder := ParkedOrder{
+		Id:             1,
+		TenantId:       1,
+		StoreId:        1,
+		Notes:          "Customer fetch wallet",
+		Status:         ParkedOrderStatusParked,
+		Version:        1,
+		ParkedByUserId: 1,
+		ExpiresAt:      time.Now().Add(time.Hour),
+		Lines: []*ParkedOrderLine{
+			{ItemId: 1, Quantity: 2, StorePriceSnapshot: 10_000, DiscountAmount: 500, ItemNameSnapshot: "Item"},
+		},
+	}
+
+	assert.Equal(t, ParkedOrderStatusParked, parkedOrder.Status)
+	assert.Nil(t, parkedOrder.CustomerId)
+	assert.Nil(t, parkedOrder.OrderItemId)
+	assert.Len(t, parkedOrder.Lines, 1)
+	assert.Equal(t, "parked_order", parkedOrder.TableName())
+	assert.Equal(t, "parked_order_line", parkedOrder.Lines[0].TableName())
+}
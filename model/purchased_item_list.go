@@ -13,6 +13,7 @@ type PurchasedItem struct {
 	BasePriceSnapshot  int        `json:"base_price_snapshot" gorm:"column:base_price_snapshot"`
 	DiscountAmount     int        `json:"discount_amount" gorm:"column:discount_amount"`
 	TotalAmount        int        `json:"total_amount" gorm:"column:total_amount"`
+	TaxAmount          int        `json:"tax_amount" gorm:"column:tax_amount"` // Tax of the line, included in total_amount
 	CreatedAt          *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
 	ItemId             int        `json:"item_id" gorm:"column:item_id"`
 	ItemNameSnapshot   string     `json:"item_name_snapshot" gorm:"column:item_name_snapshot"`
@@ -0,0 +1,235 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type ReportScheduleFrequency string
+
+const (
+	ReportScheduleDaily   ReportScheduleFrequency = "DAILY"   // Period is yesterday
+	ReportScheduleWeekly  ReportScheduleFrequency = "WEEKLY"  // Period is the last 7 days
+	ReportScheduleMonthly ReportScheduleFrequency = "MONTHLY" // Period is the last calendar month
+)
+
+type ReportScheduleChannel string
+
+const (
+	ReportScheduleEmail   ReportScheduleChannel = "EMAIL"
+	ReportScheduleWebhook ReportScheduleChannel = "WEBHOOK"
+)
+
+/*
+ReportSchedule send a report of the tenant by email or webhook at a local time
+
+	Resource and Format are the same as the export (e.g. sales_report, profit / csv, xlsx, pdf).
+	TimeOfDay (HH:MM), DayOfWeek and DayOfMonth are at the tenant timezone.
+	NextRunAt is computed by the repository on create / edit and after each run,
+	a run missed while the API was down is sent once, not once per missed period
+*/
+type ReportSchedule struct {
+	Id              int                     `json:"id,omitempty"           gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId        int                     `json:"tenant_id"              gorm:"column:tenant_id"`
+	StoreId         int                     `json:"store_id"               gorm:"column:store_id"` // 0 means all store
+	Name            string                  `json:"name"                   gorm:"column:name"`
+	Resource        string                  `json:"resource"               gorm:"column:resource"`
+	Format          string                  `json:"format"                 gorm:"column:format"`
+	Frequency       ReportScheduleFrequency `json:"frequency"              gorm:"column:frequency"`
+	TimeOfDay       string                  `json:"time_of_day"            gorm:"column:time_of_day"`
+	DayOfWeek       int                     `json:"day_of_week"            gorm:"column:day_of_week"`  // WEEKLY only, 0 is Sunday
+	DayOfMonth      int                     `json:"day_of_month"           gorm:"column:day_of_month"` // MONTHLY only, 1 - 28
+	Channel         ReportScheduleChannel   `json:"channel"                gorm:"column:channel"`
+	Recipients      string                  `json:"recipients"             gorm:"column:recipients"` // EMAIL only, separated by comma
+	WebhookUrl      string                  `json:"webhook_url"            gorm:"column:webhook_url"`
+	WebhookSecret   *string                 `json:"-"                      gorm:"column:webhook_secret"` // Write only, sign the webhook body. nil keep the current one on edit
+	IsActive        bool                    `json:"is_active"              gorm:"column:is_active"`
+	NextRunAt       *time.Time              `json:"next_run_at"            gorm:"column:next_run_at"`
+	LastRunAt       *time.Time              `json:"last_run_at"            gorm:"column:last_run_at"`
+	CreatedByUserId int                     `json:"created_by_user_id"     gorm:"column:created_by_user_id;<-:create"`
+	CreatedAt       *time.Time              `json:"created_at,omitempty"   gorm:"column:created_at;<-:create"`
+	UpdatedAt       *time.Time              `json:"updated_at,omitempty"   gorm:"column:updated_at"`
+}
+
+func (ReportSchedule) TableName() string {
+	return "report_schedule"
+}
+
+// Validate the fields owned by the schedule, resource and format are checked by the export
+func (schedule *ReportSchedule) Validate() error {
+	if strings.TrimSpace(schedule.Name) == "" {
+		return errors.New("Schedule name is Required !")
+	}
+	if _, _, err := schedule.clock(); err != nil {
+		return err
+	}
+
+	switch schedule.Frequency {
+	case ReportScheduleDaily:
+	case ReportScheduleWeekly:
+		if schedule.DayOfWeek < 0 || schedule.DayOfWeek > 6 {
+			return fmt.Errorf("Invalid day_of_week: %d. Allowed: 0 (Sunday) - 6 (Saturday)", schedule.DayOfWeek)
+		}
+	case ReportScheduleMonthly:
+		// Up to 28 so every month has the day
+		if schedule.DayOfMonth < 1 || schedule.DayOfMonth > 28 {
+			return fmt.Errorf("Invalid day_of_month: %d. Allowed: 1 - 28", schedule.DayOfMonth)
+		}
+	default:
+		return fmt.Errorf("Invalid frequency: %s. Allowed: DAILY, WEEKLY, MONTHLY", schedule.Frequency)
+	}
+
+	switch schedule.Channel {
+	case ReportScheduleEmail:
+		recipients := schedule.RecipientList()
+		if len(recipients) == 0 {
+			return errors.New("At least 1 recipient is Required for EMAIL channel !")
+		}
+		if err := validateRecipients(recipients); err != nil {
+			return err
+		}
+	case ReportScheduleWebhook:
+		webhookUrl, err := url.Parse(schedule.WebhookUrl)
+		if err != nil || (webhookUrl.Scheme != "https" && webhookUrl.Scheme != "http") || webhookUrl.Host == "" {
+			return fmt.Errorf("Invalid webhook_url: %s", schedule.WebhookUrl)
+		}
+	default:
+		return fmt.Errorf("Invalid channel: %s. Allowed: EMAIL, WEBHOOK", schedule.Channel)
+	}
+
+	return nil
+}
+
+// Secret sign the webhook body, "" means not signed
+func (schedule *ReportSchedule) Secret() string {
+	if schedule.WebhookSecret == nil {
+		return ""
+	}
+	return *schedule.WebhookSecret
+}
+
+// RecipientList split Recipients, empty entries are ignored
+func (schedule *ReportSchedule) RecipientList() []string {
+	return splitRecipients(schedule.Recipients)
+}
+
+// splitRecipients split emails separated by comma, empty entries are ignored
+func splitRecipients(value string) []string {
+	recipients := make([]string, 0)
+	for _, recipient := range strings.Split(value, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+func validateRecipients(recipients []string) error {
+	for _, recipient := range recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("Invalid recipient email: %s", recipient)
+		}
+	}
+	return nil
+}
+
+func (schedule *ReportSchedule) clock() (hour, minute int, err error) {
+	clock, err := time.Parse("15:04", schedule.TimeOfDay)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid time_of_day: %s. Expected HH:MM (24 hours)", schedule.TimeOfDay)
+	}
+	return clock.Hour(), clock.Minute(), nil
+}
+
+/*
+NextRun is the first run strictly after "after", at the local time of location.
+Schedule must be valid
+*/
+func (schedule *ReportSchedule) NextRun(after time.Time, location *time.Location) time.Time {
+	hour, minute, _ := schedule.clock()
+	local := after.In(location)
+
+	switch schedule.Frequency {
+	case ReportScheduleWeekly:
+		next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, location)
+		for next.Weekday() != time.Weekday(schedule.DayOfWeek) || !next.After(after) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, hour, minute, 0, 0, location)
+		}
+		return next
+
+	case ReportScheduleMonthly:
+		next := time.Date(local.Year(), local.Month(), schedule.DayOfMonth, hour, minute, 0, 0, location)
+		if !next.After(after) {
+			next = time.Date(local.Year(), local.Month()+1, schedule.DayOfMonth, hour, minute, 0, 0, location)
+		}
+		return next
+	}
+
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, location)
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, location)
+	}
+	return next
+}
+
+/*
+Period of the report sent at scheduledAt: [start, end), end is the start of that local day.
+Daily 07:00 on the 2nd report the whole 1st, not 07:00 to 07:00
+*/
+func (schedule *ReportSchedule) Period(scheduledAt time.Time, location *time.Location) (start, end time.Time) {
+	local := scheduledAt.In(location)
+	end = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+
+	switch schedule.Frequency {
+	case ReportScheduleWeekly:
+		return end.AddDate(0, 0, -7), end
+	case ReportScheduleMonthly:
+		end = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, location)
+		return end.AddDate(0, -1, 0), end
+	}
+	return end.AddDate(0, 0, -1), end
+}
+
+type ReportScheduleRunStatus string
+
+const (
+	ReportScheduleRunRunning  ReportScheduleRunStatus = "RUNNING"
+	ReportScheduleRunSuccess  ReportScheduleRunStatus = "SUCCESS"
+	ReportScheduleRunRetrying ReportScheduleRunStatus = "RETRYING"
+	ReportScheduleRunFailed   ReportScheduleRunStatus = "FAILED" // Gave up after ReportScheduleMaxAttempts
+)
+
+// A run is tried at most this many times, including the first one
+const ReportScheduleMaxAttempts = 3
+
+/*
+ReportScheduleRun is the history of 1 scheduled delivery
+
+	NextAttemptAt is when a RETRYING run is tried again. While RUNNING it is a lease,
+	a run still RUNNING after it (API crashed in the middle) is claimed again
+*/
+type ReportScheduleRun struct {
+	Id            int                     `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	ScheduleId    int                     `json:"schedule_id"          gorm:"column:schedule_id"`
+	TenantId      int                     `json:"tenant_id"            gorm:"column:tenant_id"`
+	Status        ReportScheduleRunStatus `json:"status"               gorm:"column:status"`
+	Attempts      int                     `json:"attempts"             gorm:"column:attempts"`
+	ScheduledAt   time.Time               `json:"scheduled_at"         gorm:"column:scheduled_at"`
+	PeriodStart   time.Time               `json:"period_start"         gorm:"column:period_start"`
+	PeriodEnd     time.Time               `json:"period_end"           gorm:"column:period_end"`
+	Timezone      string                  `json:"timezone"             gorm:"column:timezone"` // Of the tenant when the run was created
+	NextAttemptAt *time.Time              `json:"next_attempt_at"      gorm:"column:next_attempt_at"`
+	LastError     string                  `json:"last_error"           gorm:"column:last_error"`
+	FileName      string                  `json:"file_name"            gorm:"column:file_name"`
+	FinishedAt    *time.Time              `json:"finished_at"          gorm:"column:finished_at"`
+	CreatedAt     *time.Time              `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+	Schedule      *ReportSchedule         `json:"-"                    gorm:"foreignKey:ScheduleId"`
+}
+
+func (ReportScheduleRun) TableName() string {
+	return "report_schedule_run"
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportSchedule(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	newSchedule := func() *ReportSchedule {
+		return &ReportSchedule{
+			Name:       "Daily sales",
+			Resource:   "sales_report",
+			Format:     "csv",
+			Frequency:  ReportScheduleDaily,
+			TimeOfDay:  "07:00",
+			Channel:    ReportScheduleEmail,
+			Recipients: "owner@example.com, ",
+		}
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, newSchedule().Validate())
+		assert.Equal(t, []string{"owner@example.com"}, newSchedule().RecipientList())
+
+		invalids := []func(schedule *ReportSchedule){
+			func(schedule *ReportSchedule) { schedule.Name = " " },
+			func(schedule *ReportSchedule) { schedule.TimeOfDay = "25:00" },
+			func(schedule *ReportSchedule) { schedule.Frequency = "HOURLY" },
+			func(schedule *ReportSchedule) { schedule.Frequency, schedule.DayOfWeek = ReportScheduleWeekly, 7 },
+			func(schedule *ReportSchedule) { schedule.Frequency, schedule.DayOfMonth = ReportScheduleMonthly, 31 },
+			func(schedule *ReportSchedule) { schedule.Recipients = "not an email" },
+			func(schedule *ReportSchedule) {
+				schedule.Channel, schedule.WebhookUrl = ReportScheduleWebhook, "ftp://example.com"
+			},
+		}
+		for i, invalid := range invalids {
+			schedule := newSchedule()
+			invalid(schedule)
+			assert.Error(t, schedule.Validate(), i)
+		}
+	})
+
+	t.Run("NextRun", func(t *testing.T) {
+		// 2026-01-07 is a Wednesday, 06:00 at Jakarta
+		after := time.Date(2026, 1, 7, 6, 0, 0, 0, jakarta)
+
+		daily := newSchedule()
+		assert.Equal(t, time.Date(2026, 1, 7, 7, 0, 0, 0, jakarta), daily.NextRun(after, jakarta))
+		// Exactly at the run time is the next day
+		assert.Equal(t, time.Date(2026, 1, 8, 7, 0, 0, 0, jakarta), daily.NextRun(daily.NextRun(after, jakarta), jakarta))
+
+		weekly := newSchedule()
+		weekly.Frequency, weekly.DayOfWeek = ReportScheduleWeekly, int(time.Monday)
+		assert.Equal(t, time.Date(2026, 1, 12, 7, 0, 0, 0, jakarta), weekly.NextRun(after, jakarta))
+
+		monthly := newSchedule()
+		monthly.Frequency, monthly.DayOfMonth = ReportScheduleMonthly, 1
+		assert.Equal(t, time.Date(2026, 2, 1, 7, 0, 0, 0, jakarta), monthly.NextRun(after, jakarta))
+		monthly.DayOfMonth = 28
+		assert.Equal(t, time.Date(2026, 1, 28, 7, 0, 0, 0, jakarta), monthly.NextRun(after, jakarta))
+	})
+
+	t.Run("Period", func(t *testing.T) {
+		scheduledAt := time.Date(2026, 3, 2, 7, 0, 0, 0, jakarta)
+
+		daily := newSchedule()
+		start, end := daily.Period(scheduledAt, jakarta)
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, jakarta), start)
+		assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, jakarta), end)
+
+		weekly := newSchedule()
+		weekly.Frequency = ReportScheduleWeekly
+		start, _ = weekly.Period(scheduledAt, jakarta)
+		assert.Equal(t, time.Date(2026, 2, 23, 0, 0, 0, 0, jakarta), start)
+
+		monthly := newSchedule()
+		monthly.Frequency = ReportScheduleMonthly
+		start, end = monthly.Period(scheduledAt, jakarta)
+		assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, jakarta), start)
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, jakarta), end)
+	})
+
+	assert.Equal(t, "report_schedule", ReportSchedule{}.TableName())
+	assert.Equal(t, "report_schedule_run", ReportScheduleRun{}.TableName())
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+type SignInAttemptResult string
+
+const (
+	SignInSucceeded         SignInAttemptResult = "SUCCEEDED"
+	SignInWrongCredential   SignInAttemptResult = "WRONG_CREDENTIAL" // Wrong password or unknown email, not told apart. Also a wrong two factor code
+	SignInBlocked           SignInAttemptResult = "BLOCKED"          // Refused before checking the password
+	SignInTwoFactorRequired SignInAttemptResult = "TWO_FACTOR_REQUIRED"
+)
+
+/*
+SignInAttempt is the audit of 1 email and password sign in, never updated.
+
+	UserId is nil when the email is unknown
+*/
+type SignInAttempt struct {
+	Id        int                 `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	UserId    *int                `json:"user_id"              gorm:"column:user_id"`
+	Email     string              `json:"email"                gorm:"column:email"`
+	IpAddress string              `json:"ip_address"           gorm:"column:ip_address"`
+	UserAgent string              `json:"user_agent"           gorm:"column:user_agent"`
+	Result    SignInAttemptResult `json:"result"               gorm:"column:result"`
+	CreatedAt *time.Time          `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (SignInAttempt) TableName() string {
+	return "sign_in_attempt"
+}
+
+type SignInThrottleScope string
+
+const (
+	SignInThrottleAccount SignInThrottleScope = "ACCOUNT" // Key is the normalized email
+	SignInThrottleIp      SignInThrottleScope = "IP"
+)
+
+/*
+SignInThrottle count the failed sign in of 1 account or 1 IP in a row.
+
+	Sign in is refused while BlockedUntil is after now, see SignInThrottlePolicy
+*/
+type SignInThrottle struct {
+	Id           int                 `json:"id,omitempty"   gorm:"primaryKey;autoIncrement;column:id"`
+	Scope        SignInThrottleScope `json:"scope"          gorm:"column:scope"`
+	Key          string              `json:"key"            gorm:"column:key"`
+	FailedCount  int                 `json:"failed_count"   gorm:"column:failed_count"`
+	LastFailedAt time.Time           `json:"last_failed_at" gorm:"column:last_failed_at"`
+	BlockedUntil *time.Time          `json:"blocked_until"  gorm:"column:blocked_until"`
+}
+
+func (SignInThrottle) TableName() string {
+	return "sign_in_throttle"
+}
+
+func (throttle *SignInThrottle) IsBlocked(now time.Time) bool {
+	return throttle.BlockedUntil != nil && throttle.BlockedUntil.After(now)
+}
+
+/*
+SignInThrottlePolicy of a scope.
+
+	The first FreeAttempts failures are not delayed, then the wait double from BaseDelay up to MaxDelay.
+	At LockoutAfter failures it is locked for LockoutDuration.
+	A failure after ResetAfter without any restart the count
+*/
+type SignInThrottlePolicy struct {
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	ResetAfter      time.Duration
+}
+
+var (
+	AccountThrottlePolicy = SignInThrottlePolicy{
+		FreeAttempts:    3,
+		BaseDelay:       time.Second * 2,
+		MaxDelay:        time.Minute * 5,
+		LockoutAfter:    10,
+		LockoutDuration: time.Minute * 30,
+		ResetAfter:      time.Hour * 24,
+	}
+
+	// Looser, many cashiers may sign in behind the same shop IP
+	IpThrottlePolicy = SignInThrottlePolicy{
+		FreeAttempts:    10,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute * 5,
+		LockoutAfter:    50,
+		LockoutDuration: time.Hour,
+		ResetAfter:      time.Hour,
+	}
+)
+
+// BlockedUntil after the failedCount-th failure at now, nil when the next attempt is allowed right away
+func (policy SignInThrottlePolicy) BlockedUntil(failedCount int, now time.Time) *time.Time {
+	if failedCount >= policy.LockoutAfter {
+		blockedUntil := now.Add(policy.LockoutDuration)
+		return &blockedUntil
+	}
+	if failedCount <= policy.FreeAttempts {
+		return nil
+	}
+
+	delay := policy.BaseDelay
+	for i := policy.FreeAttempts + 1; i < failedCount && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	blockedUntil := now.Add(delay)
+	return &blockedUntil
+}
+
+// IsLockout is a failure count reaching the lockout, not only a backoff
+func (policy SignInThrottlePolicy) IsLockout(failedCount int) bool {
+	return failedCount >= policy.LockoutAfter
+}
+
+// The account throttle key of an email
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignInThrottlePolicy(t *testing.T) {
+	now := time.Now()
+	policy := AccountThrottlePolicy
+
+	for failedCount := 0; failedCount <= policy.FreeAttempts; failedCount++ {
+		assert.Nil(t, policy.BlockedUntil(failedCount, now))
+	}
+
+	blockedUntil := policy.BlockedUntil(policy.FreeAttempts+1, now)
+	require.NotNil(t, blockedUntil)
+	assert.Equal(t, now.Add(policy.BaseDelay), *blockedUntil)
+
+	blockedUntil = policy.BlockedUntil(policy.FreeAttempts+2, now)
+	require.NotNil(t, blockedUntil)
+	assert.Equal(t, now.Add(2*policy.BaseDelay), *blockedUntil)
+
+	// The backoff never goes over the max delay before the lockout
+	slow := SignInThrottlePolicy{FreeAttempts: 0, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute, LockoutAfter: 100, LockoutDuration: time.Hour}
+	blockedUntil = slow.BlockedUntil(20, now)
+	require.NotNil(t, blockedUntil)
+	assert.Equal(t, now.Add(slow.MaxDelay), *blockedUntil)
+	assert.False(t, slow.IsLockout(20))
+
+	blockedUntil = policy.BlockedUntil(policy.LockoutAfter, now)
+	require.NotNil(t, blockedUntil)
+	assert.Equal(t, now.Add(policy.LockoutDuration), *blockedUntil)
+	assert.True(t, policy.IsLockout(policy.LockoutAfter))
+	assert.False(t, policy.IsLockout(policy.LockoutAfter-1))
+}
+
+func TestSignInThrottle(t *testing.T) {
+	now := time.Now()
+	throttle := &SignInThrottle{}
+	assert.False(t, throttle.IsBlocked(now))
+
+	blockedUntil := now.Add(time.Minute)
+	throttle.BlockedUntil = &blockedUntil
+	assert.True(t, throttle.IsBlocked(now))
+	assert.False(t, throttle.IsBlocked(blockedUntil))
+
+	assert.Equal(t, "cashier@gmail.com", NormalizeEmail("  Cashier@Gmail.com "))
+	assert.Equal(t, "sign_in_attempt", SignInAttempt{}.TableName())
+	assert.Equal(t, "sign_in_throttle", SignInThrottle{}.TableName())
+}
@@ -9,8 +9,14 @@ type Tenant struct {
 	Name        string    `json:"name" gorm:"column:name"`
 	OwnerUserId int       `json:"owner_user_id" gorm:"column:owner_user_id"`
 	IsActive    bool      `json:"is_active" gorm:"column:is_active"` // by default at database is TRUE
+	Timezone    string    `json:"timezone" gorm:"column:timezone"`   // IANA name, e.g. Asia/Jakarta. "" means UTC
 	CreatedAt   time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
 
+	RequireTwoFactor bool `json:"require_two_factor" gorm:"column:require_two_factor"` // Owner and managers must enable TOTP to access the tenant
+
+	// Set with IsActive false by the owner archiving the tenant, it is deleted for good after TenantDeletionGracePeriod
+	ArchivedAt *time.Time `json:"archived_at" gorm:"column:archived_at"`
+
 	Users []User `json:"users,omitempty" gorm:"many2many:user_mtm_tenant;foreignKey:Id;joinForeignKey:TenantId;References:Id;joinReferences:UserId"`
 }
 
@@ -18,10 +24,40 @@ func (tenant *Tenant) TableName() string {
 	return "tenant"
 }
 
+// How long an archived tenant can be restored (and exported) before it is deleted with all its data
+const TenantDeletionGracePeriod = time.Hour * 24 * 30
+
+// When the archived tenant will be deleted, nil when not archived
+func (tenant *Tenant) DeletesAt() *time.Time {
+	if tenant.ArchivedAt == nil {
+		return nil
+	}
+
+	deletesAt := tenant.ArchivedAt.Add(TenantDeletionGracePeriod)
+	return &deletesAt
+}
+
+/*
+TenantRole of a member, the owner is tenant.owner_user_id.
+
+	"" (members added before the roles) is a cashier
+*/
+type TenantRole string
+
+const (
+	TenantRoleManager TenantRole = "MANAGER"
+	TenantRoleCashier TenantRole = "CASHIER"
+)
+
+func (role TenantRole) IsValid() bool {
+	return role == TenantRoleManager || role == TenantRoleCashier
+}
+
 type UserMtmTenant struct {
 	Id        int        `json:"id,omitempty" gorm:"primaryKey;autoIncrement;column:id"`
 	UserId    int        `json:"user_id" gorm:"column:user_id"`
 	TenantId  int        `json:"tenant_id" gorm:"column:tenant_id"`
+	Role      TenantRole `json:"role" gorm:"column:role"`
 	CreatedAt *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
 }
 
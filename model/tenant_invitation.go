@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// How long the emailed invite link can be used, resending it start again
+const TenantInvitationLifetime = time.Hour * 24 * 7
+
+/*
+TenantInvitation to join a tenant sent to an email, only the token hash is stored.
+
+	Accepting it by the user of that email create the user_mtm_tenant row with the role
+*/
+type TenantInvitation struct {
+	Id               int        `json:"id,omitempty"          gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId         int        `json:"tenant_id"             gorm:"column:tenant_id"`
+	Email            string     `json:"email"                 gorm:"column:email"` // Normalized, see NormalizeEmail
+	Role             TenantRole `json:"role"                  gorm:"column:role"`
+	TokenHash        string     `json:"-"                     gorm:"column:token_hash"`
+	InvitedByUserId  int        `json:"invited_by_user_id"    gorm:"column:invited_by_user_id"`
+	ExpiresAt        time.Time  `json:"expires_at"            gorm:"column:expires_at"`
+	SentAt           time.Time  `json:"sent_at"               gorm:"column:sent_at"` // Last time the email was sent
+	AcceptedAt       *time.Time `json:"accepted_at"           gorm:"column:accepted_at"`
+	AcceptedByUserId *int       `json:"accepted_by_user_id"   gorm:"column:accepted_by_user_id"`
+	RevokedAt        *time.Time `json:"revoked_at"            gorm:"column:revoked_at"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"  gorm:"column:created_at;<-:create"`
+
+	TenantName string `json:"tenant_name,omitempty" gorm:"-"` // Filled by Create and Renew, for the email
+}
+
+func (TenantInvitation) TableName() string {
+	return "tenant_invitation"
+}
+
+// Not accepted nor revoked yet, an expired one can still be resent
+func (invitation *TenantInvitation) IsPending() bool {
+	return invitation.AcceptedAt == nil && invitation.RevokedAt == nil
+}
+
+// Pending and not expired at now
+func (invitation *TenantInvitation) CanBeAccepted(now time.Time) bool {
+	return invitation.IsPending() && now.Before(invitation.ExpiresAt)
+}
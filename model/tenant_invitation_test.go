@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantInvitation(t *testing.T) {
+	now := time.Now()
+	invitation := &TenantInvitation{ExpiresAt: now.Add(TenantInvitationLifetime)}
+	assert.True(t, invitation.IsPending())
+	assert.True(t, invitation.CanBeAccepted(now))
+	assert.False(t, invitation.CanBeAccepted(invitation.ExpiresAt))
+
+	// Expired is still pending, it can be resent
+	expired := &TenantInvitation{ExpiresAt: now.Add(-time.Minute)}
+	assert.True(t, expired.IsPending())
+	assert.False(t, expired.CanBeAccepted(now))
+
+	invitation.RevokedAt = &now
+	assert.False(t, invitation.IsPending())
+	assert.False(t, invitation.CanBeAccepted(now))
+
+	assert.Equal(t, "tenant_invitation", TenantInvitation{}.TableName())
+}
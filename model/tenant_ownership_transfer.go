@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// How long the new owner have to accept
+const TenantOwnershipTransferLifetime = time.Hour * 72
+
+/*
+TenantOwnershipTransfer of a tenant from its owner to another member, applied only once the member accept it.
+
+	A tenant has at most 1 pending transfer, requesting another one cancel it
+*/
+type TenantOwnershipTransfer struct {
+	Id          int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId    int        `json:"tenant_id"            gorm:"column:tenant_id"`
+	FromUserId  int        `json:"from_user_id"         gorm:"column:from_user_id"`
+	ToUserId    int        `json:"to_user_id"           gorm:"column:to_user_id"`
+	ExpiresAt   time.Time  `json:"expires_at"           gorm:"column:expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at"          gorm:"column:accepted_at"`
+	CancelledAt *time.Time `json:"cancelled_at"         gorm:"column:cancelled_at"` // By the owner, or declined by the member
+	CreatedAt   *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (TenantOwnershipTransfer) TableName() string {
+	return "tenant_ownership_transfer"
+}
+
+// Not accepted nor cancelled and not expired at now
+func (transfer *TenantOwnershipTransfer) IsPending(now time.Time) bool {
+	return transfer.AcceptedAt == nil && transfer.CancelledAt == nil && now.Before(transfer.ExpiresAt)
+}
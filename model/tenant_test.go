@@ -42,3 +42,31 @@ func TestUserMtmTenant(t *testing.T) {
 	assert.Equal(t, 1, tenant.TenantId)
 	assert.Equal(t, time.Now().UTC().Day(), tenant.CreatedAt.UTC().Day())
 }
+
+func TestTenantRole(t *testing.T) {
+	assert.True(t, TenantRoleManager.IsValid())
+	assert.True(t, TenantRoleCashier.IsValid())
+	assert.False(t, TenantRole("OWNER").IsValid())
+	assert.False(t, TenantRole("").IsValid())
+}
+
+func TestTenantArchive(t *testing.T) {
+	tenant := &Tenant{IsActive: true}
+	assert.Nil(t, tenant.DeletesAt())
+
+	archivedAt := time.Now()
+	tenant.ArchivedAt = &archivedAt
+	assert.Equal(t, archivedAt.Add(TenantDeletionGracePeriod), *tenant.DeletesAt())
+}
+
+func TestTenantOwnershipTransfer(t *testing.T) {
+	now := time.Now()
+	transfer := &TenantOwnershipTransfer{ExpiresAt: now.Add(TenantOwnershipTransferLifetime)}
+	assert.True(t, transfer.IsPending(now))
+	assert.False(t, transfer.IsPending(transfer.ExpiresAt))
+
+	transfer.CancelledAt = &now
+	assert.False(t, transfer.IsPending(now))
+
+	assert.Equal(t, "tenant_ownership_transfer", TenantOwnershipTransfer{}.TableName())
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"regexp"
+	"time"
+)
+
+const (
+	TerminalSessionLifetime = time.Hour * 12 // 1 shift, a PIN sign in has no refresh token
+	PinMaxAttempts          = 5
+	PinLockoutDuration      = time.Minute * 15
+)
+
+var pinRegex = regexp.MustCompile(`^[0-9]{4,6}$`)
+
+/*
+Terminal is a shared till registered to 1 store.
+
+	It hold a device token (only the hash is stored), members sign in on it with their PIN.
+	A revoked terminal sign out every session started on it
+*/
+type Terminal struct {
+	Id              int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId        int        `json:"tenant_id"            gorm:"column:tenant_id"`
+	StoreId         int        `json:"store_id"             gorm:"column:store_id"`
+	Name            string     `json:"name"                 gorm:"column:name"`
+	TokenHash       string     `json:"-"                    gorm:"column:token_hash"`
+	CreatedByUserId int        `json:"created_by_user_id"   gorm:"column:created_by_user_id"`
+	LastUsedAt      *time.Time `json:"last_used_at"         gorm:"column:last_used_at"`
+	RevokedAt       *time.Time `json:"revoked_at"           gorm:"column:revoked_at"`
+	CreatedAt       *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (Terminal) TableName() string {
+	return "terminal"
+}
+
+// TerminalRegistration is returned once, the device token can't be read again
+type TerminalRegistration struct {
+	Terminal    *Terminal `json:"terminal"`
+	DeviceToken string    `json:"device_token"`
+}
+
+/*
+TenantMemberPin is the PIN of 1 member in 1 tenant, bcrypt hashed.
+
+	PinMaxAttempts wrong PINs in a row lock it for PinLockoutDuration
+*/
+type TenantMemberPin struct {
+	Id             int        `json:"id,omitempty"    gorm:"primaryKey;autoIncrement;column:id"`
+	TenantId       int        `json:"tenant_id"       gorm:"column:tenant_id"`
+	UserId         int        `json:"user_id"         gorm:"column:user_id"`
+	PinHash        string     `json:"-"               gorm:"column:pin_hash"`
+	FailedAttempts int        `json:"failed_attempts" gorm:"column:failed_attempts"`
+	LockedUntil    *time.Time `json:"locked_until"    gorm:"column:locked_until"`
+	UpdatedAt      *time.Time `json:"updated_at"      gorm:"column:updated_at"`
+}
+
+func (TenantMemberPin) TableName() string {
+	return "tenant_member_pin"
+}
+
+func (pin *TenantMemberPin) IsLocked(now time.Time) bool {
+	return pin.LockedUntil != nil && pin.LockedUntil.After(now)
+}
+
+// IsValidPin is 4 to 6 digits
+func IsValidPin(pin string) bool {
+	return pinRegex.MatchString(pin)
+}
+
+// TerminalMember is shown on the terminal to pick who is signing in
+type TerminalMember struct {
+	UserId int    `json:"user_id"`
+	Name   string `json:"name"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantMemberPin(t *testing.T) {
+	now := time.Now()
+	pin := &TenantMemberPin{}
+	assert.False(t, pin.IsLocked(now))
+
+	lockedUntil := now.Add(PinLockoutDuration)
+	pin.LockedUntil = &lockedUntil
+	assert.True(t, pin.IsLocked(now))
+	assert.False(t, pin.IsLocked(lockedUntil))
+
+	assert.True(t, IsValidPin("1234"))
+	assert.True(t, IsValidPin("123456"))
+	assert.False(t, IsValidPin("123"))
+	assert.False(t, IsValidPin("1234567"))
+	assert.False(t, IsValidPin("12 34"))
+
+	assert.Equal(t, "terminal", Terminal{}.TableName())
+	assert.Equal(t, "tenant_member_pin", TenantMemberPin{}.TableName())
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Recovery codes issued at once, every new set replace the previous one
+const RecoveryCodeCount = 10
+
+// The second step of the sign in must be done within it
+const TwoFactorChallengeLifetime = time.Minute * 5
+
+// Wrong codes allowed per challenge, the password must be entered again after it
+const TwoFactorChallengeMaxAttempts = 5
+
+/*
+UserRecoveryCode is 1 single use code replacing the TOTP code when the phone is lost, only the hash is stored
+*/
+type UserRecoveryCode struct {
+	Id        int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	UserId    int        `json:"user_id"              gorm:"column:user_id"`
+	CodeHash  string     `json:"-"                    gorm:"column:code_hash"`
+	UsedAt    *time.Time `json:"used_at"              gorm:"column:used_at"`
+	CreatedAt *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_code"
+}
+
+// TwoFactorEnrolment is shown once, the provisioning URI is rendered as QR code by the client
+type TwoFactorEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RemainingRecoveryCodes int        `json:"remaining_recovery_codes"`
+	RequiredByTenant       bool       `json:"required_by_tenant"` // Owner or manager of a tenant requiring it
+}
+
+// TwoFactorChallenge is returned by the password step, exchanged with the code for the session
+type TwoFactorChallenge struct {
+	Token     string    `json:"two_factor_token"`
+	ExpiresAt time.Time `json:"two_factor_token_expires_at"`
+}
@@ -10,6 +10,13 @@ type User struct {
 	Password  string    `json:"-"                    gorm:"column:password"` // hide from JSON
 	CreatedAt time.Time `json:"created_at,omitempty" gorm:"column:created_at;autoCreateTime;<-:create"`
 
+	EmailVerifiedAt *time.Time `json:"email_verified_at" gorm:"column:email_verified_at"` // nil until the verification link is opened
+
+	// TOTP, the secret is kept while the enrolment is not confirmed (TotpEnabledAt nil)
+	TotpSecret    string     `json:"-"               gorm:"column:totp_secret"`
+	TotpEnabledAt *time.Time `json:"totp_enabled_at" gorm:"column:totp_enabled_at"`
+	TotpLastStep  int64      `json:"-"               gorm:"column:totp_last_step"` // Last accepted time step, a code is used once
+
 	Tenants []Tenant `json:"tenants,omitempty" gorm:"many2many:user_mtm_tenant;foreignKey:Id;joinForeignKey:UserId;References:Id;joinReferences:TenantId"`
 }
 
@@ -17,6 +24,14 @@ func (user *User) TableName() string {
 	return "user"
 }
 
+func (user *User) IsEmailVerified() bool {
+	return user.EmailVerifiedAt != nil
+}
+
+func (user *User) IsTwoFactorEnabled() bool {
+	return user.TotpEnabledAt != nil && user.TotpSecret != ""
+}
+
 type UserRegisterForm struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+type SessionRevokeReason string
+
+const (
+	SessionRevokeSignOut       SessionRevokeReason = "SIGN_OUT"
+	SessionRevokeReuseDetected SessionRevokeReason = "REUSE_DETECTED"  // A rotated refresh token was used again
+	SessionRevokeByUser        SessionRevokeReason = "REVOKED_BY_USER" // From the session list, e.g. a lost tablet
+	SessionRevokePasswordReset SessionRevokeReason = "PASSWORD_CHANGED"
+	SessionRevokeTwoFactor     SessionRevokeReason = "TWO_FACTOR_CHANGED"
+	SessionRevokeTerminal      SessionRevokeReason = "TERMINAL_REVOKED"
+)
+
+const (
+	SessionDeviceNameMaxLength = 100
+	SessionUserAgentMaxLength  = 255
+)
+
+/*
+UserSession is 1 sign in, the access token carry its id (sid claim).
+
+	ExpiresAt slide with every refresh, a session idle for the refresh token lifetime is over.
+	A revoked session is kept for the history, its access token is refused right away
+*/
+type UserSession struct {
+	Id            int                 `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	UserId        int                 `json:"user_id"              gorm:"column:user_id"`
+	DeviceName    string              `json:"device_name"          gorm:"column:device_name"`
+	UserAgent     string              `json:"user_agent"           gorm:"column:user_agent"`
+	IpAddress     string              `json:"ip_address"           gorm:"column:ip_address"`
+	TerminalId    *int                `json:"terminal_id"          gorm:"column:terminal_id"` // PIN sign in, only the terminal sales routes
+	LastSeenAt    *time.Time          `json:"last_seen_at"         gorm:"column:last_seen_at"`
+	ExpiresAt     time.Time           `json:"expires_at"           gorm:"column:expires_at"`
+	RevokedAt     *time.Time          `json:"revoked_at"           gorm:"column:revoked_at"`
+	RevokedReason SessionRevokeReason `json:"revoked_reason"       gorm:"column:revoked_reason"`
+	CreatedAt     *time.Time          `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+	IsCurrent     bool                `json:"is_current"           gorm:"-"` // The session of the request
+}
+
+func (UserSession) TableName() string {
+	return "user_session"
+}
+
+// IsActive is not revoked and not expired at now
+func (session *UserSession) IsActive(now time.Time) bool {
+	return session.RevokedAt == nil && session.ExpiresAt.After(now)
+}
+
+// SessionDevice is where the sign in came from, given by the controller
+type SessionDevice struct {
+	DeviceName string
+	UserAgent  string
+	IpAddress  string
+}
+
+// Apply the device to the session, too long name and user agent are cut
+func (device SessionDevice) Apply(session *UserSession) {
+	session.DeviceName = truncate(strings.TrimSpace(device.DeviceName), SessionDeviceNameMaxLength)
+	session.UserAgent = truncate(device.UserAgent, SessionUserAgentMaxLength)
+	session.IpAddress = device.IpAddress
+}
+
+func truncate(value string, maxLength int) string {
+	runes := []rune(value)
+	if len(runes) <= maxLength {
+		return value
+	}
+	return string(runes[:maxLength])
+}
+
+/*
+UserRefreshToken is 1 refresh token of a session, only the hash is stored.
+
+	Every refresh rotate it: RotatedAt is set and a new one is issued.
+	Using a rotated token again means it was stolen, the whole session is revoked
+*/
+type UserRefreshToken struct {
+	Id        int        `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	SessionId int        `json:"session_id"           gorm:"column:session_id"`
+	TokenHash string     `json:"-"                    gorm:"column:token_hash"`
+	ExpiresAt time.Time  `json:"expires_at"           gorm:"column:expires_at"`
+	RotatedAt *time.Time `json:"rotated_at"           gorm:"column:rotated_at"`
+	CreatedAt *time.Time `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (UserRefreshToken) TableName() string {
+	return "user_refresh_token"
+}
+
+// AuthTokens is returned by sign in and refresh
+type AuthTokens struct {
+	AccessToken           string    `json:"token"`
+	AccessTokenExpiresAt  time.Time `json:"token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSession(t *testing.T) {
+	now := time.Now()
+	session := &UserSession{UserId: 1, ExpiresAt: now.Add(time.Hour)}
+	assert.True(t, session.IsActive(now))
+	assert.False(t, session.IsActive(now.Add(time.Hour)))
+
+	session.RevokedAt = &now
+	assert.False(t, session.IsActive(now))
+
+	assert.Equal(t, "user_session", UserSession{}.TableName())
+	assert.Equal(t, "user_refresh_token", UserRefreshToken{}.TableName())
+}
+
+func TestSessionDevice(t *testing.T) {
+	session := &UserSession{}
+	SessionDevice{
+		DeviceName: "  " + strings.Repeat("é", SessionDeviceNameMaxLength+5),
+		UserAgent:  strings.Repeat("a", SessionUserAgentMaxLength+1),
+		IpAddress:  "10.0.0.1",
+	}.Apply(session)
+
+	assert.Equal(t, SessionDeviceNameMaxLength, len([]rune(session.DeviceName)))
+	assert.Equal(t, SessionUserAgentMaxLength, len(session.UserAgent))
+	assert.Equal(t, "10.0.0.1", session.IpAddress)
+
+	SessionDevice{DeviceName: " Cashier tablet "}.Apply(session)
+	assert.Equal(t, "Cashier tablet", session.DeviceName)
+	assert.Equal(t, "", session.UserAgent)
+}
@@ -22,6 +22,16 @@ func TestUser(t *testing.T) {
 	assert.Equal(t, "Test user", user.Name)
 	assert.Equal(t, "Test@gmail.com", user.Email)
 	assert.Equal(t, "00000000-0000-0000-0000-000000000000", user.UserUuid)
+
+	assert.False(t, user.IsEmailVerified())
+	user.EmailVerifiedAt = &now
+	assert.True(t, user.IsEmailVerified())
+
+	// A pending enrolment is not enabled
+	user.TotpSecret = "JBSWY3DPEHPK3PXP"
+	assert.False(t, user.IsTwoFactorEnabled())
+	user.TotpEnabledAt = &now
+	assert.True(t, user.IsTwoFactorEnabled())
 }
 
 func TestUserRegisterForm(t *testing.T) {
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type UserTokenPurpose string
+
+const (
+	UserTokenPasswordReset     UserTokenPurpose = "PASSWORD_RESET"
+	UserTokenEmailVerification UserTokenPurpose = "EMAIL_VERIFICATION"
+	UserTokenTwoFactor         UserTokenPurpose = "TWO_FACTOR" // Password checked, waiting for the TOTP code
+)
+
+// How long the emailed link can be used
+const (
+	PasswordResetTokenLifetime     = time.Hour
+	EmailVerificationTokenLifetime = time.Hour * 48
+)
+
+/*
+UserToken is a single use token sent by email (forgot password, verify email), only the hash is stored.
+
+	Issuing a new one invalidate the unused ones of the same purpose, only the latest link work
+*/
+type UserToken struct {
+	Id        int              `json:"id,omitempty"         gorm:"primaryKey;autoIncrement;column:id"`
+	UserId    int              `json:"user_id"              gorm:"column:user_id"`
+	Purpose   UserTokenPurpose `json:"purpose"              gorm:"column:purpose"`
+	TokenHash string           `json:"-"                    gorm:"column:token_hash"`
+	ExpiresAt time.Time        `json:"expires_at"           gorm:"column:expires_at"`
+	UsedAt    *time.Time       `json:"used_at"              gorm:"column:used_at"`
+	Attempts  int              `json:"attempts"             gorm:"column:attempts"` // Wrong answers so far
+	CreatedAt *time.Time       `json:"created_at,omitempty" gorm:"column:created_at;<-:create"`
+}
+
+func (UserToken) TableName() string {
+	return "user_token"
+}
+
+// Lifetime of the purpose, 0 when unknown
+func (purpose UserTokenPurpose) Lifetime() time.Duration {
+	switch purpose {
+	case UserTokenPasswordReset:
+		return PasswordResetTokenLifetime
+	case UserTokenEmailVerification:
+		return EmailVerificationTokenLifetime
+	case UserTokenTwoFactor:
+		return TwoFactorChallengeLifetime
+	}
+	return 0
+}
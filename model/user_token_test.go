@@ -0,0 +1,16 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserToken(t *testing.T) {
+	assert.Equal(t, PasswordResetTokenLifetime, UserTokenPasswordReset.Lifetime())
+	assert.Equal(t, EmailVerificationTokenLifetime, UserTokenEmailVerification.Lifetime())
+	assert.Equal(t, TwoFactorChallengeLifetime, UserTokenTwoFactor.Lifetime())
+	assert.Zero(t, UserTokenPurpose("UNKNOWN").Lifetime())
+
+	assert.Equal(t, "user_token", UserToken{}.TableName())
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+)
+
+var (
+	ErrApiKeyAccessDenied = errors.New("Only the owner or a manager of the tenant can manage the API keys")
+	ErrApiKeyNotFound     = errors.New("API key not found")
+)
+
+/*
+API keys of the tenants, only the key hash is stored.
+
+	The key itself is checked by ProtectedRoute
+*/
+type ApiKeyRepository interface {
+	/*
+		Create the key. ErrApiKeyAccessDenied when managerUserId is not the owner or a manager
+	*/
+	Create(apiKey *model.ApiKey, managerUserId int) (*model.ApiKey, error)
+
+	/*
+		Every key of the tenant, revoked included
+	*/
+	GetByTenant(tenantId int) ([]model.ApiKey, error)
+
+	/*
+		Revoke the key, it is refused right away.
+		ErrApiKeyNotFound when it is not a key of the tenant or already revoked
+	*/
+	Revoke(tenantId int, apiKeyId int, managerUserId int) error
+}
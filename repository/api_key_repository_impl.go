@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ApiKeyRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewApiKeyRepositoryImpl(client *gorm.DB) ApiKeyRepository {
+	return &ApiKeyRepositoryImpl{Client: client}
+}
+
+// Create implements ApiKeyRepository.
+func (repository *ApiKeyRepositoryImpl) Create(apiKey *model.ApiKey, managerUserId int) (*model.ApiKey, error) {
+	allowed, err := canManageTenant(repository.Client, apiKey.TenantId, managerUserId)
+	if err != nil {
+		return nil, fmt.Errorf("Create api key failed: %w", err)
+	}
+	if !allowed {
+		return nil, ErrApiKeyAccessDenied
+	}
+
+	apiKey.CreatedByUserId = managerUserId
+	err = repository.Client.Create(apiKey).Error
+	if err != nil {
+		return nil, fmt.Errorf("Create api key failed: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// GetByTenant implements ApiKeyRepository.
+func (repository *ApiKeyRepositoryImpl) GetByTenant(tenantId int) ([]model.ApiKey, error) {
+	var apiKeys []model.ApiKey
+	err := repository.Client.
+		Where("tenant_id = ?", tenantId).
+		Order("revoked_at IS NOT NULL, created_at DESC").
+		Find(&apiKeys).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetByTenant api key failed: %w", err)
+	}
+
+	return apiKeys, nil
+}
+
+// Revoke implements ApiKeyRepository.
+func (repository *ApiKeyRepositoryImpl) Revoke(tenantId int, apiKeyId int, managerUserId int) error {
+	allowed, err := canManageTenant(repository.Client, tenantId, managerUserId)
+	if err != nil {
+		return fmt.Errorf("Revoke api key failed: %w", err)
+	}
+	if !allowed {
+		return ErrApiKeyAccessDenied
+	}
+
+	result := repository.Client.Model(&model.ApiKey{}).
+		Where("id = ? AND tenant_id = ? AND revoked_at IS NULL", apiKeyId, tenantId).
+		UpdateColumn("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("Revoke api key failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrApiKeyNotFound
+	}
+
+	return nil
+}
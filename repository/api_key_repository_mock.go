@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"cashier-api/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ApiKeyRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewApiKeyRepositoryMock(mock *mock.Mock) ApiKeyRepository {
+	return &ApiKeyRepositoryMock{Mock: mock}
+}
+
+// Create implements ApiKeyRepository.
+func (repository *ApiKeyRepositoryMock) Create(apiKey *model.ApiKey, managerUserId int) (*model.ApiKey, error) {
+	args := repository.Mock.Called(apiKey, managerUserId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ApiKey), nil
+}
+
+// GetByTenant implements ApiKeyRepository.
+func (repository *ApiKeyRepositoryMock) GetByTenant(tenantId int) ([]model.ApiKey, error) {
+	args := repository.Mock.Called(tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.ApiKey), nil
+}
+
+// Revoke implements ApiKeyRepository.
+func (repository *ApiKeyRepositoryMock) Revoke(tenantId int, apiKeyId int, managerUserId int) error {
+	args := repository.Mock.Called(tenantId, apiKeyId, managerUserId)
+	return args.Error(0)
+}
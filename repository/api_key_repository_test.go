@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestApiKeyRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("CreateAndRevoke", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewApiKeyRepositoryImpl(tx)
+
+		_, err := repo.Create(&model.ApiKey{TenantId: tenantId, Name: "Shop", Prefix: "epk_abc", KeyHash: "api-key-1", Scopes: "read:sales"}, ownerId+1000)
+		assert.ErrorIs(t, err, ErrApiKeyAccessDenied)
+
+		apiKey, err := repo.Create(&model.ApiKey{TenantId: tenantId, Name: "Shop", Prefix: "epk_abc", KeyHash: "api-key-1", Scopes: "read:sales"}, ownerId)
+		require.NoError(t, err)
+		require.NotZero(t, apiKey.Id)
+		assert.Equal(t, ownerId, apiKey.CreatedByUserId)
+
+		apiKeys, err := repo.GetByTenant(tenantId)
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 1)
+		assert.Equal(t, apiKey.Id, apiKeys[0].Id)
+
+		assert.ErrorIs(t, repo.Revoke(tenantId, apiKey.Id, ownerId+1000), ErrApiKeyAccessDenied)
+		require.NoError(t, repo.Revoke(tenantId, apiKey.Id, ownerId))
+		assert.ErrorIs(t, repo.Revoke(tenantId, apiKey.Id, ownerId), ErrApiKeyNotFound)
+
+		apiKeys, err = repo.GetByTenant(tenantId)
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 1)
+		assert.NotNil(t, apiKeys[0].RevokedAt)
+	})
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+)
+
+var ErrAuditOwnerOnly = errors.New("Only the owner of the tenant can read the audit log")
+
+/*
+The audit log, append only: there is no update nor delete
+*/
+type AuditRepository interface {
+	/*
+		Append 1 event
+	*/
+	Create(event *model.AuditEvent) error
+
+	/*
+		Events of the tenant matching the filter, latest first. page start at 0.
+		ErrAuditOwnerOnly when ownerUserId is not the owner of the tenant
+	*/
+	Search(tenantId int, filter *model.AuditEventFilter, limit, page int, ownerUserId int) ([]*model.AuditEvent, int, error)
+
+	/*
+		The row of the entity as JSON, "" when it is not found at the tenant
+	*/
+	Snapshot(entity *model.AuditEntity, tenantId int, entityId int) (model.AuditValue, error)
+}
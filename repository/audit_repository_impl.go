@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewAuditRepositoryImpl(client *gorm.DB) AuditRepository {
+	return &AuditRepositoryImpl{Client: client}
+}
+
+// Create implements AuditRepository.
+func (repository *AuditRepositoryImpl) Create(event *model.AuditEvent) error {
+	err := repository.Client.Create(event).Error
+	if err != nil {
+		return fmt.Errorf("Create audit event failed: %w", err)
+	}
+	return nil
+}
+
+// Search implements AuditRepository.
+func (repository *AuditRepositoryImpl) Search(tenantId int, filter *model.AuditEventFilter, limit, page int, ownerUserId int) ([]*model.AuditEvent, int, error) {
+	var owners int64
+	err := repository.Client.Model(&model.Tenant{}).
+		Where("id = ? AND owner_user_id = ?", tenantId, ownerUserId).
+		Count(&owners).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if owners == 0 {
+		return nil, 0, ErrAuditOwnerOnly
+	}
+
+	offset := page * limit
+
+	var events = make([]*model.AuditEvent, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.AuditEvent{}).
+		Where("tenant_id = ?", tenantId)
+	if filter.UserId != 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityId != 0 {
+		query = query.Where("entity_id = ?", filter.EntityId)
+	}
+	if filter.Method != "" {
+		query = query.Where("method = ?", filter.Method)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("created_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("created_at <= ?", *filter.EndDate)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, int(totalCount), nil
+}
+
+// Snapshot implements AuditRepository.
+func (repository *AuditRepositoryImpl) Snapshot(entity *model.AuditEntity, tenantId int, entityId int) (model.AuditValue, error) {
+	var value string
+	result := repository.Client.Table(entity.Table+" AS t").
+		Select("row_to_json(t)::text").
+		Where("t."+entity.PrimaryKey+" = ? AND t.tenant_id = ?", entityId, tenantId).
+		Limit(1).
+		Scan(&value)
+	if result.Error != nil {
+		return "", fmt.Errorf("Snapshot %s %d failed: %w", entity.Type, entityId, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return "", nil
+	}
+
+	return model.AuditValue(value), nil
+}
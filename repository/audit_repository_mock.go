@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"cashier-api/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewAuditRepositoryMock(mock *mock.Mock) AuditRepository {
+	return &AuditRepositoryMock{Mock: mock}
+}
+
+// Create implements AuditRepository.
+func (repository *AuditRepositoryMock) Create(event *model.AuditEvent) error {
+	args := repository.Mock.Called(event)
+	return args.Error(0)
+}
+
+// Search implements AuditRepository.
+func (repository *AuditRepositoryMock) Search(tenantId int, filter *model.AuditEventFilter, limit, page int, ownerUserId int) ([]*model.AuditEvent, int, error) {
+	args := repository.Mock.Called(tenantId, filter, limit, page, ownerUserId)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.AuditEvent), args.Int(1), nil
+}
+
+// Snapshot implements AuditRepository.
+func (repository *AuditRepositoryMock) Snapshot(entity *model.AuditEntity, tenantId int, entityId int) (model.AuditValue, error) {
+	args := repository.Mock.Called(entity, tenantId, entityId)
+	return args.Get(0).(model.AuditValue), args.Error(1)
+}
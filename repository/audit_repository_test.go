@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAuditRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("SnapshotAndSearch", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewAuditRepositoryImpl(tx)
+		storeEntity := &model.AuditEntity{Type: "store", Table: "store", PrimaryKey: "id", IdFields: []string{"store_id"}}
+
+		before, err := repo.Snapshot(storeEntity, tenantId, storeId)
+		require.NoError(t, err)
+		var row map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(before), &row))
+		assert.Equal(t, float64(storeId), row["id"])
+
+		// Another tenant never see the row
+		other, err := repo.Snapshot(storeEntity, tenantId+1000, storeId)
+		require.NoError(t, err)
+		assert.Equal(t, model.AuditValue(""), other)
+
+		require.NoError(t, repo.Create(&model.AuditEvent{
+			TenantId: &tenantId, UserId: ownerId, Method: "PUT", Route: "/api/v1/stores/:tenantId", StatusCode: 200,
+			EntityType: "store", EntityId: &storeId, Before: before, After: before, RequestId: "request-1",
+		}))
+		require.NoError(t, repo.Create(&model.AuditEvent{
+			TenantId: &tenantId, UserId: ownerId, Method: "POST", Route: "/api/v1/customers/:tenantId", StatusCode: 200,
+			EntityType: "customer", RequestId: "request-2",
+		}))
+
+		events, count, err := repo.Search(tenantId, &model.AuditEventFilter{EntityType: "store", EntityId: storeId}, 10, 0, ownerId)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		require.Len(t, events, 1)
+		assert.Equal(t, before, events[0].Before)
+
+		_, count, err = repo.Search(tenantId, &model.AuditEventFilter{}, 10, 0, ownerId)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		_, _, err = repo.Search(tenantId, &model.AuditEventFilter{}, 10, 0, ownerId+1000)
+		assert.ErrorIs(t, err, ErrAuditOwnerOnly)
+	})
+
+	t.Run("SnapshotWarehouseItem", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		repo := NewAuditRepositoryImpl(tx)
+		item := &model.Item{ItemName: "Audit Test Item", StockType: model.StockTypeTracked, BasePrice: 1500, TenantId: tenantId, IsActive: true}
+		require.NoError(t, tx.Create(item).Error)
+
+		// The warehouse primary key is item_id, not id
+		itemEntity := &model.AuditEntity{Type: "item", Table: "warehouse", PrimaryKey: "item_id", IdFields: []string{"item_id"}}
+		value, err := repo.Snapshot(itemEntity, tenantId, item.ItemId)
+		require.NoError(t, err)
+		var row map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(value), &row))
+		assert.Equal(t, float64(item.ItemId), row["item_id"])
+		assert.Equal(t, float64(1500), row["base_price"])
+	})
+}
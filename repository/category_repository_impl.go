@@ -106,7 +106,8 @@ func (repository *CategoryRepositoryImpl) Get(tenantId, page, limit int, nameQue
 		return nil, 0, err
 	}
 
-	if err := query.Offset(start).Limit(limit).Find(&results).Error; err != nil {
+	// Stable order, so every page is the next rows
+	if err := query.Order("id ASC").Offset(start).Limit(limit).Find(&results).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+)
+
+/*
+X and Z reports of a store, stored permanently for audit
+*/
+type ClosingReportRepository interface {
+	/*
+		Compute the report of the store from the last Z-report (or the start of the business day)
+		until now, then store it. Z-report take the next sequential number of the store,
+		only 1 Z-report per business day at the tenant timezone
+	*/
+	Generate(tenantId, storeId, userId int, reportType model.ClosingReportType, now time.Time) (*model.ClosingReport, error)
+
+	/*
+		Return 1 stored report
+	*/
+	FindById(closingReportId, tenantId int) (*model.ClosingReport, error)
+
+	/*
+		Stored reports of a store (newest first), reportType = "" will not filter
+		2nd params return is the count of all data
+	*/
+	Get(tenantId, storeId int, reportType model.ClosingReportType, limit, page int) ([]*model.ClosingReport, int, error)
+}
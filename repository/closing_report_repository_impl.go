@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ClosingReportRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewClosingReportRepositoryImpl(client *gorm.DB) ClosingReportRepository {
+	return &ClosingReportRepositoryImpl{Client: client}
+}
+
+/*
+tenantLocation load the tenant timezone, "" (not set yet) is UTC
+*/
+func tenantLocation(tx *gorm.DB, tenantId int) (*time.Location, error) {
+	timezone, err := NewOrderItemRepositoryImpl(tx).GetTenantTimezone(tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return time.LoadLocation(timezone)
+}
+
+// Generate implements ClosingReportRepository.
+func (repository *ClosingReportRepositoryImpl) Generate(tenantId, storeId, userId int, reportType model.ClosingReportType, now time.Time) (*model.ClosingReport, error) {
+	if !reportType.IsValid() {
+		return nil, fmt.Errorf("Invalid closing report type: %s", reportType)
+	}
+
+	var report *model.ClosingReport
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		// Locked, so 2 tills never close the same store at the same time
+		var store model.Store
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", storeId, tenantId).
+			Take(&store).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("Store %d not found for tenant %d", storeId, tenantId)
+		}
+		if err != nil {
+			return err
+		}
+
+		location, err := tenantLocation(tx, tenantId)
+		if err != nil {
+			return err
+		}
+
+		// to_timestamp() filter by second, so the next period start exactly where this one end
+		periodEnd := now.Truncate(time.Second)
+		businessDate := periodEnd.In(location).Format(time.DateOnly)
+
+		var lastZ model.ClosingReport
+		err = tx.Where("store_id = ? AND type = ?", storeId, model.ClosingReportTypeZ).
+			Order("z_number DESC").
+			Take(&lastZ).Error
+		hasLastZ := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		periodStart := startOfDay(periodEnd.In(location))
+		if hasLastZ {
+			periodStart = lastZ.PeriodEnd
+			if reportType == model.ClosingReportTypeZ && lastZ.BusinessDate == businessDate {
+				return fmt.Errorf("Business day %s of store %d is already closed by Z-report #%d", businessDate, storeId, *lastZ.ZNumber)
+			}
+		}
+
+		report, err = computeClosingReport(tx, tenantId, storeId, periodStart, periodEnd)
+		if err != nil {
+			return err
+		}
+		report.Type = reportType
+		report.BusinessDate = businessDate
+		report.Timezone = location.String()
+		report.UserId = userId
+
+		if reportType == model.ClosingReportTypeZ {
+			zNumber := 1
+			if hasLastZ {
+				zNumber = *lastZ.ZNumber + 1
+			}
+			report.ZNumber = &zNumber
+		}
+
+		return tx.Create(report).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+/*
+computeClosingReport aggregate the store activity of [periodStart, periodEnd),
+sales total are the same as GetSalesReport
+*/
+func computeClosingReport(tx *gorm.DB, tenantId, storeId int, periodStart, periodEnd time.Time) (*model.ClosingReport, error) {
+	startDate, endDate := periodStart.Unix(), periodEnd.Unix()
+	dateFilter := &query.DateFilter{StartDate: &startDate, EndDate: &endDate}
+
+	salesReport, err := NewOrderItemRepositoryImpl(tx).GetSalesReport(tenantId, storeId, dateFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payment method and invoice range, Model() applies deleted_at IS NULL automatically
+	var payment struct {
+		PointsDiscount int
+		GiftCardAmount int
+		FirstInvoiceId *int
+		LastInvoiceId  *int
+	}
+	err = applySalesReportFilters(tx.Model(&model.OrderItem{}), "", tenantId, storeId, dateFilter).
+		Select(`
+			COALESCE(SUM(points_discount), 0)  AS points_discount,
+			COALESCE(SUM(gift_card_amount), 0) AS gift_card_amount,
+			MIN(id) AS first_invoice_id,
+			MAX(id) AS last_invoice_id
+		`).
+		Scan(&payment).Error
+	if err != nil {
+		return nil, fmt.Errorf("closing report payment query failed: %w", err)
+	}
+
+	// Tax is included in the line total, summed from the lines of the same sales
+	var taxAmount int
+	err = applySalesReportFilters(
+		tx.Table("purchased_item_list pil").
+			Joins(completedSalesJoin),
+		"oi.", tenantId, storeId, dateFilter,
+	).
+		Select("COALESCE(SUM(pil.tax_amount), 0)").
+		Scan(&taxAmount).Error
+	if err != nil {
+		return nil, fmt.Errorf("closing report tax query failed: %w", err)
+	}
+
+	// Only a voided sale, an order voided before it was COMPLETED never counted as one
+	var voids struct {
+		VoidCount  int
+		VoidAmount int
+	}
+	err = applyDateRange(
+		tx.Table("order_item").
+			Where("tenant_id = ? AND store_id = ? AND deleted_at IS NOT NULL", tenantId, storeId).
+			Where("status = ?", model.OrderStatusCompleted),
+		"deleted_at", dateFilter,
+	).
+		Select("COUNT(id) AS void_count, COALESCE(SUM(total_amount), 0) AS void_amount").
+		Scan(&voids).Error
+	if err != nil {
+		return nil, fmt.Errorf("closing report void query failed: %w", err)
+	}
+
+	var refunds struct {
+		RefundCount  int
+		RefundAmount int
+	}
+	err = applyDateRange(
+		tx.Model(&model.GiftCardMovement{}).
+			Where("tenant_id = ? AND store_id = ? AND type = ?", tenantId, storeId, model.GiftCardMovementIssue),
+		"created_at", dateFilter,
+	).
+		Select("COUNT(id) AS refund_count, COALESCE(SUM(amount), 0) AS refund_amount").
+		Scan(&refunds).Error
+	if err != nil {
+		return nil, fmt.Errorf("closing report refund query failed: %w", err)
+	}
+
+	return &model.ClosingReport{
+		TenantId:         tenantId,
+		StoreId:          storeId,
+		PeriodStart:      periodStart,
+		PeriodEnd:        periodEnd,
+		TransactionCount: salesReport.SumTransactions,
+		TotalQuantity:    salesReport.SumTotalQuantity,
+		Subtotal:         salesReport.SumSubtotal,
+		DiscountAmount:   salesReport.SumDiscountAmount,
+		PointsDiscount:   payment.PointsDiscount,
+		TotalAmount:      salesReport.SumTotalAmount,
+		TaxAmount:        taxAmount,
+		Profit:           salesReport.SumProfit,
+		CashAmount:       salesReport.SumTotalAmount - payment.GiftCardAmount,
+		CashTendered:     salesReport.SumPurchasedPrice,
+		GiftCardAmount:   payment.GiftCardAmount,
+		VoidCount:        voids.VoidCount,
+		VoidAmount:       voids.VoidAmount,
+		RefundCount:      refunds.RefundCount,
+		RefundAmount:     refunds.RefundAmount,
+		FirstInvoiceId:   payment.FirstInvoiceId,
+		LastInvoiceId:    payment.LastInvoiceId,
+	}, nil
+}
+
+// FindById implements ClosingReportRepository.
+func (repository *ClosingReportRepositoryImpl) FindById(closingReportId, tenantId int) (*model.ClosingReport, error) {
+	var report model.ClosingReport
+	err := repository.Client.
+		Where("id = ? AND tenant_id = ?", closingReportId, tenantId).
+		Take(&report).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("No closing report found with id %d", closingReportId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// Get implements ClosingReportRepository.
+func (repository *ClosingReportRepositoryImpl) Get(tenantId, storeId int, reportType model.ClosingReportType, limit, page int) ([]*model.ClosingReport, int, error) {
+	offset := page * limit
+
+	var reports = make([]*model.ClosingReport, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.ClosingReport{}).
+		Where("tenant_id = ? AND store_id = ?", tenantId, storeId)
+	if reportType != "" {
+		query = query.Where("type = ?", reportType)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("period_end DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reports, int(totalCount), nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ClosingReportRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewClosingReportRepositoryMock(mock *mock.Mock) ClosingReportRepository {
+	return &ClosingReportRepositoryMock{Mock: mock}
+}
+
+// Generate implements ClosingReportRepository.
+func (repository *ClosingReportRepositoryMock) Generate(tenantId, storeId, userId int, reportType model.ClosingReportType, now time.Time) (*model.ClosingReport, error) {
+	args := repository.Mock.Called(tenantId, storeId, userId, reportType, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ClosingReport), nil
+}
+
+// FindById implements ClosingReportRepository.
+func (repository *ClosingReportRepositoryMock) FindById(closingReportId, tenantId int) (*model.ClosingReport, error) {
+	args := repository.Mock.Called(closingReportId, tenantId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ClosingReport), nil
+}
+
+// Get implements ClosingReportRepository.
+func (repository *ClosingReportRepositoryMock) Get(tenantId, storeId int, reportType model.ClosingReportType, limit, page int) ([]*model.ClosingReport, int, error) {
+	args := repository.Mock.Called(tenantId, storeId, reportType, limit, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.ClosingReport), args.Int(1), nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestClosingReportRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("Generate", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		repo := NewClosingReportRepositoryImpl(tx)
+
+		orderItem, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 50_000,
+			TotalQuantity:  2,
+			TotalAmount:    45_000,
+			DiscountAmount: 5_000,
+			Subtotal:       50_000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+		})
+		require.NoError(t, err)
+
+		item := &model.Item{ItemName: "Closing Item", Stocks: 5, StockType: model.StockTypeTracked, BasePrice: 10_000, TenantId: tenantId, IsActive: true}
+		require.NoError(t, tx.Create(item).Error)
+		require.NoError(t, tx.Create(&model.PurchasedItem{
+			ItemId: item.ItemId, ItemNameSnapshot: item.ItemName, Quantity: 2, StorePriceSnapshot: 25_000, BasePriceSnapshot: 10_000,
+			DiscountAmount: 2_500, TotalAmount: 45_000, TaxAmount: 4_090, OrderItemId: orderItem.Id,
+		}).Error)
+
+		_, err = NewGiftCardRepositoryImpl(tx).IssueStoreCredit(&model.GiftCard{TenantId: tenantId, Code: "CLOSING23456", InitialBalance: 10_000}, &orderItem.Id, userId, "Refund")
+		require.NoError(t, err)
+
+		now := time.Now().Add(time.Second)
+		xReport, err := repo.Generate(tenantId, storeId, userId, model.ClosingReportTypeX, now)
+		require.NoError(t, err)
+		assert.Nil(t, xReport.ZNumber)
+		assert.Equal(t, 1, xReport.TransactionCount)
+		assert.Equal(t, 45_000, xReport.CashAmount)
+		assert.Equal(t, 4_090, xReport.TaxAmount)
+		assert.Equal(t, 1, xReport.RefundCount)
+		assert.Equal(t, 10_000, xReport.RefundAmount)
+		require.NotNil(t, xReport.FirstInvoiceId)
+		assert.Equal(t, orderItem.Id, *xReport.FirstInvoiceId)
+
+		zReport, err := repo.Generate(tenantId, storeId, userId, model.ClosingReportTypeZ, now)
+		require.NoError(t, err)
+		require.NotNil(t, zReport.ZNumber)
+		assert.Equal(t, 1, *zReport.ZNumber)
+		assert.Equal(t, xReport.TotalAmount, zReport.TotalAmount)
+
+		// The business day is closed
+		_, err = repo.Generate(tenantId, storeId, userId, model.ClosingReportTypeZ, now.Add(time.Minute))
+		assert.Error(t, err)
+
+		// X-report after closing start from the Z-report
+		afterClose, err := repo.Generate(tenantId, storeId, userId, model.ClosingReportTypeX, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, afterClose.PeriodStart.Equal(zReport.PeriodEnd))
+		assert.Zero(t, afterClose.TransactionCount)
+		assert.Nil(t, afterClose.FirstInvoiceId)
+
+		// Next day get the next number
+		nextDay, err := repo.Generate(tenantId, storeId, userId, model.ClosingReportTypeZ, now.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 2, *nextDay.ZNumber)
+
+		reports, count, err := repo.Get(tenantId, storeId, model.ClosingReportTypeZ, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, nextDay.Id, reports[0].Id)
+
+		found, err := repo.FindById(zReport.Id, tenantId)
+		require.NoError(t, err)
+		assert.Equal(t, zReport.TotalAmount, found.TotalAmount)
+
+		_, err = repo.FindById(zReport.Id, tenantId+1)
+		assert.Error(t, err)
+	})
+
+	t.Run("SaleCompletedAfterClose", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		repo := NewClosingReportRepositoryImpl(tx)
+		orderItemRepo := NewOrderItemRepositoryImpl(tx)
+
+		ready, err := orderItemRepo.PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 30_000,
+			TotalQuantity:  1,
+			TotalAmount:    30_000,
+			Subtotal:       30_000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+			Status:         model.OrderStatusReady,
+		})
+		require.NoError(t, err)
+
+		// Placed before the close, not a sale yet
+		now := time.Now()
+		zReport, err := repo.Generate(tenantId, storeId, userId, model.ClosingReportTypeZ, now)
+		require.NoError(t, err)
+		assert.Zero(t, zReport.TransactionCount)
+
+		_, err = orderItemRepo.UpdateStatus(&UpdateOrderStatusParams{
+			OrderItemId: ready.Id,
+			TenantId:    tenantId,
+			UserId:      userId,
+			Status:      model.OrderStatusCompleted,
+		})
+		require.NoError(t, err)
+
+		// Completed after the close, it belong to the next period
+		afterClose, err := repo.Generate(tenantId, storeId, userId, model.ClosingReportTypeX, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, afterClose.PeriodStart.Equal(zReport.PeriodEnd))
+		assert.Equal(t, 1, afterClose.TransactionCount)
+		assert.Equal(t, 30_000, afterClose.TotalAmount)
+	})
+
+	t.Run("VoidOfSaleOnly", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		orderItemRepo := NewOrderItemRepositoryImpl(tx)
+
+		// Voided sale and voided order that was never a sale
+		for _, status := range []model.OrderStatus{model.OrderStatusCompleted, model.OrderStatusPlaced} {
+			orderItem, err := orderItemRepo.PlaceOrderItem(&model.OrderItem{
+				PurchasedPrice: 20_000,
+				TotalQuantity:  1,
+				TotalAmount:    20_000,
+				Subtotal:       20_000,
+				TenantId:       tenantId,
+				StoreId:        storeId,
+				Status:         status,
+			})
+			require.NoError(t, err)
+			require.NoError(t, orderItemRepo.DeleteInvoice(orderItem.Id, tenantId, userId))
+		}
+
+		xReport, err := NewClosingReportRepositoryImpl(tx).Generate(tenantId, storeId, userId, model.ClosingReportTypeX, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 1, xReport.VoidCount)
+		assert.Equal(t, 20_000, xReport.VoidAmount)
+	})
+
+	t.Run("StoreOfAnotherTenant", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+
+		_, err := NewClosingReportRepositoryImpl(tx).Generate(tenantId+1, storeId, 1, model.ClosingReportTypeX, time.Now())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,35 @@
+package repository
+
+import "cashier-api/model"
+
+/*
+Customer directory, scoped by tenant.
+Purchase history is stored at order_item.customer_id
+*/
+type CustomerRepository interface {
+	/*
+		Get the list of customer, phoneQuery will match any part of the phone number
+		2nd params return is the count of all data
+	*/
+	Get(tenantId, limit, page int, phoneQuery string) ([]*model.Customer, int, error)
+
+	/*
+		Return 1 customer
+	*/
+	FindById(customerId, tenantId int) (*model.Customer, error)
+
+	/*
+		Create new customer
+	*/
+	Create(customer *model.Customer) (*model.Customer, error)
+
+	/*
+		Edit customer properties (name, phone, email, notes)
+	*/
+	Edit(customer *model.Customer) (*model.Customer, error)
+
+	/*
+		Using aggregate function from SQL to get lifetime spend and visit count
+	*/
+	GetStats(customerId, tenantId int) (*model.CustomerStats, error)
+}
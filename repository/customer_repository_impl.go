@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+const CustomerTable string = "customer"
+
+type CustomerRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewCustomerRepositoryImpl(client *gorm.DB) CustomerRepository {
+	return &CustomerRepositoryImpl{Client: client}
+}
+
+// Get implements CustomerRepository.
+func (repository *CustomerRepositoryImpl) Get(tenantId, limit, page int, phoneQuery string) ([]*model.Customer, int, error) {
+	offset := page * limit
+
+	var customers = make([]*model.Customer, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.Customer{}).
+		Where("tenant_id = ?", tenantId)
+
+	if phoneQuery != "" {
+		query = query.Where("phone LIKE ?", "%"+phoneQuery+"%")
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("name ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&customers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return customers, int(totalCount), nil
+}
+
+// FindById implements CustomerRepository.
+func (repository *CustomerRepositoryImpl) FindById(customerId, tenantId int) (*model.Customer, error) {
+	var customer model.Customer
+	err := repository.Client.
+		Where("id = ? AND tenant_id = ?", customerId, tenantId).
+		Take(&customer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("No customer found with tenant_id=%d and id=%d", tenantId, customerId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// Create implements CustomerRepository.
+func (repository *CustomerRepositoryImpl) Create(customer *model.Customer) (*model.Customer, error) {
+	if err := repository.Client.Create(customer).Error; err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// Edit implements CustomerRepository.
+func (repository *CustomerRepositoryImpl) Edit(customer *model.Customer) (*model.Customer, error) {
+	// Struct with id definition will trigger auto update updated_at query
+	result := repository.Client.Model(&model.Customer{Id: customer.Id}).
+		Where("tenant_id = ? AND id = ?", customer.TenantId, customer.Id).
+		Updates(map[string]any{
+			"name":  customer.Name,
+			"phone": customer.Phone,
+			"email": customer.Email,
+			"notes": customer.Notes,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("No customer found with tenant_id=%d and id=%d", customer.TenantId, customer.Id)
+	}
+
+	return repository.FindById(customer.Id, customer.TenantId)
+}
+
+// GetStats implements CustomerRepository.
+func (repository *CustomerRepositoryImpl) GetStats(customerId, tenantId int) (*model.CustomerStats, error) {
+	var stats model.CustomerStats
+
+	// Model() applies deleted_at IS NULL automatically, voided invoice and open order are not counted
+	err := repository.Client.Model(&model.OrderItem{}).
+		Select(`
+			COALESCE(SUM(total_amount), 0) AS lifetime_spend,
+			COUNT(id)                      AS visit_count,
+			MAX(created_at)                AS last_visit_at
+		`).
+		Where("tenant_id = ? AND customer_id = ?", tenantId, customerId).
+		Where("status = ?", model.OrderStatusCompleted).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetStats customer summary failed: %w", err)
+	}
+
+	return &stats, nil
+}
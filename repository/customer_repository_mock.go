@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"cashier-api/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CustomerRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewCustomerRepositoryMock(mock *mock.Mock) CustomerRepository {
+	return &CustomerRepositoryMock{Mock: mock}
+}
+
+// Get implements CustomerRepository.
+func (repository *CustomerRepositoryMock) Get(tenantId, limit, page int, phoneQuery string) ([]*model.Customer, int, error) {
+	args := repository.Mock.Called(tenantId, limit, page, phoneQuery)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.Customer), args.Int(1), nil
+}
+
+// FindById implements CustomerRepository.
+func (repository *CustomerRepositoryMock) FindById(customerId, tenantId int) (*model.Customer, error) {
+	args := repository.Mock.Called(customerId, tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Customer), nil
+}
+
+// Create implements CustomerRepository.
+func (repository *CustomerRepositoryMock) Create(customer *model.Customer) (*model.Customer, error) {
+	args := repository.Mock.Called(customer)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Customer), nil
+}
+
+// Edit implements CustomerRepository.
+func (repository *CustomerRepositoryMock) Edit(customer *model.Customer) (*model.Customer, error) {
+	args := repository.Mock.Called(customer)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Customer), nil
+}
+
+// GetStats implements CustomerRepository.
+func (repository *CustomerRepositoryMock) GetStats(customerId, tenantId int) (*model.CustomerStats, error) {
+	args := repository.Mock.Called(customerId, tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.CustomerStats), nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestCustomerRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("NormalCreate", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, _ := seedOrderItemTestDependencies(t, tx)
+			customerRepo := NewCustomerRepositoryImpl(tx)
+
+			createdCustomer, err := customerRepo.Create(&model.Customer{
+				Name:     "Customer Repository Test",
+				Phone:    "081234567890",
+				TenantId: tenantId,
+			})
+			assert.NoError(t, err)
+			assert.NotZero(t, createdCustomer.Id)
+			assert.NotNil(t, createdCustomer.CreatedAt)
+		})
+
+		t.Run("DuplicatePhone", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, _ := seedOrderItemTestDependencies(t, tx)
+			customerRepo := NewCustomerRepositoryImpl(tx)
+
+			_, err := customerRepo.Create(&model.Customer{Name: "First", Phone: "081200000000", TenantId: tenantId})
+			require.NoError(t, err)
+
+			createdCustomer, err := customerRepo.Create(&model.Customer{Name: "Second", Phone: "081200000000", TenantId: tenantId})
+			assert.Nil(t, createdCustomer)
+			assert.Error(t, err)
+
+			pgErr, ok := err.(*pgconn.PgError)
+			assert.True(t, ok)
+			assert.Equal(t, "23505", pgErr.Code)
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("SearchByPhone", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, _ := seedOrderItemTestDependencies(t, tx)
+			customerRepo := NewCustomerRepositoryImpl(tx)
+
+			for _, c := range []*model.Customer{
+				{Name: "Andi", Phone: "081111111111", TenantId: tenantId},
+				{Name: "Budi", Phone: "082222222222", TenantId: tenantId},
+				{Name: "Cici", Phone: "081133333333", TenantId: tenantId},
+			} {
+				_, err := customerRepo.Create(c)
+				require.NoError(t, err)
+			}
+
+			customers, count, err := customerRepo.Get(tenantId, 10, 0, "0811")
+			assert.NoError(t, err)
+			assert.Equal(t, 2, count)
+			assert.Len(t, customers, 2)
+
+			customers, count, err = customerRepo.Get(tenantId, 10, 0, "")
+			assert.NoError(t, err)
+			assert.Equal(t, 3, count)
+			assert.Len(t, customers, 3)
+		})
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		t.Run("NormalEdit", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, _ := seedOrderItemTestDependencies(t, tx)
+			customerRepo := NewCustomerRepositoryImpl(tx)
+
+			createdCustomer, err := customerRepo.Create(&model.Customer{Name: "Before", Phone: "081234500000", TenantId: tenantId})
+			require.NoError(t, err)
+
+			editedCustomer, err := customerRepo.Edit(&model.Customer{
+				Id:       createdCustomer.Id,
+				Name:     "After",
+				Phone:    "081234500000",
+				Notes:    "Regular",
+				TenantId: tenantId,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "After", editedCustomer.Name)
+			assert.Equal(t, "Regular", editedCustomer.Notes)
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, _ := seedOrderItemTestDependencies(t, tx)
+			customerRepo := NewCustomerRepositoryImpl(tx)
+
+			editedCustomer, err := customerRepo.Edit(&model.Customer{Id: 999999999, Name: "Nobody", Phone: "081234500000", TenantId: tenantId})
+			assert.Error(t, err)
+			assert.Nil(t, editedCustomer)
+		})
+	})
+
+	t.Run("GetStats", func(t *testing.T) {
+		t.Run("VoidedInvoiceNotCounted", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+			customerRepo := NewCustomerRepositoryImpl(tx)
+			orderItemRepo := NewOrderItemRepositoryImpl(tx)
+
+			customer, err := customerRepo.Create(&model.Customer{Name: "Stats", Phone: "081299999999", TenantId: tenantId})
+			require.NoError(t, err)
+
+			var placed []*model.OrderItem
+			for _, amount := range []int{10000, 20000, 30000} {
+				orderItem, err := orderItemRepo.PlaceOrderItem(&model.OrderItem{
+					PurchasedPrice: amount,
+					TotalQuantity:  1,
+					TotalAmount:    amount,
+					Subtotal:       amount,
+					TenantId:       tenantId,
+					StoreId:        storeId,
+					CustomerId:     &customer.Id,
+				})
+				require.NoError(t, err)
+				placed = append(placed, orderItem)
+			}
+
+			// Void the last one
+			require.NoError(t, orderItemRepo.DeleteInvoice(placed[2].Id, tenantId, tenantOwnerId(t, tx, tenantId)))
+
+			stats, err := customerRepo.GetStats(customer.Id, tenantId)
+			assert.NoError(t, err)
+			assert.Equal(t, 30000, stats.LifetimeSpend)
+			assert.Equal(t, 2, stats.VisitCount)
+			assert.NotNil(t, stats.LastVisitAt)
+
+			invoices, count, err := orderItemRepo.Get(tenantId, 0, customer.Id, 10, 0, nil, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, count)
+			assert.Len(t, invoices, 2)
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+)
+
+var ErrRefundNotCompleted = errors.New("Only a COMPLETED order can be refunded")
+
+/*
+Gift card and store credit, scoped by tenant.
+Every balance change write a gift_card_movement inside the same transaction
+*/
+type GiftCardRepository interface {
+	/*
+		Insert new INACTIVE gift card
+	*/
+	Create(giftCard *model.GiftCard) (*model.GiftCard, error)
+
+	/*
+		Return 1 gift card by normalized code
+	*/
+	FindByCode(code string, tenantId int) (*model.GiftCard, error)
+
+	/*
+		Sell the gift card at storeId, the balance become the initial balance
+	*/
+	Activate(giftCardId, tenantId, storeId, userId int) (*model.GiftCard, error)
+
+	/*
+		Insert new ACTIVE store credit, orderItemId is the refunded invoice (optional).
+		ErrRefundNotCompleted when the invoice is not COMPLETED
+	*/
+	IssueStoreCredit(storeCredit *model.GiftCard, orderItemId *int, userId int, note string) (*model.GiftCard, error)
+
+	/*
+		Use the balance as payment of orderItemId, return the card after redemption
+	*/
+	Redeem(code string, tenantId, amount, orderItemId, storeId, userId int) (*model.GiftCard, error)
+
+	/*
+		Give back every redemption of orderItemId (voided invoice)
+	*/
+	ReverseOrderItem(orderItemId, tenantId int) error
+
+	/*
+		Get the movements of 1 card (newest first)
+		2nd params return is the count of all data
+	*/
+	GetMovements(giftCardId, tenantId, limit, page int) ([]*model.GiftCardMovement, int, error)
+
+	/*
+		Outstanding balance of every ACTIVE card, grouped by type
+	*/
+	GetLiability(tenantId int) ([]*model.GiftCardLiability, error)
+}
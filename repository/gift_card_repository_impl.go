@@ -0,0 +1,354 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const GiftCardTable string = "gift_card"
+const GiftCardMovementTable string = "gift_card_movement"
+
+type GiftCardRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewGiftCardRepositoryImpl(client *gorm.DB) GiftCardRepository {
+	return &GiftCardRepositoryImpl{Client: client}
+}
+
+// Create implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) Create(giftCard *model.GiftCard) (*model.GiftCard, error) {
+	giftCard.Type = model.GiftCardTypeGiftCard
+	giftCard.Status = model.GiftCardStatusInactive
+	giftCard.Balance = 0
+
+	if err := repository.Client.Create(giftCard).Error; err != nil {
+		return nil, err
+	}
+
+	return giftCard, nil
+}
+
+// FindByCode implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) FindByCode(code string, tenantId int) (*model.GiftCard, error) {
+	var giftCard model.GiftCard
+	err := repository.Client.
+		Where("tenant_id = ? AND code = ?", tenantId, code).
+		Take(&giftCard).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("No gift card found with code %s", code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &giftCard, nil
+}
+
+// Activate implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) Activate(giftCardId, tenantId, storeId, userId int) (*model.GiftCard, error) {
+	var giftCard model.GiftCard
+
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		var storeCount int64
+		if err := tx.Model(&model.Store{}).
+			Where("id = ? AND tenant_id = ?", storeId, tenantId).
+			Count(&storeCount).Error; err != nil {
+			return err
+		}
+		if storeCount == 0 {
+			return fmt.Errorf("Store %d not found for tenant %d", storeId, tenantId)
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", giftCardId, tenantId).
+			Take(&giftCard).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("No gift card found with id %d", giftCardId)
+		}
+		if err != nil {
+			return err
+		}
+
+		if giftCard.Status != model.GiftCardStatusInactive {
+			return fmt.Errorf("Gift card %s is already activated", giftCard.Code)
+		}
+
+		now := time.Now()
+		giftCard.Status = model.GiftCardStatusActive
+		giftCard.Balance = giftCard.InitialBalance
+		giftCard.ActivatedStoreId = &storeId
+		giftCard.ActivatedAt = &now
+		giftCard.UpdatedAt = &now
+
+		err = tx.Model(&model.GiftCard{}).
+			Where("id = ?", giftCard.Id).
+			Updates(map[string]any{
+				"status":             giftCard.Status,
+				"balance":            giftCard.Balance,
+				"activated_store_id": storeId,
+				"activated_at":       now,
+				"updated_at":         now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&model.GiftCardMovement{
+			TenantId:     tenantId,
+			GiftCardId:   giftCard.Id,
+			Type:         model.GiftCardMovementActivate,
+			Amount:       giftCard.Balance,
+			BalanceAfter: giftCard.Balance,
+			StoreId:      &storeId,
+			UserId:       userId,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &giftCard, nil
+}
+
+// IssueStoreCredit implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) IssueStoreCredit(storeCredit *model.GiftCard, orderItemId *int, userId int, note string) (*model.GiftCard, error) {
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		if storeCredit.CustomerId != nil {
+			var customerCount int64
+			if err := tx.Model(&model.Customer{}).
+				Where("id = ? AND tenant_id = ?", *storeCredit.CustomerId, storeCredit.TenantId).
+				Count(&customerCount).Error; err != nil {
+				return err
+			}
+			if customerCount == 0 {
+				return fmt.Errorf("Customer %d not found for tenant %d", *storeCredit.CustomerId, storeCredit.TenantId)
+			}
+		}
+
+		// Could not refund more than what has been paid
+		var storeId *int
+		if orderItemId != nil {
+			// Locked, so 2 refunds of the same invoice are checked one after the other
+			var orderItem model.OrderItem
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND tenant_id = ?", *orderItemId, storeCredit.TenantId).
+				Take(&orderItem).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("order item %d not found", *orderItemId)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Only a COMPLETED order was paid, a cancelled one already gave the money back
+			if orderItem.Status != model.OrderStatusCompleted {
+				return ErrRefundNotCompleted
+			}
+
+			var refunded int
+			err = tx.Model(&model.GiftCardMovement{}).
+				Select("COALESCE(SUM(amount), 0)").
+				Where("tenant_id = ? AND order_item_id = ? AND type = ?", storeCredit.TenantId, *orderItemId, model.GiftCardMovementIssue).
+				Scan(&refunded).Error
+			if err != nil {
+				return err
+			}
+			if refunded+storeCredit.InitialBalance > orderItem.TotalAmount {
+				return fmt.Errorf("Refund exceed the invoice total, already refunded %d of %d", refunded, orderItem.TotalAmount)
+			}
+
+			// Refund belong to the store of the invoice, store reports filter on it
+			storeId = &orderItem.StoreId
+		}
+
+		now := time.Now()
+		storeCredit.Type = model.GiftCardTypeStoreCredit
+		storeCredit.Status = model.GiftCardStatusActive
+		storeCredit.Balance = storeCredit.InitialBalance
+		storeCredit.ActivatedAt = &now
+		if err := tx.Create(storeCredit).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&model.GiftCardMovement{
+			TenantId:     storeCredit.TenantId,
+			GiftCardId:   storeCredit.Id,
+			Type:         model.GiftCardMovementIssue,
+			Amount:       storeCredit.Balance,
+			BalanceAfter: storeCredit.Balance,
+			OrderItemId:  orderItemId,
+			StoreId:      storeId,
+			UserId:       userId,
+			Note:         note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return storeCredit, nil
+}
+
+// Redeem implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) Redeem(code string, tenantId, amount, orderItemId, storeId, userId int) (*model.GiftCard, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("Invalid gift card amount: %d", amount)
+	}
+
+	var giftCard model.GiftCard
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND code = ?", tenantId, code).
+			Take(&giftCard).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("No gift card found with code %s", code)
+		}
+		if err != nil {
+			return err
+		}
+
+		if giftCard.Status != model.GiftCardStatusActive {
+			return fmt.Errorf("Gift card %s is not active", code)
+		}
+		if giftCard.Balance < amount {
+			return fmt.Errorf("Insufficient gift card balance, available %d, requested %d", giftCard.Balance, amount)
+		}
+
+		giftCard.Balance -= amount
+		err = tx.Model(&model.GiftCard{}).
+			Where("id = ?", giftCard.Id).
+			Updates(map[string]any{
+				"balance":    giftCard.Balance,
+				"updated_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&model.GiftCardMovement{
+			TenantId:     tenantId,
+			GiftCardId:   giftCard.Id,
+			Type:         model.GiftCardMovementRedeem,
+			Amount:       -amount,
+			BalanceAfter: giftCard.Balance,
+			OrderItemId:  &orderItemId,
+			StoreId:      &storeId,
+			UserId:       userId,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &giftCard, nil
+}
+
+// ReverseOrderItem implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) ReverseOrderItem(orderItemId, tenantId int) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		var movements []*model.GiftCardMovement
+		err := tx.Where("tenant_id = ? AND order_item_id = ?", tenantId, orderItemId).
+			Where("type IN ?", []model.GiftCardMovementType{model.GiftCardMovementRedeem, model.GiftCardMovementReversal}).
+			Order("id ASC").
+			Find(&movements).Error
+		if err != nil {
+			return err
+		}
+
+		for _, movement := range movements {
+			// Already reversed, nothing to do
+			if movement.Type == model.GiftCardMovementReversal {
+				return nil
+			}
+		}
+
+		for _, movement := range movements {
+			var giftCard model.GiftCard
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", movement.GiftCardId).
+				Take(&giftCard).Error
+			if err != nil {
+				return err
+			}
+
+			giftCard.Balance -= movement.Amount
+			err = tx.Model(&model.GiftCard{}).
+				Where("id = ?", giftCard.Id).
+				Updates(map[string]any{
+					"balance":    giftCard.Balance,
+					"updated_at": time.Now(),
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Create(&model.GiftCardMovement{
+				TenantId:     tenantId,
+				GiftCardId:   giftCard.Id,
+				Type:         model.GiftCardMovementReversal,
+				Amount:       -movement.Amount,
+				BalanceAfter: giftCard.Balance,
+				OrderItemId:  movement.OrderItemId,
+				StoreId:      movement.StoreId,
+				UserId:       movement.UserId,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetMovements implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) GetMovements(giftCardId, tenantId, limit, page int) ([]*model.GiftCardMovement, int, error) {
+	offset := page * limit
+
+	var movements = make([]*model.GiftCardMovement, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.GiftCardMovement{}).
+		Where("tenant_id = ? AND gift_card_id = ?", tenantId, giftCardId)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&movements).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return movements, int(totalCount), nil
+}
+
+// GetLiability implements GiftCardRepository.
+func (repository *GiftCardRepositoryImpl) GetLiability(tenantId int) ([]*model.GiftCardLiability, error) {
+	var liabilities = make([]*model.GiftCardLiability, 0)
+
+	err := repository.Client.Model(&model.GiftCard{}).
+		Select(`
+			type,
+			COUNT(id)                 AS card_count,
+			COALESCE(SUM(balance), 0) AS outstanding_balance
+		`).
+		Where("tenant_id = ? AND status = ?", tenantId, model.GiftCardStatusActive).
+		Group("type").
+		Order("type ASC").
+		Scan(&liabilities).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetLiability failed: %w", err)
+	}
+
+	return liabilities, nil
+}
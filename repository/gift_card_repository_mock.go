@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"cashier-api/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type GiftCardRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewGiftCardRepositoryMock(mock *mock.Mock) GiftCardRepository {
+	return &GiftCardRepositoryMock{Mock: mock}
+}
+
+// Create implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) Create(giftCard *model.GiftCard) (*model.GiftCard, error) {
+	args := repository.Mock.Called(giftCard)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GiftCard), nil
+}
+
+// FindByCode implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) FindByCode(code string, tenantId int) (*model.GiftCard, error) {
+	args := repository.Mock.Called(code, tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GiftCard), nil
+}
+
+// Activate implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) Activate(giftCardId, tenantId, storeId, userId int) (*model.GiftCard, error) {
+	args := repository.Mock.Called(giftCardId, tenantId, storeId, userId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GiftCard), nil
+}
+
+// IssueStoreCredit implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) IssueStoreCredit(storeCredit *model.GiftCard, orderItemId *int, userId int, note string) (*model.GiftCard, error) {
+	args := repository.Mock.Called(storeCredit, orderItemId, userId, note)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GiftCard), nil
+}
+
+// Redeem implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) Redeem(code string, tenantId, amount, orderItemId, storeId, userId int) (*model.GiftCard, error) {
+	args := repository.Mock.Called(code, tenantId, amount, orderItemId, storeId, userId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.GiftCard), nil
+}
+
+// ReverseOrderItem implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) ReverseOrderItem(orderItemId, tenantId int) error {
+	args := repository.Mock.Called(orderItemId, tenantId)
+	return args.Error(0)
+}
+
+// GetMovements implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) GetMovements(giftCardId, tenantId, limit, page int) ([]*model.GiftCardMovement, int, error) {
+	args := repository.Mock.Called(giftCardId, tenantId, limit, page)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.GiftCardMovement), args.Int(1), nil
+}
+
+// GetLiability implements GiftCardRepository.
+func (repository *GiftCardRepositoryMock) GetLiability(tenantId int) ([]*model.GiftCardLiability, error) {
+	args := repository.Mock.Called(tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*model.GiftCardLiability), nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGiftCardRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	// seed tenant, store, owner and 1 invoice
+	seed := func(t *testing.T, tx *gorm.DB) (tenantId int, storeId int, userId int, orderItem *model.OrderItem) {
+		tenantId, storeId = seedOrderItemTestDependencies(t, tx)
+
+		var tenant model.Tenant
+		require.NoError(t, tx.Where("id = ?", tenantId).Take(&tenant).Error)
+
+		orderItem, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 100_000,
+			TotalQuantity:  1,
+			TotalAmount:    100_000,
+			Subtotal:       100_000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+		})
+		require.NoError(t, err)
+
+		return tenantId, storeId, tenant.OwnerUserId, orderItem
+	}
+
+	t.Run("ActivateAndRedeem", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId, userId, orderItem := seed(t, tx)
+		giftCardRepo := NewGiftCardRepositoryImpl(tx)
+
+		giftCard, err := giftCardRepo.Create(&model.GiftCard{TenantId: tenantId, Code: "REPOTEST2345", InitialBalance: 100_000})
+		require.NoError(t, err)
+		assert.Equal(t, model.GiftCardStatusInactive, giftCard.Status)
+		assert.Equal(t, 0, giftCard.Balance)
+
+		// Inactive card could not be used
+		_, err = giftCardRepo.Redeem("REPOTEST2345", tenantId, 1_000, orderItem.Id, storeId, userId)
+		assert.Error(t, err)
+
+		activatedGiftCard, err := giftCardRepo.Activate(giftCard.Id, tenantId, storeId, userId)
+		require.NoError(t, err)
+		assert.Equal(t, 100_000, activatedGiftCard.Balance)
+
+		// Activate twice is not allowed
+		_, err = giftCardRepo.Activate(giftCard.Id, tenantId, storeId, userId)
+		assert.Error(t, err)
+
+		redeemedGiftCard, err := giftCardRepo.Redeem("REPOTEST2345", tenantId, 60_000, orderItem.Id, storeId, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, 40_000, redeemedGiftCard.Balance)
+
+		_, err = giftCardRepo.Redeem("REPOTEST2345", tenantId, 40_001, orderItem.Id, storeId, userId)
+		assert.Error(t, err)
+
+		movements, count, err := giftCardRepo.GetMovements(giftCard.Id, tenantId, 10, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, model.GiftCardMovementRedeem, movements[0].Type)
+		assert.Equal(t, 40_000, movements[0].BalanceAfter)
+
+		// Voiding the invoice give the balance back
+		require.NoError(t, NewOrderItemRepositoryImpl(tx).DeleteInvoice(orderItem.Id, tenantId, userId))
+		foundGiftCard, err := giftCardRepo.FindByCode("REPOTEST2345", tenantId)
+		assert.NoError(t, err)
+		assert.Equal(t, 100_000, foundGiftCard.Balance)
+	})
+
+	t.Run("IssueStoreCredit", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _, userId, orderItem := seed(t, tx)
+		giftCardRepo := NewGiftCardRepositoryImpl(tx)
+
+		storeCredit, err := giftCardRepo.IssueStoreCredit(&model.GiftCard{TenantId: tenantId, Code: "CREDIT234567", InitialBalance: 70_000}, &orderItem.Id, userId, "Refund")
+		assert.NoError(t, err)
+		assert.Equal(t, model.GiftCardTypeStoreCredit, storeCredit.Type)
+		assert.Equal(t, model.GiftCardStatusActive, storeCredit.Status)
+
+		// Could not refund more than the invoice total
+		_, err = giftCardRepo.IssueStoreCredit(&model.GiftCard{TenantId: tenantId, Code: "CREDIT234568", InitialBalance: 30_001}, &orderItem.Id, userId, "Refund")
+		assert.Error(t, err)
+
+		// Not paid yet, or already given back by the cancel
+		for _, status := range []model.OrderStatus{model.OrderStatusPlaced, model.OrderStatusCancelled} {
+			notCompleted, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+				PurchasedPrice: 50_000,
+				TotalQuantity:  1,
+				TotalAmount:    50_000,
+				Subtotal:       50_000,
+				TenantId:       tenantId,
+				StoreId:        orderItem.StoreId,
+				Status:         status,
+			})
+			require.NoError(t, err)
+			_, err = giftCardRepo.IssueStoreCredit(&model.GiftCard{TenantId: tenantId, Code: "CREDIT23456" + string(status[0]), InitialBalance: 10_000}, &notCompleted.Id, userId, "Refund")
+			assert.ErrorIs(t, err, ErrRefundNotCompleted)
+		}
+
+		liabilities, err := giftCardRepo.GetLiability(tenantId)
+		assert.NoError(t, err)
+		require.Len(t, liabilities, 1)
+		assert.Equal(t, model.GiftCardTypeStoreCredit, liabilities[0].Type)
+		assert.Equal(t, 70_000, liabilities[0].OutstandingBalance)
+	})
+}
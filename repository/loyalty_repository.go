@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+)
+
+/*
+Loyalty points, scoped by tenant.
+
+	Every method that write more than 1 row is wrapped with transaction,
+	when the repository is created from a running transaction (tx)
+	it will become a nested transaction (savepoint).
+*/
+type LoyaltyRepository interface {
+	/*
+		Return the tenant setting, when never configured return
+		an inactive setting (not an error)
+	*/
+	GetSetting(tenantId int) (*model.LoyaltySetting, error)
+
+	/*
+		Insert or update the tenant setting (1 row per tenant)
+	*/
+	SaveSetting(setting *model.LoyaltySetting) (*model.LoyaltySetting, error)
+
+	/*
+		Available points, expired points is excluded
+	*/
+	GetBalance(customerId, tenantId int) (int, error)
+
+	/*
+		Get the ledger of 1 customer (newest first)
+		2nd params return is the count of all data
+	*/
+	GetLedger(customerId, tenantId, limit, page int) ([]*model.LoyaltyLedger, int, error)
+
+	/*
+		Give points for the amount spent at orderItemId, following the setting.
+		Return the earned points (0 will not write anything)
+	*/
+	Earn(setting *model.LoyaltySetting, customerId, orderItemId, amount int) (int, error)
+
+	/*
+		Spend points for orderItemId, the points that expire first is used first
+	*/
+	Redeem(tenantId, customerId, orderItemId, points int) error
+
+	/*
+		Reverse every points earned / redeemed by orderItemId (voided invoice)
+	*/
+	ReverseOrderItem(orderItemId, tenantId int) error
+
+	/*
+		Write EXPIRE entry for every expired points (all tenant),
+		return the sum of expired points
+	*/
+	ExpirePoints(now time.Time) (int, error)
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const LoyaltySettingTable string = "loyalty_setting"
+const LoyaltyLedgerTable string = "loyalty_ledger"
+
+type LoyaltyRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewLoyaltyRepositoryImpl(client *gorm.DB) LoyaltyRepository {
+	return &LoyaltyRepositoryImpl{Client: client}
+}
+
+// GetSetting implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) GetSetting(tenantId int) (*model.LoyaltySetting, error) {
+	var setting model.LoyaltySetting
+	err := repository.Client.
+		Where("tenant_id = ?", tenantId).
+		Take(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.LoyaltySetting{
+			TenantId: tenantId,
+			Rounding: model.LoyaltyRoundingFloor,
+			IsActive: false,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+// SaveSetting implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) SaveSetting(setting *model.LoyaltySetting) (*model.LoyaltySetting, error) {
+	now := time.Now()
+	setting.Id = 0
+	setting.UpdatedAt = &now
+
+	err := repository.Client.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"earn_amount",
+			"earn_points",
+			"rounding",
+			"redeem_value",
+			"expiry_months",
+			"is_active",
+			"updated_at",
+		}),
+	}).Create(setting).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.GetSetting(setting.TenantId)
+}
+
+// GetBalance implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) GetBalance(customerId, tenantId int) (int, error) {
+	var balance int
+	err := repository.Client.Model(&model.LoyaltyLedger{}).
+		Select("COALESCE(SUM(remaining_points), 0)").
+		Where("tenant_id = ? AND customer_id = ?", tenantId, customerId).
+		Where("remaining_points > 0").
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, fmt.Errorf("GetBalance failed: %w", err)
+	}
+
+	return balance, nil
+}
+
+// GetLedger implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) GetLedger(customerId, tenantId, limit, page int) ([]*model.LoyaltyLedger, int, error) {
+	offset := page * limit
+
+	var entries = make([]*model.LoyaltyLedger, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.LoyaltyLedger{}).
+		Where("tenant_id = ? AND customer_id = ?", tenantId, customerId)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, int(totalCount), nil
+}
+
+// Earn implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) Earn(setting *model.LoyaltySetting, customerId, orderItemId, amount int) (int, error) {
+	if !setting.IsActive {
+		return 0, nil
+	}
+
+	points := setting.PointsFor(amount)
+	if points <= 0 {
+		return 0, nil
+	}
+
+	entry := &model.LoyaltyLedger{
+		TenantId:        setting.TenantId,
+		CustomerId:      customerId,
+		OrderItemId:     &orderItemId,
+		Type:            model.LoyaltyLedgerEarn,
+		Points:          points,
+		RemainingPoints: points,
+		ExpiresAt:       setting.ExpiresAt(time.Now()),
+	}
+	if err := repository.Client.Create(entry).Error; err != nil {
+		return 0, err
+	}
+
+	return points, nil
+}
+
+// Redeem implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) Redeem(tenantId, customerId, orderItemId, points int) error {
+	if points <= 0 {
+		return fmt.Errorf("Invalid redeemed points: %d", points)
+	}
+
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		consumed, err := consumePoints(tx, tenantId, customerId, points)
+		if err != nil {
+			return err
+		}
+		if consumed < points {
+			return fmt.Errorf("Insufficient points, available %d, requested %d", consumed, points)
+		}
+
+		return tx.Create(&model.LoyaltyLedger{
+			TenantId:    tenantId,
+			CustomerId:  customerId,
+			OrderItemId: &orderItemId,
+			Type:        model.LoyaltyLedgerRedeem,
+			Points:      -points,
+		}).Error
+	})
+}
+
+// ReverseOrderItem implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) ReverseOrderItem(orderItemId, tenantId int) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		var entries []*model.LoyaltyLedger
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND order_item_id = ?", tenantId, orderItemId).
+			Order("id ASC").
+			Find(&entries).Error
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			// Already reversed, nothing to do
+			if entry.Type == model.LoyaltyLedgerReversal {
+				return nil
+			}
+		}
+
+		for _, entry := range entries {
+			switch entry.Type {
+			case model.LoyaltyLedgerEarn:
+				// Take back from the same entry first, the rest from other points
+				taken := min(entry.RemainingPoints, entry.Points)
+				if taken > 0 {
+					err := tx.Model(&model.LoyaltyLedger{}).
+						Where("id = ?", entry.Id).
+						Update("remaining_points", entry.RemainingPoints-taken).Error
+					if err != nil {
+						return err
+					}
+				}
+
+				if taken < entry.Points {
+					consumed, err := consumePoints(tx, tenantId, entry.CustomerId, entry.Points-taken)
+					if err != nil {
+						return err
+					}
+					taken += consumed
+				}
+
+				if taken < entry.Points {
+					// Customer already spent it, the balance never goes below 0
+					log.Warnf("Warning ! only %d of %d earned points reversed for order item %d, tenant %d", taken, entry.Points, orderItemId, tenantId)
+				}
+
+				if taken > 0 {
+					if err := createReversal(tx, entry, -taken, 0, nil); err != nil {
+						return err
+					}
+				}
+
+			case model.LoyaltyLedgerRedeem:
+				// Give the points back as a new credit
+				setting, err := NewLoyaltyRepositoryImpl(tx).GetSetting(tenantId)
+				if err != nil {
+					return err
+				}
+
+				if err := createReversal(tx, entry, -entry.Points, -entry.Points, setting.ExpiresAt(time.Now())); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// ExpirePoints implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryImpl) ExpirePoints(now time.Time) (int, error) {
+	expired := 0
+
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		var credits []*model.LoyaltyLedger
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("remaining_points > 0 AND expires_at <= ?", now).
+			Find(&credits).Error
+		if err != nil {
+			return err
+		}
+
+		for _, credit := range credits {
+			err := tx.Model(&model.LoyaltyLedger{}).
+				Where("id = ?", credit.Id).
+				Update("remaining_points", 0).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Create(&model.LoyaltyLedger{
+				TenantId:   credit.TenantId,
+				CustomerId: credit.CustomerId,
+				Type:       model.LoyaltyLedgerExpire,
+				Points:     -credit.RemainingPoints,
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			expired += credit.RemainingPoints
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
+}
+
+/*
+consumePoints decrease remaining_points from the credit that expire first.
+Return the consumed points, may be less than requested when not enough
+*/
+func consumePoints(tx *gorm.DB, tenantId, customerId, points int) (int, error) {
+	var credits []*model.LoyaltyLedger
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND customer_id = ?", tenantId, customerId).
+		Where("remaining_points > 0").
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("expires_at ASC NULLS LAST").
+		Order("id ASC").
+		Find(&credits).Error
+	if err != nil {
+		return 0, err
+	}
+
+	consumed := 0
+	for _, credit := range credits {
+		if consumed == points {
+			break
+		}
+
+		used := min(credit.RemainingPoints, points-consumed)
+		err := tx.Model(&model.LoyaltyLedger{}).
+			Where("id = ?", credit.Id).
+			Update("remaining_points", credit.RemainingPoints-used).Error
+		if err != nil {
+			return 0, err
+		}
+		consumed += used
+	}
+
+	return consumed, nil
+}
+
+func createReversal(tx *gorm.DB, reversed *model.LoyaltyLedger, points, remainingPoints int, expiresAt *time.Time) error {
+	return tx.Create(&model.LoyaltyLedger{
+		TenantId:        reversed.TenantId,
+		CustomerId:      reversed.CustomerId,
+		OrderItemId:     reversed.OrderItemId,
+		Type:            model.LoyaltyLedgerReversal,
+		Points:          points,
+		RemainingPoints: remainingPoints,
+		ExpiresAt:       expiresAt,
+	}).Error
+}
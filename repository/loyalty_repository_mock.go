@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LoyaltyRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewLoyaltyRepositoryMock(mock *mock.Mock) LoyaltyRepository {
+	return &LoyaltyRepositoryMock{Mock: mock}
+}
+
+// GetSetting implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) GetSetting(tenantId int) (*model.LoyaltySetting, error) {
+	args := repository.Mock.Called(tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.LoyaltySetting), nil
+}
+
+// SaveSetting implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) SaveSetting(setting *model.LoyaltySetting) (*model.LoyaltySetting, error) {
+	args := repository.Mock.Called(setting)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.LoyaltySetting), nil
+}
+
+// GetBalance implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) GetBalance(customerId, tenantId int) (int, error) {
+	args := repository.Mock.Called(customerId, tenantId)
+	return args.Int(0), args.Error(1)
+}
+
+// GetLedger implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) GetLedger(customerId, tenantId, limit, page int) ([]*model.LoyaltyLedger, int, error) {
+	args := repository.Mock.Called(customerId, tenantId, limit, page)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.LoyaltyLedger), args.Int(1), nil
+}
+
+// Earn implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) Earn(setting *model.LoyaltySetting, customerId, orderItemId, amount int) (int, error) {
+	args := repository.Mock.Called(setting, customerId, orderItemId, amount)
+	return args.Int(0), args.Error(1)
+}
+
+// Redeem implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) Redeem(tenantId, customerId, orderItemId, points int) error {
+	args := repository.Mock.Called(tenantId, customerId, orderItemId, points)
+	return args.Error(0)
+}
+
+// ReverseOrderItem implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) ReverseOrderItem(orderItemId, tenantId int) error {
+	args := repository.Mock.Called(orderItemId, tenantId)
+	return args.Error(0)
+}
+
+// ExpirePoints implements LoyaltyRepository.
+func (repository *LoyaltyRepositoryMock) ExpirePoints(now time.Time) (int, error) {
+	args := repository.Mock.Called(now)
+	return args.Int(0), args.Error(1)
+}
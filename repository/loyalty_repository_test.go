@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLoyaltyRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	// seed tenant, store, customer, active loyalty setting and 1 invoice of the customer
+	seed := func(t *testing.T, tx *gorm.DB) (*model.LoyaltySetting, *model.Customer, *model.OrderItem) {
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+
+		customer, err := NewCustomerRepositoryImpl(tx).Create(&model.Customer{Name: "Loyalty", Phone: "081288888888", TenantId: tenantId})
+		require.NoError(t, err)
+
+		setting, err := NewLoyaltyRepositoryImpl(tx).SaveSetting(&model.LoyaltySetting{
+			TenantId:     tenantId,
+			EarnAmount:   10_000,
+			EarnPoints:   1,
+			Rounding:     model.LoyaltyRoundingFloor,
+			RedeemValue:  100,
+			ExpiryMonths: 12,
+			IsActive:     true,
+		})
+		require.NoError(t, err)
+
+		orderItem, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 100_000,
+			TotalQuantity:  1,
+			TotalAmount:    100_000,
+			Subtotal:       100_000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+			CustomerId:     &customer.Id,
+		})
+		require.NoError(t, err)
+
+		return setting, customer, orderItem
+	}
+
+	t.Run("Setting", func(t *testing.T) {
+		t.Run("NeverConfigured", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, _ := seedOrderItemTestDependencies(t, tx)
+			setting, err := NewLoyaltyRepositoryImpl(tx).GetSetting(tenantId)
+			assert.NoError(t, err)
+			assert.False(t, setting.IsActive)
+		})
+
+		t.Run("SaveTwice", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			setting, _, _ := seed(t, tx)
+			loyaltyRepo := NewLoyaltyRepositoryImpl(tx)
+
+			setting.EarnPoints = 2
+			savedSetting, err := loyaltyRepo.SaveSetting(setting)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, savedSetting.EarnPoints)
+
+			var count int64
+			require.NoError(t, tx.Model(&model.LoyaltySetting{}).Where("tenant_id = ?", setting.TenantId).Count(&count).Error)
+			assert.Equal(t, int64(1), count)
+		})
+	})
+
+	t.Run("EarnAndRedeem", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		setting, customer, orderItem := seed(t, tx)
+		loyaltyRepo := NewLoyaltyRepositoryImpl(tx)
+
+		earned, err := loyaltyRepo.Earn(setting, customer.Id, orderItem.Id, 105_000)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, earned)
+
+		require.NoError(t, loyaltyRepo.Redeem(setting.TenantId, customer.Id, orderItem.Id, 4))
+
+		balance, err := loyaltyRepo.GetBalance(customer.Id, setting.TenantId)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, balance)
+
+		// Could not spend more than the balance
+		err = loyaltyRepo.Redeem(setting.TenantId, customer.Id, orderItem.Id, 7)
+		assert.Error(t, err)
+
+		entries, count, err := loyaltyRepo.GetLedger(customer.Id, setting.TenantId, 10, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("ReverseOrderItem", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		setting, customer, orderItem := seed(t, tx)
+		loyaltyRepo := NewLoyaltyRepositoryImpl(tx)
+
+		// 5 points from another sale
+		otherOrderItem, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 50_000,
+			TotalQuantity:  1,
+			TotalAmount:    50_000,
+			Subtotal:       50_000,
+			TenantId:       setting.TenantId,
+			StoreId:        orderItem.StoreId,
+			CustomerId:     &customer.Id,
+		})
+		require.NoError(t, err)
+		_, err = loyaltyRepo.Earn(setting, customer.Id, otherOrderItem.Id, 50_000)
+		require.NoError(t, err)
+
+		require.NoError(t, loyaltyRepo.Redeem(setting.TenantId, customer.Id, orderItem.Id, 3))
+		_, err = loyaltyRepo.Earn(setting, customer.Id, orderItem.Id, 100_000)
+		require.NoError(t, err)
+
+		balance, err := loyaltyRepo.GetBalance(customer.Id, setting.TenantId)
+		require.NoError(t, err)
+		require.Equal(t, 12, balance)
+
+		require.NoError(t, NewOrderItemRepositoryImpl(tx).DeleteInvoice(orderItem.Id, setting.TenantId, tenantOwnerId(t, tx, setting.TenantId)))
+
+		// 10 earned taken back, 3 redeemed given back
+		balance, err = loyaltyRepo.GetBalance(customer.Id, setting.TenantId)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, balance)
+
+		// Reversing twice change nothing
+		require.NoError(t, loyaltyRepo.ReverseOrderItem(orderItem.Id, setting.TenantId))
+		balance, err = loyaltyRepo.GetBalance(customer.Id, setting.TenantId)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, balance)
+	})
+
+	t.Run("ExpirePoints", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		setting, customer, orderItem := seed(t, tx)
+		loyaltyRepo := NewLoyaltyRepositoryImpl(tx)
+
+		_, err := loyaltyRepo.Earn(setting, customer.Id, orderItem.Id, 100_000)
+		require.NoError(t, err)
+
+		// Not expired yet
+		expired, err := loyaltyRepo.ExpirePoints(time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, expired)
+
+		expired, err = loyaltyRepo.ExpirePoints(time.Now().AddDate(1, 0, 1))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, expired, 10)
+
+		balance, err := loyaltyRepo.GetBalance(customer.Id, setting.TenantId)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, balance)
+	})
+}
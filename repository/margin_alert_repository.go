@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+)
+
+/*
+Margin alert compare the selling price with the warehouse base price,
+soft deleted (and cancelled) order never counted
+*/
+type MarginAlertRepository interface {
+	/*
+		Return the tenant setting, when never configured return
+		an inactive setting with the default threshold (not an error)
+	*/
+	GetSetting(tenantId int) (*model.MarginAlertSetting, error)
+
+	/*
+		Insert or update the tenant setting (1 row per tenant)
+	*/
+	SaveSetting(setting *model.MarginAlertSetting) (*model.MarginAlertSetting, error)
+
+	/*
+		Store stock priced below the warehouse base price, every sale of it is a loss.
+		Biggest loss per unit first, storeId = 0 will not filter.
+		2nd params return is the count of all data
+	*/
+	GetBelowCostStocks(tenantId, storeId, limit, page int) ([]*BelowCostStockRow, int, error)
+
+	/*
+		Items sold since the given time with a realised margin below minMarginPercent,
+		lowest margin first. storeId = 0 will not filter
+	*/
+	GetLowMarginItems(tenantId, storeId int, since time.Time, minMarginPercent float64) ([]*LowMarginItemRow, error)
+
+	/*
+		Invoice lines of the given items sold since the given time with a margin below minMarginPercent.
+		Newest first, at most perItemLimit lines per item (LineCount is the count of all of them)
+	*/
+	GetLowMarginInvoices(tenantId, storeId int, since time.Time, minMarginPercent float64, itemIds []int, perItemLimit int) ([]*LowMarginInvoiceRow, error)
+
+	/*
+		Mark every active setting not alerted for the interval as alerted at now and return them,
+		so 2 instances of the API never send the same alert
+	*/
+	ClaimDueAlerts(now time.Time, interval time.Duration) ([]*model.MarginAlertSetting, error)
+}
+
+type BelowCostStockRow struct {
+	StoreId       int     `json:"store_id"       gorm:"column:store_id"`
+	StoreName     string  `json:"store_name"     gorm:"column:store_name"`
+	ItemId        int     `json:"item_id"        gorm:"column:item_id"`
+	ItemName      string  `json:"item_name"      gorm:"column:item_name"`
+	Stocks        int     `json:"stocks"         gorm:"column:stocks"`
+	Price         int     `json:"price"          gorm:"column:price"`
+	BasePrice     int     `json:"base_price"     gorm:"column:base_price"`
+	UnitLoss      int     `json:"unit_loss"      gorm:"column:unit_loss"` // base price - price
+	MarginPercent float64 `json:"margin_percent" gorm:"-"`                // filled by service
+}
+
+type LowMarginItemRow struct {
+	ItemId        int                    `json:"item_id"        gorm:"column:item_id"`
+	ItemName      string                 `json:"item_name"      gorm:"column:item_name"`
+	TotalQuantity int                    `json:"total_quantity" gorm:"column:total_quantity"`
+	TotalRevenue  int                    `json:"total_revenue"  gorm:"column:total_revenue"`
+	TotalCogs     int                    `json:"total_cogs"     gorm:"column:total_cogs"`
+	TotalProfit   int                    `json:"total_profit"   gorm:"column:total_profit"`
+	MarginPercent float64                `json:"margin_percent" gorm:"-"` // filled by service
+	InvoiceCount  int                    `json:"invoice_count"  gorm:"-"` // lines below the threshold, filled by service
+	Invoices      []*LowMarginInvoiceRow `json:"invoices"       gorm:"-"` // the newest of them, filled by service
+}
+
+// LowMarginInvoiceRow is 1 line of purchased_item_list, OrderItemId is the invoice
+type LowMarginInvoiceRow struct {
+	OrderItemId   int       `json:"order_item_id"  gorm:"column:order_item_id"`
+	StoreId       int       `json:"store_id"       gorm:"column:store_id"`
+	ItemId        int       `json:"item_id"        gorm:"column:item_id"`
+	CreatedAt     time.Time `json:"created_at"     gorm:"column:created_at"`
+	Quantity      int       `json:"quantity"       gorm:"column:quantity"`
+	TotalAmount   int       `json:"total_amount"   gorm:"column:total_amount"`
+	Cogs          int       `json:"cogs"           gorm:"column:cogs"`
+	Profit        int       `json:"profit"         gorm:"column:profit"`
+	LineCount     int       `json:"-"              gorm:"column:line_count"`
+	MarginPercent float64   `json:"margin_percent" gorm:"-"` // filled by service
+}
+
+type LowMarginReport struct {
+	MinMarginPercent float64             `json:"min_margin_percent"`
+	WindowDays       int                 `json:"window_days"`
+	Since            time.Time           `json:"since"`
+	Items            []*LowMarginItemRow `json:"items"` // Lowest margin first
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MarginAlertRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewMarginAlertRepositoryImpl(client *gorm.DB) MarginAlertRepository {
+	return &MarginAlertRepositoryImpl{Client: client}
+}
+
+// GetSetting implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryImpl) GetSetting(tenantId int) (*model.MarginAlertSetting, error) {
+	var setting model.MarginAlertSetting
+	err := repository.Client.
+		Where("tenant_id = ?", tenantId).
+		Take(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.MarginAlertSetting{
+			TenantId:         tenantId,
+			MinMarginPercent: model.MarginAlertDefaultMinMarginPercent,
+			WindowDays:       model.MarginAlertDefaultWindowDays,
+			IsActive:         false,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+// SaveSetting implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryImpl) SaveSetting(setting *model.MarginAlertSetting) (*model.MarginAlertSetting, error) {
+	now := time.Now()
+	setting.Id = 0
+	setting.UpdatedAt = &now
+
+	err := repository.Client.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"min_margin_percent",
+			"window_days",
+			"is_active",
+			"recipients",
+			"updated_at",
+		}),
+	}).Create(setting).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.GetSetting(setting.TenantId)
+}
+
+// GetBelowCostStocks implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryImpl) GetBelowCostStocks(tenantId, storeId, limit, page int) ([]*BelowCostStockRow, int, error) {
+	offset := page * limit
+
+	db := repository.Client.Table("store_stock ss").
+		Select(`
+			ss.store_id,
+			s.name AS store_name,
+			w.item_id,
+			w.item_name,
+			ss.stocks,
+			ss.price,
+			w.base_price,
+			w.base_price - ss.price AS unit_loss
+		`).
+		Joins("INNER JOIN warehouse w ON w.item_id = ss.item_id").
+		Joins("INNER JOIN store s ON s.id = ss.store_id").
+		Where("ss.tenant_id = ? AND ss.price < w.base_price", tenantId)
+	if storeId > 0 {
+		db = db.Where("ss.store_id = ?", storeId)
+	}
+
+	var totalCount int64
+	if err := db.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("GetBelowCostStocks count failed: %w", err)
+	}
+
+	var rows = make([]*BelowCostStockRow, 0)
+	err := db.
+		Order("unit_loss DESC").
+		Order("ss.store_id ASC").
+		Order("w.item_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetBelowCostStocks failed: %w", err)
+	}
+
+	return rows, int(totalCount), nil
+}
+
+// soldLinesSince join the invoice of every line sold since the given time
+func (repository *MarginAlertRepositoryImpl) soldLinesSince(tenantId, storeId int, since time.Time) *gorm.DB {
+	db := repository.Client.Table("purchased_item_list pil").
+		Joins(completedSalesJoin).
+		Where("oi.tenant_id = ? AND oi.completed_at >= ?", tenantId, since)
+	if storeId > 0 {
+		db = db.Where("oi.store_id = ?", storeId)
+	}
+	return db
+}
+
+// GetLowMarginItems implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryImpl) GetLowMarginItems(tenantId, storeId int, since time.Time, minMarginPercent float64) ([]*LowMarginItemRow, error) {
+	// profit * 100 < min * revenue, so an item without revenue but with cost is flagged too
+	var rows = make([]*LowMarginItemRow, 0)
+	err := repository.soldLinesSince(tenantId, storeId, since).
+		Select(`
+			pil.item_id,
+			MAX(pil.item_name_snapshot) AS item_name,
+			SUM(pil.quantity) AS total_quantity,
+			SUM(`+lineRevenue+`) AS total_revenue,
+			SUM(pil.base_price_snapshot * pil.quantity) AS total_cogs,
+			SUM(`+lineRevenue+`) - SUM(pil.base_price_snapshot * pil.quantity) AS total_profit
+		`).
+		Group("pil.item_id").
+		Having("(SUM("+lineRevenue+") - SUM(pil.base_price_snapshot * pil.quantity)) * 100.0 < ? * SUM("+lineRevenue+")", minMarginPercent).
+		Order("(SUM(" + lineRevenue + ") - SUM(pil.base_price_snapshot * pil.quantity)) * 1.0 / NULLIF(SUM(" + lineRevenue + "), 0) ASC NULLS FIRST").
+		Order("pil.item_id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetLowMarginItems failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetLowMarginInvoices implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryImpl) GetLowMarginInvoices(
+	tenantId, storeId int,
+	since time.Time,
+	minMarginPercent float64,
+	itemIds []int,
+	perItemLimit int,
+) ([]*LowMarginInvoiceRow, error) {
+	var rows = make([]*LowMarginInvoiceRow, 0)
+	if len(itemIds) == 0 {
+		return rows, nil
+	}
+
+	lines := repository.soldLinesSince(tenantId, storeId, since).
+		Select(`
+			pil.order_item_id,
+			oi.store_id,
+			pil.item_id,
+			oi.completed_at AS created_at,
+			pil.quantity,
+			`+lineRevenue+` AS total_amount,
+			pil.base_price_snapshot * pil.quantity AS cogs,
+			`+lineRevenue+` - pil.base_price_snapshot * pil.quantity AS profit,
+			COUNT(*) OVER (PARTITION BY pil.item_id) AS line_count,
+			ROW_NUMBER() OVER (PARTITION BY pil.item_id ORDER BY oi.completed_at DESC, pil.id DESC) AS line_number
+		`).
+		Where("pil.item_id IN ?", itemIds).
+		Where("("+lineRevenue+" - pil.base_price_snapshot * pil.quantity) * 100.0 < ? * "+lineRevenue, minMarginPercent)
+
+	err := repository.Client.Table("(?) l", lines).
+		Where("l.line_number <= ?", perItemLimit).
+		Order("l.item_id ASC").
+		Order("l.line_number ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetLowMarginInvoices failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ClaimDueAlerts implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryImpl) ClaimDueAlerts(now time.Time, interval time.Duration) ([]*model.MarginAlertSetting, error) {
+	// 1 statement, the row lock of UPDATE make the claim atomic
+	var settings = make([]*model.MarginAlertSetting, 0)
+	err := repository.Client.Model(&settings).
+		Clauses(clause.Returning{}).
+		Where("is_active = TRUE").
+		Where("last_alerted_at IS NULL OR last_alerted_at <= ?", now.Add(-interval)).
+		UpdateColumn("last_alerted_at", now).Error
+	if err != nil {
+		return nil, fmt.Errorf("ClaimDueAlerts failed: %w", err)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MarginAlertRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewMarginAlertRepositoryMock(mock *mock.Mock) MarginAlertRepository {
+	return &MarginAlertRepositoryMock{Mock: mock}
+}
+
+// GetSetting implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryMock) GetSetting(tenantId int) (*model.MarginAlertSetting, error) {
+	args := repository.Mock.Called(tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.MarginAlertSetting), nil
+}
+
+// SaveSetting implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryMock) SaveSetting(setting *model.MarginAlertSetting) (*model.MarginAlertSetting, error) {
+	args := repository.Mock.Called(setting)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.MarginAlertSetting), nil
+}
+
+// GetBelowCostStocks implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryMock) GetBelowCostStocks(tenantId, storeId, limit, page int) ([]*BelowCostStockRow, int, error) {
+	args := repository.Mock.Called(tenantId, storeId, limit, page)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*BelowCostStockRow), args.Int(1), nil
+}
+
+// GetLowMarginItems implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryMock) GetLowMarginItems(tenantId, storeId int, since time.Time, minMarginPercent float64) ([]*LowMarginItemRow, error) {
+	args := repository.Mock.Called(tenantId, storeId, since, minMarginPercent)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*LowMarginItemRow), nil
+}
+
+// GetLowMarginInvoices implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryMock) GetLowMarginInvoices(
+	tenantId, storeId int,
+	since time.Time,
+	minMarginPercent float64,
+	itemIds []int,
+	perItemLimit int,
+) ([]*LowMarginInvoiceRow, error) {
+	args := repository.Mock.Called(tenantId, storeId, since, minMarginPercent, itemIds, perItemLimit)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*LowMarginInvoiceRow), nil
+}
+
+// ClaimDueAlerts implements MarginAlertRepository.
+func (repository *MarginAlertRepositoryMock) ClaimDueAlerts(now time.Time, interval time.Duration) ([]*model.MarginAlertSetting, error) {
+	args := repository.Mock.Called(now, interval)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*model.MarginAlertSetting), nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMarginAlertRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("Setting", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		repo := NewMarginAlertRepositoryImpl(tx)
+
+		// Never configured
+		setting, err := repo.GetSetting(tenantId)
+		require.NoError(t, err)
+		assert.Zero(t, setting.Id)
+		assert.Equal(t, model.MarginAlertDefaultWindowDays, setting.WindowDays)
+		assert.False(t, setting.IsActive)
+
+		_, err = repo.SaveSetting(&model.MarginAlertSetting{TenantId: tenantId, MinMarginPercent: 5, WindowDays: 7})
+		require.NoError(t, err)
+		setting, err = repo.SaveSetting(&model.MarginAlertSetting{
+			TenantId:         tenantId,
+			MinMarginPercent: 12.5,
+			WindowDays:       14,
+			IsActive:         true,
+			Recipients:       "owner@example.com",
+		})
+		require.NoError(t, err)
+		assert.NotZero(t, setting.Id)
+		assert.Equal(t, 12.5, setting.MinMarginPercent)
+		assert.Equal(t, 14, setting.WindowDays)
+
+		// Claimed once per interval
+		now := time.Now()
+		claimed, err := repo.ClaimDueAlerts(now, time.Hour*24)
+		require.NoError(t, err)
+		assert.Contains(t, claimedTenants(claimed), tenantId)
+
+		claimed, err = repo.ClaimDueAlerts(now.Add(time.Hour), time.Hour*24)
+		require.NoError(t, err)
+		assert.NotContains(t, claimedTenants(claimed), tenantId)
+
+		claimed, err = repo.ClaimDueAlerts(now.Add(time.Hour*25), time.Hour*24)
+		require.NoError(t, err)
+		assert.Contains(t, claimedTenants(claimed), tenantId)
+	})
+
+	t.Run("Margin", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		repo := NewMarginAlertRepositoryImpl(tx)
+
+		cheap := &model.Item{ItemName: "Below Cost Item", Stocks: 5, StockType: model.StockTypeTracked, BasePrice: 1000, TenantId: tenantId, IsActive: true}
+		healthy := &model.Item{ItemName: "Healthy Item", Stocks: 5, StockType: model.StockTypeTracked, BasePrice: 1000, TenantId: tenantId, IsActive: true}
+		require.NoError(t, tx.Create(cheap).Error)
+		require.NoError(t, tx.Create(healthy).Error)
+		require.NoError(t, tx.Create(&model.StoreStock{Stocks: 3, Price: 900, ItemId: cheap.ItemId, TenantId: tenantId, StoreId: storeId}).Error)
+		require.NoError(t, tx.Create(&model.StoreStock{Stocks: 3, Price: 2000, ItemId: healthy.ItemId, TenantId: tenantId, StoreId: storeId}).Error)
+
+		stocks, count, err := repo.GetBelowCostStocks(tenantId, 0, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		require.Len(t, stocks, 1)
+		assert.Equal(t, cheap.ItemId, stocks[0].ItemId)
+		assert.Equal(t, 100, stocks[0].UnitLoss)
+
+		// 1 invoice with both items, the cheap one sold below the base price
+		orderItem, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 2_900,
+			TotalQuantity:  2,
+			TotalAmount:    2_900,
+			Subtotal:       2_900,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+		})
+		require.NoError(t, err)
+		for _, line := range []*model.PurchasedItem{
+			{ItemId: cheap.ItemId, ItemNameSnapshot: cheap.ItemName, Quantity: 1, StorePriceSnapshot: 900, BasePriceSnapshot: 1000, TotalAmount: 900},
+			{ItemId: healthy.ItemId, ItemNameSnapshot: healthy.ItemName, Quantity: 1, StorePriceSnapshot: 2000, BasePriceSnapshot: 1000, TotalAmount: 2000},
+		} {
+			line.OrderItemId = orderItem.Id
+			require.NoError(t, tx.Create(line).Error)
+		}
+
+		since := time.Now().AddDate(0, 0, -1)
+		items, err := repo.GetLowMarginItems(tenantId, storeId, since, 10)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, cheap.ItemId, items[0].ItemId)
+		assert.Equal(t, -100, items[0].TotalProfit)
+
+		// 60% margin is flagged with a higher threshold
+		items, err = repo.GetLowMarginItems(tenantId, 0, since, 60)
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+
+		invoices, err := repo.GetLowMarginInvoices(tenantId, storeId, since, 10, []int{cheap.ItemId}, 10)
+		require.NoError(t, err)
+		require.Len(t, invoices, 1)
+		assert.Equal(t, orderItem.Id, invoices[0].OrderItemId)
+		assert.Equal(t, 1, invoices[0].LineCount)
+
+		// Outside the window
+		items, err = repo.GetLowMarginItems(tenantId, storeId, time.Now().Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Len(t, items, 0)
+	})
+}
+
+func claimedTenants(settings []*model.MarginAlertSetting) []int {
+	tenantIds := make([]int, 0, len(settings))
+	for _, setting := range settings {
+		tenantIds = append(tenantIds, setting.TenantId)
+	}
+	return tenantIds
+}
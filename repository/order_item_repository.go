@@ -16,8 +16,9 @@ type OrderItemRepository interface {
 	/*
 		Get the list of order_item, purchased_item_list will not included
 		2nd params return is the count of all data
+		customerId = 0 will not filter by customer
 	*/
-	Get(tenantId int, storeId int, limit int, page int, filters []*query.QueryFilter, dateFilter *query.DateFilter) ([]*model.OrderItem, int, error)
+	Get(tenantId int, storeId int, customerId int, limit int, page int, filters []*query.QueryFilter, dateFilter *query.DateFilter) ([]*model.OrderItem, int, error)
 
 	/*
 
@@ -32,15 +33,41 @@ type OrderItemRepository interface {
 	// Edit(quantity int, item *model.Item) error
 
 	/*
-		Using aggregate function from SQL to get report
+		Using aggregate function from SQL to get report.
+		A sale is dated by completed_at, a placed order count when it is COMPLETED
 	*/
 	GetSalesReport(tenantId int, storeId int, dateFilter *query.DateFilter) (*SalesReport, error)
 
+	/*
+		Same metrics as GetSalesReport grouped by bucket, bucket is truncated
+		at the given timezone. Only bucket with sales are returned (oldest first),
+		BucketStart is the local wall clock time
+	*/
+	GetSalesSeries(tenantId int, storeId int, bucket SalesBucket, timezone string, dateFilter *query.DateFilter) ([]*SalesSeriesPoint, error)
+
+	/*
+		Return tenant timezone, "" means not configured
+	*/
+	GetTenantTimezone(tenantId int) (string, error)
+
 	/*
 		Get per-item profit data for Excel export
 	*/
 	GetProfitReport(tenantId int, storeId int, dateFilter *query.DateFilter) ([]*ProfitReportRow, error)
 
+	/*
+		Sales per category, item without category is grouped as "Uncategorized" (id 0).
+		Item registered at many categories is counted once, at its first category (lowest id).
+		storeId = 0 means from all store
+	*/
+	GetCategoryReport(tenantId int, storeId int, dateFilter *query.DateFilter) ([]*CategoryReportRow, error)
+
+	/*
+		Sales of every store of the tenant at the same period (highest revenue first),
+		store without sales is included with zero
+	*/
+	GetStoreComparison(tenantId int, dateFilter *query.DateFilter) ([]*StoreComparisonRow, error)
+
 	/*
 		Get tenant name and store name for display purposes.
 		If storeId is 0, storeName will be "All Stores".
@@ -48,9 +75,28 @@ type OrderItemRepository interface {
 	GetTenantAndStoreName(tenantId int, storeId int) (tenantName string, storeName string, err error)
 
 	/*
-		Soft delete invoice.
+		Soft delete invoice, loyalty points earned / redeemed and
+		gift card redeemed by it are reversed.
+	*/
+	DeleteInvoice(orderItemId int, tenantId int, userId int) error
+
+	/*
+		Move the order into the next status and record it at order_status_history.
+		COMPLETED require the order to be fully paid and earn its loyalty points,
+		CANCELLED return the stock, gift card and redeemed points (not a void)
+	*/
+	UpdateStatus(params *UpdateOrderStatusParams) (*model.OrderItem, error)
+
+	/*
+		Get PLACED, IN_PREPARATION and READY orders of a store (oldest first)
+		2nd params return is the count of all data
+	*/
+	GetOpenOrders(tenantId, storeId, limit, page int) ([]*model.OrderItem, int, error)
+
+	/*
+		Every status change of the order (oldest first)
 	*/
-	DeleteInvoice(orderItemId int, tenantId int) error
+	GetStatusHistory(orderItemId, tenantId int) ([]*model.OrderStatusHistory, error)
 }
 
 type CreateTransactionParams struct {
@@ -68,6 +114,40 @@ type CreateTransactionParams struct {
 	UserId   int `json:"user_id"`
 	TenantId int `json:"tenant_id"`
 	StoreId  int `json:"store_id"`
+
+	// Optional, 0 means anonymous sale
+	CustomerId int `json:"customer_id"`
+
+	// Optional loyalty redemption, require customer.
+	// PointsDiscount = RedeemPoints * loyalty_setting.redeem_value,
+	// it's not included yet at DiscountAmount and TotalAmount
+	RedeemPoints   int `json:"redeem_points"`
+	PointsDiscount int `json:"points_discount"`
+
+	// Optional gift card / store credit payment,
+	// PurchasedPrice (cash) + GiftCardAmount should cover the amount due
+	GiftCardCode   string `json:"gift_card_code"`
+	GiftCardAmount int    `json:"gift_card_amount"`
+
+	// Optional, the parked order converted by this transaction,
+	// the version must be the latest one
+	ParkedOrderId      int `json:"parked_order_id"`
+	ParkedOrderVersion int `json:"parked_order_version"`
+
+	// Empty means the sale is paid and COMPLETED right away,
+	// PLACED is only set by OrderItemService.PlaceOrderItem
+	Status model.OrderStatus `json:"-"`
+}
+
+type UpdateOrderStatusParams struct {
+	OrderItemId int               `json:"order_item_id"`
+	TenantId    int               `json:"tenant_id"`
+	UserId      int               `json:"user_id"`
+	Status      model.OrderStatus `json:"status"`
+
+	// Optional, cash paid when the order is COMPLETED.
+	// 0 keep the purchased_price given when the order is placed
+	PurchasedPrice int `json:"purchased_price"`
 }
 
 type SalesReport struct {
@@ -80,19 +160,74 @@ type SalesReport struct {
 	SumProfit         int `json:"sum_profit"`
 }
 
+type SalesBucket string
+
+const (
+	SalesBucketHour  SalesBucket = "hour"
+	SalesBucketDay   SalesBucket = "day"
+	SalesBucketWeek  SalesBucket = "week" // Start on monday, same as PostgreSQL date_trunc
+	SalesBucketMonth SalesBucket = "month"
+)
+
+func (bucket SalesBucket) IsValid() bool {
+	switch bucket {
+	case SalesBucketHour, SalesBucketDay, SalesBucketWeek, SalesBucketMonth:
+		return true
+	}
+	return false
+}
+
+type SalesSeriesPoint struct {
+	BucketStart time.Time `json:"bucket_start"`
+	SalesReport
+}
+
+type SalesSeries struct {
+	Bucket   SalesBucket         `json:"bucket"`
+	Timezone string              `json:"timezone"`
+	Points   []*SalesSeriesPoint `json:"points"` // Zero filled, oldest first
+}
+
 type ProfitReportRow struct {
 	ItemId        int    `json:"item_id"        gorm:"column:item_id"`
 	ItemName      string `json:"item_name"      gorm:"column:item_name"`
 	TotalQuantity int    `json:"total_quantity" gorm:"column:total_quantity"`
-	TotalRevenue  int    `json:"total_revenue"  gorm:"column:total_revenue"`
+	TotalRevenue  int    `json:"total_revenue"  gorm:"column:total_revenue"` // After the points discount share of the order
 	TotalCogs     int    `json:"total_cogs"     gorm:"column:total_cogs"`
 	TotalDiscount int    `json:"total_discount" gorm:"column:total_discount"`
 	TotalProfit   int    `json:"total_profit"   gorm:"column:total_profit"`
 }
 
+type CategoryReportRow struct {
+	CategoryId    int     `json:"category_id"    gorm:"column:category_id"`
+	CategoryName  string  `json:"category_name"  gorm:"column:category_name"`
+	TotalQuantity int     `json:"total_quantity" gorm:"column:total_quantity"`
+	TotalRevenue  int     `json:"total_revenue"  gorm:"column:total_revenue"`
+	TotalDiscount int     `json:"total_discount" gorm:"column:total_discount"`
+	TotalProfit   int     `json:"total_profit"   gorm:"column:total_profit"`
+	RevenueShare  float64 `json:"revenue_share"  gorm:"-"` // 0 - 100, filled by service
+}
+
+type StoreComparisonRow struct {
+	StoreId           int     `json:"store_id"            gorm:"column:store_id"`
+	StoreName         string  `json:"store_name"          gorm:"column:store_name"`
+	SumTotalAmount    int     `json:"sum_total_amount"    gorm:"column:sum_total_amount"`
+	SumTransactions   int     `json:"sum_transactions"    gorm:"column:sum_transactions"`
+	SumTotalQuantity  int     `json:"sum_total_quantity"  gorm:"column:sum_total_quantity"`
+	SumDiscountAmount int     `json:"sum_discount_amount" gorm:"column:sum_discount_amount"`
+	SumProfit         int     `json:"sum_profit"          gorm:"column:sum_profit"`
+	RevenueShare      float64 `json:"revenue_share"       gorm:"-"` // 0 - 100, filled by service
+	TransactionShare  float64 `json:"transaction_share"   gorm:"-"`
+	ProfitShare       float64 `json:"profit_share"        gorm:"-"`
+}
+
 type TransactionDataReturn struct {
 	CreatedOrderItemId int        `json:"created_order_item_id"         gorm:"column:v_id"`
 	CreatedAt          *time.Time `json:"created_at" gorm:"column:v_created_at"`
 	TotalAmount        int        `json:"total_amount" gorm:"column:v_total_amount"`
 	CashIn             int        `json:"purchased_price" gorm:"column:v_purchased_price"`
+	PointsRedeemed     int        `json:"points_redeemed" gorm:"-"`
+	PointsEarned       int        `json:"points_earned" gorm:"-"`
+	GiftCardAmount     int        `json:"gift_card_amount" gorm:"-"`
+	GiftCardBalance    int        `json:"gift_card_balance" gorm:"-"` // Remaining balance after redemption
 }
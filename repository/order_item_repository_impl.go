@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const OrderItemTable string = "order_item"
@@ -59,6 +60,7 @@ QueryFilter:
 func (repository *OrderItemRepositoryImpl) Get(
 	tenantId int,
 	storeId int,
+	customerId int,
 	limit int,
 	page int,
 	filters []*query.QueryFilter,
@@ -77,6 +79,11 @@ func (repository *OrderItemRepositoryImpl) Get(
 		db = db.Where("store_id = ?", storeId)
 	}
 
+	// 0 means every sale, including anonymous one
+	if customerId > 0 {
+		db = db.Where("customer_id = ?", customerId)
+	}
+
 	// Apply date filter
 	if dateFilter != nil {
 		// This is on demand filter may change in the future, maybe use column such as update_at, etc...
@@ -117,6 +124,8 @@ func (repository *OrderItemRepositoryImpl) Get(
 		}
 		db = db.Order(fmt.Sprintf("%s %s", filter.Column, direction))
 	}
+	// Newest first, and the tie breaker of the filters so every page is the next rows
+	db = db.Order("id DESC")
 
 	// Apply pagination
 	result := db.Limit(limit).Offset(start).Find(&results)
@@ -135,36 +144,246 @@ func (repository *OrderItemRepositoryImpl) Transactions(params *CreateTransactio
 	}
 
 	var transactionDataReturn *TransactionDataReturn
-	// Because it's return row, use SELECT *
-	result := repository.Client.Raw("SELECT * FROM transactions($1, $2, $3, $4, $5, $6::JSONB, $7, $8, $9)",
-		params.PurchasedPrice,
-		params.TotalQuantity,
-		params.TotalAmount,
-		params.DiscountAmount,
-		params.SubTotal,
+	err = repository.Client.Transaction(func(tx *gorm.DB) error {
+		// Customer is optional, but when given it must belong to the same tenant
+		if params.CustomerId > 0 {
+			var count int64
+			err := tx.Model(&model.Customer{}).
+				Where("id = ? AND tenant_id = ?", params.CustomerId, params.TenantId).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return fmt.Errorf("Customer %d not found for tenant %d", params.CustomerId, params.TenantId)
+			}
+		}
+
+		// Parked order is locked until the sale is committed, so 2 tills never convert it twice
+		if params.ParkedOrderId > 0 {
+			parkedOrder, err := lockParkedOrder(tx, params.ParkedOrderId, params.TenantId)
+			if err != nil {
+				return err
+			}
+			if parkedOrder.Version != params.ParkedOrderVersion {
+				return fmt.Errorf("Parked order %d was changed by another till, reload it first", params.ParkedOrderId)
+			}
+			if parkedOrder.StoreId != params.StoreId {
+				return fmt.Errorf("Parked order %d belong to another store", params.ParkedOrderId)
+			}
+		}
 
-		string(itemsJSON), // cast to JSONB in the query
+		// Because it's return row, use SELECT *
+		result := tx.Raw("SELECT * FROM transactions($1, $2, $3, $4, $5, $6::JSONB, $7, $8, $9)",
+			params.PurchasedPrice,
+			params.TotalQuantity,
+			params.TotalAmount,
+			params.DiscountAmount,
+			params.SubTotal,
 
-		params.UserId,
-		params.TenantId,
-		params.StoreId,
-	).Scan(&transactionDataReturn)
-	fmt.Println(result)
+			string(itemsJSON), // cast to JSONB in the query
 
-	if result.Error != nil {
+			params.UserId,
+			params.TenantId,
+			params.StoreId,
+		).Scan(&transactionDataReturn)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// transactions() always create a COMPLETED sale, placed order start its lifecycle here
+		if params.Status == model.OrderStatusPlaced {
+			err := tx.Model(&model.OrderItem{}).
+				Where("id = ?", transactionDataReturn.CreatedOrderItemId).
+				Updates(map[string]any{"status": model.OrderStatusPlaced, "completed_at": nil}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Create(&model.OrderStatusHistory{
+				TenantId:    params.TenantId,
+				OrderItemId: transactionDataReturn.CreatedOrderItemId,
+				ToStatus:    model.OrderStatusPlaced,
+				UserId:      params.UserId,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if params.ParkedOrderId > 0 {
+			err := tx.Model(&model.ParkedOrder{}).
+				Where("id = ?", params.ParkedOrderId).
+				Updates(map[string]any{
+					"status":        model.ParkedOrderStatusConverted,
+					"order_item_id": transactionDataReturn.CreatedOrderItemId,
+					"updated_at":    time.Now(),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// transactions() does not know about tax, write it into the created lines
+		if err := applyLineTax(tx, params, transactionDataReturn); err != nil {
+			return err
+		}
+
+		// transactions() does not know about gift card, redeem it inside the same transaction
+		if params.GiftCardAmount > 0 {
+			if err := applyGiftCard(tx, params, transactionDataReturn); err != nil {
+				return err
+			}
+		}
+
+		// transactions() does not know about customer, link it inside the same transaction
+		if params.CustomerId > 0 {
+			err := tx.Model(&model.OrderItem{}).
+				Where("id = ? AND tenant_id = ?", transactionDataReturn.CreatedOrderItemId, params.TenantId).
+				Update("customer_id", params.CustomerId).Error
+			if err != nil {
+				return err
+			}
+
+			return applyLoyalty(tx, params, transactionDataReturn)
+		}
+
+		return nil
+	})
+
+	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(result.Error, &pgErr) {
+		if errors.As(err, &pgErr) {
 			log.Warnf("PostgreSQL error during transaction: code=%s, message=%s", pgErr.Code, pgErr.Message)
 			return nil, errors.New(pgErr.Message) // return clean message to caller (service layer)
 		}
 
-		log.Errorf("Unexpected error during transaction: %v", result.Error)
-		return nil, result.Error
+		log.Errorf("Unexpected error during transaction: %v", err)
+		return nil, err
 	}
 
 	return transactionDataReturn, nil
 }
 
+/*
+applyLineTax write the tax of every line created by transactions(),
+lines are created in the same order as params.Items
+*/
+func applyLineTax(tx *gorm.DB, params *CreateTransactionParams, created *TransactionDataReturn) error {
+	var purchasedItems []*model.PurchasedItem
+	err := tx.Where("order_item_id = ?", created.CreatedOrderItemId).
+		Order("id ASC").
+		Find(&purchasedItems).Error
+	if err != nil {
+		return err
+	}
+	if len(purchasedItems) != len(params.Items) {
+		return fmt.Errorf("Expected %d lines, transactions() created %d", len(params.Items), len(purchasedItems))
+	}
+
+	for i, item := range params.Items {
+		if item.TaxAmount == 0 {
+			continue
+		}
+		if purchasedItems[i].ItemId != item.ItemId {
+			return fmt.Errorf("Line %d is item %d, expected item %d", i, purchasedItems[i].ItemId, item.ItemId)
+		}
+
+		err := tx.Model(&model.PurchasedItem{}).
+			Where("id = ?", purchasedItems[i].Id).
+			Update("tax_amount", item.TaxAmount).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+applyGiftCard redeem the gift card as a payment of the created order item
+*/
+func applyGiftCard(tx *gorm.DB, params *CreateTransactionParams, created *TransactionDataReturn) error {
+	giftCard, err := NewGiftCardRepositoryImpl(tx).Redeem(
+		params.GiftCardCode,
+		params.TenantId,
+		params.GiftCardAmount,
+		created.CreatedOrderItemId,
+		params.StoreId,
+		params.UserId,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&model.OrderItem{}).
+		Where("id = ? AND tenant_id = ?", created.CreatedOrderItemId, params.TenantId).
+		Update("gift_card_amount", params.GiftCardAmount).Error
+	if err != nil {
+		return err
+	}
+
+	created.GiftCardAmount = params.GiftCardAmount
+	created.GiftCardBalance = giftCard.Balance
+	return nil
+}
+
+/*
+applyLoyalty redeem and earn points of the created order item.
+
+	Points discount is applied after transactions() as an order level discount,
+	so order_item.total_amount is the amount actually paid by the customer.
+	Points are earned from that amount, a placed order earn nothing until COMPLETED.
+*/
+func applyLoyalty(tx *gorm.DB, params *CreateTransactionParams, created *TransactionDataReturn) error {
+	loyaltyRepository := NewLoyaltyRepositoryImpl(tx)
+
+	setting, err := loyaltyRepository.GetSetting(params.TenantId)
+	if err != nil {
+		return err
+	}
+
+	if params.RedeemPoints > 0 {
+		if !setting.IsActive {
+			return errors.New("Loyalty programme is not active")
+		}
+		if params.PointsDiscount != params.RedeemPoints*setting.RedeemValue {
+			return fmt.Errorf("Invalid points discount, expected %d got %d", params.RedeemPoints*setting.RedeemValue, params.PointsDiscount)
+		}
+
+		if err := loyaltyRepository.Redeem(params.TenantId, params.CustomerId, created.CreatedOrderItemId, params.RedeemPoints); err != nil {
+			return err
+		}
+
+		err := tx.Model(&model.OrderItem{}).
+			Where("id = ? AND tenant_id = ?", created.CreatedOrderItemId, params.TenantId).
+			Updates(map[string]any{
+				"discount_amount": gorm.Expr("discount_amount + ?", params.PointsDiscount),
+				"total_amount":    gorm.Expr("total_amount - ?", params.PointsDiscount),
+				"points_discount": params.PointsDiscount,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		created.TotalAmount -= params.PointsDiscount
+		created.PointsRedeemed = params.RedeemPoints
+	}
+
+	// Placed order earn its points once it is COMPLETED, see UpdateStatus
+	if params.Status == model.OrderStatusPlaced {
+		return nil
+	}
+
+	earned, err := loyaltyRepository.Earn(setting, params.CustomerId, created.CreatedOrderItemId, params.TotalAmount-params.PointsDiscount)
+	if err != nil {
+		return err
+	}
+	created.PointsEarned = earned
+
+	return nil
+}
+
 // FindById implements OrderItemRepository.
 func (repository *OrderItemRepositoryImpl) FindById(orderItemId int, tenantId int) (*model.OrderItemWithStore, []*model.PurchasedItem, error) {
 	type row struct {
@@ -176,17 +395,22 @@ func (repository *OrderItemRepositoryImpl) FindById(orderItemId int, tenantId in
 		Quantity                    int    `gorm:"column:quantity"`
 		PurchasedItemDiscountAmount int    `gorm:"column:purchased_item_discount_amount"`
 		PurchasedItemTotalAmount    int    `gorm:"column:purchased_item_total_amount"`
+		PurchasedItemTaxAmount      int    `gorm:"column:purchased_item_tax_amount"`
 		ItemNameSnapshot            string `gorm:"column:item_name_snapshot"`
 
 		// order_item
-		OrderItemId             int       `gorm:"column:order_item_id"`
-		PurchasedPrice          int       `gorm:"column:purchased_price"`
-		Subtotal                int       `gorm:"column:subtotal"`
-		TotalQuantity           int       `gorm:"column:total_quantity"`
-		OrderItemTotalAmount    int       `gorm:"column:order_item_total_amount"`
-		OrderItemDiscountAmount int       `gorm:"column:order_item_discount_amount"`
-		CreatedAt               time.Time `gorm:"column:created_at"`
-		StoreId                 int       `gorm:"column:store_id"`
+		OrderItemId             int               `gorm:"column:order_item_id"`
+		PurchasedPrice          int               `gorm:"column:purchased_price"`
+		Subtotal                int               `gorm:"column:subtotal"`
+		TotalQuantity           int               `gorm:"column:total_quantity"`
+		OrderItemTotalAmount    int               `gorm:"column:order_item_total_amount"`
+		OrderItemDiscountAmount int               `gorm:"column:order_item_discount_amount"`
+		CreatedAt               time.Time         `gorm:"column:created_at"`
+		StoreId                 int               `gorm:"column:store_id"`
+		CustomerId              *int              `gorm:"column:customer_id"`
+		PointsDiscount          int               `gorm:"column:points_discount"`
+		GiftCardAmount          int               `gorm:"column:gift_card_amount"`
+		Status                  model.OrderStatus `gorm:"column:status"`
 
 		// store
 		StoreName string `gorm:"column:store_name"`
@@ -204,6 +428,7 @@ func (repository *OrderItemRepositoryImpl) FindById(orderItemId int, tenantId in
 			purchased_item_list.quantity,
 			purchased_item_list.discount_amount     AS purchased_item_discount_amount,
 			purchased_item_list.total_amount        AS purchased_item_total_amount,
+			purchased_item_list.tax_amount          AS purchased_item_tax_amount,
 			purchased_item_list.item_name_snapshot,
 			order_item.id                           AS order_item_id,
 			order_item.purchased_price,
@@ -213,6 +438,10 @@ func (repository *OrderItemRepositoryImpl) FindById(orderItemId int, tenantId in
 			order_item.discount_amount              AS order_item_discount_amount,
 			order_item.created_at,
 			order_item.store_id,
+			order_item.customer_id,
+			order_item.points_discount,
+			order_item.gift_card_amount,
+			order_item.status,
 			store.name                              AS store_name
 		`).
 		Joins("INNER JOIN purchased_item_list ON purchased_item_list.order_item_id = order_item.id").
@@ -240,6 +469,10 @@ func (repository *OrderItemRepositoryImpl) FindById(orderItemId int, tenantId in
 		CreatedAt:      first.CreatedAt,
 		StoreId:        first.StoreId,
 		TenantId:       tenantId,
+		CustomerId:     first.CustomerId,
+		PointsDiscount: first.PointsDiscount,
+		GiftCardAmount: first.GiftCardAmount,
+		Status:         first.Status,
 		StoreName:      first.StoreName,
 	}
 
@@ -254,6 +487,7 @@ func (repository *OrderItemRepositoryImpl) FindById(orderItemId int, tenantId in
 			Quantity:           r.Quantity,
 			DiscountAmount:     r.PurchasedItemDiscountAmount,
 			TotalAmount:        r.PurchasedItemTotalAmount,
+			TaxAmount:          r.PurchasedItemTaxAmount,
 			ItemNameSnapshot:   r.ItemNameSnapshot,
 			OrderItemId:        orderItemId,
 		}
@@ -269,12 +503,12 @@ func (repository *OrderItemRepositoryImpl) GetProfitReport(tenantId int, storeId
 			pil.item_id,
 			MAX(pil.item_name_snapshot) AS item_name,
 			SUM(pil.quantity) AS total_quantity,
-			SUM(pil.total_amount) AS total_revenue,
+			SUM(`+lineRevenue+`) AS total_revenue,
 			SUM(pil.base_price_snapshot * pil.quantity) AS total_cogs,
 			SUM(pil.discount_amount * pil.quantity) AS total_discount,
-			SUM(pil.total_amount) - SUM(pil.base_price_snapshot * pil.quantity) AS total_profit
+			SUM(`+lineRevenue+`) - SUM(pil.base_price_snapshot * pil.quantity) AS total_profit
 		`).
-		Joins("JOIN order_item oi ON oi.id = pil.order_item_id").
+		Joins(completedSalesJoin).
 		Where("oi.tenant_id = ?", tenantId).
 		Group("pil.item_id").
 		Order("total_profit DESC")
@@ -287,13 +521,13 @@ func (repository *OrderItemRepositoryImpl) GetProfitReport(tenantId int, storeId
 		if dateFilter.StartDate != nil && dateFilter.EndDate != nil {
 			startDate := common.EpochToRFC3339(*dateFilter.StartDate)
 			endDate := common.EpochToRFC3339(*dateFilter.EndDate)
-			db = db.Where("oi.created_at >= ? AND oi.created_at < ?", startDate, endDate)
+			db = db.Where("oi.completed_at >= ? AND oi.completed_at < ?", startDate, endDate)
 		} else if dateFilter.StartDate != nil {
 			startDate := common.EpochToRFC3339(*dateFilter.StartDate)
-			db = db.Where("oi.created_at >= ?", startDate)
+			db = db.Where("oi.completed_at >= ?", startDate)
 		} else if dateFilter.EndDate != nil {
 			endDate := common.EpochToRFC3339(*dateFilter.EndDate)
-			db = db.Where("oi.created_at < ?", endDate)
+			db = db.Where("oi.completed_at < ?", endDate)
 		}
 	}
 
@@ -330,30 +564,54 @@ func (repository *OrderItemRepositoryImpl) GetTenantAndStoreName(tenantId int, s
 	return tenantName, storeName, nil
 }
 
-// GetReport implements [OrderItemRepository].
-func (repository *OrderItemRepositoryImpl) GetSalesReport(tenantId int, storeId int, dateFilter *query.DateFilter) (*SalesReport, error) {
-	// Base condition builder to avoid repeating date filter logic
-	applyFilters := func(db *gorm.DB, tablePrefix string) *gorm.DB {
-		db = db.Where(tablePrefix+"tenant_id = ?", tenantId)
+// completedSalesJoin join the sold lines to their order, only a COMPLETED order is a sale
+const completedSalesJoin = "INNER JOIN order_item oi ON oi.id = pil.order_item_id AND oi.deleted_at IS NULL AND oi.status = '" +
+	string(model.OrderStatusCompleted) + "'"
 
-		if storeId > 0 {
-			db = db.Where(tablePrefix+"store_id = ?", storeId)
-		}
+// lineRevenue the line total less its share of the order points discount, needs completedSalesJoin.
+// The points discount is an order level discount, it is shared across the lines by their total.
+const lineRevenue = "(pil.total_amount - COALESCE(ROUND(pil.total_amount::numeric * oi.points_discount / " +
+	"NULLIF(oi.total_amount + oi.points_discount, 0))::int, 0))"
 
-		if dateFilter != nil {
-			if dateFilter.StartDate != nil && dateFilter.EndDate != nil {
-				db = db.Where(tablePrefix+"created_at >= to_timestamp(?) AND "+tablePrefix+"created_at < to_timestamp(?)",
-					*dateFilter.StartDate, *dateFilter.EndDate)
-			} else if dateFilter.StartDate != nil {
-				db = db.Where(tablePrefix+"created_at >= to_timestamp(?)", *dateFilter.StartDate)
-			} else if dateFilter.EndDate != nil {
-				db = db.Where(tablePrefix+"created_at <= to_timestamp(?)", *dateFilter.EndDate)
-			}
-		}
+/*
+applySalesReportFilters is the base condition of every sales report,
+to avoid repeating date filter logic. Open and cancelled orders are not a sale yet
+*/
+func applySalesReportFilters(db *gorm.DB, tablePrefix string, tenantId int, storeId int, dateFilter *query.DateFilter) *gorm.DB {
+	db = db.Where(tablePrefix+"tenant_id = ?", tenantId).
+		Where(tablePrefix+"status = ?", model.OrderStatusCompleted)
 
+	if storeId > 0 {
+		db = db.Where(tablePrefix+"store_id = ?", storeId)
+	}
+
+	return applyDateRange(db, tablePrefix+"completed_at", dateFilter)
+}
+
+// applyDateRange filter the qualified timestamp column, dateFilter is allowed to nil
+func applyDateRange(db *gorm.DB, column string, dateFilter *query.DateFilter) *gorm.DB {
+	if dateFilter == nil {
 		return db
 	}
 
+	if dateFilter.StartDate != nil && dateFilter.EndDate != nil {
+		db = db.Where(column+" >= to_timestamp(?) AND "+column+" < to_timestamp(?)",
+			*dateFilter.StartDate, *dateFilter.EndDate)
+	} else if dateFilter.StartDate != nil {
+		db = db.Where(column+" >= to_timestamp(?)", *dateFilter.StartDate)
+	} else if dateFilter.EndDate != nil {
+		db = db.Where(column+" <= to_timestamp(?)", *dateFilter.EndDate)
+	}
+
+	return db
+}
+
+// GetReport implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) GetSalesReport(tenantId int, storeId int, dateFilter *query.DateFilter) (*SalesReport, error) {
+	applyFilters := func(db *gorm.DB, tablePrefix string) *gorm.DB {
+		return applySalesReportFilters(db, tablePrefix, tenantId, storeId, dateFilter)
+	}
+
 	// order_summary — Model() applies deleted_at IS NULL automatically
 	type orderSummary struct {
 		SumPurchasedPrice int
@@ -387,11 +645,11 @@ func (repository *OrderItemRepositoryImpl) GetSalesReport(tenantId int, storeId
 
 	profitQuery := applyFilters(
 		repository.Client.Table("purchased_item_list pil").
-			Joins("INNER JOIN order_item oi ON oi.id = pil.order_item_id AND oi.deleted_at IS NULL"),
+			Joins(completedSalesJoin),
 		"oi.",
 	)
 	err = profitQuery.Select(`
-        COALESCE(SUM(pil.total_amount) - SUM(pil.base_price_snapshot * pil.quantity), 0) AS sum_profit
+        COALESCE(SUM(` + lineRevenue + `) - SUM(pil.base_price_snapshot * pil.quantity), 0) AS sum_profit
     `).Scan(&pSummary).Error
 	if err != nil {
 		return nil, fmt.Errorf("GetSalesReport profit_summary failed: %w", err)
@@ -409,20 +667,375 @@ func (repository *OrderItemRepositoryImpl) GetSalesReport(tenantId int, storeId
 }
 
 // DeleteInvoice implements [OrderItemRepository].
-func (repository *OrderItemRepositoryImpl) DeleteInvoice(orderItemId int, tenantId int) error {
+func (repository *OrderItemRepositoryImpl) DeleteInvoice(orderItemId int, tenantId int, userId int) error {
 	// In the future will be implement security such as:
 	// - Only owner could delete the invoice
 	// - Permission request
-	result := repository.Client.
-		Where("id = ? AND tenant_id = ?", orderItemId, tenantId).
-		Delete(&model.OrderItem{})
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		// Soft delete by hand to keep who voided it, Model() skip the already deleted one
+		result := tx.Model(&model.OrderItem{}).
+			Where("id = ? AND tenant_id = ?", orderItemId, tenantId).
+			Updates(map[string]any{
+				"deleted_at":        time.Now(),
+				"voided_by_user_id": userId,
+			})
 
-	if result.Error != nil {
-		return result.Error
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("order item %d not found", orderItemId)
+		}
+
+		if err := NewGiftCardRepositoryImpl(tx).ReverseOrderItem(orderItemId, tenantId); err != nil {
+			return err
+		}
+
+		// Anonymous sale has no ledger entry, then nothing reversed
+		return NewLoyaltyRepositoryImpl(tx).ReverseOrderItem(orderItemId, tenantId)
+	})
+}
+
+// UpdateStatus implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) UpdateStatus(params *UpdateOrderStatusParams) (*model.OrderItem, error) {
+	var orderItem model.OrderItem
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		// Locked, so 2 devices never move the same order at the same time
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", params.OrderItemId, params.TenantId).
+			Take(&orderItem).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("order item %d not found", params.OrderItemId)
+		}
+		if err != nil {
+			return err
+		}
+
+		fromStatus := orderItem.Status
+		if !fromStatus.CanTransitionTo(params.Status) {
+			return fmt.Errorf("Order %d could not move from %s to %s", params.OrderItemId, fromStatus, params.Status)
+		}
+
+		updates := map[string]any{"status": params.Status}
+		if params.Status == model.OrderStatusCompleted {
+			// The sale happen now, not when it was placed
+			updates["completed_at"] = time.Now()
+			if params.PurchasedPrice > 0 {
+				orderItem.PurchasedPrice = params.PurchasedPrice
+				updates["purchased_price"] = params.PurchasedPrice
+			}
+
+			// total_amount already exclude points discount
+			if orderItem.PurchasedPrice+orderItem.GiftCardAmount < orderItem.TotalAmount {
+				return fmt.Errorf("Insufficient payment: need %d, got %d",
+					orderItem.TotalAmount, orderItem.PurchasedPrice+orderItem.GiftCardAmount)
+			}
+		}
+
+		if err := tx.Model(&orderItem).Updates(updates).Error; err != nil {
+			return err
+		}
+		orderItem.Status = params.Status
+
+		err = tx.Create(&model.OrderStatusHistory{
+			TenantId:    params.TenantId,
+			OrderItemId: params.OrderItemId,
+			FromStatus:  fromStatus,
+			ToStatus:    params.Status,
+			UserId:      params.UserId,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		switch params.Status {
+		case model.OrderStatusCompleted:
+			return earnCompletedOrder(tx, &orderItem)
+		case model.OrderStatusCancelled:
+			return cancelOrder(tx, &orderItem)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("order item %d not found", orderItemId)
+
+	return &orderItem, nil
+}
+
+/*
+earnCompletedOrder give the loyalty points of a placed order once it is COMPLETED,
+total_amount already exclude the points discount
+*/
+func earnCompletedOrder(tx *gorm.DB, orderItem *model.OrderItem) error {
+	if orderItem.CustomerId == nil {
+		return nil
 	}
 
-	return nil
+	loyaltyRepository := NewLoyaltyRepositoryImpl(tx)
+	setting, err := loyaltyRepository.GetSetting(orderItem.TenantId)
+	if err != nil {
+		return err
+	}
+
+	_, err = loyaltyRepository.Earn(setting, *orderItem.CustomerId, orderItem.Id, orderItem.TotalAmount)
+	return err
+}
+
+/*
+cancelOrder undo a placed order that never became a sale.
+
+	Unlike DeleteInvoice it is not a void, the order stay visible as CANCELLED.
+	Stock taken by transactions() is returned to the store, the gift card
+	and the redeemed points are given back to the customer.
+*/
+func cancelOrder(tx *gorm.DB, orderItem *model.OrderItem) error {
+	var purchasedItems []*model.PurchasedItem
+	err := tx.Where("order_item_id = ?", orderItem.Id).Find(&purchasedItems).Error
+	if err != nil {
+		return err
+	}
+
+	for _, purchasedItem := range purchasedItems {
+		// Unlimited item has no stock to return
+		err := tx.Model(&model.StoreStock{}).
+			Where("item_id = ? AND store_id = ? AND tenant_id = ?", purchasedItem.ItemId, orderItem.StoreId, orderItem.TenantId).
+			Where("item_id IN (?)", tx.Model(&model.Item{}).
+				Select("item_id").
+				Where("stock_type = ?", model.StockTypeTracked)).
+			Update("stocks", gorm.Expr("stocks + ?", purchasedItem.Quantity)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := NewGiftCardRepositoryImpl(tx).ReverseOrderItem(orderItem.Id, orderItem.TenantId); err != nil {
+		return err
+	}
+
+	// Anonymous order has no ledger entry, then nothing reversed
+	return NewLoyaltyRepositoryImpl(tx).ReverseOrderItem(orderItem.Id, orderItem.TenantId)
+}
+
+// GetOpenOrders implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) GetOpenOrders(tenantId, storeId, limit, page int) ([]*model.OrderItem, int, error) {
+	offset := page * limit
+
+	var orderItems = make([]*model.OrderItem, 0)
+	var totalCount int64
+
+	// Only open status is listed, cancelled and completed order never listed
+	query := repository.Client.Model(&model.OrderItem{}).
+		Where("tenant_id = ? AND store_id = ?", tenantId, storeId).
+		Where("status IN ?", model.OpenOrderStatuses())
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Kitchen handle the oldest order first
+	if err := query.
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orderItems).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return orderItems, int(totalCount), nil
+}
+
+// GetStatusHistory implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) GetStatusHistory(orderItemId, tenantId int) ([]*model.OrderStatusHistory, error) {
+	var history = make([]*model.OrderStatusHistory, 0)
+	err := repository.Client.
+		Where("order_item_id = ? AND tenant_id = ?", orderItemId, tenantId).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetSalesSeries implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) GetSalesSeries(
+	tenantId int,
+	storeId int,
+	bucket SalesBucket,
+	timezone string,
+	dateFilter *query.DateFilter,
+) ([]*SalesSeriesPoint, error) {
+	if !bucket.IsValid() {
+		return nil, fmt.Errorf("Invalid bucket: %s", bucket)
+	}
+
+	// bucket is one of the constant, safe to be formatted. The result is the tenant local time
+	bucketColumn := func(tablePrefix string) string {
+		return fmt.Sprintf("date_trunc('%s', %scompleted_at AT TIME ZONE ?) AS bucket", bucket, tablePrefix)
+	}
+
+	type orderSummary struct {
+		Bucket            time.Time
+		SumPurchasedPrice int
+		SumSubtotal       int
+		SumTotalQuantity  int
+		SumDiscountAmount int
+		SumTotalAmount    int
+		SumTransactions   int
+	}
+	var oSummaries []*orderSummary
+
+	err := applySalesReportFilters(repository.Client.Model(&model.OrderItem{}), "", tenantId, storeId, dateFilter).
+		Select(bucketColumn("")+`,
+			COALESCE(SUM(purchased_price), 0)  AS sum_purchased_price,
+			COALESCE(SUM(subtotal), 0)         AS sum_subtotal,
+			COALESCE(SUM(total_quantity), 0)   AS sum_total_quantity,
+			COALESCE(SUM(discount_amount), 0)  AS sum_discount_amount,
+			COALESCE(SUM(total_amount), 0)     AS sum_total_amount,
+			COALESCE(COUNT(id), 0)             AS sum_transactions
+		`, timezone).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&oSummaries).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetSalesSeries order_summary failed: %w", err)
+	}
+
+	type profitSummary struct {
+		Bucket    time.Time
+		SumProfit int
+	}
+	var pSummaries []*profitSummary
+
+	err = applySalesReportFilters(
+		repository.Client.Table("purchased_item_list pil").
+			Joins(completedSalesJoin),
+		"oi.", tenantId, storeId, dateFilter,
+	).
+		Select(bucketColumn("oi.")+`,
+			COALESCE(SUM(`+lineRevenue+`) - SUM(pil.base_price_snapshot * pil.quantity), 0) AS sum_profit
+		`, timezone).
+		Group("bucket").
+		Scan(&pSummaries).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetSalesSeries profit_summary failed: %w", err)
+	}
+
+	profitByBucket := make(map[time.Time]int, len(pSummaries))
+	for _, p := range pSummaries {
+		profitByBucket[p.Bucket] = p.SumProfit
+	}
+
+	points := make([]*SalesSeriesPoint, len(oSummaries))
+	for i, o := range oSummaries {
+		points[i] = &SalesSeriesPoint{
+			BucketStart: o.Bucket,
+			SalesReport: SalesReport{
+				SumPurchasedPrice: o.SumPurchasedPrice,
+				SumSubtotal:       o.SumSubtotal,
+				SumTotalQuantity:  o.SumTotalQuantity,
+				SumDiscountAmount: o.SumDiscountAmount,
+				SumTotalAmount:    o.SumTotalAmount,
+				SumProfit:         profitByBucket[o.Bucket],
+				SumTransactions:   o.SumTransactions,
+			},
+		}
+	}
+
+	return points, nil
+}
+
+// GetTenantTimezone implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) GetTenantTimezone(tenantId int) (string, error) {
+	var tenant model.Tenant
+	err := repository.Client.Select("timezone").Where("id = ?", tenantId).Take(&tenant).Error
+	if err != nil {
+		return "", err
+	}
+
+	return tenant.Timezone, nil
+}
+
+// GetCategoryReport implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) GetCategoryReport(tenantId int, storeId int, dateFilter *query.DateFilter) ([]*CategoryReportRow, error) {
+	// First category of every item (only category of the tenant), so a sale is never counted twice
+	firstCategory := repository.Client.Table("category_mtm_warehouse cmw").
+		Select("cmw.item_id, MIN(c.id) AS category_id").
+		Joins("INNER JOIN category c ON c.id = cmw.category_id").
+		Where("c.tenant_id = ?", tenantId).
+		Group("cmw.item_id")
+
+	db := applySalesReportFilters(
+		repository.Client.Table("purchased_item_list pil").
+			Joins(completedSalesJoin).
+			Joins("LEFT JOIN (?) fc ON fc.item_id = pil.item_id", firstCategory).
+			Joins("LEFT JOIN category cat ON cat.id = fc.category_id"),
+		"oi.", tenantId, storeId, dateFilter,
+	).
+		Select(`
+			COALESCE(cat.id, 0) AS category_id,
+			COALESCE(MAX(cat.category_name), 'Uncategorized') AS category_name,
+			SUM(pil.quantity) AS total_quantity,
+			SUM(` + lineRevenue + `) AS total_revenue,
+			SUM(pil.discount_amount * pil.quantity) AS total_discount,
+			SUM(` + lineRevenue + `) - SUM(pil.base_price_snapshot * pil.quantity) AS total_profit
+		`).
+		Group("COALESCE(cat.id, 0)").
+		Order("total_revenue DESC").
+		Order("category_id ASC")
+
+	var rows = make([]*CategoryReportRow, 0)
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("GetCategoryReport failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetStoreComparison implements [OrderItemRepository].
+func (repository *OrderItemRepositoryImpl) GetStoreComparison(tenantId int, dateFilter *query.DateFilter) ([]*StoreComparisonRow, error) {
+	// Model() applies deleted_at IS NULL automatically, storeId = 0 means every store
+	orderSummary := applySalesReportFilters(repository.Client.Model(&model.OrderItem{}), "", tenantId, 0, dateFilter).
+		Select(`
+			store_id,
+			SUM(total_amount)    AS sum_total_amount,
+			COUNT(id)            AS sum_transactions,
+			SUM(total_quantity)  AS sum_total_quantity,
+			SUM(discount_amount) AS sum_discount_amount
+		`).
+		Group("store_id")
+
+	profitSummary := applySalesReportFilters(
+		repository.Client.Table("purchased_item_list pil").
+			Joins(completedSalesJoin),
+		"oi.", tenantId, 0, dateFilter,
+	).
+		Select("oi.store_id, SUM(" + lineRevenue + ") - SUM(pil.base_price_snapshot * pil.quantity) AS sum_profit").
+		Group("oi.store_id")
+
+	var rows = make([]*StoreComparisonRow, 0)
+	err := repository.Client.Table("store s").
+		Select(`
+			s.id AS store_id,
+			s.name AS store_name,
+			COALESCE(o.sum_total_amount, 0)    AS sum_total_amount,
+			COALESCE(o.sum_transactions, 0)    AS sum_transactions,
+			COALESCE(o.sum_total_quantity, 0)  AS sum_total_quantity,
+			COALESCE(o.sum_discount_amount, 0) AS sum_discount_amount,
+			COALESCE(p.sum_profit, 0)          AS sum_profit
+		`).
+		Joins("LEFT JOIN (?) o ON o.store_id = s.id", orderSummary).
+		Joins("LEFT JOIN (?) p ON p.store_id = s.id", profitSummary).
+		Where("s.tenant_id = ?", tenantId).
+		Order("sum_total_amount DESC").
+		Order("s.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetStoreComparison failed: %w", err)
+	}
+
+	return rows, nil
 }
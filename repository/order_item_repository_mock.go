@@ -19,12 +19,13 @@ func NewOrderItemRepositoryMock(mock *mock.Mock) OrderItemRepository {
 func (repository *OrderItemRepositoryMock) Get(
 	tenantId int,
 	storeId int,
+	customerId int,
 	limit int,
 	page int,
 	filters []*query.QueryFilter,
 	dateFilter *query.DateFilter,
 ) ([]*model.OrderItem, int, error) {
-	args := repository.Mock.Called(tenantId, storeId, limit, page, filters, dateFilter)
+	args := repository.Mock.Called(tenantId, storeId, customerId, limit, page, filters, dateFilter)
 
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(1)
@@ -87,11 +88,77 @@ func (repository *OrderItemRepositoryMock) GetTenantAndStoreName(tenantId int, s
 }
 
 // DeleteInvoice implements [OrderItemRepository].
-func (repository *OrderItemRepositoryMock) DeleteInvoice(orderItemId int, tenantId int) error {
-	args := repository.Mock.Called(orderItemId, tenantId)
+func (repository *OrderItemRepositoryMock) DeleteInvoice(orderItemId int, tenantId int, userId int) error {
+	args := repository.Mock.Called(orderItemId, tenantId, userId)
 	if args.Get(0) != nil {
 		return args.Error(0)
 	}
 
 	return nil
 }
+
+// UpdateStatus implements [OrderItemRepository].
+func (repository *OrderItemRepositoryMock) UpdateStatus(params *UpdateOrderStatusParams) (*model.OrderItem, error) {
+	args := repository.Mock.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.OrderItem), nil
+}
+
+// GetOpenOrders implements [OrderItemRepository].
+func (repository *OrderItemRepositoryMock) GetOpenOrders(tenantId, storeId, limit, page int) ([]*model.OrderItem, int, error) {
+	args := repository.Mock.Called(tenantId, storeId, limit, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.OrderItem), args.Int(1), nil
+}
+
+// GetStatusHistory implements [OrderItemRepository].
+func (repository *OrderItemRepositoryMock) GetStatusHistory(orderItemId, tenantId int) ([]*model.OrderStatusHistory, error) {
+	args := repository.Mock.Called(orderItemId, tenantId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*model.OrderStatusHistory), nil
+}
+
+// GetSalesSeries implements [OrderItemRepository].
+func (repository *OrderItemRepositoryMock) GetSalesSeries(tenantId int, storeId int, bucket SalesBucket, timezone string, dateFilter *query.DateFilter) ([]*SalesSeriesPoint, error) {
+	args := repository.Mock.Called(tenantId, storeId, bucket, timezone, dateFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*SalesSeriesPoint), nil
+}
+
+// GetTenantTimezone implements [OrderItemRepository].
+func (repository *OrderItemRepositoryMock) GetTenantTimezone(tenantId int) (string, error) {
+	args := repository.Mock.Called(tenantId)
+	return args.String(0), args.Error(1)
+}
+
+// GetCategoryReport implements [OrderItemRepository].
+func (repository *OrderItemRepositoryMock) GetCategoryReport(tenantId int, storeId int, dateFilter *query.DateFilter) ([]*CategoryReportRow, error) {
+	args := repository.Mock.Called(tenantId, storeId, dateFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*CategoryReportRow), nil
+}
+
+// GetStoreComparison implements [OrderItemRepository].
+func (repository *OrderItemRepositoryMock) GetStoreComparison(tenantId int, dateFilter *query.DateFilter) ([]*StoreComparisonRow, error) {
+	args := repository.Mock.Called(tenantId, dateFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*StoreComparisonRow), nil
+}
@@ -45,6 +45,15 @@ func seedOrderItemTestDependencies(t *testing.T, tx *gorm.DB) (tenantId int, sto
 	return tenant.Id, store.Id
 }
 
+// tenantOwnerId returns the user seeded by seedOrderItemTestDependencies
+func tenantOwnerId(t *testing.T, tx *gorm.DB, tenantId int) int {
+	t.Helper()
+
+	var tenant model.Tenant
+	require.NoError(t, tx.Where("id = ?", tenantId).Take(&tenant).Error)
+	return tenant.OwnerUserId
+}
+
 func TestOrderItemRepository(t *testing.T) {
 	var gormClient *gorm.DB = client.CreateGormClient()
 
@@ -255,7 +264,7 @@ func TestOrderItemRepository(t *testing.T) {
 				assert.NotZero(t, result.Id)
 			}
 
-			results, count, err := orderItemRepo.Get(tenantId, 0, 5, 0, nil, nil)
+			results, count, err := orderItemRepo.Get(tenantId, 0, 0, 5, 0, nil, nil)
 
 			assert.Nil(t, err)
 			assert.Equal(t, 5, count)
@@ -301,6 +310,7 @@ func TestOrderItemRepository(t *testing.T) {
 			results, count, err := orderItemRepo.Get(
 				tenantId,
 				0,
+				0,
 				5,
 				0,
 				[]*query.QueryFilter{
@@ -343,6 +353,7 @@ func TestOrderItemRepository(t *testing.T) {
 			results, count, err := orderItemRepo.Get(
 				tenantId,
 				0,
+				0,
 				5,
 				0,
 				[]*query.QueryFilter{
@@ -370,6 +381,9 @@ func TestOrderItemRepository(t *testing.T) {
 	t.Run("GetSalesReport", func(t *testing.T) {
 		t.Skip("DBMS relation too deep")
 	})
+	t.Run("GetSalesSeries", func(t *testing.T) {
+		t.Skip("DBMS relation too deep")
+	})
 
 	t.Run("DeleteInvoice", func(t *testing.T) {
 		t.Run("SuccessCase", func(t *testing.T) {
@@ -391,7 +405,8 @@ func TestOrderItemRepository(t *testing.T) {
 			require.NoError(t, err)
 			require.NotZero(t, created.Id)
 
-			err = repo.DeleteInvoice(created.Id, tenantId)
+			userId := tenantOwnerId(t, tx, tenantId)
+			err = repo.DeleteInvoice(created.Id, tenantId, userId)
 			assert.NoError(t, err)
 
 			// Verify soft deleted — row still exists but deleted_at is set
@@ -399,9 +414,14 @@ func TestOrderItemRepository(t *testing.T) {
 			err = tx.Unscoped().First(&deleted, created.Id).Error
 			assert.NoError(t, err)
 			assert.True(t, deleted.DeletedAt.Valid)
+			require.NotNil(t, deleted.VoidedByUserId)
+			assert.Equal(t, userId, *deleted.VoidedByUserId)
+
+			// Voided twice is not allowed
+			assert.Error(t, repo.DeleteInvoice(created.Id, tenantId, userId))
 
 			// Verify excluded from normal queries
-			results, count, err := repo.Get(tenantId, 0, 10, 0, nil, nil)
+			results, count, err := repo.Get(tenantId, 0, 0, 10, 0, nil, nil)
 			assert.NoError(t, err)
 			assert.Equal(t, 0, count)
 			assert.Len(t, results, 0)
@@ -415,7 +435,7 @@ func TestOrderItemRepository(t *testing.T) {
 			repo := NewOrderItemRepositoryImpl(tx)
 
 			// id: 1 should not exist
-			err := repo.DeleteInvoice(1, tenantId)
+			err := repo.DeleteInvoice(1, tenantId, tenantOwnerId(t, tx, tenantId))
 
 			// RowsAffected = 0 now returns an error since we added the check
 			assert.Error(t, err)
@@ -440,7 +460,7 @@ func TestOrderItemRepository(t *testing.T) {
 			require.NoError(t, err)
 			require.NotZero(t, created.Id)
 
-			err = repo.DeleteInvoice(created.Id, tenantId+1)
+			err = repo.DeleteInvoice(created.Id, tenantId+1, tenantOwnerId(t, tx, tenantId))
 			assert.Error(t, err)
 
 			// Verify the record is untouched
@@ -450,4 +470,296 @@ func TestOrderItemRepository(t *testing.T) {
 			assert.False(t, untouched.DeletedAt.Valid)
 		})
 	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		// Placed order is not paid yet, purchased_price is given when COMPLETED
+		place := func(t *testing.T, tx *gorm.DB) (*model.OrderItem, int) {
+			tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+
+			var tenant model.Tenant
+			require.NoError(t, tx.Where("id = ?", tenantId).Take(&tenant).Error)
+
+			created, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+				TotalQuantity: 1,
+				TotalAmount:   10000,
+				Subtotal:      10000,
+				TenantId:      tenantId,
+				StoreId:       storeId,
+				Status:        model.OrderStatusPlaced,
+			})
+			require.NoError(t, err)
+
+			return created, tenant.OwnerUserId
+		}
+
+		t.Run("FullLifecycle", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			created, userId := place(t, tx)
+			repo := NewOrderItemRepositoryImpl(tx)
+
+			openOrders, count, err := repo.GetOpenOrders(created.TenantId, created.StoreId, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.Equal(t, created.Id, openOrders[0].Id)
+
+			for _, status := range []model.OrderStatus{model.OrderStatusInPreparation, model.OrderStatusReady} {
+				updated, err := repo.UpdateStatus(&UpdateOrderStatusParams{
+					OrderItemId: created.Id, TenantId: created.TenantId, UserId: userId, Status: status,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, status, updated.Status)
+			}
+
+			// Not paid yet
+			_, err = repo.UpdateStatus(&UpdateOrderStatusParams{
+				OrderItemId: created.Id, TenantId: created.TenantId, UserId: userId, Status: model.OrderStatusCompleted,
+			})
+			assert.ErrorContains(t, err, "Insufficient payment")
+
+			completed, err := repo.UpdateStatus(&UpdateOrderStatusParams{
+				OrderItemId: created.Id, TenantId: created.TenantId, UserId: userId,
+				Status: model.OrderStatusCompleted, PurchasedPrice: 10000,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 10000, completed.PurchasedPrice)
+
+			_, count, err = repo.GetOpenOrders(created.TenantId, created.StoreId, 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, 0, count)
+
+			history, err := repo.GetStatusHistory(created.Id, created.TenantId)
+			require.NoError(t, err)
+			require.Len(t, history, 3)
+			assert.Equal(t, model.OrderStatusPlaced, history[0].FromStatus)
+			assert.Equal(t, model.OrderStatusCompleted, history[2].ToStatus)
+			assert.Equal(t, userId, history[2].UserId)
+		})
+
+		t.Run("InvalidTransition", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			created, userId := place(t, tx)
+
+			_, err := NewOrderItemRepositoryImpl(tx).UpdateStatus(&UpdateOrderStatusParams{
+				OrderItemId: created.Id, TenantId: created.TenantId, UserId: userId,
+				Status: model.OrderStatusCompleted, PurchasedPrice: 10000,
+			})
+			assert.ErrorContains(t, err, "could not move from PLACED to COMPLETED")
+		})
+
+		t.Run("CancelReturnStock", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			created, userId := place(t, tx)
+
+			tracked := &model.Item{ItemName: "Tracked Item", Stocks: 5, StockType: model.StockTypeTracked, BasePrice: 1000, TenantId: created.TenantId, IsActive: true}
+			unlimited := &model.Item{ItemName: "Unlimited Item", StockType: model.StockTypeUnlimited, BasePrice: 1000, TenantId: created.TenantId, IsActive: true}
+			require.NoError(t, tx.Create(tracked).Error)
+			require.NoError(t, tx.Create(unlimited).Error)
+
+			// Stock already taken when the order was placed
+			trackedStock := &model.StoreStock{Stocks: 1, Price: 5000, ItemId: tracked.ItemId, TenantId: created.TenantId, StoreId: created.StoreId}
+			unlimitedStock := &model.StoreStock{Stocks: 0, Price: 5000, ItemId: unlimited.ItemId, TenantId: created.TenantId, StoreId: created.StoreId}
+			require.NoError(t, tx.Create(trackedStock).Error)
+			require.NoError(t, tx.Create(unlimitedStock).Error)
+			for _, line := range []*model.PurchasedItem{
+				{ItemId: tracked.ItemId, ItemNameSnapshot: tracked.ItemName, Quantity: 2, StorePriceSnapshot: 5000, BasePriceSnapshot: 1000, TotalAmount: 10000},
+				{ItemId: unlimited.ItemId, ItemNameSnapshot: unlimited.ItemName, Quantity: 1, StorePriceSnapshot: 5000, BasePriceSnapshot: 1000, TotalAmount: 5000},
+			} {
+				line.OrderItemId = created.Id
+				require.NoError(t, tx.Create(line).Error)
+			}
+
+			_, err := NewOrderItemRepositoryImpl(tx).UpdateStatus(&UpdateOrderStatusParams{
+				OrderItemId: created.Id, TenantId: created.TenantId, UserId: userId, Status: model.OrderStatusCancelled,
+			})
+			require.NoError(t, err)
+
+			// Not a void, the order stay visible as CANCELLED
+			var cancelled model.OrderItem
+			require.NoError(t, tx.First(&cancelled, created.Id).Error)
+			assert.Equal(t, model.OrderStatusCancelled, cancelled.Status)
+			assert.Nil(t, cancelled.VoidedByUserId)
+
+			require.NoError(t, tx.First(trackedStock, trackedStock.Id).Error)
+			assert.Equal(t, 3, trackedStock.Stocks)
+			require.NoError(t, tx.First(unlimitedStock, unlimitedStock.Id).Error)
+			assert.Equal(t, 0, unlimitedStock.Stocks)
+		})
+
+		t.Run("CompletedEarnPoints", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			created, userId := place(t, tx)
+
+			customer, err := NewCustomerRepositoryImpl(tx).Create(&model.Customer{Name: "Placed", Phone: "081277777777", TenantId: created.TenantId})
+			require.NoError(t, err)
+			require.NoError(t, tx.Model(created).Update("customer_id", customer.Id).Error)
+
+			loyaltyRepo := NewLoyaltyRepositoryImpl(tx)
+			_, err = loyaltyRepo.SaveSetting(&model.LoyaltySetting{
+				TenantId: created.TenantId, EarnAmount: 1000, EarnPoints: 1, Rounding: model.LoyaltyRoundingFloor, RedeemValue: 100, IsActive: true,
+			})
+			require.NoError(t, err)
+
+			repo := NewOrderItemRepositoryImpl(tx)
+			for _, status := range []model.OrderStatus{model.OrderStatusInPreparation, model.OrderStatusReady} {
+				_, err := repo.UpdateStatus(&UpdateOrderStatusParams{
+					OrderItemId: created.Id, TenantId: created.TenantId, UserId: userId, Status: status,
+				})
+				require.NoError(t, err)
+			}
+
+			// Open order is not a sale yet
+			balance, err := loyaltyRepo.GetBalance(customer.Id, created.TenantId)
+			require.NoError(t, err)
+			assert.Equal(t, 0, balance)
+
+			report, err := repo.GetSalesReport(created.TenantId, created.StoreId, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 0, report.SumTransactions)
+
+			_, err = repo.UpdateStatus(&UpdateOrderStatusParams{
+				OrderItemId: created.Id, TenantId: created.TenantId, UserId: userId,
+				Status: model.OrderStatusCompleted, PurchasedPrice: 10000,
+			})
+			require.NoError(t, err)
+
+			balance, err = loyaltyRepo.GetBalance(customer.Id, created.TenantId)
+			require.NoError(t, err)
+			assert.Equal(t, 10, balance)
+
+			report, err = repo.GetSalesReport(created.TenantId, created.StoreId, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 1, report.SumTransactions)
+			assert.Equal(t, 10000, report.SumTotalAmount)
+		})
+
+		t.Run("WrongTenant", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			created, userId := place(t, tx)
+
+			_, err := NewOrderItemRepositoryImpl(tx).UpdateStatus(&UpdateOrderStatusParams{
+				OrderItemId: created.Id, TenantId: created.TenantId + 1, UserId: userId, Status: model.OrderStatusInPreparation,
+			})
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("GetCategoryReport", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+
+		repo := NewOrderItemRepositoryImpl(tx)
+		rows, err := repo.GetCategoryReport(tenantId, storeId, nil)
+		require.NoError(t, err)
+		assert.Len(t, rows, 0)
+
+		// Item of 2 categories is counted once, at the first one
+		item := &model.Item{ItemName: "Category Item", Stocks: 5, StockType: model.StockTypeTracked, BasePrice: 1000, TenantId: tenantId, IsActive: true}
+		require.NoError(t, tx.Create(item).Error)
+		drinks := &model.Category{CategoryName: "Drinks", TenantId: tenantId}
+		require.NoError(t, tx.Create(drinks).Error)
+		snacks := &model.Category{CategoryName: "Snacks", TenantId: tenantId}
+		require.NoError(t, tx.Create(snacks).Error)
+		for _, category := range []*model.Category{drinks, snacks} {
+			require.NoError(t, tx.Create(&model.CategoryMtmWarehouse{CategoryId: category.Id, ItemId: item.ItemId}).Error)
+		}
+
+		orderItem, err := repo.PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 4000,
+			TotalQuantity:  2,
+			TotalAmount:    4000,
+			Subtotal:       4000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+		})
+		require.NoError(t, err)
+		require.NoError(t, tx.Create(&model.PurchasedItem{
+			ItemId: item.ItemId, ItemNameSnapshot: item.ItemName, Quantity: 2, StorePriceSnapshot: 2000, BasePriceSnapshot: 1000,
+			TotalAmount: 4000, OrderItemId: orderItem.Id,
+		}).Error)
+
+		rows, err = repo.GetCategoryReport(tenantId, storeId, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, drinks.Id, rows[0].CategoryId)
+		assert.Equal(t, "Drinks", rows[0].CategoryName)
+		assert.Equal(t, 2, rows[0].TotalQuantity)
+		assert.Equal(t, 4000, rows[0].TotalRevenue)
+		assert.Equal(t, 2000, rows[0].TotalProfit)
+	})
+
+	t.Run("GetProfitReport", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+
+		// Points discount of 400 is shared by the line total, 300 and 100
+		coffee := &model.Item{ItemName: "Profit Coffee", Stocks: 5, StockType: model.StockTypeTracked, BasePrice: 1000, TenantId: tenantId, IsActive: true}
+		require.NoError(t, tx.Create(coffee).Error)
+		bread := &model.Item{ItemName: "Profit Bread", Stocks: 5, StockType: model.StockTypeTracked, BasePrice: 500, TenantId: tenantId, IsActive: true}
+		require.NoError(t, tx.Create(bread).Error)
+
+		repo := NewOrderItemRepositoryImpl(tx)
+		orderItem, err := repo.PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 4000,
+			TotalQuantity:  2,
+			DiscountAmount: 400,
+			PointsDiscount: 400,
+			TotalAmount:    3600,
+			Subtotal:       4000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+		})
+		require.NoError(t, err)
+		require.NoError(t, tx.Create(&model.PurchasedItem{
+			ItemId: coffee.ItemId, ItemNameSnapshot: coffee.ItemName, Quantity: 1, StorePriceSnapshot: 3000, BasePriceSnapshot: 1000,
+			TotalAmount: 3000, OrderItemId: orderItem.Id,
+		}).Error)
+		require.NoError(t, tx.Create(&model.PurchasedItem{
+			ItemId: bread.ItemId, ItemNameSnapshot: bread.ItemName, Quantity: 1, StorePriceSnapshot: 1000, BasePriceSnapshot: 500,
+			TotalAmount: 1000, OrderItemId: orderItem.Id,
+		}).Error)
+
+		rows, err := repo.GetProfitReport(tenantId, storeId, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, coffee.ItemId, rows[0].ItemId)
+		assert.Equal(t, 2700, rows[0].TotalRevenue)
+		assert.Equal(t, 1700, rows[0].TotalProfit)
+		assert.Equal(t, bread.ItemId, rows[1].ItemId)
+		assert.Equal(t, 900, rows[1].TotalRevenue)
+		assert.Equal(t, 400, rows[1].TotalProfit)
+
+		report, err := repo.GetSalesReport(tenantId, storeId, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3600, report.SumTotalAmount)
+		assert.Equal(t, 2100, report.SumProfit)
+	})
+
+	t.Run("GetStoreComparison", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+
+		// Store without sales is still listed
+		rows, err := NewOrderItemRepositoryImpl(tx).GetStoreComparison(tenantId, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, storeId, rows[0].StoreId)
+		assert.Zero(t, rows[0].SumTotalAmount)
+		assert.Zero(t, rows[0].SumProfit)
+	})
 }
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+)
+
+/*
+Parked (draft) orders, scoped by tenant and store.
+Conversion into a sale is done by OrderItemRepository.Transactions
+with CreateTransactionParams.ParkedOrderId
+*/
+type ParkedOrderRepository interface {
+	/*
+		Insert the parked order with its lines
+	*/
+	Create(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error)
+
+	/*
+		Get PARKED and not expired orders of a store (oldest first), lines included
+		2nd params return is the count of all data
+	*/
+	Get(tenantId, storeId, limit, page int) ([]*model.ParkedOrder, int, error)
+
+	/*
+		Return 1 parked order with its lines, any status
+	*/
+	FindById(parkedOrderId, tenantId int) (*model.ParkedOrder, error)
+
+	/*
+		Replace customer, notes, expiry and lines. parkedOrder.Version should be
+		the current version, the returned parked order has the next version
+	*/
+	Edit(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error)
+
+	/*
+		Set PARKED order into CANCELLED
+	*/
+	Cancel(parkedOrderId, tenantId int) error
+
+	/*
+		Set every PARKED order that pass expires_at into EXPIRED (all tenant),
+		return the count of expired orders
+	*/
+	ExpireParkedOrders(now time.Time) (int, error)
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const ParkedOrderTable string = "parked_order"
+const ParkedOrderLineTable string = "parked_order_line"
+
+type ParkedOrderRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewParkedOrderRepositoryImpl(client *gorm.DB) ParkedOrderRepository {
+	return &ParkedOrderRepositoryImpl{Client: client}
+}
+
+/*
+checkParkedOrderOwnership make sure store and customer (optional) belong to the tenant
+*/
+func checkParkedOrderOwnership(tx *gorm.DB, parkedOrder *model.ParkedOrder) error {
+	var count int64
+	if err := tx.Model(&model.Store{}).
+		Where("id = ? AND tenant_id = ?", parkedOrder.StoreId, parkedOrder.TenantId).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("Store %d not found for tenant %d", parkedOrder.StoreId, parkedOrder.TenantId)
+	}
+
+	if parkedOrder.CustomerId != nil {
+		if err := tx.Model(&model.Customer{}).
+			Where("id = ? AND tenant_id = ?", *parkedOrder.CustomerId, parkedOrder.TenantId).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("Customer %d not found for tenant %d", *parkedOrder.CustomerId, parkedOrder.TenantId)
+		}
+	}
+
+	return nil
+}
+
+/*
+lockParkedOrder select the parked order FOR UPDATE, it must be PARKED and not expired
+*/
+func lockParkedOrder(tx *gorm.DB, parkedOrderId, tenantId int) (*model.ParkedOrder, error) {
+	var parkedOrder model.ParkedOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND tenant_id = ?", parkedOrderId, tenantId).
+		Take(&parkedOrder).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("No parked order found with id %d", parkedOrderId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if parkedOrder.Status != model.ParkedOrderStatusParked {
+		return nil, fmt.Errorf("Parked order %d is already %s", parkedOrderId, parkedOrder.Status)
+	}
+	if !parkedOrder.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("Parked order %d is expired", parkedOrderId)
+	}
+
+	return &parkedOrder, nil
+}
+
+// Create implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryImpl) Create(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error) {
+	parkedOrder.Status = model.ParkedOrderStatusParked
+	parkedOrder.Version = 1
+
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		if err := checkParkedOrderOwnership(tx, parkedOrder); err != nil {
+			return err
+		}
+
+		// Lines are inserted by association
+		return tx.Create(parkedOrder).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parkedOrder, nil
+}
+
+// Get implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryImpl) Get(tenantId, storeId, limit, page int) ([]*model.ParkedOrder, int, error) {
+	offset := page * limit
+
+	var parkedOrders = make([]*model.ParkedOrder, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.ParkedOrder{}).
+		Where("tenant_id = ? AND store_id = ?", tenantId, storeId).
+		Where("status = ? AND expires_at > ?", model.ParkedOrderStatusParked, time.Now())
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&parkedOrders).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return parkedOrders, int(totalCount), nil
+}
+
+// FindById implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryImpl) FindById(parkedOrderId, tenantId int) (*model.ParkedOrder, error) {
+	var parkedOrder model.ParkedOrder
+	err := repository.Client.
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("id = ? AND tenant_id = ?", parkedOrderId, tenantId).
+		Take(&parkedOrder).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("No parked order found with id %d", parkedOrderId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &parkedOrder, nil
+}
+
+// Edit implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryImpl) Edit(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error) {
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		current, err := lockParkedOrder(tx, parkedOrder.Id, parkedOrder.TenantId)
+		if err != nil {
+			return err
+		}
+		if current.Version != parkedOrder.Version {
+			return fmt.Errorf("Parked order %d was changed by another till, reload it first (version %d, given %d)", parkedOrder.Id, current.Version, parkedOrder.Version)
+		}
+
+		// Store never change, the order stay at the same store
+		parkedOrder.StoreId = current.StoreId
+		if err := checkParkedOrderOwnership(tx, parkedOrder); err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.ParkedOrder{}).
+			Where("id = ?", parkedOrder.Id).
+			Updates(map[string]any{
+				"customer_id": parkedOrder.CustomerId,
+				"notes":       parkedOrder.Notes,
+				"expires_at":  parkedOrder.ExpiresAt,
+				"version":     current.Version + 1,
+				"updated_at":  time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("parked_order_id = ?", parkedOrder.Id).Delete(&model.ParkedOrderLine{}).Error; err != nil {
+			return err
+		}
+
+		for _, line := range parkedOrder.Lines {
+			line.Id = 0
+			line.ParkedOrderId = parkedOrder.Id
+		}
+		return tx.Create(&parkedOrder.Lines).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindById(parkedOrder.Id, parkedOrder.TenantId)
+}
+
+// Cancel implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryImpl) Cancel(parkedOrderId, tenantId int) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockParkedOrder(tx, parkedOrderId, tenantId); err != nil {
+			return err
+		}
+
+		return tx.Model(&model.ParkedOrder{}).
+			Where("id = ?", parkedOrderId).
+			Updates(map[string]any{
+				"status":     model.ParkedOrderStatusCancelled,
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+// ExpireParkedOrders implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryImpl) ExpireParkedOrders(now time.Time) (int, error) {
+	result := repository.Client.Model(&model.ParkedOrder{}).
+		Where("status = ? AND expires_at <= ?", model.ParkedOrderStatusParked, now).
+		Updates(map[string]any{
+			"status":     model.ParkedOrderStatusExpired,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
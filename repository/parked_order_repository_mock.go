@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ParkedOrderRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewParkedOrderRepositoryMock(mock *mock.Mock) ParkedOrderRepository {
+	return &ParkedOrderRepositoryMock{Mock: mock}
+}
+
+// Create implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryMock) Create(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error) {
+	args := repository.Mock.Called(parkedOrder)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ParkedOrder), nil
+}
+
+// Get implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryMock) Get(tenantId, storeId, limit, page int) ([]*model.ParkedOrder, int, error) {
+	args := repository.Mock.Called(tenantId, storeId, limit, page)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.ParkedOrder), args.Int(1), nil
+}
+
+// FindById implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryMock) FindById(parkedOrderId, tenantId int) (*model.ParkedOrder, error) {
+	args := repository.Mock.Called(parkedOrderId, tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ParkedOrder), nil
+}
+
+// Edit implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryMock) Edit(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error) {
+	args := repository.Mock.Called(parkedOrder)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ParkedOrder), nil
+}
+
+// Cancel implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryMock) Cancel(parkedOrderId, tenantId int) error {
+	args := repository.Mock.Called(parkedOrderId, tenantId)
+	return args.Error(0)
+}
+
+// ExpireParkedOrders implements ParkedOrderRepository.
+func (repository *ParkedOrderRepositoryMock) ExpireParkedOrders(now time.Time) (int, error) {
+	args := repository.Mock.Called(now)
+	return args.Int(0), args.Error(1)
+}
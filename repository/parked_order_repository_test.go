@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestParkedOrderRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	// seed tenant & store once per transaction
+	seed := func(t *testing.T, tx *gorm.DB) *model.Store {
+		_, storeId := seedOrderItemTestDependencies(t, tx)
+
+		var store model.Store
+		require.NoError(t, tx.Where("id = ?", storeId).Take(&store).Error)
+		return &store
+	}
+
+	park := func(t *testing.T, tx *gorm.DB, store *model.Store, expiresAt time.Time) *model.ParkedOrder {
+		var tenant model.Tenant
+		require.NoError(t, tx.Where("id = ?", store.TenantId).Take(&tenant).Error)
+
+		parkedOrder, err := NewParkedOrderRepositoryImpl(tx).Create(&model.ParkedOrder{
+			TenantId:       store.TenantId,
+			StoreId:        store.Id,
+			Notes:          "Customer fetch wallet",
+			ParkedByUserId: tenant.OwnerUserId,
+			ExpiresAt:      expiresAt,
+			Lines: []*model.ParkedOrderLine{
+				{ItemId: 1, Quantity: 2, StorePriceSnapshot: 10_000, ItemNameSnapshot: "Item A"},
+			},
+		})
+		require.NoError(t, err)
+		return parkedOrder
+	}
+
+	t.Run("CreateAndResume", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		parkedOrder := park(t, tx, seed(t, tx), time.Now().Add(time.Hour))
+		parkedOrderRepo := NewParkedOrderRepositoryImpl(tx)
+
+		assert.Equal(t, model.ParkedOrderStatusParked, parkedOrder.Status)
+		assert.Equal(t, 1, parkedOrder.Version)
+
+		parkedOrders, count, err := parkedOrderRepo.Get(parkedOrder.TenantId, parkedOrder.StoreId, 10, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Len(t, parkedOrders[0].Lines, 1)
+
+		resumed, err := parkedOrderRepo.FindById(parkedOrder.Id, parkedOrder.TenantId)
+		assert.NoError(t, err)
+		assert.Equal(t, "Item A", resumed.Lines[0].ItemNameSnapshot)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		parkedOrder := park(t, tx, seed(t, tx), time.Now().Add(time.Hour))
+		parkedOrderRepo := NewParkedOrderRepositoryImpl(tx)
+
+		editedParkedOrder, err := parkedOrderRepo.Edit(&model.ParkedOrder{
+			Id:        parkedOrder.Id,
+			TenantId:  parkedOrder.TenantId,
+			Notes:     "Add 1 more",
+			Version:   1,
+			ExpiresAt: time.Now().Add(time.Hour),
+			Lines: []*model.ParkedOrderLine{
+				{ItemId: 1, Quantity: 3, StorePriceSnapshot: 10_000, ItemNameSnapshot: "Item A"},
+				{ItemId: 2, Quantity: 1, StorePriceSnapshot: 5_000, ItemNameSnapshot: "Item B"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, editedParkedOrder.Version)
+		assert.Len(t, editedParkedOrder.Lines, 2)
+
+		// Another till still has version 1
+		_, err = parkedOrderRepo.Edit(&model.ParkedOrder{
+			Id:        parkedOrder.Id,
+			TenantId:  parkedOrder.TenantId,
+			Version:   1,
+			ExpiresAt: time.Now().Add(time.Hour),
+			Lines:     []*model.ParkedOrderLine{{ItemId: 1, Quantity: 1, ItemNameSnapshot: "Item A"}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("CancelAndExpire", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		store := seed(t, tx)
+		parkedOrder := park(t, tx, store, time.Now().Add(time.Hour))
+		parkedOrderRepo := NewParkedOrderRepositoryImpl(tx)
+
+		require.NoError(t, parkedOrderRepo.Cancel(parkedOrder.Id, parkedOrder.TenantId))
+		assert.Error(t, parkedOrderRepo.Cancel(parkedOrder.Id, parkedOrder.TenantId))
+
+		expiredOrder := park(t, tx, store, time.Now().Add(time.Minute))
+		expired, err := parkedOrderRepo.ExpireParkedOrders(time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, expired, 1)
+
+		found, err := parkedOrderRepo.FindById(expiredOrder.Id, expiredOrder.TenantId)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ParkedOrderStatusExpired, found.Status)
+	})
+}
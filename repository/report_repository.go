@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"cashier-api/helper/query"
+	"time"
+)
+
+/*
+Read only analytics across order_item, purchased_item_list and stocks.
+Soft deleted (and cancelled) order never counted
+*/
+type ReportRepository interface {
+	/*
+		Items sold in the period ordered by the metric (highest first).
+		storeId = 0 and categoryId = 0 will not filter, limit = 0 return every item
+	*/
+	GetItemRanking(tenantId, storeId, categoryId int, metric ItemRankingMetric, dateFilter *query.DateFilter, limit int) ([]*ItemRankingRow, error)
+
+	/*
+		Active items that still have stock but not sold since the given time (never sold first)
+		2nd params return is the count of all data
+	*/
+	GetSlowMovers(tenantId, storeId, categoryId int, since time.Time, limit, page int) ([]*SlowMoverRow, int, error)
+
+	/*
+		Sales, voids and refunds done by every user of the tenant (highest revenue first),
+		member without activity is included with zero. storeId = 0 will not filter.
+		Void (of a COMPLETED sale only) is filtered by the time it was voided, refund is store credit issued
+	*/
+	GetCashierPerformance(tenantId, storeId int, dateFilter *query.DateFilter) ([]*CashierPerformanceRow, error)
+
+	/*
+		Value of the stock on hand per location and category, at cost (base price) and at retail (store price).
+		storeId = 0 return the warehouse and every store, otherwise only the store.
+		Stock on hand now, there is no stock history to value a past date
+	*/
+	GetInventoryValuation(tenantId, storeId int) ([]*InventoryValuationRow, error)
+
+	/*
+		Timezone of the tenant, the day of a sale is the tenant local day
+	*/
+	GetTenantTimezone(tenantId int) (string, error)
+
+	/*
+		Quantity sold per store, item and local day of [since, until).
+		A day without sale has no row. storeId = 0 will not filter
+	*/
+	GetDailyItemSales(tenantId, storeId int, timezone string, since, until time.Time) ([]*DailyItemSalesRow, error)
+
+	/*
+		Current stock of the tracked active items per location, ordered by store id then item id.
+		storeId = 0 return the warehouse (StoreId 0) and every store, otherwise only the store
+	*/
+	GetReorderStocks(tenantId, storeId int) ([]*ReorderStockRow, error)
+}
+
+type ItemRankingMetric string
+
+const (
+	ItemRankingByQuantity ItemRankingMetric = "quantity"
+	ItemRankingByRevenue  ItemRankingMetric = "revenue"
+	ItemRankingByProfit   ItemRankingMetric = "profit"
+)
+
+func (metric ItemRankingMetric) IsValid() bool {
+	switch metric {
+	case ItemRankingByQuantity, ItemRankingByRevenue, ItemRankingByProfit:
+		return true
+	}
+	return false
+}
+
+type ItemRankingRow struct {
+	ItemId        int    `json:"item_id"        gorm:"column:item_id"`
+	ItemName      string `json:"item_name"      gorm:"column:item_name"`
+	TotalQuantity int    `json:"total_quantity" gorm:"column:total_quantity"`
+	TotalRevenue  int    `json:"total_revenue"  gorm:"column:total_revenue"`
+	TotalProfit   int    `json:"total_profit"   gorm:"column:total_profit"`
+}
+
+type SlowMoverRow struct {
+	ItemId     int        `json:"item_id"      gorm:"column:item_id"`
+	ItemName   string     `json:"item_name"    gorm:"column:item_name"`
+	Stocks     int        `json:"stocks"       gorm:"column:stocks"`       // store stock, or warehouse + every store when storeId = 0
+	LastSoldAt *time.Time `json:"last_sold_at" gorm:"column:last_sold_at"` // nil means never sold
+}
+
+/*
+Hours worked is not part of it, shift is not recorded yet
+*/
+type CashierPerformanceRow struct {
+	UserId           int     `json:"user_id"           gorm:"column:user_id"`
+	UserName         string  `json:"user_name"         gorm:"column:user_name"`
+	TransactionCount int     `json:"transaction_count" gorm:"column:transaction_count"`
+	TotalQuantity    int     `json:"total_quantity"    gorm:"column:total_quantity"`
+	TotalSubtotal    int     `json:"total_subtotal"    gorm:"column:total_subtotal"` // before discount
+	TotalRevenue     int     `json:"total_revenue"     gorm:"column:total_revenue"`
+	TotalDiscount    int     `json:"total_discount"    gorm:"column:total_discount"`
+	VoidCount        int     `json:"void_count"        gorm:"column:void_count"`
+	VoidAmount       int     `json:"void_amount"       gorm:"column:void_amount"`
+	RefundCount      int     `json:"refund_count"      gorm:"column:refund_count"`
+	RefundAmount     int     `json:"refund_amount"     gorm:"column:refund_amount"`
+	AverageBasket    float64 `json:"average_basket"    gorm:"-"` // revenue per transaction, filled by service
+	DiscountRate     float64 `json:"discount_rate"     gorm:"-"` // 0 - 100 of subtotal, filled by service
+}
+
+type ABCClass string
+
+const (
+	ABCClassA ABCClass = "A" // First 80% of the revenue
+	ABCClassB ABCClass = "B" // Next 15%
+	ABCClassC ABCClass = "C" // Last 5%
+)
+
+type ABCItem struct {
+	ItemRankingRow
+	RevenueShare    float64  `json:"revenue_share"`    // 0 - 100
+	CumulativeShare float64  `json:"cumulative_share"` // 0 - 100, including this item
+	Class           ABCClass `json:"class"`
+}
+
+type ABCAnalysis struct {
+	TotalRevenue int        `json:"total_revenue"`
+	CountA       int        `json:"count_a"`
+	CountB       int        `json:"count_b"`
+	CountC       int        `json:"count_c"`
+	Items        []*ABCItem `json:"items"` // Highest revenue first
+}
+
+/*
+InventoryValuationRow is 1 category at 1 location (StoreId 0 is the warehouse).
+An item in many categories is counted once, in its first category (lowest id),
+so the rows add up to the location total. Unlimited stock and stock <= 0 are not counted
+*/
+type InventoryValuationRow struct {
+	StoreId         int    `json:"store_id"         gorm:"column:store_id"`
+	LocationName    string `json:"location_name"    gorm:"column:location_name"`
+	CategoryId      int    `json:"category_id"      gorm:"column:category_id"` // 0 is uncategorized
+	CategoryName    string `json:"category_name"    gorm:"column:category_name"`
+	ItemCount       int    `json:"item_count"       gorm:"column:item_count"`
+	TotalStocks     int    `json:"total_stocks"     gorm:"column:total_stocks"`
+	CostValue       int    `json:"cost_value"       gorm:"column:cost_value"`
+	RetailValue     int    `json:"retail_value"     gorm:"column:retail_value"` // 0 for the warehouse, it has no selling price
+	PotentialProfit int    `json:"potential_profit" gorm:"-"`                   // retail - cost, store only, filled by service
+}
+
+type InventoryValuationLocation struct {
+	StoreId         int                      `json:"store_id"` // 0 is the warehouse
+	LocationName    string                   `json:"location_name"`
+	ItemCount       int                      `json:"item_count"`
+	TotalStocks     int                      `json:"total_stocks"`
+	CostValue       int                      `json:"cost_value"`
+	RetailValue     int                      `json:"retail_value"`
+	PotentialProfit int                      `json:"potential_profit"`
+	Categories      []*InventoryValuationRow `json:"categories"` // Highest cost value first
+}
+
+// Category across every location of the valuation
+type InventoryValuationCategory struct {
+	CategoryId   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	TotalStocks  int    `json:"total_stocks"`
+	CostValue    int    `json:"cost_value"`
+	RetailValue  int    `json:"retail_value"`
+}
+
+type InventoryValuation struct {
+	TotalCostValue       int                           `json:"total_cost_value"`
+	TotalRetailValue     int                           `json:"total_retail_value"`     // Store stock only
+	TotalPotentialProfit int                           `json:"total_potential_profit"` // Store stock only
+	Locations            []*InventoryValuationLocation `json:"locations"`              // Warehouse first, then by store id
+	Categories           []*InventoryValuationCategory `json:"categories"`             // Highest cost value first
+}
+
+type DailyItemSalesRow struct {
+	StoreId  int       `json:"store_id"  gorm:"column:store_id"`
+	ItemId   int       `json:"item_id"   gorm:"column:item_id"`
+	SaleDate time.Time `json:"sale_date" gorm:"column:sale_date"` // Local day, the time zone of the value is meaningless
+	Quantity int       `json:"quantity"  gorm:"column:quantity"`
+}
+
+type ReorderStockRow struct {
+	StoreId      int    `json:"store_id"      gorm:"column:store_id"` // 0 is the warehouse
+	LocationName string `json:"location_name" gorm:"column:location_name"`
+	ItemId       int    `json:"item_id"       gorm:"column:item_id"`
+	ItemName     string `json:"item_name"     gorm:"column:item_name"`
+	Stocks       int    `json:"stocks"        gorm:"column:stocks"`
+}
+
+/*
+ReorderSuggestion of 1 item at 1 location.
+
+	A store demand is its own sales, the warehouse demand is the sales of every store
+	and its on hand is the stock of the warehouse and every store (it refills them)
+*/
+type ReorderSuggestion struct {
+	ItemId             int     `json:"item_id"`
+	ItemName           string  `json:"item_name"`
+	OnHand             int     `json:"on_hand"`
+	AverageDailyDemand float64 `json:"average_daily_demand"` // Forecast of the lead time and review days
+	LeadTimeDemand     float64 `json:"lead_time_demand"`
+	SafetyStock        float64 `json:"safety_stock"`
+	ReorderPoint       float64 `json:"reorder_point"`      // Lead time demand + safety stock
+	SuggestedQuantity  int     `json:"suggested_quantity"` // Up to the demand until the next review + safety stock
+}
+
+type ReorderLocation struct {
+	StoreId      int                  `json:"store_id"` // 0 is the warehouse
+	LocationName string               `json:"location_name"`
+	Items        []*ReorderSuggestion `json:"items"` // Highest suggested quantity first
+}
+
+type ReorderSuggestions struct {
+	Timezone     string             `json:"timezone"`
+	HistoryStart time.Time          `json:"history_start"`
+	HistoryEnd   time.Time          `json:"history_end"` // Exclusive, today is not complete yet
+	LeadTimeDays int                `json:"lead_time_days"`
+	ReviewDays   int                `json:"review_days"`
+	ServiceLevel int                `json:"service_level"`
+	Locations    []*ReorderLocation `json:"locations"` // Only location with at least 1 suggestion, warehouse first
+}
@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/model"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewReportRepositoryImpl(client *gorm.DB) ReportRepository {
+	return &ReportRepositoryImpl{Client: client}
+}
+
+/*
+filterByCategory keep item registered at the category of the tenant,
+itemColumn is the qualified item id column. categoryId = 0 will not filter
+*/
+func filterByCategory(db *gorm.DB, itemColumn string, tenantId, categoryId int) *gorm.DB {
+	if categoryId <= 0 {
+		return db
+	}
+
+	return db.Where(`EXISTS (
+		SELECT 1 FROM category_mtm_warehouse cmw
+		INNER JOIN category c ON c.id = cmw.category_id
+		WHERE cmw.item_id = `+itemColumn+` AND c.id = ? AND c.tenant_id = ?
+	)`, categoryId, tenantId)
+}
+
+// GetItemRanking implements ReportRepository.
+func (repository *ReportRepositoryImpl) GetItemRanking(
+	tenantId, storeId, categoryId int,
+	metric ItemRankingMetric,
+	dateFilter *query.DateFilter,
+	limit int,
+) ([]*ItemRankingRow, error) {
+	// metric is one of the constant, safe to be used as ORDER BY
+	var orderBy string
+	switch metric {
+	case ItemRankingByQuantity:
+		orderBy = "total_quantity DESC"
+	case ItemRankingByRevenue:
+		orderBy = "total_revenue DESC"
+	case ItemRankingByProfit:
+		orderBy = "total_profit DESC"
+	default:
+		return nil, fmt.Errorf("Invalid ranking metric: %s", metric)
+	}
+
+	db := applySalesReportFilters(
+		repository.Client.Table("purchased_item_list pil").
+			Joins(completedSalesJoin),
+		"oi.", tenantId, storeId, dateFilter,
+	)
+	db = filterByCategory(db, "pil.item_id", tenantId, categoryId).
+		Select(`
+			pil.item_id,
+			MAX(pil.item_name_snapshot) AS item_name,
+			SUM(pil.quantity) AS total_quantity,
+			SUM(` + lineRevenue + `) AS total_revenue,
+			SUM(` + lineRevenue + `) - SUM(pil.base_price_snapshot * pil.quantity) AS total_profit
+		`).
+		Group("pil.item_id").
+		Order(orderBy).
+		Order("pil.item_id ASC") // Stable order for the same value
+
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var rows = make([]*ItemRankingRow, 0)
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("GetItemRanking failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetSlowMovers implements ReportRepository.
+func (repository *ReportRepositoryImpl) GetSlowMovers(tenantId, storeId, categoryId int, since time.Time, limit, page int) ([]*SlowMoverRow, int, error) {
+	offset := page * limit
+
+	// Last sale of every item, optionally only from the store
+	lastSold := repository.Client.Table("purchased_item_list pil").
+		Select("pil.item_id, MAX(oi.completed_at) AS last_sold_at").
+		Joins(completedSalesJoin).
+		Where("oi.tenant_id = ?", tenantId).
+		Group("pil.item_id")
+
+	var db *gorm.DB
+	if storeId > 0 {
+		lastSold = lastSold.Where("oi.store_id = ?", storeId)
+		db = repository.Client.Table("warehouse w").
+			Select("w.item_id, w.item_name, ss.stocks, ls.last_sold_at").
+			Joins("INNER JOIN store_stock ss ON ss.item_id = w.item_id AND ss.store_id = ?", storeId).
+			Where("ss.stocks > 0")
+	} else {
+		storeStocks := repository.Client.Table("store_stock").
+			Select("item_id, SUM(stocks) AS stocks").
+			Where("tenant_id = ?", tenantId).
+			Group("item_id")
+		db = repository.Client.Table("warehouse w").
+			Select("w.item_id, w.item_name, w.stocks + COALESCE(ss.stocks, 0) AS stocks, ls.last_sold_at").
+			Joins("LEFT JOIN (?) ss ON ss.item_id = w.item_id", storeStocks).
+			Where("w.stocks + COALESCE(ss.stocks, 0) > 0")
+	}
+
+	db = db.
+		Joins("LEFT JOIN (?) ls ON ls.item_id = w.item_id", lastSold).
+		Where("w.tenant_id = ? AND w.is_active = TRUE", tenantId).
+		// Unlimited stock is never counted as overstock
+		Where("w.stock_type <> ?", "UNLIMITED").
+		Where("ls.last_sold_at IS NULL OR ls.last_sold_at < ?", since)
+	db = filterByCategory(db, "w.item_id", tenantId, categoryId)
+
+	var totalCount int64
+	if err := db.Count(&totalCount).Error; err != nil {
+		return nil, 0, fmt.Errorf("GetSlowMovers count failed: %w", err)
+	}
+
+	var rows = make([]*SlowMoverRow, 0)
+	err := db.
+		Order("ls.last_sold_at ASC NULLS FIRST").
+		Order("w.item_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetSlowMovers failed: %w", err)
+	}
+
+	return rows, int(totalCount), nil
+}
+
+// GetCashierPerformance implements ReportRepository.
+func (repository *ReportRepositoryImpl) GetCashierPerformance(tenantId, storeId int, dateFilter *query.DateFilter) ([]*CashierPerformanceRow, error) {
+	// Model() applies deleted_at IS NULL automatically
+	sales := applySalesReportFilters(repository.Client.Model(&model.OrderItem{}), "", tenantId, storeId, dateFilter).
+		Select(`
+			user_id,
+			COUNT(id)            AS transaction_count,
+			SUM(total_quantity)  AS total_quantity,
+			SUM(subtotal)        AS total_subtotal,
+			SUM(total_amount)    AS total_revenue,
+			SUM(discount_amount) AS total_discount
+		`).
+		Group("user_id")
+
+	voids := repository.Client.Table("order_item").
+		Select("voided_by_user_id AS user_id, COUNT(id) AS void_count, SUM(total_amount) AS void_amount").
+		Where("tenant_id = ? AND deleted_at IS NOT NULL AND voided_by_user_id IS NOT NULL", tenantId).
+		Where("status = ?", model.OrderStatusCompleted)
+	if storeId > 0 {
+		voids = voids.Where("store_id = ?", storeId)
+	}
+	voids = applyDateRange(voids, "deleted_at", dateFilter).Group("voided_by_user_id")
+
+	refunds := repository.Client.Model(&model.GiftCardMovement{}).
+		Select("user_id, COUNT(id) AS refund_count, SUM(amount) AS refund_amount").
+		Where("tenant_id = ? AND type = ?", tenantId, model.GiftCardMovementIssue)
+	if storeId > 0 {
+		refunds = refunds.Where("store_id = ?", storeId)
+	}
+	refunds = applyDateRange(refunds, "created_at", dateFilter).Group("user_id")
+
+	// Member and owner of the tenant, and removed staff that still has activity at the period
+	users := repository.Client.Raw(`
+		SELECT user_id FROM user_mtm_tenant WHERE tenant_id = ?
+		UNION SELECT owner_user_id FROM tenant WHERE id = ?
+		UNION SELECT user_id FROM (?) s
+		UNION SELECT user_id FROM (?) v
+		UNION SELECT user_id FROM (?) r
+	`, tenantId, tenantId, sales, voids, refunds)
+
+	var rows = make([]*CashierPerformanceRow, 0)
+	err := repository.Client.Table("(?) tu", users).
+		Select(`
+			u.id AS user_id,
+			u.name AS user_name,
+			COALESCE(s.transaction_count, 0) AS transaction_count,
+			COALESCE(s.total_quantity, 0)    AS total_quantity,
+			COALESCE(s.total_subtotal, 0)    AS total_subtotal,
+			COALESCE(s.total_revenue, 0)     AS total_revenue,
+			COALESCE(s.total_discount, 0)    AS total_discount,
+			COALESCE(v.void_count, 0)        AS void_count,
+			COALESCE(v.void_amount, 0)       AS void_amount,
+			COALESCE(r.refund_count, 0)      AS refund_count,
+			COALESCE(r.refund_amount, 0)     AS refund_amount
+		`).
+		Joins(`INNER JOIN "user" u ON u.id = tu.user_id`).
+		Joins("LEFT JOIN (?) s ON s.user_id = u.id", sales).
+		Joins("LEFT JOIN (?) v ON v.user_id = u.id", voids).
+		Joins("LEFT JOIN (?) r ON r.user_id = u.id", refunds).
+		Order("total_revenue DESC").
+		Order("u.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetCashierPerformance failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetInventoryValuation implements ReportRepository.
+func (repository *ReportRepositoryImpl) GetInventoryValuation(tenantId, storeId int) ([]*InventoryValuationRow, error) {
+	// Stock line of every location: store_id 0 is the warehouse, it has no selling price
+	warehouseLines := repository.Client.Table("warehouse w").
+		Select("0 AS store_id, w.item_id, w.stocks, w.base_price AS unit_cost, 0 AS unit_price").
+		Where("w.tenant_id = ? AND w.stock_type <> ? AND w.stocks > 0", tenantId, model.StockTypeUnlimited)
+
+	storeLines := repository.Client.Table("store_stock ss").
+		Select("ss.store_id, w.item_id, ss.stocks, w.base_price AS unit_cost, ss.price AS unit_price").
+		Joins("INNER JOIN warehouse w ON w.item_id = ss.item_id").
+		Where("ss.tenant_id = ? AND w.stock_type <> ? AND ss.stocks > 0", tenantId, model.StockTypeUnlimited)
+
+	var lines *gorm.DB
+	if storeId > 0 {
+		lines = storeLines.Where("ss.store_id = ?", storeId)
+	} else {
+		lines = repository.Client.Raw("(?) UNION ALL (?)", warehouseLines, storeLines)
+	}
+
+	// First category of every item, so an item is never counted twice
+	firstCategory := repository.Client.Table("category_mtm_warehouse cmw").
+		Select("cmw.item_id, MIN(c.id) AS category_id").
+		Joins("INNER JOIN category c ON c.id = cmw.category_id").
+		Where("c.tenant_id = ?", tenantId).
+		Group("cmw.item_id")
+
+	db := repository.Client.Table("(?) AS l", lines).
+		Joins("LEFT JOIN store s ON s.id = l.store_id").
+		Joins("LEFT JOIN (?) fc ON fc.item_id = l.item_id", firstCategory).
+		Joins("LEFT JOIN category c ON c.id = fc.category_id").
+		Select(`
+			l.store_id,
+			COALESCE(MAX(s.name), 'Warehouse') AS location_name,
+			COALESCE(fc.category_id, 0) AS category_id,
+			COALESCE(MAX(c.category_name), 'Uncategorized') AS category_name,
+			COUNT(l.item_id) AS item_count,
+			SUM(l.stocks) AS total_stocks,
+			SUM(l.stocks::bigint * l.unit_cost) AS cost_value,
+			SUM(l.stocks::bigint * l.unit_price) AS retail_value
+		`).
+		Group("l.store_id").
+		Group("COALESCE(fc.category_id, 0)").
+		Order("l.store_id ASC").
+		Order("cost_value DESC").
+		Order("category_id ASC")
+
+	var rows = make([]*InventoryValuationRow, 0)
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("GetInventoryValuation failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetTenantTimezone implements ReportRepository.
+func (repository *ReportRepositoryImpl) GetTenantTimezone(tenantId int) (string, error) {
+	return NewOrderItemRepositoryImpl(repository.Client).GetTenantTimezone(tenantId)
+}
+
+// GetDailyItemSales implements ReportRepository.
+func (repository *ReportRepositoryImpl) GetDailyItemSales(tenantId, storeId int, timezone string, since, until time.Time) ([]*DailyItemSalesRow, error) {
+	db := repository.Client.Table("purchased_item_list pil").
+		Select(`
+			oi.store_id,
+			pil.item_id,
+			date_trunc('day', oi.completed_at AT TIME ZONE ?) AS sale_date,
+			SUM(pil.quantity) AS quantity
+		`, timezone).
+		Joins(completedSalesJoin).
+		Where("oi.tenant_id = ? AND oi.completed_at >= ? AND oi.completed_at < ?", tenantId, since, until)
+	if storeId > 0 {
+		db = db.Where("oi.store_id = ?", storeId)
+	}
+
+	var rows = make([]*DailyItemSalesRow, 0)
+	err := db.
+		Group("oi.store_id").
+		Group("pil.item_id").
+		Group("sale_date").
+		Order("sale_date ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetDailyItemSales failed: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetReorderStocks implements ReportRepository.
+func (repository *ReportRepositoryImpl) GetReorderStocks(tenantId, storeId int) ([]*ReorderStockRow, error) {
+	warehouseStocks := repository.Client.Table("warehouse w").
+		Select("0 AS store_id, 'Warehouse' AS location_name, w.item_id, w.item_name, w.stocks").
+		Where("w.tenant_id = ? AND w.is_active = TRUE AND w.stock_type <> ?", tenantId, model.StockTypeUnlimited)
+
+	storeStocks := repository.Client.Table("store_stock ss").
+		Select("ss.store_id, s.name AS location_name, w.item_id, w.item_name, ss.stocks").
+		Joins("INNER JOIN warehouse w ON w.item_id = ss.item_id").
+		Joins("INNER JOIN store s ON s.id = ss.store_id").
+		Where("ss.tenant_id = ? AND w.is_active = TRUE AND w.stock_type <> ?", tenantId, model.StockTypeUnlimited)
+
+	var stocks *gorm.DB
+	if storeId > 0 {
+		stocks = storeStocks.Where("ss.store_id = ?", storeId)
+	} else {
+		stocks = repository.Client.Raw("(?) UNION ALL (?)", warehouseStocks, storeStocks)
+	}
+
+	var rows = make([]*ReorderStockRow, 0)
+	err := repository.Client.Table("(?) AS l", stocks).
+		Order("l.store_id ASC").
+		Order("l.item_id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetReorderStocks failed: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"cashier-api/helper/query"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReportRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewReportRepositoryMock(mock *mock.Mock) ReportRepository {
+	return &ReportRepositoryMock{Mock: mock}
+}
+
+// GetItemRanking implements ReportRepository.
+func (repository *ReportRepositoryMock) GetItemRanking(tenantId, storeId, categoryId int, metric ItemRankingMetric, dateFilter *query.DateFilter, limit int) ([]*ItemRankingRow, error) {
+	args := repository.Mock.Called(tenantId, storeId, categoryId, metric, dateFilter, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*ItemRankingRow), nil
+}
+
+// GetSlowMovers implements ReportRepository.
+func (repository *ReportRepositoryMock) GetSlowMovers(tenantId, storeId, categoryId int, since time.Time, limit, page int) ([]*SlowMoverRow, int, error) {
+	args := repository.Mock.Called(tenantId, storeId, categoryId, since, limit, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*SlowMoverRow), args.Int(1), nil
+}
+
+// GetCashierPerformance implements ReportRepository.
+func (repository *ReportRepositoryMock) GetCashierPerformance(tenantId, storeId int, dateFilter *query.DateFilter) ([]*CashierPerformanceRow, error) {
+	args := repository.Mock.Called(tenantId, storeId, dateFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*CashierPerformanceRow), nil
+}
+
+// GetInventoryValuation implements ReportRepository.
+func (repository *ReportRepositoryMock) GetInventoryValuation(tenantId, storeId int) ([]*InventoryValuationRow, error) {
+	args := repository.Mock.Called(tenantId, storeId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*InventoryValuationRow), nil
+}
+
+// GetTenantTimezone implements ReportRepository.
+func (repository *ReportRepositoryMock) GetTenantTimezone(tenantId int) (string, error) {
+	args := repository.Mock.Called(tenantId)
+	return args.String(0), args.Error(1)
+}
+
+// GetDailyItemSales implements ReportRepository.
+func (repository *ReportRepositoryMock) GetDailyItemSales(tenantId, storeId int, timezone string, since, until time.Time) ([]*DailyItemSalesRow, error) {
+	args := repository.Mock.Called(tenantId, storeId, timezone, since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*DailyItemSalesRow), nil
+}
+
+// GetReorderStocks implements ReportRepository.
+func (repository *ReportRepositoryMock) GetReorderStocks(tenantId, storeId int) ([]*ReorderStockRow, error) {
+	args := repository.Mock.Called(tenantId, storeId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*ReorderStockRow), nil
+}
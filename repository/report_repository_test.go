@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestReportRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	// Item with stock at the warehouse and the store, but never sold
+	seedItem := func(t *testing.T, tx *gorm.DB) (tenantId, storeId, itemId int) {
+		tenantId, storeId = seedOrderItemTestDependencies(t, tx)
+
+		item := &model.Item{
+			ItemName:  "Report Test Item",
+			Stocks:    5,
+			StockType: model.StockTypeTracked,
+			BasePrice: 1000,
+			TenantId:  tenantId,
+			IsActive:  true,
+		}
+		require.NoError(t, tx.Create(item).Error)
+
+		require.NoError(t, tx.Create(&model.StoreStock{
+			Stocks:   3,
+			Price:    2000,
+			ItemId:   item.ItemId,
+			TenantId: tenantId,
+			StoreId:  storeId,
+		}).Error)
+
+		return tenantId, storeId, item.ItemId
+	}
+
+	t.Run("GetItemRanking", func(t *testing.T) {
+		t.Run("NoSales", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, storeId, _ := seedItem(t, tx)
+
+			rows, err := NewReportRepositoryImpl(tx).GetItemRanking(tenantId, storeId, 0, ItemRankingByProfit, nil, 10)
+			require.NoError(t, err)
+			assert.Len(t, rows, 0)
+		})
+
+		t.Run("InvalidMetric", func(t *testing.T) {
+			_, err := NewReportRepositoryImpl(gormClient).GetItemRanking(1, 0, 0, "margin", nil, 10)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("GetSlowMovers", func(t *testing.T) {
+		t.Run("NeverSold", func(t *testing.T) {
+			tx := gormClient.Begin()
+			defer tx.Rollback()
+
+			tenantId, storeId, itemId := seedItem(t, tx)
+			repo := NewReportRepositoryImpl(tx)
+
+			// Single store
+			rows, count, err := repo.GetSlowMovers(tenantId, storeId, 0, time.Now().AddDate(0, 0, -30), 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+			require.Len(t, rows, 1)
+			assert.Equal(t, itemId, rows[0].ItemId)
+			assert.Equal(t, 3, rows[0].Stocks)
+			assert.Nil(t, rows[0].LastSoldAt)
+
+			// Warehouse + every store
+			rows, _, err = repo.GetSlowMovers(tenantId, 0, 0, time.Now().AddDate(0, 0, -30), 10, 0)
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			assert.Equal(t, 8, rows[0].Stocks)
+		})
+	})
+
+	t.Run("GetCashierPerformance", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+
+		// Owner without activity is still listed
+		rows, err := NewReportRepositoryImpl(tx).GetCashierPerformance(tenantId, storeId, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, userId, rows[0].UserId)
+		assert.Zero(t, rows[0].TransactionCount)
+		assert.Zero(t, rows[0].VoidCount)
+
+		// Refund of an invoice is counted at the store of the invoice
+		orderItem, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 50_000,
+			TotalQuantity:  1,
+			TotalAmount:    50_000,
+			Subtotal:       50_000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+		})
+		require.NoError(t, err)
+		_, err = NewGiftCardRepositoryImpl(tx).IssueStoreCredit(&model.GiftCard{TenantId: tenantId, Code: "REFUND234567", InitialBalance: 20_000}, &orderItem.Id, userId, "Refund")
+		require.NoError(t, err)
+
+		rows, err = NewReportRepositoryImpl(tx).GetCashierPerformance(tenantId, storeId, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, 1, rows[0].RefundCount)
+		assert.Equal(t, 20_000, rows[0].RefundAmount)
+	})
+
+	t.Run("GetInventoryValuation", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId, itemId := seedItem(t, tx)
+
+		// 2 categories, counted once in the first one
+		drinks := &model.Category{CategoryName: "Drinks", TenantId: tenantId}
+		require.NoError(t, tx.Create(drinks).Error)
+		snacks := &model.Category{CategoryName: "Snacks", TenantId: tenantId}
+		require.NoError(t, tx.Create(snacks).Error)
+		for _, category := range []*model.Category{drinks, snacks} {
+			require.NoError(t, tx.Create(&model.CategoryMtmWarehouse{CategoryId: category.Id, ItemId: itemId}).Error)
+		}
+
+		// Unlimited stock is not valued
+		require.NoError(t, tx.Create(&model.Item{
+			ItemName:  "Service Fee",
+			Stocks:    100,
+			StockType: model.StockTypeUnlimited,
+			BasePrice: 500,
+			TenantId:  tenantId,
+			IsActive:  true,
+		}).Error)
+
+		repo := NewReportRepositoryImpl(tx)
+		rows, err := repo.GetInventoryValuation(tenantId, 0)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		warehouse, store := rows[0], rows[1]
+		assert.Equal(t, 0, warehouse.StoreId)
+		assert.Equal(t, "Warehouse", warehouse.LocationName)
+		assert.Equal(t, drinks.Id, warehouse.CategoryId)
+		assert.Equal(t, 5_000, warehouse.CostValue)
+		assert.Zero(t, warehouse.RetailValue)
+
+		assert.Equal(t, storeId, store.StoreId)
+		assert.Equal(t, 1, store.ItemCount)
+		assert.Equal(t, 3_000, store.CostValue)
+		assert.Equal(t, 6_000, store.RetailValue)
+
+		rows, err = repo.GetInventoryValuation(tenantId, storeId)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, storeId, rows[0].StoreId)
+	})
+
+	t.Run("Reorder", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId, itemId := seedItem(t, tx)
+		repo := NewReportRepositoryImpl(tx)
+
+		stocks, err := repo.GetReorderStocks(tenantId, 0)
+		require.NoError(t, err)
+		require.Len(t, stocks, 2)
+		assert.Equal(t, "Warehouse", stocks[0].LocationName)
+		assert.Equal(t, 5, stocks[0].Stocks)
+		assert.Equal(t, storeId, stocks[1].StoreId)
+		assert.Equal(t, 3, stocks[1].Stocks)
+
+		orderItem, err := NewOrderItemRepositoryImpl(tx).PlaceOrderItem(&model.OrderItem{
+			PurchasedPrice: 4_000,
+			TotalQuantity:  2,
+			TotalAmount:    4_000,
+			Subtotal:       4_000,
+			TenantId:       tenantId,
+			StoreId:        storeId,
+		})
+		require.NoError(t, err)
+		require.NoError(t, tx.Create(&model.PurchasedItem{
+			ItemId: itemId, OrderItemId: orderItem.Id, Quantity: 2, StorePriceSnapshot: 2000, BasePriceSnapshot: 1000, TotalAmount: 4000,
+		}).Error)
+
+		now := time.Now()
+		sales, err := repo.GetDailyItemSales(tenantId, storeId, "UTC", now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, sales, 1)
+		assert.Equal(t, itemId, sales[0].ItemId)
+		assert.Equal(t, 2, sales[0].Quantity)
+		assert.Equal(t, now.UTC().Day(), sales[0].SaleDate.Day())
+	})
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+)
+
+/*
+Report schedules of a tenant and the history of their runs
+*/
+type ReportScheduleRepository interface {
+	/*
+		Get the list of schedule
+		2nd params return is the count of all data
+	*/
+	Get(tenantId, limit, page int) ([]*model.ReportSchedule, int, error)
+
+	/*
+		Return 1 schedule
+	*/
+	FindById(scheduleId, tenantId int) (*model.ReportSchedule, error)
+
+	/*
+		Create new schedule, NextRunAt is computed at the tenant timezone
+	*/
+	Create(schedule *model.ReportSchedule) (*model.ReportSchedule, error)
+
+	/*
+		Edit every setting of the schedule, NextRunAt is computed again
+	*/
+	Edit(schedule *model.ReportSchedule) (*model.ReportSchedule, error)
+
+	/*
+		Delete the schedule and its run history
+	*/
+	Delete(scheduleId, tenantId int) error
+
+	/*
+		Run history of a schedule (newest first)
+		2nd params return is the count of all data
+	*/
+	GetRuns(scheduleId, tenantId, limit, page int) ([]*model.ReportScheduleRun, int, error)
+
+	/*
+		Create a RUNNING run for every active schedule due at now and move its NextRunAt,
+		then take the RETRYING runs due at now and the RUNNING runs whose lease is over.
+		Claimed runs are RUNNING until now + lease with Attempts increased, Schedule is loaded.
+		Rows are locked with SKIP LOCKED, so 2 instances never claim the same run
+	*/
+	ClaimDueRuns(now time.Time, lease time.Duration) ([]*model.ReportScheduleRun, error)
+
+	/*
+		Save the result of a claimed run (status, next attempt, error, file name, finished at)
+	*/
+	FinishRun(run *model.ReportScheduleRun) error
+}
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportScheduleRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewReportScheduleRepositoryImpl(client *gorm.DB) ReportScheduleRepository {
+	return &ReportScheduleRepositoryImpl{Client: client}
+}
+
+// checkReportScheduleStore make sure the store (when not all store) belong to the tenant
+func checkReportScheduleStore(tx *gorm.DB, schedule *model.ReportSchedule) error {
+	if schedule.StoreId == 0 {
+		return nil
+	}
+
+	var count int64
+	err := tx.Model(&model.Store{}).
+		Where("id = ? AND tenant_id = ?", schedule.StoreId, schedule.TenantId).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("Store %d not found for tenant %d", schedule.StoreId, schedule.TenantId)
+	}
+	return nil
+}
+
+// Get implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) Get(tenantId, limit, page int) ([]*model.ReportSchedule, int, error) {
+	offset := page * limit
+
+	var schedules = make([]*model.ReportSchedule, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.ReportSchedule{}).
+		Where("tenant_id = ?", tenantId)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&schedules).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return schedules, int(totalCount), nil
+}
+
+// FindById implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) FindById(scheduleId, tenantId int) (*model.ReportSchedule, error) {
+	var schedule model.ReportSchedule
+	err := repository.Client.
+		Where("id = ? AND tenant_id = ?", scheduleId, tenantId).
+		Take(&schedule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("No report schedule found with id %d", scheduleId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// Create implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) Create(schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		if err := checkReportScheduleStore(tx, schedule); err != nil {
+			return err
+		}
+
+		location, err := tenantLocation(tx, schedule.TenantId)
+		if err != nil {
+			return err
+		}
+		nextRunAt := schedule.NextRun(time.Now(), location)
+		schedule.NextRunAt = &nextRunAt
+		schedule.LastRunAt = nil
+
+		return tx.Create(schedule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// Edit implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) Edit(schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	var edited model.ReportSchedule
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		// Locked, so the job never run the schedule with half of the new settings
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ?", schedule.Id, schedule.TenantId).
+			Take(&edited).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("No report schedule found with id %d", schedule.Id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := checkReportScheduleStore(tx, schedule); err != nil {
+			return err
+		}
+
+		location, err := tenantLocation(tx, schedule.TenantId)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]any{
+			"store_id":     schedule.StoreId,
+			"name":         schedule.Name,
+			"resource":     schedule.Resource,
+			"format":       schedule.Format,
+			"frequency":    schedule.Frequency,
+			"time_of_day":  schedule.TimeOfDay,
+			"day_of_week":  schedule.DayOfWeek,
+			"day_of_month": schedule.DayOfMonth,
+			"channel":      schedule.Channel,
+			"recipients":   schedule.Recipients,
+			"webhook_url":  schedule.WebhookUrl,
+			"is_active":    schedule.IsActive,
+			"next_run_at":  schedule.NextRun(time.Now(), location),
+			"updated_at":   time.Now(),
+		}
+		// Secret is never returned, so the client could not send it back
+		if schedule.WebhookSecret != nil {
+			updates["webhook_secret"] = *schedule.WebhookSecret
+		}
+
+		err = tx.Model(&edited).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", schedule.Id).Take(&edited).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &edited, nil
+}
+
+// Delete implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) Delete(scheduleId, tenantId int) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND tenant_id = ?", scheduleId, tenantId).Delete(&model.ReportSchedule{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("No report schedule found with id %d", scheduleId)
+		}
+
+		return tx.Where("schedule_id = ?", scheduleId).Delete(&model.ReportScheduleRun{}).Error
+	})
+}
+
+// GetRuns implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) GetRuns(scheduleId, tenantId, limit, page int) ([]*model.ReportScheduleRun, int, error) {
+	offset := page * limit
+
+	var runs = make([]*model.ReportScheduleRun, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.ReportScheduleRun{}).
+		Where("schedule_id = ? AND tenant_id = ?", scheduleId, tenantId)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("scheduled_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return runs, int(totalCount), nil
+}
+
+// ClaimDueRuns implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) ClaimDueRuns(now time.Time, lease time.Duration) ([]*model.ReportScheduleRun, error) {
+	claimed := make([]*model.ReportScheduleRun, 0)
+	leaseEnd := now.Add(lease)
+
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		skipLocked := clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}
+
+		var schedules []*model.ReportSchedule
+		err := tx.Clauses(skipLocked).
+			Where("is_active = ? AND next_run_at <= ?", true, now).
+			Order("next_run_at ASC").
+			Find(&schedules).Error
+		if err != nil {
+			return err
+		}
+
+		for _, schedule := range schedules {
+			location, err := tenantLocation(tx, schedule.TenantId)
+			if err != nil {
+				return err
+			}
+
+			periodStart, periodEnd := schedule.Period(*schedule.NextRunAt, location)
+			run := &model.ReportScheduleRun{
+				ScheduleId:    schedule.Id,
+				TenantId:      schedule.TenantId,
+				Status:        model.ReportScheduleRunRunning,
+				Attempts:      1,
+				ScheduledAt:   *schedule.NextRunAt,
+				PeriodStart:   periodStart,
+				PeriodEnd:     periodEnd,
+				Timezone:      location.String(),
+				NextAttemptAt: &leaseEnd,
+			}
+			if err := tx.Create(run).Error; err != nil {
+				return err
+			}
+
+			// Missed runs are not sent one by one, the next run is after now
+			err = tx.Model(schedule).
+				Updates(map[string]any{
+					"next_run_at": schedule.NextRun(now, location),
+					"last_run_at": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			run.Schedule = schedule
+			claimed = append(claimed, run)
+		}
+
+		var retries []*model.ReportScheduleRun
+		err = tx.Clauses(skipLocked).
+			Where("status IN ? AND next_attempt_at <= ?",
+				[]model.ReportScheduleRunStatus{model.ReportScheduleRunRetrying, model.ReportScheduleRunRunning}, now).
+			Order("next_attempt_at ASC").
+			Find(&retries).Error
+		if err != nil {
+			return err
+		}
+
+		for _, run := range retries {
+			// RUNNING with an expired lease already used its last attempt
+			if run.Attempts >= model.ReportScheduleMaxAttempts {
+				err := tx.Model(run).
+					Updates(map[string]any{
+						"status":          model.ReportScheduleRunFailed,
+						"next_attempt_at": nil,
+						"last_error":      "Run did not finish before its lease expired",
+						"finished_at":     now,
+					}).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			var schedule model.ReportSchedule
+			if err := tx.Where("id = ?", run.ScheduleId).Take(&schedule).Error; err != nil {
+				return err
+			}
+
+			run.Status = model.ReportScheduleRunRunning
+			run.Attempts++
+			run.NextAttemptAt = &leaseEnd
+			err := tx.Model(run).
+				Updates(map[string]any{
+					"status":          run.Status,
+					"attempts":        run.Attempts,
+					"next_attempt_at": run.NextAttemptAt,
+				}).Error
+			if err != nil {
+				return err
+			}
+
+			run.Schedule = &schedule
+			claimed = append(claimed, run)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// FinishRun implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryImpl) FinishRun(run *model.ReportScheduleRun) error {
+	return repository.Client.Model(&model.ReportScheduleRun{}).
+		Where("id = ?", run.Id).
+		Updates(map[string]any{
+			"status":          run.Status,
+			"next_attempt_at": run.NextAttemptAt,
+			"last_error":      run.LastError,
+			"file_name":       run.FileName,
+			"finished_at":     run.FinishedAt,
+		}).Error
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReportScheduleRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewReportScheduleRepositoryMock(mock *mock.Mock) ReportScheduleRepository {
+	return &ReportScheduleRepositoryMock{Mock: mock}
+}
+
+// Get implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) Get(tenantId, limit, page int) ([]*model.ReportSchedule, int, error) {
+	args := repository.Mock.Called(tenantId, limit, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.ReportSchedule), args.Int(1), nil
+}
+
+// FindById implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) FindById(scheduleId, tenantId int) (*model.ReportSchedule, error) {
+	args := repository.Mock.Called(scheduleId, tenantId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ReportSchedule), nil
+}
+
+// Create implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) Create(schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	args := repository.Mock.Called(schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ReportSchedule), nil
+}
+
+// Edit implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) Edit(schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	args := repository.Mock.Called(schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.ReportSchedule), nil
+}
+
+// Delete implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) Delete(scheduleId, tenantId int) error {
+	args := repository.Mock.Called(scheduleId, tenantId)
+	return args.Error(0)
+}
+
+// GetRuns implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) GetRuns(scheduleId, tenantId, limit, page int) ([]*model.ReportScheduleRun, int, error) {
+	args := repository.Mock.Called(scheduleId, tenantId, limit, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.ReportScheduleRun), args.Int(1), nil
+}
+
+// ClaimDueRuns implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) ClaimDueRuns(now time.Time, lease time.Duration) ([]*model.ReportScheduleRun, error) {
+	args := repository.Mock.Called(now, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*model.ReportScheduleRun), nil
+}
+
+// FinishRun implements ReportScheduleRepository.
+func (repository *ReportScheduleRepositoryMock) FinishRun(run *model.ReportScheduleRun) error {
+	args := repository.Mock.Called(run)
+	return args.Error(0)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestReportScheduleRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	newSchedule := func(tenantId, userId int) *model.ReportSchedule {
+		return &model.ReportSchedule{
+			TenantId:        tenantId,
+			Name:            "Daily sales",
+			Resource:        "sales_report",
+			Format:          "csv",
+			Frequency:       model.ReportScheduleDaily,
+			TimeOfDay:       "07:00",
+			Channel:         model.ReportScheduleEmail,
+			Recipients:      "owner@example.com",
+			IsActive:        true,
+			CreatedByUserId: userId,
+		}
+	}
+
+	// Other tenants of the test database could be due too
+	findRun := func(runs []*model.ReportScheduleRun, scheduleId int) *model.ReportScheduleRun {
+		for _, run := range runs {
+			if run.ScheduleId == scheduleId {
+				return run
+			}
+		}
+		return nil
+	}
+
+	t.Run("CreateAndEdit", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		repo := NewReportScheduleRepositoryImpl(tx)
+
+		schedule, err := repo.Create(newSchedule(tenantId, tenantOwnerId(t, tx, tenantId)))
+		require.NoError(t, err)
+		require.NotNil(t, schedule.NextRunAt)
+		assert.True(t, schedule.NextRunAt.After(time.Now()))
+
+		schedule.StoreId = storeId
+		schedule.Frequency = model.ReportScheduleWeekly
+		schedule.DayOfWeek = 1
+		edited, err := repo.Edit(schedule)
+		require.NoError(t, err)
+		assert.Equal(t, storeId, edited.StoreId)
+		assert.Equal(t, time.Monday, edited.NextRunAt.Weekday())
+
+		// Store of another tenant
+		schedule.StoreId = -1
+		_, err = repo.Edit(schedule)
+		assert.Error(t, err)
+
+		schedules, count, err := repo.Get(tenantId, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Len(t, schedules, 1)
+
+		require.NoError(t, repo.Delete(schedule.Id, tenantId))
+		_, err = repo.FindById(schedule.Id, tenantId)
+		assert.Error(t, err)
+	})
+
+	t.Run("ClaimDueRuns", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		repo := NewReportScheduleRepositoryImpl(tx)
+
+		schedule, err := repo.Create(newSchedule(tenantId, tenantOwnerId(t, tx, tenantId)))
+		require.NoError(t, err)
+
+		now := schedule.NextRunAt.Add(time.Minute)
+		runs, err := repo.ClaimDueRuns(now, time.Minute*10)
+		require.NoError(t, err)
+		run := findRun(runs, schedule.Id)
+		require.NotNil(t, run)
+		assert.Equal(t, model.ReportScheduleRunRunning, run.Status)
+		assert.Equal(t, 1, run.Attempts)
+		assert.Equal(t, schedule.Id, run.Schedule.Id)
+		assert.Equal(t, 24*time.Hour, run.PeriodEnd.Sub(run.PeriodStart))
+
+		moved, err := repo.FindById(schedule.Id, tenantId)
+		require.NoError(t, err)
+		assert.True(t, moved.NextRunAt.After(now))
+
+		// Nothing due until the run fail
+		runs, err = repo.ClaimDueRuns(now, time.Minute*10)
+		require.NoError(t, err)
+		assert.Nil(t, findRun(runs, schedule.Id))
+
+		retryAt := now.Add(time.Minute * 5)
+		run.Status = model.ReportScheduleRunRetrying
+		run.NextAttemptAt = &retryAt
+		run.LastError = "connection refused"
+		require.NoError(t, repo.FinishRun(run))
+
+		runs, err = repo.ClaimDueRuns(retryAt, time.Minute*10)
+		require.NoError(t, err)
+		retried := findRun(runs, schedule.Id)
+		require.NotNil(t, retried)
+		assert.Equal(t, run.Id, retried.Id)
+		assert.Equal(t, 2, retried.Attempts)
+
+		history, count, err := repo.GetRuns(schedule.Id, tenantId, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, "connection refused", history[0].LastError)
+	})
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("Session not found")
+	ErrRefreshTokenInvalid = errors.New("Refresh token is invalid or expired, please sign in again")
+	ErrRefreshTokenReused  = errors.New("Refresh token was already used, every device of this session is signed out")
+)
+
+/*
+Sign in sessions and their refresh tokens, only the token hash is stored.
+
+	Every method that write more than 1 row is wrapped with transaction
+*/
+type SessionRepository interface {
+	/*
+		Create the session with its first refresh token, both expire at session.ExpiresAt.
+		"" create the session only (PIN sign in on a terminal can't be refreshed)
+	*/
+	Create(session *model.UserSession, refreshTokenHash string) (*model.UserSession, error)
+
+	/*
+		Rotate the refresh token: mark it rotated, issue the new one and slide the session to expiresAt.
+		ErrRefreshTokenInvalid when unknown, expired or the session is over.
+		ErrRefreshTokenReused when it was already rotated, the session is revoked (committed)
+	*/
+	Rotate(refreshTokenHash, newRefreshTokenHash string, now, expiresAt time.Time) (*model.UserSession, error)
+
+	/*
+		Return the session of the refresh token, even revoked or expired
+	*/
+	FindByRefreshToken(refreshTokenHash string) (*model.UserSession, error)
+
+	/*
+		Revoke the session, already revoked is not an error
+	*/
+	Revoke(sessionId int, reason model.SessionRevokeReason) error
+
+	/*
+		Active sessions of the user, last seen first
+	*/
+	FindActiveByUser(userId int, now time.Time) ([]model.UserSession, error)
+
+	/*
+		Revoke 1 active session of the user, ErrSessionNotFound when it is not his or already over
+	*/
+	RevokeOfUser(userId int, sessionId int, reason model.SessionRevokeReason) error
+
+	/*
+		Revoke every active session of the user except exceptSessionId (0 revoke all), return the revoked count
+	*/
+	RevokeAllOfUser(userId int, exceptSessionId int, reason model.SessionRevokeReason) (int, error)
+
+	/*
+		Delete the refresh tokens of every session expired before the given time.
+		Rotated tokens are kept until then, they are needed to detect a reuse
+	*/
+	DeleteExpiredTokens(before time.Time) (int, error)
+}
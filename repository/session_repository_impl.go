@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewSessionRepositoryImpl(client *gorm.DB) SessionRepository {
+	return &SessionRepositoryImpl{Client: client}
+}
+
+// Create implements SessionRepository.
+func (repository *SessionRepositoryImpl) Create(session *model.UserSession, refreshTokenHash string) (*model.UserSession, error) {
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		if refreshTokenHash == "" {
+			return nil
+		}
+
+		return tx.Create(&model.UserRefreshToken{
+			SessionId: session.Id,
+			TokenHash: refreshTokenHash,
+			ExpiresAt: session.ExpiresAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Create session failed: %w", err)
+	}
+
+	return session, nil
+}
+
+// Rotate implements SessionRepository.
+func (repository *SessionRepositoryImpl) Rotate(refreshTokenHash, newRefreshTokenHash string, now, expiresAt time.Time) (*model.UserSession, error) {
+	var session model.UserSession
+	reused := false
+
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		// Locked, 2 refresh with the same token can't both rotate it
+		var refreshToken model.UserRefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", refreshTokenHash).
+			Take(&refreshToken).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRefreshTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", refreshToken.SessionId).
+			Take(&session).Error
+		if err != nil {
+			return err
+		}
+		if !session.IsActive(now) || !refreshToken.ExpiresAt.After(now) {
+			return ErrRefreshTokenInvalid
+		}
+
+		// Committed, so the thief and the owner are both signed out
+		if refreshToken.RotatedAt != nil {
+			reused = true
+			return revokeSession(tx, &session, model.SessionRevokeReuseDetected, now)
+		}
+
+		err = tx.Model(&refreshToken).Update("rotated_at", now).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&model.UserRefreshToken{
+			SessionId: session.Id,
+			TokenHash: newRefreshTokenHash,
+			ExpiresAt: expiresAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		session.ExpiresAt = expiresAt
+		session.LastSeenAt = &now
+		return tx.Model(&session).
+			Updates(map[string]interface{}{"expires_at": expiresAt, "last_seen_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+
+	return &session, nil
+}
+
+func revokeSession(tx *gorm.DB, session *model.UserSession, reason model.SessionRevokeReason, now time.Time) error {
+	session.RevokedAt = &now
+	session.RevokedReason = reason
+	return tx.Model(session).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason}).Error
+}
+
+// FindByRefreshToken implements SessionRepository.
+func (repository *SessionRepositoryImpl) FindByRefreshToken(refreshTokenHash string) (*model.UserSession, error) {
+	var session model.UserSession
+	err := repository.Client.
+		Where("id = (?)", repository.Client.Model(&model.UserRefreshToken{}).
+			Select("session_id").
+			Where("token_hash = ?", refreshTokenHash)).
+		Take(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Revoke implements SessionRepository.
+func (repository *SessionRepositoryImpl) Revoke(sessionId int, reason model.SessionRevokeReason) error {
+	err := repository.Client.Model(&model.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionId).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+	if err != nil {
+		return fmt.Errorf("Revoke session failed: %w", err)
+	}
+
+	return nil
+}
+
+// FindActiveByUser implements SessionRepository.
+func (repository *SessionRepositoryImpl) FindActiveByUser(userId int, now time.Time) ([]model.UserSession, error) {
+	var sessions []model.UserSession
+	err := repository.Client.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, now).
+		Order("COALESCE(last_seen_at, created_at) DESC, id DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindActiveByUser failed: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeOfUser implements SessionRepository.
+func (repository *SessionRepositoryImpl) RevokeOfUser(userId int, sessionId int, reason model.SessionRevokeReason) error {
+	result := repository.Client.Model(&model.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionId, userId, time.Now()).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason})
+	if result.Error != nil {
+		return fmt.Errorf("Revoke session failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllOfUser implements SessionRepository.
+func (repository *SessionRepositoryImpl) RevokeAllOfUser(userId int, exceptSessionId int, reason model.SessionRevokeReason) (int, error) {
+	result := repository.Client.Model(&model.UserSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL AND expires_at > ?", userId, exceptSessionId, time.Now()).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason})
+	if result.Error != nil {
+		return 0, fmt.Errorf("Revoke sessions failed: %w", result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// DeleteExpiredTokens implements SessionRepository.
+func (repository *SessionRepositoryImpl) DeleteExpiredTokens(before time.Time) (int, error) {
+	result := repository.Client.
+		Where("session_id IN (?)", repository.Client.Model(&model.UserSession{}).
+			Select("id").
+			Where("expires_at < ?", before)).
+		Delete(&model.UserRefreshToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("DeleteExpiredTokens failed: %w", result.Error)
+	}
+
+	return int(result.RowsAffected), nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SessionRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewSessionRepositoryMock(mock *mock.Mock) SessionRepository {
+	return &SessionRepositoryMock{Mock: mock}
+}
+
+// Create implements SessionRepository.
+func (repository *SessionRepositoryMock) Create(session *model.UserSession, refreshTokenHash string) (*model.UserSession, error) {
+	args := repository.Mock.Called(session, refreshTokenHash)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UserSession), nil
+}
+
+// Rotate implements SessionRepository.
+func (repository *SessionRepositoryMock) Rotate(refreshTokenHash, newRefreshTokenHash string, now, expiresAt time.Time) (*model.UserSession, error) {
+	args := repository.Mock.Called(refreshTokenHash, newRefreshTokenHash, now, expiresAt)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UserSession), nil
+}
+
+// FindByRefreshToken implements SessionRepository.
+func (repository *SessionRepositoryMock) FindByRefreshToken(refreshTokenHash string) (*model.UserSession, error) {
+	args := repository.Mock.Called(refreshTokenHash)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UserSession), nil
+}
+
+// Revoke implements SessionRepository.
+func (repository *SessionRepositoryMock) Revoke(sessionId int, reason model.SessionRevokeReason) error {
+	args := repository.Mock.Called(sessionId, reason)
+	return args.Error(0)
+}
+
+// FindActiveByUser implements SessionRepository.
+func (repository *SessionRepositoryMock) FindActiveByUser(userId int, now time.Time) ([]model.UserSession, error) {
+	args := repository.Mock.Called(userId, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.UserSession), nil
+}
+
+// RevokeOfUser implements SessionRepository.
+func (repository *SessionRepositoryMock) RevokeOfUser(userId int, sessionId int, reason model.SessionRevokeReason) error {
+	args := repository.Mock.Called(userId, sessionId, reason)
+	return args.Error(0)
+}
+
+// RevokeAllOfUser implements SessionRepository.
+func (repository *SessionRepositoryMock) RevokeAllOfUser(userId int, exceptSessionId int, reason model.SessionRevokeReason) (int, error) {
+	args := repository.Mock.Called(userId, exceptSessionId, reason)
+	return args.Int(0), args.Error(1)
+}
+
+// DeleteExpiredTokens implements SessionRepository.
+func (repository *SessionRepositoryMock) DeleteExpiredTokens(before time.Time) (int, error) {
+	args := repository.Mock.Called(before)
+	return args.Int(0), args.Error(1)
+}
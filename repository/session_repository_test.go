@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSessionRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	seedSession := func(t *testing.T, tx *gorm.DB, userId int, refreshTokenHash string, expiresAt time.Time) *model.UserSession {
+		t.Helper()
+
+		session, err := NewSessionRepositoryImpl(tx).Create(&model.UserSession{
+			UserId:    userId,
+			ExpiresAt: expiresAt,
+		}, refreshTokenHash)
+		require.NoError(t, err)
+		require.NotZero(t, session.Id)
+		return session
+	}
+
+	t.Run("Rotate", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		now := time.Now()
+		session := seedSession(t, tx, userId, "hash-1", now.Add(time.Hour))
+		repo := NewSessionRepositoryImpl(tx)
+
+		rotated, err := repo.Rotate("hash-1", "hash-2", now, now.Add(time.Hour*2))
+		require.NoError(t, err)
+		assert.Equal(t, session.Id, rotated.Id)
+		assert.WithinDuration(t, now.Add(time.Hour*2), rotated.ExpiresAt, time.Second)
+
+		rotated, err = repo.Rotate("hash-2", "hash-3", now, now.Add(time.Hour*2))
+		require.NoError(t, err)
+		assert.Equal(t, session.Id, rotated.Id)
+
+		found, err := repo.FindByRefreshToken("hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, session.Id, found.Id)
+
+		// Unknown token
+		_, err = repo.Rotate("unknown", "hash-4", now, now.Add(time.Hour*2))
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+	})
+
+	t.Run("ReuseRevokeSession", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		now := time.Now()
+		session := seedSession(t, tx, userId, "reuse-1", now.Add(time.Hour))
+		repo := NewSessionRepositoryImpl(tx)
+
+		_, err := repo.Rotate("reuse-1", "reuse-2", now, now.Add(time.Hour))
+		require.NoError(t, err)
+
+		_, err = repo.Rotate("reuse-1", "reuse-3", now, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+		var revoked model.UserSession
+		require.NoError(t, tx.Take(&revoked, "id = ?", session.Id).Error)
+		assert.NotNil(t, revoked.RevokedAt)
+		assert.Equal(t, model.SessionRevokeReuseDetected, revoked.RevokedReason)
+
+		// The latest token die with the session
+		_, err = repo.Rotate("reuse-2", "reuse-4", now, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+	})
+
+	t.Run("RevokeAndExpire", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		now := time.Now()
+		revoked := seedSession(t, tx, userId, "revoke-1", now.Add(time.Hour))
+		repo := NewSessionRepositoryImpl(tx)
+
+		require.NoError(t, repo.Revoke(revoked.Id, model.SessionRevokeSignOut))
+		require.NoError(t, repo.Revoke(revoked.Id, model.SessionRevokeSignOut))
+		_, err := repo.Rotate("revoke-1", "revoke-2", now, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+		seedSession(t, tx, userId, "expire-1", now.Add(-time.Hour))
+		_, err = repo.Rotate("expire-1", "expire-2", now, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+		deleted, err := repo.DeleteExpiredTokens(now)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, 1)
+
+		_, err = repo.FindByRefreshToken("expire-1")
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		_, err = repo.FindByRefreshToken("revoke-1")
+		assert.NoError(t, err, "Not expired yet")
+	})
+
+	t.Run("ListAndRevokeOfUser", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		now := time.Now()
+		tablet := seedSession(t, tx, userId, "list-1", now.Add(time.Hour))
+		phone := seedSession(t, tx, userId, "list-2", now.Add(time.Hour))
+		laptop := seedSession(t, tx, userId, "list-3", now.Add(time.Hour))
+		seedSession(t, tx, userId, "list-4", now.Add(-time.Hour))
+		repo := NewSessionRepositoryImpl(tx)
+
+		sessions, err := repo.FindActiveByUser(userId, now)
+		require.NoError(t, err)
+		assert.Len(t, sessions, 3)
+
+		// Other user can't revoke it
+		assert.ErrorIs(t, repo.RevokeOfUser(userId+1, tablet.Id, model.SessionRevokeByUser), ErrSessionNotFound)
+		require.NoError(t, repo.RevokeOfUser(userId, tablet.Id, model.SessionRevokeByUser))
+		assert.ErrorIs(t, repo.RevokeOfUser(userId, tablet.Id, model.SessionRevokeByUser), ErrSessionNotFound)
+
+		revoked, err := repo.RevokeAllOfUser(userId, laptop.Id, model.SessionRevokeByUser)
+		require.NoError(t, err)
+		assert.Equal(t, 1, revoked)
+
+		sessions, err = repo.FindActiveByUser(userId, now)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, laptop.Id, sessions[0].Id)
+		assert.NotEqual(t, phone.Id, sessions[0].Id)
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"time"
+)
+
+var ErrUnlockAccessDenied = errors.New("Only the tenant owner can unlock the sign in of a member")
+
+/*
+Audit of the email and password sign in, and the failed sign in counters.
+
+	Attempts are append only
+*/
+type SignInAttemptRepository interface {
+	/*
+		Append the attempt
+	*/
+	Record(attempt *model.SignInAttempt) error
+
+	/*
+		Attempts of the user, latest first. page start at 0
+	*/
+	GetByUser(userId int, limit, page int) ([]*model.SignInAttempt, int, error)
+
+	/*
+		The counter of the email or the IP blocked for the longest at now, nil when none
+	*/
+	FindBlocking(email string, ipAddress string, now time.Time) (*model.SignInThrottle, error)
+
+	/*
+		Count 1 failure and set the block of the policy, return the counter
+	*/
+	Fail(scope model.SignInThrottleScope, key string, policy model.SignInThrottlePolicy, now time.Time) (*model.SignInThrottle, error)
+
+	/*
+		Remove the counter, missing is not an error
+	*/
+	Clear(scope model.SignInThrottleScope, key string) error
+
+	/*
+		Clear the account counter of a member of the tenant.
+		ErrUnlockAccessDenied when ownerUserId is not the owner, gorm.ErrRecordNotFound when not a member
+	*/
+	UnlockMember(tenantId int, ownerUserId int, userId int) error
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SignInAttemptRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewSignInAttemptRepositoryImpl(client *gorm.DB) SignInAttemptRepository {
+	return &SignInAttemptRepositoryImpl{Client: client}
+}
+
+// Record implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryImpl) Record(attempt *model.SignInAttempt) error {
+	err := repository.Client.Create(attempt).Error
+	if err != nil {
+		return fmt.Errorf("Record sign in attempt failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUser implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryImpl) GetByUser(userId int, limit, page int) ([]*model.SignInAttempt, int, error) {
+	offset := page * limit
+
+	var attempts = make([]*model.SignInAttempt, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.SignInAttempt{}).
+		Where("user_id = ?", userId)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return attempts, int(totalCount), nil
+}
+
+// FindBlocking implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryImpl) FindBlocking(email string, ipAddress string, now time.Time) (*model.SignInThrottle, error) {
+	var throttle model.SignInThrottle
+	err := repository.Client.
+		Where("(scope = ? AND key = ?) OR (scope = ? AND key = ?)",
+			model.SignInThrottleAccount, model.NormalizeEmail(email), model.SignInThrottleIp, ipAddress).
+		Where("blocked_until > ?", now).
+		Order("blocked_until DESC").
+		Take(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("FindBlocking failed: %w", err)
+	}
+
+	return &throttle, nil
+}
+
+// Fail implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryImpl) Fail(scope model.SignInThrottleScope, key string, policy model.SignInThrottlePolicy, now time.Time) (*model.SignInThrottle, error) {
+	throttle := model.SignInThrottle{Scope: scope, Key: key, FailedCount: 1, LastFailedAt: now}
+
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		// Upsert, failures at once on the same key are all counted
+		err := tx.Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "scope"}, {Name: "key"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"failed_count":   gorm.Expr("CASE WHEN sign_in_throttle.last_failed_at < ? THEN 1 ELSE sign_in_throttle.failed_count + 1 END", now.Add(-policy.ResetAfter)),
+					"last_failed_at": now,
+				}),
+			},
+			clause.Returning{},
+		).Create(&throttle).Error
+		if err != nil {
+			return err
+		}
+
+		throttle.BlockedUntil = policy.BlockedUntil(throttle.FailedCount, now)
+		return tx.Model(&throttle).UpdateColumn("blocked_until", throttle.BlockedUntil).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Fail sign in throttle failed: %w", err)
+	}
+
+	return &throttle, nil
+}
+
+// Clear implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryImpl) Clear(scope model.SignInThrottleScope, key string) error {
+	err := repository.Client.
+		Where("scope = ? AND key = ?", scope, key).
+		Delete(&model.SignInThrottle{}).Error
+	if err != nil {
+		return fmt.Errorf("Clear sign in throttle failed: %w", err)
+	}
+
+	return nil
+}
+
+// UnlockMember implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryImpl) UnlockMember(tenantId int, ownerUserId int, userId int) error {
+	var owned int64
+	err := repository.Client.Table(TenantTable).
+		Where("id = ? AND owner_user_id = ?", tenantId, ownerUserId).
+		Count(&owned).Error
+	if err != nil {
+		return err
+	}
+	if owned == 0 {
+		return ErrUnlockAccessDenied
+	}
+
+	var member model.User
+	err = repository.Client.Model(&model.User{}).
+		Select("email").
+		Where("id = ? AND id IN (?)", userId, repository.Client.Table(UserMtmTenantTable).
+			Select("user_id").
+			Where("tenant_id = ?", tenantId)).
+		Take(&member).Error
+	if err != nil {
+		return err
+	}
+
+	return repository.Clear(model.SignInThrottleAccount, model.NormalizeEmail(member.Email))
+}
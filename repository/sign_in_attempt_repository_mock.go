@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SignInAttemptRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewSignInAttemptRepositoryMock(mock *mock.Mock) SignInAttemptRepository {
+	return &SignInAttemptRepositoryMock{Mock: mock}
+}
+
+// Record implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryMock) Record(attempt *model.SignInAttempt) error {
+	args := repository.Mock.Called(attempt)
+	return args.Error(0)
+}
+
+// GetByUser implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryMock) GetByUser(userId int, limit, page int) ([]*model.SignInAttempt, int, error) {
+	args := repository.Mock.Called(userId, limit, page)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.SignInAttempt), args.Int(1), nil
+}
+
+// FindBlocking implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryMock) FindBlocking(email string, ipAddress string, now time.Time) (*model.SignInThrottle, error) {
+	args := repository.Mock.Called(email, ipAddress, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.SignInThrottle), nil
+}
+
+// Fail implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryMock) Fail(scope model.SignInThrottleScope, key string, policy model.SignInThrottlePolicy, now time.Time) (*model.SignInThrottle, error) {
+	args := repository.Mock.Called(scope, key, policy, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.SignInThrottle), nil
+}
+
+// Clear implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryMock) Clear(scope model.SignInThrottleScope, key string) error {
+	args := repository.Mock.Called(scope, key)
+	return args.Error(0)
+}
+
+// UnlockMember implements SignInAttemptRepository.
+func (repository *SignInAttemptRepositoryMock) UnlockMember(tenantId int, ownerUserId int, userId int) error {
+	args := repository.Mock.Called(tenantId, ownerUserId, userId)
+	return args.Error(0)
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSignInAttemptRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("RecordAndGetByUser", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewSignInAttemptRepositoryImpl(tx)
+
+		require.NoError(t, repo.Record(&model.SignInAttempt{UserId: &ownerId, Email: "orderitem_test@example.com", Result: model.SignInWrongCredential}))
+		require.NoError(t, repo.Record(&model.SignInAttempt{UserId: &ownerId, Email: "orderitem_test@example.com", Result: model.SignInSucceeded}))
+		require.NoError(t, repo.Record(&model.SignInAttempt{Email: "unknown@example.com", Result: model.SignInWrongCredential}))
+
+		attempts, count, err := repo.GetByUser(ownerId, 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		require.Len(t, attempts, 1)
+		assert.Equal(t, model.SignInSucceeded, attempts[0].Result)
+	})
+
+	t.Run("FailFindBlockingAndClear", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		repo := NewSignInAttemptRepositoryImpl(tx)
+		now := time.Now()
+		policy := model.SignInThrottlePolicy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, LockoutAfter: 10, LockoutDuration: time.Hour}
+
+		throttle, err := repo.Fail(model.SignInThrottleAccount, "throttle_test@example.com", policy, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, throttle.FailedCount)
+		assert.Nil(t, throttle.BlockedUntil)
+
+		blocking, err := repo.FindBlocking("throttle_test@example.com", "10.0.0.1", now)
+		require.NoError(t, err)
+		assert.Nil(t, blocking)
+
+		throttle, err = repo.Fail(model.SignInThrottleAccount, "throttle_test@example.com", policy, now)
+		require.NoError(t, err)
+		assert.Equal(t, 2, throttle.FailedCount)
+		require.NotNil(t, throttle.BlockedUntil)
+
+		blocking, err = repo.FindBlocking("throttle_test@example.com", "10.0.0.1", now)
+		require.NoError(t, err)
+		require.NotNil(t, blocking)
+		assert.Equal(t, model.SignInThrottleAccount, blocking.Scope)
+
+		require.NoError(t, repo.Clear(model.SignInThrottleAccount, "throttle_test@example.com"))
+		blocking, err = repo.FindBlocking("throttle_test@example.com", "10.0.0.1", now)
+		require.NoError(t, err)
+		assert.Nil(t, blocking)
+	})
+
+	t.Run("UnlockMember", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewSignInAttemptRepositoryImpl(tx)
+		now := time.Now()
+
+		member := &model.User{Name: "Locked Member", Email: "locked_member@example.com", Password: "password"}
+		require.NoError(t, tx.Create(member).Error)
+
+		assert.ErrorIs(t, repo.UnlockMember(tenantId, member.Id, member.Id), ErrUnlockAccessDenied)
+		assert.ErrorIs(t, repo.UnlockMember(tenantId, ownerId, member.Id), gorm.ErrRecordNotFound)
+
+		require.NoError(t, tx.Create(&model.UserMtmTenant{UserId: member.Id, TenantId: tenantId}).Error)
+		_, err := repo.Fail(model.SignInThrottleAccount, member.Email, model.SignInThrottlePolicy{LockoutAfter: 1, LockoutDuration: time.Hour}, now)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.UnlockMember(tenantId, ownerId, member.Id))
+		blocking, err := repo.FindBlocking(member.Email, "10.0.0.1", now)
+		require.NoError(t, err)
+		assert.Nil(t, blocking)
+	})
+}
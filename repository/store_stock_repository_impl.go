@@ -124,6 +124,7 @@ func (repository *StoreStockRepositoryImpl) GetV2(
 			COALESCE(category.id, 0)             AS category_id,
 			COALESCE(category.category_name, '') AS category_name
 		`).
+		Order("store_stock.id DESC"). // Same created_at still page the same way
 		Limit(limit).
 		Offset(offset).
 		Scan(&results).Error
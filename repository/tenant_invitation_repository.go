@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvitationAccessDenied  = errors.New("Only the owner or a manager of the tenant can manage the invitations, and only the owner can invite a manager")
+	ErrInvitationNotFound      = errors.New("Invitation not found or not pending anymore")
+	ErrInvitationInvalid       = errors.New("The invitation is invalid, expired or already used. Please ask for a new one")
+	ErrInvitationEmailMismatch = errors.New("The invitation was sent to another email, sign in with the invited email to accept it")
+	ErrInvitationAlreadyMember = errors.New("The user is already a member of the tenant")
+)
+
+/*
+Email invitations to join a tenant, only the token hash is stored.
+
+	Every method that write more than 1 row is wrapped with transaction
+*/
+type TenantInvitationRepository interface {
+	/*
+		Create the invitation, the pending one of the same tenant and email is revoked.
+		ErrInvitationAccessDenied when managerUserId is not the owner or a manager (owner only for a manager role),
+		ErrInvitationAlreadyMember when the email is already a member
+	*/
+	Create(invitation *model.TenantInvitation, managerUserId int) (*model.TenantInvitation, error)
+
+	/*
+		Pending invitations of the tenant (expired included), latest first. page start at 0
+	*/
+	GetPending(tenantId int, limit, page int) ([]*model.TenantInvitation, int, error)
+
+	/*
+		Replace the token of the pending invitation and push its expiry, to send it again.
+		ErrInvitationNotFound when not pending
+	*/
+	Renew(tenantId int, invitationId int, managerUserId int, tokenHash string, now time.Time) (*model.TenantInvitation, error)
+
+	/*
+		Revoke the pending invitation, its link stop working. ErrInvitationNotFound when not pending
+	*/
+	Revoke(tenantId int, invitationId int, managerUserId int) error
+
+	/*
+		Accept the invitation as the user, adding him to the tenant with the invited role.
+		ErrInvitationInvalid when the token can not be accepted at now,
+		ErrInvitationEmailMismatch when the user email is not the invited one
+	*/
+	Accept(tokenHash string, userId int, now time.Time) (*model.UserMtmTenant, error)
+}
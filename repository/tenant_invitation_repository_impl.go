@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TenantInvitationRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewTenantInvitationRepositoryImpl(client *gorm.DB) TenantInvitationRepository {
+	return &TenantInvitationRepositoryImpl{Client: client}
+}
+
+// The tenant when the user can invite with the role, ErrInvitationAccessDenied otherwise
+func invitingTenant(tx *gorm.DB, tenantId int, userId int, role model.TenantRole) (*model.Tenant, error) {
+	var tenant model.Tenant
+	err := tx.Where("id = ?", tenantId).Take(&tenant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationAccessDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tenant.OwnerUserId == userId {
+		return &tenant, nil
+	}
+	if role == model.TenantRoleManager {
+		return nil, ErrInvitationAccessDenied
+	}
+
+	allowed, err := canManageTenant(tx, tenantId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrInvitationAccessDenied
+	}
+
+	return &tenant, nil
+}
+
+// Create implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryImpl) Create(invitation *model.TenantInvitation, managerUserId int) (*model.TenantInvitation, error) {
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		tenant, err := invitingTenant(tx, invitation.TenantId, managerUserId, invitation.Role)
+		if err != nil {
+			return err
+		}
+
+		var members int64
+		err = tx.Model(&model.User{}).
+			Where("LOWER(email) = ?", invitation.Email).
+			Where("id = ? OR id IN (?)", tenant.OwnerUserId, tx.Table(UserMtmTenantTable).
+				Select("user_id").
+				Where("tenant_id = ?", tenant.Id)).
+			Count(&members).Error
+		if err != nil {
+			return err
+		}
+		if members > 0 {
+			return ErrInvitationAlreadyMember
+		}
+
+		// Only the latest invitation of an email can be accepted
+		err = tx.Model(&model.TenantInvitation{}).
+			Where("tenant_id = ? AND email = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitation.TenantId, invitation.Email).
+			UpdateColumn("revoked_at", invitation.SentAt).Error
+		if err != nil {
+			return err
+		}
+
+		invitation.InvitedByUserId = managerUserId
+		err = tx.Create(invitation).Error
+		if err != nil {
+			return err
+		}
+
+		invitation.TenantName = tenant.Name
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvitationAccessDenied) || errors.Is(err, ErrInvitationAlreadyMember) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Create tenant invitation failed: %w", err)
+	}
+
+	return invitation, nil
+}
+
+// GetPending implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryImpl) GetPending(tenantId int, limit, page int) ([]*model.TenantInvitation, int, error) {
+	offset := page * limit
+
+	var invitations = make([]*model.TenantInvitation, 0)
+	var totalCount int64
+
+	query := repository.Client.Model(&model.TenantInvitation{}).
+		Where("tenant_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", tenantId)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&invitations).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return invitations, int(totalCount), nil
+}
+
+// Renew implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryImpl) Renew(tenantId int, invitationId int, managerUserId int, tokenHash string, now time.Time) (*model.TenantInvitation, error) {
+	var invitation model.TenantInvitation
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitationId, tenantId).
+			Take(&invitation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvitationNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		tenant, err := invitingTenant(tx, tenantId, managerUserId, invitation.Role)
+		if err != nil {
+			return err
+		}
+
+		invitation.TokenHash = tokenHash
+		invitation.SentAt = now
+		invitation.ExpiresAt = now.Add(model.TenantInvitationLifetime)
+		err = tx.Model(&invitation).
+			Updates(map[string]interface{}{"token_hash": invitation.TokenHash, "sent_at": invitation.SentAt, "expires_at": invitation.ExpiresAt}).Error
+		if err != nil {
+			return err
+		}
+
+		invitation.TenantName = tenant.Name
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvitationAccessDenied) || errors.Is(err, ErrInvitationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Renew tenant invitation failed: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// Revoke implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryImpl) Revoke(tenantId int, invitationId int, managerUserId int) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		var invitation model.TenantInvitation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND tenant_id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitationId, tenantId).
+			Take(&invitation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvitationNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = invitingTenant(tx, tenantId, managerUserId, invitation.Role)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&invitation).UpdateColumn("revoked_at", time.Now()).Error
+	})
+}
+
+// Accept implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryImpl) Accept(tokenHash string, userId int, now time.Time) (*model.UserMtmTenant, error) {
+	var userMtmTenant *model.UserMtmTenant
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		var invitation model.TenantInvitation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", tokenHash, now).
+			Take(&invitation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvitationInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		var user model.User
+		err = tx.Select("id", "email").Where("id = ?", userId).Take(&user).Error
+		if err != nil {
+			return err
+		}
+		if model.NormalizeEmail(user.Email) != invitation.Email {
+			return ErrInvitationEmailMismatch
+		}
+
+		var members int64
+		err = tx.Table(TenantTable).
+			Where("id = ?", invitation.TenantId).
+			Where("owner_user_id = ? OR id IN (?)", userId, tx.Table(UserMtmTenantTable).
+				Select("tenant_id").
+				Where("user_id = ?", userId)).
+			Count(&members).Error
+		if err != nil {
+			return err
+		}
+		if members > 0 {
+			return ErrInvitationAlreadyMember
+		}
+
+		userMtmTenant = &model.UserMtmTenant{UserId: userId, TenantId: invitation.TenantId, Role: invitation.Role}
+		err = tx.Create(userMtmTenant).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&invitation).
+			Updates(map[string]interface{}{"accepted_at": now, "accepted_by_user_id": userId}).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvitationInvalid) || errors.Is(err, ErrInvitationEmailMismatch) || errors.Is(err, ErrInvitationAlreadyMember) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Accept tenant invitation failed: %w", err)
+	}
+
+	return userMtmTenant, nil
+}
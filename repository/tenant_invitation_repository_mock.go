@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TenantInvitationRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewTenantInvitationRepositoryMock(mock *mock.Mock) TenantInvitationRepository {
+	return &TenantInvitationRepositoryMock{Mock: mock}
+}
+
+// Create implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryMock) Create(invitation *model.TenantInvitation, managerUserId int) (*model.TenantInvitation, error) {
+	args := repository.Mock.Called(invitation, managerUserId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.TenantInvitation), nil
+}
+
+// GetPending implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryMock) GetPending(tenantId int, limit, page int) ([]*model.TenantInvitation, int, error) {
+	args := repository.Mock.Called(tenantId, limit, page)
+
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.TenantInvitation), args.Int(1), nil
+}
+
+// Renew implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryMock) Renew(tenantId int, invitationId int, managerUserId int, tokenHash string, now time.Time) (*model.TenantInvitation, error) {
+	args := repository.Mock.Called(tenantId, invitationId, managerUserId, tokenHash, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.TenantInvitation), nil
+}
+
+// Revoke implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryMock) Revoke(tenantId int, invitationId int, managerUserId int) error {
+	args := repository.Mock.Called(tenantId, invitationId, managerUserId)
+	return args.Error(0)
+}
+
+// Accept implements TenantInvitationRepository.
+func (repository *TenantInvitationRepositoryMock) Accept(tokenHash string, userId int, now time.Time) (*model.UserMtmTenant, error) {
+	args := repository.Mock.Called(tokenHash, userId, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UserMtmTenant), nil
+}
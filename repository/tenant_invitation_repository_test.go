@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTenantInvitationRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	newInvitation := func(tenantId int, email string, role model.TenantRole, tokenHash string, now time.Time) *model.TenantInvitation {
+		return &model.TenantInvitation{
+			TenantId:  tenantId,
+			Email:     email,
+			Role:      role,
+			TokenHash: tokenHash,
+			ExpiresAt: now.Add(model.TenantInvitationLifetime),
+			SentAt:    now,
+		}
+	}
+
+	t.Run("InviteAndAccept", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTenantInvitationRepositoryImpl(tx)
+		now := time.Now()
+
+		invitee := &model.User{Name: "Invitee", Email: "Invitee_test@example.com", Password: "password"}
+		require.NoError(t, tx.Create(invitee).Error)
+		other := &model.User{Name: "Other", Email: "other_invitee_test@example.com", Password: "password"}
+		require.NoError(t, tx.Create(other).Error)
+
+		_, err := repo.Create(newInvitation(tenantId, "invitee_test@example.com", model.TenantRoleCashier, "invite-1", now), invitee.Id)
+		assert.ErrorIs(t, err, ErrInvitationAccessDenied)
+
+		_, err = repo.Create(newInvitation(tenantId, "orderitem_test@example.com", model.TenantRoleCashier, "invite-owner", now), ownerId)
+		assert.ErrorIs(t, err, ErrInvitationAlreadyMember)
+
+		first, err := repo.Create(newInvitation(tenantId, "invitee_test@example.com", model.TenantRoleCashier, "invite-1", now), ownerId)
+		require.NoError(t, err)
+		assert.NotEmpty(t, first.TenantName)
+
+		// Inviting again revoke the first one
+		second, err := repo.Create(newInvitation(tenantId, "invitee_test@example.com", model.TenantRoleManager, "invite-2", now), ownerId)
+		require.NoError(t, err)
+		invitations, count, err := repo.GetPending(tenantId, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, second.Id, invitations[0].Id)
+
+		_, err = repo.Accept("invite-1", invitee.Id, now)
+		assert.ErrorIs(t, err, ErrInvitationInvalid)
+		_, err = repo.Accept("invite-2", other.Id, now)
+		assert.ErrorIs(t, err, ErrInvitationEmailMismatch)
+		_, err = repo.Accept("invite-2", invitee.Id, second.ExpiresAt)
+		assert.ErrorIs(t, err, ErrInvitationInvalid)
+
+		userMtmTenant, err := repo.Accept("invite-2", invitee.Id, now)
+		require.NoError(t, err)
+		assert.Equal(t, model.TenantRoleManager, userMtmTenant.Role)
+
+		_, err = repo.Accept("invite-2", invitee.Id, now)
+		assert.ErrorIs(t, err, ErrInvitationInvalid)
+		_, count, err = repo.GetPending(tenantId, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		// A manager invite cashiers, not managers
+		_, err = repo.Create(newInvitation(tenantId, "other_invitee_test@example.com", model.TenantRoleManager, "invite-3", now), invitee.Id)
+		assert.ErrorIs(t, err, ErrInvitationAccessDenied)
+		_, err = repo.Create(newInvitation(tenantId, "other_invitee_test@example.com", model.TenantRoleCashier, "invite-3", now), invitee.Id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("RenewAndRevoke", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTenantInvitationRepositoryImpl(tx)
+		now := time.Now()
+
+		invitation, err := repo.Create(newInvitation(tenantId, "renew_test@example.com", model.TenantRoleCashier, "invite-old", now.Add(-model.TenantInvitationLifetime*2)), ownerId)
+		require.NoError(t, err)
+
+		renewed, err := repo.Renew(tenantId, invitation.Id, ownerId, "invite-new", now)
+		require.NoError(t, err)
+		assert.True(t, renewed.CanBeAccepted(now))
+
+		var stored model.TenantInvitation
+		require.NoError(t, tx.Take(&stored, "id = ?", invitation.Id).Error)
+		assert.Equal(t, "invite-new", stored.TokenHash)
+
+		require.NoError(t, repo.Revoke(tenantId, invitation.Id, ownerId))
+		assert.ErrorIs(t, repo.Revoke(tenantId, invitation.Id, ownerId), ErrInvitationNotFound)
+		_, err = repo.Renew(tenantId, invitation.Id, ownerId, "invite-newer", now)
+		assert.ErrorIs(t, err, ErrInvitationNotFound)
+	})
+}
@@ -1,6 +1,18 @@
 package repository
 
-import "cashier-api/model"
+import (
+	"cashier-api/model"
+	"errors"
+	"time"
+)
+
+var (
+	ErrTenantOwnerOnly           = errors.New("Only the tenant owner can do this")
+	ErrTenantNameMismatch        = errors.New("The confirmation does not match the tenant name")
+	ErrTenantNotArchived         = errors.New("Tenant not found or not archived")
+	ErrOwnershipTransferNotFound = errors.New("No pending ownership transfer for this user")
+	ErrOwnershipTransferTarget   = errors.New("The new owner must be another member of the tenant")
+)
 
 type TenantRepository interface {
 	/*
@@ -15,7 +27,8 @@ type TenantRepository interface {
 	Create(tenant *model.Tenant) (*model.Tenant, error)
 
 	/*
-		Delete 1 tenant
+		Delete 1 tenant for good, with every row of the tenant (stores, items, orders, customers, ...).
+		The users stay, only their membership is removed. The audit log is append only, its events stay too
 	*/
 	Delete(tenantId int) error
 
@@ -45,6 +58,7 @@ type TenantRepository interface {
 	/*
 		Remove user from tenant
 		- delete from user_mtm_tenant
+		- revoke the API keys created by the removed member
 	*/
 	RemoveUserFromTenant(userMtmTenantId *model.UserMtmTenant, userId int) (string, error)
 
@@ -52,4 +66,63 @@ type TenantRepository interface {
 		Get 1 tenant users/members
 	*/
 	GetTenantMembers(tenantId int) ([]*model.User, error)
+
+	/*
+		Set the tenant timezone, only the owner allowed.
+		Return "[TenantRepository:SetTimezone]" when tenant not owned by the user
+	*/
+	SetTimezone(tenantId, ownerUserId int, timezone string) error
+
+	/*
+		Force 2FA for the owner and managers, only the owner allowed.
+		Return "[TenantRepository:SetTwoFactorPolicy]" when tenant not owned by the user
+	*/
+	SetTwoFactorPolicy(tenantId, ownerUserId int, required bool) error
+
+	/*
+		Set the role of a member, only the owner allowed.
+		Return "[TenantRepository:SetMemberRole]" when tenant not owned by the user,
+		gorm.ErrRecordNotFound when the user is not a member.
+		The API keys created by a member demoted from manager are revoked
+	*/
+	SetMemberRole(tenantId, ownerUserId, userId int, role model.TenantRole) error
+
+	/*
+		Request the transfer of the tenant to another member, the pending one is cancelled.
+		ErrTenantOwnerOnly when FromUserId is not the owner, ErrOwnershipTransferTarget when ToUserId is not a member
+	*/
+	RequestOwnershipTransfer(transfer *model.TenantOwnershipTransfer) (*model.TenantOwnershipTransfer, error)
+
+	/*
+		The pending transfer of the tenant at now, ErrOwnershipTransferNotFound when none
+	*/
+	FindOwnershipTransfer(tenantId int, now time.Time) (*model.TenantOwnershipTransfer, error)
+
+	/*
+		Accept the pending transfer to the user: he become the owner, the previous owner stay as a manager.
+		ErrOwnershipTransferNotFound when there is no pending transfer to the user
+	*/
+	AcceptOwnershipTransfer(tenantId, userId int, now time.Time) (*model.TenantOwnershipTransfer, error)
+
+	/*
+		Cancel the pending transfer, by the owner who requested it or the member who decline it.
+		ErrOwnershipTransferNotFound when there is no pending transfer of the user
+	*/
+	CancelOwnershipTransfer(tenantId, userId int, now time.Time) error
+
+	/*
+		Deactivate the tenant, the confirmName must be the tenant name. The pending transfer is cancelled.
+		ErrTenantOwnerOnly when not owned by the user
+	*/
+	Archive(tenantId, ownerUserId int, confirmName string, now time.Time) (*model.Tenant, error)
+
+	/*
+		Activate again the archived tenant. ErrTenantNotArchived when not an archived tenant of the user
+	*/
+	Restore(tenantId, ownerUserId int) error
+
+	/*
+		Id of the tenants archived before the time
+	*/
+	GetArchivedBefore(before time.Time) ([]int, error)
 }
@@ -3,10 +3,13 @@ package repository
 import (
 	"cashier-api/model"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TenantRepositoryImpl struct {
@@ -41,7 +44,55 @@ func (repository *TenantRepositoryImpl) Create(tenant *model.Tenant) (*model.Ten
 }
 
 func (repository *TenantRepositoryImpl) Delete(tenantId int) error {
-	panic("not implemented") // TODO: Implement
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		orderItems := tx.Unscoped().Model(&model.OrderItem{}).Select("id").Where("tenant_id = ?", tenantId)
+		terminalSessions := tx.Model(&model.UserSession{}).Select("id").
+			Where("terminal_id IN (?)", tx.Model(&model.Terminal{}).Select("id").Where("tenant_id = ?", tenantId))
+
+		// Children first, the rows without tenant_id are found by their parent
+		steps := []struct {
+			model interface{}
+			query string
+			arg   interface{}
+		}{
+			{&model.CategoryMtmWarehouse{}, "category_id IN (?)", tx.Model(&model.Category{}).Select("id").Where("tenant_id = ?", tenantId)},
+			{&model.PurchasedItem{}, "order_item_id IN (?)", orderItems},
+			{&model.ParkedOrderLine{}, "parked_order_id IN (?)", tx.Model(&model.ParkedOrder{}).Select("id").Where("tenant_id = ?", tenantId)},
+			{&model.UserRefreshToken{}, "session_id IN (?)", terminalSessions},
+			{&model.UserSession{}, "id IN (?)", terminalSessions},
+			{&model.ReportScheduleRun{}, "tenant_id = ?", tenantId},
+			{&model.GiftCardMovement{}, "tenant_id = ?", tenantId},
+			{&model.LoyaltyLedger{}, "tenant_id = ?", tenantId},
+			{&model.OrderStatusHistory{}, "tenant_id = ?", tenantId},
+			{&model.ParkedOrder{}, "tenant_id = ?", tenantId},
+			{&model.GiftCard{}, "tenant_id = ?", tenantId},
+			{&model.ClosingReport{}, "tenant_id = ?", tenantId},
+			{&model.ReportSchedule{}, "tenant_id = ?", tenantId},
+			{&model.LoyaltySetting{}, "tenant_id = ?", tenantId},
+			{&model.MarginAlertSetting{}, "tenant_id = ?", tenantId},
+			{&model.TenantInvitation{}, "tenant_id = ?", tenantId},
+			{&model.TenantOwnershipTransfer{}, "tenant_id = ?", tenantId},
+			{&model.ApiKey{}, "tenant_id = ?", tenantId},
+			{&model.TenantMemberPin{}, "tenant_id = ?", tenantId},
+			{&model.Terminal{}, "tenant_id = ?", tenantId},
+			{&model.OrderItem{}, "tenant_id = ?", tenantId},
+			{&model.StoreStock{}, "tenant_id = ?", tenantId},
+			{&model.Customer{}, "tenant_id = ?", tenantId},
+			{&model.Category{}, "tenant_id = ?", tenantId},
+			{&model.Item{}, "tenant_id = ?", tenantId},
+			{&model.Store{}, "tenant_id = ?", tenantId},
+			{&model.UserMtmTenant{}, "tenant_id = ?", tenantId},
+			{&model.Tenant{}, "id = ?", tenantId},
+		}
+		for _, step := range steps {
+			err := tx.Unscoped().Where(step.query, step.arg).Delete(step.model).Error
+			if err != nil {
+				return fmt.Errorf("Delete tenant %d failed at %T: %w", tenantId, step.model, err)
+			}
+		}
+
+		return nil
+	})
 }
 
 func (repository *TenantRepositoryImpl) GetTenantWithUser(userId int) ([]*model.Tenant, error) {
@@ -91,7 +142,7 @@ Will create new data into user_mtm_tenant table
 that will make user have many to many relation with tenant table
 */
 func (repository *TenantRepositoryImpl) AddUserToTenant(userId, tenantId int) (*model.UserMtmTenant, error) {
-	newUserMtmTenant := &model.UserMtmTenant{UserId: userId, TenantId: tenantId}
+	newUserMtmTenant := &model.UserMtmTenant{UserId: userId, TenantId: tenantId, Role: model.TenantRoleCashier}
 	err := repository.Client.Table(UserMtmTenantTable).
 		Create(newUserMtmTenant).Error
 	if err != nil {
@@ -102,17 +153,28 @@ func (repository *TenantRepositoryImpl) AddUserToTenant(userId, tenantId int) (*
 
 func (repository *TenantRepositoryImpl) RemoveUserFromTenant(userMtmTenant *model.UserMtmTenant, userId int) (string, error) {
 	var response string
-	err := repository.Client.
-		Raw("SELECT remove_user_from_tenant(?, ?, ?)", userId, userMtmTenant.UserId, userMtmTenant.TenantId).
-		Scan(&response).Error
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Raw("SELECT remove_user_from_tenant(?, ?, ?)", userId, userMtmTenant.UserId, userMtmTenant.TenantId).
+			Scan(&response).Error
+		if err != nil {
+			return err
+		}
+
+		if strings.Contains(response, "[ERROR] ") {
+			return errors.New(response)
+		}
+
+		// An API key act as its creator, a removed member must not keep access through it
+		if strings.Contains(response, "Removed from tenant") {
+			return revokeMemberApiKeys(tx, userMtmTenant.TenantId, userMtmTenant.UserId)
+		}
+		return nil
+	})
 	if err != nil {
 		return "", err
 	}
 
-	if strings.Contains(response, "[ERROR] ") {
-		return "", errors.New(response)
-	}
-
 	switch {
 	case strings.Contains(response, "Current tenant will be archived"),
 		strings.Contains(response, "Removed from tenant"):
@@ -125,6 +187,13 @@ func (repository *TenantRepositoryImpl) RemoveUserFromTenant(userMtmTenant *mode
 	return response, nil
 }
 
+// Revoke the active API keys created by the member in the tenant
+func revokeMemberApiKeys(tx *gorm.DB, tenantId, userId int) error {
+	return tx.Model(&model.ApiKey{}).
+		Where("tenant_id = ? AND created_by_user_id = ? AND revoked_at IS NULL", tenantId, userId).
+		UpdateColumn("revoked_at", time.Now()).Error
+}
+
 // GetTenantMembers implements TenantRepository.
 func (repository *TenantRepositoryImpl) GetTenantMembers(tenantId int) ([]*model.User, error) {
 	var tenant model.Tenant
@@ -143,3 +212,256 @@ func (repository *TenantRepositoryImpl) GetTenantMembers(tenantId int) ([]*model
 
 	return results, nil
 }
+
+// SetTimezone implements TenantRepository.
+func (repository *TenantRepositoryImpl) SetTimezone(tenantId, ownerUserId int, timezone string) error {
+	result := repository.Client.Table(TenantTable).
+		Where("id = ? AND owner_user_id = ?", tenantId, ownerUserId).
+		Update("timezone", timezone)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("[TenantRepository:SetTimezone]")
+	}
+
+	return nil
+}
+
+// SetTwoFactorPolicy implements TenantRepository.
+func (repository *TenantRepositoryImpl) SetTwoFactorPolicy(tenantId, ownerUserId int, required bool) error {
+	result := repository.Client.Table(TenantTable).
+		Where("id = ? AND owner_user_id = ?", tenantId, ownerUserId).
+		Update("require_two_factor", required)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("[TenantRepository:SetTwoFactorPolicy]")
+	}
+
+	return nil
+}
+
+// SetMemberRole implements TenantRepository.
+func (repository *TenantRepositoryImpl) SetMemberRole(tenantId, ownerUserId, userId int, role model.TenantRole) error {
+	var owned int64
+	err := repository.Client.Table(TenantTable).
+		Where("id = ? AND owner_user_id = ?", tenantId, ownerUserId).
+		Count(&owned).Error
+	if err != nil {
+		return err
+	}
+	if owned == 0 {
+		return errors.New("[TenantRepository:SetMemberRole]")
+	}
+
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(UserMtmTenantTable).
+			Where("tenant_id = ? AND user_id = ?", tenantId, userId).
+			Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Only the owner and managers manage API keys, a demoted member lose the ones created
+		if role != model.TenantRoleManager {
+			return revokeMemberApiKeys(tx, tenantId, userId)
+		}
+		return nil
+	})
+}
+
+// RequestOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryImpl) RequestOwnershipTransfer(transfer *model.TenantOwnershipTransfer) (*model.TenantOwnershipTransfer, error) {
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		var owned int64
+		err := tx.Table(TenantTable).
+			Where("id = ? AND owner_user_id = ?", transfer.TenantId, transfer.FromUserId).
+			Count(&owned).Error
+		if err != nil {
+			return err
+		}
+		if owned == 0 {
+			return ErrTenantOwnerOnly
+		}
+
+		var member int64
+		err = tx.Table(UserMtmTenantTable).
+			Where("tenant_id = ? AND user_id = ? AND user_id <> ?", transfer.TenantId, transfer.ToUserId, transfer.FromUserId).
+			Count(&member).Error
+		if err != nil {
+			return err
+		}
+		if member == 0 {
+			return ErrOwnershipTransferTarget
+		}
+
+		err = tx.Model(&model.TenantOwnershipTransfer{}).
+			Where("tenant_id = ? AND accepted_at IS NULL AND cancelled_at IS NULL", transfer.TenantId).
+			UpdateColumn("cancelled_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(transfer).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrTenantOwnerOnly) || errors.Is(err, ErrOwnershipTransferTarget) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("RequestOwnershipTransfer failed: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// FindOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryImpl) FindOwnershipTransfer(tenantId int, now time.Time) (*model.TenantOwnershipTransfer, error) {
+	var transfer model.TenantOwnershipTransfer
+	err := repository.Client.
+		Where("tenant_id = ? AND accepted_at IS NULL AND cancelled_at IS NULL AND expires_at > ?", tenantId, now).
+		Take(&transfer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOwnershipTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// AcceptOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryImpl) AcceptOwnershipTransfer(tenantId, userId int, now time.Time) (*model.TenantOwnershipTransfer, error) {
+	var transfer model.TenantOwnershipTransfer
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND to_user_id = ? AND accepted_at IS NULL AND cancelled_at IS NULL AND expires_at > ?", tenantId, userId, now).
+			Take(&transfer).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOwnershipTransferNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// The owner did not change since the request, and the member was not removed
+		result := tx.Table(TenantTable).
+			Where("id = ? AND owner_user_id = ?", tenantId, transfer.FromUserId).
+			Where("id IN (?)", tx.Table(UserMtmTenantTable).Select("tenant_id").Where("user_id = ?", userId)).
+			Update("owner_user_id", userId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOwnershipTransferNotFound
+		}
+
+		err = tx.Table(UserMtmTenantTable).
+			Where("tenant_id = ? AND user_id IN ?", tenantId, []int{transfer.FromUserId, userId}).
+			Update("role", model.TenantRoleManager).Error
+		if err != nil {
+			return err
+		}
+
+		transfer.AcceptedAt = &now
+		return tx.Model(&transfer).UpdateColumn("accepted_at", now).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrOwnershipTransferNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("AcceptOwnershipTransfer failed: %w", err)
+	}
+
+	log.Infof("Tenant %d ownership transferred from user %d to user %d", tenantId, transfer.FromUserId, userId)
+	return &transfer, nil
+}
+
+// CancelOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryImpl) CancelOwnershipTransfer(tenantId, userId int, now time.Time) error {
+	result := repository.Client.Model(&model.TenantOwnershipTransfer{}).
+		Where("tenant_id = ? AND accepted_at IS NULL AND cancelled_at IS NULL AND expires_at > ?", tenantId, now).
+		Where("from_user_id = ? OR to_user_id = ?", userId, userId).
+		UpdateColumn("cancelled_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOwnershipTransferNotFound
+	}
+
+	return nil
+}
+
+// Archive implements TenantRepository.
+func (repository *TenantRepositoryImpl) Archive(tenantId, ownerUserId int, confirmName string, now time.Time) (*model.Tenant, error) {
+	var tenant model.Tenant
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND owner_user_id = ?", tenantId, ownerUserId).
+			Take(&tenant).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTenantOwnerOnly
+		}
+		if err != nil {
+			return err
+		}
+		if tenant.Name != confirmName {
+			return ErrTenantNameMismatch
+		}
+
+		tenant.IsActive = false
+		tenant.ArchivedAt = &now
+		err = tx.Model(&tenant).
+			Updates(map[string]interface{}{"is_active": false, "archived_at": now}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.TenantOwnershipTransfer{}).
+			Where("tenant_id = ? AND accepted_at IS NULL AND cancelled_at IS NULL", tenantId).
+			UpdateColumn("cancelled_at", now).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrTenantOwnerOnly) || errors.Is(err, ErrTenantNameMismatch) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Archive tenant failed: %w", err)
+	}
+
+	return &tenant, nil
+}
+
+// Restore implements TenantRepository.
+func (repository *TenantRepositoryImpl) Restore(tenantId, ownerUserId int) error {
+	result := repository.Client.Table(TenantTable).
+		Where("id = ? AND owner_user_id = ? AND archived_at IS NOT NULL", tenantId, ownerUserId).
+		Updates(map[string]interface{}{"is_active": true, "archived_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTenantNotArchived
+	}
+
+	return nil
+}
+
+// GetArchivedBefore implements TenantRepository.
+func (repository *TenantRepositoryImpl) GetArchivedBefore(before time.Time) ([]int, error) {
+	var tenantIds = make([]int, 0)
+	err := repository.Client.Table(TenantTable).
+		Where("is_active = FALSE AND archived_at < ?", before).
+		Order("archived_at").
+		Pluck("id", &tenantIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tenantIds, nil
+}
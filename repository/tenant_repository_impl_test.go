@@ -4,10 +4,12 @@ import (
 	"cashier-api/helper/client"
 	"cashier-api/model"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestTenantRepositoryImpl(t *testing.T) {
@@ -487,3 +489,96 @@ func TestTenantRepositoryImpl(t *testing.T) {
 		require.Nil(t, err, "If this fail, then delete data immediately TestTenantRepositoryImpl/Register 3")
 	})
 }
+
+func TestTenantRepositoryOwnershipAndArchive(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("OwnershipTransfer", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTenantRepositoryImpl(tx)
+		now := time.Now()
+
+		member := &model.User{Name: "New Owner", Email: "new_owner_test@example.com", Password: "password"}
+		require.NoError(t, tx.Create(member).Error)
+		require.NoError(t, tx.Create(&model.UserMtmTenant{UserId: ownerId, TenantId: tenantId}).Error)
+
+		newTransfer := func(fromUserId int) *model.TenantOwnershipTransfer {
+			return &model.TenantOwnershipTransfer{TenantId: tenantId, FromUserId: fromUserId, ToUserId: member.Id, ExpiresAt: now.Add(model.TenantOwnershipTransferLifetime)}
+		}
+
+		_, err := repo.RequestOwnershipTransfer(newTransfer(member.Id))
+		assert.ErrorIs(t, err, ErrTenantOwnerOnly)
+		_, err = repo.RequestOwnershipTransfer(newTransfer(ownerId))
+		assert.ErrorIs(t, err, ErrOwnershipTransferTarget)
+
+		require.NoError(t, tx.Create(&model.UserMtmTenant{UserId: member.Id, TenantId: tenantId, Role: model.TenantRoleCashier}).Error)
+		transfer, err := repo.RequestOwnershipTransfer(newTransfer(ownerId))
+		require.NoError(t, err)
+
+		found, err := repo.FindOwnershipTransfer(tenantId, now)
+		require.NoError(t, err)
+		assert.Equal(t, transfer.Id, found.Id)
+
+		// Only the receiving member accept
+		_, err = repo.AcceptOwnershipTransfer(tenantId, ownerId, now)
+		assert.ErrorIs(t, err, ErrOwnershipTransferNotFound)
+		_, err = repo.AcceptOwnershipTransfer(tenantId, member.Id, now)
+		require.NoError(t, err)
+
+		var tenant model.Tenant
+		require.NoError(t, tx.Take(&tenant, "id = ?", tenantId).Error)
+		assert.Equal(t, member.Id, tenant.OwnerUserId)
+
+		var previousOwner model.UserMtmTenant
+		require.NoError(t, tx.Take(&previousOwner, "tenant_id = ? AND user_id = ?", tenantId, ownerId).Error)
+		assert.Equal(t, model.TenantRoleManager, previousOwner.Role)
+
+		_, err = repo.FindOwnershipTransfer(tenantId, now)
+		assert.ErrorIs(t, err, ErrOwnershipTransferNotFound)
+		assert.ErrorIs(t, repo.CancelOwnershipTransfer(tenantId, ownerId, now), ErrOwnershipTransferNotFound)
+	})
+
+	t.Run("ArchiveRestoreAndDelete", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTenantRepositoryImpl(tx)
+		now := time.Now()
+
+		_, err := repo.Archive(tenantId, ownerId+1000, "Order Item Test Tenant", now)
+		assert.ErrorIs(t, err, ErrTenantOwnerOnly)
+		_, err = repo.Archive(tenantId, ownerId, "Order Item", now)
+		assert.ErrorIs(t, err, ErrTenantNameMismatch)
+
+		tenant, err := repo.Archive(tenantId, ownerId, "Order Item Test Tenant", now)
+		require.NoError(t, err)
+		assert.False(t, tenant.IsActive)
+
+		tenantIds, err := repo.GetArchivedBefore(now.Add(time.Second))
+		require.NoError(t, err)
+		assert.Contains(t, tenantIds, tenantId)
+		tenantIds, err = repo.GetArchivedBefore(now.Add(-time.Second))
+		require.NoError(t, err)
+		assert.NotContains(t, tenantIds, tenantId)
+
+		require.NoError(t, repo.Restore(tenantId, ownerId))
+		assert.ErrorIs(t, repo.Restore(tenantId, ownerId), ErrTenantNotArchived)
+
+		require.NoError(t, tx.Create(&model.UserMtmTenant{UserId: ownerId, TenantId: tenantId}).Error)
+		require.NoError(t, repo.Delete(tenantId))
+
+		var remaining int64
+		require.NoError(t, tx.Model(&model.Store{}).Where("id = ?", storeId).Count(&remaining).Error)
+		assert.Zero(t, remaining)
+		require.NoError(t, tx.Model(&model.UserMtmTenant{}).Where("tenant_id = ?", tenantId).Count(&remaining).Error)
+		assert.Zero(t, remaining)
+		require.NoError(t, tx.Model(&model.User{}).Where("id = ?", ownerId).Count(&remaining).Error)
+		assert.Equal(t, int64(1), remaining)
+	})
+}
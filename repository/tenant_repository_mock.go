@@ -2,6 +2,7 @@ package repository
 
 import (
 	"cashier-api/model"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -33,8 +34,9 @@ func (t *TenantRepositoryMock) Create(tenant *model.Tenant) (*model.Tenant, erro
 }
 
 // Delete implements TenantRepository.
-func (t *TenantRepositoryMock) Delete(tenantId int) error {
-	panic("unimplemented")
+func (repository *TenantRepositoryMock) Delete(tenantId int) error {
+	args := repository.Mock.Called(tenantId)
+	return args.Error(0)
 }
 
 // GetByUserId implements TenantRepository.
@@ -81,3 +83,88 @@ func (repository *TenantRepositoryMock) GetTenantMembers(tenantId int) ([]*model
 
 	return args.Get(0).([]*model.User), nil
 }
+
+// SetTimezone implements TenantRepository.
+func (repository *TenantRepositoryMock) SetTimezone(tenantId, ownerUserId int, timezone string) error {
+	args := repository.Mock.Called(tenantId, ownerUserId, timezone)
+	return args.Error(0)
+}
+
+// SetTwoFactorPolicy implements TenantRepository.
+func (repository *TenantRepositoryMock) SetTwoFactorPolicy(tenantId, ownerUserId int, required bool) error {
+	args := repository.Mock.Called(tenantId, ownerUserId, required)
+	return args.Error(0)
+}
+
+// SetMemberRole implements TenantRepository.
+func (repository *TenantRepositoryMock) SetMemberRole(tenantId, ownerUserId, userId int, role model.TenantRole) error {
+	args := repository.Mock.Called(tenantId, ownerUserId, userId, role)
+	return args.Error(0)
+}
+
+// RequestOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryMock) RequestOwnershipTransfer(transfer *model.TenantOwnershipTransfer) (*model.TenantOwnershipTransfer, error) {
+	args := repository.Mock.Called(transfer)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.TenantOwnershipTransfer), nil
+}
+
+// FindOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryMock) FindOwnershipTransfer(tenantId int, now time.Time) (*model.TenantOwnershipTransfer, error) {
+	args := repository.Mock.Called(tenantId, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.TenantOwnershipTransfer), nil
+}
+
+// AcceptOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryMock) AcceptOwnershipTransfer(tenantId, userId int, now time.Time) (*model.TenantOwnershipTransfer, error) {
+	args := repository.Mock.Called(tenantId, userId, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.TenantOwnershipTransfer), nil
+}
+
+// CancelOwnershipTransfer implements TenantRepository.
+func (repository *TenantRepositoryMock) CancelOwnershipTransfer(tenantId, userId int, now time.Time) error {
+	args := repository.Mock.Called(tenantId, userId, now)
+	return args.Error(0)
+}
+
+// Archive implements TenantRepository.
+func (repository *TenantRepositoryMock) Archive(tenantId, ownerUserId int, confirmName string, now time.Time) (*model.Tenant, error) {
+	args := repository.Mock.Called(tenantId, ownerUserId, confirmName, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Tenant), nil
+}
+
+// Restore implements TenantRepository.
+func (repository *TenantRepositoryMock) Restore(tenantId, ownerUserId int) error {
+	args := repository.Mock.Called(tenantId, ownerUserId)
+	return args.Error(0)
+}
+
+// GetArchivedBefore implements TenantRepository.
+func (repository *TenantRepositoryMock) GetArchivedBefore(before time.Time) ([]int, error) {
+	args := repository.Mock.Called(before)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"time"
+)
+
+var (
+	ErrTerminalAccessDenied = errors.New("Only the owner or a manager of the tenant can manage the terminals")
+	ErrTerminalNotFound     = errors.New("Terminal not found")
+	ErrTerminalInvalid      = errors.New("Terminal is not registered or was revoked")
+	ErrPinNotSet            = errors.New("No PIN set for this member")
+)
+
+/*
+Shared terminals of the stores and the member PINs used on them.
+
+	Every method that write more than 1 row is wrapped with transaction
+*/
+type TerminalRepository interface {
+	/*
+		Register the terminal, the store must be an active store of the tenant.
+		ErrTerminalAccessDenied when managerUserId is not the owner or a manager
+	*/
+	Create(terminal *model.Terminal, managerUserId int) (*model.Terminal, error)
+
+	/*
+		Every terminal of the tenant, revoked included
+	*/
+	GetByTenant(tenantId int) ([]model.Terminal, error)
+
+	/*
+		Revoke the terminal and every session signed in on it.
+		ErrTerminalNotFound when it is not a terminal of the tenant or already revoked
+	*/
+	Revoke(tenantId int, terminalId int, managerUserId int) error
+
+	/*
+		The active terminal of the device token, ErrTerminalInvalid otherwise
+	*/
+	FindByToken(tokenHash string) (*model.Terminal, error)
+
+	/*
+		Members of the tenant with a PIN, by name
+	*/
+	GetMembers(tenantId int) ([]model.TerminalMember, error)
+
+	/*
+		Create or replace the PIN, unlock it
+	*/
+	SetPin(tenantId int, userId int, pinHash string) error
+
+	/*
+		The PIN of a current member, ErrPinNotSet when none or not a member anymore
+	*/
+	FindPin(tenantId int, userId int) (*model.TenantMemberPin, error)
+
+	/*
+		Count 1 wrong PIN, at maxAttempts the PIN is locked until now + lockout and the count restart.
+		Return the lock end, nil when not locked
+	*/
+	FailPin(pinId int, maxAttempts int, lockout time.Duration, now time.Time) (*time.Time, error)
+
+	/*
+		Right PIN, clear the failed attempts and mark the terminal used
+	*/
+	PinSucceeded(pinId int, terminalId int, now time.Time) error
+}
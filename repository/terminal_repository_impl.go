@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TerminalRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewTerminalRepositoryImpl(client *gorm.DB) TerminalRepository {
+	return &TerminalRepositoryImpl{Client: client}
+}
+
+// Owner of the tenant or a member with the manager role
+func canManageTenant(tx *gorm.DB, tenantId int, userId int) (bool, error) {
+	var count int64
+	err := tx.Table(TenantTable).
+		Where("id = ?", tenantId).
+		Where("owner_user_id = ? OR id IN (?)", userId,
+			tx.Table(UserMtmTenantTable).
+				Select("tenant_id").
+				Where("user_id = ? AND role = ?", userId, model.TenantRoleManager)).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Create implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) Create(terminal *model.Terminal, managerUserId int) (*model.Terminal, error) {
+	allowed, err := canManageTenant(repository.Client, terminal.TenantId, managerUserId)
+	if err != nil {
+		return nil, fmt.Errorf("Create terminal failed: %w", err)
+	}
+	if !allowed {
+		return nil, ErrTerminalAccessDenied
+	}
+
+	var store int64
+	err = repository.Client.Table(StoreTable).
+		Where("id = ? AND tenant_id = ? AND is_active = TRUE", terminal.StoreId, terminal.TenantId).
+		Count(&store).Error
+	if err != nil {
+		return nil, fmt.Errorf("Create terminal failed: %w", err)
+	}
+	if store == 0 {
+		return nil, errors.New("Store not found or not active")
+	}
+
+	terminal.CreatedByUserId = managerUserId
+	err = repository.Client.Create(terminal).Error
+	if err != nil {
+		return nil, fmt.Errorf("Create terminal failed: %w", err)
+	}
+
+	return terminal, nil
+}
+
+// GetByTenant implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) GetByTenant(tenantId int) ([]model.Terminal, error) {
+	var terminals []model.Terminal
+	err := repository.Client.
+		Where("tenant_id = ?", tenantId).
+		Order("revoked_at IS NOT NULL, store_id, name").
+		Find(&terminals).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetByTenant terminal failed: %w", err)
+	}
+
+	return terminals, nil
+}
+
+// Revoke implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) Revoke(tenantId int, terminalId int, managerUserId int) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		allowed, err := canManageTenant(tx, tenantId, managerUserId)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrTerminalAccessDenied
+		}
+
+		now := time.Now()
+		result := tx.Model(&model.Terminal{}).
+			Where("id = ? AND tenant_id = ? AND revoked_at IS NULL", terminalId, tenantId).
+			UpdateColumn("revoked_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("Revoke terminal failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTerminalNotFound
+		}
+
+		return tx.Model(&model.UserSession{}).
+			Where("terminal_id = ? AND revoked_at IS NULL", terminalId).
+			Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": model.SessionRevokeTerminal}).Error
+	})
+}
+
+// FindByToken implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) FindByToken(tokenHash string) (*model.Terminal, error) {
+	var terminal model.Terminal
+	err := repository.Client.
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		Take(&terminal).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTerminalInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &terminal, nil
+}
+
+// GetMembers implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) GetMembers(tenantId int) ([]model.TerminalMember, error) {
+	var members []model.TerminalMember
+	err := repository.Client.Table("tenant_member_pin AS p").
+		Select("u.id AS user_id, u.name").
+		Joins(`JOIN "user" u ON u.id = p.user_id`).
+		Joins("JOIN user_mtm_tenant umt ON umt.user_id = p.user_id AND umt.tenant_id = p.tenant_id").
+		Where("p.tenant_id = ?", tenantId).
+		Order("u.name").
+		Scan(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetMembers terminal failed: %w", err)
+	}
+
+	return members, nil
+}
+
+// SetPin implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) SetPin(tenantId int, userId int, pinHash string) error {
+	now := time.Now()
+	err := repository.Client.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"pin_hash":        pinHash,
+			"failed_attempts": 0,
+			"locked_until":    nil,
+			"updated_at":      now,
+		}),
+	}).Create(&model.TenantMemberPin{
+		TenantId:  tenantId,
+		UserId:    userId,
+		PinHash:   pinHash,
+		UpdatedAt: &now,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("SetPin failed: %w", err)
+	}
+
+	return nil
+}
+
+// FindPin implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) FindPin(tenantId int, userId int) (*model.TenantMemberPin, error) {
+	var pin model.TenantMemberPin
+	err := repository.Client.
+		Where("tenant_id = ? AND user_id = ?", tenantId, userId).
+		Where("EXISTS (?)", repository.Client.Table(UserMtmTenantTable).
+			Select("1").
+			Where("tenant_id = ? AND user_id = ?", tenantId, userId)).
+		Take(&pin).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPinNotSet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pin, nil
+}
+
+// FailPin implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) FailPin(pinId int, maxAttempts int, lockout time.Duration, now time.Time) (*time.Time, error) {
+	// 1 statement, wrong PINs typed at once on 2 terminals are all counted
+	var updated []model.TenantMemberPin
+	err := repository.Client.Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("id = ?", pinId).
+		UpdateColumns(map[string]interface{}{
+			"failed_attempts": gorm.Expr("CASE WHEN failed_attempts + 1 >= ? THEN 0 ELSE failed_attempts + 1 END", maxAttempts),
+			"locked_until":    gorm.Expr("CASE WHEN failed_attempts + 1 >= ? THEN ? ELSE locked_until END", maxAttempts, now.Add(lockout)),
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("FailPin failed: %w", err)
+	}
+	if len(updated) == 0 || !updated[0].IsLocked(now) {
+		return nil, nil
+	}
+
+	return updated[0].LockedUntil, nil
+}
+
+// PinSucceeded implements TerminalRepository.
+func (repository *TerminalRepositoryImpl) PinSucceeded(pinId int, terminalId int, now time.Time) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.TenantMemberPin{}).
+			Where("id = ? AND failed_attempts > 0", pinId).
+			UpdateColumn("failed_attempts", 0).Error
+		if err != nil {
+			return fmt.Errorf("PinSucceeded failed: %w", err)
+		}
+
+		return tx.Model(&model.Terminal{}).
+			Where("id = ?", terminalId).
+			UpdateColumn("last_used_at", now).Error
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TerminalRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewTerminalRepositoryMock(mock *mock.Mock) TerminalRepository {
+	return &TerminalRepositoryMock{Mock: mock}
+}
+
+// Create implements TerminalRepository.
+func (repository *TerminalRepositoryMock) Create(terminal *model.Terminal, managerUserId int) (*model.Terminal, error) {
+	args := repository.Mock.Called(terminal, managerUserId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Terminal), nil
+}
+
+// GetByTenant implements TerminalRepository.
+func (repository *TerminalRepositoryMock) GetByTenant(tenantId int) ([]model.Terminal, error) {
+	args := repository.Mock.Called(tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.Terminal), nil
+}
+
+// Revoke implements TerminalRepository.
+func (repository *TerminalRepositoryMock) Revoke(tenantId int, terminalId int, managerUserId int) error {
+	args := repository.Mock.Called(tenantId, terminalId, managerUserId)
+	return args.Error(0)
+}
+
+// FindByToken implements TerminalRepository.
+func (repository *TerminalRepositoryMock) FindByToken(tokenHash string) (*model.Terminal, error) {
+	args := repository.Mock.Called(tokenHash)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.Terminal), nil
+}
+
+// GetMembers implements TerminalRepository.
+func (repository *TerminalRepositoryMock) GetMembers(tenantId int) ([]model.TerminalMember, error) {
+	args := repository.Mock.Called(tenantId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]model.TerminalMember), nil
+}
+
+// SetPin implements TerminalRepository.
+func (repository *TerminalRepositoryMock) SetPin(tenantId int, userId int, pinHash string) error {
+	args := repository.Mock.Called(tenantId, userId, pinHash)
+	return args.Error(0)
+}
+
+// FindPin implements TerminalRepository.
+func (repository *TerminalRepositoryMock) FindPin(tenantId int, userId int) (*model.TenantMemberPin, error) {
+	args := repository.Mock.Called(tenantId, userId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.TenantMemberPin), nil
+}
+
+// FailPin implements TerminalRepository.
+func (repository *TerminalRepositoryMock) FailPin(pinId int, maxAttempts int, lockout time.Duration, now time.Time) (*time.Time, error) {
+	args := repository.Mock.Called(pinId, maxAttempts, lockout, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*time.Time), nil
+}
+
+// PinSucceeded implements TerminalRepository.
+func (repository *TerminalRepositoryMock) PinSucceeded(pinId int, terminalId int, now time.Time) error {
+	args := repository.Mock.Called(pinId, terminalId, now)
+	return args.Error(0)
+}
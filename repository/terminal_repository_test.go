@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTerminalRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("RegisterAndRevoke", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, storeId := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTerminalRepositoryImpl(tx)
+
+		_, err := repo.Create(&model.Terminal{TenantId: tenantId, StoreId: storeId, Name: "Till 1", TokenHash: "terminal-1"}, ownerId+1000)
+		assert.ErrorIs(t, err, ErrTerminalAccessDenied)
+
+		terminal, err := repo.Create(&model.Terminal{TenantId: tenantId, StoreId: storeId, Name: "Till 1", TokenHash: "terminal-1"}, ownerId)
+		require.NoError(t, err)
+		require.NotZero(t, terminal.Id)
+
+		found, err := repo.FindByToken("terminal-1")
+		require.NoError(t, err)
+		assert.Equal(t, terminal.Id, found.Id)
+
+		session, err := NewSessionRepositoryImpl(tx).Create(&model.UserSession{
+			UserId:     ownerId,
+			TerminalId: &terminal.Id,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}, "")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Revoke(tenantId, terminal.Id, ownerId))
+		assert.ErrorIs(t, repo.Revoke(tenantId, terminal.Id, ownerId), ErrTerminalNotFound)
+		_, err = repo.FindByToken("terminal-1")
+		assert.ErrorIs(t, err, ErrTerminalInvalid)
+
+		var revoked model.UserSession
+		require.NoError(t, tx.Take(&revoked, "id = ?", session.Id).Error)
+		assert.Equal(t, model.SessionRevokeTerminal, revoked.RevokedReason)
+	})
+
+	t.Run("PinLockout", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTerminalRepositoryImpl(tx)
+		now := time.Now()
+
+		_, err := repo.FindPin(tenantId, ownerId)
+		assert.ErrorIs(t, err, ErrPinNotSet)
+
+		require.NoError(t, repo.SetPin(tenantId, ownerId, "hash-1"))
+		pin, err := repo.FindPin(tenantId, ownerId)
+		require.NoError(t, err)
+
+		for i := 1; i < 3; i++ {
+			lockedUntil, err := repo.FailPin(pin.Id, 3, time.Minute, now)
+			require.NoError(t, err)
+			assert.Nil(t, lockedUntil)
+		}
+		lockedUntil, err := repo.FailPin(pin.Id, 3, time.Minute, now)
+		require.NoError(t, err)
+		require.NotNil(t, lockedUntil)
+		assert.WithinDuration(t, now.Add(time.Minute), *lockedUntil, time.Second)
+
+		// A new PIN unlock it
+		require.NoError(t, repo.SetPin(tenantId, ownerId, "hash-2"))
+		pin, err = repo.FindPin(tenantId, ownerId)
+		require.NoError(t, err)
+		assert.False(t, pin.IsLocked(now))
+		assert.Equal(t, "hash-2", pin.PinHash)
+
+		members, err := repo.GetMembers(tenantId)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.Equal(t, ownerId, members[0].UserId)
+	})
+}
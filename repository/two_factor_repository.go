@@ -0,0 +1,50 @@
+package repository
+
+import "time"
+
+/*
+TOTP secret (columns of the user table) and recovery codes of a user.
+
+	Every method that write more than 1 row is wrapped with transaction
+*/
+type TwoFactorRepository interface {
+	/*
+		Store a pending secret, not enabled until Enable. An enabled TOTP is not replaced
+	*/
+	SaveSecret(userId int, secret string) error
+
+	/*
+		Enable the pending secret at the confirmed step and replace the recovery codes
+	*/
+	Enable(userId int, step int64, now time.Time, recoveryCodeHashes []string) error
+
+	/*
+		Remove the secret and the recovery codes
+	*/
+	Disable(userId int) error
+
+	/*
+		Accept the time step only when it is after the last accepted one, a TOTP code is used once
+	*/
+	UseStep(userId int, step int64) (bool, error)
+
+	/*
+		Mark the recovery code used, false when unknown or already used
+	*/
+	UseRecoveryCode(userId int, codeHash string, now time.Time) (bool, error)
+
+	/*
+		Delete every recovery code of the user and insert the new ones
+	*/
+	ReplaceRecoveryCodes(userId int, recoveryCodeHashes []string) error
+
+	/*
+		Count the unused recovery codes
+	*/
+	CountRecoveryCodes(userId int) (int, error)
+
+	/*
+		The user own, or manage, a tenant requiring 2FA
+	*/
+	IsRequiredForUser(userId int) (bool, error)
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewTwoFactorRepositoryImpl(client *gorm.DB) TwoFactorRepository {
+	return &TwoFactorRepositoryImpl{Client: client}
+}
+
+// SaveSecret implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) SaveSecret(userId int, secret string) error {
+	result := repository.Client.Model(&model.User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", userId).
+		UpdateColumns(map[string]interface{}{"totp_secret": secret, "totp_last_step": 0})
+	if result.Error != nil {
+		return fmt.Errorf("SaveSecret failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("Two factor authentication is already enabled")
+	}
+
+	return nil
+}
+
+// Enable implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) Enable(userId int, step int64, now time.Time, recoveryCodeHashes []string) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND totp_enabled_at IS NULL AND totp_secret <> ''", userId).
+			UpdateColumns(map[string]interface{}{"totp_enabled_at": now, "totp_last_step": step})
+		if result.Error != nil {
+			return fmt.Errorf("Enable two factor failed: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("No pending two factor enrolment, please enroll first")
+		}
+
+		return replaceRecoveryCodes(tx, userId, recoveryCodeHashes)
+	})
+}
+
+// Disable implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) Disable(userId int) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).
+			Where("id = ?", userId).
+			UpdateColumns(map[string]interface{}{"totp_secret": "", "totp_enabled_at": nil, "totp_last_step": 0}).Error
+		if err != nil {
+			return fmt.Errorf("Disable two factor failed: %w", err)
+		}
+
+		return tx.Where("user_id = ?", userId).Delete(&model.UserRecoveryCode{}).Error
+	})
+}
+
+// UseStep implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) UseStep(userId int, step int64) (bool, error) {
+	// Conditional update, 2 requests with the same code can't both pass
+	result := repository.Client.Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userId, step).
+		UpdateColumn("totp_last_step", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("UseStep failed: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UseRecoveryCode implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) UseRecoveryCode(userId int, codeHash string, now time.Time) (bool, error) {
+	result := repository.Client.Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		UpdateColumn("used_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("UseRecoveryCode failed: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) ReplaceRecoveryCodes(userId int, recoveryCodeHashes []string) error {
+	return repository.Client.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userId, recoveryCodeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userId int, recoveryCodeHashes []string) error {
+	err := tx.Where("user_id = ?", userId).Delete(&model.UserRecoveryCode{}).Error
+	if err != nil {
+		return fmt.Errorf("Delete recovery codes failed: %w", err)
+	}
+
+	codes := make([]model.UserRecoveryCode, 0, len(recoveryCodeHashes))
+	for _, codeHash := range recoveryCodeHashes {
+		codes = append(codes, model.UserRecoveryCode{UserId: userId, CodeHash: codeHash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+
+	err = tx.Create(&codes).Error
+	if err != nil {
+		return fmt.Errorf("Create recovery codes failed: %w", err)
+	}
+	return nil
+}
+
+// CountRecoveryCodes implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) CountRecoveryCodes(userId int) (int, error) {
+	var count int64
+	err := repository.Client.Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userId).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("CountRecoveryCodes failed: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// IsRequiredForUser implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryImpl) IsRequiredForUser(userId int) (bool, error) {
+	var count int64
+	err := repository.Client.Model(&model.Tenant{}).
+		Where("require_two_factor = TRUE").
+		Where("owner_user_id = ? OR id IN (?)", userId,
+			repository.Client.Model(&model.UserMtmTenant{}).
+				Select("tenant_id").
+				Where("user_id = ? AND role = ?", userId, model.TenantRoleManager)).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("IsRequiredForUser failed: %w", err)
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TwoFactorRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewTwoFactorRepositoryMock(mock *mock.Mock) TwoFactorRepository {
+	return &TwoFactorRepositoryMock{Mock: mock}
+}
+
+// SaveSecret implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) SaveSecret(userId int, secret string) error {
+	args := repository.Mock.Called(userId, secret)
+	return args.Error(0)
+}
+
+// Enable implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) Enable(userId int, step int64, now time.Time, recoveryCodeHashes []string) error {
+	args := repository.Mock.Called(userId, step, now, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+// Disable implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) Disable(userId int) error {
+	args := repository.Mock.Called(userId)
+	return args.Error(0)
+}
+
+// UseStep implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) UseStep(userId int, step int64) (bool, error) {
+	args := repository.Mock.Called(userId, step)
+	return args.Bool(0), args.Error(1)
+}
+
+// UseRecoveryCode implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) UseRecoveryCode(userId int, codeHash string, now time.Time) (bool, error) {
+	args := repository.Mock.Called(userId, codeHash, now)
+	return args.Bool(0), args.Error(1)
+}
+
+// ReplaceRecoveryCodes implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) ReplaceRecoveryCodes(userId int, recoveryCodeHashes []string) error {
+	args := repository.Mock.Called(userId, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+// CountRecoveryCodes implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) CountRecoveryCodes(userId int) (int, error) {
+	args := repository.Mock.Called(userId)
+	return args.Int(0), args.Error(1)
+}
+
+// IsRequiredForUser implements TwoFactorRepository.
+func (repository *TwoFactorRepositoryMock) IsRequiredForUser(userId int) (bool, error) {
+	args := repository.Mock.Called(userId)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTwoFactorRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("EnableAndUse", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTwoFactorRepositoryImpl(tx)
+		now := time.Now()
+
+		// Not enrolled yet
+		assert.Error(t, repo.Enable(userId, 100, now, []string{"code-1"}))
+
+		require.NoError(t, repo.SaveSecret(userId, "JBSWY3DPEHPK3PXP"))
+		require.NoError(t, repo.Enable(userId, 100, now, []string{"code-1", "code-2"}))
+		assert.Error(t, repo.SaveSecret(userId, "OTHERSECRET"), "Enabled secret is not replaced")
+
+		// A code is used once
+		used, err := repo.UseStep(userId, 100)
+		require.NoError(t, err)
+		assert.False(t, used)
+		used, err = repo.UseStep(userId, 101)
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		used, err = repo.UseRecoveryCode(userId, "code-1", now)
+		require.NoError(t, err)
+		assert.True(t, used)
+		used, err = repo.UseRecoveryCode(userId, "code-1", now)
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		count, err := repo.CountRecoveryCodes(userId)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		require.NoError(t, repo.ReplaceRecoveryCodes(userId, []string{"code-3", "code-4", "code-5"}))
+		count, err = repo.CountRecoveryCodes(userId)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		require.NoError(t, repo.Disable(userId))
+		var user model.User
+		require.NoError(t, tx.Take(&user, "id = ?", userId).Error)
+		assert.False(t, user.IsTwoFactorEnabled())
+		count, err = repo.CountRecoveryCodes(userId)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("IsRequiredForUser", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		ownerId := tenantOwnerId(t, tx, tenantId)
+		repo := NewTwoFactorRepositoryImpl(tx)
+
+		required, err := repo.IsRequiredForUser(ownerId)
+		require.NoError(t, err)
+		assert.False(t, required)
+
+		require.NoError(t, NewTenantRepositoryImpl(tx).SetTwoFactorPolicy(tenantId, ownerId, true))
+		required, err = repo.IsRequiredForUser(ownerId)
+		require.NoError(t, err)
+		assert.True(t, required)
+	})
+}
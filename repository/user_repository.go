@@ -1,6 +1,9 @@
 package repository
 
-import "cashier-api/model"
+import (
+	"cashier-api/model"
+	"time"
+)
 
 type UserRepository interface {
 	/*
@@ -8,6 +11,21 @@ type UserRepository interface {
 	*/
 	GetByEmail(email string) (*model.User, error)
 
+	/*
+		Get user by id, used to refresh the access token
+	*/
+	FindById(userId int) (*model.User, error)
+
+	/*
+		Replace the password hash
+	*/
+	UpdatePassword(userId int, passwordHash string) error
+
+	/*
+		Set email_verified_at, a verified email stay verified
+	*/
+	MarkEmailVerified(userId int, now time.Time) error
+
 	/*
 		Will connect to tenant table
 	*/
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"cashier-api/model"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -45,3 +46,34 @@ func (repository *UserRepositoryImpl) GetByEmail(email string) (*model.User, err
 
 	return &user, nil
 }
+
+func (repository *UserRepositoryImpl) FindById(userId int) (*model.User, error) {
+	var user model.User
+
+	result := repository.Client.Take(&user, "id = ?", userId)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+func (repository *UserRepositoryImpl) UpdatePassword(userId int, passwordHash string) error {
+	result := repository.Client.Model(&model.User{}).
+		Where("id = ?", userId).
+		UpdateColumn("password", passwordHash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (repository *UserRepositoryImpl) MarkEmailVerified(userId int, now time.Time) error {
+	return repository.Client.Model(&model.User{}).
+		Where("id = ? AND email_verified_at IS NULL", userId).
+		UpdateColumn("email_verified_at", now).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"cashier-api/model"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -38,3 +39,26 @@ func (repository *UserRepositoryMock) CreateWithEmailAndPassword(newUser model.U
 	// Normal condition
 	return args.Get(0).(*model.User), nil
 }
+
+// FindById implements UserRepository.
+func (repository *UserRepositoryMock) FindById(userId int) (*model.User, error) {
+	args := repository.Mock.Called(userId)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.User), nil
+}
+
+// UpdatePassword implements UserRepository.
+func (repository *UserRepositoryMock) UpdatePassword(userId int, passwordHash string) error {
+	args := repository.Mock.Called(userId, passwordHash)
+	return args.Error(0)
+}
+
+// MarkEmailVerified implements UserRepository.
+func (repository *UserRepositoryMock) MarkEmailVerified(userId int, now time.Time) error {
+	args := repository.Mock.Called(userId, now)
+	return args.Error(0)
+}
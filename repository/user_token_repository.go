@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"time"
+)
+
+var ErrUserTokenInvalid = errors.New("The link is invalid, expired or already used. Please request a new one")
+
+/*
+Single use tokens sent by email, only the token hash is stored
+*/
+type UserTokenRepository interface {
+	/*
+		Create the token, the unused tokens of the same user and purpose expire right away
+	*/
+	Create(userToken *model.UserToken) (*model.UserToken, error)
+
+	/*
+		Mark the token used and return it, only once and only before it expire.
+		ErrUserTokenInvalid otherwise
+	*/
+	Consume(purpose model.UserTokenPurpose, tokenHash string, now time.Time) (*model.UserToken, error)
+
+	/*
+		Return the token when it can still be consumed, without consuming it.
+		ErrUserTokenInvalid otherwise
+	*/
+	FindValid(purpose model.UserTokenPurpose, tokenHash string, now time.Time) (*model.UserToken, error)
+
+	/*
+		Count 1 wrong answer, the token expire when it reach maxAttempts
+	*/
+	FailAttempt(userTokenId int, maxAttempts int) error
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTokenRepositoryImpl struct {
+	Client *gorm.DB
+}
+
+func NewUserTokenRepositoryImpl(client *gorm.DB) UserTokenRepository {
+	return &UserTokenRepositoryImpl{Client: client}
+}
+
+// Create implements UserTokenRepository.
+func (repository *UserTokenRepositoryImpl) Create(userToken *model.UserToken) (*model.UserToken, error) {
+	err := repository.Client.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", userToken.UserId, userToken.Purpose, time.Now()).
+			Update("expires_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(userToken).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Create user token failed: %w", err)
+	}
+
+	return userToken, nil
+}
+
+// Consume implements UserTokenRepository.
+func (repository *UserTokenRepositoryImpl) Consume(purpose model.UserTokenPurpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	// 1 statement, the same link opened twice at once is consumed only once
+	var consumed []model.UserToken
+	err := repository.Client.Model(&consumed).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		UpdateColumn("used_at", now).Error
+	if err != nil {
+		return nil, fmt.Errorf("Consume user token failed: %w", err)
+	}
+	if len(consumed) == 0 {
+		return nil, ErrUserTokenInvalid
+	}
+
+	return &consumed[0], nil
+}
+
+// FindValid implements UserTokenRepository.
+func (repository *UserTokenRepositoryImpl) FindValid(purpose model.UserTokenPurpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	var userToken model.UserToken
+	err := repository.Client.
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		Take(&userToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("FindValid user token failed: %w", err)
+	}
+
+	return &userToken, nil
+}
+
+// FailAttempt implements UserTokenRepository.
+func (repository *UserTokenRepositoryImpl) FailAttempt(userTokenId int, maxAttempts int) error {
+	err := repository.Client.Model(&model.UserToken{}).
+		Where("id = ?", userTokenId).
+		UpdateColumns(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"expires_at": gorm.Expr("CASE WHEN attempts + 1 >= ? THEN ? ELSE expires_at END", maxAttempts, time.Now()),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("FailAttempt user token failed: %w", err)
+	}
+
+	return nil
+}
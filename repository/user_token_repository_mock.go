@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"cashier-api/model"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UserTokenRepositoryMock struct {
+	Mock *mock.Mock
+}
+
+func NewUserTokenRepositoryMock(mock *mock.Mock) UserTokenRepository {
+	return &UserTokenRepositoryMock{Mock: mock}
+}
+
+// Create implements UserTokenRepository.
+func (repository *UserTokenRepositoryMock) Create(userToken *model.UserToken) (*model.UserToken, error) {
+	args := repository.Mock.Called(userToken)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UserToken), nil
+}
+
+// Consume implements UserTokenRepository.
+func (repository *UserTokenRepositoryMock) Consume(purpose model.UserTokenPurpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	args := repository.Mock.Called(purpose, tokenHash, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UserToken), nil
+}
+
+// FindValid implements UserTokenRepository.
+func (repository *UserTokenRepositoryMock) FindValid(purpose model.UserTokenPurpose, tokenHash string, now time.Time) (*model.UserToken, error) {
+	args := repository.Mock.Called(purpose, tokenHash, now)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.UserToken), nil
+}
+
+// FailAttempt implements UserTokenRepository.
+func (repository *UserTokenRepositoryMock) FailAttempt(userTokenId int, maxAttempts int) error {
+	args := repository.Mock.Called(userTokenId, maxAttempts)
+	return args.Error(0)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"cashier-api/helper/client"
+	"cashier-api/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestUserTokenRepository(t *testing.T) {
+	var gormClient *gorm.DB = client.CreateGormClient()
+
+	t.Run("Consume", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		repo := NewUserTokenRepositoryImpl(tx)
+		now := time.Now()
+
+		_, err := repo.Create(&model.UserToken{UserId: userId, Purpose: model.UserTokenPasswordReset, TokenHash: "reset-1", ExpiresAt: now.Add(time.Hour)})
+		require.NoError(t, err)
+		_, err = repo.Create(&model.UserToken{UserId: userId, Purpose: model.UserTokenPasswordReset, TokenHash: "reset-2", ExpiresAt: now.Add(time.Hour)})
+		require.NoError(t, err)
+
+		// Only the latest link work
+		_, err = repo.Consume(model.UserTokenPasswordReset, "reset-1", now.Add(time.Second))
+		assert.ErrorIs(t, err, ErrUserTokenInvalid)
+
+		// Wrong purpose
+		_, err = repo.Consume(model.UserTokenEmailVerification, "reset-2", now)
+		assert.ErrorIs(t, err, ErrUserTokenInvalid)
+
+		consumed, err := repo.Consume(model.UserTokenPasswordReset, "reset-2", now)
+		require.NoError(t, err)
+		assert.Equal(t, userId, consumed.UserId)
+		assert.NotNil(t, consumed.UsedAt)
+
+		// Single use
+		_, err = repo.Consume(model.UserTokenPasswordReset, "reset-2", now)
+		assert.ErrorIs(t, err, ErrUserTokenInvalid)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		repo := NewUserTokenRepositoryImpl(tx)
+		now := time.Now()
+
+		_, err := repo.Create(&model.UserToken{UserId: userId, Purpose: model.UserTokenEmailVerification, TokenHash: "verify-1", ExpiresAt: now.Add(time.Hour)})
+		require.NoError(t, err)
+
+		_, err = repo.Consume(model.UserTokenEmailVerification, "verify-1", now.Add(time.Hour*2))
+		assert.ErrorIs(t, err, ErrUserTokenInvalid)
+	})
+
+	t.Run("FailAttempt", func(t *testing.T) {
+		tx := gormClient.Begin()
+		defer tx.Rollback()
+
+		tenantId, _ := seedOrderItemTestDependencies(t, tx)
+		userId := tenantOwnerId(t, tx, tenantId)
+		repo := NewUserTokenRepositoryImpl(tx)
+		now := time.Now()
+
+		challenge, err := repo.Create(&model.UserToken{UserId: userId, Purpose: model.UserTokenTwoFactor, TokenHash: "challenge-1", ExpiresAt: now.Add(time.Minute)})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.FailAttempt(challenge.Id, 2))
+		found, err := repo.FindValid(model.UserTokenTwoFactor, "challenge-1", now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, found.Attempts)
+		assert.Nil(t, found.UsedAt, "Not consumed")
+
+		// Max attempts reached, expired
+		require.NoError(t, repo.FailAttempt(challenge.Id, 2))
+		_, err = repo.FindValid(model.UserTokenTwoFactor, "challenge-1", time.Now())
+		assert.ErrorIs(t, err, ErrUserTokenInvalid)
+	})
+}
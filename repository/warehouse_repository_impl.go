@@ -42,7 +42,9 @@ func (warehouse *WarehouseRepositoryImpl) GetActiveItem(tenantId int, limit int,
 	}
 
 	// Get paginated result
+	// Stable order, so every page is the next rows
 	if err := query.
+		Order("item_id ASC").
 		Limit(limit).
 		Offset(offset).
 		Find(&items).Error; err != nil {
@@ -69,7 +71,9 @@ func (warehouse *WarehouseRepositoryImpl) Get(tenantId int, limit int, page int,
 		return nil, 0, err
 	}
 
+	// Stable order, so every page is the next rows
 	if err := query.
+		Order("item_id ASC").
 		Limit(limit).
 		Offset(offset).
 		Find(&items).Error; err != nil {
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"cashier-api/model"
+	"time"
+)
+
+type ApiKeyService interface {
+	/*
+		Create a key of the tenant with the scopes, the key is returned once.
+		expiresAt nil never expire. Owner or manager only
+	*/
+	Create(tenantId int, name string, scopes []model.ApiKeyScope, expiresAt *time.Time, sub int) (*model.ApiKeyCreated, error)
+
+	/*
+		Every key of the tenant, without the key itself
+	*/
+	GetApiKeys(tenantId int) ([]model.ApiKey, error)
+
+	/*
+		Revoke the key. Owner or manager only
+	*/
+	Revoke(tenantId int, apiKeyId int, sub int) error
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeySize = 32
+
+	// Shown part of the key, ApiKeyTokenPrefix included
+	apiKeyPrefixLength = 12
+)
+
+type ApiKeyServiceImpl struct {
+	Repository repository.ApiKeyRepository
+}
+
+func NewApiKeyServiceImpl(repository repository.ApiKeyRepository) ApiKeyService {
+	return &ApiKeyServiceImpl{Repository: repository}
+}
+
+// Create implements ApiKeyService.
+func (service *ApiKeyServiceImpl) Create(tenantId int, name string, scopes []model.ApiKeyScope, expiresAt *time.Time, sub int) (*model.ApiKeyCreated, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("API key name is Required !")
+	} else if len([]rune(name)) > model.SessionDeviceNameMaxLength {
+		return nil, fmt.Errorf("API key name is longer than %d characters", model.SessionDeviceNameMaxLength)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("API key expiry should be in the future")
+	}
+
+	joinedScopes, err := model.JoinApiKeyScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := token.New(apiKeySize)
+	if err != nil {
+		return nil, errors.New("Failed to create token")
+	}
+	key := model.ApiKeyTokenPrefix + secret
+
+	apiKey, err := service.Repository.Create(&model.ApiKey{
+		TenantId:  tenantId,
+		Name:      name,
+		Prefix:    key[:apiKeyPrefixLength],
+		KeyHash:   token.Hash(key),
+		Scopes:    joinedScopes,
+		ExpiresAt: expiresAt,
+	}, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ApiKeyCreated{ApiKey: apiKey, Key: key}, nil
+}
+
+// GetApiKeys implements ApiKeyService.
+func (service *ApiKeyServiceImpl) GetApiKeys(tenantId int) ([]model.ApiKey, error) {
+	return service.Repository.GetByTenant(tenantId)
+}
+
+// Revoke implements ApiKeyService.
+func (service *ApiKeyServiceImpl) Revoke(tenantId int, apiKeyId int, sub int) error {
+	return service.Repository.Revoke(tenantId, apiKeyId, sub)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const USER_ID = 5
+
+	newService := func() (ApiKeyService, *repository.ApiKeyRepositoryMock) {
+		apiKeyRepo := repository.NewApiKeyRepositoryMock(&mock.Mock{}).(*repository.ApiKeyRepositoryMock)
+		return NewApiKeyServiceImpl(apiKeyRepo), apiKeyRepo
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		apiKeyService, apiKeyRepo := newService()
+
+		created := &model.ApiKey{}
+		apiKeyRepo.Mock.On("Create", mock.AnythingOfType("*model.ApiKey"), USER_ID).Run(func(args mock.Arguments) {
+			*created = *args.Get(0).(*model.ApiKey)
+			created.Id = 3
+		}).Return(created, nil).Once()
+
+		apiKey, err := apiKeyService.Create(TENANT_ID, " Online shop ", []model.ApiKeyScope{model.ApiKeyScopeReadStock, model.ApiKeyScopeWriteSales}, nil, USER_ID)
+		require.Nil(t, err)
+		assert.Equal(t, 3, apiKey.Id)
+		assert.Equal(t, "Online shop", apiKey.Name)
+		assert.Equal(t, "read:stock,write:sales", apiKey.Scopes)
+		assert.Nil(t, apiKey.ExpiresAt)
+
+		// Only the hash is stored, the prefix is the start of the key
+		assert.True(t, strings.HasPrefix(apiKey.Key, model.ApiKeyTokenPrefix))
+		assert.True(t, strings.HasPrefix(apiKey.Key, apiKey.Prefix))
+		assert.Equal(t, token.Hash(apiKey.Key), apiKey.KeyHash)
+	})
+
+	t.Run("CreateInvalid", func(t *testing.T) {
+		apiKeyService, apiKeyRepo := newService()
+		past := time.Now().Add(-time.Minute)
+
+		_, err := apiKeyService.Create(TENANT_ID, " ", []model.ApiKeyScope{model.ApiKeyScopeReadSales}, nil, USER_ID)
+		assert.NotNil(t, err)
+		_, err = apiKeyService.Create(TENANT_ID, strings.Repeat("a", model.SessionDeviceNameMaxLength+1), []model.ApiKeyScope{model.ApiKeyScopeReadSales}, nil, USER_ID)
+		assert.NotNil(t, err)
+		_, err = apiKeyService.Create(TENANT_ID, "Shop", []model.ApiKeyScope{"admin"}, nil, USER_ID)
+		assert.NotNil(t, err)
+		_, err = apiKeyService.Create(TENANT_ID, "Shop", []model.ApiKeyScope{model.ApiKeyScopeReadSales}, &past, USER_ID)
+		assert.NotNil(t, err)
+
+		apiKeyRepo.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("CreateAccessDenied", func(t *testing.T) {
+		apiKeyService, apiKeyRepo := newService()
+		apiKeyRepo.Mock.On("Create", mock.AnythingOfType("*model.ApiKey"), USER_ID).Return(nil, repository.ErrApiKeyAccessDenied)
+
+		_, err := apiKeyService.Create(TENANT_ID, "Shop", []model.ApiKeyScope{model.ApiKeyScopeReadSales}, nil, USER_ID)
+		assert.ErrorIs(t, err, repository.ErrApiKeyAccessDenied)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		apiKeyService, apiKeyRepo := newService()
+		apiKeyRepo.Mock.On("Revoke", TENANT_ID, 3, USER_ID).Return(repository.ErrApiKeyNotFound)
+
+		assert.ErrorIs(t, apiKeyService.Revoke(TENANT_ID, 3, USER_ID), repository.ErrApiKeyNotFound)
+	})
+}
@@ -0,0 +1,27 @@
+package service
+
+import "cashier-api/model"
+
+type AuditService interface {
+	/*
+		The entity changed by the routes of the resource (e.g. "stores" for /api/v1/stores/...),
+		nil when the resource changes are not snapshotted
+	*/
+	Entity(resource string) *model.AuditEntity
+
+	/*
+		The row of the entity as JSON, "" when not found or failed (only logged,
+		the request is never refused because of the audit)
+	*/
+	Snapshot(entity *model.AuditEntity, tenantId int, entityId int) model.AuditValue
+
+	/*
+		Append the event to the audit log
+	*/
+	Record(event *model.AuditEvent) error
+
+	/*
+		Search the audit log of the tenant, latest first. Owner only
+	*/
+	Search(tenantId int, filter *model.AuditEventFilter, limit, page int, sub int) ([]*model.AuditEvent, int, error)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+Resources whose changes are snapshotted, by the first path segment after /api/v1.
+
+	Tables holding a secret (token or key hash) should never be added
+*/
+var auditEntities = map[string]*model.AuditEntity{
+	"stores":        {Type: "store", Table: "store", PrimaryKey: "id", IdFields: []string{"store_id"}},
+	"categories":    {Type: "category", Table: "category", PrimaryKey: "id", IdFields: []string{"category_id"}},
+	"warehouses":    {Type: "item", Table: "warehouse", PrimaryKey: "item_id", IdFields: []string{"item_id", "item.item_id"}},
+	"store_stocks":  {Type: "store_stock", Table: "store_stock", PrimaryKey: "id", IdFields: []string{"store_stock_id", "id"}},
+	"customers":     {Type: "customer", Table: "customer", PrimaryKey: "id", IdFields: []string{"customer_id"}},
+	"order_items":   {Type: "order_item", Table: "order_item", PrimaryKey: "id", IdFields: []string{"order_item_id"}},
+	"parked_orders": {Type: "parked_order", Table: "parked_order", PrimaryKey: "id", IdFields: []string{"parked_order_id"}},
+}
+
+type AuditServiceImpl struct {
+	Repository repository.AuditRepository
+}
+
+func NewAuditServiceImpl(repository repository.AuditRepository) AuditService {
+	return &AuditServiceImpl{Repository: repository}
+}
+
+// Entity implements AuditService.
+func (service *AuditServiceImpl) Entity(resource string) *model.AuditEntity {
+	return auditEntities[resource]
+}
+
+// Snapshot implements AuditService.
+func (service *AuditServiceImpl) Snapshot(entity *model.AuditEntity, tenantId int, entityId int) model.AuditValue {
+	value, err := service.Repository.Snapshot(entity, tenantId, entityId)
+	if err != nil {
+		log.Warnf("Could not snapshot %s %d of tenant %d, reason: %s", entity.Type, entityId, tenantId, err.Error())
+		return ""
+	}
+	return value
+}
+
+// Record implements AuditService.
+func (service *AuditServiceImpl) Record(event *model.AuditEvent) error {
+	return service.Repository.Create(event)
+}
+
+// Search implements AuditService.
+func (service *AuditServiceImpl) Search(tenantId int, filter *model.AuditEventFilter, limit, page int, sub int) ([]*model.AuditEvent, int, error) {
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, 0, errors.New("end_date could not be before start_date")
+	}
+
+	return service.Repository.Search(tenantId, filter, limit, page-1, sub)
+}
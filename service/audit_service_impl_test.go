@@ -0,0 +1,63 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const USER_ID = 5
+
+	newService := func() (AuditService, *repository.AuditRepositoryMock) {
+		auditRepo := repository.NewAuditRepositoryMock(&mock.Mock{}).(*repository.AuditRepositoryMock)
+		return NewAuditServiceImpl(auditRepo), auditRepo
+	}
+
+	t.Run("Entity", func(t *testing.T) {
+		auditService, _ := newService()
+		assert.Equal(t, "store", auditService.Entity("stores").Type)
+		assert.Equal(t, "item_id", auditService.Entity("warehouses").PrimaryKey)
+		assert.Nil(t, auditService.Entity("users"))
+		// Holding a token hash
+		assert.Nil(t, auditService.Entity("terminals"))
+		assert.Nil(t, auditService.Entity("api_keys"))
+	})
+
+	t.Run("SnapshotFailed", func(t *testing.T) {
+		auditService, auditRepo := newService()
+		entity := auditService.Entity("stores")
+		auditRepo.Mock.On("Snapshot", entity, TENANT_ID, 3).Return(model.AuditValue(`{"id":3}`), nil)
+		auditRepo.Mock.On("Snapshot", entity, TENANT_ID, 4).Return(model.AuditValue(""), errors.New("connection refused"))
+
+		assert.Equal(t, model.AuditValue(`{"id":3}`), auditService.Snapshot(entity, TENANT_ID, 3))
+		assert.Equal(t, model.AuditValue(""), auditService.Snapshot(entity, TENANT_ID, 4))
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		auditService, auditRepo := newService()
+		filter := &model.AuditEventFilter{}
+		auditRepo.Mock.On("Search", TENANT_ID, filter, 10, 0, USER_ID).Return([]*model.AuditEvent{{Id: 1}}, 1, nil)
+
+		events, count, err := auditService.Search(TENANT_ID, filter, 10, 1, USER_ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+		assert.Len(t, events, 1)
+
+		_, _, err = auditService.Search(TENANT_ID, filter, 0, 1, USER_ID)
+		assert.NotNil(t, err)
+		_, _, err = auditService.Search(TENANT_ID, filter, 10, 0, USER_ID)
+		assert.NotNil(t, err)
+
+		now := time.Now()
+		yesterday := now.Add(-time.Hour * 24)
+		_, _, err = auditService.Search(TENANT_ID, &model.AuditEventFilter{StartDate: &now, EndDate: &yesterday}, 10, 1, USER_ID)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,27 @@
+package service
+
+import "cashier-api/model"
+
+type ClosingReportService interface {
+	/*
+		X-report, the business day of the store so far. It's stored but close nothing
+	*/
+	GenerateXReport(tenantId, storeId, userId int) (*model.ClosingReport, error)
+
+	/*
+		Z-report, close the business day of the store with the next sequential number.
+		Once closed, the stored report is final and the day could not be closed again
+	*/
+	GenerateZReport(tenantId, storeId, userId int) (*model.ClosingReport, error)
+
+	/*
+		Reprint 1 stored report, it's never recomputed
+	*/
+	FindById(closingReportId, tenantId int) (*model.ClosingReport, error)
+
+	/*
+		Stored reports of a store (newest first), reportType = "" return both
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	Get(tenantId, storeId int, reportType model.ClosingReportType, limit, page int) ([]*model.ClosingReport, int, error)
+}
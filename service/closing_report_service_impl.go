@@ -0,0 +1,64 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ClosingReportServiceImpl struct {
+	Repository repository.ClosingReportRepository
+}
+
+func NewClosingReportServiceImpl(repository repository.ClosingReportRepository) ClosingReportService {
+	return &ClosingReportServiceImpl{Repository: repository}
+}
+
+func (service *ClosingReportServiceImpl) generate(tenantId, storeId, userId int, reportType model.ClosingReportType) (*model.ClosingReport, error) {
+	if tenantId <= 0 || storeId <= 0 || userId <= 0 {
+		return nil, errors.New("Tenant id, Store id, User id is Required !")
+	}
+
+	return service.Repository.Generate(tenantId, storeId, userId, reportType, time.Now())
+}
+
+// GenerateXReport implements ClosingReportService.
+func (service *ClosingReportServiceImpl) GenerateXReport(tenantId, storeId, userId int) (*model.ClosingReport, error) {
+	return service.generate(tenantId, storeId, userId, model.ClosingReportTypeX)
+}
+
+// GenerateZReport implements ClosingReportService.
+func (service *ClosingReportServiceImpl) GenerateZReport(tenantId, storeId, userId int) (*model.ClosingReport, error) {
+	return service.generate(tenantId, storeId, userId, model.ClosingReportTypeZ)
+}
+
+// FindById implements ClosingReportService.
+func (service *ClosingReportServiceImpl) FindById(closingReportId, tenantId int) (*model.ClosingReport, error) {
+	if tenantId <= 0 || closingReportId <= 0 {
+		return nil, errors.New("Tenant id or Closing report id Required !")
+	}
+
+	return service.Repository.FindById(closingReportId, tenantId)
+}
+
+// Get implements ClosingReportService.
+func (service *ClosingReportServiceImpl) Get(tenantId, storeId int, reportType model.ClosingReportType, limit, page int) ([]*model.ClosingReport, int, error) {
+	if tenantId <= 0 || storeId <= 0 {
+		return nil, 0, errors.New("Tenant id, Store id is Required !")
+	}
+
+	if reportType != "" && !reportType.IsValid() {
+		return nil, 0, fmt.Errorf("Invalid closing report type: %s. Allowed: X, Z", reportType)
+	}
+
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	return service.Repository.Get(tenantId, storeId, reportType, limit, page-1)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClosingReportServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const STORE_ID = 1
+	const USER_ID = 1
+
+	newService := func() (*repository.ClosingReportRepositoryMock, ClosingReportService) {
+		closingReportRepo := repository.NewClosingReportRepositoryMock(&mock.Mock{}).(*repository.ClosingReportRepositoryMock)
+		return closingReportRepo, NewClosingReportServiceImpl(closingReportRepo)
+	}
+
+	t.Run("GenerateXReport", func(t *testing.T) {
+		closingReportRepo, closingReportService := newService()
+		closingReportRepo.Mock.On("Generate", TENANT_ID, STORE_ID, USER_ID, model.ClosingReportTypeX, mock.AnythingOfType("time.Time")).
+			Return(&model.ClosingReport{Id: 1, Type: model.ClosingReportTypeX}, nil)
+
+		report, err := closingReportService.GenerateXReport(TENANT_ID, STORE_ID, USER_ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ClosingReportTypeX, report.Type)
+		closingReportRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("GenerateZReport", func(t *testing.T) {
+		t.Run("NormalClose", func(t *testing.T) {
+			zNumber := 3
+			closingReportRepo, closingReportService := newService()
+			closingReportRepo.Mock.On("Generate", TENANT_ID, STORE_ID, USER_ID, model.ClosingReportTypeZ, mock.AnythingOfType("time.Time")).
+				Return(&model.ClosingReport{Id: 2, Type: model.ClosingReportTypeZ, ZNumber: &zNumber}, nil)
+
+			report, err := closingReportService.GenerateZReport(TENANT_ID, STORE_ID, USER_ID)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, *report.ZNumber)
+		})
+
+		t.Run("AlreadyClosed", func(t *testing.T) {
+			closingReportRepo, closingReportService := newService()
+			closingReportRepo.Mock.On("Generate", TENANT_ID, STORE_ID, USER_ID, model.ClosingReportTypeZ, mock.AnythingOfType("time.Time")).
+				Return(nil, errors.New("Business day 2026-01-31 of store 1 is already closed by Z-report #3"))
+
+			report, err := closingReportService.GenerateZReport(TENANT_ID, STORE_ID, USER_ID)
+			assert.Error(t, err)
+			assert.Nil(t, report)
+		})
+
+		t.Run("MissingId", func(t *testing.T) {
+			closingReportRepo, closingReportService := newService()
+
+			_, err := closingReportService.GenerateZReport(TENANT_ID, 0, USER_ID)
+			assert.Error(t, err)
+			_, err = closingReportService.GenerateZReport(TENANT_ID, STORE_ID, 0)
+			assert.Error(t, err)
+			closingReportRepo.Mock.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("FindById", func(t *testing.T) {
+		closingReportRepo, closingReportService := newService()
+		closingReportRepo.Mock.On("FindById", 1, TENANT_ID).Return(&model.ClosingReport{Id: 1}, nil)
+
+		report, err := closingReportService.FindById(1, TENANT_ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Id)
+
+		_, err = closingReportService.FindById(0, TENANT_ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			closingReportRepo, closingReportService := newService()
+			closingReportRepo.Mock.On("Get", TENANT_ID, STORE_ID, model.ClosingReportTypeZ, 10, 0).
+				Return([]*model.ClosingReport{{Id: 1}}, 1, nil)
+
+			reports, count, err := closingReportService.Get(TENANT_ID, STORE_ID, model.ClosingReportTypeZ, 10, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.Len(t, reports, 1)
+		})
+
+		t.Run("InvalidParams", func(t *testing.T) {
+			closingReportRepo, closingReportService := newService()
+
+			_, _, err := closingReportService.Get(TENANT_ID, STORE_ID, "Y", 10, 1)
+			assert.Error(t, err)
+			_, _, err = closingReportService.Get(TENANT_ID, STORE_ID, "", 0, 1)
+			assert.Error(t, err)
+			_, _, err = closingReportService.Get(TENANT_ID, STORE_ID, "", 10, 0)
+			assert.Error(t, err)
+			_, _, err = closingReportService.Get(TENANT_ID, 0, "", 10, 1)
+			assert.Error(t, err)
+			closingReportRepo.Mock.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package service
+
+import "cashier-api/model"
+
+type CustomerService interface {
+	/*
+		Get the list of customer, search by phone number
+		2nd params return is the count of all data
+	*/
+	Get(tenantId, limit, page int, phoneQuery string) ([]*model.Customer, int, error)
+
+	/*
+		Create new customer, phone will be normalized before stored
+	*/
+	Create(customer *model.Customer) (*model.Customer, error)
+
+	/*
+		Edit customer properties (name, phone, email, notes)
+	*/
+	Edit(customer *model.Customer) (*model.Customer, error)
+
+	/*
+		Customer with lifetime spend, visit count and paginated invoices.
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	GetDetail(customerId, tenantId, limit, page int) (*model.CustomerDetail, error)
+}
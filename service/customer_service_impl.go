@@ -0,0 +1,169 @@
+package service
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type CustomerServiceImpl struct {
+	Repository          repository.CustomerRepository
+	OrderItemRepository repository.OrderItemRepository
+	NameRegexRule       *regexp.Regexp
+	PhoneRegexRule      *regexp.Regexp
+	EmailRegexRule      *regexp.Regexp
+}
+
+func NewCustomerServiceImpl(repository repository.CustomerRepository, orderItemRepository repository.OrderItemRepository) CustomerService {
+	return &CustomerServiceImpl{
+		Repository:          repository,
+		OrderItemRepository: orderItemRepository,
+		NameRegexRule:       regexp.MustCompile(`^[\p{L}][\p{L}0-9'. ]{0,99}$`),
+		PhoneRegexRule:      regexp.MustCompile(`^\+?[0-9]{6,15}$`),
+		EmailRegexRule:      regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`),
+	}
+}
+
+/*
+Cashier may type "0812-3456 7890" or "(0812) 34567890",
+both should be stored and searched as "081234567890"
+*/
+func normalizePhone(phone string) string {
+	replacer := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+	return replacer.Replace(strings.TrimSpace(phone))
+}
+
+func (service *CustomerServiceImpl) validate(customer *model.Customer) error {
+	if customer.TenantId < 1 {
+		return errors.New("Invalid tenant id")
+	}
+
+	customer.Name = strings.TrimSpace(customer.Name)
+	if !service.NameRegexRule.MatchString(customer.Name) {
+		return fmt.Errorf("Current customer name is not allowed: %s", customer.Name)
+	}
+
+	customer.Phone = normalizePhone(customer.Phone)
+	if !service.PhoneRegexRule.MatchString(customer.Phone) {
+		return fmt.Errorf("Invalid phone number: %s", customer.Phone)
+	}
+
+	// Email is optional
+	customer.Email = strings.TrimSpace(customer.Email)
+	if customer.Email != "" && !service.EmailRegexRule.MatchString(customer.Email) {
+		return fmt.Errorf("Invalid email: %s", customer.Email)
+	}
+
+	if len(customer.Notes) > 500 {
+		return errors.New("Notes could not be more than 500 characters")
+	}
+
+	return nil
+}
+
+// Get implements CustomerService.
+func (service *CustomerServiceImpl) Get(tenantId, limit, page int, phoneQuery string) ([]*model.Customer, int, error) {
+	if tenantId < 1 {
+		return nil, 0, errors.New("Invalid tenant id")
+	}
+
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	customers, count, err := service.Repository.Get(tenantId, limit, page-1, normalizePhone(phoneQuery))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return customers, count, nil
+}
+
+// Create implements CustomerService.
+func (service *CustomerServiceImpl) Create(customer *model.Customer) (*model.Customer, error) {
+	if customer.Id != 0 {
+		return nil, fmt.Errorf("Data type error. customer Id should not be inserted. Specified customer id: %d", customer.Id)
+	}
+
+	if err := service.validate(customer); err != nil {
+		return nil, err
+	}
+
+	createdCustomer, err := service.Repository.Create(customer)
+	if err != nil {
+		if strings.Contains(err.Error(), "(23505)") {
+			return nil, errors.New("Current phone number already registered / duplicate phone")
+		}
+
+		return nil, err
+	}
+
+	return createdCustomer, nil
+}
+
+// Edit implements CustomerService.
+func (service *CustomerServiceImpl) Edit(customer *model.Customer) (*model.Customer, error) {
+	if customer.Id < 1 {
+		return nil, errors.New("Invalid customer id")
+	}
+
+	if err := service.validate(customer); err != nil {
+		return nil, err
+	}
+
+	editedCustomer, err := service.Repository.Edit(customer)
+	if err != nil {
+		if strings.Contains(err.Error(), "(23505)") {
+			return nil, errors.New("Current phone number already registered / duplicate phone")
+		}
+
+		return nil, err
+	}
+
+	return editedCustomer, nil
+}
+
+// GetDetail implements CustomerService.
+func (service *CustomerServiceImpl) GetDetail(customerId, tenantId, limit, page int) (*model.CustomerDetail, error) {
+	if customerId < 1 || tenantId < 1 {
+		return nil, errors.New("Tenant id or Customer id Required !")
+	}
+
+	if limit < 1 {
+		return nil, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	customer, err := service.Repository.FindById(customerId, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := service.Repository.GetStats(customerId, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Latest invoice first, storeId = 0 means from all store
+	filters := []*query.QueryFilter{{Column: query.CreatedAtColumn, Ascending: false}}
+	invoices, count, err := service.OrderItemRepository.Get(tenantId, 0, customerId, limit, page-1, filters, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CustomerDetail{
+		Customer:   customer,
+		Stats:      stats,
+		Invoices:   invoices,
+		TotalCount: count,
+	}, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCustomerServiceImpl(t *testing.T) {
+	now := time.Now()
+	const TENANT_ID = 1
+	const LIMIT = 10
+	const PAGE = 1
+
+	newService := func() (*repository.CustomerRepositoryMock, *repository.OrderItemRepositoryMock, CustomerService) {
+		customerRepo := repository.NewCustomerRepositoryMock(&mock.Mock{}).(*repository.CustomerRepositoryMock)
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		return customerRepo, orderItemRepo, NewCustomerServiceImpl(customerRepo, orderItemRepo)
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			customerRepo, _, customerService := newService()
+			expectedCustomers := []*model.Customer{
+				{Id: 1, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID, CreatedAt: &now},
+			}
+
+			// page - 1 and normalized phone query are expected to reach repository
+			customerRepo.Mock.On("Get", TENANT_ID, LIMIT, PAGE-1, "0812345").Return(expectedCustomers, 1, nil)
+
+			customers, count, err := customerService.Get(TENANT_ID, LIMIT, PAGE, "0812-345")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.Equal(t, expectedCustomers, customers)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			_, _, customerService := newService()
+
+			customers, count, err := customerService.Get(0, LIMIT, PAGE, "")
+			assert.Error(t, err)
+			assert.Nil(t, customers)
+			assert.Equal(t, 0, count)
+
+			customers, count, err = customerService.Get(TENANT_ID, 0, PAGE, "")
+			assert.Error(t, err)
+			assert.Nil(t, customers)
+			assert.Equal(t, 0, count)
+
+			customers, count, err = customerService.Get(TENANT_ID, LIMIT, 0, "")
+			assert.Error(t, err)
+			assert.Nil(t, customers)
+			assert.Equal(t, 0, count)
+		})
+
+		t.Run("RepositoryError", func(t *testing.T) {
+			customerRepo, _, customerService := newService()
+			customerRepo.Mock.On("Get", TENANT_ID, LIMIT, PAGE-1, "").Return(nil, 0, errors.New("database error"))
+
+			customers, count, err := customerService.Get(TENANT_ID, LIMIT, PAGE, "")
+			assert.Error(t, err)
+			assert.Nil(t, customers)
+			assert.Equal(t, 0, count)
+		})
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("NormalCreate", func(t *testing.T) {
+			customerRepo, _, customerService := newService()
+			customerRepo.Mock.On("Create", mock.MatchedBy(func(c *model.Customer) bool {
+				// Phone is normalized before reaching repository
+				return c.Phone == "+6281234567890" && c.Name == "Budi Santoso" && c.TenantId == TENANT_ID
+			})).Return(&model.Customer{Id: 1, Name: "Budi Santoso", Phone: "+6281234567890", TenantId: TENANT_ID}, nil)
+
+			createdCustomer, err := customerService.Create(&model.Customer{
+				Name:     " Budi Santoso ",
+				Phone:    "+62 812-3456-7890",
+				Email:    "budi@example.com",
+				TenantId: TENANT_ID,
+			})
+			assert.NoError(t, err)
+			assert.NotNil(t, createdCustomer)
+			assert.Equal(t, 1, createdCustomer.Id)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			_, _, customerService := newService()
+
+			// Invalid tenant
+			createdCustomer, err := customerService.Create(&model.Customer{Name: "Budi", Phone: "081234567890"})
+			assert.Error(t, err)
+			assert.Nil(t, createdCustomer)
+
+			// Invalid name
+			createdCustomer, err = customerService.Create(&model.Customer{Name: "", Phone: "081234567890", TenantId: TENANT_ID})
+			assert.Error(t, err)
+			assert.Nil(t, createdCustomer)
+
+			// Invalid phone
+			createdCustomer, err = customerService.Create(&model.Customer{Name: "Budi", Phone: "12ab", TenantId: TENANT_ID})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "Invalid phone number")
+			assert.Nil(t, createdCustomer)
+
+			// Invalid email
+			createdCustomer, err = customerService.Create(&model.Customer{Name: "Budi", Phone: "081234567890", Email: "budi.example.com", TenantId: TENANT_ID})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "Invalid email")
+			assert.Nil(t, createdCustomer)
+
+			// Id should not be inserted
+			createdCustomer, err = customerService.Create(&model.Customer{Id: 1, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID})
+			assert.Error(t, err)
+			assert.Nil(t, createdCustomer)
+		})
+
+		t.Run("DuplicatePhone", func(t *testing.T) {
+			customerRepo, _, customerService := newService()
+			customerRepo.Mock.On("Create", mock.Anything).
+				Return(nil, errors.New(`ERROR: duplicate key value violates unique constraint "customer_tenant_id_phone_key" (SQLSTATE 23505) (23505)`))
+
+			createdCustomer, err := customerService.Create(&model.Customer{Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID})
+			assert.Error(t, err)
+			assert.Equal(t, "Current phone number already registered / duplicate phone", err.Error())
+			assert.Nil(t, createdCustomer)
+		})
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		t.Run("NormalEdit", func(t *testing.T) {
+			customerRepo, _, customerService := newService()
+			expectedCustomer := &model.Customer{Id: 1, Name: "Budi", Phone: "081234567890", Notes: "VIP", TenantId: TENANT_ID}
+			customerRepo.Mock.On("Edit", mock.AnythingOfType("*model.Customer")).Return(expectedCustomer, nil)
+
+			editedCustomer, err := customerService.Edit(&model.Customer{Id: 1, Name: "Budi", Phone: "0812 3456 7890", Notes: "VIP", TenantId: TENANT_ID})
+			assert.NoError(t, err)
+			assert.Equal(t, expectedCustomer, editedCustomer)
+		})
+
+		t.Run("InvalidCustomerId", func(t *testing.T) {
+			_, _, customerService := newService()
+
+			editedCustomer, err := customerService.Edit(&model.Customer{Id: 0, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID})
+			assert.Error(t, err)
+			assert.Nil(t, editedCustomer)
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			customerRepo, _, customerService := newService()
+			customerRepo.Mock.On("Edit", mock.Anything).Return(nil, errors.New("No customer found with tenant_id=1 and id=99"))
+
+			editedCustomer, err := customerService.Edit(&model.Customer{Id: 99, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID})
+			assert.Error(t, err)
+			assert.Nil(t, editedCustomer)
+		})
+	})
+
+	t.Run("GetDetail", func(t *testing.T) {
+		t.Run("NormalGetDetail", func(t *testing.T) {
+			customerRepo, orderItemRepo, customerService := newService()
+			customerId := 7
+			expectedCustomer := &model.Customer{Id: customerId, Name: "Budi", Phone: "081234567890", TenantId: TENANT_ID}
+			expectedStats := &model.CustomerStats{LifetimeSpend: 150000, VisitCount: 2, LastVisitAt: &now}
+			expectedInvoices := []*model.OrderItem{
+				{Id: 2, TotalAmount: 100000, CustomerId: &customerId, TenantId: TENANT_ID},
+				{Id: 1, TotalAmount: 50000, CustomerId: &customerId, TenantId: TENANT_ID},
+			}
+
+			customerRepo.Mock.On("FindById", customerId, TENANT_ID).Return(expectedCustomer, nil)
+			customerRepo.Mock.On("GetStats", customerId, TENANT_ID).Return(expectedStats, nil)
+			orderItemRepo.Mock.On("Get", TENANT_ID, 0, customerId, LIMIT, PAGE-1,
+				[]*query.QueryFilter{{Column: query.CreatedAtColumn, Ascending: false}}, (*query.DateFilter)(nil)).
+				Return(expectedInvoices, 2, nil)
+
+			detail, err := customerService.GetDetail(customerId, TENANT_ID, LIMIT, PAGE)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedCustomer, detail.Customer)
+			assert.Equal(t, 150000, detail.Stats.LifetimeSpend)
+			assert.Equal(t, 2, detail.Stats.VisitCount)
+			assert.Len(t, detail.Invoices, 2)
+			assert.Equal(t, 2, detail.TotalCount)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			_, _, customerService := newService()
+
+			detail, err := customerService.GetDetail(0, TENANT_ID, LIMIT, PAGE)
+			assert.Error(t, err)
+			assert.Nil(t, detail)
+
+			detail, err = customerService.GetDetail(1, 0, LIMIT, PAGE)
+			assert.Error(t, err)
+			assert.Nil(t, detail)
+
+			detail, err = customerService.GetDetail(1, TENANT_ID, 0, PAGE)
+			assert.Error(t, err)
+			assert.Nil(t, detail)
+
+			detail, err = customerService.GetDetail(1, TENANT_ID, LIMIT, 0)
+			assert.Error(t, err)
+			assert.Nil(t, detail)
+		})
+
+		t.Run("CustomerNotFound", func(t *testing.T) {
+			customerRepo, _, customerService := newService()
+			customerRepo.Mock.On("FindById", 99, TENANT_ID).Return(nil, errors.New("No customer found with tenant_id=1 and id=99"))
+
+			detail, err := customerService.GetDetail(99, TENANT_ID, LIMIT, PAGE)
+			assert.Error(t, err)
+			assert.Nil(t, detail)
+		})
+	})
+}
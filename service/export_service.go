@@ -0,0 +1,61 @@
+package service
+
+import (
+	"cashier-api/helper/export"
+	"cashier-api/helper/query"
+	"io"
+)
+
+type ExportResource string
+
+const (
+	ExportOrderItems      ExportResource = "order_items"
+	ExportStoreStocks     ExportResource = "store_stocks" // store_id is required
+	ExportWarehouseItems  ExportResource = "warehouse_items"
+	ExportCategories      ExportResource = "categories"
+	ExportMembers         ExportResource = "members"
+	ExportSalesReport     ExportResource = "sales_report"
+	ExportProfit          ExportResource = "profit"
+	ExportCategoryReport  ExportResource = "category_report"
+	ExportStoreComparison ExportResource = "store_comparison"
+	ExportInventoryValue  ExportResource = "inventory_valuation" // current stock, a past end_date is refused
+)
+
+func (resource ExportResource) IsValid() bool {
+	switch resource {
+	case ExportOrderItems, ExportStoreStocks, ExportWarehouseItems, ExportCategories, ExportMembers,
+		ExportSalesReport, ExportProfit, ExportCategoryReport, ExportStoreComparison, ExportInventoryValue:
+		return true
+	}
+	return false
+}
+
+type ExportRequest struct {
+	TenantId   int
+	StoreId    int // 0 means all store, except store_stocks
+	Resource   ExportResource
+	Format     export.Format
+	DateFilter *query.DateFilter // only for order items and reports, allowed to nil
+}
+
+/*
+Export is a checked request, rows are only read while Write is running
+*/
+type Export struct {
+	FileName    string
+	ContentType string
+	write       func(w io.Writer) error
+}
+
+// Write stream the file into w, page by page
+func (exp *Export) Write(w io.Writer) error {
+	return exp.write(w)
+}
+
+type ExportService interface {
+	/*
+		Check the request and resolve the title block (tenant / store name),
+		so the error could still be returned before the response is started
+	*/
+	Prepare(request *ExportRequest) (*Export, error)
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"cashier-api/helper/export"
+	"cashier-api/helper/query"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Rows are read from the database by this size, never the whole list at once
+const exportBatchSize = 500
+
+type ExportServiceImpl struct {
+	OrderItemService     OrderItemService
+	ReportService        ReportService
+	OrderItemRepository  repository.OrderItemRepository
+	StoreStockRepository repository.StoreStockRepository
+	WarehouseRepository  repository.WarehouseRepository
+	CategoryRepository   repository.CategoryRepository
+	TenantRepository     repository.TenantRepository
+}
+
+func NewExportServiceImpl(
+	orderItemService OrderItemService,
+	reportService ReportService,
+	orderItemRepository repository.OrderItemRepository,
+	storeStockRepository repository.StoreStockRepository,
+	warehouseRepository repository.WarehouseRepository,
+	categoryRepository repository.CategoryRepository,
+	tenantRepository repository.TenantRepository,
+) ExportService {
+	return &ExportServiceImpl{
+		OrderItemService:     orderItemService,
+		ReportService:        reportService,
+		OrderItemRepository:  orderItemRepository,
+		StoreStockRepository: storeStockRepository,
+		WarehouseRepository:  warehouseRepository,
+		CategoryRepository:   categoryRepository,
+		TenantRepository:     tenantRepository,
+	}
+}
+
+/*
+exportSource is the title, columns and rows of 1 resource,
+rows write every row into the writer
+*/
+type exportSource struct {
+	title   string
+	columns []export.Column
+	rows    func(writer export.Writer) error
+}
+
+/*
+paginate call fetch with page 0, 1, 2... until every row is read,
+fetch return how many rows it got and the count of all data
+*/
+func paginate(fetch func(limit, page int) (fetched int, count int, err error)) error {
+	for page := 0; ; page++ {
+		fetched, count, err := fetch(exportBatchSize, page)
+		if err != nil {
+			return err
+		}
+		if fetched < exportBatchSize || (page+1)*exportBatchSize >= count {
+			return nil
+		}
+	}
+}
+
+// Prepare implements ExportService.
+func (service *ExportServiceImpl) Prepare(request *ExportRequest) (*Export, error) {
+	if !request.Format.IsValid() {
+		return nil, fmt.Errorf("Invalid export format: %s. Allowed: csv, xlsx, pdf", request.Format)
+	}
+	if err := validateReportFilter(request.TenantId, request.StoreId, 0, request.DateFilter); err != nil {
+		return nil, err
+	}
+
+	source, err := service.source(request)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantName, storeName, err := service.OrderItemRepository.GetTenantAndStoreName(request.TenantId, request.StoreId)
+	if err != nil {
+		return nil, err
+	}
+
+	period, err := service.period(request.TenantId, request.DateFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	title := export.Title{
+		Name:        source.title,
+		Tenant:      tenantName,
+		Store:       storeName,
+		Period:      period,
+		GeneratedAt: time.Now(),
+	}
+	format := request.Format
+
+	return &Export{
+		FileName:    format.FileName(string(request.Resource)),
+		ContentType: format.ContentType(),
+		write: func(w io.Writer) error {
+			writer, err := export.NewWriter(w, format, title, source.columns)
+			if err != nil {
+				return err
+			}
+			if err := source.rows(writer); err != nil {
+				writer.Close()
+				return err
+			}
+			return writer.Close()
+		},
+	}, nil
+}
+
+// period print the date filter at the tenant timezone, "" when there is no filter
+func (service *ExportServiceImpl) period(tenantId int, dateFilter *query.DateFilter) (string, error) {
+	if dateFilter == nil || (dateFilter.StartDate == nil && dateFilter.EndDate == nil) {
+		return "", nil
+	}
+
+	timezone, err := service.OrderItemRepository.GetTenantTimezone(tenantId)
+	if err != nil {
+		return "", err
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	format := func(unix *int64) string {
+		if unix == nil {
+			return "..."
+		}
+		return time.Unix(*unix, 0).In(location).Format("2006-01-02 15:04")
+	}
+	return fmt.Sprintf("%s - %s (%s)", format(dateFilter.StartDate), format(dateFilter.EndDate), location), nil
+}
+
+func (service *ExportServiceImpl) source(request *ExportRequest) (*exportSource, error) {
+	tenantId, storeId, dateFilter := request.TenantId, request.StoreId, request.DateFilter
+
+	switch request.Resource {
+	case ExportOrderItems:
+		return &exportSource{
+			title: "Order Items",
+			columns: []export.Column{
+				{Header: "Invoice #", Type: export.ColumnInteger},
+				{Header: "Created At", Type: export.ColumnDateTime},
+				{Header: "Store Id", Type: export.ColumnInteger},
+				{Header: "Status", Type: export.ColumnText, Width: 16},
+				{Header: "Customer Id", Type: export.ColumnInteger},
+				{Header: "Qty", Type: export.ColumnInteger},
+				{Header: "Subtotal (Rp)", Type: export.ColumnCurrency},
+				{Header: "Discount (Rp)", Type: export.ColumnCurrency},
+				{Header: "Total (Rp)", Type: export.ColumnCurrency},
+				{Header: "Paid (Rp)", Type: export.ColumnCurrency},
+				{Header: "Gift Card (Rp)", Type: export.ColumnCurrency},
+			},
+			rows: func(writer export.Writer) error {
+				return paginate(func(limit, page int) (int, int, error) {
+					orderItems, count, err := service.OrderItemRepository.Get(tenantId, storeId, 0, limit, page, nil, dateFilter)
+					if err != nil {
+						return 0, 0, err
+					}
+					for _, orderItem := range orderItems {
+						err := writer.WriteRow(
+							orderItem.Id, orderItem.CreatedAt, orderItem.StoreId, string(orderItem.Status), orderItem.CustomerId,
+							orderItem.TotalQuantity, orderItem.Subtotal, orderItem.DiscountAmount, orderItem.TotalAmount,
+							orderItem.PurchasedPrice, orderItem.GiftCardAmount,
+						)
+						if err != nil {
+							return 0, 0, err
+						}
+					}
+					return len(orderItems), count, nil
+				})
+			},
+		}, nil
+
+	case ExportStoreStocks:
+		if storeId <= 0 {
+			return nil, errors.New("Store id is Required to export store stocks !")
+		}
+		return &exportSource{
+			title: "Store Stocks",
+			columns: []export.Column{
+				{Header: "Item Id", Type: export.ColumnInteger},
+				{Header: "Item Name", Type: export.ColumnText},
+				{Header: "Category", Type: export.ColumnText, Width: 20},
+				{Header: "Stock Type", Type: export.ColumnText, Width: 14},
+				{Header: "Stocks", Type: export.ColumnInteger},
+				{Header: "Base Price (Rp)", Type: export.ColumnCurrency},
+				{Header: "Price (Rp)", Type: export.ColumnCurrency},
+				{Header: "Active", Type: export.ColumnText, Width: 8},
+			},
+			rows: func(writer export.Writer) error {
+				return paginate(func(limit, page int) (int, int, error) {
+					storeStocks, count, err := service.StoreStockRepository.GetV2(tenantId, storeId, limit, page, "", 0, nil)
+					if err != nil {
+						return 0, 0, err
+					}
+					for _, storeStock := range storeStocks {
+						err := writer.WriteRow(
+							storeStock.ItemId, storeStock.ItemName, storeStock.CategoryName, string(storeStock.StockType),
+							storeStock.Stocks, storeStock.BasePrice, storeStock.Price, storeStock.IsActive,
+						)
+						if err != nil {
+							return 0, 0, err
+						}
+					}
+					return len(storeStocks), count, nil
+				})
+			},
+		}, nil
+
+	case ExportWarehouseItems:
+		return &exportSource{
+			title: "Warehouse Items",
+			columns: []export.Column{
+				{Header: "Item Id", Type: export.ColumnInteger},
+				{Header: "Item Name", Type: export.ColumnText},
+				{Header: "Stock Type", Type: export.ColumnText, Width: 14},
+				{Header: "Stocks", Type: export.ColumnInteger},
+				{Header: "Base Price (Rp)", Type: export.ColumnCurrency},
+				{Header: "Active", Type: export.ColumnText, Width: 8},
+				{Header: "Created At", Type: export.ColumnDateTime},
+			},
+			rows: func(writer export.Writer) error {
+				return paginate(func(limit, page int) (int, int, error) {
+					items, count, err := service.WarehouseRepository.Get(tenantId, limit, page, "")
+					if err != nil {
+						return 0, 0, err
+					}
+					for _, item := range items {
+						err := writer.WriteRow(
+							item.ItemId, item.ItemName, string(item.StockType), item.Stocks, item.BasePrice, item.IsActive, item.CreatedAt,
+						)
+						if err != nil {
+							return 0, 0, err
+						}
+					}
+					return len(items), count, nil
+				})
+			},
+		}, nil
+
+	case ExportCategories:
+		return &exportSource{
+			title: "Categories",
+			columns: []export.Column{
+				{Header: "Category Id", Type: export.ColumnInteger},
+				{Header: "Category Name", Type: export.ColumnText},
+				{Header: "Created At", Type: export.ColumnDateTime},
+			},
+			rows: func(writer export.Writer) error {
+				return paginate(func(limit, page int) (int, int, error) {
+					categories, count, err := service.CategoryRepository.Get(tenantId, page, limit, "")
+					if err != nil {
+						return 0, 0, err
+					}
+					for _, category := range categories {
+						if err := writer.WriteRow(category.Id, category.CategoryName, category.CreatedAt); err != nil {
+							return 0, 0, err
+						}
+					}
+					return len(categories), count, nil
+				})
+			},
+		}, nil
+
+	case ExportMembers:
+		return &exportSource{
+			title: "Members",
+			columns: []export.Column{
+				{Header: "User Id", Type: export.ColumnInteger},
+				{Header: "Name", Type: export.ColumnText},
+				{Header: "Email", Type: export.ColumnText},
+				{Header: "Registered At", Type: export.ColumnDateTime},
+			},
+			rows: func(writer export.Writer) error {
+				// Member of a tenant is a short list, not paginated
+				users, err := service.TenantRepository.GetTenantMembers(tenantId)
+				if err != nil {
+					return err
+				}
+				for _, user := range users {
+					if err := writer.WriteRow(user.Id, user.Name, user.Email, user.CreatedAt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, nil
+
+	case ExportSalesReport:
+		return &exportSource{
+			title: "Sales Report",
+			columns: []export.Column{
+				{Header: "Transactions", Type: export.ColumnInteger},
+				{Header: "Qty Sold", Type: export.ColumnInteger},
+				{Header: "Subtotal (Rp)", Type: export.ColumnCurrency},
+				{Header: "Discount (Rp)", Type: export.ColumnCurrency},
+				{Header: "Total (Rp)", Type: export.ColumnCurrency},
+				{Header: "Paid (Rp)", Type: export.ColumnCurrency},
+				{Header: "Profit (Rp)", Type: export.ColumnCurrency},
+			},
+			rows: func(writer export.Writer) error {
+				report, err := service.OrderItemService.GetSalesReport(tenantId, storeId, dateFilter)
+				if err != nil {
+					return err
+				}
+				return writer.WriteRow(
+					report.SumTransactions, report.SumTotalQuantity, report.SumSubtotal, report.SumDiscountAmount,
+					report.SumTotalAmount, report.SumPurchasedPrice, report.SumProfit,
+				)
+			},
+		}, nil
+
+	case ExportProfit:
+		return &exportSource{
+			title: "Profit Per Item",
+			columns: []export.Column{
+				{Header: "Item Id", Type: export.ColumnInteger},
+				{Header: "Item Name", Type: export.ColumnText},
+				{Header: "Qty Sold", Type: export.ColumnInteger},
+				{Header: "Revenue (Rp)", Type: export.ColumnCurrency},
+				{Header: "COGS (Rp)", Type: export.ColumnCurrency},
+				{Header: "Discount (Rp)", Type: export.ColumnCurrency},
+				{Header: "Profit (Rp)", Type: export.ColumnCurrency},
+				{Header: "Margin (%)", Type: export.ColumnPercent},
+			},
+			rows: func(writer export.Writer) error {
+				rows, err := service.OrderItemRepository.GetProfitReport(tenantId, storeId, dateFilter)
+				if err != nil {
+					return err
+				}
+				for _, row := range rows {
+					err := writer.WriteRow(
+						row.ItemId, row.ItemName, row.TotalQuantity, row.TotalRevenue, row.TotalCogs, row.TotalDiscount,
+						row.TotalProfit, percentOf(row.TotalProfit, row.TotalRevenue),
+					)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, nil
+
+	case ExportCategoryReport:
+		return &exportSource{
+			title: "Sales Per Category",
+			columns: []export.Column{
+				{Header: "Category Id", Type: export.ColumnInteger},
+				{Header: "Category", Type: export.ColumnText},
+				{Header: "Qty Sold", Type: export.ColumnInteger},
+				{Header: "Revenue (Rp)", Type: export.ColumnCurrency},
+				{Header: "Discount (Rp)", Type: export.ColumnCurrency},
+				{Header: "Profit (Rp)", Type: export.ColumnCurrency},
+				{Header: "Revenue Share (%)", Type: export.ColumnPercent, Width: 18},
+			},
+			rows: func(writer export.Writer) error {
+				rows, err := service.OrderItemService.GetCategoryReport(tenantId, storeId, dateFilter)
+				if err != nil {
+					return err
+				}
+				for _, row := range rows {
+					err := writer.WriteRow(
+						row.CategoryId, row.CategoryName, row.TotalQuantity, row.TotalRevenue, row.TotalDiscount, row.TotalProfit, row.RevenueShare,
+					)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, nil
+
+	case ExportStoreComparison:
+		return &exportSource{
+			title: "Store Comparison",
+			columns: []export.Column{
+				{Header: "Store Id", Type: export.ColumnInteger},
+				{Header: "Store", Type: export.ColumnText},
+				{Header: "Transactions", Type: export.ColumnInteger},
+				{Header: "Qty Sold", Type: export.ColumnInteger},
+				{Header: "Revenue (Rp)", Type: export.ColumnCurrency},
+				{Header: "Discount (Rp)", Type: export.ColumnCurrency},
+				{Header: "Profit (Rp)", Type: export.ColumnCurrency},
+				{Header: "Revenue Share (%)", Type: export.ColumnPercent, Width: 18},
+				{Header: "Transaction Share (%)", Type: export.ColumnPercent, Width: 20},
+				{Header: "Profit Share (%)", Type: export.ColumnPercent, Width: 16},
+			},
+			rows: func(writer export.Writer) error {
+				rows, err := service.OrderItemService.GetStoreComparison(tenantId, dateFilter)
+				if err != nil {
+					return err
+				}
+				for _, row := range rows {
+					err := writer.WriteRow(
+						row.StoreId, row.StoreName, row.SumTransactions, row.SumTotalQuantity, row.SumTotalAmount,
+						row.SumDiscountAmount, row.SumProfit, row.RevenueShare, row.TransactionShare, row.ProfitShare,
+					)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}, nil
+
+	case ExportInventoryValue:
+		// Valued at the end of the period, refused before the response is started
+		var asOf *int64
+		if dateFilter != nil {
+			asOf = dateFilter.EndDate
+		}
+		if err := validateValuationAsOf(asOf); err != nil {
+			return nil, err
+		}
+
+		return &exportSource{
+			title: "Inventory Valuation",
+			columns: []export.Column{
+				{Header: "Location", Type: export.ColumnText},
+				{Header: "Category", Type: export.ColumnText},
+				{Header: "Items", Type: export.ColumnInteger},
+				{Header: "Stocks", Type: export.ColumnInteger},
+				{Header: "Cost Value (Rp)", Type: export.ColumnCurrency, Width: 18},
+				{Header: "Retail Value (Rp)", Type: export.ColumnCurrency, Width: 18},
+				{Header: "Potential Profit (Rp)", Type: export.ColumnCurrency, Width: 20},
+			},
+			rows: func(writer export.Writer) error {
+				valuation, err := service.ReportService.GetInventoryValuation(tenantId, storeId, asOf)
+				if err != nil {
+					return err
+				}
+				for _, location := range valuation.Locations {
+					for _, row := range location.Categories {
+						err := writer.WriteRow(
+							row.LocationName, row.CategoryName, row.ItemCount, row.TotalStocks, row.CostValue, row.RetailValue, row.PotentialProfit,
+						)
+						if err != nil {
+							return err
+						}
+					}
+				}
+				return writer.WriteRow(
+					"Total", "", nil, nil, valuation.TotalCostValue, valuation.TotalRetailValue, valuation.TotalPotentialProfit,
+				)
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Invalid export resource: %s", request.Resource)
+}
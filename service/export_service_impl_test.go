@@ -0,0 +1,165 @@
+package service
+
+import (
+	"bytes"
+	"cashier-api/helper/export"
+	"cashier-api/helper/query"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const STORE_ID = 1
+
+	newService := func() (*repository.OrderItemRepositoryMock, *repository.CategoryRepositoryMock, *repository.ReportRepositoryMock, ExportService) {
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		categoryRepo := repository.NewCategoryRepositoryMock(&mock.Mock{}).(*repository.CategoryRepositoryMock)
+		storeStockRepo := repository.NewStoreStockRepositoryMock(&mock.Mock{})
+		tenantRepo := repository.NewTenantRepositoryMock(&mock.Mock{})
+		reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+
+		exportService := NewExportServiceImpl(
+			NewOrderItemServiceImpl(orderItemRepo), NewReportServiceImpl(reportRepo), orderItemRepo, storeStockRepo, nil, categoryRepo, tenantRepo,
+		)
+		return orderItemRepo, categoryRepo, reportRepo, exportService
+	}
+
+	readCSV := func(t *testing.T, exp *Export) [][]string {
+		var buffer bytes.Buffer
+		require.NoError(t, exp.Write(&buffer))
+
+		reader := csv.NewReader(&buffer)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	t.Run("OrderItemsInBatches", func(t *testing.T) {
+		orderItemRepo, _, _, exportService := newService()
+		orderItemRepo.Mock.On("GetTenantAndStoreName", TENANT_ID, 0).Return("Tenant", "All Stores", nil)
+
+		firstBatch := make([]*model.OrderItem, exportBatchSize)
+		now := time.Now()
+		for i := range firstBatch {
+			firstBatch[i] = &model.OrderItem{Id: i + 1, TotalAmount: 10_000, CreatedAt: now}
+		}
+		orderItemRepo.Mock.On("Get", TENANT_ID, 0, 0, exportBatchSize, 0, mock.Anything, mock.Anything).
+			Return(firstBatch, exportBatchSize+1, nil).Once()
+		orderItemRepo.Mock.On("Get", TENANT_ID, 0, 0, exportBatchSize, 1, mock.Anything, mock.Anything).
+			Return([]*model.OrderItem{{Id: exportBatchSize + 1, TotalAmount: 25_000}}, exportBatchSize+1, nil).Once()
+
+		exp, err := exportService.Prepare(&ExportRequest{TenantId: TENANT_ID, Resource: ExportOrderItems, Format: export.FormatCSV})
+		require.NoError(t, err)
+		assert.Equal(t, "order_items.csv", exp.FileName)
+		assert.Equal(t, "text/csv; charset=utf-8", exp.ContentType)
+
+		records := readCSV(t, exp)
+		assert.Equal(t, []string{"Report", "Order Items"}, records[0])
+		assert.Equal(t, []string{"Tenant", "Tenant"}, records[1])
+		last := records[len(records)-1]
+		assert.Equal(t, "501", last[0])
+		assert.Equal(t, "25000", last[8])
+		orderItemRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("Period", func(t *testing.T) {
+		orderItemRepo, categoryRepo, _, exportService := newService()
+		orderItemRepo.Mock.On("GetTenantAndStoreName", TENANT_ID, 0).Return("Tenant", "All Stores", nil)
+		orderItemRepo.Mock.On("GetTenantTimezone", TENANT_ID).Return("Asia/Jakarta", nil)
+		categoryRepo.Mock.On("Get", TENANT_ID, 0, exportBatchSize, "").
+			Return([]*model.Category{{Id: 1, CategoryName: "Drinks"}}, 1, nil)
+
+		startDate := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+		exp, err := exportService.Prepare(&ExportRequest{
+			TenantId:   TENANT_ID,
+			Resource:   ExportCategories,
+			Format:     export.FormatCSV,
+			DateFilter: &query.DateFilter{StartDate: &startDate},
+		})
+		require.NoError(t, err)
+
+		records := readCSV(t, exp)
+		assert.Contains(t, records, []string{"Period", "2026-01-01 07:00 - ... (Asia/Jakarta)"})
+		assert.Equal(t, "Drinks", records[len(records)-1][1])
+	})
+
+	t.Run("InventoryValuationExcel", func(t *testing.T) {
+		orderItemRepo, _, reportRepo, exportService := newService()
+		orderItemRepo.Mock.On("GetTenantAndStoreName", TENANT_ID, 0).Return("Tenant", "All Stores", nil)
+		reportRepo.Mock.On("GetInventoryValuation", TENANT_ID, 0).Return([]*repository.InventoryValuationRow{
+			{StoreId: 0, LocationName: "Warehouse", CategoryName: "Drinks", ItemCount: 1, TotalStocks: 5, CostValue: 5_000},
+			{StoreId: STORE_ID, LocationName: "Store", CategoryName: "Drinks", ItemCount: 1, TotalStocks: 3, CostValue: 3_000, RetailValue: 6_000},
+		}, nil)
+
+		exp, err := exportService.Prepare(&ExportRequest{TenantId: TENANT_ID, Resource: ExportInventoryValue, Format: export.FormatXLSX})
+		require.NoError(t, err)
+		assert.Equal(t, "inventory_valuation.xlsx", exp.FileName)
+
+		var buffer bytes.Buffer
+		require.NoError(t, exp.Write(&buffer))
+		f, err := excelize.OpenReader(&buffer)
+		require.NoError(t, err)
+		defer f.Close()
+
+		rows, err := f.GetRows("Report")
+		require.NoError(t, err)
+		total := rows[len(rows)-1]
+		assert.Equal(t, "Total", total[0])
+		assert.Equal(t, "8,000", total[4])
+		assert.Equal(t, "3,000", total[6])
+	})
+
+	t.Run("InventoryValuationPastDate", func(t *testing.T) {
+		orderItemRepo, _, reportRepo, exportService := newService()
+		orderItemRepo.Mock.On("GetTenantAndStoreName", TENANT_ID, 0).Return("Tenant", "All Stores", nil)
+
+		yesterday := time.Now().AddDate(0, 0, -1).Unix()
+		exp, err := exportService.Prepare(&ExportRequest{
+			TenantId:   TENANT_ID,
+			Resource:   ExportInventoryValue,
+			Format:     export.FormatXLSX,
+			DateFilter: &query.DateFilter{EndDate: &yesterday},
+		})
+		assert.ErrorIs(t, err, ErrValuationAsOfPast)
+		assert.Nil(t, exp)
+		reportRepo.Mock.AssertNotCalled(t, "GetInventoryValuation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("FetchError", func(t *testing.T) {
+		orderItemRepo, categoryRepo, _, exportService := newService()
+		orderItemRepo.Mock.On("GetTenantAndStoreName", TENANT_ID, 0).Return("Tenant", "All Stores", nil)
+		categoryRepo.Mock.On("Get", TENANT_ID, 0, exportBatchSize, "").Return(nil, 0, errors.New("connection lost"))
+
+		exp, err := exportService.Prepare(&ExportRequest{TenantId: TENANT_ID, Resource: ExportCategories, Format: export.FormatXLSX})
+		require.NoError(t, err)
+		assert.Error(t, exp.Write(&bytes.Buffer{}))
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		_, _, _, exportService := newService()
+
+		requests := []*ExportRequest{
+			{TenantId: TENANT_ID, Resource: ExportOrderItems, Format: "docx"},
+			{TenantId: TENANT_ID, Resource: "passwords", Format: export.FormatCSV},
+			{TenantId: TENANT_ID, Resource: ExportStoreStocks, Format: export.FormatCSV},
+			{TenantId: 0, Resource: ExportOrderItems, Format: export.FormatCSV},
+			{TenantId: TENANT_ID, StoreId: -STORE_ID, Resource: ExportOrderItems, Format: export.FormatPDF},
+		}
+		for _, request := range requests {
+			exp, err := exportService.Prepare(request)
+			assert.Error(t, err, request)
+			assert.Nil(t, exp)
+		}
+	})
+}
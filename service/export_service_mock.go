@@ -0,0 +1,21 @@
+package service
+
+import "github.com/stretchr/testify/mock"
+
+type ExportServiceMock struct {
+	Mock *mock.Mock
+}
+
+func NewExportServiceMock(mock *mock.Mock) ExportService {
+	return &ExportServiceMock{Mock: mock}
+}
+
+// Prepare implements ExportService.
+func (service *ExportServiceMock) Prepare(request *ExportRequest) (*Export, error) {
+	args := service.Mock.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*Export), nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import "cashier-api/model"
+
+type GiftCardService interface {
+	/*
+		Issue new INACTIVE gift card, the code is generated when empty
+	*/
+	Issue(giftCard *model.GiftCard) (*model.GiftCard, error)
+
+	/*
+		Sell the gift card at a store, the balance become available
+	*/
+	Activate(code string, tenantId, storeId, userId int) (*model.GiftCard, error)
+
+	/*
+		Balance enquiry by code
+	*/
+	GetBalance(code string, tenantId int) (*model.GiftCard, error)
+
+	/*
+		Issue store credit from a refund, orderItemId is optional
+	*/
+	IssueStoreCredit(storeCredit *model.GiftCard, orderItemId *int, userId int, note string) (*model.GiftCard, error)
+
+	/*
+		Card with paginated movements.
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	GetStatement(code string, tenantId, limit, page int) (*model.GiftCardStatement, error)
+
+	/*
+		Outstanding balance grouped by card type
+	*/
+	GetLiability(tenantId int) ([]*model.GiftCardLiability, error)
+}
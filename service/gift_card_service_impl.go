@@ -0,0 +1,193 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Without 0, O, 1, I to avoid typo when the code is typed by cashier
+const giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+const giftCardCodeLength = 16
+const maxGiftCardBalance = 1_000_000_000
+
+type GiftCardServiceImpl struct {
+	Repository    repository.GiftCardRepository
+	CodeRegexRule *regexp.Regexp
+}
+
+func NewGiftCardServiceImpl(repository repository.GiftCardRepository) GiftCardService {
+	return &GiftCardServiceImpl{
+		Repository:    repository,
+		CodeRegexRule: regexp.MustCompile(`^[A-Z0-9]{8,32}$`),
+	}
+}
+
+/*
+Printed code may be "ABCD-2345-EFGH-6789",
+both stored and searched as "ABCD2345EFGH6789"
+*/
+func normalizeGiftCardCode(code string) string {
+	replacer := strings.NewReplacer(" ", "", "-", "")
+	return strings.ToUpper(replacer.Replace(strings.TrimSpace(code)))
+}
+
+func generateGiftCardCode() (string, error) {
+	code := make([]byte, giftCardCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(giftCardCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = giftCardCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+/*
+createWithCode call create with generated code when the code is empty,
+collision of generated code is retried
+*/
+func (service *GiftCardServiceImpl) createWithCode(giftCard *model.GiftCard, create func() (*model.GiftCard, error)) (*model.GiftCard, error) {
+	generated := giftCard.Code == ""
+
+	giftCard.Code = normalizeGiftCardCode(giftCard.Code)
+	if !generated && !service.CodeRegexRule.MatchString(giftCard.Code) {
+		return nil, fmt.Errorf("Invalid gift card code: %s", giftCard.Code)
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		if generated {
+			code, err := generateGiftCardCode()
+			if err != nil {
+				return nil, err
+			}
+			giftCard.Code = code
+		}
+
+		createdGiftCard, err := create()
+		if err == nil {
+			return createdGiftCard, nil
+		}
+
+		if !strings.Contains(err.Error(), "(23505)") {
+			return nil, err
+		}
+		if !generated {
+			return nil, errors.New("Current gift card code already registered / duplicate code")
+		}
+	}
+
+	return nil, errors.New("Could not generate unique gift card code, please try again")
+}
+
+// Issue implements GiftCardService.
+func (service *GiftCardServiceImpl) Issue(giftCard *model.GiftCard) (*model.GiftCard, error) {
+	if giftCard.Id != 0 {
+		return nil, fmt.Errorf("Data type error. gift card Id should not be inserted. Specified gift card id: %d", giftCard.Id)
+	}
+	if giftCard.TenantId < 1 {
+		return nil, errors.New("Invalid tenant id")
+	}
+	if giftCard.InitialBalance < 1 || giftCard.InitialBalance > maxGiftCardBalance {
+		return nil, fmt.Errorf("Initial balance should be between 1 and %d. Given %d", maxGiftCardBalance, giftCard.InitialBalance)
+	}
+
+	return service.createWithCode(giftCard, func() (*model.GiftCard, error) {
+		return service.Repository.Create(giftCard)
+	})
+}
+
+// Activate implements GiftCardService.
+func (service *GiftCardServiceImpl) Activate(code string, tenantId, storeId, userId int) (*model.GiftCard, error) {
+	if tenantId < 1 || storeId < 1 || userId < 1 {
+		return nil, errors.New("Tenant id, Store id, User id is Required !")
+	}
+
+	giftCard, err := service.Repository.FindByCode(normalizeGiftCardCode(code), tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Repository.Activate(giftCard.Id, tenantId, storeId, userId)
+}
+
+// GetBalance implements GiftCardService.
+func (service *GiftCardServiceImpl) GetBalance(code string, tenantId int) (*model.GiftCard, error) {
+	if tenantId < 1 {
+		return nil, errors.New("Invalid tenant id")
+	}
+
+	code = normalizeGiftCardCode(code)
+	if code == "" {
+		return nil, errors.New("Gift card code is required")
+	}
+
+	return service.Repository.FindByCode(code, tenantId)
+}
+
+// IssueStoreCredit implements GiftCardService.
+func (service *GiftCardServiceImpl) IssueStoreCredit(storeCredit *model.GiftCard, orderItemId *int, userId int, note string) (*model.GiftCard, error) {
+	if storeCredit.TenantId < 1 || userId < 1 {
+		return nil, errors.New("Tenant id, User id is Required !")
+	}
+	if storeCredit.InitialBalance < 1 || storeCredit.InitialBalance > maxGiftCardBalance {
+		return nil, fmt.Errorf("Store credit amount should be between 1 and %d. Given %d", maxGiftCardBalance, storeCredit.InitialBalance)
+	}
+	if storeCredit.CustomerId != nil && *storeCredit.CustomerId < 1 {
+		return nil, fmt.Errorf("Invalid customer id: %d", *storeCredit.CustomerId)
+	}
+	if orderItemId != nil && *orderItemId < 1 {
+		return nil, fmt.Errorf("Invalid order item id: %d", *orderItemId)
+	}
+	if len(note) > 500 {
+		return nil, errors.New("Note could not be more than 500 characters")
+	}
+
+	// Store credit code is always generated
+	storeCredit.Code = ""
+	return service.createWithCode(storeCredit, func() (*model.GiftCard, error) {
+		return service.Repository.IssueStoreCredit(storeCredit, orderItemId, userId, note)
+	})
+}
+
+// GetStatement implements GiftCardService.
+func (service *GiftCardServiceImpl) GetStatement(code string, tenantId, limit, page int) (*model.GiftCardStatement, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	giftCard, err := service.GetBalance(code, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, count, err := service.Repository.GetMovements(giftCard.Id, tenantId, limit, page-1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GiftCardStatement{
+		GiftCard:   giftCard,
+		Movements:  movements,
+		TotalCount: count,
+	}, nil
+}
+
+// GetLiability implements GiftCardService.
+func (service *GiftCardServiceImpl) GetLiability(tenantId int) ([]*model.GiftCardLiability, error) {
+	if tenantId < 1 {
+		return nil, errors.New("Invalid tenant id")
+	}
+
+	return service.Repository.GetLiability(tenantId)
+}
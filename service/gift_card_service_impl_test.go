@@ -0,0 +1,211 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGiftCardServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const STORE_ID = 1
+	const USER_ID = 1
+	const LIMIT = 10
+	const PAGE = 1
+
+	newService := func() (*repository.GiftCardRepositoryMock, GiftCardService) {
+		giftCardRepo := repository.NewGiftCardRepositoryMock(&mock.Mock{}).(*repository.GiftCardRepositoryMock)
+		return giftCardRepo, NewGiftCardServiceImpl(giftCardRepo)
+	}
+
+	t.Run("GenerateGiftCardCode", func(t *testing.T) {
+		code, err := generateGiftCardCode()
+		assert.NoError(t, err)
+		assert.Len(t, code, giftCardCodeLength)
+		assert.NotContains(t, code, "0")
+		assert.NotContains(t, code, "O")
+		assert.Equal(t, code, normalizeGiftCardCode(code))
+	})
+
+	t.Run("Issue", func(t *testing.T) {
+		t.Run("GivenCode", func(t *testing.T) {
+			giftCardRepo, giftCardService := newService()
+			giftCardRepo.Mock.On("Create", mock.MatchedBy(func(giftCard *model.GiftCard) bool {
+				return giftCard.Code == "ABCD2345EFGH6789"
+			})).Return(&model.GiftCard{Id: 1, Code: "ABCD2345EFGH6789", InitialBalance: 100_000}, nil)
+
+			issuedGiftCard, err := giftCardService.Issue(&model.GiftCard{
+				TenantId:       TENANT_ID,
+				Code:           "abcd-2345-efgh-6789",
+				InitialBalance: 100_000,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, issuedGiftCard.Id)
+		})
+
+		t.Run("GeneratedCodeCollision", func(t *testing.T) {
+			giftCardRepo, giftCardService := newService()
+			giftCardRepo.Mock.On("Create", mock.Anything).
+				Return(nil, errors.New("ERROR: duplicate key value violates unique constraint (SQLSTATE 23505) (23505)")).Once()
+			giftCardRepo.Mock.On("Create", mock.Anything).
+				Return(&model.GiftCard{Id: 2}, nil).Once()
+
+			issuedGiftCard, err := giftCardService.Issue(&model.GiftCard{TenantId: TENANT_ID, InitialBalance: 50_000})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, issuedGiftCard.Id)
+			giftCardRepo.Mock.AssertNumberOfCalls(t, "Create", 2)
+		})
+
+		t.Run("DuplicateGivenCode", func(t *testing.T) {
+			giftCardRepo, giftCardService := newService()
+			giftCardRepo.Mock.On("Create", mock.Anything).
+				Return(nil, errors.New("ERROR: duplicate key value violates unique constraint (23505)"))
+
+			issuedGiftCard, err := giftCardService.Issue(&model.GiftCard{TenantId: TENANT_ID, Code: "ABCD2345EFGH6789", InitialBalance: 50_000})
+			assert.Error(t, err)
+			assert.Equal(t, "Current gift card code already registered / duplicate code", err.Error())
+			assert.Nil(t, issuedGiftCard)
+			giftCardRepo.Mock.AssertNumberOfCalls(t, "Create", 1)
+		})
+
+		t.Run("InvalidGiftCard", func(t *testing.T) {
+			_, giftCardService := newService()
+
+			for _, giftCard := range []*model.GiftCard{
+				{Id: 1, TenantId: TENANT_ID, InitialBalance: 1},
+				{TenantId: 0, InitialBalance: 1},
+				{TenantId: TENANT_ID, InitialBalance: 0},
+				{TenantId: TENANT_ID, InitialBalance: maxGiftCardBalance + 1},
+				{TenantId: TENANT_ID, InitialBalance: 1, Code: "SHORT"},
+				{TenantId: TENANT_ID, InitialBalance: 1, Code: "ABCD2345EFGH678!"},
+			} {
+				issuedGiftCard, err := giftCardService.Issue(giftCard)
+				assert.Error(t, err)
+				assert.Nil(t, issuedGiftCard)
+			}
+		})
+	})
+
+	t.Run("Activate", func(t *testing.T) {
+		t.Run("NormalActivate", func(t *testing.T) {
+			giftCardRepo, giftCardService := newService()
+			giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).Return(&model.GiftCard{Id: 1}, nil)
+			giftCardRepo.Mock.On("Activate", 1, TENANT_ID, STORE_ID, USER_ID).
+				Return(&model.GiftCard{Id: 1, Status: model.GiftCardStatusActive, Balance: 100_000}, nil)
+
+			activatedGiftCard, err := giftCardService.Activate("abcd 2345 efgh 6789", TENANT_ID, STORE_ID, USER_ID)
+			assert.NoError(t, err)
+			assert.Equal(t, model.GiftCardStatusActive, activatedGiftCard.Status)
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			giftCardRepo, giftCardService := newService()
+			giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).Return(nil, errors.New("No gift card found"))
+
+			activatedGiftCard, err := giftCardService.Activate("ABCD2345EFGH6789", TENANT_ID, STORE_ID, USER_ID)
+			assert.Error(t, err)
+			assert.Nil(t, activatedGiftCard)
+		})
+
+		t.Run("MissingStore", func(t *testing.T) {
+			_, giftCardService := newService()
+
+			activatedGiftCard, err := giftCardService.Activate("ABCD2345EFGH6789", TENANT_ID, 0, USER_ID)
+			assert.Error(t, err)
+			assert.Nil(t, activatedGiftCard)
+		})
+	})
+
+	t.Run("GetBalance", func(t *testing.T) {
+		giftCardRepo, giftCardService := newService()
+		giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).Return(&model.GiftCard{Id: 1, Balance: 40_000}, nil)
+
+		giftCard, err := giftCardService.GetBalance("ABCD-2345-EFGH-6789", TENANT_ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 40_000, giftCard.Balance)
+
+		giftCard, err = giftCardService.GetBalance("", TENANT_ID)
+		assert.Error(t, err)
+		assert.Nil(t, giftCard)
+	})
+
+	t.Run("IssueStoreCredit", func(t *testing.T) {
+		t.Run("NormalIssue", func(t *testing.T) {
+			giftCardRepo, giftCardService := newService()
+			orderItemId := 10
+			giftCardRepo.Mock.On("IssueStoreCredit", mock.MatchedBy(func(storeCredit *model.GiftCard) bool {
+				// Given code is ignored
+				return len(storeCredit.Code) == giftCardCodeLength && storeCredit.Code != "MYOWNCODE1234"
+			}), &orderItemId, USER_ID, "Damaged item").
+				Return(&model.GiftCard{Id: 1, Type: model.GiftCardTypeStoreCredit, Balance: 25_000}, nil)
+
+			storeCredit, err := giftCardService.IssueStoreCredit(&model.GiftCard{
+				TenantId:       TENANT_ID,
+				Code:           "MYOWNCODE1234",
+				InitialBalance: 25_000,
+			}, &orderItemId, USER_ID, "Damaged item")
+			assert.NoError(t, err)
+			assert.Equal(t, model.GiftCardTypeStoreCredit, storeCredit.Type)
+		})
+
+		t.Run("InvalidStoreCredit", func(t *testing.T) {
+			_, giftCardService := newService()
+			invalidId := 0
+
+			storeCredit, err := giftCardService.IssueStoreCredit(&model.GiftCard{TenantId: TENANT_ID, InitialBalance: 0}, nil, USER_ID, "")
+			assert.Error(t, err)
+			assert.Nil(t, storeCredit)
+
+			storeCredit, err = giftCardService.IssueStoreCredit(&model.GiftCard{TenantId: TENANT_ID, InitialBalance: 1, CustomerId: &invalidId}, nil, USER_ID, "")
+			assert.Error(t, err)
+			assert.Nil(t, storeCredit)
+
+			storeCredit, err = giftCardService.IssueStoreCredit(&model.GiftCard{TenantId: TENANT_ID, InitialBalance: 1}, &invalidId, USER_ID, "")
+			assert.Error(t, err)
+			assert.Nil(t, storeCredit)
+
+			storeCredit, err = giftCardService.IssueStoreCredit(&model.GiftCard{TenantId: TENANT_ID, InitialBalance: 1}, nil, 0, "")
+			assert.Error(t, err)
+			assert.Nil(t, storeCredit)
+		})
+	})
+
+	t.Run("GetStatement", func(t *testing.T) {
+		giftCardRepo, giftCardService := newService()
+		expectedMovements := []*model.GiftCardMovement{
+			{Id: 2, Type: model.GiftCardMovementRedeem, Amount: -60_000, BalanceAfter: 40_000},
+			{Id: 1, Type: model.GiftCardMovementActivate, Amount: 100_000, BalanceAfter: 100_000},
+		}
+		giftCardRepo.Mock.On("FindByCode", "ABCD2345EFGH6789", TENANT_ID).Return(&model.GiftCard{Id: 1, Balance: 40_000}, nil)
+		giftCardRepo.Mock.On("GetMovements", 1, TENANT_ID, LIMIT, PAGE-1).Return(expectedMovements, 2, nil)
+
+		statement, err := giftCardService.GetStatement("ABCD2345EFGH6789", TENANT_ID, LIMIT, PAGE)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, statement.TotalCount)
+		assert.Equal(t, expectedMovements, statement.Movements)
+
+		statement, err = giftCardService.GetStatement("ABCD2345EFGH6789", TENANT_ID, LIMIT, 0)
+		assert.Error(t, err)
+		assert.Nil(t, statement)
+	})
+
+	t.Run("GetLiability", func(t *testing.T) {
+		giftCardRepo, giftCardService := newService()
+		expectedLiabilities := []*model.GiftCardLiability{
+			{Type: model.GiftCardTypeGiftCard, CardCount: 2, OutstandingBalance: 140_000},
+		}
+		giftCardRepo.Mock.On("GetLiability", TENANT_ID).Return(expectedLiabilities, nil)
+
+		liabilities, err := giftCardService.GetLiability(TENANT_ID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedLiabilities, liabilities)
+
+		liabilities, err = giftCardService.GetLiability(0)
+		assert.Error(t, err)
+		assert.Nil(t, liabilities)
+	})
+}
@@ -0,0 +1,26 @@
+package service
+
+import "cashier-api/model"
+
+type LoyaltyService interface {
+	/*
+		Return the tenant loyalty setting (inactive when never configured)
+	*/
+	GetSetting(tenantId int) (*model.LoyaltySetting, error)
+
+	/*
+		Create or replace the tenant loyalty setting
+	*/
+	SaveSetting(setting *model.LoyaltySetting) (*model.LoyaltySetting, error)
+
+	/*
+		Customer points balance with paginated ledger.
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	GetStatement(customerId, tenantId, limit, page int) (*model.LoyaltyStatement, error)
+
+	/*
+		Called periodically by job, return the sum of expired points
+	*/
+	ExpirePoints() (int, error)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type LoyaltyServiceImpl struct {
+	Repository         repository.LoyaltyRepository
+	CustomerRepository repository.CustomerRepository
+}
+
+func NewLoyaltyServiceImpl(repository repository.LoyaltyRepository, customerRepository repository.CustomerRepository) LoyaltyService {
+	return &LoyaltyServiceImpl{
+		Repository:         repository,
+		CustomerRepository: customerRepository,
+	}
+}
+
+// GetSetting implements LoyaltyService.
+func (service *LoyaltyServiceImpl) GetSetting(tenantId int) (*model.LoyaltySetting, error) {
+	if tenantId < 1 {
+		return nil, errors.New("Invalid tenant id")
+	}
+
+	return service.Repository.GetSetting(tenantId)
+}
+
+// SaveSetting implements LoyaltyService.
+func (service *LoyaltyServiceImpl) SaveSetting(setting *model.LoyaltySetting) (*model.LoyaltySetting, error) {
+	if setting.TenantId < 1 {
+		return nil, errors.New("Invalid tenant id")
+	}
+
+	if setting.EarnAmount < 1 {
+		return nil, fmt.Errorf("Earn amount could not less than 1. Given %d", setting.EarnAmount)
+	}
+	if setting.EarnPoints < 0 || setting.RedeemValue < 0 {
+		return nil, errors.New("Earn points and redeem value should never be < 0")
+	}
+
+	switch setting.Rounding {
+	case model.LoyaltyRoundingFloor, model.LoyaltyRoundingRound, model.LoyaltyRoundingCeil:
+	case "":
+		setting.Rounding = model.LoyaltyRoundingFloor
+	default:
+		return nil, fmt.Errorf("Invalid rounding: %s", setting.Rounding)
+	}
+
+	// 0 means never expire, 10 years is more than enough
+	if setting.ExpiryMonths < 0 || setting.ExpiryMonths > 120 {
+		return nil, fmt.Errorf("Expiry months should be between 0 and 120. Given %d", setting.ExpiryMonths)
+	}
+
+	return service.Repository.SaveSetting(setting)
+}
+
+// GetStatement implements LoyaltyService.
+func (service *LoyaltyServiceImpl) GetStatement(customerId, tenantId, limit, page int) (*model.LoyaltyStatement, error) {
+	if customerId < 1 || tenantId < 1 {
+		return nil, errors.New("Tenant id or Customer id Required !")
+	}
+
+	if limit < 1 {
+		return nil, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	// Make sure customer belong to the tenant
+	if _, err := service.CustomerRepository.FindById(customerId, tenantId); err != nil {
+		return nil, err
+	}
+
+	balance, err := service.Repository.GetBalance(customerId, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, count, err := service.Repository.GetLedger(customerId, tenantId, limit, page-1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoyaltyStatement{
+		CustomerId: customerId,
+		Balance:    balance,
+		Entries:    entries,
+		TotalCount: count,
+	}, nil
+}
+
+// ExpirePoints implements LoyaltyService.
+func (service *LoyaltyServiceImpl) ExpirePoints() (int, error) {
+	return service.Repository.ExpirePoints(time.Now())
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoyaltyServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const CUSTOMER_ID = 1
+	const LIMIT = 10
+	const PAGE = 1
+
+	newService := func() (*repository.LoyaltyRepositoryMock, *repository.CustomerRepositoryMock, LoyaltyService) {
+		loyaltyRepo := repository.NewLoyaltyRepositoryMock(&mock.Mock{}).(*repository.LoyaltyRepositoryMock)
+		customerRepo := repository.NewCustomerRepositoryMock(&mock.Mock{}).(*repository.CustomerRepositoryMock)
+		return loyaltyRepo, customerRepo, NewLoyaltyServiceImpl(loyaltyRepo, customerRepo)
+	}
+
+	t.Run("GetSetting", func(t *testing.T) {
+		loyaltyRepo, _, loyaltyService := newService()
+		expectedSetting := &model.LoyaltySetting{TenantId: TENANT_ID, IsActive: false}
+		loyaltyRepo.Mock.On("GetSetting", TENANT_ID).Return(expectedSetting, nil)
+
+		setting, err := loyaltyService.GetSetting(TENANT_ID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSetting, setting)
+
+		setting, err = loyaltyService.GetSetting(0)
+		assert.Error(t, err)
+		assert.Nil(t, setting)
+	})
+
+	t.Run("SaveSetting", func(t *testing.T) {
+		t.Run("NormalSave", func(t *testing.T) {
+			loyaltyRepo, _, loyaltyService := newService()
+			setting := &model.LoyaltySetting{
+				TenantId:     TENANT_ID,
+				EarnAmount:   10_000,
+				EarnPoints:   1,
+				RedeemValue:  100,
+				ExpiryMonths: 12,
+				IsActive:     true,
+			}
+			loyaltyRepo.Mock.On("SaveSetting", setting).Return(setting, nil)
+
+			savedSetting, err := loyaltyService.SaveSetting(setting)
+			assert.NoError(t, err)
+			// Empty rounding default to FLOOR
+			assert.Equal(t, model.LoyaltyRoundingFloor, savedSetting.Rounding)
+		})
+
+		t.Run("InvalidSetting", func(t *testing.T) {
+			_, _, loyaltyService := newService()
+
+			for _, setting := range []*model.LoyaltySetting{
+				{TenantId: 0, EarnAmount: 10_000},
+				{TenantId: TENANT_ID, EarnAmount: 0},
+				{TenantId: TENANT_ID, EarnAmount: 10_000, EarnPoints: -1},
+				{TenantId: TENANT_ID, EarnAmount: 10_000, RedeemValue: -1},
+				{TenantId: TENANT_ID, EarnAmount: 10_000, Rounding: "HALF_EVEN"},
+				{TenantId: TENANT_ID, EarnAmount: 10_000, ExpiryMonths: -1},
+				{TenantId: TENANT_ID, EarnAmount: 10_000, ExpiryMonths: 121},
+			} {
+				savedSetting, err := loyaltyService.SaveSetting(setting)
+				assert.Error(t, err)
+				assert.Nil(t, savedSetting)
+			}
+		})
+	})
+
+	t.Run("GetStatement", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			loyaltyRepo, customerRepo, loyaltyService := newService()
+			expectedEntries := []*model.LoyaltyLedger{
+				{Id: 2, Type: model.LoyaltyLedgerRedeem, Points: -5},
+				{Id: 1, Type: model.LoyaltyLedgerEarn, Points: 10, RemainingPoints: 5},
+			}
+			customerRepo.Mock.On("FindById", CUSTOMER_ID, TENANT_ID).Return(&model.Customer{Id: CUSTOMER_ID}, nil)
+			loyaltyRepo.Mock.On("GetBalance", CUSTOMER_ID, TENANT_ID).Return(5, nil)
+			loyaltyRepo.Mock.On("GetLedger", CUSTOMER_ID, TENANT_ID, LIMIT, PAGE-1).Return(expectedEntries, 2, nil)
+
+			statement, err := loyaltyService.GetStatement(CUSTOMER_ID, TENANT_ID, LIMIT, PAGE)
+			assert.NoError(t, err)
+			assert.Equal(t, 5, statement.Balance)
+			assert.Equal(t, 2, statement.TotalCount)
+			assert.Equal(t, expectedEntries, statement.Entries)
+		})
+
+		t.Run("CustomerNotFound", func(t *testing.T) {
+			_, customerRepo, loyaltyService := newService()
+			customerRepo.Mock.On("FindById", CUSTOMER_ID, TENANT_ID).Return(nil, errors.New("No customer found"))
+
+			statement, err := loyaltyService.GetStatement(CUSTOMER_ID, TENANT_ID, LIMIT, PAGE)
+			assert.Error(t, err)
+			assert.Nil(t, statement)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			_, _, loyaltyService := newService()
+
+			statement, err := loyaltyService.GetStatement(0, TENANT_ID, LIMIT, PAGE)
+			assert.Error(t, err)
+			assert.Nil(t, statement)
+
+			statement, err = loyaltyService.GetStatement(CUSTOMER_ID, TENANT_ID, 0, PAGE)
+			assert.Error(t, err)
+			assert.Nil(t, statement)
+
+			statement, err = loyaltyService.GetStatement(CUSTOMER_ID, TENANT_ID, LIMIT, 0)
+			assert.Error(t, err)
+			assert.Nil(t, statement)
+		})
+	})
+
+	t.Run("ExpirePoints", func(t *testing.T) {
+		loyaltyRepo, _, loyaltyService := newService()
+		loyaltyRepo.Mock.On("ExpirePoints", mock.AnythingOfType("time.Time")).Return(42, nil)
+
+		expired, err := loyaltyService.ExpirePoints()
+		assert.NoError(t, err)
+		assert.Equal(t, 42, expired)
+	})
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"time"
+)
+
+type MarginAlertService interface {
+	/*
+		Get the tenant margin alert setting
+	*/
+	GetSetting(tenantId int) (*model.MarginAlertSetting, error)
+
+	/*
+		Create or replace the tenant margin alert setting
+	*/
+	SaveSetting(setting *model.MarginAlertSetting) (*model.MarginAlertSetting, error)
+
+	/*
+		Store stock priced below the warehouse base price. storeId = 0 will not filter
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	GetBelowCostStocks(tenantId, storeId, limit, page int) ([]*repository.BelowCostStockRow, int, error)
+
+	/*
+		Items whose realised margin of the setting window is below the tenant threshold,
+		with the newest offending invoices. storeId = 0 will not filter
+	*/
+	GetLowMarginItems(tenantId, storeId int) (*repository.LowMarginReport, error)
+
+	/*
+		Background job: email the recipients of every active setting at most once a day,
+		only when something is flagged. Return how many alerts were sent
+	*/
+	SendAlerts(now time.Time) (int, error)
+}
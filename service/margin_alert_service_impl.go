@@ -0,0 +1,209 @@
+package service
+
+import (
+	"cashier-api/helper/mail"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// An active setting is alerted at most once per interval
+	marginAlertInterval = time.Hour * 24
+	// Offending invoices linked to every low margin item
+	marginAlertInvoicesPerItem = 10
+	// Rows of each section written in the alert email
+	marginAlertEmailRows = 20
+)
+
+type MarginAlertServiceImpl struct {
+	Repository repository.MarginAlertRepository
+	Mailer     mail.Mailer
+}
+
+func NewMarginAlertServiceImpl(repository repository.MarginAlertRepository, mailer mail.Mailer) MarginAlertService {
+	return &MarginAlertServiceImpl{Repository: repository, Mailer: mailer}
+}
+
+// GetSetting implements MarginAlertService.
+func (service *MarginAlertServiceImpl) GetSetting(tenantId int) (*model.MarginAlertSetting, error) {
+	if tenantId < 1 {
+		return nil, errors.New("Invalid tenant id")
+	}
+
+	return service.Repository.GetSetting(tenantId)
+}
+
+// SaveSetting implements MarginAlertService.
+func (service *MarginAlertServiceImpl) SaveSetting(setting *model.MarginAlertSetting) (*model.MarginAlertSetting, error) {
+	if setting.TenantId < 1 {
+		return nil, errors.New("Invalid tenant id")
+	}
+	if err := setting.Validate(); err != nil {
+		return nil, err
+	}
+
+	return service.Repository.SaveSetting(setting)
+}
+
+// GetBelowCostStocks implements MarginAlertService.
+func (service *MarginAlertServiceImpl) GetBelowCostStocks(tenantId, storeId, limit, page int) ([]*repository.BelowCostStockRow, int, error) {
+	if err := validateReportFilter(tenantId, storeId, 0, nil); err != nil {
+		return nil, 0, err
+	}
+
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	rows, count, err := service.Repository.GetBelowCostStocks(tenantId, storeId, limit, page-1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, row := range rows {
+		row.MarginPercent = percentOf(row.Price-row.BasePrice, row.Price)
+	}
+
+	return rows, count, nil
+}
+
+// GetLowMarginItems implements MarginAlertService.
+func (service *MarginAlertServiceImpl) GetLowMarginItems(tenantId, storeId int) (*repository.LowMarginReport, error) {
+	if err := validateReportFilter(tenantId, storeId, 0, nil); err != nil {
+		return nil, err
+	}
+
+	setting, err := service.Repository.GetSetting(tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.lowMarginReport(setting, storeId, time.Now())
+}
+
+func (service *MarginAlertServiceImpl) lowMarginReport(setting *model.MarginAlertSetting, storeId int, now time.Time) (*repository.LowMarginReport, error) {
+	since := setting.Since(now)
+	items, err := service.Repository.GetLowMarginItems(setting.TenantId, storeId, since, setting.MinMarginPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIds := make([]int, 0, len(items))
+	for _, item := range items {
+		itemIds = append(itemIds, item.ItemId)
+	}
+	invoices, err := service.Repository.GetLowMarginInvoices(
+		setting.TenantId, storeId, since, setting.MinMarginPercent, itemIds, marginAlertInvoicesPerItem,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invoices are ordered by item, link them to their item
+	invoicesByItem := make(map[int][]*repository.LowMarginInvoiceRow)
+	for _, invoice := range invoices {
+		invoice.MarginPercent = percentOf(invoice.Profit, invoice.TotalAmount)
+		invoicesByItem[invoice.ItemId] = append(invoicesByItem[invoice.ItemId], invoice)
+	}
+	for _, item := range items {
+		item.MarginPercent = percentOf(item.TotalProfit, item.TotalRevenue)
+		item.Invoices = invoicesByItem[item.ItemId]
+		if item.Invoices == nil {
+			item.Invoices = make([]*repository.LowMarginInvoiceRow, 0)
+		} else {
+			item.InvoiceCount = item.Invoices[0].LineCount
+		}
+	}
+
+	return &repository.LowMarginReport{
+		MinMarginPercent: setting.MinMarginPercent,
+		WindowDays:       setting.WindowDays,
+		Since:            since,
+		Items:            items,
+	}, nil
+}
+
+// SendAlerts implements MarginAlertService.
+func (service *MarginAlertServiceImpl) SendAlerts(now time.Time) (int, error) {
+	settings, err := service.Repository.ClaimDueAlerts(now, marginAlertInterval)
+	if err != nil {
+		return 0, err
+	}
+
+	// A failed alert is only logged, it is sent again by the next interval
+	sent := 0
+	for _, setting := range settings {
+		ok, err := service.sendAlert(setting, now)
+		if err != nil {
+			log.Warnf("[MARGIN ALERT] tenant %d failed: %s", setting.TenantId, err.Error())
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// sendAlert return false when nothing is flagged (no email)
+func (service *MarginAlertServiceImpl) sendAlert(setting *model.MarginAlertSetting, now time.Time) (bool, error) {
+	belowCost, belowCostCount, err := service.Repository.GetBelowCostStocks(setting.TenantId, 0, marginAlertEmailRows, 0)
+	if err != nil {
+		return false, err
+	}
+	report, err := service.lowMarginReport(setting, 0, now)
+	if err != nil {
+		return false, err
+	}
+
+	if belowCostCount == 0 && len(report.Items) == 0 {
+		return false, nil
+	}
+
+	var body strings.Builder
+	if belowCostCount > 0 {
+		fmt.Fprintf(&body, "Store price below the base price (%d):\r\n", belowCostCount)
+		for _, row := range belowCost {
+			fmt.Fprintf(&body, "- %s / %s: price %d, base price %d\r\n", row.StoreName, row.ItemName, row.Price, row.BasePrice)
+		}
+		if belowCostCount > len(belowCost) {
+			fmt.Fprintf(&body, "... and %d more\r\n", belowCostCount-len(belowCost))
+		}
+		body.WriteString("\r\n")
+	}
+
+	if len(report.Items) > 0 {
+		fmt.Fprintf(&body, "Items below %v%% margin over the last %d days (%d):\r\n",
+			report.MinMarginPercent, report.WindowDays, len(report.Items))
+		for i, item := range report.Items {
+			if i == marginAlertEmailRows {
+				fmt.Fprintf(&body, "... and %d more\r\n", len(report.Items)-i)
+				break
+			}
+
+			invoiceIds := make([]string, 0, len(item.Invoices))
+			for _, invoice := range item.Invoices {
+				invoiceIds = append(invoiceIds, fmt.Sprintf("#%d", invoice.OrderItemId))
+			}
+			fmt.Fprintf(&body, "- %s: margin %.2f%%, revenue %d, profit %d, %d invoice(s) %s\r\n",
+				item.ItemName, item.MarginPercent, item.TotalRevenue, item.TotalProfit, item.InvoiceCount, strings.Join(invoiceIds, ", "))
+		}
+	}
+
+	err = service.Mailer.Send(&mail.Message{
+		To:      setting.RecipientList(),
+		Subject: fmt.Sprintf("Margin alert: %d store price(s) below cost, %d low margin item(s)", belowCostCount, len(report.Items)),
+		Body:    body.String(),
+	})
+	return err == nil, err
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarginAlertServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+
+	newService := func(mailer *recordMailer) (*repository.MarginAlertRepositoryMock, MarginAlertService) {
+		marginAlertRepo := repository.NewMarginAlertRepositoryMock(&mock.Mock{}).(*repository.MarginAlertRepositoryMock)
+		return marginAlertRepo, NewMarginAlertServiceImpl(marginAlertRepo, mailer)
+	}
+
+	newSetting := func() *model.MarginAlertSetting {
+		return &model.MarginAlertSetting{
+			TenantId:         TENANT_ID,
+			MinMarginPercent: 10,
+			WindowDays:       30,
+			IsActive:         true,
+			Recipients:       "owner@example.com",
+		}
+	}
+
+	t.Run("SaveSetting", func(t *testing.T) {
+		marginAlertRepo, marginAlertService := newService(&recordMailer{})
+		marginAlertRepo.Mock.On("SaveSetting", mock.Anything).Return(newSetting(), nil)
+
+		_, err := marginAlertService.SaveSetting(newSetting())
+		require.NoError(t, err)
+
+		invalid := newSetting()
+		invalid.WindowDays = 0
+		_, err = marginAlertService.SaveSetting(invalid)
+		assert.Error(t, err)
+
+		noTenant := newSetting()
+		noTenant.TenantId = 0
+		_, err = marginAlertService.SaveSetting(noTenant)
+		assert.Error(t, err)
+
+		marginAlertRepo.Mock.AssertNumberOfCalls(t, "SaveSetting", 1)
+	})
+
+	t.Run("GetBelowCostStocks", func(t *testing.T) {
+		marginAlertRepo, marginAlertService := newService(&recordMailer{})
+		marginAlertRepo.Mock.On("GetBelowCostStocks", TENANT_ID, 0, 10, 0).Return([]*repository.BelowCostStockRow{
+			{ItemId: 1, Price: 800, BasePrice: 1000, UnitLoss: 200},
+		}, 1, nil)
+
+		rows, count, err := marginAlertService.GetBelowCostStocks(TENANT_ID, 0, 10, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.InDelta(t, -25.0, rows[0].MarginPercent, 0.001)
+
+		_, _, err = marginAlertService.GetBelowCostStocks(TENANT_ID, 0, 0, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("GetLowMarginItems", func(t *testing.T) {
+		marginAlertRepo, marginAlertService := newService(&recordMailer{})
+		marginAlertRepo.Mock.On("GetSetting", TENANT_ID).Return(newSetting(), nil)
+		marginAlertRepo.Mock.On("GetLowMarginItems", TENANT_ID, 0, mock.Anything, 10.0).Return([]*repository.LowMarginItemRow{
+			{ItemId: 1, ItemName: "Coffee", TotalRevenue: 10_000, TotalCogs: 9_500, TotalProfit: 500},
+			{ItemId: 2, ItemName: "Tea", TotalRevenue: 4_000, TotalCogs: 3_800, TotalProfit: 200},
+		}, nil)
+		marginAlertRepo.Mock.On("GetLowMarginInvoices", TENANT_ID, 0, mock.Anything, 10.0, []int{1, 2}, marginAlertInvoicesPerItem).
+			Return([]*repository.LowMarginInvoiceRow{
+				{OrderItemId: 12, ItemId: 1, TotalAmount: 5_000, Profit: -100, LineCount: 3},
+				{OrderItemId: 10, ItemId: 1, TotalAmount: 5_000, Profit: 100, LineCount: 3},
+			}, nil)
+
+		report, err := marginAlertService.GetLowMarginItems(TENANT_ID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, report.MinMarginPercent)
+		require.Len(t, report.Items, 2)
+
+		coffee, tea := report.Items[0], report.Items[1]
+		assert.InDelta(t, 5.0, coffee.MarginPercent, 0.001)
+		assert.Equal(t, 3, coffee.InvoiceCount)
+		require.Len(t, coffee.Invoices, 2)
+		assert.Equal(t, 12, coffee.Invoices[0].OrderItemId)
+		assert.InDelta(t, -2.0, coffee.Invoices[0].MarginPercent, 0.001)
+
+		// Never nil, so the JSON is an empty array
+		assert.NotNil(t, tea.Invoices)
+		assert.Zero(t, tea.InvoiceCount)
+
+		_, err = marginAlertService.GetLowMarginItems(TENANT_ID, -1)
+		assert.Error(t, err)
+	})
+
+	t.Run("SendAlerts", func(t *testing.T) {
+		now := time.Now()
+
+		t.Run("Flagged", func(t *testing.T) {
+			mailer := &recordMailer{}
+			marginAlertRepo, marginAlertService := newService(mailer)
+			marginAlertRepo.Mock.On("ClaimDueAlerts", now, marginAlertInterval).Return([]*model.MarginAlertSetting{newSetting()}, nil)
+			marginAlertRepo.Mock.On("GetBelowCostStocks", TENANT_ID, 0, marginAlertEmailRows, 0).Return([]*repository.BelowCostStockRow{
+				{StoreName: "Main", ItemName: "Sugar", Price: 800, BasePrice: 1000},
+			}, 1, nil)
+			marginAlertRepo.Mock.On("GetLowMarginItems", TENANT_ID, 0, now.AddDate(0, 0, -30), 10.0).Return([]*repository.LowMarginItemRow{
+				{ItemId: 1, ItemName: "Coffee", TotalRevenue: 10_000, TotalProfit: 500},
+			}, nil)
+			marginAlertRepo.Mock.On("GetLowMarginInvoices", TENANT_ID, 0, now.AddDate(0, 0, -30), 10.0, []int{1}, marginAlertInvoicesPerItem).
+				Return([]*repository.LowMarginInvoiceRow{{OrderItemId: 12, ItemId: 1, LineCount: 1}}, nil)
+
+			sent, err := marginAlertService.SendAlerts(now)
+			require.NoError(t, err)
+			assert.Equal(t, 1, sent)
+			require.Len(t, mailer.messages, 1)
+			assert.Equal(t, []string{"owner@example.com"}, mailer.messages[0].To)
+			assert.Contains(t, mailer.messages[0].Body, "Main / Sugar: price 800, base price 1000")
+			assert.Contains(t, mailer.messages[0].Body, "Coffee: margin 5.00%")
+			assert.Contains(t, mailer.messages[0].Body, "#12")
+		})
+
+		t.Run("NothingFlagged", func(t *testing.T) {
+			mailer := &recordMailer{}
+			marginAlertRepo, marginAlertService := newService(mailer)
+			marginAlertRepo.Mock.On("ClaimDueAlerts", now, marginAlertInterval).Return([]*model.MarginAlertSetting{newSetting()}, nil)
+			marginAlertRepo.Mock.On("GetBelowCostStocks", TENANT_ID, 0, marginAlertEmailRows, 0).Return([]*repository.BelowCostStockRow{}, 0, nil)
+			marginAlertRepo.Mock.On("GetLowMarginItems", TENANT_ID, 0, mock.Anything, 10.0).Return([]*repository.LowMarginItemRow{}, nil)
+			marginAlertRepo.Mock.On("GetLowMarginInvoices", TENANT_ID, 0, mock.Anything, 10.0, []int{}, marginAlertInvoicesPerItem).
+				Return([]*repository.LowMarginInvoiceRow{}, nil)
+
+			sent, err := marginAlertService.SendAlerts(now)
+			require.NoError(t, err)
+			assert.Zero(t, sent)
+			assert.Empty(t, mailer.messages)
+		})
+
+		t.Run("MailFailed", func(t *testing.T) {
+			mailer := &recordMailer{err: errors.New("connection refused")}
+			marginAlertRepo, marginAlertService := newService(mailer)
+			marginAlertRepo.Mock.On("ClaimDueAlerts", now, marginAlertInterval).Return([]*model.MarginAlertSetting{newSetting()}, nil)
+			marginAlertRepo.Mock.On("GetBelowCostStocks", TENANT_ID, 0, marginAlertEmailRows, 0).Return([]*repository.BelowCostStockRow{
+				{StoreName: "Main", ItemName: "Sugar", Price: 800, BasePrice: 1000},
+			}, 1, nil)
+			marginAlertRepo.Mock.On("GetLowMarginItems", TENANT_ID, 0, mock.Anything, 10.0).Return([]*repository.LowMarginItemRow{}, nil)
+			marginAlertRepo.Mock.On("GetLowMarginInvoices", TENANT_ID, 0, mock.Anything, 10.0, []int{}, marginAlertInvoicesPerItem).
+				Return([]*repository.LowMarginInvoiceRow{}, nil)
+
+			// Only logged, the next interval send it again
+			sent, err := marginAlertService.SendAlerts(now)
+			require.NoError(t, err)
+			assert.Zero(t, sent)
+		})
+	})
+}
@@ -8,16 +8,34 @@ import (
 
 type OrderItemService interface {
 	/*
-		When cashier app press the button, then
-		this will called
+		Same as Transactions, but the order start as PLACED and could be paid later.
+		The order then move through IN_PREPARATION, READY until COMPLETED
 	*/
-	PlaceOrderItem(*model.OrderItem) (*model.OrderItem, error)
+	PlaceOrderItem(params *repository.CreateTransactionParams) (*repository.TransactionDataReturn, error)
+
+	/*
+		Move a placed order into the next status, the change is recorded with the user.
+		COMPLETED require full payment (purchased_price), CANCELLED return the stock
+	*/
+	UpdateOrderStatus(params *repository.UpdateOrderStatusParams) (*model.OrderItem, error)
+
+	/*
+		Get PLACED, IN_PREPARATION and READY orders of a store (oldest first)
+		2nd params return is the count of all data
+	*/
+	GetOpenOrders(tenantId, storeId, limit, page int) ([]*model.OrderItem, int, error)
+
+	/*
+		Every status change of the order (oldest first)
+	*/
+	GetStatusHistory(orderItemId, tenantId int) ([]*model.OrderStatusHistory, error)
 
 	/*
 		Get the list of order_item, purchased_item_list will not included
 		2nd params return is the count of all data
+		customerId = 0 will not filter by customer
 	*/
-	Get(tenantId, storeId, limit, page int, filters []*query.QueryFilter, dateFilter *query.DateFilter) ([]*model.OrderItem, int, error)
+	Get(tenantId, storeId, customerId, limit, page int, filters []*query.QueryFilter, dateFilter *query.DateFilter) ([]*model.OrderItem, int, error)
 
 	/*
 		Always minus page by 1 because PostgreSQL start index from 0
@@ -38,7 +56,25 @@ type OrderItemService interface {
 	GetSalesReport(tenantId int, storeId int, dateFilter *query.DateFilter) (*repository.SalesReport, error)
 
 	/*
-		Build an Excel workbook with per-item profit breakdown and a summary sheet.
+		Sales report per hour / day / week / month at the tenant timezone,
+		bucket without sales is filled with zero. Start and end date are required
+	*/
+	GetSalesSeries(tenantId int, storeId int, bucket repository.SalesBucket, dateFilter *query.DateFilter) (*repository.SalesSeries, error)
+
+	/*
+		Revenue, quantity, discount and profit per category with share of total revenue (%).
+		Item at many categories is counted once at its first category, so the shares add up to 100
+	*/
+	GetCategoryReport(tenantId int, storeId int, dateFilter *query.DateFilter) ([]*repository.CategoryReportRow, error)
+
+	/*
+		Compare every store of the tenant at the same period with share of total (%)
+	*/
+	GetStoreComparison(tenantId int, dateFilter *query.DateFilter) ([]*repository.StoreComparisonRow, error)
+
+	/*
+		Build an Excel workbook with per-item profit breakdown, per category breakdown and a summary sheet.
+		Store comparison sheet is added when exporting all store (storeId = 0).
 		Returns the raw .xlsx bytes.
 	*/
 	ExportProfitExcel(tenantId int, storeId int, dateFilter *query.DateFilter) ([]byte, error)
@@ -46,5 +82,5 @@ type OrderItemService interface {
 	/*
 		Soft delete invoice.
 	*/
-	DeleteInvoice(orderItemId int, tenantId int) error
+	DeleteInvoice(orderItemId int, tenantId int, userId int) error
 }
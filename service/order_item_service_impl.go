@@ -1,6 +1,7 @@
 package service
 
 import (
+	"cashier-api/helper/export"
 	"cashier-api/helper/query"
 	"cashier-api/model"
 	"cashier-api/repository"
@@ -28,6 +29,7 @@ func NewOrderItemServiceImpl(repository repository.OrderItemRepository) OrderIte
 func (service *OrderItemServiceImpl) Get(
 	tenantId int,
 	storeId int,
+	customerId int,
 	limit int,
 	page int,
 	filters []*query.QueryFilter,
@@ -37,6 +39,10 @@ func (service *OrderItemServiceImpl) Get(
 		return nil, 0, errors.New("Tenant id is Required !")
 	}
 
+	if customerId < 0 {
+		return nil, 0, fmt.Errorf("Invalid customer id: %d", customerId)
+	}
+
 	if limit < 1 {
 		return nil, 0, fmt.Errorf("Limit could not less then 1 (limit >= 1). Given limit %d", limit)
 	}
@@ -79,7 +85,7 @@ func (service *OrderItemServiceImpl) Get(
 		}
 	}
 
-	orderItems, count, err := service.Repository.Get(tenantId, storeId, limit, page-1, filters, dateFilter)
+	orderItems, count, err := service.Repository.Get(tenantId, storeId, customerId, limit, page-1, filters, dateFilter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -87,23 +93,78 @@ func (service *OrderItemServiceImpl) Get(
 	return orderItems, count, nil
 }
 
+// Transactions implements OrderItemService.
+func (service *OrderItemServiceImpl) Transactions(params *repository.CreateTransactionParams) (*repository.TransactionDataReturn, error) {
+	// Could only be PLACED by PlaceOrderItem
+	params.Status = ""
+	if err := service.validateTransaction(params, true); err != nil {
+		return nil, err
+	}
+
+	transactionDataReturn, err := service.Repository.Transactions(params)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create transaction: %w", err)
+	}
+
+	return transactionDataReturn, nil
+}
+
 // PlaceOrderItem implements OrderItemService.
-func (service *OrderItemServiceImpl) PlaceOrderItem(*model.OrderItem) (*model.OrderItem, error) {
-	panic("unimplemented")
+func (service *OrderItemServiceImpl) PlaceOrderItem(params *repository.CreateTransactionParams) (*repository.TransactionDataReturn, error) {
+	// Placed order is paid when it's COMPLETED
+	params.Status = model.OrderStatusPlaced
+	if err := service.validateTransaction(params, false); err != nil {
+		return nil, err
+	}
+
+	transactionDataReturn, err := service.Repository.Transactions(params)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to place order: %w", err)
+	}
+
+	return transactionDataReturn, nil
 }
 
-// Transactions implements OrderItemService.
-func (service *OrderItemServiceImpl) Transactions(params *repository.CreateTransactionParams) (*repository.TransactionDataReturn, error) {
+/*
+validateTransaction check the request and recalculate every total from the items.
+requirePayment = false allow the cash to be paid later
+*/
+func (service *OrderItemServiceImpl) validateTransaction(params *repository.CreateTransactionParams, requirePayment bool) error {
 	if params.TenantId <= 0 || params.StoreId <= 0 || params.UserId <= 0 {
-		return nil, errors.New("Tenant id, Store id, User id is Required !")
+		return errors.New("Tenant id, Store id, User id is Required !")
+	}
+
+	// Customer is optional, 0 means anonymous sale
+	if params.CustomerId < 0 {
+		return fmt.Errorf("Invalid customer id: %d", params.CustomerId)
+	}
+
+	// Loyalty redemption is optional, the discount value is verified again by the repository
+	if params.RedeemPoints < 0 || params.PointsDiscount < 0 {
+		return errors.New("Redeemed points and points discount should never be < 0")
+	}
+	if params.RedeemPoints > 0 && params.CustomerId == 0 {
+		return errors.New("Customer is required to redeem points")
+	}
+	if params.RedeemPoints == 0 && params.PointsDiscount != 0 {
+		return errors.New("Points discount given without redeemed points")
+	}
+
+	// Gift card payment is optional, the balance is checked by the repository
+	if params.GiftCardAmount < 0 {
+		return errors.New("Gift card amount should never be < 0")
+	}
+	params.GiftCardCode = normalizeGiftCardCode(params.GiftCardCode)
+	if params.GiftCardAmount > 0 && params.GiftCardCode == "" {
+		return errors.New("Gift card code is required to pay with gift card")
 	}
 
 	if len(params.Items) == 0 {
-		return nil, errors.New("At least one item is required")
+		return errors.New("At least one item is required")
 	}
 
 	if len(params.Items) > 1000 {
-		return nil, errors.New("Too many items (max 1000)")
+		return errors.New("Too many items (max 1000)")
 	}
 
 	var (
@@ -117,7 +178,7 @@ func (service *OrderItemServiceImpl) Transactions(params *repository.CreateTrans
 		// Check price consistency for same item
 		if existingPrice, exists := priceConsistencyCheck[item.ItemId]; exists {
 			if existingPrice != item.StorePriceSnapshot {
-				return nil, fmt.Errorf("Price mismatch for item_id %d: expected %d, got %d",
+				return fmt.Errorf("Price mismatch for item_id %d: expected %d, got %d",
 					item.ItemId, existingPrice, item.StorePriceSnapshot)
 			}
 		} else {
@@ -125,12 +186,12 @@ func (service *OrderItemServiceImpl) Transactions(params *repository.CreateTrans
 		}
 
 		if item.Quantity < 1 {
-			return nil, fmt.Errorf("Given quantity %d, from item_id: %d. Quantity should never be <= 0", item.Quantity, item.Id)
+			return fmt.Errorf("Given quantity %d, from item_id: %d. Quantity should never be <= 0", item.Quantity, item.Id)
 		}
 
 		if !service.ItemNameRegexRule.MatchString(item.ItemNameSnapshot) {
 			// This is the same regex with WarehouseService.CreateItem
-			return nil, fmt.Errorf("Illegal input from item name snapshot: %s", item.ItemNameSnapshot)
+			return fmt.Errorf("Illegal input from item name snapshot: %s", item.ItemNameSnapshot)
 		}
 
 		// Calculate totals
@@ -145,45 +206,108 @@ func (service *OrderItemServiceImpl) Transactions(params *repository.CreateTrans
 
 		// Validate individual item total
 		if item.TotalAmount != itemTotal {
-			return nil, fmt.Errorf("Item %d total mismatch: expected %d, got %d",
+			return fmt.Errorf("Item %d total mismatch: expected %d, got %d",
 				item.ItemId, itemTotal, item.TotalAmount)
 		}
+
+		// Tax is included in the line total
+		if item.TaxAmount < 0 || item.TaxAmount > item.TotalAmount {
+			return fmt.Errorf("Item %d tax %d should be between 0 and the line total %d",
+				item.ItemId, item.TaxAmount, item.TotalAmount)
+		}
 	}
 
 	// Validate against provided totals
 	if calculatedQuantity != params.TotalQuantity {
-		return nil, fmt.Errorf("Total quantity mismatch: calculated %d, provided %d",
+		return fmt.Errorf("Total quantity mismatch: calculated %d, provided %d",
 			calculatedQuantity, params.TotalQuantity)
 	}
 
 	if calculatedSubTotal != params.SubTotal {
-		return nil, fmt.Errorf("Subtotal mismatch: calculated %d, provided %d",
+		return fmt.Errorf("Subtotal mismatch: calculated %d, provided %d",
 			calculatedSubTotal, params.SubTotal)
 	}
 
 	if calculatedTotal != params.TotalAmount {
-		return nil, fmt.Errorf("Total amount mismatch: calculated %d, provided %d",
+		return fmt.Errorf("Total amount mismatch: calculated %d, provided %d",
 			calculatedTotal, params.TotalAmount)
 	}
 
 	if calculatedDiscount != params.DiscountAmount {
-		return nil, fmt.Errorf("Discount amount mismatch: calculated %d, provided %d",
+		return fmt.Errorf("Discount amount mismatch: calculated %d, provided %d",
 			calculatedDiscount, params.DiscountAmount)
 	}
 
+	if params.PointsDiscount > params.TotalAmount {
+		return fmt.Errorf("Points discount %d exceed the total amount %d",
+			params.PointsDiscount, params.TotalAmount)
+	}
+
 	// Validate payment (if you track cash given)
 	// Remove this if PurchasedPrice is just another name for TotalAmount
-	if params.PurchasedPrice < params.TotalAmount {
-		return nil, fmt.Errorf("Insufficient payment: need %d, got %d",
-			params.TotalAmount, params.PurchasedPrice)
+	amountDue := params.TotalAmount - params.PointsDiscount
+	if params.GiftCardAmount > amountDue {
+		return fmt.Errorf("Gift card amount %d exceed the amount due %d",
+			params.GiftCardAmount, amountDue)
 	}
 
-	transactionDataReturn, err := service.Repository.Transactions(params)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to create transaction: %w", err)
+	if params.PurchasedPrice < 0 {
+		return fmt.Errorf("Purchased price should never be < 0. Given %d", params.PurchasedPrice)
+	}
+	if requirePayment && params.PurchasedPrice+params.GiftCardAmount < amountDue {
+		return fmt.Errorf("Insufficient payment: need %d, got %d",
+			amountDue, params.PurchasedPrice+params.GiftCardAmount)
 	}
 
-	return transactionDataReturn, nil
+	return nil
+}
+
+// UpdateOrderStatus implements OrderItemService.
+func (service *OrderItemServiceImpl) UpdateOrderStatus(params *repository.UpdateOrderStatusParams) (*model.OrderItem, error) {
+	if params.OrderItemId <= 0 || params.TenantId <= 0 || params.UserId <= 0 {
+		return nil, errors.New("Tenant id, Order item id, User id is Required !")
+	}
+
+	if !params.Status.IsValid() {
+		return nil, fmt.Errorf("Invalid order status: %s", params.Status)
+	}
+	if params.Status == model.OrderStatusPlaced {
+		return nil, errors.New("Order could not be placed again")
+	}
+
+	if params.PurchasedPrice < 0 {
+		return nil, fmt.Errorf("Purchased price should never be < 0. Given %d", params.PurchasedPrice)
+	}
+	if params.PurchasedPrice > 0 && params.Status != model.OrderStatusCompleted {
+		return nil, errors.New("Purchased price could only be given when the order is COMPLETED")
+	}
+
+	return service.Repository.UpdateStatus(params)
+}
+
+// GetOpenOrders implements OrderItemService.
+func (service *OrderItemServiceImpl) GetOpenOrders(tenantId, storeId, limit, page int) ([]*model.OrderItem, int, error) {
+	if tenantId <= 0 || storeId <= 0 {
+		return nil, 0, errors.New("Tenant id, Store id is Required !")
+	}
+
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	return service.Repository.GetOpenOrders(tenantId, storeId, limit, page-1)
+}
+
+// GetStatusHistory implements OrderItemService.
+func (service *OrderItemServiceImpl) GetStatusHistory(orderItemId, tenantId int) ([]*model.OrderStatusHistory, error) {
+	if tenantId <= 0 || orderItemId <= 0 {
+		return nil, errors.New("Tenant id or Order item id Required !")
+	}
+
+	return service.Repository.GetStatusHistory(orderItemId, tenantId)
 }
 
 // FindById implements OrderItemService.
@@ -253,6 +377,127 @@ func (service *OrderItemServiceImpl) GetSalesReport(tenantId int, storeId int, d
 	return salesReport, nil
 }
 
+// Series longer than this should use a bigger bucket
+const maxSalesSeriesBuckets = 1000
+
+/*
+truncateToBucket return the start of the bucket containing t,
+using t location (same as date_trunc on the tenant local time)
+*/
+func truncateToBucket(t time.Time, bucket repository.SalesBucket) time.Time {
+	year, month, day := t.Date()
+	switch bucket {
+	case repository.SalesBucketHour:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+	case repository.SalesBucketWeek:
+		// Monday is the first day of the week
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case repository.SalesBucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucket(t time.Time, bucket repository.SalesBucket) time.Time {
+	year, month, day := t.Date()
+	switch bucket {
+	case repository.SalesBucketHour:
+		return t.Add(time.Hour)
+	case repository.SalesBucketWeek:
+		return time.Date(year, month, day+7, 0, 0, 0, 0, t.Location())
+	case repository.SalesBucketMonth:
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Bucket from PostgreSQL has no timezone, compare it by the wall clock
+func bucketKey(t time.Time) string {
+	return t.Format("2006-01-02T15")
+}
+
+// GetSalesSeries implements OrderItemService.
+func (service *OrderItemServiceImpl) GetSalesSeries(tenantId int, storeId int, bucket repository.SalesBucket, dateFilter *query.DateFilter) (*repository.SalesSeries, error) {
+	if tenantId <= 0 {
+		return nil, errors.New("Tenant id is Required !")
+	}
+	// storeId = 0 is allowed, this allow to get series from all store
+	if storeId < 0 {
+		return nil, fmt.Errorf("Given store id value is not allowed. storeId: %d", storeId)
+	}
+
+	if !bucket.IsValid() {
+		return nil, fmt.Errorf("Invalid bucket: %s. Allowed: hour, day, week, month", bucket)
+	}
+
+	// Zero filling need both ends
+	if dateFilter == nil || dateFilter.StartDate == nil || dateFilter.EndDate == nil {
+		return nil, errors.New("Start date and end date are required")
+	}
+	if *dateFilter.StartDate < 0 || *dateFilter.EndDate < 0 {
+		return nil, fmt.Errorf("Invalid date timestamp: %d - %d", *dateFilter.StartDate, *dateFilter.EndDate)
+	}
+	if *dateFilter.StartDate >= *dateFilter.EndDate {
+		return nil, fmt.Errorf("Start date (%d) should be before end date (%d)", *dateFilter.StartDate, *dateFilter.EndDate)
+	}
+	maxTimestamp := int64(4102444800) // 2100-01-01 00:00:00 UTC
+	if *dateFilter.EndDate > maxTimestamp {
+		return nil, fmt.Errorf("End date is too far in the future: %d", *dateFilter.EndDate)
+	}
+
+	timezone, err := service.Repository.GetTenantTimezone(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid tenant timezone: %s", timezone)
+	}
+
+	// Build every bucket first, so the range is checked before querying
+	end := time.Unix(*dateFilter.EndDate, 0).In(location)
+	buckets := make([]time.Time, 0)
+	for current := truncateToBucket(time.Unix(*dateFilter.StartDate, 0).In(location), bucket); current.Before(end); current = nextBucket(current, bucket) {
+		// The repeated hour when DST end is a single bucket at PostgreSQL
+		if len(buckets) > 0 && bucketKey(buckets[len(buckets)-1]) == bucketKey(current) {
+			continue
+		}
+		if len(buckets) == maxSalesSeriesBuckets {
+			return nil, fmt.Errorf("Too many buckets (max %d), please use a bigger bucket or shorter range", maxSalesSeriesBuckets)
+		}
+		buckets = append(buckets, current)
+	}
+
+	points, err := service.Repository.GetSalesSeries(tenantId, storeId, bucket, timezone, dateFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	pointByKey := make(map[string]*repository.SalesSeriesPoint, len(points))
+	for _, point := range points {
+		pointByKey[bucketKey(point.BucketStart)] = point
+	}
+
+	series := &repository.SalesSeries{
+		Bucket:   bucket,
+		Timezone: timezone,
+		Points:   make([]*repository.SalesSeriesPoint, len(buckets)),
+	}
+	for i, bucketStart := range buckets {
+		series.Points[i] = &repository.SalesSeriesPoint{BucketStart: bucketStart}
+		if point, ok := pointByKey[bucketKey(bucketStart)]; ok {
+			series.Points[i].SalesReport = point.SalesReport
+		}
+	}
+
+	return series, nil
+}
+
 // ExportProfitExcel implements OrderItemService.
 func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int, dateFilter *query.DateFilter) ([]byte, error) {
 	if tenantId <= 0 {
@@ -272,6 +517,20 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 		return nil, err
 	}
 
+	categoryRows, err := service.GetCategoryReport(tenantId, storeId, dateFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comparing stores only make sense when exporting all store
+	var storeRows []*repository.StoreComparisonRow
+	if storeId == 0 {
+		storeRows, err = service.GetStoreComparison(tenantId, dateFilter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	f := excelize.NewFile()
 	defer f.Close()
 
@@ -279,51 +538,11 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 	itemSheet := "Profit Per Item"
 	f.SetSheetName("Sheet1", itemSheet)
 
-	// Header style: bold + center
-	headerStyle, _ := f.NewStyle(&excelize.Style{
-		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
-		Fill:      excelize.Fill{Type: "pattern", Color: []string{"4472C4"}, Pattern: 1},
-		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
-		Border: []excelize.Border{
-			{Type: "left", Color: "000000", Style: 1},
-			{Type: "right", Color: "000000", Style: 1},
-			{Type: "top", Color: "000000", Style: 1},
-			{Type: "bottom", Color: "000000", Style: 1},
-		},
-	})
-
-	// Currency style (IDR, no decimal)
-	currencyStyle, _ := f.NewStyle(&excelize.Style{
-		NumFmt: 3, // #,##0
-		Border: []excelize.Border{
-			{Type: "left", Color: "CCCCCC", Style: 1},
-			{Type: "right", Color: "CCCCCC", Style: 1},
-			{Type: "top", Color: "CCCCCC", Style: 1},
-			{Type: "bottom", Color: "CCCCCC", Style: 1},
-		},
-	})
-
-	// Profit column style with green/red conditional logic skipped for simplicity — use currency
-	profitStyle, _ := f.NewStyle(&excelize.Style{
-		NumFmt: 3,
-		Font:   &excelize.Font{Bold: true},
-		Border: []excelize.Border{
-			{Type: "left", Color: "CCCCCC", Style: 1},
-			{Type: "right", Color: "CCCCCC", Style: 1},
-			{Type: "top", Color: "CCCCCC", Style: 1},
-			{Type: "bottom", Color: "CCCCCC", Style: 1},
-		},
-	})
-
-	marginStyle, _ := f.NewStyle(&excelize.Style{
-		NumFmt: 2, // 0.00
-		Border: []excelize.Border{
-			{Type: "left", Color: "CCCCCC", Style: 1},
-			{Type: "right", Color: "CCCCCC", Style: 1},
-			{Type: "top", Color: "CCCCCC", Style: 1},
-			{Type: "bottom", Color: "CCCCCC", Style: 1},
-		},
-	})
+	// Same look as the generic export
+	styles, err := export.NewXlsxStyles(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create excel styles: %w", err)
+	}
 
 	headers := []string{"#", "Item Name", "Qty Sold", "Revenue (Rp)", "COGS (Rp)", "Discount (Rp)", "Profit (Rp)", "Margin (%)"}
 	colWidths := []float64{5, 35, 12, 18, 18, 18, 18, 14}
@@ -332,7 +551,7 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 		col, _ := excelize.ColumnNumberToName(i + 1)
 		cell := fmt.Sprintf("%s1", col)
 		f.SetCellValue(itemSheet, cell, h)
-		f.SetCellStyle(itemSheet, cell, cell, headerStyle)
+		f.SetCellStyle(itemSheet, cell, cell, styles.Header)
 		f.SetColWidth(itemSheet, col, col, colWidths[i])
 	}
 	f.SetRowHeight(itemSheet, 1, 20)
@@ -359,11 +578,11 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 			f.SetCellValue(itemSheet, cell, val)
 			switch j {
 			case 3, 4, 5: // Revenue, COGS, Discount
-				f.SetCellStyle(itemSheet, cell, cell, currencyStyle)
+				f.SetCellStyle(itemSheet, cell, cell, styles.Currency)
 			case 6: // Profit
-				f.SetCellStyle(itemSheet, cell, cell, profitStyle)
+				f.SetCellStyle(itemSheet, cell, cell, styles.Emphasis)
 			case 7: // Margin
-				f.SetCellStyle(itemSheet, cell, cell, marginStyle)
+				f.SetCellStyle(itemSheet, cell, cell, styles.Percent)
 			}
 		}
 
@@ -376,18 +595,6 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 
 	// Total row
 	totalRow := len(rows) + 2
-	totalStyle, _ := f.NewStyle(&excelize.Style{
-		Font:   &excelize.Font{Bold: true},
-		NumFmt: 3,
-		Fill:   excelize.Fill{Type: "pattern", Color: []string{"D9E1F2"}, Pattern: 1},
-		Border: []excelize.Border{
-			{Type: "left", Color: "000000", Style: 2},
-			{Type: "right", Color: "000000", Style: 2},
-			{Type: "top", Color: "000000", Style: 2},
-			{Type: "bottom", Color: "000000", Style: 2},
-		},
-	})
-
 	totalCells := map[string]interface{}{
 		"A": "TOTAL", "B": "", "C": grandQty,
 		"D": grandRevenue, "E": grandCogs, "F": grandDiscount, "G": grandProfit,
@@ -395,52 +602,24 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 	for col, val := range totalCells {
 		cell := fmt.Sprintf("%s%d", col, totalRow)
 		f.SetCellValue(itemSheet, cell, val)
-		f.SetCellStyle(itemSheet, cell, cell, totalStyle)
+		f.SetCellStyle(itemSheet, cell, cell, styles.Total)
 	}
 	grandMargin := 0.0
 	if grandRevenue > 0 {
 		grandMargin = float64(grandProfit) / float64(grandRevenue) * 100
 	}
-	marginTotalStyle, _ := f.NewStyle(&excelize.Style{
-		Font:   &excelize.Font{Bold: true},
-		NumFmt: 2,
-		Fill:   excelize.Fill{Type: "pattern", Color: []string{"D9E1F2"}, Pattern: 1},
-		Border: []excelize.Border{
-			{Type: "left", Color: "000000", Style: 2},
-			{Type: "right", Color: "000000", Style: 2},
-			{Type: "top", Color: "000000", Style: 2},
-			{Type: "bottom", Color: "000000", Style: 2},
-		},
-	})
 	f.SetCellValue(itemSheet, fmt.Sprintf("H%d", totalRow), grandMargin)
-	f.SetCellStyle(itemSheet, fmt.Sprintf("H%d", totalRow), fmt.Sprintf("H%d", totalRow), marginTotalStyle)
+	f.SetCellStyle(itemSheet, fmt.Sprintf("H%d", totalRow), fmt.Sprintf("H%d", totalRow), styles.TotalPercent)
 
 	// ── Sheet 2: Summary ──────────────────────────────────────────────────────
 	summarySheet := "Summary"
 	f.NewSheet(summarySheet)
 
-	labelStyle, _ := f.NewStyle(&excelize.Style{
-		Font:      &excelize.Font{Bold: true},
-		Fill:      excelize.Fill{Type: "pattern", Color: []string{"EBF0FA"}, Pattern: 1},
-		Alignment: &excelize.Alignment{Horizontal: "left"},
-	})
-	valueStyle, _ := f.NewStyle(&excelize.Style{
-		NumFmt:    3,
-		Alignment: &excelize.Alignment{Horizontal: "right"},
-	})
-	valueTextStyle, _ := f.NewStyle(&excelize.Style{
-		Alignment: &excelize.Alignment{Horizontal: "right"},
-	})
-	titleStyle, _ := f.NewStyle(&excelize.Style{
-		Font:      &excelize.Font{Bold: true, Size: 14, Color: "1F3864"},
-		Alignment: &excelize.Alignment{Horizontal: "left"},
-	})
-
 	f.SetColWidth(summarySheet, "A", "A", 28)
 	f.SetColWidth(summarySheet, "B", "B", 22)
 
 	f.SetCellValue(summarySheet, "A1", "Profit Report")
-	f.SetCellStyle(summarySheet, "A1", "A1", titleStyle)
+	f.SetCellStyle(summarySheet, "A1", "A1", styles.Title)
 	f.MergeCell(summarySheet, "A1", "B1")
 
 	generatedAt := time.Now().Format("02 Jan 2006 15:04:05")
@@ -465,12 +644,12 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 		labelCell := fmt.Sprintf("A%d", row)
 		valueCell := fmt.Sprintf("B%d", row)
 		f.SetCellValue(summarySheet, labelCell, sr[0])
-		f.SetCellStyle(summarySheet, labelCell, labelCell, labelStyle)
+		f.SetCellStyle(summarySheet, labelCell, labelCell, styles.Label)
 		f.SetCellValue(summarySheet, valueCell, sr[1])
 		if _, ok := sr[1].(int); ok {
-			f.SetCellStyle(summarySheet, valueCell, valueCell, valueStyle)
+			f.SetCellStyle(summarySheet, valueCell, valueCell, styles.Currency)
 		} else {
-			f.SetCellStyle(summarySheet, valueCell, valueCell, valueTextStyle)
+			f.SetCellStyle(summarySheet, valueCell, valueCell, styles.Text)
 		}
 	}
 
@@ -511,28 +690,20 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 	f.SetColWidth(summarySheet, "D", "D", 18)
 	f.SetColWidth(summarySheet, "E", "E", 18)
 
-	pieHeaderStyle, _ := f.NewStyle(&excelize.Style{
-		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
-		Fill:      excelize.Fill{Type: "pattern", Color: []string{"4472C4"}, Pattern: 1},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
-	})
 	f.SetCellValue(summarySheet, "D2", "Component")
 	f.SetCellValue(summarySheet, "E2", "Amount (Rp)")
-	f.SetCellStyle(summarySheet, "D2", "E2", pieHeaderStyle)
+	f.SetCellStyle(summarySheet, "D2", "E2", styles.Header)
 
 	pieData := [][]interface{}{
 		{"COGS", grandCogs},
 		{"Discount", grandDiscount},
 		{"Net Profit", grandProfit},
 	}
-	pieCurrencyStyle, _ := f.NewStyle(&excelize.Style{
-		NumFmt: 3, // #,##0
-	})
 	for i, pd := range pieData {
 		row := i + 3
 		f.SetCellValue(summarySheet, fmt.Sprintf("D%d", row), pd[0])
 		f.SetCellValue(summarySheet, fmt.Sprintf("E%d", row), pd[1])
-		f.SetCellStyle(summarySheet, fmt.Sprintf("E%d", row), fmt.Sprintf("E%d", row), pieCurrencyStyle)
+		f.SetCellStyle(summarySheet, fmt.Sprintf("E%d", row), fmt.Sprintf("E%d", row), styles.Currency)
 	}
 
 	f.AddChart(summarySheet, "D7", &excelize.Chart{
@@ -559,6 +730,45 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 		Dimension: excelize.ChartDimension{Width: 400, Height: 300},
 	})
 
+	// ── Sheet 3: Per Category ─────────────────────────────────────────────────
+	categorySheet := "Per Category"
+	f.NewSheet(categorySheet)
+
+	categoryTable := make([][]interface{}, 0, len(categoryRows))
+	for i, row := range categoryRows {
+		categoryTable = append(categoryTable, []interface{}{
+			i + 1, row.CategoryName, row.TotalQuantity, row.TotalRevenue, row.TotalDiscount, row.TotalProfit, row.RevenueShare,
+		})
+	}
+	writeExcelTable(f, categorySheet,
+		[]string{"#", "Category", "Qty Sold", "Revenue (Rp)", "Discount (Rp)", "Profit (Rp)", "Revenue Share (%)"},
+		[]float64{5, 30, 12, 18, 18, 18, 18},
+		categoryTable,
+		map[int]int{3: styles.Currency, 4: styles.Currency, 5: styles.Currency, 6: styles.Percent},
+		styles.Header,
+	)
+
+	// ── Sheet 4: Store Comparison ─────────────────────────────────────────────
+	if storeId == 0 {
+		storeSheet := "Store Comparison"
+		f.NewSheet(storeSheet)
+
+		storeTable := make([][]interface{}, 0, len(storeRows))
+		for i, row := range storeRows {
+			storeTable = append(storeTable, []interface{}{
+				i + 1, row.StoreName, row.SumTransactions, row.SumTotalQuantity, row.SumTotalAmount, row.SumDiscountAmount, row.SumProfit,
+				row.RevenueShare, row.TransactionShare, row.ProfitShare,
+			})
+		}
+		writeExcelTable(f, storeSheet,
+			[]string{"#", "Store", "Transactions", "Qty Sold", "Revenue (Rp)", "Discount (Rp)", "Profit (Rp)", "Revenue Share (%)", "Transaction Share (%)", "Profit Share (%)"},
+			[]float64{5, 30, 14, 12, 18, 18, 18, 18, 20, 16},
+			storeTable,
+			map[int]int{4: styles.Currency, 5: styles.Currency, 6: styles.Currency, 7: styles.Percent, 8: styles.Percent, 9: styles.Percent},
+			styles.Header,
+		)
+	}
+
 	buf, err := f.WriteToBuffer()
 	if err != nil {
 		return nil, fmt.Errorf("failed to write excel buffer: %w", err)
@@ -567,16 +777,101 @@ func (service *OrderItemServiceImpl) ExportProfitExcel(tenantId int, storeId int
 	return buf.Bytes(), nil
 }
 
+/*
+writeExcelTable write header at row 1 and the rows below it,
+columnStyles map the column index (0 based) to the style of its cells
+*/
+func writeExcelTable(f *excelize.File, sheet string, headers []string, colWidths []float64, rows [][]interface{}, columnStyles map[int]int, headerStyle int) {
+	for i, h := range headers {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		cell := fmt.Sprintf("%s1", col)
+		f.SetCellValue(sheet, cell, h)
+		f.SetCellStyle(sheet, cell, cell, headerStyle)
+		f.SetColWidth(sheet, col, col, colWidths[i])
+	}
+	f.SetRowHeight(sheet, 1, 20)
+
+	for i, values := range rows {
+		for j, val := range values {
+			col, _ := excelize.ColumnNumberToName(j + 1)
+			cell := fmt.Sprintf("%s%d", col, i+2)
+			f.SetCellValue(sheet, cell, val)
+			if style, ok := columnStyles[j]; ok {
+				f.SetCellStyle(sheet, cell, cell, style)
+			}
+		}
+	}
+}
+
+// GetCategoryReport implements [OrderItemService].
+func (service *OrderItemServiceImpl) GetCategoryReport(tenantId int, storeId int, dateFilter *query.DateFilter) ([]*repository.CategoryReportRow, error) {
+	if err := validateReportFilter(tenantId, storeId, 0, dateFilter); err != nil {
+		return nil, err
+	}
+
+	rows, err := service.Repository.GetCategoryReport(tenantId, storeId, dateFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	totalRevenue := 0
+	for _, row := range rows {
+		totalRevenue += row.TotalRevenue
+	}
+	for _, row := range rows {
+		row.RevenueShare = percentOf(row.TotalRevenue, totalRevenue)
+	}
+
+	return rows, nil
+}
+
+// GetStoreComparison implements [OrderItemService].
+func (service *OrderItemServiceImpl) GetStoreComparison(tenantId int, dateFilter *query.DateFilter) ([]*repository.StoreComparisonRow, error) {
+	if err := validateReportFilter(tenantId, 0, 0, dateFilter); err != nil {
+		return nil, err
+	}
+
+	rows, err := service.Repository.GetStoreComparison(tenantId, dateFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalRevenue, totalTransactions, totalProfit int
+	for _, row := range rows {
+		totalRevenue += row.SumTotalAmount
+		totalTransactions += row.SumTransactions
+		totalProfit += row.SumProfit
+	}
+	for _, row := range rows {
+		row.RevenueShare = percentOf(row.SumTotalAmount, totalRevenue)
+		row.TransactionShare = percentOf(row.SumTransactions, totalTransactions)
+		row.ProfitShare = percentOf(row.SumProfit, totalProfit)
+	}
+
+	return rows, nil
+}
+
+// part / total in percent (0 - 100), 0 when total is not positive
+func percentOf(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
 // DeleteInvoice implements [OrderItemService].
-func (service *OrderItemServiceImpl) DeleteInvoice(orderItemId int, tenantId int) error {
+func (service *OrderItemServiceImpl) DeleteInvoice(orderItemId int, tenantId int, userId int) error {
 	if orderItemId <= 0 {
 		return fmt.Errorf("invalid order item id: %d", orderItemId)
 	}
 	if tenantId <= 0 {
 		return fmt.Errorf("invalid tenant id: %d", tenantId)
 	}
+	if userId <= 0 {
+		return fmt.Errorf("invalid user id: %d", userId)
+	}
 
-	err := service.Repository.DeleteInvoice(orderItemId, tenantId)
+	err := service.Repository.DeleteInvoice(orderItemId, tenantId, userId)
 	if err != nil {
 		return err
 	}
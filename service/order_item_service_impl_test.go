@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"cashier-api/helper/query"
 	"cashier-api/model"
 	"cashier-api/repository"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/xuri/excelize/v2"
 )
 
 func TestOrderItemServiceImpl(t *testing.T) {
@@ -46,10 +48,10 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			expectedCount := 1
 
 			// Mock expects page-1 (0-based indexing)
-			orderItemRepo.Mock.On("Get", TENANT_ID, STORE_ID, LIMIT, 0, filters, (*query.DateFilter)(nil)).
+			orderItemRepo.Mock.On("Get", TENANT_ID, STORE_ID, 0, LIMIT, 0, filters, (*query.DateFilter)(nil)).
 				Return(expectedItems, expectedCount, nil)
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, filters, nil)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, filters, nil)
 
 			assert.NoError(t, err)
 			assert.Equal(t, expectedCount, count)
@@ -60,7 +62,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 			// Invalid tenant id
-			orderItems, count, err := orderItemService.Get(0, STORE_ID, LIMIT, PAGE, nil, nil)
+			orderItems, count, err := orderItemService.Get(0, STORE_ID, 0, LIMIT, PAGE, nil, nil)
 			assert.Error(t, err)
 			assert.Equal(t, 0, count)
 			assert.Nil(t, orderItems)
@@ -71,27 +73,27 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 
 			// Test invalid limit (0)
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, PAGE, nil, nil)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, 0, PAGE, nil, nil)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "Limit could not less then 1")
 			assert.Equal(t, 0, count)
 			assert.Nil(t, orderItems)
 
 			// Test invalid page (0)
-			orderItems, count, err = orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, 0, nil, nil)
+			orderItems, count, err = orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, 0, nil, nil)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "page could not less then 1")
 			assert.Equal(t, 0, count)
 			assert.Nil(t, orderItems)
 
 			// Negative limit
-			orderItems, count, err = orderItemService.Get(TENANT_ID, STORE_ID, -5, PAGE, nil, nil)
+			orderItems, count, err = orderItemService.Get(TENANT_ID, STORE_ID, 0, -5, PAGE, nil, nil)
 			assert.Error(t, err)
 			assert.Equal(t, 0, count)
 			assert.Nil(t, orderItems)
 
 			// negative page
-			orderItems, count, err = orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, -5, nil, nil)
+			orderItems, count, err = orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, -5, nil, nil)
 			assert.Error(t, err)
 			assert.Equal(t, 0, count)
 			assert.Nil(t, orderItems)
@@ -101,7 +103,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, 0, nil, nil)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, 0, nil, nil)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "page could not less then 1")
@@ -113,7 +115,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, -1, nil, nil)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, -1, nil, nil)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "page could not less then 1")
@@ -132,7 +134,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 				EndDate:   &endDate,
 			}
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, nil, dateFilter)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, nil, dateFilter)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "Start date")
@@ -150,7 +152,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 				StartDate: &startDate,
 			}
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, nil, dateFilter)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, nil, dateFilter)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "Invalid start date timestamp")
@@ -167,7 +169,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 				EndDate: &endDate,
 			}
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, nil, dateFilter)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, nil, dateFilter)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "Invalid emd date timestamp")
@@ -184,7 +186,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 				StartDate: &startDate,
 			}
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, nil, dateFilter)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, nil, dateFilter)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "Start date is too far in the future")
@@ -201,7 +203,7 @@ func TestOrderItemServiceImpl(t *testing.T) {
 				EndDate: &endDate,
 			}
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, nil, dateFilter)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, nil, dateFilter)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "End date is too far in the future")
@@ -223,10 +225,10 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			expectedItems := []*model.OrderItem{}
 			expectedCount := 3
 
-			orderItemRepo.Mock.On("Get", TENANT_ID, STORE_ID, LIMIT, 0, ([]*query.QueryFilter)(nil), dateFilter).
+			orderItemRepo.Mock.On("Get", TENANT_ID, STORE_ID, 0, LIMIT, 0, ([]*query.QueryFilter)(nil), dateFilter).
 				Return(expectedItems, expectedCount, nil)
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, nil, dateFilter)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, nil, dateFilter)
 
 			assert.NoError(t, err)
 			assert.Equal(t, expectedCount, count)
@@ -240,10 +242,10 @@ func TestOrderItemServiceImpl(t *testing.T) {
 
 			expectedError := errors.New("database connection failed")
 
-			orderItemRepo.Mock.On("Get", TENANT_ID, STORE_ID, LIMIT, 0, ([]*query.QueryFilter)(nil), (*query.DateFilter)(nil)).
+			orderItemRepo.Mock.On("Get", TENANT_ID, STORE_ID, 0, LIMIT, 0, ([]*query.QueryFilter)(nil), (*query.DateFilter)(nil)).
 				Return(([]*model.OrderItem)(nil), 0, expectedError)
 
-			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE, nil, nil)
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, 0, LIMIT, PAGE, nil, nil)
 
 			assert.Error(t, err)
 			assert.Equal(t, expectedError, err)
@@ -251,6 +253,34 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			assert.Nil(t, orderItems)
 			orderItemRepo.Mock.AssertExpectations(t)
 		})
+
+		t.Run("FilterByCustomer", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+			customerId := 7
+
+			expectedItems := []*model.OrderItem{
+				{Id: 1, TotalAmount: 1000, CustomerId: &customerId, StoreId: STORE_ID, TenantId: TENANT_ID},
+			}
+			orderItemRepo.Mock.On("Get", TENANT_ID, STORE_ID, customerId, LIMIT, 0, ([]*query.QueryFilter)(nil), (*query.DateFilter)(nil)).
+				Return(expectedItems, 1, nil)
+
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, customerId, LIMIT, PAGE, nil, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.Equal(t, &customerId, orderItems[0].CustomerId)
+		})
+
+		t.Run("InvalidCustomerId", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			orderItems, count, err := orderItemService.Get(TENANT_ID, STORE_ID, -1, LIMIT, PAGE, nil, nil)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "Invalid customer id")
+			assert.Equal(t, 0, count)
+			assert.Nil(t, orderItems)
+		})
 	})
 
 	t.Run("FindById", func(t *testing.T) {
@@ -403,6 +433,195 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			assert.Nil(t, transactionDataReturn)
 		})
 
+		t.Run("InvalidCustomerId", func(t *testing.T) {
+			invalidParams := &repository.CreateTransactionParams{
+				UserId:     USER_ID,
+				TenantId:   TENANT_ID,
+				StoreId:    STORE_ID,
+				CustomerId: -1,
+			}
+
+			transactionDataReturn, err := orderItemService.Transactions(invalidParams)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "Invalid customer id")
+			assert.Nil(t, transactionDataReturn)
+		})
+
+		t.Run("RedeemPoints", func(t *testing.T) {
+			newParams := func() *repository.CreateTransactionParams {
+				return &repository.CreateTransactionParams{
+					PurchasedPrice: 8_000,
+					TotalQuantity:  1,
+					TotalAmount:    10_000,
+					DiscountAmount: 0,
+					SubTotal:       10_000,
+
+					Items: []*model.PurchasedItem{
+						{
+							Quantity:           1,
+							StorePriceSnapshot: 10_000,
+							DiscountAmount:     0,
+							TotalAmount:        10_000,
+							ItemId:             1,
+							ItemNameSnapshot:   "Item Name Snapshot",
+						},
+					},
+
+					UserId:         USER_ID,
+					TenantId:       TENANT_ID,
+					StoreId:        STORE_ID,
+					CustomerId:     1,
+					RedeemPoints:   20,
+					PointsDiscount: 2_000,
+				}
+			}
+
+			t.Run("PaidWithPointsDiscount", func(t *testing.T) {
+				params := newParams()
+
+				expectedTransactionDataReturn := &repository.TransactionDataReturn{
+					CreatedOrderItemId: 2,
+					TotalAmount:        8_000,
+					PointsRedeemed:     20,
+				}
+				orderItemRepo.Mock.On("Transactions", params).Return(expectedTransactionDataReturn, nil).Once()
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.NoError(t, err)
+				assert.Equal(t, 20, transactionDataReturn.PointsRedeemed)
+				assert.Equal(t, 8_000, transactionDataReturn.TotalAmount)
+			})
+
+			t.Run("InsufficientPayment", func(t *testing.T) {
+				params := newParams()
+				params.PurchasedPrice = 7_999
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Equal(t, "Insufficient payment: need 8000, got 7999", err.Error())
+				assert.Nil(t, transactionDataReturn)
+			})
+
+			t.Run("AnonymousSale", func(t *testing.T) {
+				params := newParams()
+				params.CustomerId = 0
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Equal(t, "Customer is required to redeem points", err.Error())
+				assert.Nil(t, transactionDataReturn)
+			})
+
+			t.Run("NegativePoints", func(t *testing.T) {
+				params := newParams()
+				params.RedeemPoints = -1
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Nil(t, transactionDataReturn)
+			})
+
+			t.Run("DiscountWithoutPoints", func(t *testing.T) {
+				params := newParams()
+				params.RedeemPoints = 0
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Equal(t, "Points discount given without redeemed points", err.Error())
+				assert.Nil(t, transactionDataReturn)
+			})
+
+			t.Run("DiscountExceedTotal", func(t *testing.T) {
+				params := newParams()
+				params.PointsDiscount = 10_001
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "exceed the total amount")
+				assert.Nil(t, transactionDataReturn)
+			})
+		})
+
+		t.Run("GiftCardPayment", func(t *testing.T) {
+			newParams := func() *repository.CreateTransactionParams {
+				return &repository.CreateTransactionParams{
+					PurchasedPrice: 4_000,
+					TotalQuantity:  1,
+					TotalAmount:    10_000,
+					DiscountAmount: 0,
+					SubTotal:       10_000,
+
+					Items: []*model.PurchasedItem{
+						{
+							Quantity:           1,
+							StorePriceSnapshot: 10_000,
+							DiscountAmount:     0,
+							TotalAmount:        10_000,
+							ItemId:             1,
+							ItemNameSnapshot:   "Item Name Snapshot",
+						},
+					},
+
+					UserId:         USER_ID,
+					TenantId:       TENANT_ID,
+					StoreId:        STORE_ID,
+					GiftCardCode:   "abcd-2345-efgh-6789",
+					GiftCardAmount: 6_000,
+				}
+			}
+
+			t.Run("CashAndGiftCard", func(t *testing.T) {
+				params := newParams()
+
+				orderItemRepo.Mock.On("Transactions", mock.MatchedBy(func(p *repository.CreateTransactionParams) bool {
+					return p.GiftCardCode == "ABCD2345EFGH6789" && p.GiftCardAmount == 6_000
+				})).Return(&repository.TransactionDataReturn{CreatedOrderItemId: 3, GiftCardAmount: 6_000}, nil).Once()
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.NoError(t, err)
+				assert.Equal(t, 6_000, transactionDataReturn.GiftCardAmount)
+			})
+
+			t.Run("InsufficientPayment", func(t *testing.T) {
+				params := newParams()
+				params.PurchasedPrice = 3_999
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Equal(t, "Insufficient payment: need 10000, got 9999", err.Error())
+				assert.Nil(t, transactionDataReturn)
+			})
+
+			t.Run("ExceedAmountDue", func(t *testing.T) {
+				params := newParams()
+				params.GiftCardAmount = 10_001
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "exceed the amount due")
+				assert.Nil(t, transactionDataReturn)
+			})
+
+			t.Run("MissingCode", func(t *testing.T) {
+				params := newParams()
+				params.GiftCardCode = " "
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Equal(t, "Gift card code is required to pay with gift card", err.Error())
+				assert.Nil(t, transactionDataReturn)
+			})
+
+			t.Run("NegativeAmount", func(t *testing.T) {
+				params := newParams()
+				params.GiftCardAmount = -1
+
+				transactionDataReturn, err := orderItemService.Transactions(params)
+				assert.Error(t, err)
+				assert.Nil(t, transactionDataReturn)
+			})
+		})
+
 		t.Run("EmptyTransactions", func(t *testing.T) {
 			invalidParams := &repository.CreateTransactionParams{
 				UserId:   USER_ID,
@@ -500,6 +719,36 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			assert.ErrorContains(t, err, "total mismatch")
 		})
 
+		t.Run("TaxExceedLineTotal", func(t *testing.T) {
+			expectedParams := &repository.CreateTransactionParams{
+				PurchasedPrice: 10_000,
+				TotalQuantity:  1,
+				TotalAmount:    10_000,
+				DiscountAmount: 0,
+				SubTotal:       10_000,
+
+				Items: []*model.PurchasedItem{
+					{
+						Quantity:           1,
+						StorePriceSnapshot: 10_000,
+						TotalAmount:        10_000,
+						TaxAmount:          10_001, // Tax is included in the line total
+						ItemId:             1,
+						ItemNameSnapshot:   "Item Name Snapshot",
+					},
+				},
+
+				UserId:   USER_ID,
+				TenantId: TENANT_ID,
+				StoreId:  STORE_ID,
+			}
+
+			transactionDataReturn, err := orderItemService.Transactions(expectedParams)
+			assert.Error(t, err)
+			assert.Nil(t, transactionDataReturn)
+			assert.ErrorContains(t, err, "should be between 0 and the line total")
+		})
+
 		t.Run("TotalQuantityMismatch", func(t *testing.T) {
 			expectedParams := &repository.CreateTransactionParams{
 				PurchasedPrice: 10_000,
@@ -836,9 +1085,9 @@ func TestOrderItemServiceImpl(t *testing.T) {
 
 			const ORDER_ITEM_ID = 1
 
-			orderItemRepo.Mock.On("DeleteInvoice", ORDER_ITEM_ID, TENANT_ID).Return(nil)
+			orderItemRepo.Mock.On("DeleteInvoice", ORDER_ITEM_ID, TENANT_ID, USER_ID).Return(nil)
 
-			err := orderItemService.DeleteInvoice(ORDER_ITEM_ID, TENANT_ID)
+			err := orderItemService.DeleteInvoice(ORDER_ITEM_ID, TENANT_ID, USER_ID)
 
 			assert.NoError(t, err)
 			orderItemRepo.Mock.AssertExpectations(t)
@@ -848,45 +1097,56 @@ func TestOrderItemServiceImpl(t *testing.T) {
 			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 
-			err := orderItemService.DeleteInvoice(0, TENANT_ID)
+			err := orderItemService.DeleteInvoice(0, TENANT_ID, USER_ID)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid order item id")
 			// Repository should never be called
-			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything)
+			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything, mock.Anything)
 		})
 
 		t.Run("InvalidOrderItemId_Negative", func(t *testing.T) {
 			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 
-			err := orderItemService.DeleteInvoice(-1, TENANT_ID)
+			err := orderItemService.DeleteInvoice(-1, TENANT_ID, USER_ID)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid order item id")
-			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything)
+			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything, mock.Anything)
 		})
 
 		t.Run("InvalidTenantId_Zero", func(t *testing.T) {
 			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 
-			err := orderItemService.DeleteInvoice(1, 0)
+			err := orderItemService.DeleteInvoice(1, 0, USER_ID)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid tenant id")
-			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything)
+			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything, mock.Anything)
 		})
 
 		t.Run("InvalidTenantId_Negative", func(t *testing.T) {
 			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
 			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
 
-			err := orderItemService.DeleteInvoice(1, -1)
+			err := orderItemService.DeleteInvoice(1, -1, USER_ID)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid tenant id")
-			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything)
+			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("InvalidUserId", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			err := orderItemService.DeleteInvoice(1, TENANT_ID, 0)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid user id")
+			orderItemRepo.Mock.AssertNotCalled(t, "DeleteInvoice", mock.Anything, mock.Anything, mock.Anything)
 		})
 
 		t.Run("NotFound", func(t *testing.T) {
@@ -895,10 +1155,10 @@ func TestOrderItemServiceImpl(t *testing.T) {
 
 			const ORDER_ITEM_ID = 999999
 
-			orderItemRepo.Mock.On("DeleteInvoice", ORDER_ITEM_ID, TENANT_ID).
+			orderItemRepo.Mock.On("DeleteInvoice", ORDER_ITEM_ID, TENANT_ID, USER_ID).
 				Return(fmt.Errorf("order item %d not found", ORDER_ITEM_ID))
 
-			err := orderItemService.DeleteInvoice(ORDER_ITEM_ID, TENANT_ID)
+			err := orderItemService.DeleteInvoice(ORDER_ITEM_ID, TENANT_ID, USER_ID)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "not found")
@@ -911,14 +1171,370 @@ func TestOrderItemServiceImpl(t *testing.T) {
 
 			const ORDER_ITEM_ID = 1
 
-			orderItemRepo.Mock.On("DeleteInvoice", ORDER_ITEM_ID, TENANT_ID).
+			orderItemRepo.Mock.On("DeleteInvoice", ORDER_ITEM_ID, TENANT_ID, USER_ID).
 				Return(errors.New("database connection failed"))
 
-			err := orderItemService.DeleteInvoice(ORDER_ITEM_ID, TENANT_ID)
+			err := orderItemService.DeleteInvoice(ORDER_ITEM_ID, TENANT_ID, USER_ID)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "database connection failed")
 			orderItemRepo.Mock.AssertExpectations(t)
 		})
 	})
+
+	t.Run("PlaceOrderItem", func(t *testing.T) {
+		newParams := func() *repository.CreateTransactionParams {
+			return &repository.CreateTransactionParams{
+				TotalQuantity: 1,
+				TotalAmount:   10_000,
+				SubTotal:      10_000,
+				Items: []*model.PurchasedItem{
+					{Quantity: 1, StorePriceSnapshot: 10_000, TotalAmount: 10_000, ItemId: 1, ItemNameSnapshot: "Latte"},
+				},
+				UserId:   USER_ID,
+				TenantId: TENANT_ID,
+				StoreId:  STORE_ID,
+			}
+		}
+
+		t.Run("PayLater", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			params := newParams()
+			orderItemRepo.Mock.On("Transactions", mock.MatchedBy(func(p *repository.CreateTransactionParams) bool {
+				return p.Status == model.OrderStatusPlaced && p.PurchasedPrice == 0
+			})).Return(&repository.TransactionDataReturn{CreatedOrderItemId: 1}, nil)
+
+			placed, err := orderItemService.PlaceOrderItem(params)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, placed.CreatedOrderItemId)
+			orderItemRepo.Mock.AssertExpectations(t)
+		})
+
+		t.Run("TransactionsNeverPlaced", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			params := newParams()
+			params.PurchasedPrice = 10_000
+			params.Status = model.OrderStatusPlaced
+			orderItemRepo.Mock.On("Transactions", mock.MatchedBy(func(p *repository.CreateTransactionParams) bool {
+				return p.Status == ""
+			})).Return(&repository.TransactionDataReturn{CreatedOrderItemId: 1}, nil)
+
+			_, err := orderItemService.Transactions(params)
+			assert.NoError(t, err)
+			orderItemRepo.Mock.AssertExpectations(t)
+		})
+
+		t.Run("InvalidItems", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			params := newParams()
+			params.TotalAmount = 9_000
+
+			placed, err := orderItemService.PlaceOrderItem(params)
+			assert.ErrorContains(t, err, "Total amount mismatch")
+			assert.Nil(t, placed)
+			orderItemRepo.Mock.AssertNotCalled(t, "Transactions", mock.Anything)
+		})
+
+		t.Run("NegativePurchasedPrice", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			params := newParams()
+			params.PurchasedPrice = -1
+
+			_, err := orderItemService.PlaceOrderItem(params)
+			assert.Error(t, err)
+			orderItemRepo.Mock.AssertNotCalled(t, "Transactions", mock.Anything)
+		})
+	})
+
+	t.Run("UpdateOrderStatus", func(t *testing.T) {
+		t.Run("NormalUpdate", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			params := &repository.UpdateOrderStatusParams{
+				OrderItemId: 1, TenantId: TENANT_ID, UserId: USER_ID,
+				Status: model.OrderStatusCompleted, PurchasedPrice: 10_000,
+			}
+			orderItemRepo.Mock.On("UpdateStatus", params).
+				Return(&model.OrderItem{Id: 1, Status: model.OrderStatusCompleted}, nil)
+
+			orderItem, err := orderItemService.UpdateOrderStatus(params)
+			assert.NoError(t, err)
+			assert.Equal(t, model.OrderStatusCompleted, orderItem.Status)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			invalidParams := []*repository.UpdateOrderStatusParams{
+				{TenantId: TENANT_ID, UserId: USER_ID, Status: model.OrderStatusReady},
+				{OrderItemId: 1, TenantId: TENANT_ID, Status: model.OrderStatusReady},
+				{OrderItemId: 1, TenantId: TENANT_ID, UserId: USER_ID, Status: "SERVED"},
+				{OrderItemId: 1, TenantId: TENANT_ID, UserId: USER_ID, Status: model.OrderStatusPlaced},
+				{OrderItemId: 1, TenantId: TENANT_ID, UserId: USER_ID, Status: model.OrderStatusReady, PurchasedPrice: 10_000},
+				{OrderItemId: 1, TenantId: TENANT_ID, UserId: USER_ID, Status: model.OrderStatusCompleted, PurchasedPrice: -1},
+			}
+			for _, params := range invalidParams {
+				orderItem, err := orderItemService.UpdateOrderStatus(params)
+				assert.Error(t, err)
+				assert.Nil(t, orderItem)
+			}
+			orderItemRepo.Mock.AssertNotCalled(t, "UpdateStatus", mock.Anything)
+		})
+	})
+
+	t.Run("GetOpenOrders", func(t *testing.T) {
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+		t.Run("NormalGet", func(t *testing.T) {
+			// Page is decreased by 1
+			orderItemRepo.Mock.On("GetOpenOrders", TENANT_ID, STORE_ID, LIMIT, PAGE-1).
+				Return([]*model.OrderItem{{Id: 1, Status: model.OrderStatusPlaced}}, 1, nil)
+
+			orderItems, count, err := orderItemService.GetOpenOrders(TENANT_ID, STORE_ID, LIMIT, PAGE)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.Len(t, orderItems, 1)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			_, _, err := orderItemService.GetOpenOrders(TENANT_ID, 0, LIMIT, PAGE)
+			assert.Error(t, err)
+
+			_, _, err = orderItemService.GetOpenOrders(TENANT_ID, STORE_ID, 0, PAGE)
+			assert.Error(t, err)
+
+			_, _, err = orderItemService.GetOpenOrders(TENANT_ID, STORE_ID, LIMIT, 0)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("GetStatusHistory", func(t *testing.T) {
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+		orderItemRepo.Mock.On("GetStatusHistory", 1, TENANT_ID).
+			Return([]*model.OrderStatusHistory{{OrderItemId: 1, ToStatus: model.OrderStatusPlaced, UserId: USER_ID}}, nil)
+
+		history, err := orderItemService.GetStatusHistory(1, TENANT_ID)
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+
+		_, err = orderItemService.GetStatusHistory(0, TENANT_ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("GetSalesSeries", func(t *testing.T) {
+		// 2024-03-01 00:00:00 - 2024-03-01 03:00:00 UTC
+		start, end := int64(1709251200), int64(1709262000)
+		dateFilter := &query.DateFilter{StartDate: &start, EndDate: &end}
+
+		t.Run("ZeroFilledHourly", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			orderItemRepo.Mock.On("GetTenantTimezone", TENANT_ID).Return("Asia/Jakarta", nil)
+			orderItemRepo.Mock.On("GetSalesSeries", TENANT_ID, 0, repository.SalesBucketHour, "Asia/Jakarta", dateFilter).
+				Return([]*repository.SalesSeriesPoint{
+					{
+						// Local wall clock from PostgreSQL, 08:00 at Jakarta
+						BucketStart: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+						SalesReport: repository.SalesReport{SumTotalAmount: 50_000, SumProfit: 10_000, SumTransactions: 2},
+					},
+				}, nil)
+
+			series, err := orderItemService.GetSalesSeries(TENANT_ID, 0, repository.SalesBucketHour, dateFilter)
+			assert.NoError(t, err)
+			assert.Equal(t, "Asia/Jakarta", series.Timezone)
+			assert.Len(t, series.Points, 3)
+			assert.Equal(t, 7, series.Points[0].BucketStart.Hour())
+			assert.Equal(t, 0, series.Points[0].SumTransactions)
+			assert.Equal(t, 50_000, series.Points[1].SumTotalAmount)
+			assert.Equal(t, 10_000, series.Points[1].SumProfit)
+			assert.Equal(t, 0, series.Points[2].SumTotalAmount)
+		})
+
+		t.Run("WeeklyStartOnMonday", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			// Timezone not configured
+			orderItemRepo.Mock.On("GetTenantTimezone", TENANT_ID).Return("", nil)
+			orderItemRepo.Mock.On("GetSalesSeries", TENANT_ID, STORE_ID, repository.SalesBucketWeek, "UTC", dateFilter).
+				Return([]*repository.SalesSeriesPoint{}, nil)
+
+			series, err := orderItemService.GetSalesSeries(TENANT_ID, STORE_ID, repository.SalesBucketWeek, dateFilter)
+			assert.NoError(t, err)
+			assert.Len(t, series.Points, 1)
+			assert.Equal(t, time.Monday, series.Points[0].BucketStart.Weekday())
+			assert.Equal(t, 26, series.Points[0].BucketStart.Day()) // 2024-02-26
+		})
+
+		t.Run("DaylightSavingEnd", func(t *testing.T) {
+			// 2024-11-03 04:00 - 08:00 UTC, New York clock go back from 02:00 to 01:00
+			start, end := int64(1730606400), int64(1730620800)
+			dateFilter := &query.DateFilter{StartDate: &start, EndDate: &end}
+
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			orderItemRepo.Mock.On("GetTenantTimezone", TENANT_ID).Return("America/New_York", nil)
+			orderItemRepo.Mock.On("GetSalesSeries", TENANT_ID, 0, repository.SalesBucketHour, "America/New_York", dateFilter).
+				Return([]*repository.SalesSeriesPoint{}, nil)
+
+			series, err := orderItemService.GetSalesSeries(TENANT_ID, 0, repository.SalesBucketHour, dateFilter)
+			assert.NoError(t, err)
+			// 00:00, 01:00 (twice, 1 bucket), 02:00
+			assert.Len(t, series.Points, 3)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			_, err := orderItemService.GetSalesSeries(TENANT_ID, 0, "year", dateFilter)
+			assert.Error(t, err)
+
+			_, err = orderItemService.GetSalesSeries(TENANT_ID, 0, repository.SalesBucketDay, nil)
+			assert.Error(t, err)
+
+			_, err = orderItemService.GetSalesSeries(TENANT_ID, 0, repository.SalesBucketDay, &query.DateFilter{StartDate: &end, EndDate: &start})
+			assert.Error(t, err)
+
+			_, err = orderItemService.GetSalesSeries(0, 0, repository.SalesBucketDay, dateFilter)
+			assert.Error(t, err)
+			orderItemRepo.Mock.AssertNotCalled(t, "GetSalesSeries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("TooManyBuckets", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			// 1 year hourly
+			end := start + 365*24*60*60
+			orderItemRepo.Mock.On("GetTenantTimezone", TENANT_ID).Return("UTC", nil)
+
+			_, err := orderItemService.GetSalesSeries(TENANT_ID, 0, repository.SalesBucketHour, &query.DateFilter{StartDate: &start, EndDate: &end})
+			assert.ErrorContains(t, err, "Too many buckets")
+		})
+	})
+
+	t.Run("GetCategoryReport", func(t *testing.T) {
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+		orderItemRepo.Mock.On("GetCategoryReport", TENANT_ID, 0, (*query.DateFilter)(nil)).Return([]*repository.CategoryReportRow{
+			{CategoryId: 1, CategoryName: "Drink", TotalQuantity: 10, TotalRevenue: 7500, TotalProfit: 2500},
+			{CategoryId: 0, CategoryName: "Uncategorized", TotalQuantity: 2, TotalRevenue: 2500, TotalProfit: 500},
+		}, nil)
+
+		rows, err := orderItemService.GetCategoryReport(TENANT_ID, 0, nil)
+		assert.Nil(t, err)
+		assert.Len(t, rows, 2)
+		assert.InDelta(t, 75.0, rows[0].RevenueShare, 0.001)
+		assert.InDelta(t, 25.0, rows[1].RevenueShare, 0.001)
+
+		_, err = orderItemService.GetCategoryReport(0, 0, nil)
+		assert.Error(t, err)
+		_, err = orderItemService.GetCategoryReport(TENANT_ID, -1, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("GetStoreComparison", func(t *testing.T) {
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+		orderItemRepo.Mock.On("GetStoreComparison", TENANT_ID, (*query.DateFilter)(nil)).Return([]*repository.StoreComparisonRow{
+			{StoreId: 1, StoreName: "Main", SumTotalAmount: 6000, SumTransactions: 3, SumProfit: 1000},
+			{StoreId: 2, StoreName: "Branch", SumTotalAmount: 2000, SumTransactions: 1, SumProfit: 1000},
+			{StoreId: 3, StoreName: "New", SumTotalAmount: 0, SumTransactions: 0, SumProfit: 0},
+		}, nil)
+
+		rows, err := orderItemService.GetStoreComparison(TENANT_ID, nil)
+		assert.Nil(t, err)
+		assert.InDelta(t, 75.0, rows[0].RevenueShare, 0.001)
+		assert.InDelta(t, 75.0, rows[0].TransactionShare, 0.001)
+		assert.InDelta(t, 50.0, rows[1].ProfitShare, 0.001)
+		assert.Zero(t, rows[2].RevenueShare)
+
+		t.Run("NoSales", func(t *testing.T) {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+			orderItemRepo.Mock.On("GetStoreComparison", TENANT_ID, (*query.DateFilter)(nil)).Return([]*repository.StoreComparisonRow{
+				{StoreId: 1, StoreName: "Main"},
+			}, nil)
+
+			rows, err := orderItemService.GetStoreComparison(TENANT_ID, nil)
+			assert.Nil(t, err)
+			assert.Zero(t, rows[0].RevenueShare)
+		})
+	})
+
+	t.Run("ExportProfitExcel", func(t *testing.T) {
+		newRepo := func() *repository.OrderItemRepositoryMock {
+			orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+			orderItemRepo.Mock.On("GetTenantAndStoreName", TENANT_ID, mock.Anything).Return("Tenant", "All Stores", nil)
+			orderItemRepo.Mock.On("GetProfitReport", TENANT_ID, mock.Anything, (*query.DateFilter)(nil)).Return([]*repository.ProfitReportRow{
+				{ItemId: 1, ItemName: "Tea", TotalQuantity: 2, TotalRevenue: 2000, TotalCogs: 1000, TotalProfit: 1000},
+			}, nil)
+			orderItemRepo.Mock.On("GetCategoryReport", TENANT_ID, mock.Anything, (*query.DateFilter)(nil)).Return([]*repository.CategoryReportRow{
+				{CategoryId: 1, CategoryName: "Drink", TotalQuantity: 2, TotalRevenue: 2000, TotalProfit: 1000},
+			}, nil)
+			orderItemRepo.Mock.On("GetStoreComparison", TENANT_ID, (*query.DateFilter)(nil)).Return([]*repository.StoreComparisonRow{
+				{StoreId: STORE_ID, StoreName: "Main", SumTotalAmount: 2000, SumTransactions: 1, SumProfit: 1000},
+			}, nil)
+			return orderItemRepo
+		}
+
+		t.Run("AllStore", func(t *testing.T) {
+			orderItemService := NewOrderItemServiceImpl(newRepo())
+
+			xlsx, err := orderItemService.ExportProfitExcel(TENANT_ID, 0, nil)
+			assert.Nil(t, err)
+
+			f, err := excelize.OpenReader(bytes.NewReader(xlsx))
+			assert.Nil(t, err)
+			defer f.Close()
+			assert.Equal(t, []string{"Profit Per Item", "Summary", "Per Category", "Store Comparison"}, f.GetSheetList())
+
+			// Profit column stand out from the other amounts
+			profitStyleId, err := f.GetCellStyle("Profit Per Item", "G2")
+			assert.Nil(t, err)
+			profitStyle, err := f.GetStyle(profitStyleId)
+			assert.Nil(t, err)
+			assert.True(t, profitStyle.Font != nil && profitStyle.Font.Bold)
+			revenueStyleId, _ := f.GetCellStyle("Profit Per Item", "D2")
+			revenueStyle, _ := f.GetStyle(revenueStyleId)
+			assert.True(t, revenueStyle.Font == nil || !revenueStyle.Font.Bold)
+
+			category, _ := f.GetCellValue("Per Category", "B2")
+			assert.Equal(t, "Drink", category)
+			store, _ := f.GetCellValue("Store Comparison", "B2")
+			assert.Equal(t, "Main", store)
+		})
+
+		t.Run("SingleStore", func(t *testing.T) {
+			orderItemRepo := newRepo()
+			orderItemService := NewOrderItemServiceImpl(orderItemRepo)
+
+			xlsx, err := orderItemService.ExportProfitExcel(TENANT_ID, STORE_ID, nil)
+			assert.Nil(t, err)
+
+			f, err := excelize.OpenReader(bytes.NewReader(xlsx))
+			assert.Nil(t, err)
+			defer f.Close()
+			assert.Equal(t, []string{"Profit Per Item", "Summary", "Per Category"}, f.GetSheetList())
+			orderItemRepo.Mock.AssertNotCalled(t, "GetStoreComparison", mock.Anything, mock.Anything)
+		})
+	})
 }
@@ -17,8 +17,8 @@ func NewOrderItemServiceMock(mock *mock.Mock) OrderItemService {
 }
 
 // Get implements OrderItemService.
-func (service *OrderItemServiceMock) Get(tenantId int, storeId int, limit int, page int, filters []*query.QueryFilter, dateFilter *query.DateFilter) ([]*model.OrderItem, int, error) {
-	args := service.Mock.Called(tenantId, storeId, limit, page, filters, dateFilter)
+func (service *OrderItemServiceMock) Get(tenantId int, storeId int, customerId int, limit int, page int, filters []*query.QueryFilter, dateFilter *query.DateFilter) ([]*model.OrderItem, int, error) {
+	args := service.Mock.Called(tenantId, storeId, customerId, limit, page, filters, dateFilter)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
@@ -27,8 +27,43 @@ func (service *OrderItemServiceMock) Get(tenantId int, storeId int, limit int, p
 }
 
 // PlaceOrderItem implements OrderItemService.
-func (service *OrderItemServiceMock) PlaceOrderItem(*model.OrderItem) (*model.OrderItem, error) {
-	panic("unimplemented")
+func (service *OrderItemServiceMock) PlaceOrderItem(params *repository.CreateTransactionParams) (*repository.TransactionDataReturn, error) {
+	args := service.Mock.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*repository.TransactionDataReturn), nil
+}
+
+// UpdateOrderStatus implements OrderItemService.
+func (service *OrderItemServiceMock) UpdateOrderStatus(params *repository.UpdateOrderStatusParams) (*model.OrderItem, error) {
+	args := service.Mock.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*model.OrderItem), nil
+}
+
+// GetOpenOrders implements OrderItemService.
+func (service *OrderItemServiceMock) GetOpenOrders(tenantId, storeId, limit, page int) ([]*model.OrderItem, int, error) {
+	args := service.Mock.Called(tenantId, storeId, limit, page)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+
+	return args.Get(0).([]*model.OrderItem), args.Int(1), nil
+}
+
+// GetStatusHistory implements OrderItemService.
+func (service *OrderItemServiceMock) GetStatusHistory(orderItemId, tenantId int) ([]*model.OrderStatusHistory, error) {
+	args := service.Mock.Called(orderItemId, tenantId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*model.OrderStatusHistory), nil
 }
 
 // FindById implements OrderItemService.
@@ -61,6 +96,16 @@ func (service *OrderItemServiceMock) GetSalesReport(tenantId int, storeId int, d
 	return args.Get(0).(*repository.SalesReport), nil
 }
 
+// GetSalesSeries implements OrderItemService.
+func (service *OrderItemServiceMock) GetSalesSeries(tenantId int, storeId int, bucket repository.SalesBucket, dateFilter *query.DateFilter) (*repository.SalesSeries, error) {
+	args := service.Mock.Called(tenantId, storeId, bucket, dateFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*repository.SalesSeries), nil
+}
+
 // ExportProfitExcel implements OrderItemService.
 func (service *OrderItemServiceMock) ExportProfitExcel(tenantId int, storeId int, dateFilter *query.DateFilter) ([]byte, error) {
 	args := service.Mock.Called(tenantId, storeId, dateFilter)
@@ -72,11 +117,31 @@ func (service *OrderItemServiceMock) ExportProfitExcel(tenantId int, storeId int
 }
 
 // DeleteInvoice implements [OrderItemService].
-func (service *OrderItemServiceMock) DeleteInvoice(orderItemId int, tenantId int) error {
-	args := service.Mock.Called(orderItemId, tenantId)
+func (service *OrderItemServiceMock) DeleteInvoice(orderItemId int, tenantId int, userId int) error {
+	args := service.Mock.Called(orderItemId, tenantId, userId)
 	if args.Get(0) != nil {
 		return args.Error(0)
 	}
 
 	return nil
 }
+
+// GetCategoryReport implements OrderItemService.
+func (service *OrderItemServiceMock) GetCategoryReport(tenantId int, storeId int, dateFilter *query.DateFilter) ([]*repository.CategoryReportRow, error) {
+	args := service.Mock.Called(tenantId, storeId, dateFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*repository.CategoryReportRow), nil
+}
+
+// GetStoreComparison implements OrderItemService.
+func (service *OrderItemServiceMock) GetStoreComparison(tenantId int, dateFilter *query.DateFilter) ([]*repository.StoreComparisonRow, error) {
+	args := service.Mock.Called(tenantId, dateFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*repository.StoreComparisonRow), nil
+}
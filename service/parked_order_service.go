@@ -0,0 +1,58 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+)
+
+type ParkedOrderService interface {
+	/*
+		Park a new basket for a store, it expire after ParkedOrderTTL
+	*/
+	Park(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error)
+
+	/*
+		Get the parked orders of a store (oldest first)
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	Get(tenantId, storeId, limit, page int) ([]*model.ParkedOrder, int, error)
+
+	/*
+		Resume 1 parked order with its lines
+	*/
+	FindById(parkedOrderId, tenantId int) (*model.ParkedOrder, error)
+
+	/*
+		Replace customer, notes and lines, the expiry is extended
+	*/
+	Edit(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error)
+
+	/*
+		Cancel the parked order
+	*/
+	Cancel(parkedOrderId, tenantId int) error
+
+	/*
+		Convert the parked order into a real transaction,
+		the lines and customer of the parked order are used
+	*/
+	Convert(parkedOrderId, tenantId, userId int, payment *ParkedOrderPayment) (*repository.TransactionDataReturn, error)
+
+	/*
+		Called periodically by job, return the count of expired orders
+	*/
+	ExpireParkedOrders() (int, error)
+}
+
+/*
+Payment given by the cashier when a parked order is converted,
+Version is the parked order version seen by the till
+*/
+type ParkedOrderPayment struct {
+	Version        int    `json:"version"`
+	PurchasedPrice int    `json:"purchased_price"`
+	RedeemPoints   int    `json:"redeem_points"`
+	PointsDiscount int    `json:"points_discount"`
+	GiftCardCode   string `json:"gift_card_code"`
+	GiftCardAmount int    `json:"gift_card_amount"`
+}
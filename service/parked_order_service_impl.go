@@ -0,0 +1,195 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Parked order is removed from the list after this duration, edit extend it
+const ParkedOrderTTL = 24 * time.Hour
+
+type ParkedOrderServiceImpl struct {
+	Repository        repository.ParkedOrderRepository
+	OrderItemService  OrderItemService
+	ItemNameRegexRule *regexp.Regexp
+}
+
+func NewParkedOrderServiceImpl(repository repository.ParkedOrderRepository, orderItemService OrderItemService) ParkedOrderService {
+	return &ParkedOrderServiceImpl{
+		Repository:        repository,
+		OrderItemService:  orderItemService,
+		ItemNameRegexRule: regexp.MustCompile(`^[\p{Han}\p{Hiragana}\p{Katakana}a-zA-Z][\p{Han}\p{Hiragana}\p{Katakana}a-zA-Z0-9' ]*$`),
+	}
+}
+
+func (service *ParkedOrderServiceImpl) validate(parkedOrder *model.ParkedOrder) error {
+	if parkedOrder.TenantId < 1 {
+		return errors.New("Invalid tenant id")
+	}
+
+	if parkedOrder.CustomerId != nil && *parkedOrder.CustomerId < 1 {
+		return fmt.Errorf("Invalid customer id: %d", *parkedOrder.CustomerId)
+	}
+
+	if len(parkedOrder.Notes) > 500 {
+		return errors.New("Notes could not be more than 500 characters")
+	}
+
+	if len(parkedOrder.Lines) == 0 {
+		return errors.New("At least one line is required")
+	}
+	if len(parkedOrder.Lines) > 1000 {
+		return errors.New("Too many lines (max 1000)")
+	}
+
+	for _, line := range parkedOrder.Lines {
+		if line.ItemId < 1 {
+			return fmt.Errorf("Invalid item id: %d", line.ItemId)
+		}
+		if line.Quantity < 1 {
+			return fmt.Errorf("Given quantity %d, from item_id: %d. Quantity should never be <= 0", line.Quantity, line.ItemId)
+		}
+		if line.StorePriceSnapshot < 0 || line.DiscountAmount < 0 || line.DiscountAmount > line.StorePriceSnapshot {
+			return fmt.Errorf("Invalid price or discount from item_id: %d", line.ItemId)
+		}
+		if lineTotal := (line.StorePriceSnapshot - line.DiscountAmount) * line.Quantity; line.TaxAmount < 0 || line.TaxAmount > lineTotal {
+			return fmt.Errorf("Item %d tax %d should be between 0 and the line total %d", line.ItemId, line.TaxAmount, lineTotal)
+		}
+		if !service.ItemNameRegexRule.MatchString(line.ItemNameSnapshot) {
+			// This is the same regex with WarehouseService.CreateItem
+			return fmt.Errorf("Illegal input from item name snapshot: %s", line.ItemNameSnapshot)
+		}
+	}
+
+	return nil
+}
+
+// Park implements ParkedOrderService.
+func (service *ParkedOrderServiceImpl) Park(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error) {
+	if parkedOrder.Id != 0 {
+		return nil, fmt.Errorf("Data type error. parked order Id should not be inserted. Specified parked order id: %d", parkedOrder.Id)
+	}
+	if parkedOrder.StoreId < 1 || parkedOrder.ParkedByUserId < 1 {
+		return nil, errors.New("Store id, User id is Required !")
+	}
+
+	if err := service.validate(parkedOrder); err != nil {
+		return nil, err
+	}
+
+	parkedOrder.ExpiresAt = time.Now().Add(ParkedOrderTTL)
+	return service.Repository.Create(parkedOrder)
+}
+
+// Get implements ParkedOrderService.
+func (service *ParkedOrderServiceImpl) Get(tenantId, storeId, limit, page int) ([]*model.ParkedOrder, int, error) {
+	if tenantId < 1 || storeId < 1 {
+		return nil, 0, errors.New("Tenant id, Store id is Required !")
+	}
+
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	return service.Repository.Get(tenantId, storeId, limit, page-1)
+}
+
+// FindById implements ParkedOrderService.
+func (service *ParkedOrderServiceImpl) FindById(parkedOrderId, tenantId int) (*model.ParkedOrder, error) {
+	if parkedOrderId < 1 || tenantId < 1 {
+		return nil, errors.New("Tenant id or Parked order id Required !")
+	}
+
+	return service.Repository.FindById(parkedOrderId, tenantId)
+}
+
+// Edit implements ParkedOrderService.
+func (service *ParkedOrderServiceImpl) Edit(parkedOrder *model.ParkedOrder) (*model.ParkedOrder, error) {
+	if parkedOrder.Id < 1 {
+		return nil, errors.New("Invalid parked order id")
+	}
+
+	if err := service.validate(parkedOrder); err != nil {
+		return nil, err
+	}
+
+	parkedOrder.ExpiresAt = time.Now().Add(ParkedOrderTTL)
+	return service.Repository.Edit(parkedOrder)
+}
+
+// Cancel implements ParkedOrderService.
+func (service *ParkedOrderServiceImpl) Cancel(parkedOrderId, tenantId int) error {
+	if parkedOrderId < 1 || tenantId < 1 {
+		return errors.New("Tenant id or Parked order id Required !")
+	}
+
+	return service.Repository.Cancel(parkedOrderId, tenantId)
+}
+
+// Convert implements ParkedOrderService.
+func (service *ParkedOrderServiceImpl) Convert(parkedOrderId, tenantId, userId int, payment *ParkedOrderPayment) (*repository.TransactionDataReturn, error) {
+	if parkedOrderId < 1 || tenantId < 1 || userId < 1 {
+		return nil, errors.New("Tenant id, Parked order id, User id is Required !")
+	}
+
+	parkedOrder, err := service.Repository.FindById(parkedOrderId, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checked again inside the DB transaction, this one only fail fast
+	if parkedOrder.Status != model.ParkedOrderStatusParked {
+		return nil, fmt.Errorf("Parked order %d is already %s", parkedOrderId, parkedOrder.Status)
+	}
+
+	params := &repository.CreateTransactionParams{
+		PurchasedPrice: payment.PurchasedPrice,
+		UserId:         userId,
+		TenantId:       tenantId,
+		StoreId:        parkedOrder.StoreId,
+		RedeemPoints:   payment.RedeemPoints,
+		PointsDiscount: payment.PointsDiscount,
+		GiftCardCode:   payment.GiftCardCode,
+		GiftCardAmount: payment.GiftCardAmount,
+
+		ParkedOrderId:      parkedOrder.Id,
+		ParkedOrderVersion: payment.Version,
+	}
+	if parkedOrder.CustomerId != nil {
+		params.CustomerId = *parkedOrder.CustomerId
+	}
+
+	for _, line := range parkedOrder.Lines {
+		subTotal := line.StorePriceSnapshot * line.Quantity
+		discount := line.DiscountAmount * line.Quantity
+
+		params.Items = append(params.Items, &model.PurchasedItem{
+			Quantity:           line.Quantity,
+			StorePriceSnapshot: line.StorePriceSnapshot,
+			DiscountAmount:     line.DiscountAmount,
+			TaxAmount:          line.TaxAmount,
+			TotalAmount:        subTotal - discount,
+			ItemId:             line.ItemId,
+			ItemNameSnapshot:   line.ItemNameSnapshot,
+		})
+		params.TotalQuantity += line.Quantity
+		params.SubTotal += subTotal
+		params.DiscountAmount += discount
+		params.TotalAmount += subTotal - discount
+	}
+
+	// Same validation as a normal transaction
+	return service.OrderItemService.Transactions(params)
+}
+
+// ExpireParkedOrders implements ParkedOrderService.
+func (service *ParkedOrderServiceImpl) ExpireParkedOrders() (int, error) {
+	return service.Repository.ExpireParkedOrders(time.Now())
+}
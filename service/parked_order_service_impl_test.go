@@ -0,0 +1,193 @@
+package service
+
+import (
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParkedOrderServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const STORE_ID = 1
+	const USER_ID = 1
+	const LIMIT = 10
+	const PAGE = 1
+
+	newService := func() (*repository.ParkedOrderRepositoryMock, *repository.OrderItemRepositoryMock, ParkedOrderService) {
+		parkedOrderRepo := repository.NewParkedOrderRepositoryMock(&mock.Mock{}).(*repository.ParkedOrderRepositoryMock)
+		orderItemRepo := repository.NewOrderItemRepositoryMock(&mock.Mock{}).(*repository.OrderItemRepositoryMock)
+		return parkedOrderRepo, orderItemRepo, NewParkedOrderServiceImpl(parkedOrderRepo, NewOrderItemServiceImpl(orderItemRepo))
+	}
+
+	newLines := func() []*model.ParkedOrderLine {
+		return []*model.ParkedOrderLine{
+			{ItemId: 1, Quantity: 2, StorePriceSnapshot: 10_000, DiscountAmount: 500, TaxAmount: 1_710, ItemNameSnapshot: "Item A"},
+			{ItemId: 2, Quantity: 1, StorePriceSnapshot: 9_000, DiscountAmount: 0, ItemNameSnapshot: "Item B"},
+		}
+	}
+
+	t.Run("Park", func(t *testing.T) {
+		t.Run("NormalPark", func(t *testing.T) {
+			parkedOrderRepo, _, parkedOrderService := newService()
+			parkedOrderRepo.Mock.On("Create", mock.MatchedBy(func(parkedOrder *model.ParkedOrder) bool {
+				return parkedOrder.ExpiresAt.After(time.Now().Add(ParkedOrderTTL - time.Minute))
+			})).Return(&model.ParkedOrder{Id: 1, Status: model.ParkedOrderStatusParked, Version: 1}, nil)
+
+			parkedOrder, err := parkedOrderService.Park(&model.ParkedOrder{
+				TenantId:       TENANT_ID,
+				StoreId:        STORE_ID,
+				ParkedByUserId: USER_ID,
+				Notes:          "Customer fetch wallet",
+				Lines:          newLines(),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, parkedOrder.Version)
+		})
+
+		t.Run("InvalidParkedOrder", func(t *testing.T) {
+			_, _, parkedOrderService := newService()
+			invalidCustomerId := 0
+
+			invalidLines := func(edit func(line *model.ParkedOrderLine)) []*model.ParkedOrderLine {
+				lines := newLines()
+				edit(lines[0])
+				return lines
+			}
+
+			for _, parkedOrder := range []*model.ParkedOrder{
+				{Id: 1, TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: newLines()},
+				{TenantId: TENANT_ID, StoreId: 0, ParkedByUserId: USER_ID, Lines: newLines()},
+				{TenantId: 0, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: newLines()},
+				{TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: nil},
+				{TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: newLines(), CustomerId: &invalidCustomerId},
+				{TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: invalidLines(func(line *model.ParkedOrderLine) { line.Quantity = 0 })},
+				{TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: invalidLines(func(line *model.ParkedOrderLine) { line.DiscountAmount = 10_001 })},
+				{TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: invalidLines(func(line *model.ParkedOrderLine) { line.TaxAmount = -1 })},
+				{TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: invalidLines(func(line *model.ParkedOrderLine) { line.TaxAmount = 19_001 })},
+				{TenantId: TENANT_ID, StoreId: STORE_ID, ParkedByUserId: USER_ID, Lines: invalidLines(func(line *model.ParkedOrderLine) { line.ItemNameSnapshot = "<script>" })},
+			} {
+				createdParkedOrder, err := parkedOrderService.Park(parkedOrder)
+				assert.Error(t, err)
+				assert.Nil(t, createdParkedOrder)
+			}
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		parkedOrderRepo, _, parkedOrderService := newService()
+		expectedParkedOrders := []*model.ParkedOrder{{Id: 1, Lines: newLines()}}
+		parkedOrderRepo.Mock.On("Get", TENANT_ID, STORE_ID, LIMIT, PAGE-1).Return(expectedParkedOrders, 1, nil)
+
+		parkedOrders, count, err := parkedOrderService.Get(TENANT_ID, STORE_ID, LIMIT, PAGE)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, expectedParkedOrders, parkedOrders)
+
+		parkedOrders, _, err = parkedOrderService.Get(TENANT_ID, 0, LIMIT, PAGE)
+		assert.Error(t, err)
+		assert.Nil(t, parkedOrders)
+
+		parkedOrders, _, err = parkedOrderService.Get(TENANT_ID, STORE_ID, LIMIT, 0)
+		assert.Error(t, err)
+		assert.Nil(t, parkedOrders)
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		t.Run("NormalEdit", func(t *testing.T) {
+			parkedOrderRepo, _, parkedOrderService := newService()
+			parkedOrderRepo.Mock.On("Edit", mock.Anything).Return(&model.ParkedOrder{Id: 1, Version: 3}, nil)
+
+			editedParkedOrder, err := parkedOrderService.Edit(&model.ParkedOrder{Id: 1, TenantId: TENANT_ID, Version: 2, Lines: newLines()})
+			assert.NoError(t, err)
+			assert.Equal(t, 3, editedParkedOrder.Version)
+		})
+
+		t.Run("StaleVersion", func(t *testing.T) {
+			parkedOrderRepo, _, parkedOrderService := newService()
+			parkedOrderRepo.Mock.On("Edit", mock.Anything).Return(nil, errors.New("Parked order 1 was changed by another till"))
+
+			editedParkedOrder, err := parkedOrderService.Edit(&model.ParkedOrder{Id: 1, TenantId: TENANT_ID, Version: 1, Lines: newLines()})
+			assert.Error(t, err)
+			assert.Nil(t, editedParkedOrder)
+		})
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		parkedOrderRepo, _, parkedOrderService := newService()
+		parkedOrderRepo.Mock.On("Cancel", 1, TENANT_ID).Return(nil)
+
+		assert.NoError(t, parkedOrderService.Cancel(1, TENANT_ID))
+		assert.Error(t, parkedOrderService.Cancel(0, TENANT_ID))
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		t.Run("NormalConvert", func(t *testing.T) {
+			parkedOrderRepo, orderItemRepo, parkedOrderService := newService()
+			customerId := 7
+			parkedOrderRepo.Mock.On("FindById", 1, TENANT_ID).Return(&model.ParkedOrder{
+				Id:         1,
+				TenantId:   TENANT_ID,
+				StoreId:    STORE_ID,
+				CustomerId: &customerId,
+				Status:     model.ParkedOrderStatusParked,
+				Version:    2,
+				Lines:      newLines(),
+			}, nil)
+
+			// (10_000 * 2 - 500 * 2) + 9_000
+			orderItemRepo.Mock.On("Transactions", mock.MatchedBy(func(params *repository.CreateTransactionParams) bool {
+				return params.SubTotal == 29_000 &&
+					params.DiscountAmount == 1_000 &&
+					params.TotalAmount == 28_000 &&
+					params.TotalQuantity == 3 &&
+					params.CustomerId == customerId &&
+					params.StoreId == STORE_ID &&
+					params.ParkedOrderId == 1 &&
+					params.ParkedOrderVersion == 2 &&
+					// Tax parked on the line is kept on the sale
+					params.Items[0].TaxAmount == 1_710 &&
+					params.Items[1].TaxAmount == 0
+			})).Return(&repository.TransactionDataReturn{CreatedOrderItemId: 10, TotalAmount: 28_000}, nil)
+
+			transactionDataReturn, err := parkedOrderService.Convert(1, TENANT_ID, USER_ID, &ParkedOrderPayment{Version: 2, PurchasedPrice: 30_000})
+			assert.NoError(t, err)
+			assert.Equal(t, 10, transactionDataReturn.CreatedOrderItemId)
+		})
+
+		t.Run("InsufficientPayment", func(t *testing.T) {
+			parkedOrderRepo, _, parkedOrderService := newService()
+			parkedOrderRepo.Mock.On("FindById", 1, TENANT_ID).
+				Return(&model.ParkedOrder{Id: 1, StoreId: STORE_ID, Status: model.ParkedOrderStatusParked, Lines: newLines()}, nil)
+
+			transactionDataReturn, err := parkedOrderService.Convert(1, TENANT_ID, USER_ID, &ParkedOrderPayment{PurchasedPrice: 1})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "Insufficient payment")
+			assert.Nil(t, transactionDataReturn)
+		})
+
+		t.Run("AlreadyConverted", func(t *testing.T) {
+			parkedOrderRepo, _, parkedOrderService := newService()
+			parkedOrderRepo.Mock.On("FindById", 1, TENANT_ID).
+				Return(&model.ParkedOrder{Id: 1, StoreId: STORE_ID, Status: model.ParkedOrderStatusConverted, Lines: newLines()}, nil)
+
+			transactionDataReturn, err := parkedOrderService.Convert(1, TENANT_ID, USER_ID, &ParkedOrderPayment{PurchasedPrice: 30_000})
+			assert.Error(t, err)
+			assert.Equal(t, "Parked order 1 is already CONVERTED", err.Error())
+			assert.Nil(t, transactionDataReturn)
+		})
+	})
+
+	t.Run("ExpireParkedOrders", func(t *testing.T) {
+		parkedOrderRepo, _, parkedOrderService := newService()
+		parkedOrderRepo.Mock.On("ExpireParkedOrders", mock.AnythingOfType("time.Time")).Return(3, nil)
+
+		expired, err := parkedOrderService.ExpireParkedOrders()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, expired)
+	})
+}
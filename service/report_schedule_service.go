@@ -0,0 +1,46 @@
+package service
+
+import (
+	"cashier-api/model"
+	"time"
+)
+
+type ReportScheduleService interface {
+	/*
+		Get the list of schedule
+		2nd params return is the count of all data
+	*/
+	Get(tenantId, limit, page int) ([]*model.ReportSchedule, int, error)
+
+	/*
+		Return 1 schedule
+	*/
+	FindById(scheduleId, tenantId int) (*model.ReportSchedule, error)
+
+	/*
+		Create new schedule, resource and format must be exportable
+	*/
+	Create(schedule *model.ReportSchedule) (*model.ReportSchedule, error)
+
+	/*
+		Edit every setting of the schedule
+	*/
+	Edit(schedule *model.ReportSchedule) (*model.ReportSchedule, error)
+
+	/*
+		Delete the schedule and its run history
+	*/
+	Delete(scheduleId, tenantId int) error
+
+	/*
+		Run history of a schedule (newest first)
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	GetRuns(scheduleId, tenantId, limit, page int) ([]*model.ReportScheduleRun, int, error)
+
+	/*
+		Background job: export and deliver every due run (new and retried).
+		Return how many were delivered, a failed delivery is retried later, not returned
+	*/
+	RunDue(now time.Time) (int, error)
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bytes"
+	"cashier-api/helper/export"
+	"cashier-api/helper/mail"
+	"cashier-api/helper/query"
+	"cashier-api/helper/webhook"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// A run still RUNNING after this is claimed again
+	reportScheduleLease = time.Minute * 10
+	// Wait before the next attempt, multiplied by the attempts already done
+	reportScheduleRetryDelay = time.Minute * 5
+)
+
+type ReportScheduleServiceImpl struct {
+	Repository    repository.ReportScheduleRepository
+	ExportService ExportService
+	Mailer        mail.Mailer
+	Webhook       webhook.Sender
+}
+
+func NewReportScheduleServiceImpl(
+	repository repository.ReportScheduleRepository,
+	exportService ExportService,
+	mailer mail.Mailer,
+	webhookSender webhook.Sender,
+) ReportScheduleService {
+	return &ReportScheduleServiceImpl{
+		Repository:    repository,
+		ExportService: exportService,
+		Mailer:        mailer,
+		Webhook:       webhookSender,
+	}
+}
+
+func validateReportSchedule(schedule *model.ReportSchedule) error {
+	if schedule.TenantId <= 0 {
+		return errors.New("Tenant id is Required !")
+	}
+	if schedule.StoreId < 0 {
+		return fmt.Errorf("Invalid store id: %d", schedule.StoreId)
+	}
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	// Never call the internal network of the API
+	if schedule.Channel == model.ReportScheduleWebhook {
+		if err := webhook.CheckUrl(schedule.WebhookUrl); err != nil {
+			return err
+		}
+	}
+
+	resource := ExportResource(schedule.Resource)
+	if !resource.IsValid() {
+		return fmt.Errorf("Invalid export resource: %s", schedule.Resource)
+	}
+	if resource == ExportStoreStocks && schedule.StoreId == 0 {
+		return errors.New("Store id is Required to export store stocks !")
+	}
+	if !export.Format(schedule.Format).IsValid() {
+		return fmt.Errorf("Invalid export format: %s. Allowed: csv, xlsx, pdf", schedule.Format)
+	}
+
+	return nil
+}
+
+// Get implements ReportScheduleService.
+func (service *ReportScheduleServiceImpl) Get(tenantId, limit, page int) ([]*model.ReportSchedule, int, error) {
+	if tenantId <= 0 {
+		return nil, 0, errors.New("Tenant id is Required !")
+	}
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	return service.Repository.Get(tenantId, limit, page-1)
+}
+
+// FindById implements ReportScheduleService.
+func (service *ReportScheduleServiceImpl) FindById(scheduleId, tenantId int) (*model.ReportSchedule, error) {
+	if tenantId <= 0 || scheduleId <= 0 {
+		return nil, errors.New("Tenant id or Report schedule id Required !")
+	}
+
+	return service.Repository.FindById(scheduleId, tenantId)
+}
+
+// Create implements ReportScheduleService.
+func (service *ReportScheduleServiceImpl) Create(schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	if err := validateReportSchedule(schedule); err != nil {
+		return nil, err
+	}
+	if schedule.CreatedByUserId <= 0 {
+		return nil, fmt.Errorf("invalid user id: %d", schedule.CreatedByUserId)
+	}
+
+	return service.Repository.Create(schedule)
+}
+
+// Edit implements ReportScheduleService.
+func (service *ReportScheduleServiceImpl) Edit(schedule *model.ReportSchedule) (*model.ReportSchedule, error) {
+	if schedule.Id <= 0 {
+		return nil, errors.New("Report schedule id is Required !")
+	}
+	if err := validateReportSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	return service.Repository.Edit(schedule)
+}
+
+// Delete implements ReportScheduleService.
+func (service *ReportScheduleServiceImpl) Delete(scheduleId, tenantId int) error {
+	if tenantId <= 0 || scheduleId <= 0 {
+		return errors.New("Tenant id or Report schedule id Required !")
+	}
+
+	return service.Repository.Delete(scheduleId, tenantId)
+}
+
+// GetRuns implements ReportScheduleService.
+func (service *ReportScheduleServiceImpl) GetRuns(scheduleId, tenantId, limit, page int) ([]*model.ReportScheduleRun, int, error) {
+	if tenantId <= 0 || scheduleId <= 0 {
+		return nil, 0, errors.New("Tenant id or Report schedule id Required !")
+	}
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	return service.Repository.GetRuns(scheduleId, tenantId, limit, page-1)
+}
+
+// RunDue implements ReportScheduleService.
+func (service *ReportScheduleServiceImpl) RunDue(now time.Time) (int, error) {
+	runs, err := service.Repository.ClaimDueRuns(now, reportScheduleLease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	var finishErrors []error
+	for _, run := range runs {
+		fileName, err := service.deliver(run)
+		finishedAt := time.Now()
+		run.FileName = fileName
+
+		switch {
+		case err == nil:
+			run.Status = model.ReportScheduleRunSuccess
+			run.NextAttemptAt = nil
+			run.LastError = ""
+			run.FinishedAt = &finishedAt
+			delivered++
+		case run.Attempts < model.ReportScheduleMaxAttempts:
+			nextAttemptAt := finishedAt.Add(reportScheduleRetryDelay * time.Duration(run.Attempts))
+			run.Status = model.ReportScheduleRunRetrying
+			run.NextAttemptAt = &nextAttemptAt
+			run.LastError = err.Error()
+		default:
+			run.Status = model.ReportScheduleRunFailed
+			run.NextAttemptAt = nil
+			run.LastError = err.Error()
+			run.FinishedAt = &finishedAt
+		}
+		if err != nil {
+			log.Warnf("[REPORT SCHEDULE] run %d of schedule %d attempt %d failed: %s", run.Id, run.ScheduleId, run.Attempts, err.Error())
+		}
+
+		// Keep delivering the other runs, this one is claimed again when its lease expire
+		if err := service.Repository.FinishRun(run); err != nil {
+			finishErrors = append(finishErrors, err)
+		}
+	}
+
+	return delivered, errors.Join(finishErrors...)
+}
+
+// deliver export the report of the run period and send it by the schedule channel
+func (service *ReportScheduleServiceImpl) deliver(run *model.ReportScheduleRun) (string, error) {
+	schedule := run.Schedule
+	startDate, endDate := run.PeriodStart.Unix(), run.PeriodEnd.Unix()
+
+	exp, err := service.ExportService.Prepare(&ExportRequest{
+		TenantId:   schedule.TenantId,
+		StoreId:    schedule.StoreId,
+		Resource:   ExportResource(schedule.Resource),
+		Format:     export.Format(schedule.Format),
+		DateFilter: &query.DateFilter{Column: "created_at", StartDate: &startDate, EndDate: &endDate},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Scheduled reports are summaries, small enough to be attached from memory
+	var buffer bytes.Buffer
+	if err := exp.Write(&buffer); err != nil {
+		return exp.FileName, err
+	}
+
+	switch schedule.Channel {
+	case model.ReportScheduleEmail:
+		// The run must not be SUCCESS when nothing is sent
+		if !mail.IsConfigured(service.Mailer) {
+			return exp.FileName, mail.ErrNotConfigured
+		}
+
+		location, err := time.LoadLocation(run.Timezone)
+		if err != nil {
+			location = time.UTC
+		}
+		// End is exclusive, the last day of the period is the day before
+		firstDay := run.PeriodStart.In(location).Format(time.DateOnly)
+		lastDay := run.PeriodEnd.In(location).AddDate(0, 0, -1).Format(time.DateOnly)
+		period := firstDay
+		if lastDay != firstDay {
+			period = firstDay + " - " + lastDay
+		}
+
+		return exp.FileName, service.Mailer.Send(&mail.Message{
+			To:      schedule.RecipientList(),
+			Subject: fmt.Sprintf("%s (%s)", schedule.Name, period),
+			Body:    fmt.Sprintf("Your scheduled report %q is attached.\r\nPeriod: %s (%s)\r\n", schedule.Name, period, location),
+			Attachments: []*mail.Attachment{
+				{FileName: exp.FileName, ContentType: exp.ContentType, Data: buffer.Bytes()},
+			},
+		})
+
+	case model.ReportScheduleWebhook:
+		return exp.FileName, service.Webhook.Send(&webhook.Delivery{
+			Url:         schedule.WebhookUrl,
+			Secret:      schedule.Secret(),
+			Event:       "report_schedule.delivered",
+			FileName:    exp.FileName,
+			ContentType: exp.ContentType,
+			Body:        buffer.Bytes(),
+		})
+	}
+
+	return exp.FileName, fmt.Errorf("Invalid channel: %s", schedule.Channel)
+}
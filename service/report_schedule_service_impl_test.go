@@ -0,0 +1,218 @@
+package service
+
+import (
+	"cashier-api/helper/mail"
+	"cashier-api/helper/webhook"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordMailer keep the sent messages instead of sending them
+type recordMailer struct {
+	messages []*mail.Message
+	err      error
+}
+
+func (mailer *recordMailer) Send(message *mail.Message) error {
+	mailer.messages = append(mailer.messages, message)
+	return mailer.err
+}
+
+func TestReportScheduleServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const USER_ID = 1
+
+	newService := func(mailer mail.Mailer) (*repository.ReportScheduleRepositoryMock, *ExportServiceMock, ReportScheduleService) {
+		scheduleRepo := repository.NewReportScheduleRepositoryMock(&mock.Mock{}).(*repository.ReportScheduleRepositoryMock)
+		exportService := NewExportServiceMock(&mock.Mock{}).(*ExportServiceMock)
+		// Test server listen on loopback, refused by webhook.NewHTTPSender
+		webhookSender := &webhook.HTTPSender{Client: &http.Client{Timeout: time.Second * 5}}
+		return scheduleRepo, exportService, NewReportScheduleServiceImpl(scheduleRepo, exportService, mailer, webhookSender)
+	}
+
+	newSchedule := func() *model.ReportSchedule {
+		return &model.ReportSchedule{
+			TenantId:        TENANT_ID,
+			Name:            "Daily sales",
+			Resource:        string(ExportSalesReport),
+			Format:          "csv",
+			Frequency:       model.ReportScheduleDaily,
+			TimeOfDay:       "07:00",
+			Channel:         model.ReportScheduleEmail,
+			Recipients:      "owner@example.com",
+			IsActive:        true,
+			CreatedByUserId: USER_ID,
+		}
+	}
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	newRun := func(schedule *model.ReportSchedule, attempts int) *model.ReportScheduleRun {
+		return &model.ReportScheduleRun{
+			Id:          1,
+			ScheduleId:  1,
+			TenantId:    TENANT_ID,
+			Status:      model.ReportScheduleRunRunning,
+			Attempts:    attempts,
+			PeriodStart: time.Date(2026, 1, 30, 0, 0, 0, 0, jakarta).UTC(),
+			PeriodEnd:   time.Date(2026, 1, 31, 0, 0, 0, 0, jakarta).UTC(),
+			Timezone:    "Asia/Jakarta",
+			Schedule:    schedule,
+		}
+	}
+
+	csvExport := &Export{
+		FileName:    "sales_report.csv",
+		ContentType: "text/csv; charset=utf-8",
+		write: func(w io.Writer) error {
+			_, err := w.Write([]byte("Report,Sales Report\n"))
+			return err
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("NormalCreate", func(t *testing.T) {
+			scheduleRepo, _, scheduleService := newService(&recordMailer{})
+			schedule := newSchedule()
+			scheduleRepo.Mock.On("Create", schedule).Return(schedule, nil)
+
+			created, err := scheduleService.Create(schedule)
+			assert.NoError(t, err)
+			assert.Equal(t, "Daily sales", created.Name)
+		})
+
+		t.Run("InvalidSchedule", func(t *testing.T) {
+			_, _, scheduleService := newService(&recordMailer{})
+
+			invalids := []func(schedule *model.ReportSchedule){
+				func(schedule *model.ReportSchedule) { schedule.Resource = "passwords" },
+				func(schedule *model.ReportSchedule) { schedule.Format = "docx" },
+				func(schedule *model.ReportSchedule) { schedule.Resource = string(ExportStoreStocks) },
+				func(schedule *model.ReportSchedule) { schedule.Frequency = "HOURLY" },
+				func(schedule *model.ReportSchedule) { schedule.CreatedByUserId = 0 },
+				func(schedule *model.ReportSchedule) {
+					schedule.Channel, schedule.WebhookUrl = model.ReportScheduleWebhook, "http://169.254.169.254/latest/meta-data"
+				},
+				func(schedule *model.ReportSchedule) {
+					schedule.Channel, schedule.WebhookUrl = model.ReportScheduleWebhook, "http://localhost:8000/api/v1"
+				},
+			}
+			for i, invalid := range invalids {
+				schedule := newSchedule()
+				invalid(schedule)
+				_, err := scheduleService.Create(schedule)
+				assert.Error(t, err, i)
+			}
+		})
+	})
+
+	t.Run("RunDue", func(t *testing.T) {
+		t.Run("EmailDelivered", func(t *testing.T) {
+			mailer := &recordMailer{}
+			scheduleRepo, exportService, scheduleService := newService(mailer)
+			run := newRun(newSchedule(), 1)
+			now := time.Now()
+
+			scheduleRepo.Mock.On("ClaimDueRuns", now, reportScheduleLease).Return([]*model.ReportScheduleRun{run}, nil)
+			exportService.Mock.On("Prepare", mock.MatchedBy(func(request *ExportRequest) bool {
+				return request.Resource == ExportSalesReport &&
+					*request.DateFilter.StartDate == run.PeriodStart.Unix() && *request.DateFilter.EndDate == run.PeriodEnd.Unix()
+			})).Return(csvExport, nil)
+			scheduleRepo.Mock.On("FinishRun", run).Return(nil)
+
+			delivered, err := scheduleService.RunDue(now)
+			require.NoError(t, err)
+			assert.Equal(t, 1, delivered)
+			assert.Equal(t, model.ReportScheduleRunSuccess, run.Status)
+			assert.Equal(t, "sales_report.csv", run.FileName)
+			assert.NotNil(t, run.FinishedAt)
+
+			require.Len(t, mailer.messages, 1)
+			assert.Equal(t, "Daily sales (2026-01-30)", mailer.messages[0].Subject)
+			assert.Equal(t, []string{"owner@example.com"}, mailer.messages[0].To)
+			assert.Equal(t, "Report,Sales Report\n", string(mailer.messages[0].Attachments[0].Data))
+			scheduleRepo.Mock.AssertExpectations(t)
+		})
+
+		t.Run("WebhookRetried", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			scheduleRepo, exportService, scheduleService := newService(&recordMailer{})
+			schedule := newSchedule()
+			schedule.Channel, schedule.WebhookUrl = model.ReportScheduleWebhook, server.URL
+			run := newRun(schedule, 1)
+			now := time.Now()
+
+			scheduleRepo.Mock.On("ClaimDueRuns", now, reportScheduleLease).Return([]*model.ReportScheduleRun{run}, nil)
+			exportService.Mock.On("Prepare", mock.Anything).Return(csvExport, nil)
+			scheduleRepo.Mock.On("FinishRun", run).Return(nil)
+
+			delivered, err := scheduleService.RunDue(now)
+			require.NoError(t, err)
+			assert.Zero(t, delivered)
+			assert.Equal(t, model.ReportScheduleRunRetrying, run.Status)
+			require.NotNil(t, run.NextAttemptAt)
+			assert.True(t, run.NextAttemptAt.After(now))
+			assert.Contains(t, run.LastError, "503")
+			assert.Nil(t, run.FinishedAt)
+		})
+
+		t.Run("EmailNotConfigured", func(t *testing.T) {
+			scheduleRepo, exportService, scheduleService := newService(mail.NewLogMailer())
+			run := newRun(newSchedule(), 1)
+			now := time.Now()
+
+			scheduleRepo.Mock.On("ClaimDueRuns", now, reportScheduleLease).Return([]*model.ReportScheduleRun{run}, nil)
+			exportService.Mock.On("Prepare", mock.Anything).Return(csvExport, nil)
+			scheduleRepo.Mock.On("FinishRun", run).Return(nil)
+
+			delivered, err := scheduleService.RunDue(now)
+			require.NoError(t, err)
+			assert.Zero(t, delivered)
+			assert.Equal(t, model.ReportScheduleRunRetrying, run.Status)
+			assert.Equal(t, mail.ErrNotConfigured.Error(), run.LastError)
+		})
+
+		t.Run("GaveUp", func(t *testing.T) {
+			scheduleRepo, exportService, scheduleService := newService(&recordMailer{err: errors.New("connection refused")})
+			run := newRun(newSchedule(), model.ReportScheduleMaxAttempts)
+			now := time.Now()
+
+			scheduleRepo.Mock.On("ClaimDueRuns", now, reportScheduleLease).Return([]*model.ReportScheduleRun{run}, nil)
+			exportService.Mock.On("Prepare", mock.Anything).Return(csvExport, nil)
+			scheduleRepo.Mock.On("FinishRun", run).Return(nil)
+
+			_, err := scheduleService.RunDue(now)
+			require.NoError(t, err)
+			assert.Equal(t, model.ReportScheduleRunFailed, run.Status)
+			assert.Nil(t, run.NextAttemptAt)
+			assert.Equal(t, "connection refused", run.LastError)
+		})
+	})
+
+	t.Run("GetRuns", func(t *testing.T) {
+		scheduleRepo, _, scheduleService := newService(&recordMailer{})
+		scheduleRepo.Mock.On("GetRuns", 1, TENANT_ID, 10, 0).Return([]*model.ReportScheduleRun{{Id: 1}}, 1, nil)
+
+		runs, count, err := scheduleService.GetRuns(1, TENANT_ID, 10, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Len(t, runs, 1)
+
+		_, _, err = scheduleService.GetRuns(1, TENANT_ID, 10, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/repository"
+)
+
+type ReportService interface {
+	/*
+		Top items of the period ranked by quantity, revenue or profit.
+		storeId = 0 and categoryId = 0 will not filter
+	*/
+	GetTopSellers(tenantId, storeId, categoryId int, metric repository.ItemRankingMetric, dateFilter *query.DateFilter, limit int) ([]*repository.ItemRankingRow, error)
+
+	/*
+		Items with stock but no sales in the last N days.
+		Always minus page by 1 because PostgreSQL start index from 0
+	*/
+	GetSlowMovers(tenantId, storeId, categoryId, days, limit, page int) ([]*repository.SlowMoverRow, int, error)
+
+	/*
+		Classify every sold item of the period into A / B / C by revenue contribution
+	*/
+	GetABCAnalysis(tenantId, storeId, categoryId int, dateFilter *query.DateFilter) (*repository.ABCAnalysis, error)
+
+	/*
+		Transactions, revenue, average basket, discount, voids and refunds per user,
+		for staff review and spotting discount abuse. storeId = 0 will not filter
+	*/
+	GetCashierPerformance(tenantId, storeId int, dateFilter *query.DateFilter) ([]*repository.CashierPerformanceRow, error)
+
+	/*
+		Money sitting on the shelves, per location and category with the totals.
+		storeId = 0 include the warehouse and every store.
+		asOf (unix second, optional) could not be in the past, return ErrValuationAsOfPast
+	*/
+	GetInventoryValuation(tenantId, storeId int, asOf *int64) (*repository.InventoryValuation, error)
+
+	/*
+		Suggested quantity to order per location. The daily sales of the last historyDays are forecasted
+		with exponential smoothing and day of week seasonality, then compared with the stock on hand,
+		the lead time and a safety stock at the service level (%). storeId = 0 include the warehouse and every store
+	*/
+	GetReorderSuggestions(tenantId, storeId, historyDays, leadTimeDays, serviceLevel int) (*repository.ReorderSuggestions, error)
+}
@@ -0,0 +1,429 @@
+package service
+
+import (
+	"cashier-api/helper/forecast"
+	"cashier-api/helper/query"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Cumulative revenue share (%) upper bound of class A and B
+const (
+	abcClassALimit = 80.0
+	abcClassBLimit = 95.0
+)
+
+/*
+Stock history is not recorded, so only the stock on hand could be valued.
+An as of within the tolerance is still now (clock of the client)
+*/
+const valuationAsOfTolerance = time.Minute
+
+var ErrValuationAsOfPast = errors.New("Valuation as of a past date is not available, stock history is not recorded")
+
+func validateValuationAsOf(asOf *int64) error {
+	if asOf != nil && *asOf < time.Now().Add(-valuationAsOfTolerance).Unix() {
+		return ErrValuationAsOfPast
+	}
+	return nil
+}
+
+// Reorder is reviewed weekly, a suggestion cover the lead time and the days until the next review
+const reorderReviewDays = 7
+
+type ReportServiceImpl struct {
+	Repository repository.ReportRepository
+}
+
+func NewReportServiceImpl(repository repository.ReportRepository) ReportService {
+	return &ReportServiceImpl{Repository: repository}
+}
+
+/*
+validateReportFilter check the common filter of every report,
+dateFilter is allowed to nil
+*/
+func validateReportFilter(tenantId, storeId, categoryId int, dateFilter *query.DateFilter) error {
+	if tenantId <= 0 {
+		return errors.New("Tenant id is Required !")
+	}
+	// 0 is allowed, this allow to get report from all store / category
+	if storeId < 0 {
+		return fmt.Errorf("Given store id value is not allowed. storeId: %d", storeId)
+	}
+	if categoryId < 0 {
+		return fmt.Errorf("Given category id value is not allowed. categoryId: %d", categoryId)
+	}
+
+	if dateFilter == nil {
+		return nil
+	}
+
+	if dateFilter.StartDate != nil && *dateFilter.StartDate < 0 {
+		return fmt.Errorf("Invalid start date timestamp: %d", *dateFilter.StartDate)
+	}
+	if dateFilter.EndDate != nil && *dateFilter.EndDate < 0 {
+		return fmt.Errorf("Invalid end date timestamp: %d", *dateFilter.EndDate)
+	}
+	if dateFilter.StartDate != nil && dateFilter.EndDate != nil && *dateFilter.StartDate > *dateFilter.EndDate {
+		return fmt.Errorf("Start date (%d) cannot be after end date (%d)", *dateFilter.StartDate, *dateFilter.EndDate)
+	}
+
+	return nil
+}
+
+// GetTopSellers implements ReportService.
+func (service *ReportServiceImpl) GetTopSellers(
+	tenantId, storeId, categoryId int,
+	metric repository.ItemRankingMetric,
+	dateFilter *query.DateFilter,
+	limit int,
+) ([]*repository.ItemRankingRow, error) {
+	if err := validateReportFilter(tenantId, storeId, categoryId, dateFilter); err != nil {
+		return nil, err
+	}
+
+	if metric == "" {
+		metric = repository.ItemRankingByQuantity
+	}
+	if !metric.IsValid() {
+		return nil, fmt.Errorf("Invalid ranking metric: %s. Allowed: quantity, revenue, profit", metric)
+	}
+
+	if limit < 1 || limit > 100 {
+		return nil, fmt.Errorf("limit should be between 1 and 100. Given limit %d", limit)
+	}
+
+	return service.Repository.GetItemRanking(tenantId, storeId, categoryId, metric, dateFilter, limit)
+}
+
+// GetSlowMovers implements ReportService.
+func (service *ReportServiceImpl) GetSlowMovers(tenantId, storeId, categoryId, days, limit, page int) ([]*repository.SlowMoverRow, int, error) {
+	if err := validateReportFilter(tenantId, storeId, categoryId, nil); err != nil {
+		return nil, 0, err
+	}
+
+	if days < 1 || days > 365 {
+		return nil, 0, fmt.Errorf("days should be between 1 and 365. Given days %d", days)
+	}
+
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	return service.Repository.GetSlowMovers(tenantId, storeId, categoryId, since, limit, page-1)
+}
+
+// GetABCAnalysis implements ReportService.
+func (service *ReportServiceImpl) GetABCAnalysis(tenantId, storeId, categoryId int, dateFilter *query.DateFilter) (*repository.ABCAnalysis, error) {
+	if err := validateReportFilter(tenantId, storeId, categoryId, dateFilter); err != nil {
+		return nil, err
+	}
+
+	// limit = 0, every sold item is classified
+	rows, err := service.Repository.GetItemRanking(tenantId, storeId, categoryId, repository.ItemRankingByRevenue, dateFilter, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return classifyABC(rows), nil
+}
+
+// GetCashierPerformance implements ReportService.
+func (service *ReportServiceImpl) GetCashierPerformance(tenantId, storeId int, dateFilter *query.DateFilter) ([]*repository.CashierPerformanceRow, error) {
+	if err := validateReportFilter(tenantId, storeId, 0, dateFilter); err != nil {
+		return nil, err
+	}
+
+	rows, err := service.Repository.GetCashierPerformance(tenantId, storeId, dateFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.TransactionCount > 0 {
+			row.AverageBasket = float64(row.TotalRevenue) / float64(row.TransactionCount)
+		}
+		row.DiscountRate = percentOf(row.TotalDiscount, row.TotalSubtotal)
+	}
+
+	return rows, nil
+}
+
+// GetInventoryValuation implements ReportService.
+func (service *ReportServiceImpl) GetInventoryValuation(tenantId, storeId int, asOf *int64) (*repository.InventoryValuation, error) {
+	if err := validateReportFilter(tenantId, storeId, 0, nil); err != nil {
+		return nil, err
+	}
+	if err := validateValuationAsOf(asOf); err != nil {
+		return nil, err
+	}
+
+	rows, err := service.Repository.GetInventoryValuation(tenantId, storeId)
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeInventoryValuation(rows), nil
+}
+
+/*
+summarizeInventoryValuation expect rows ordered by store id, so the locations keep that order.
+Potential profit is only for store, warehouse stock has no selling price yet
+*/
+func summarizeInventoryValuation(rows []*repository.InventoryValuationRow) *repository.InventoryValuation {
+	valuation := &repository.InventoryValuation{
+		Locations:  make([]*repository.InventoryValuationLocation, 0),
+		Categories: make([]*repository.InventoryValuationCategory, 0),
+	}
+	categories := make(map[int]*repository.InventoryValuationCategory)
+
+	var location *repository.InventoryValuationLocation
+	for _, row := range rows {
+		if row.StoreId > 0 {
+			row.PotentialProfit = row.RetailValue - row.CostValue
+		}
+
+		if location == nil || location.StoreId != row.StoreId {
+			location = &repository.InventoryValuationLocation{
+				StoreId:      row.StoreId,
+				LocationName: row.LocationName,
+				Categories:   make([]*repository.InventoryValuationRow, 0),
+			}
+			valuation.Locations = append(valuation.Locations, location)
+		}
+		location.ItemCount += row.ItemCount
+		location.TotalStocks += row.TotalStocks
+		location.CostValue += row.CostValue
+		location.RetailValue += row.RetailValue
+		location.PotentialProfit += row.PotentialProfit
+		location.Categories = append(location.Categories, row)
+
+		category, ok := categories[row.CategoryId]
+		if !ok {
+			category = &repository.InventoryValuationCategory{CategoryId: row.CategoryId, CategoryName: row.CategoryName}
+			categories[row.CategoryId] = category
+			valuation.Categories = append(valuation.Categories, category)
+		}
+		category.TotalStocks += row.TotalStocks
+		category.CostValue += row.CostValue
+		category.RetailValue += row.RetailValue
+
+		valuation.TotalCostValue += row.CostValue
+		valuation.TotalRetailValue += row.RetailValue
+		valuation.TotalPotentialProfit += row.PotentialProfit
+	}
+
+	sort.SliceStable(valuation.Categories, func(i, j int) bool {
+		return valuation.Categories[i].CostValue > valuation.Categories[j].CostValue
+	})
+
+	return valuation
+}
+
+// GetReorderSuggestions implements ReportService.
+func (service *ReportServiceImpl) GetReorderSuggestions(tenantId, storeId, historyDays, leadTimeDays, serviceLevel int) (*repository.ReorderSuggestions, error) {
+	if err := validateReportFilter(tenantId, storeId, 0, nil); err != nil {
+		return nil, err
+	}
+
+	// 2 weeks is the minimum to see the day of week pattern
+	if historyDays < 14 || historyDays > 365 {
+		return nil, fmt.Errorf("history days should be between 14 and 365. Given history days %d", historyDays)
+	}
+	if leadTimeDays < 1 || leadTimeDays > 90 {
+		return nil, fmt.Errorf("lead time days should be between 1 and 90. Given lead time days %d", leadTimeDays)
+	}
+	z, err := forecast.ZScore(serviceLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone, err := service.Repository.GetTenantTimezone(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid tenant timezone: %s", timezone)
+	}
+
+	// Complete local days only, today is still selling
+	now := time.Now().In(location)
+	historyEnd := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	historyStart := historyEnd.AddDate(0, 0, -historyDays)
+
+	stocks, err := service.Repository.GetReorderStocks(tenantId, storeId)
+	if err != nil {
+		return nil, err
+	}
+	sales, err := service.Repository.GetDailyItemSales(tenantId, storeId, timezone, historyStart, historyEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := &repository.ReorderSuggestions{
+		Timezone:     timezone,
+		HistoryStart: historyStart,
+		HistoryEnd:   historyEnd,
+		LeadTimeDays: leadTimeDays,
+		ReviewDays:   reorderReviewDays,
+		ServiceLevel: serviceLevel,
+	}
+	suggestions.Locations = suggestReorders(stocks, sales, historyStart, historyDays, leadTimeDays, z)
+
+	return suggestions, nil
+}
+
+/*
+suggestReorders forecast every stock row, stocks are ordered by store id.
+A location is suggested when its on hand is at or below the reorder point,
+the quantity refill it up to the demand until the next review + safety stock
+*/
+func suggestReorders(
+	stocks []*repository.ReorderStockRow,
+	sales []*repository.DailyItemSalesRow,
+	historyStart time.Time,
+	historyDays, leadTimeDays int,
+	z float64,
+) []*repository.ReorderLocation {
+	// Day index of a local day, the sale date time zone is meaningless so compare the calendar date
+	firstDay := time.Date(historyStart.Year(), historyStart.Month(), historyStart.Day(), 0, 0, 0, 0, time.UTC)
+	dayIndex := func(date time.Time) int {
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		return int(day.Sub(firstDay).Hours() / 24)
+	}
+
+	// Store series, and the series of every store summed for the warehouse
+	type seriesKey struct{ storeId, itemId int }
+	series := make(map[seriesKey][]float64)
+	add := func(key seriesKey, day, quantity int) {
+		if series[key] == nil {
+			series[key] = make([]float64, historyDays)
+		}
+		series[key][day] += float64(quantity)
+	}
+	for _, sale := range sales {
+		day := dayIndex(sale.SaleDate)
+		if day < 0 || day >= historyDays {
+			continue
+		}
+		add(seriesKey{sale.StoreId, sale.ItemId}, day, sale.Quantity)
+		add(seriesKey{0, sale.ItemId}, day, sale.Quantity)
+	}
+
+	// The warehouse refill the stores, their stock is part of its on hand
+	storeStocks := make(map[int]int)
+	for _, stock := range stocks {
+		if stock.StoreId > 0 {
+			storeStocks[stock.ItemId] += stock.Stocks
+		}
+	}
+
+	horizon := leadTimeDays + reorderReviewDays
+	locations := make([]*repository.ReorderLocation, 0)
+	var location *repository.ReorderLocation
+	for _, stock := range stocks {
+		history, ok := series[seriesKey{stock.StoreId, stock.ItemId}]
+		if !ok {
+			continue
+		}
+
+		onHand := stock.Stocks
+		if stock.StoreId == 0 {
+			onHand += storeStocks[stock.ItemId]
+		}
+
+		result := forecast.Forecast(history, historyStart.Weekday(), forecast.DefaultAlpha, horizon)
+		leadTimeDemand := result.Sum(leadTimeDays)
+		safetyStock := result.SafetyStock(z, leadTimeDays)
+		reorderPoint := leadTimeDemand + safetyStock
+		if float64(onHand) > reorderPoint {
+			continue
+		}
+		suggested := int(math.Ceil(result.Sum(horizon) + safetyStock - float64(onHand)))
+		if suggested <= 0 {
+			continue
+		}
+
+		if location == nil || location.StoreId != stock.StoreId {
+			location = &repository.ReorderLocation{
+				StoreId:      stock.StoreId,
+				LocationName: stock.LocationName,
+				Items:        make([]*repository.ReorderSuggestion, 0),
+			}
+			locations = append(locations, location)
+		}
+		location.Items = append(location.Items, &repository.ReorderSuggestion{
+			ItemId:             stock.ItemId,
+			ItemName:           stock.ItemName,
+			OnHand:             onHand,
+			AverageDailyDemand: round2(result.Sum(horizon) / float64(horizon)),
+			LeadTimeDemand:     round2(leadTimeDemand),
+			SafetyStock:        round2(safetyStock),
+			ReorderPoint:       round2(reorderPoint),
+			SuggestedQuantity:  suggested,
+		})
+	}
+
+	for _, location := range locations {
+		sort.SliceStable(location.Items, func(i, j int) bool {
+			return location.Items[i].SuggestedQuantity > location.Items[j].SuggestedQuantity
+		})
+	}
+
+	return locations
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+/*
+classifyABC expect rows ordered by revenue (highest first).
+Item is A while the revenue before it is under 80%, B under 95%, the rest is C.
+So the top item is always A even it alone is more than 80%
+*/
+func classifyABC(rows []*repository.ItemRankingRow) *repository.ABCAnalysis {
+	analysis := &repository.ABCAnalysis{Items: make([]*repository.ABCItem, len(rows))}
+	for _, row := range rows {
+		analysis.TotalRevenue += row.TotalRevenue
+	}
+
+	cumulative := 0.0
+	for i, row := range rows {
+		item := &repository.ABCItem{ItemRankingRow: *row}
+		if analysis.TotalRevenue > 0 {
+			item.RevenueShare = float64(row.TotalRevenue) * 100 / float64(analysis.TotalRevenue)
+		}
+
+		switch {
+		case analysis.TotalRevenue > 0 && cumulative < abcClassALimit:
+			item.Class = repository.ABCClassA
+			analysis.CountA++
+		case analysis.TotalRevenue > 0 && cumulative < abcClassBLimit:
+			item.Class = repository.ABCClassB
+			analysis.CountB++
+		default:
+			item.Class = repository.ABCClassC
+			analysis.CountC++
+		}
+
+		cumulative += item.RevenueShare
+		item.CumulativeShare = cumulative
+		analysis.Items[i] = item
+	}
+
+	return analysis
+}
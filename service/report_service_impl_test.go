@@ -0,0 +1,267 @@
+package service
+
+import (
+	"cashier-api/helper/query"
+	"cashier-api/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const STORE_ID = 1
+
+	t.Run("GetTopSellers", func(t *testing.T) {
+		t.Run("DefaultMetric", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			rows := []*repository.ItemRankingRow{{ItemId: 1, ItemName: "Latte", TotalQuantity: 10}}
+			reportRepo.Mock.On("GetItemRanking", TENANT_ID, STORE_ID, 0, repository.ItemRankingByQuantity, (*query.DateFilter)(nil), 10).
+				Return(rows, nil)
+
+			result, err := reportService.GetTopSellers(TENANT_ID, STORE_ID, 0, "", nil, 10)
+			require.NoError(t, err)
+			assert.Equal(t, rows, result)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			start, end := int64(200), int64(100)
+
+			_, err := reportService.GetTopSellers(0, 0, 0, repository.ItemRankingByProfit, nil, 10)
+			assert.Error(t, err)
+			_, err = reportService.GetTopSellers(TENANT_ID, -1, 0, repository.ItemRankingByProfit, nil, 10)
+			assert.Error(t, err)
+			_, err = reportService.GetTopSellers(TENANT_ID, 0, -1, repository.ItemRankingByProfit, nil, 10)
+			assert.Error(t, err)
+			_, err = reportService.GetTopSellers(TENANT_ID, 0, 0, "margin", nil, 10)
+			assert.Error(t, err)
+			_, err = reportService.GetTopSellers(TENANT_ID, 0, 0, repository.ItemRankingByProfit, nil, 101)
+			assert.Error(t, err)
+			_, err = reportService.GetTopSellers(TENANT_ID, 0, 0, repository.ItemRankingByProfit, &query.DateFilter{StartDate: &start, EndDate: &end}, 10)
+			assert.Error(t, err)
+
+			reportRepo.Mock.AssertNotCalled(t, "GetItemRanking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("GetSlowMovers", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			// Page is decreased by 1, since is N days ago
+			reportRepo.Mock.On("GetSlowMovers", TENANT_ID, STORE_ID, 0, mock.MatchedBy(func(since time.Time) bool {
+				return time.Since(since) > 29*24*time.Hour && time.Since(since) < 31*24*time.Hour
+			}), 10, 0).Return([]*repository.SlowMoverRow{{ItemId: 1, Stocks: 5}}, 1, nil)
+
+			rows, count, err := reportService.GetSlowMovers(TENANT_ID, STORE_ID, 0, 30, 10, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.Len(t, rows, 1)
+			reportRepo.Mock.AssertExpectations(t)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			_, _, err := reportService.GetSlowMovers(TENANT_ID, STORE_ID, 0, 0, 10, 1)
+			assert.Error(t, err)
+			_, _, err = reportService.GetSlowMovers(TENANT_ID, STORE_ID, 0, 366, 10, 1)
+			assert.Error(t, err)
+			_, _, err = reportService.GetSlowMovers(TENANT_ID, STORE_ID, 0, 30, 0, 1)
+			assert.Error(t, err)
+			_, _, err = reportService.GetSlowMovers(TENANT_ID, STORE_ID, 0, 30, 10, 0)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("GetABCAnalysis", func(t *testing.T) {
+		t.Run("Classify", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			reportRepo.Mock.On("GetItemRanking", TENANT_ID, 0, 0, repository.ItemRankingByRevenue, (*query.DateFilter)(nil), 0).
+				Return([]*repository.ItemRankingRow{
+					{ItemId: 1, TotalRevenue: 600}, // 0%   -> A
+					{ItemId: 2, TotalRevenue: 200}, // 60%  -> A
+					{ItemId: 3, TotalRevenue: 100}, // 80%  -> B
+					{ItemId: 4, TotalRevenue: 60},  // 90%  -> B
+					{ItemId: 5, TotalRevenue: 40},  // 96%  -> C
+				}, nil)
+
+			analysis, err := reportService.GetABCAnalysis(TENANT_ID, 0, 0, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 1000, analysis.TotalRevenue)
+			assert.Equal(t, 2, analysis.CountA)
+			assert.Equal(t, 2, analysis.CountB)
+			assert.Equal(t, 1, analysis.CountC)
+			assert.Equal(t, repository.ABCClassC, analysis.Items[4].Class)
+			assert.InDelta(t, 60.0, analysis.Items[0].RevenueShare, 0.001)
+			assert.InDelta(t, 100.0, analysis.Items[4].CumulativeShare, 0.001)
+		})
+
+		t.Run("DominantItemIsA", func(t *testing.T) {
+			analysis := classifyABC([]*repository.ItemRankingRow{{ItemId: 1, TotalRevenue: 990}, {ItemId: 2, TotalRevenue: 10}})
+			assert.Equal(t, repository.ABCClassA, analysis.Items[0].Class)
+			assert.Equal(t, repository.ABCClassC, analysis.Items[1].Class)
+		})
+
+		t.Run("NoRevenue", func(t *testing.T) {
+			analysis := classifyABC([]*repository.ItemRankingRow{{ItemId: 1}})
+			assert.Equal(t, 0, analysis.TotalRevenue)
+			assert.Equal(t, repository.ABCClassC, analysis.Items[0].Class)
+		})
+
+		t.Run("RepositoryError", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			reportRepo.Mock.On("GetItemRanking", TENANT_ID, 0, 0, repository.ItemRankingByRevenue, (*query.DateFilter)(nil), 0).
+				Return(nil, errors.New("database connection failed"))
+
+			analysis, err := reportService.GetABCAnalysis(TENANT_ID, 0, 0, nil)
+			assert.Error(t, err)
+			assert.Nil(t, analysis)
+		})
+	})
+
+	t.Run("GetCashierPerformance", func(t *testing.T) {
+		t.Run("NormalGet", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			reportRepo.Mock.On("GetCashierPerformance", TENANT_ID, 0, (*query.DateFilter)(nil)).Return([]*repository.CashierPerformanceRow{
+				{UserId: 1, TransactionCount: 4, TotalSubtotal: 100_000, TotalRevenue: 90_000, TotalDiscount: 10_000, VoidCount: 1},
+				{UserId: 2},
+			}, nil)
+
+			rows, err := reportService.GetCashierPerformance(TENANT_ID, 0, nil)
+			assert.NoError(t, err)
+			assert.InDelta(t, 22_500.0, rows[0].AverageBasket, 0.001)
+			assert.InDelta(t, 10.0, rows[0].DiscountRate, 0.001)
+
+			// Without transaction
+			assert.Zero(t, rows[1].AverageBasket)
+			assert.Zero(t, rows[1].DiscountRate)
+		})
+
+		t.Run("InvalidFilter", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			_, err := reportService.GetCashierPerformance(0, 0, nil)
+			assert.Error(t, err)
+
+			_, err = reportService.GetCashierPerformance(TENANT_ID, -1, nil)
+			assert.Error(t, err)
+			reportRepo.Mock.AssertNotCalled(t, "GetCashierPerformance", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("GetInventoryValuation", func(t *testing.T) {
+		reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+		reportService := NewReportServiceImpl(reportRepo)
+
+		reportRepo.Mock.On("GetInventoryValuation", TENANT_ID, 0).Return([]*repository.InventoryValuationRow{
+			{StoreId: 0, LocationName: "Warehouse", CategoryId: 1, CategoryName: "Drinks", ItemCount: 2, TotalStocks: 10, CostValue: 10_000},
+			{StoreId: STORE_ID, LocationName: "Store", CategoryId: 2, CategoryName: "Snacks", ItemCount: 1, TotalStocks: 5, CostValue: 20_000, RetailValue: 30_000},
+			{StoreId: STORE_ID, LocationName: "Store", CategoryId: 1, CategoryName: "Drinks", ItemCount: 1, TotalStocks: 3, CostValue: 3_000, RetailValue: 6_000},
+		}, nil)
+
+		valuation, err := reportService.GetInventoryValuation(TENANT_ID, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 33_000, valuation.TotalCostValue)
+		assert.Equal(t, 36_000, valuation.TotalRetailValue)
+		// Warehouse has no selling price, not a loss
+		assert.Equal(t, 13_000, valuation.TotalPotentialProfit)
+
+		require.Len(t, valuation.Locations, 2)
+		assert.Equal(t, "Warehouse", valuation.Locations[0].LocationName)
+		assert.Zero(t, valuation.Locations[0].PotentialProfit)
+		assert.Equal(t, 23_000, valuation.Locations[1].CostValue)
+		assert.Len(t, valuation.Locations[1].Categories, 2)
+
+		require.Len(t, valuation.Categories, 2)
+		assert.Equal(t, "Snacks", valuation.Categories[0].CategoryName)
+		assert.Equal(t, 13_000, valuation.Categories[1].CostValue)
+
+		now := time.Now().Unix()
+		_, err = reportService.GetInventoryValuation(TENANT_ID, 0, &now)
+		require.NoError(t, err)
+
+		_, err = reportService.GetInventoryValuation(TENANT_ID, -1, nil)
+		assert.Error(t, err)
+
+		// No stock history, a past date is refused instead of answered with the current stock
+		yesterday := time.Now().AddDate(0, 0, -1).Unix()
+		_, err = reportService.GetInventoryValuation(TENANT_ID, 0, &yesterday)
+		assert.ErrorIs(t, err, ErrValuationAsOfPast)
+	})
+
+	t.Run("GetReorderSuggestions", func(t *testing.T) {
+		t.Run("Suggest", func(t *testing.T) {
+			jakarta, err := time.LoadLocation("Asia/Jakarta")
+			require.NoError(t, err)
+			historyStart := time.Date(2026, 1, 5, 0, 0, 0, 0, jakarta)
+
+			// 2 sold every day for 2 weeks, the sale date is the local day
+			sales := []*repository.DailyItemSalesRow{
+				{StoreId: STORE_ID, ItemId: 1, SaleDate: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC), Quantity: 99}, // Before the history
+			}
+			for day := 0; day < 14; day++ {
+				sales = append(sales, &repository.DailyItemSalesRow{
+					StoreId: STORE_ID, ItemId: 1, SaleDate: time.Date(2026, 1, 5+day, 0, 0, 0, 0, time.UTC), Quantity: 2,
+				})
+			}
+			stocks := []*repository.ReorderStockRow{
+				{StoreId: 0, LocationName: "Warehouse", ItemId: 1, ItemName: "Latte", Stocks: 10},
+				{StoreId: 0, LocationName: "Warehouse", ItemId: 2, ItemName: "Never Sold", Stocks: 0},
+				{StoreId: STORE_ID, LocationName: "Store", ItemId: 1, ItemName: "Latte", Stocks: 3},
+			}
+
+			locations := suggestReorders(stocks, sales, historyStart, 14, 7, 1.65)
+			require.Len(t, locations, 2)
+
+			// Demand of the lead time and the review days (14 * 2), stable demand need no safety stock
+			warehouse := locations[0]
+			require.Len(t, warehouse.Items, 1)
+			assert.Equal(t, 13, warehouse.Items[0].OnHand)
+			assert.Equal(t, 14.0, warehouse.Items[0].ReorderPoint)
+			assert.Equal(t, 15, warehouse.Items[0].SuggestedQuantity)
+
+			store := locations[1]
+			assert.Equal(t, STORE_ID, store.StoreId)
+			require.Len(t, store.Items, 1)
+			assert.Equal(t, 2.0, store.Items[0].AverageDailyDemand)
+			assert.Equal(t, 25, store.Items[0].SuggestedQuantity)
+
+			// Enough stock is not suggested
+			stocks[2].Stocks = 15
+			locations = suggestReorders(stocks[2:], sales, historyStart, 14, 7, 1.65)
+			assert.Len(t, locations, 0)
+		})
+
+		t.Run("InvalidParameter", func(t *testing.T) {
+			reportRepo := repository.NewReportRepositoryMock(&mock.Mock{}).(*repository.ReportRepositoryMock)
+			reportService := NewReportServiceImpl(reportRepo)
+
+			_, err := reportService.GetReorderSuggestions(TENANT_ID, 0, 7, 7, 95)
+			assert.Error(t, err)
+			_, err = reportService.GetReorderSuggestions(TENANT_ID, 0, 56, 0, 95)
+			assert.Error(t, err)
+			_, err = reportService.GetReorderSuggestions(TENANT_ID, 0, 56, 7, 80)
+			assert.Error(t, err)
+			reportRepo.Mock.AssertNotCalled(t, "GetReorderStocks", mock.Anything, mock.Anything)
+		})
+	})
+}
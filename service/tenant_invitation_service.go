@@ -0,0 +1,31 @@
+package service
+
+import "cashier-api/model"
+
+type TenantInvitationService interface {
+	/*
+		Invite the email to the tenant with the role and email the invite link, valid for 7 days.
+		Owner or manager only, only the owner can invite a manager
+	*/
+	Invite(tenantId int, email string, role model.TenantRole, sub int) (*model.TenantInvitation, error)
+
+	/*
+		Invitations not accepted nor revoked yet, expired included
+	*/
+	GetPending(tenantId int, limit, page int) ([]*model.TenantInvitation, int, error)
+
+	/*
+		Email a new invite link of the pending invitation, the previous link stop working
+	*/
+	Resend(tenantId int, invitationId int, sub int) (*model.TenantInvitation, error)
+
+	/*
+		Revoke the pending invitation
+	*/
+	Revoke(tenantId int, invitationId int, sub int) error
+
+	/*
+		Accept the invitation as the signed in user, he must be signed in with the invited email
+	*/
+	Accept(invitationToken string, sub int) (*model.UserMtmTenant, error)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cashier-api/helper/mail"
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const invitationTokenSize = 32
+
+type TenantInvitationServiceImpl struct {
+	Repository repository.TenantInvitationRepository
+	Mailer     mail.Mailer
+	EmailRegex *regexp.Regexp
+
+	// Front end base url of the emailed links (APP_URL), only the token is sent without it
+	AppUrl string
+}
+
+func NewTenantInvitationServiceImpl(repository repository.TenantInvitationRepository, mailer mail.Mailer) TenantInvitationService {
+	return &TenantInvitationServiceImpl{
+		Repository: repository,
+		Mailer:     mailer,
+		EmailRegex: regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`),
+		AppUrl:     strings.TrimSuffix(os.Getenv("APP_URL"), "/"),
+	}
+}
+
+// Invite implements TenantInvitationService.
+func (service *TenantInvitationServiceImpl) Invite(tenantId int, email string, role model.TenantRole, sub int) (*model.TenantInvitation, error) {
+	email = model.NormalizeEmail(email)
+	if !service.EmailRegex.MatchString(email) {
+		return nil, errors.New("Could not invite. Check input email")
+	}
+	if !role.IsValid() {
+		return nil, fmt.Errorf("Invalid role: %s", role)
+	}
+
+	plainToken, err := token.New(invitationTokenSize)
+	if err != nil {
+		return nil, errors.New("Failed to create token")
+	}
+
+	now := time.Now()
+	invitation, err := service.Repository.Create(&model.TenantInvitation{
+		TenantId:  tenantId,
+		Email:     email,
+		Role:      role,
+		TokenHash: token.Hash(plainToken),
+		ExpiresAt: now.Add(model.TenantInvitationLifetime),
+		SentAt:    now,
+	}, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, service.send(invitation, plainToken)
+}
+
+// GetPending implements TenantInvitationService.
+func (service *TenantInvitationServiceImpl) GetPending(tenantId int, limit, page int) ([]*model.TenantInvitation, int, error) {
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	return service.Repository.GetPending(tenantId, limit, page-1)
+}
+
+// Resend implements TenantInvitationService.
+func (service *TenantInvitationServiceImpl) Resend(tenantId int, invitationId int, sub int) (*model.TenantInvitation, error) {
+	plainToken, err := token.New(invitationTokenSize)
+	if err != nil {
+		return nil, errors.New("Failed to create token")
+	}
+
+	invitation, err := service.Repository.Renew(tenantId, invitationId, sub, token.Hash(plainToken), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, service.send(invitation, plainToken)
+}
+
+// Revoke implements TenantInvitationService.
+func (service *TenantInvitationServiceImpl) Revoke(tenantId int, invitationId int, sub int) error {
+	return service.Repository.Revoke(tenantId, invitationId, sub)
+}
+
+// Accept implements TenantInvitationService.
+func (service *TenantInvitationServiceImpl) Accept(invitationToken string, sub int) (*model.UserMtmTenant, error) {
+	if invitationToken == "" {
+		return nil, repository.ErrInvitationInvalid
+	}
+
+	return service.Repository.Accept(token.Hash(invitationToken), sub, time.Now())
+}
+
+// The invitation is kept when the email fail, it can be resent
+func (service *TenantInvitationServiceImpl) send(invitation *model.TenantInvitation, plainToken string) error {
+	err := service.Mailer.Send(invitationMessage(invitation, plainToken, service.AppUrl))
+	if err != nil {
+		log.Errorf("[TenantInvitationService:send] Could not send the invitation %d, reason: %s", invitation.Id, err.Error())
+		return errors.New("Something gone wrong here ! Could not send the invitation email, please resend it")
+	}
+
+	return nil
+}
+
+func invitationMessage(invitation *model.TenantInvitation, plainToken string, appUrl string) *mail.Message {
+	link := plainToken
+	if appUrl != "" {
+		link = appUrl + "/accept_invitation?token=" + url.QueryEscape(plainToken)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi,\n\nYou are invited to join %s as %s.\n\n", invitation.TenantName, strings.ToLower(string(invitation.Role)))
+	fmt.Fprintf(&body, "Sign up or sign in with this email, then use this to accept it. It expire in %d day(s):\n\n", int(model.TenantInvitationLifetime.Hours()/24))
+	fmt.Fprintf(&body, "%s\n\n", link)
+	body.WriteString("If you do not know this tenant, you can ignore this email.\n")
+
+	return &mail.Message{
+		To:      []string{invitation.Email},
+		Subject: fmt.Sprintf("You are invited to join %s", invitation.TenantName),
+		Body:    body.String(),
+	}
+}
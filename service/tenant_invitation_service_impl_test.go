@@ -0,0 +1,117 @@
+package service
+
+import (
+	"cashier-api/helper/mail"
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantInvitationServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const USER_ID = 5
+
+	newService := func() (*TenantInvitationServiceImpl, *repository.TenantInvitationRepositoryMock, *mail.MemoryMailer) {
+		invitationRepo := repository.NewTenantInvitationRepositoryMock(&mock.Mock{}).(*repository.TenantInvitationRepositoryMock)
+		mailer := mail.NewMemoryMailer()
+		invitationService := NewTenantInvitationServiceImpl(invitationRepo, mailer).(*TenantInvitationServiceImpl)
+		invitationService.AppUrl = "https://app.example.com"
+		return invitationService, invitationRepo, mailer
+	}
+
+	t.Run("Invite", func(t *testing.T) {
+		invitationService, invitationRepo, mailer := newService()
+
+		_, err := invitationService.Invite(TENANT_ID, "not an email", model.TenantRoleCashier, USER_ID)
+		assert.NotNil(t, err)
+		_, err = invitationService.Invite(TENANT_ID, "new@example.com", "OWNER", USER_ID)
+		assert.NotNil(t, err)
+
+		created := &model.TenantInvitation{}
+		invitationRepo.Mock.On("Create", mock.MatchedBy(func(invitation *model.TenantInvitation) bool {
+			return invitation.Email == "new@example.com" && invitation.Role == model.TenantRoleManager && invitation.TokenHash != ""
+		}), USER_ID).Run(func(args mock.Arguments) {
+			*created = *args.Get(0).(*model.TenantInvitation)
+			created.Id = 3
+			created.TenantName = "Toko"
+		}).Return(created, nil).Once()
+
+		invitation, err := invitationService.Invite(TENANT_ID, " New@Example.com ", model.TenantRoleManager, USER_ID)
+		require.Nil(t, err)
+		assert.Equal(t, 3, invitation.Id)
+
+		message := mailer.Last()
+		require.NotNil(t, message)
+		assert.Equal(t, []string{"new@example.com"}, message.To)
+		assert.Contains(t, message.Subject, "Toko")
+
+		// Only the hash is stored, the emailed link carry the token
+		plainToken := message.Body[strings.Index(message.Body, "token=")+len("token=") : strings.Index(message.Body, "\n\nIf you")]
+		assert.Equal(t, token.Hash(plainToken), invitation.TokenHash)
+	})
+
+	t.Run("InviteMailFailed", func(t *testing.T) {
+		invitationService, invitationRepo, mailer := newService()
+		mailer.Err = errors.New("smtp down")
+
+		invitationRepo.Mock.On("Create", mock.AnythingOfType("*model.TenantInvitation"), USER_ID).
+			Return(&model.TenantInvitation{Id: 3, Email: "new@example.com", Role: model.TenantRoleCashier}, nil).Once()
+
+		// Kept, it can be resent
+		invitation, err := invitationService.Invite(TENANT_ID, "new@example.com", model.TenantRoleCashier, USER_ID)
+		assert.NotNil(t, err)
+		assert.Equal(t, 3, invitation.Id)
+	})
+
+	t.Run("Resend", func(t *testing.T) {
+		invitationService, invitationRepo, mailer := newService()
+
+		invitationRepo.Mock.On("Renew", TENANT_ID, 3, USER_ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+			Return(&model.TenantInvitation{Id: 3, Email: "new@example.com", Role: model.TenantRoleCashier}, nil).Once()
+		invitationRepo.Mock.On("Renew", TENANT_ID, 4, USER_ID, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+			Return(nil, repository.ErrInvitationNotFound).Once()
+
+		_, err := invitationService.Resend(TENANT_ID, 3, USER_ID)
+		require.Nil(t, err)
+		assert.Len(t, mailer.Messages(), 1)
+
+		_, err = invitationService.Resend(TENANT_ID, 4, USER_ID)
+		assert.ErrorIs(t, err, repository.ErrInvitationNotFound)
+		assert.Len(t, mailer.Messages(), 1)
+	})
+
+	t.Run("GetPending", func(t *testing.T) {
+		invitationService, invitationRepo, _ := newService()
+
+		_, _, err := invitationService.GetPending(TENANT_ID, 0, 1)
+		assert.NotNil(t, err)
+		_, _, err = invitationService.GetPending(TENANT_ID, 10, 0)
+		assert.NotNil(t, err)
+
+		invitationRepo.Mock.On("GetPending", TENANT_ID, 10, 0).Return([]*model.TenantInvitation{{Id: 3}}, 1, nil).Once()
+		invitations, count, err := invitationService.GetPending(TENANT_ID, 10, 1)
+		require.Nil(t, err)
+		assert.Equal(t, 1, count)
+		assert.Len(t, invitations, 1)
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		invitationService, invitationRepo, _ := newService()
+
+		_, err := invitationService.Accept("", USER_ID)
+		assert.ErrorIs(t, err, repository.ErrInvitationInvalid)
+
+		invitationRepo.Mock.On("Accept", token.Hash("invite-token"), USER_ID, mock.AnythingOfType("time.Time")).
+			Return(&model.UserMtmTenant{UserId: USER_ID, TenantId: TENANT_ID, Role: model.TenantRoleCashier}, nil).Once()
+		userMtmTenant, err := invitationService.Accept("invite-token", USER_ID)
+		require.Nil(t, err)
+		assert.Equal(t, TENANT_ID, userMtmTenant.TenantId)
+	})
+}
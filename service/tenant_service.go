@@ -1,6 +1,9 @@
 package service
 
-import "cashier-api/model"
+import (
+	"cashier-api/model"
+	"time"
+)
 
 type TenantService interface {
 	/*
@@ -30,4 +33,59 @@ type TenantService interface {
 		Get 1 tenant users/members
 	*/
 	GetTenantMembers(tenantId int, sub int) ([]*model.User, error)
+
+	/*
+		Set the timezone used by reports (IANA name, e.g. Asia/Jakarta),
+		only tenant owner allowed
+	*/
+	SetTimezone(tenantId int, timezone string, sub int) error
+
+	/*
+		Force 2FA for the owner and managers, only tenant owner allowed.
+		Without 2FA they are refused by RestrictByTenant until they enroll
+	*/
+	SetTwoFactorPolicy(tenantId int, required bool, sub int) error
+
+	/*
+		Set a member role (MANAGER, CASHIER), only tenant owner allowed
+	*/
+	SetMemberRole(tenantId int, userId int, role model.TenantRole, sub int) error
+
+	/*
+		Ask the member to become the owner, applied only when he accept within 72 hours.
+		Only tenant owner allowed
+	*/
+	RequestOwnershipTransfer(tenantId int, toUserId int, sub int) (*model.TenantOwnershipTransfer, error)
+
+	/*
+		The pending ownership transfer of the tenant
+	*/
+	GetOwnershipTransfer(tenantId int) (*model.TenantOwnershipTransfer, error)
+
+	/*
+		Accept the pending transfer addressed to the user, he become the owner
+		and the previous owner stay as a manager
+	*/
+	AcceptOwnershipTransfer(tenantId int, sub int) (*model.TenantOwnershipTransfer, error)
+
+	/*
+		Cancel the pending transfer, by the owner or by the member declining it
+	*/
+	CancelOwnershipTransfer(tenantId int, sub int) error
+
+	/*
+		Deactivate the tenant, confirmed by its name. Writes are refused and the data can still be exported,
+		until it is deleted for good after the grace period. Only tenant owner allowed
+	*/
+	ArchiveTenant(tenantId int, confirmName string, sub int) (*model.Tenant, error)
+
+	/*
+		Activate again the archived tenant before its deletion. Only tenant owner allowed
+	*/
+	RestoreTenant(tenantId int, sub int) error
+
+	/*
+		Delete for good the tenants archived for longer than the grace period, return how many were deleted
+	*/
+	DeleteArchivedTenants(now time.Time) (int, error)
 }
@@ -6,8 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type TenantServiceImpl struct {
@@ -105,3 +108,144 @@ func (service *TenantServiceImpl) GetTenantMembers(tenantId int, sub int) ([]*mo
 
 	return nil, errors.New("[TenantService:GetTenantMembers]")
 }
+
+// SetTimezone implements TenantService.
+func (service *TenantServiceImpl) SetTimezone(tenantId int, timezone string, sub int) error {
+	if tenantId <= 0 {
+		return errors.New("Tenant id is Required !")
+	}
+
+	timezone = strings.TrimSpace(timezone)
+	// "Local" depends on the server, never stored
+	if timezone == "" || timezone == "Local" {
+		return fmt.Errorf("Invalid timezone: %s", timezone)
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("Invalid timezone: %s", timezone)
+	}
+
+	err := service.Repository.SetTimezone(tenantId, sub, timezone)
+	if err != nil && err.Error() == "[TenantRepository:SetTimezone]" {
+		log.Warnf("Forbidden action detected ! tenantId: %d, sub: %d; Performing SetTimezone", tenantId, sub)
+		return errors.New("[TenantService:SetTimezone]")
+	}
+
+	return err
+}
+
+// SetTwoFactorPolicy implements TenantService.
+func (service *TenantServiceImpl) SetTwoFactorPolicy(tenantId int, required bool, sub int) error {
+	if tenantId <= 0 {
+		return errors.New("Tenant id is Required !")
+	}
+
+	err := service.Repository.SetTwoFactorPolicy(tenantId, sub, required)
+	if err != nil && err.Error() == "[TenantRepository:SetTwoFactorPolicy]" {
+		log.Warnf("Forbidden action detected ! tenantId: %d, sub: %d; Performing SetTwoFactorPolicy", tenantId, sub)
+		return errors.New("[TenantService:SetTwoFactorPolicy]")
+	}
+
+	return err
+}
+
+// SetMemberRole implements TenantService.
+func (service *TenantServiceImpl) SetMemberRole(tenantId int, userId int, role model.TenantRole, sub int) error {
+	if tenantId <= 0 {
+		return errors.New("Tenant id is Required !")
+	} else if !role.IsValid() {
+		return fmt.Errorf("Invalid role: %s. Allowed: %s, %s", role, model.TenantRoleManager, model.TenantRoleCashier)
+	} else if userId == sub {
+		return errors.New("Owner role could not be changed")
+	}
+
+	err := service.Repository.SetMemberRole(tenantId, sub, userId, role)
+	if err != nil && err.Error() == "[TenantRepository:SetMemberRole]" {
+		log.Warnf("Forbidden action detected ! tenantId: %d, sub: %d; Performing SetMemberRole", tenantId, sub)
+		return errors.New("[TenantService:SetMemberRole]")
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("User is not a member of the tenant")
+	}
+
+	return err
+}
+
+// RequestOwnershipTransfer implements TenantService.
+func (service *TenantServiceImpl) RequestOwnershipTransfer(tenantId int, toUserId int, sub int) (*model.TenantOwnershipTransfer, error) {
+	if tenantId <= 0 {
+		return nil, errors.New("Tenant id is Required !")
+	} else if toUserId <= 0 || toUserId == sub {
+		return nil, repository.ErrOwnershipTransferTarget
+	}
+
+	return service.Repository.RequestOwnershipTransfer(&model.TenantOwnershipTransfer{
+		TenantId:   tenantId,
+		FromUserId: sub,
+		ToUserId:   toUserId,
+		ExpiresAt:  time.Now().Add(model.TenantOwnershipTransferLifetime),
+	})
+}
+
+// GetOwnershipTransfer implements TenantService.
+func (service *TenantServiceImpl) GetOwnershipTransfer(tenantId int) (*model.TenantOwnershipTransfer, error) {
+	return service.Repository.FindOwnershipTransfer(tenantId, time.Now())
+}
+
+// AcceptOwnershipTransfer implements TenantService.
+func (service *TenantServiceImpl) AcceptOwnershipTransfer(tenantId int, sub int) (*model.TenantOwnershipTransfer, error) {
+	return service.Repository.AcceptOwnershipTransfer(tenantId, sub, time.Now())
+}
+
+// CancelOwnershipTransfer implements TenantService.
+func (service *TenantServiceImpl) CancelOwnershipTransfer(tenantId int, sub int) error {
+	return service.Repository.CancelOwnershipTransfer(tenantId, sub, time.Now())
+}
+
+// ArchiveTenant implements TenantService.
+func (service *TenantServiceImpl) ArchiveTenant(tenantId int, confirmName string, sub int) (*model.Tenant, error) {
+	if tenantId <= 0 {
+		return nil, errors.New("Tenant id is Required !")
+	}
+
+	tenant, err := service.Repository.Archive(tenantId, sub, confirmName, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantOwnerOnly) {
+			log.Warnf("Forbidden action detected ! tenantId: %d, sub: %d; Performing ArchiveTenant", tenantId, sub)
+		}
+		return nil, err
+	}
+
+	log.Infof("Tenant %d archived by user %d, deleted at %s", tenantId, sub, tenant.DeletesAt().Format(time.RFC3339))
+	return tenant, nil
+}
+
+// RestoreTenant implements TenantService.
+func (service *TenantServiceImpl) RestoreTenant(tenantId int, sub int) error {
+	if tenantId <= 0 {
+		return errors.New("Tenant id is Required !")
+	}
+
+	return service.Repository.Restore(tenantId, sub)
+}
+
+// DeleteArchivedTenants implements TenantService.
+func (service *TenantServiceImpl) DeleteArchivedTenants(now time.Time) (int, error) {
+	tenantIds, err := service.Repository.GetArchivedBefore(now.Add(-model.TenantDeletionGracePeriod))
+	if err != nil {
+		return 0, err
+	}
+
+	// 1 transaction per tenant, a failing one does not keep the others
+	deleted := 0
+	for _, tenantId := range tenantIds {
+		err := service.Repository.Delete(tenantId)
+		if err != nil {
+			log.Errorf("[DeleteArchivedTenants:1] Could not delete the archived tenant %d, reason: %s", tenantId, err.Error())
+			continue
+		}
+		log.Infof("Archived tenant %d deleted", tenantId)
+		deleted++
+	}
+
+	return deleted, nil
+}
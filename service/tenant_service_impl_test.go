@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestTenantServiceImpl(t *testing.T) {
@@ -278,4 +279,113 @@ func TestTenantServiceImpl(t *testing.T) {
 			require.Nil(t, users)
 		})
 	})
+
+	t.Run("SetTimezone", func(t *testing.T) {
+		t.Run("NormalSet", func(t *testing.T) {
+			tenantRepo.Mock = &mock.Mock{}
+			tenantRepo.Mock.On("SetTimezone", 1, 1, "Asia/Jakarta").Return(nil)
+
+			err := tenantService.SetTimezone(1, " Asia/Jakarta ", 1)
+			require.NoError(t, err)
+			tenantRepo.Mock.AssertExpectations(t)
+		})
+
+		t.Run("InvalidTimezone", func(t *testing.T) {
+			tenantRepo.Mock = &mock.Mock{}
+
+			for _, timezone := range []string{"", "Local", "Mars/Olympus"} {
+				err := tenantService.SetTimezone(1, timezone, 1)
+				require.Error(t, err)
+			}
+			tenantRepo.Mock.AssertNotCalled(t, "SetTimezone", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("NotOwner", func(t *testing.T) {
+			tenantRepo.Mock = &mock.Mock{}
+			tenantRepo.Mock.On("SetTimezone", 1, 2, "UTC").Return(errors.New("[TenantRepository:SetTimezone]"))
+
+			err := tenantService.SetTimezone(1, "UTC", 2)
+			require.EqualError(t, err, "[TenantService:SetTimezone]")
+		})
+	})
+
+	t.Run("SetTwoFactorPolicy", func(t *testing.T) {
+		tenantRepo.Mock = &mock.Mock{}
+		tenantRepo.Mock.On("SetTwoFactorPolicy", 1, 1, true).Return(nil).Once()
+		require.NoError(t, tenantService.SetTwoFactorPolicy(1, true, 1))
+
+		tenantRepo.Mock.On("SetTwoFactorPolicy", 1, 2, true).Return(errors.New("[TenantRepository:SetTwoFactorPolicy]")).Once()
+		require.EqualError(t, tenantService.SetTwoFactorPolicy(1, true, 2), "[TenantService:SetTwoFactorPolicy]")
+	})
+
+	t.Run("SetMemberRole", func(t *testing.T) {
+		tenantRepo.Mock = &mock.Mock{}
+		tenantRepo.Mock.On("SetMemberRole", 1, 1, 3, model.TenantRoleManager).Return(nil).Once()
+		require.NoError(t, tenantService.SetMemberRole(1, 3, model.TenantRoleManager, 1))
+
+		require.Error(t, tenantService.SetMemberRole(1, 3, model.TenantRole("OWNER"), 1))
+		require.EqualError(t, tenantService.SetMemberRole(1, 1, model.TenantRoleCashier, 1), "Owner role could not be changed")
+
+		tenantRepo.Mock.On("SetMemberRole", 1, 2, 3, model.TenantRoleCashier).Return(errors.New("[TenantRepository:SetMemberRole]")).Once()
+		require.EqualError(t, tenantService.SetMemberRole(1, 3, model.TenantRoleCashier, 2), "[TenantService:SetMemberRole]")
+
+		tenantRepo.Mock.On("SetMemberRole", 1, 1, 4, model.TenantRoleCashier).Return(gorm.ErrRecordNotFound).Once()
+		require.EqualError(t, tenantService.SetMemberRole(1, 4, model.TenantRoleCashier, 1), "User is not a member of the tenant")
+	})
+
+	t.Run("OwnershipTransfer", func(t *testing.T) {
+		tenantRepo.Mock = &mock.Mock{}
+
+		_, err := tenantService.RequestOwnershipTransfer(1, 1, 1)
+		require.ErrorIs(t, err, repository.ErrOwnershipTransferTarget)
+
+		tenantRepo.Mock.On("RequestOwnershipTransfer", mock.MatchedBy(func(transfer *model.TenantOwnershipTransfer) bool {
+			return transfer.TenantId == 1 && transfer.FromUserId == 1 && transfer.ToUserId == 3 && transfer.ExpiresAt.After(time.Now())
+		})).Return(&model.TenantOwnershipTransfer{Id: 7, TenantId: 1, FromUserId: 1, ToUserId: 3}, nil).Once()
+		transfer, err := tenantService.RequestOwnershipTransfer(1, 3, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 7, transfer.Id)
+
+		tenantRepo.Mock.On("RequestOwnershipTransfer", mock.AnythingOfType("*model.TenantOwnershipTransfer")).
+			Return(nil, repository.ErrTenantOwnerOnly).Once()
+		_, err = tenantService.RequestOwnershipTransfer(1, 3, 2)
+		require.ErrorIs(t, err, repository.ErrTenantOwnerOnly)
+
+		tenantRepo.Mock.On("AcceptOwnershipTransfer", 1, 3, mock.AnythingOfType("time.Time")).
+			Return(&model.TenantOwnershipTransfer{Id: 7, TenantId: 1, FromUserId: 1, ToUserId: 3}, nil).Once()
+		_, err = tenantService.AcceptOwnershipTransfer(1, 3)
+		require.NoError(t, err)
+	})
+
+	t.Run("ArchiveTenant", func(t *testing.T) {
+		tenantRepo.Mock = &mock.Mock{}
+		archivedAt := time.Now()
+
+		tenantRepo.Mock.On("Archive", 1, 1, "Toko", mock.AnythingOfType("time.Time")).
+			Return(&model.Tenant{Id: 1, Name: "Toko", ArchivedAt: &archivedAt}, nil).Once()
+		tenant, err := tenantService.ArchiveTenant(1, "Toko", 1)
+		require.NoError(t, err)
+		assert.NotNil(t, tenant.DeletesAt())
+
+		tenantRepo.Mock.On("Archive", 1, 1, "Tok", mock.AnythingOfType("time.Time")).
+			Return(nil, repository.ErrTenantNameMismatch).Once()
+		_, err = tenantService.ArchiveTenant(1, "Tok", 1)
+		require.ErrorIs(t, err, repository.ErrTenantNameMismatch)
+	})
+
+	t.Run("DeleteArchivedTenants", func(t *testing.T) {
+		tenantRepo.Mock = &mock.Mock{}
+		now := time.Now()
+
+		tenantRepo.Mock.On("GetArchivedBefore", now.Add(-model.TenantDeletionGracePeriod)).Return([]int{4, 5, 6}, nil).Once()
+		tenantRepo.Mock.On("Delete", 4).Return(nil).Once()
+		tenantRepo.Mock.On("Delete", 5).Return(errors.New("deadlock")).Once()
+		tenantRepo.Mock.On("Delete", 6).Return(nil).Once()
+
+		// A failing tenant does not stop the others
+		deleted, err := tenantService.DeleteArchivedTenants(now)
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+		tenantRepo.Mock.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,37 @@
+package service
+
+import "cashier-api/model"
+
+type TerminalService interface {
+	/*
+		Register a shared terminal to a store, the device token is returned once.
+		Owner or manager only
+	*/
+	Register(tenantId int, storeId int, name string, sub int) (*model.TerminalRegistration, error)
+
+	/*
+		Every terminal of the tenant
+	*/
+	GetTerminals(tenantId int) ([]model.Terminal, error)
+
+	/*
+		Revoke the terminal, everyone signed in on it is signed out. Owner or manager only
+	*/
+	Revoke(tenantId int, terminalId int, sub int) error
+
+	/*
+		Set the PIN (4 to 6 digits) of the member in the tenant
+	*/
+	SetPin(tenantId int, userId int, pin string) error
+
+	/*
+		The terminal of the device token and the members who can sign in on it
+	*/
+	GetMembers(deviceToken string) (*model.Terminal, []model.TerminalMember, error)
+
+	/*
+		Sign in the member on the terminal with his PIN. The session last 1 shift without refresh token,
+		its access token only reach the terminal sales routes of the terminal store
+	*/
+	SignInWithPin(deviceToken string, userId int, pin string, device model.SessionDevice) (*model.User, *model.AuthTokens, error)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const terminalTokenSize = 32
+
+var (
+	ErrPinInvalid = errors.New("Wrong PIN")
+	ErrPinLocked  = errors.New("Too many wrong PIN, the PIN is locked for a while")
+)
+
+type TerminalServiceImpl struct {
+	Repository        repository.TerminalRepository
+	UserRepository    repository.UserRepository
+	SessionRepository repository.SessionRepository
+}
+
+func NewTerminalServiceImpl(repository repository.TerminalRepository, userRepository repository.UserRepository, sessionRepository repository.SessionRepository) TerminalService {
+	return &TerminalServiceImpl{
+		Repository:        repository,
+		UserRepository:    userRepository,
+		SessionRepository: sessionRepository,
+	}
+}
+
+// Register implements TerminalService.
+func (service *TerminalServiceImpl) Register(tenantId int, storeId int, name string, sub int) (*model.TerminalRegistration, error) {
+	name = strings.TrimSpace(name)
+	if storeId <= 0 || name == "" {
+		return nil, errors.New("Terminal need a store and a name")
+	} else if len([]rune(name)) > model.SessionDeviceNameMaxLength {
+		return nil, fmt.Errorf("Terminal name is longer than %d characters", model.SessionDeviceNameMaxLength)
+	}
+
+	deviceToken, err := token.New(terminalTokenSize)
+	if err != nil {
+		return nil, errors.New("Failed to create token")
+	}
+
+	terminal, err := service.Repository.Create(&model.Terminal{
+		TenantId:  tenantId,
+		StoreId:   storeId,
+		Name:      name,
+		TokenHash: token.Hash(deviceToken),
+	}, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TerminalRegistration{Terminal: terminal, DeviceToken: deviceToken}, nil
+}
+
+// GetTerminals implements TerminalService.
+func (service *TerminalServiceImpl) GetTerminals(tenantId int) ([]model.Terminal, error) {
+	return service.Repository.GetByTenant(tenantId)
+}
+
+// Revoke implements TerminalService.
+func (service *TerminalServiceImpl) Revoke(tenantId int, terminalId int, sub int) error {
+	return service.Repository.Revoke(tenantId, terminalId, sub)
+}
+
+// SetPin implements TerminalService.
+func (service *TerminalServiceImpl) SetPin(tenantId int, userId int, pin string) error {
+	if !model.IsValidPin(pin) {
+		return errors.New("PIN must be 4 to 6 digits")
+	}
+
+	pinHash, err := bcrypt.GenerateFromPassword([]byte(pin), 10)
+	if err != nil {
+		return errors.New("Something gone wrong here ! Could not set the PIN")
+	}
+
+	return service.Repository.SetPin(tenantId, userId, string(pinHash))
+}
+
+// GetMembers implements TerminalService.
+func (service *TerminalServiceImpl) GetMembers(deviceToken string) (*model.Terminal, []model.TerminalMember, error) {
+	terminal, err := service.Repository.FindByToken(token.Hash(deviceToken))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members, err := service.Repository.GetMembers(terminal.TenantId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return terminal, members, nil
+}
+
+// SignInWithPin implements TerminalService.
+func (service *TerminalServiceImpl) SignInWithPin(deviceToken string, userId int, pin string, device model.SessionDevice) (*model.User, *model.AuthTokens, error) {
+	terminal, err := service.Repository.FindByToken(token.Hash(deviceToken))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memberPin, err := service.Repository.FindPin(terminal.TenantId, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrPinNotSet) {
+			return nil, nil, ErrPinInvalid
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if memberPin.IsLocked(now) {
+		return nil, nil, ErrPinLocked
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(memberPin.PinHash), []byte(pin))
+	if err != nil {
+		lockedUntil, err := service.Repository.FailPin(memberPin.Id, model.PinMaxAttempts, model.PinLockoutDuration, now)
+		if err != nil {
+			log.Errorf("[SignInWithPin:1] Could not count the wrong PIN of user %d, reason: %s", userId, err.Error())
+		}
+		if lockedUntil != nil {
+			log.Warnf("PIN of user %d at tenant %d locked until %s, from terminal %d", userId, terminal.TenantId, lockedUntil.Format(time.RFC3339), terminal.Id)
+			return nil, nil, ErrPinLocked
+		}
+		return nil, nil, ErrPinInvalid
+	}
+
+	err = service.Repository.PinSucceeded(memberPin.Id, terminal.Id, now)
+	if err != nil {
+		log.Warnf("[SignInWithPin:2] Could not reset the PIN attempts of user %d, reason: %s", userId, err.Error())
+	}
+
+	user, err := service.UserRepository.FindById(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	device.DeviceName = terminal.Name
+	newSession := &model.UserSession{
+		UserId:     userId,
+		TerminalId: &terminal.Id,
+		LastSeenAt: &now,
+		ExpiresAt:  now.Add(model.TerminalSessionLifetime),
+	}
+	device.Apply(newSession)
+
+	session, err := service.SessionRepository.Create(newSession, "")
+	if err != nil {
+		log.Errorf("[SignInWithPin:3] Could not create session for user %d, reason: %s", userId, err.Error())
+		return nil, nil, errors.New("Something gone wrong here ! Could not signed in user")
+	}
+
+	accessToken, err := signAccessToken(user, session.Id, session.ExpiresAt)
+	if err != nil {
+		return nil, nil, errors.New("Failed to create token")
+	}
+
+	return publicUser(user), &model.AuthTokens{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
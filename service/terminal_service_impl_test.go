@@ -0,0 +1,114 @@
+package service
+
+import (
+	"cashier-api/helper/token"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestTerminalServiceImpl(t *testing.T) {
+	const TENANT_ID = 1
+	const USER_ID = 5
+
+	newService := func() (TerminalService, *repository.TerminalRepositoryMock, *repository.UserRepositoryMock, *repository.SessionRepositoryMock) {
+		terminalRepo := repository.NewTerminalRepositoryMock(&mock.Mock{}).(*repository.TerminalRepositoryMock)
+		userRepo := repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		sessionRepo := repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		return NewTerminalServiceImpl(terminalRepo, userRepo, sessionRepo), terminalRepo, userRepo, sessionRepo
+	}
+
+	t.Run("Register", func(t *testing.T) {
+		terminalService, terminalRepo, _, _ := newService()
+
+		_, err := terminalService.Register(TENANT_ID, 0, "Till 1", USER_ID)
+		assert.NotNil(t, err)
+		_, err = terminalService.Register(TENANT_ID, 2, "  ", USER_ID)
+		assert.NotNil(t, err)
+
+		terminalRepo.Mock.On("Create", mock.MatchedBy(func(terminal *model.Terminal) bool {
+			return terminal.StoreId == 2 && terminal.Name == "Till 1" && terminal.TokenHash != ""
+		}), USER_ID).Return(&model.Terminal{Id: 3, TenantId: TENANT_ID, StoreId: 2, Name: "Till 1"}, nil).Once()
+		registration, err := terminalService.Register(TENANT_ID, 2, " Till 1 ", USER_ID)
+		require.Nil(t, err)
+		assert.NotEqual(t, "", registration.DeviceToken)
+
+		// Only the hash is stored
+		terminalRepo.Mock.AssertCalled(t, "Create", mock.MatchedBy(func(terminal *model.Terminal) bool {
+			return terminal.TokenHash == token.Hash(registration.DeviceToken)
+		}), USER_ID)
+	})
+
+	t.Run("SetPin", func(t *testing.T) {
+		terminalService, terminalRepo, _, _ := newService()
+
+		for _, pin := range []string{"123", "1234567", "12a4", ""} {
+			assert.NotNil(t, terminalService.SetPin(TENANT_ID, USER_ID, pin), pin)
+		}
+
+		terminalRepo.Mock.On("SetPin", TENANT_ID, USER_ID, mock.MatchedBy(func(pinHash string) bool {
+			return bcrypt.CompareHashAndPassword([]byte(pinHash), []byte("4321")) == nil
+		})).Return(nil).Once()
+		assert.Nil(t, terminalService.SetPin(TENANT_ID, USER_ID, "4321"))
+		terminalRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("SignInWithPin", func(t *testing.T) {
+		if os.Getenv("JWT_S") == "" {
+			t.Skip("Required ENV not available: JWT_S")
+		}
+
+		terminalService, terminalRepo, userRepo, sessionRepo := newService()
+		pinHash, err := bcrypt.GenerateFromPassword([]byte("4321"), bcrypt.MinCost)
+		require.Nil(t, err)
+
+		terminal := &model.Terminal{Id: 3, TenantId: TENANT_ID, StoreId: 2, Name: "Till 1"}
+		terminalRepo.Mock.On("FindByToken", token.Hash("device-token")).Return(terminal, nil)
+		terminalRepo.Mock.On("FindByToken", token.Hash("revoked")).Return(nil, repository.ErrTerminalInvalid)
+		terminalRepo.Mock.On("FindPin", TENANT_ID, USER_ID).Return(&model.TenantMemberPin{Id: 8, PinHash: string(pinHash)}, nil)
+
+		_, _, err = terminalService.SignInWithPin("revoked", USER_ID, "4321", model.SessionDevice{})
+		assert.ErrorIs(t, err, repository.ErrTerminalInvalid)
+
+		// Wrong PIN, then the one locking it
+		terminalRepo.Mock.On("FailPin", 8, model.PinMaxAttempts, model.PinLockoutDuration, mock.AnythingOfType("time.Time")).Return(nil, nil).Once()
+		_, _, err = terminalService.SignInWithPin("device-token", USER_ID, "0000", model.SessionDevice{})
+		assert.ErrorIs(t, err, ErrPinInvalid)
+
+		lockedUntil := time.Now().Add(model.PinLockoutDuration)
+		terminalRepo.Mock.On("FailPin", 8, model.PinMaxAttempts, model.PinLockoutDuration, mock.AnythingOfType("time.Time")).Return(&lockedUntil, nil).Once()
+		_, _, err = terminalService.SignInWithPin("device-token", USER_ID, "0000", model.SessionDevice{})
+		assert.ErrorIs(t, err, ErrPinLocked)
+
+		terminalRepo.Mock.On("PinSucceeded", 8, 3, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		userRepo.Mock.On("FindById", USER_ID).Return(&model.User{Id: USER_ID, Name: "Cashier"}, nil).Once()
+		sessionRepo.Mock.On("Create", mock.MatchedBy(func(session *model.UserSession) bool {
+			return session.TerminalId != nil && *session.TerminalId == 3 && session.DeviceName == "Till 1"
+		}), "").Return(&model.UserSession{Id: 12, ExpiresAt: time.Now().Add(model.TerminalSessionLifetime)}, nil).Once()
+		user, tokens, err := terminalService.SignInWithPin("device-token", USER_ID, "4321", model.SessionDevice{DeviceName: "ignored"})
+		require.Nil(t, err)
+		assert.Equal(t, USER_ID, user.Id)
+		assert.NotEqual(t, "", tokens.AccessToken)
+		assert.Equal(t, "", tokens.RefreshToken, "A PIN sign in can't be refreshed")
+	})
+
+	t.Run("SignInWithPinLocked", func(t *testing.T) {
+		terminalService, terminalRepo, _, _ := newService()
+
+		lockedUntil := time.Now().Add(time.Minute)
+		terminalRepo.Mock.On("FindByToken", token.Hash("device-token")).Return(&model.Terminal{Id: 3, TenantId: TENANT_ID}, nil)
+		terminalRepo.Mock.On("FindPin", TENANT_ID, USER_ID).Return(&model.TenantMemberPin{Id: 8, LockedUntil: &lockedUntil}, nil)
+
+		// Even the right PIN is refused until the lock end
+		_, _, err := terminalService.SignInWithPin("device-token", USER_ID, "4321", model.SessionDevice{})
+		assert.ErrorIs(t, err, ErrPinLocked)
+		terminalRepo.Mock.AssertNotCalled(t, "FailPin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
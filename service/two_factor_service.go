@@ -0,0 +1,32 @@
+package service
+
+import "cashier-api/model"
+
+type TwoFactorService interface {
+	/*
+		Whether TOTP is enabled, the remaining recovery codes and if a tenant require it
+	*/
+	GetStatus(userId int) (*model.TwoFactorStatus, error)
+
+	/*
+		Start the enrolment with a new secret, shown once as provisioning URI (QR code)
+	*/
+	Enroll(userId int) (*model.TwoFactorEnrolment, error)
+
+	/*
+		Confirm the enrolment with the first code, return the recovery codes (shown once).
+		Every other session is signed out
+	*/
+	Confirm(userId int, currentSessionId int, code string) ([]string, error)
+
+	/*
+		Disable TOTP, a valid code or recovery code is needed.
+		Refused while a tenant require it
+	*/
+	Disable(userId int, code string) error
+
+	/*
+		Replace the recovery codes, a valid code or recovery code is needed
+	*/
+	RegenerateRecoveryCodes(userId int, code string) ([]string, error)
+}
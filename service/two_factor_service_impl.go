@@ -0,0 +1,234 @@
+package service
+
+import (
+	"cashier-api/helper/token"
+	"cashier-api/helper/totp"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Issuer shown by the authenticator app
+const twoFactorIssuer = "Enterprise POS"
+
+var ErrTwoFactorCodeInvalid = errors.New("Invalid two factor code")
+
+type TwoFactorServiceImpl struct {
+	UserRepository      repository.UserRepository
+	TwoFactorRepository repository.TwoFactorRepository
+	SessionRepository   repository.SessionRepository
+}
+
+func NewTwoFactorServiceImpl(userRepository repository.UserRepository, twoFactorRepository repository.TwoFactorRepository, sessionRepository repository.SessionRepository) TwoFactorService {
+	return &TwoFactorServiceImpl{
+		UserRepository:      userRepository,
+		TwoFactorRepository: twoFactorRepository,
+		SessionRepository:   sessionRepository,
+	}
+}
+
+// GetStatus implements TwoFactorService.
+func (service *TwoFactorServiceImpl) GetStatus(userId int) (*model.TwoFactorStatus, error) {
+	user, err := service.UserRepository.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &model.TwoFactorStatus{Enabled: user.IsTwoFactorEnabled(), EnabledAt: user.TotpEnabledAt}
+	if status.Enabled {
+		status.RemainingRecoveryCodes, err = service.TwoFactorRepository.CountRecoveryCodes(userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	status.RequiredByTenant, err = service.TwoFactorRepository.IsRequiredForUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// Enroll implements TwoFactorService.
+func (service *TwoFactorServiceImpl) Enroll(userId int) (*model.TwoFactorEnrolment, error) {
+	user, err := service.UserRepository.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("Two factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = service.TwoFactorRepository.SaveSecret(userId, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorEnrolment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(twoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm implements TwoFactorService.
+func (service *TwoFactorServiceImpl) Confirm(userId int, currentSessionId int, code string) ([]string, error) {
+	user, err := service.UserRepository.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("Two factor authentication is already enabled")
+	} else if user.TotpSecret == "" {
+		return nil, errors.New("No pending two factor enrolment, please enroll first")
+	}
+
+	now := time.Now()
+	step, ok := totp.Validate(user.TotpSecret, code, now, 0)
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = service.TwoFactorRepository.Enable(userId, step, now, recoveryCodeHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions signed in with the password only
+	_, err = service.SessionRepository.RevokeAllOfUser(userId, currentSessionId, model.SessionRevokeTwoFactor)
+	if err != nil {
+		log.Errorf("[TwoFactor:Confirm] Could not revoke the other sessions of user %d, reason: %s", userId, err.Error())
+	}
+
+	return recoveryCodes, nil
+}
+
+// Disable implements TwoFactorService.
+func (service *TwoFactorServiceImpl) Disable(userId int, code string) error {
+	user, err := service.enabledUser(userId)
+	if err != nil {
+		return err
+	}
+
+	required, err := service.TwoFactorRepository.IsRequiredForUser(userId)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.New("Two factor authentication is required by your tenant, it could not be disabled")
+	}
+
+	ok, err := verifySecondFactor(service.TwoFactorRepository, user, code, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	return service.TwoFactorRepository.Disable(userId)
+}
+
+// RegenerateRecoveryCodes implements TwoFactorService.
+func (service *TwoFactorServiceImpl) RegenerateRecoveryCodes(userId int, code string) ([]string, error) {
+	user, err := service.enabledUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := verifySecondFactor(service.TwoFactorRepository, user, code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = service.TwoFactorRepository.ReplaceRecoveryCodes(userId, recoveryCodeHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (service *TwoFactorServiceImpl) enabledUser(userId int) (*model.User, error) {
+	user, err := service.UserRepository.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, errors.New("Two factor authentication is not enabled")
+	}
+	return user, nil
+}
+
+var totpCodeRegex = regexp.MustCompile(`^[0-9]{6}$`)
+
+/*
+verifySecondFactor accept a TOTP code (6 digits) or an unused recovery code, both are used once.
+Shared by the sign in and the 2FA settings
+*/
+func verifySecondFactor(twoFactorRepository repository.TwoFactorRepository, user *model.User, code string, now time.Time) (bool, error) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+
+	if totpCodeRegex.MatchString(code) {
+		step, ok := totp.Validate(user.TotpSecret, code, now, user.TotpLastStep)
+		if !ok {
+			return false, nil
+		}
+		return twoFactorRepository.UseStep(user.Id, step)
+	}
+
+	if code == "" {
+		return false, nil
+	}
+	used, err := twoFactorRepository.UseRecoveryCode(user.Id, token.Hash(normalizeRecoveryCode(code)), now)
+	if used {
+		log.Infof("Recovery code used by user %d", user.Id)
+	}
+	return used, err
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes return the codes shown to the user (xxxx-xxxx) and their hash to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, model.RecoveryCodeCount)
+	hashes := make([]string, 0, model.RecoveryCodeCount)
+
+	buffer := make([]byte, 5) // 40 bits, 8 base32 characters
+	for len(codes) < model.RecoveryCodeCount {
+		if _, err := rand.Read(buffer); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(buffer))
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, token.Hash(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// The dash and the case don't matter when typing the code
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"cashier-api/helper/token"
+	"cashier-api/helper/totp"
+	"cashier-api/model"
+	"cashier-api/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorServiceImpl(t *testing.T) {
+	newService := func() (TwoFactorService, *repository.UserRepositoryMock, *repository.TwoFactorRepositoryMock, *repository.SessionRepositoryMock) {
+		userRepo := repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		twoFactorRepo := repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		sessionRepo := repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		return NewTwoFactorServiceImpl(userRepo, twoFactorRepo, sessionRepo), userRepo, twoFactorRepo, sessionRepo
+	}
+
+	t.Run("EnrollAndConfirm", func(t *testing.T) {
+		twoFactorService, userRepo, twoFactorRepo, sessionRepo := newService()
+
+		userRepo.Mock.On("FindById", 1).Return(&model.User{Id: 1, Email: "owner@gmail.com"}, nil).Once()
+		twoFactorRepo.Mock.On("SaveSecret", 1, mock.AnythingOfType("string")).Return(nil).Once()
+		enrolment, err := twoFactorService.Enroll(1)
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(enrolment.ProvisioningURI, "otpauth://totp/"))
+		assert.Contains(t, enrolment.ProvisioningURI, enrolment.Secret)
+
+		pending := &model.User{Id: 1, TotpSecret: enrolment.Secret}
+		userRepo.Mock.On("FindById", 1).Return(pending, nil)
+		_, err = twoFactorService.Confirm(1, 9, "abc")
+		assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+
+		code, err := totp.Code(enrolment.Secret, totp.Step(time.Now()))
+		require.Nil(t, err)
+		var storedHashes []string
+		twoFactorRepo.Mock.On("Enable", 1, mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]string")).
+			Run(func(args mock.Arguments) { storedHashes = args.Get(3).([]string) }).
+			Return(nil).Once()
+		sessionRepo.Mock.On("RevokeAllOfUser", 1, 9, model.SessionRevokeTwoFactor).Return(2, nil).Once()
+		recoveryCodes, err := twoFactorService.Confirm(1, 9, code)
+		require.Nil(t, err)
+		require.Len(t, recoveryCodes, model.RecoveryCodeCount)
+		require.Len(t, storedHashes, model.RecoveryCodeCount)
+
+		// Only the hash is stored, the dash and case don't matter
+		assert.Equal(t, token.Hash(normalizeRecoveryCode(strings.ToUpper(recoveryCodes[0]))), storedHashes[0])
+		assert.NotContains(t, storedHashes, recoveryCodes[0])
+		sessionRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		twoFactorService, userRepo, twoFactorRepo, _ := newService()
+
+		secret, err := totp.GenerateSecret()
+		require.Nil(t, err)
+		enabledAt := time.Now()
+		userRepo.Mock.On("FindById", 2).Return(&model.User{Id: 2, TotpSecret: secret, TotpEnabledAt: &enabledAt}, nil)
+		userRepo.Mock.On("FindById", 3).Return(&model.User{Id: 3}, nil)
+
+		assert.EqualError(t, twoFactorService.Disable(3, "123456"), "Two factor authentication is not enabled")
+
+		// Required by the tenant
+		twoFactorRepo.Mock.On("IsRequiredForUser", 2).Return(true, nil).Once()
+		assert.NotNil(t, twoFactorService.Disable(2, "abcd-efgh"))
+		twoFactorRepo.Mock.AssertNotCalled(t, "Disable", 2)
+
+		// Unknown recovery code
+		twoFactorRepo.Mock.On("IsRequiredForUser", 2).Return(false, nil)
+		twoFactorRepo.Mock.On("UseRecoveryCode", 2, token.Hash("abcdefgh"), mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+		assert.ErrorIs(t, twoFactorService.Disable(2, "ABCD-EFGH"), ErrTwoFactorCodeInvalid)
+
+		// Replayed TOTP code
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		require.Nil(t, err)
+		twoFactorRepo.Mock.On("UseStep", 2, mock.AnythingOfType("int64")).Return(false, nil).Once()
+		assert.ErrorIs(t, twoFactorService.Disable(2, code), ErrTwoFactorCodeInvalid)
+
+		twoFactorRepo.Mock.On("UseStep", 2, mock.AnythingOfType("int64")).Return(true, nil).Once()
+		twoFactorRepo.Mock.On("Disable", 2).Return(nil).Once()
+		assert.Nil(t, twoFactorService.Disable(2, code))
+		twoFactorRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("GetStatus", func(t *testing.T) {
+		twoFactorService, userRepo, twoFactorRepo, _ := newService()
+
+		enabledAt := time.Now()
+		userRepo.Mock.On("FindById", 4).Return(&model.User{Id: 4, TotpSecret: "secret", TotpEnabledAt: &enabledAt}, nil).Once()
+		twoFactorRepo.Mock.On("CountRecoveryCodes", 4).Return(7, nil).Once()
+		twoFactorRepo.Mock.On("IsRequiredForUser", 4).Return(true, nil).Once()
+		status, err := twoFactorService.GetStatus(4)
+		require.Nil(t, err)
+		assert.True(t, status.Enabled)
+		assert.Equal(t, 7, status.RemainingRecoveryCodes)
+		assert.True(t, status.RequiredByTenant)
+	})
+}
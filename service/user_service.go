@@ -1,6 +1,9 @@
 package service
 
-import "cashier-api/model"
+import (
+	"cashier-api/model"
+	"time"
+)
 
 type UserService interface {
 	/*
@@ -9,7 +12,81 @@ type UserService interface {
 	SignUpWithEmailAndPassword(email string, password string, name string) (*model.User, error)
 
 	/*
-		Log in
+		Log in, start a session with a 15 minutes access token and a rotating refresh token.
+		*TwoFactorRequiredError carry the challenge when 2FA is enabled.
+		*SignInBlockedError while the email or the IP had too many failures, every attempt is audited
 	*/
-	SignInWithEmailAndPassword(email string, password string) (*model.User, string, error)
+	SignInWithEmailAndPassword(email string, password string, device model.SessionDevice) (*model.User, *model.AuthTokens, error)
+
+	/*
+		Second step of the sign in when 2FA is enabled, answer the challenge with a TOTP or recovery code.
+		The challenge expire after 5 wrong codes
+	*/
+	SignInWithTwoFactor(challengeToken string, code string, device model.SessionDevice) (*model.User, *model.AuthTokens, error)
+
+	/*
+		Exchange the refresh token for a new pair, the old refresh token can't be used again.
+		Using it again revoke the whole session
+	*/
+	Refresh(refreshToken string) (*model.User, *model.AuthTokens, error)
+
+	/*
+		Revoke the session of the refresh token, or sessionId (sid claim) when no refresh token is given
+	*/
+	SignOut(refreshToken string, sessionId int) error
+
+	/*
+		Active sessions (signed in devices) of the user, currentSessionId is flagged is_current
+	*/
+	GetSessions(userId int, currentSessionId int) ([]model.UserSession, error)
+
+	/*
+		Revoke 1 session of the user, the device must sign in again
+	*/
+	RevokeSession(userId int, sessionId int) error
+
+	/*
+		Revoke every session of the user, the current one is kept unless includeCurrent
+	*/
+	RevokeAllSessions(userId int, currentSessionId int, includeCurrent bool) (int, error)
+
+	/*
+		Email a single use reset link. Unknown email is not an error, nobody can probe the registered emails
+	*/
+	RequestPasswordReset(email string) error
+
+	/*
+		Set the new password from the emailed token, every session is signed out and the sign in is unlocked
+	*/
+	ResetPassword(resetToken string, newPassword string) error
+
+	/*
+		Need the old password, every other session is signed out
+	*/
+	ChangePassword(userId int, currentSessionId int, oldPassword string, newPassword string) error
+
+	/*
+		Email the verification link again, already verified is an error
+	*/
+	SendEmailVerification(userId int) error
+
+	/*
+		Verify the email from the emailed token
+	*/
+	VerifyEmail(verifyToken string) error
+
+	/*
+		Sign in attempts of the user, latest first
+	*/
+	GetSignInAttempts(userId int, limit, page int) ([]*model.SignInAttempt, int, error)
+
+	/*
+		The tenant owner clear the failed sign in of a member, a locked account can sign in again
+	*/
+	UnlockSignIn(tenantId int, userId int, sub int) error
+
+	/*
+		Background job, delete the refresh tokens of the sessions expired for a day
+	*/
+	DeleteExpiredRefreshTokens(now time.Time) (int, error)
 }
@@ -1,25 +1,82 @@
 package service
 
 import (
+	"cashier-api/helper/mail"
+	"cashier-api/helper/token"
 	"cashier-api/model"
 	"cashier-api/repository"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+const (
+	accessTokenLifetime  = time.Minute * 15
+	refreshTokenLifetime = time.Hour * 24 * 30 // Slide with every refresh
+	refreshTokenSize     = 32
+	userTokenSize        = 32
+)
+
+var (
+	ErrEmailNotVerified          = errors.New("Email is not verified yet. Please open the link sent to your email")
+	ErrTwoFactorChallengeInvalid = errors.New("Two factor sign in is expired or had too many attempts, please sign in again")
+)
+
+// TwoFactorRequiredError is returned by sign in when the password is right and the user enabled 2FA
+type TwoFactorRequiredError struct {
+	Challenge *model.TwoFactorChallenge
+}
+
+func (err *TwoFactorRequiredError) Error() string {
+	return "Two factor authentication code is required"
+}
+
+// SignInBlockedError is returned by sign in while the email or the IP had too many failures, the password is not checked
+type SignInBlockedError struct {
+	RetryAt time.Time
+	Locked  bool // Lockout, not only a backoff. The owner or the password reset can unlock the account
+}
+
+func (err *SignInBlockedError) Error() string {
+	if err.Locked {
+		return fmt.Sprintf("Too many failed sign in, it is locked until %s. Reset the password or ask the tenant owner to unlock it", err.RetryAt.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("Too many failed sign in, please wait %d seconds", int(time.Until(err.RetryAt).Seconds())+1)
+}
+
 type UserServiceImpl struct {
-	Repository repository.UserRepository
+	Repository              repository.UserRepository
+	SessionRepository       repository.SessionRepository
+	UserTokenRepository     repository.UserTokenRepository
+	TwoFactorRepository     repository.TwoFactorRepository
+	SignInAttemptRepository repository.SignInAttemptRepository
+	Mailer                  mail.Mailer
+
+	// Sign in refuse unverified email (REQUIRE_EMAIL_VERIFICATION=true)
+	RequireVerifiedEmail bool
+	// Front end base url of the emailed links (APP_URL), only the token is sent without it
+	AppUrl string
 }
 
-func NewUserServiceImpl(repository repository.UserRepository) UserService {
+func NewUserServiceImpl(repository repository.UserRepository, sessionRepository repository.SessionRepository, userTokenRepository repository.UserTokenRepository, twoFactorRepository repository.TwoFactorRepository, signInAttemptRepository repository.SignInAttemptRepository, mailer mail.Mailer) UserService {
 	return &UserServiceImpl{
-		Repository: repository,
+		Repository:              repository,
+		SessionRepository:       sessionRepository,
+		UserTokenRepository:     userTokenRepository,
+		TwoFactorRepository:     twoFactorRepository,
+		SignInAttemptRepository: signInAttemptRepository,
+		Mailer:                  mailer,
+		RequireVerifiedEmail:    os.Getenv("REQUIRE_EMAIL_VERIFICATION") == "true",
+		AppUrl:                  strings.TrimSuffix(os.Getenv("APP_URL"), "/"),
 	}
 }
 
@@ -57,55 +114,552 @@ func (service *UserServiceImpl) SignUpWithEmailAndPassword(email string, passwor
 		return nil, err
 	}
 
+	// The account exist, the user can ask the link again
+	err = service.sendUserToken(createdNewUser, model.UserTokenEmailVerification)
+	if err != nil {
+		log.Errorf("[SignUp:2] Could not send the verification email to user %d, reason: %s", createdNewUser.Id, err.Error())
+	}
+
 	return createdNewUser, nil
 }
 
 // SignInWithEmailAndPassword implements UserService.
-func (service *UserServiceImpl) SignInWithEmailAndPassword(email string, password string) (*model.User, string, error) {
+func (service *UserServiceImpl) SignInWithEmailAndPassword(email string, password string, device model.SessionDevice) (*model.User, *model.AuthTokens, error) {
 	var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
 	if !emailRegex.MatchString(email) {
-		return nil, "", errors.New("Could not signing in user account. Check input email")
+		return nil, nil, errors.New("Could not signing in user account. Check input email")
 	} else if len(password) < 8 {
-		return nil, "", errors.New("Could not signing in user account. Check input password")
+		return nil, nil, errors.New("Could not signing in user account. Check input password")
+	}
+
+	// Refused before the password is checked, guessing gain nothing while blocked
+	now := time.Now()
+	blocking, err := service.SignInAttemptRepository.FindBlocking(email, device.IpAddress, now)
+	if err != nil {
+		log.Errorf("[SignIn:3] Could not check the failed sign in of %s, reason: %s", email, err.Error())
+		return nil, nil, errors.New("Something gone wrong here ! Could not signed in user")
+	}
+	if blocking != nil {
+		service.recordSignInAttempt(email, nil, device, model.SignInBlocked)
+		return nil, nil, &SignInBlockedError{
+			RetryAt: *blocking.BlockedUntil,
+			Locked:  signInThrottlePolicy(blocking.Scope).IsLockout(blocking.FailedCount),
+		}
 	}
 
 	candidateUser, err := service.Repository.GetByEmail(email)
 	if err != nil {
-		if err.Error() == "(PGRST116) JSON object requested, multiple (or no) rows returned" {
-			return nil, "", errors.New("No user with this credentials")
+		if errors.Is(err, gorm.ErrRecordNotFound) || err.Error() == "(PGRST116) JSON object requested, multiple (or no) rows returned" {
+			return nil, nil, service.signInFailed(email, nil, device, now)
 		}
-		return nil, "", err
+		return nil, nil, err
 	}
 
 	// Compare sent in pass with saved user pass hash
 	err = bcrypt.CompareHashAndPassword([]byte(candidateUser.Password), []byte(password))
 	if err != nil {
-		return nil, "", errors.New("No user with this credentials")
+		return nil, nil, service.signInFailed(email, &candidateUser.Id, device, now)
+	}
+
+	if service.RequireVerifiedEmail && !candidateUser.IsEmailVerified() {
+		return nil, nil, ErrEmailNotVerified
+	}
+
+	if candidateUser.IsTwoFactorEnabled() {
+		challenge, err := service.createTwoFactorChallenge(candidateUser)
+		if err != nil {
+			log.Errorf("[SignIn:2] Could not create two factor challenge for user %d, reason: %s", candidateUser.Id, err.Error())
+			return nil, nil, errors.New("Something gone wrong here ! Could not signed in user")
+		}
+		service.recordSignInAttempt(email, &candidateUser.Id, device, model.SignInTwoFactorRequired)
+		return nil, nil, &TwoFactorRequiredError{Challenge: challenge}
+	}
+
+	service.clearSignInFailures(candidateUser)
+	service.recordSignInAttempt(email, &candidateUser.Id, device, model.SignInSucceeded)
+	return service.startSession(candidateUser, device)
+}
+
+// Count the failure for the email and the IP, an unknown email is counted the same
+func (service *UserServiceImpl) signInFailed(email string, userId *int, device model.SessionDevice, now time.Time) error {
+	service.countSignInFailure(email, userId, device, now)
+	return errors.New("No user with this credentials")
+}
+
+/*
+Wrong password and wrong two factor code share the counters,
+so the password does not give unlimited guesses of the code
+*/
+func (service *UserServiceImpl) countSignInFailure(email string, userId *int, device model.SessionDevice, now time.Time) {
+	service.recordSignInAttempt(email, userId, device, model.SignInWrongCredential)
+
+	throttle, err := service.SignInAttemptRepository.Fail(model.SignInThrottleAccount, model.NormalizeEmail(email), model.AccountThrottlePolicy, now)
+	if err != nil {
+		log.Errorf("[SignIn:5] Could not count the failed sign in of %s, reason: %s", email, err.Error())
+	} else if model.AccountThrottlePolicy.IsLockout(throttle.FailedCount) {
+		log.Warnf("Sign in of %s locked after %d failures, last from %s", email, throttle.FailedCount, device.IpAddress)
+	}
+
+	if device.IpAddress != "" {
+		_, err = service.SignInAttemptRepository.Fail(model.SignInThrottleIp, device.IpAddress, model.IpThrottlePolicy, now)
+		if err != nil {
+			log.Errorf("[SignIn:5] Could not count the failed sign in from %s, reason: %s", device.IpAddress, err.Error())
+		}
+	}
+}
+
+// Signed in, the failures before are forgiven. Not before the second factor when enabled
+func (service *UserServiceImpl) clearSignInFailures(user *model.User) {
+	err := service.SignInAttemptRepository.Clear(model.SignInThrottleAccount, model.NormalizeEmail(user.Email))
+	if err != nil {
+		log.Warnf("[SignIn:4] Could not clear the failed sign in of user %d, reason: %s", user.Id, err.Error())
+	}
+}
+
+// Audit only, a failure to write it does not change the sign in
+func (service *UserServiceImpl) recordSignInAttempt(email string, userId *int, device model.SessionDevice, result model.SignInAttemptResult) {
+	attempt := &model.SignInAttempt{
+		UserId:    userId,
+		Email:     model.NormalizeEmail(email),
+		IpAddress: device.IpAddress,
+		UserAgent: device.UserAgent,
+		Result:    result,
+	}
+	err := service.SignInAttemptRepository.Record(attempt)
+	if err != nil {
+		log.Errorf("[SignIn:6] Could not record the sign in attempt of %s, reason: %s", email, err.Error())
+	}
+}
+
+func signInThrottlePolicy(scope model.SignInThrottleScope) model.SignInThrottlePolicy {
+	if scope == model.SignInThrottleIp {
+		return model.IpThrottlePolicy
+	}
+	return model.AccountThrottlePolicy
+}
+
+// GetSignInAttempts implements UserService.
+func (service *UserServiceImpl) GetSignInAttempts(userId int, limit, page int) ([]*model.SignInAttempt, int, error) {
+	if limit < 1 {
+		return nil, 0, fmt.Errorf("limit could not less than 1 (limit >= 1). Given limit %d", limit)
+	}
+	if page < 1 {
+		return nil, 0, fmt.Errorf("page could not less than 1 (page >= 1). Given page %d", page)
+	}
+
+	return service.SignInAttemptRepository.GetByUser(userId, limit, page-1)
+}
+
+// UnlockSignIn implements UserService.
+func (service *UserServiceImpl) UnlockSignIn(tenantId int, userId int, sub int) error {
+	err := service.SignInAttemptRepository.UnlockMember(tenantId, sub, userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("User is not a member of this tenant")
+		}
+		return err
 	}
 
-	// Generate a jwt token
-	tokenExpiredTime := time.Hour * 24 * 30
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":        candidateUser.Id,
-		"email":      candidateUser.Email,
-		"name":       candidateUser.Name,
-		"uuid":       candidateUser.UserUuid,
-		"created_at": candidateUser.CreatedAt,
-		"exp":        time.Now().UTC().Add(tokenExpiredTime).Unix(),
+	log.Infof("Sign in of user %d unlocked by the owner %d of tenant %d", userId, sub, tenantId)
+	return nil
+}
+
+// SignInWithTwoFactor implements UserService.
+func (service *UserServiceImpl) SignInWithTwoFactor(challengeToken string, code string, device model.SessionDevice) (*model.User, *model.AuthTokens, error) {
+	now := time.Now()
+	challengeHash := token.Hash(challengeToken)
+
+	challenge, err := service.UserTokenRepository.FindValid(model.UserTokenTwoFactor, challengeHash, now)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserTokenInvalid) {
+			return nil, nil, ErrTwoFactorChallengeInvalid
+		}
+		return nil, nil, err
+	}
+
+	user, err := service.Repository.FindById(challenge.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A new challenge only cost the password, the throttle of the account limit the guesses of the code
+	blocking, err := service.SignInAttemptRepository.FindBlocking(user.Email, device.IpAddress, now)
+	if err != nil {
+		log.Errorf("[SignInWithTwoFactor:2] Could not check the failed sign in of user %d, reason: %s", user.Id, err.Error())
+		return nil, nil, errors.New("Something gone wrong here ! Could not signed in user")
+	}
+	if blocking != nil {
+		service.recordSignInAttempt(user.Email, &user.Id, device, model.SignInBlocked)
+		return nil, nil, &SignInBlockedError{
+			RetryAt: *blocking.BlockedUntil,
+			Locked:  signInThrottlePolicy(blocking.Scope).IsLockout(blocking.FailedCount),
+		}
+	}
+
+	ok, err := verifySecondFactor(service.TwoFactorRepository, user, code, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		err = service.UserTokenRepository.FailAttempt(challenge.Id, model.TwoFactorChallengeMaxAttempts)
+		if err != nil {
+			log.Errorf("[SignInWithTwoFactor:1] Could not count the failed attempt of challenge %d, reason: %s", challenge.Id, err.Error())
+		}
+		service.countSignInFailure(user.Email, &user.Id, device, now)
+		return nil, nil, ErrTwoFactorCodeInvalid
+	}
+
+	// Only 1 of 2 concurrent answers start a session
+	_, err = service.UserTokenRepository.Consume(model.UserTokenTwoFactor, challengeHash, now)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserTokenInvalid) {
+			return nil, nil, ErrTwoFactorChallengeInvalid
+		}
+		return nil, nil, err
+	}
+
+	service.clearSignInFailures(user)
+	return service.startSession(user, device)
+}
+
+// Create the session of the signed in user and issue its tokens
+func (service *UserServiceImpl) startSession(user *model.User, device model.SessionDevice) (*model.User, *model.AuthTokens, error) {
+	refreshToken, err := token.New(refreshTokenSize)
+	if err != nil {
+		return nil, nil, errors.New("Failed to create token")
+	}
+
+	now := time.Now()
+	newSession := &model.UserSession{
+		UserId:     user.Id,
+		LastSeenAt: &now,
+		ExpiresAt:  now.Add(refreshTokenLifetime),
+	}
+	device.Apply(newSession)
+
+	session, err := service.SessionRepository.Create(newSession, token.Hash(refreshToken))
+	if err != nil {
+		log.Errorf("[SignIn:1] Could not create session for user %d, reason: %s", user.Id, err.Error())
+		return nil, nil, errors.New("Something gone wrong here ! Could not signed in user")
+	}
+
+	return service.issueTokens(user, session, refreshToken, now)
+}
+
+// The password is checked, the client answer the challenge with the TOTP code
+func (service *UserServiceImpl) createTwoFactorChallenge(user *model.User) (*model.TwoFactorChallenge, error) {
+	plainToken, err := token.New(userTokenSize)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(model.UserTokenTwoFactor.Lifetime())
+	_, err = service.UserTokenRepository.Create(&model.UserToken{
+		UserId:    user.Id,
+		Purpose:   model.UserTokenTwoFactor,
+		TokenHash: token.Hash(plainToken),
+		ExpiresAt: expiresAt,
 	})
-	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_S")))
 	if err != nil {
-		return nil, "", errors.New("Failed to create token")
+		return nil, err
+	}
+
+	return &model.TwoFactorChallenge{Token: plainToken, ExpiresAt: expiresAt}, nil
+}
+
+// Refresh implements UserService.
+func (service *UserServiceImpl) Refresh(refreshToken string) (*model.User, *model.AuthTokens, error) {
+	if refreshToken == "" {
+		return nil, nil, repository.ErrRefreshTokenInvalid
+	}
+
+	newRefreshToken, err := token.New(refreshTokenSize)
+	if err != nil {
+		return nil, nil, errors.New("Failed to create token")
 	}
 
-	loggedInUser := &model.User{
-		Id:        candidateUser.Id,
-		Name:      candidateUser.Name,
-		Email:     candidateUser.Email,
-		UserUuid:  candidateUser.UserUuid,
-		CreatedAt: candidateUser.CreatedAt,
+	now := time.Now()
+	session, err := service.SessionRepository.Rotate(token.Hash(refreshToken), token.Hash(newRefreshToken), now, now.Add(refreshTokenLifetime))
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenReused) {
+			log.Warn("Refresh token reused, session revoked")
+		}
+		return nil, nil, err
 	}
 
-	return loggedInUser, tokenString, nil
+	user, err := service.Repository.FindById(session.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.issueTokens(user, session, newRefreshToken, now)
+}
+
+// SignOut implements UserService.
+func (service *UserServiceImpl) SignOut(refreshToken string, sessionId int) error {
+	if refreshToken != "" {
+		session, err := service.SessionRepository.FindByRefreshToken(token.Hash(refreshToken))
+		if err != nil {
+			return err
+		}
+		sessionId = session.Id
+	}
+	if sessionId < 1 {
+		return errors.New("No session to sign out")
+	}
+
+	return service.SessionRepository.Revoke(sessionId, model.SessionRevokeSignOut)
+}
+
+// GetSessions implements UserService.
+func (service *UserServiceImpl) GetSessions(userId int, currentSessionId int) ([]model.UserSession, error) {
+	sessions, err := service.SessionRepository.FindActiveByUser(userId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].IsCurrent = sessions[i].Id == currentSessionId
+	}
+	return sessions, nil
+}
+
+// RevokeSession implements UserService.
+func (service *UserServiceImpl) RevokeSession(userId int, sessionId int) error {
+	if sessionId < 1 {
+		return errors.New("Invalid session_id")
+	}
+
+	return service.SessionRepository.RevokeOfUser(userId, sessionId, model.SessionRevokeByUser)
+}
+
+// RevokeAllSessions implements UserService.
+func (service *UserServiceImpl) RevokeAllSessions(userId int, currentSessionId int, includeCurrent bool) (int, error) {
+	exceptSessionId := currentSessionId
+	if includeCurrent {
+		exceptSessionId = 0
+	}
+
+	return service.SessionRepository.RevokeAllOfUser(userId, exceptSessionId, model.SessionRevokeByUser)
+}
+
+// RequestPasswordReset implements UserService.
+func (service *UserServiceImpl) RequestPasswordReset(email string) error {
+	var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	if !emailRegex.MatchString(email) {
+		return errors.New("Could not reset password. Check input email")
+	}
+
+	user, err := service.Repository.GetByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Debugf("Password reset requested for unknown email %s", email)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = service.sendUserToken(user, model.UserTokenPasswordReset)
+	if err != nil {
+		log.Errorf("[RequestPasswordReset:1] Could not send the reset email to user %d, reason: %s", user.Id, err.Error())
+		return errors.New("Something gone wrong here ! Could not send the reset email")
+	}
+
+	return nil
+}
+
+// ResetPassword implements UserService.
+func (service *UserServiceImpl) ResetPassword(resetToken string, newPassword string) error {
+	if len(newPassword) < 8 {
+		return errors.New("Could not reset password. Check input password")
+	}
+
+	userToken, err := service.UserTokenRepository.Consume(model.UserTokenPasswordReset, token.Hash(resetToken), time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = service.updatePassword(userToken.UserId, newPassword)
+	if err != nil {
+		return err
+	}
+
+	// The emailed link prove the owner of the account, a lockout is over
+	user, err := service.Repository.FindById(userToken.UserId)
+	if err != nil {
+		return err
+	}
+	err = service.SignInAttemptRepository.Clear(model.SignInThrottleAccount, model.NormalizeEmail(user.Email))
+	if err != nil {
+		log.Warnf("[ResetPassword:1] Could not clear the failed sign in of user %d, reason: %s", user.Id, err.Error())
+	}
+
+	// Whoever had the old password is signed out
+	_, err = service.SessionRepository.RevokeAllOfUser(userToken.UserId, 0, model.SessionRevokePasswordReset)
+	return err
+}
+
+// ChangePassword implements UserService.
+func (service *UserServiceImpl) ChangePassword(userId int, currentSessionId int, oldPassword string, newPassword string) error {
+	if len(newPassword) < 8 {
+		return errors.New("Could not change password. Check input new password")
+	} else if oldPassword == newPassword {
+		return errors.New("Could not change password. New password must be different")
+	}
+
+	user, err := service.Repository.FindById(userId)
+	if err != nil {
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword))
+	if err != nil {
+		return errors.New("Old password is wrong")
+	}
+
+	err = service.updatePassword(userId, newPassword)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.SessionRepository.RevokeAllOfUser(userId, currentSessionId, model.SessionRevokePasswordReset)
+	return err
+}
+
+// SendEmailVerification implements UserService.
+func (service *UserServiceImpl) SendEmailVerification(userId int) error {
+	user, err := service.Repository.FindById(userId)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return errors.New("Email is already verified")
+	}
+
+	err = service.sendUserToken(user, model.UserTokenEmailVerification)
+	if err != nil {
+		log.Errorf("[SendEmailVerification:1] Could not send the verification email to user %d, reason: %s", user.Id, err.Error())
+		return errors.New("Something gone wrong here ! Could not send the verification email")
+	}
+
+	return nil
+}
+
+// VerifyEmail implements UserService.
+func (service *UserServiceImpl) VerifyEmail(verifyToken string) error {
+	now := time.Now()
+	userToken, err := service.UserTokenRepository.Consume(model.UserTokenEmailVerification, token.Hash(verifyToken), now)
+	if err != nil {
+		return err
+	}
+
+	return service.Repository.MarkEmailVerified(userToken.UserId, now)
+}
+
+func (service *UserServiceImpl) updatePassword(userId int, newPassword string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), 10)
+	if err != nil {
+		log.Errorf("[updatePassword:1] Could not hash the password of user %d, reason: %s", userId, err.Error())
+		return errors.New("Something gone wrong here ! Could not update the password")
+	}
+
+	return service.Repository.UpdatePassword(userId, string(hashedPassword))
+}
+
+// Issue a single use token of the purpose and email its link to the user
+func (service *UserServiceImpl) sendUserToken(user *model.User, purpose model.UserTokenPurpose) error {
+	plainToken, err := token.New(userTokenSize)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.UserTokenRepository.Create(&model.UserToken{
+		UserId:    user.Id,
+		Purpose:   purpose,
+		TokenHash: token.Hash(plainToken),
+		ExpiresAt: time.Now().Add(purpose.Lifetime()),
+	})
+	if err != nil {
+		return err
+	}
+
+	return service.Mailer.Send(userTokenMessage(user, purpose, plainToken, service.AppUrl))
+}
+
+func userTokenMessage(user *model.User, purpose model.UserTokenPurpose, plainToken string, appUrl string) *mail.Message {
+	subject, action, path := "Reset your password", "reset your password", "/reset_password"
+	if purpose == model.UserTokenEmailVerification {
+		subject, action, path = "Verify your email", "verify your email", "/verify_email"
+	}
+
+	link := plainToken
+	if appUrl != "" {
+		link = appUrl + path + "?token=" + url.QueryEscape(plainToken)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", user.Name)
+	fmt.Fprintf(&body, "Use this to %s, it can be used once and expire in %d hour(s):\n\n", action, int(purpose.Lifetime().Hours()))
+	fmt.Fprintf(&body, "%s\n\n", link)
+	body.WriteString("If you did not ask for it, you can ignore this email.\n")
+
+	return &mail.Message{
+		To:      []string{user.Email},
+		Subject: subject,
+		Body:    body.String(),
+	}
+}
+
+// DeleteExpiredRefreshTokens implements UserService.
+func (service *UserServiceImpl) DeleteExpiredRefreshTokens(now time.Time) (int, error) {
+	deleted, err := service.SessionRepository.DeleteExpiredTokens(now.Add(-time.Hour * 24))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		log.Infof("Deleted %d refresh tokens of expired sessions", deleted)
+	}
+	return deleted, nil
+}
+
+// Sign the access token of the session, it carry the session id (sid claim) so ProtectedRoute can refuse it once revoked
+func (service *UserServiceImpl) issueTokens(user *model.User, session *model.UserSession, refreshToken string, now time.Time) (*model.User, *model.AuthTokens, error) {
+	accessTokenExpiresAt := now.Add(accessTokenLifetime)
+	tokenString, err := signAccessToken(user, session.Id, accessTokenExpiresAt)
+	if err != nil {
+		return nil, nil, errors.New("Failed to create token")
+	}
+
+	return publicUser(user), &model.AuthTokens{
+		AccessToken:           tokenString,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// The JWT read by ProtectedRoute, sub is the user id and sid the session id
+func signAccessToken(user *model.User, sessionId int, expiresAt time.Time) (string, error) {
+	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":        user.Id,
+		"sid":        sessionId,
+		"email":      user.Email,
+		"name":       user.Name,
+		"uuid":       user.UserUuid,
+		"created_at": user.CreatedAt,
+		"exp":        expiresAt.UTC().Unix(),
+	})
+	return accessToken.SignedString([]byte(os.Getenv("JWT_S")))
+}
+
+// Only the fields safe to return after sign in
+func publicUser(user *model.User) *model.User {
+	return &model.User{
+		Id:        user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		UserUuid:  user.UserUuid,
+		CreatedAt: user.CreatedAt,
+	}
 }
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"cashier-api/helper/mail"
+	"cashier-api/helper/token"
+	"cashier-api/helper/totp"
 	"cashier-api/model"
 	"cashier-api/repository"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestUserServiceImpl(t *testing.T) {
@@ -21,7 +26,12 @@ func TestUserServiceImpl(t *testing.T) {
 
 	t.Run("SignUpWithEmailAndPassword", func(t *testing.T) {
 		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
-		var userService = NewUserServiceImpl(userRepo)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+		userTokenRepo.Mock.On("Create", mock.AnythingOfType("*model.UserToken")).Return(&model.UserToken{}, nil).Maybe()
 
 		t.Run("NormalSignUp", func(t *testing.T) {
 			now := time.Now()
@@ -86,7 +96,12 @@ func TestUserServiceImpl(t *testing.T) {
 
 	t.Run("SignInWithEmailAndPassword", func(t *testing.T) {
 		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
-		var userService = NewUserServiceImpl(userRepo)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+		userTokenRepo.Mock.On("Create", mock.AnythingOfType("*model.UserToken")).Return(&model.UserToken{}, nil).Maybe()
 
 		t.Run("NormalSignIn", func(t *testing.T) {
 			now := time.Now()
@@ -119,10 +134,24 @@ func TestUserServiceImpl(t *testing.T) {
 			}
 
 			userRepo.Mock.On("GetByEmail", createdDummyUser.Email).Return(expectedDummyUserForLogin, nil)
-			user, tokenString, err := userService.SignInWithEmailAndPassword(createdDummyUser.Email, dummyPassword)
+			sessionMatcher := mock.MatchedBy(func(session *model.UserSession) bool {
+				return session.UserId == createdDummyUser.Id && session.DeviceName == "Cashier tablet" && session.IpAddress == "10.0.0.1"
+			})
+			sessionRepo.Mock.On("Create", sessionMatcher, mock.AnythingOfType("string")).
+				Return(&model.UserSession{Id: 7, UserId: createdDummyUser.Id, ExpiresAt: now.Add(refreshTokenLifetime)}, nil).
+				Once()
+			device := model.SessionDevice{DeviceName: " Cashier tablet ", UserAgent: "Mozilla/5.0", IpAddress: "10.0.0.1"}
+			user, tokens, err := userService.SignInWithEmailAndPassword(createdDummyUser.Email, dummyPassword, device)
 			assert.Nil(t, err)
 			assert.NotNil(t, user)
+			require.NotNil(t, tokens)
+			assert.NotEqual(t, "", tokens.RefreshToken)
+			assert.WithinDuration(t, now.Add(accessTokenLifetime), tokens.AccessTokenExpiresAt, time.Minute)
+			tokenString := tokens.AccessToken
 			assert.NotEqual(t, "", tokenString)
+
+			// Only the hash of the refresh token is stored
+			sessionRepo.Mock.AssertCalled(t, "Create", mock.AnythingOfType("*model.UserSession"), token.Hash(tokens.RefreshToken))
 			assert.Equal(t, createdDummyUser.Id, user.Id)
 			assert.Equal(t, createdDummyUser.Name, user.Name)
 			assert.Equal(t, createdDummyUser.Email, user.Email)
@@ -146,6 +175,10 @@ func TestUserServiceImpl(t *testing.T) {
 			assert.NotNil(t, token, "If this fail then failed to parse JWT")
 			assert.True(t, token.Valid)
 
+			sid, ok := claims["sid"].(float64)
+			assert.True(t, ok)
+			assert.Equal(t, 7, int(sid))
+
 			for key, val := range claims {
 				// fmt.Printf("%s: %v\n", key, val)
 				switch key {
@@ -167,6 +200,7 @@ func TestUserServiceImpl(t *testing.T) {
 					createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
 					assert.Nil(t, err)
 					assert.NotNil(t, createdAt)
+				case "sid":
 				case "exp":
 					exp, ok := val.(float64)
 					assert.True(t, ok)
@@ -179,4 +213,386 @@ func TestUserServiceImpl(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+		userTokenRepo.Mock.On("Create", mock.AnythingOfType("*model.UserToken")).Return(&model.UserToken{}, nil).Maybe()
+
+		t.Run("Rotated", func(t *testing.T) {
+			expiresAt := time.Now().Add(refreshTokenLifetime)
+			sessionRepo.Mock.On("Rotate", token.Hash("old-refresh-token"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+				Return(&model.UserSession{Id: 3, UserId: 9, ExpiresAt: expiresAt}, nil).
+				Once()
+			userRepo.Mock.On("FindById", 9).Return(&model.User{Id: 9, Name: "Refresh", Email: "refresh@gmail.com"}, nil).Once()
+
+			user, tokens, err := userService.Refresh("old-refresh-token")
+			require.Nil(t, err)
+			assert.Equal(t, 9, user.Id)
+			assert.NotEqual(t, "old-refresh-token", tokens.RefreshToken)
+			assert.Equal(t, expiresAt, tokens.RefreshTokenExpiresAt)
+
+			// The new token is the one stored by the rotation
+			sessionRepo.Mock.AssertCalled(t, "Rotate", token.Hash("old-refresh-token"), token.Hash(tokens.RefreshToken), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"))
+		})
+
+		t.Run("Reused", func(t *testing.T) {
+			sessionRepo.Mock.On("Rotate", token.Hash("reused-refresh-token"), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+				Return(nil, repository.ErrRefreshTokenReused).
+				Once()
+
+			user, tokens, err := userService.Refresh("reused-refresh-token")
+			assert.Nil(t, user)
+			assert.Nil(t, tokens)
+			assert.ErrorIs(t, err, repository.ErrRefreshTokenReused)
+		})
+
+		t.Run("Empty", func(t *testing.T) {
+			_, _, err := userService.Refresh("")
+			assert.ErrorIs(t, err, repository.ErrRefreshTokenInvalid)
+		})
+	})
+
+	t.Run("SignOut", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+		userTokenRepo.Mock.On("Create", mock.AnythingOfType("*model.UserToken")).Return(&model.UserToken{}, nil).Maybe()
+
+		sessionRepo.Mock.On("FindByRefreshToken", token.Hash("refresh-token")).Return(&model.UserSession{Id: 5}, nil).Once()
+		sessionRepo.Mock.On("Revoke", 5, model.SessionRevokeSignOut).Return(nil).Once()
+		assert.Nil(t, userService.SignOut("refresh-token", 0))
+
+		// Without refresh token the sid of the access token is used
+		sessionRepo.Mock.On("Revoke", 6, model.SessionRevokeSignOut).Return(nil).Once()
+		assert.Nil(t, userService.SignOut("", 6))
+
+		assert.NotNil(t, userService.SignOut("", 0))
+		sessionRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("Sessions", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+		userTokenRepo.Mock.On("Create", mock.AnythingOfType("*model.UserToken")).Return(&model.UserToken{}, nil).Maybe()
+
+		sessionRepo.Mock.On("FindActiveByUser", 1, mock.AnythingOfType("time.Time")).
+			Return([]model.UserSession{{Id: 2, UserId: 1}, {Id: 3, UserId: 1}}, nil).
+			Once()
+		sessions, err := userService.GetSessions(1, 3)
+		require.Nil(t, err)
+		require.Len(t, sessions, 2)
+		assert.False(t, sessions[0].IsCurrent)
+		assert.True(t, sessions[1].IsCurrent)
+
+		sessionRepo.Mock.On("RevokeOfUser", 1, 2, model.SessionRevokeByUser).Return(nil).Once()
+		assert.Nil(t, userService.RevokeSession(1, 2))
+		assert.NotNil(t, userService.RevokeSession(1, 0))
+
+		// The current session is kept unless asked
+		sessionRepo.Mock.On("RevokeAllOfUser", 1, 3, model.SessionRevokeByUser).Return(1, nil).Once()
+		revoked, err := userService.RevokeAllSessions(1, 3, false)
+		require.Nil(t, err)
+		assert.Equal(t, 1, revoked)
+
+		sessionRepo.Mock.On("RevokeAllOfUser", 1, 0, model.SessionRevokeByUser).Return(2, nil).Once()
+		revoked, err = userService.RevokeAllSessions(1, 3, true)
+		require.Nil(t, err)
+		assert.Equal(t, 2, revoked)
+
+		sessionRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("PasswordReset", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var mailer = mail.NewMemoryMailer()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mailer).(*UserServiceImpl)
+		userService.AppUrl = "https://pos.example.com"
+
+		t.Run("UnknownEmail", func(t *testing.T) {
+			userRepo.Mock.On("GetByEmail", "nobody@gmail.com").Return(nil, gorm.ErrRecordNotFound).Once()
+			assert.Nil(t, userService.RequestPasswordReset("nobody@gmail.com"))
+			assert.Nil(t, mailer.Last())
+		})
+
+		t.Run("LinkSentAndUsed", func(t *testing.T) {
+			userRepo.Mock.On("GetByEmail", "reset@gmail.com").Return(&model.User{Id: 4, Name: "Reset", Email: "reset@gmail.com"}, nil).Once()
+			userTokenRepo.Mock.On("Create", mock.MatchedBy(func(userToken *model.UserToken) bool {
+				return userToken.UserId == 4 && userToken.Purpose == model.UserTokenPasswordReset && userToken.TokenHash != ""
+			})).Return(&model.UserToken{Id: 1}, nil).Once()
+
+			require.Nil(t, userService.RequestPasswordReset("reset@gmail.com"))
+			message := mailer.Last()
+			require.NotNil(t, message)
+			assert.Equal(t, []string{"reset@gmail.com"}, message.To)
+			assert.Contains(t, message.Body, "https://pos.example.com/reset_password?token=")
+
+			// The link carry the plain token, only its hash is stored
+			plainToken := message.Body[strings.Index(message.Body, "?token=")+len("?token="):]
+			plainToken = plainToken[:strings.Index(plainToken, "\n")]
+			storedToken := userTokenRepo.Mock.Calls[0].Arguments.Get(0).(*model.UserToken)
+			assert.Equal(t, token.Hash(plainToken), storedToken.TokenHash)
+
+			userTokenRepo.Mock.On("Consume", model.UserTokenPasswordReset, token.Hash(plainToken), mock.AnythingOfType("time.Time")).
+				Return(&model.UserToken{UserId: 4}, nil).Once()
+			userRepo.Mock.On("UpdatePassword", 4, mock.AnythingOfType("string")).Return(nil).Once()
+			sessionRepo.Mock.On("RevokeAllOfUser", 4, 0, model.SessionRevokePasswordReset).Return(2, nil).Once()
+			userRepo.Mock.On("FindById", 4).Return(&model.User{Id: 4, Email: "Reset@gmail.com"}, nil).Once()
+			assert.Nil(t, userService.ResetPassword(plainToken, "newpassword"))
+
+			// The reset unlock the sign in
+			signInAttemptRepo.Mock.AssertCalled(t, "Clear", model.SignInThrottleAccount, "reset@gmail.com")
+
+			// Too short password does not consume the token
+			assert.NotNil(t, userService.ResetPassword(plainToken, "short"))
+		})
+
+		t.Run("InvalidToken", func(t *testing.T) {
+			userTokenRepo.Mock.On("Consume", model.UserTokenPasswordReset, token.Hash("used"), mock.AnythingOfType("time.Time")).
+				Return(nil, repository.ErrUserTokenInvalid).Once()
+			assert.ErrorIs(t, userService.ResetPassword("used", "newpassword"), repository.ErrUserTokenInvalid)
+		})
+	})
+
+	t.Run("ChangePassword", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+
+		// Hash of 12345678
+		hashedPassword := "$2a$10$BhhTb567SYl3CEZw.s9MlOsZCswa3/UdzTcGQcaU6zrbRMIbDiFiK"
+		userRepo.Mock.On("FindById", 5).Return(&model.User{Id: 5, Password: hashedPassword}, nil)
+
+		assert.EqualError(t, userService.ChangePassword(5, 9, "wrongpassword", "newpassword"), "Old password is wrong")
+		assert.NotNil(t, userService.ChangePassword(5, 9, "12345678", "12345678"))
+
+		userRepo.Mock.On("UpdatePassword", 5, mock.AnythingOfType("string")).Return(nil).Once()
+		sessionRepo.Mock.On("RevokeAllOfUser", 5, 9, model.SessionRevokePasswordReset).Return(1, nil).Once()
+		assert.Nil(t, userService.ChangePassword(5, 9, "12345678", "newpassword"))
+		sessionRepo.Mock.AssertExpectations(t)
+	})
+
+	t.Run("EmailVerification", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var mailer = mail.NewMemoryMailer()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mailer).(*UserServiceImpl)
+
+		t.Run("SignInBlocked", func(t *testing.T) {
+			userService.RequireVerifiedEmail = true
+			defer func() { userService.RequireVerifiedEmail = false }()
+
+			hashedPassword := "$2a$10$BhhTb567SYl3CEZw.s9MlOsZCswa3/UdzTcGQcaU6zrbRMIbDiFiK"
+			userRepo.Mock.On("GetByEmail", "unverified@gmail.com").Return(&model.User{Id: 6, Password: hashedPassword}, nil).Once()
+			user, tokens, err := userService.SignInWithEmailAndPassword("unverified@gmail.com", "12345678", model.SessionDevice{})
+			assert.Nil(t, user)
+			assert.Nil(t, tokens)
+			assert.ErrorIs(t, err, ErrEmailNotVerified)
+		})
+
+		t.Run("SendAndVerify", func(t *testing.T) {
+			userRepo.Mock.On("FindById", 6).Return(&model.User{Id: 6, Email: "unverified@gmail.com"}, nil).Once()
+			userTokenRepo.Mock.On("Create", mock.AnythingOfType("*model.UserToken")).Return(&model.UserToken{}, nil).Once()
+			require.Nil(t, userService.SendEmailVerification(6))
+			require.NotNil(t, mailer.Last())
+			assert.Equal(t, "Verify your email", mailer.Last().Subject)
+
+			userTokenRepo.Mock.On("Consume", model.UserTokenEmailVerification, token.Hash("verify-token"), mock.AnythingOfType("time.Time")).
+				Return(&model.UserToken{UserId: 6}, nil).Once()
+			userRepo.Mock.On("MarkEmailVerified", 6, mock.AnythingOfType("time.Time")).Return(nil).Once()
+			assert.Nil(t, userService.VerifyEmail("verify-token"))
+
+			verifiedAt := time.Now()
+			userRepo.Mock.On("FindById", 7).Return(&model.User{Id: 7, EmailVerifiedAt: &verifiedAt}, nil).Once()
+			assert.EqualError(t, userService.SendEmailVerification(7), "Email is already verified")
+		})
+	})
+
+	t.Run("SignInWithTwoFactor", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = newSignInAttemptRepositoryMock()
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+
+		secret, err := totp.GenerateSecret()
+		require.Nil(t, err)
+		enabledAt := time.Now()
+		hashedPassword := "$2a$10$BhhTb567SYl3CEZw.s9MlOsZCswa3/UdzTcGQcaU6zrbRMIbDiFiK"
+		twoFactorUser := &model.User{Id: 8, Email: "twofactor@gmail.com", Password: hashedPassword, TotpSecret: secret, TotpEnabledAt: &enabledAt}
+
+		// The password alone only give a challenge
+		userRepo.Mock.On("GetByEmail", "twofactor@gmail.com").Return(twoFactorUser, nil).Once()
+		userTokenRepo.Mock.On("Create", mock.MatchedBy(func(userToken *model.UserToken) bool {
+			return userToken.UserId == 8 && userToken.Purpose == model.UserTokenTwoFactor
+		})).Return(&model.UserToken{}, nil).Once()
+		user, tokens, err := userService.SignInWithEmailAndPassword("twofactor@gmail.com", "12345678", model.SessionDevice{})
+		assert.Nil(t, user)
+		assert.Nil(t, tokens)
+		var twoFactorRequired *TwoFactorRequiredError
+		require.ErrorAs(t, err, &twoFactorRequired)
+		challenge := twoFactorRequired.Challenge.Token
+		assert.NotEqual(t, "", challenge)
+		sessionRepo.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+		challengeHash := token.Hash(challenge)
+		userTokenRepo.Mock.On("FindValid", model.UserTokenTwoFactor, challengeHash, mock.AnythingOfType("time.Time")).
+			Return(&model.UserToken{Id: 3, UserId: 8}, nil)
+		userRepo.Mock.On("FindById", 8).Return(twoFactorUser, nil)
+
+		// Wrong code count an attempt
+		userTokenRepo.Mock.On("FailAttempt", 3, model.TwoFactorChallengeMaxAttempts).Return(nil).Once()
+		_, _, err = userService.SignInWithTwoFactor(challenge, "000000", model.SessionDevice{})
+		if err == nil {
+			t.Skip("000000 happen to be the current code")
+		}
+		assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+		userTokenRepo.Mock.AssertExpectations(t)
+
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		require.Nil(t, err)
+		twoFactorRepo.Mock.On("UseStep", 8, mock.AnythingOfType("int64")).Return(true, nil).Once()
+		userTokenRepo.Mock.On("Consume", model.UserTokenTwoFactor, challengeHash, mock.AnythingOfType("time.Time")).
+			Return(&model.UserToken{Id: 3, UserId: 8}, nil).Once()
+		sessionRepo.Mock.On("Create", mock.AnythingOfType("*model.UserSession"), mock.AnythingOfType("string")).
+			Return(&model.UserSession{Id: 11, UserId: 8, ExpiresAt: time.Now().Add(refreshTokenLifetime)}, nil).Once()
+		user, tokens, err = userService.SignInWithTwoFactor(challenge, code, model.SessionDevice{})
+		require.Nil(t, err)
+		assert.Equal(t, 8, user.Id)
+		assert.NotEqual(t, "", tokens.AccessToken)
+
+		// Expired or answered challenge
+		userTokenRepo.Mock.On("FindValid", model.UserTokenTwoFactor, token.Hash("used"), mock.AnythingOfType("time.Time")).
+			Return(nil, repository.ErrUserTokenInvalid).Once()
+		_, _, err = userService.SignInWithTwoFactor("used", code, model.SessionDevice{})
+		assert.ErrorIs(t, err, ErrTwoFactorChallengeInvalid)
+	})
+
+	t.Run("SignInThrottle", func(t *testing.T) {
+		var userRepo = repository.NewUserRepositoryMock(&mock.Mock{}).(*repository.UserRepositoryMock)
+		var sessionRepo = repository.NewSessionRepositoryMock(&mock.Mock{}).(*repository.SessionRepositoryMock)
+		var userTokenRepo = repository.NewUserTokenRepositoryMock(&mock.Mock{}).(*repository.UserTokenRepositoryMock)
+		var twoFactorRepo = repository.NewTwoFactorRepositoryMock(&mock.Mock{}).(*repository.TwoFactorRepositoryMock)
+		var signInAttemptRepo = repository.NewSignInAttemptRepositoryMock(&mock.Mock{}).(*repository.SignInAttemptRepositoryMock)
+		var userService = NewUserServiceImpl(userRepo, sessionRepo, userTokenRepo, twoFactorRepo, signInAttemptRepo, mail.NewMemoryMailer())
+		device := model.SessionDevice{IpAddress: "10.0.0.9"}
+		signInAttemptRepo.Mock.On("Record", mock.AnythingOfType("*model.SignInAttempt")).Return(nil)
+
+		t.Run("WrongPasswordCounted", func(t *testing.T) {
+			hashedPassword := "$2a$10$BhhTb567SYl3CEZw.s9MlOsZCswa3/UdzTcGQcaU6zrbRMIbDiFiK"
+			signInAttemptRepo.Mock.On("FindBlocking", "Cashier@gmail.com", "10.0.0.9", mock.AnythingOfType("time.Time")).Return(nil, nil).Once()
+			userRepo.Mock.On("GetByEmail", "Cashier@gmail.com").Return(&model.User{Id: 9, Password: hashedPassword}, nil).Once()
+			signInAttemptRepo.Mock.On("Fail", model.SignInThrottleAccount, "cashier@gmail.com", model.AccountThrottlePolicy, mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{FailedCount: 1}, nil).Once()
+			signInAttemptRepo.Mock.On("Fail", model.SignInThrottleIp, "10.0.0.9", model.IpThrottlePolicy, mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{FailedCount: 1}, nil).Once()
+
+			_, _, err := userService.SignInWithEmailAndPassword("Cashier@gmail.com", "wrongpassword", device)
+			assert.EqualError(t, err, "No user with this credentials")
+			signInAttemptRepo.Mock.AssertCalled(t, "Record", mock.MatchedBy(func(attempt *model.SignInAttempt) bool {
+				return attempt.Result == model.SignInWrongCredential && *attempt.UserId == 9 && attempt.IpAddress == "10.0.0.9"
+			}))
+		})
+
+		t.Run("UnknownEmailCounted", func(t *testing.T) {
+			signInAttemptRepo.Mock.On("FindBlocking", "nobody@gmail.com", "10.0.0.9", mock.AnythingOfType("time.Time")).Return(nil, nil).Once()
+			userRepo.Mock.On("GetByEmail", "nobody@gmail.com").Return(nil, gorm.ErrRecordNotFound).Once()
+			signInAttemptRepo.Mock.On("Fail", model.SignInThrottleAccount, "nobody@gmail.com", model.AccountThrottlePolicy, mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{FailedCount: 1}, nil).Once()
+			signInAttemptRepo.Mock.On("Fail", model.SignInThrottleIp, "10.0.0.9", model.IpThrottlePolicy, mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{FailedCount: 2}, nil).Once()
+
+			_, _, err := userService.SignInWithEmailAndPassword("nobody@gmail.com", "12345678", device)
+			assert.EqualError(t, err, "No user with this credentials")
+		})
+
+		t.Run("LockedRefusedBeforePassword", func(t *testing.T) {
+			blockedUntil := time.Now().Add(time.Minute * 20)
+			signInAttemptRepo.Mock.On("FindBlocking", "locked@gmail.com", "10.0.0.9", mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{Scope: model.SignInThrottleAccount, FailedCount: 10, BlockedUntil: &blockedUntil}, nil).Once()
+
+			_, _, err := userService.SignInWithEmailAndPassword("locked@gmail.com", "12345678", device)
+			var blocked *SignInBlockedError
+			require.ErrorAs(t, err, &blocked)
+			assert.True(t, blocked.Locked)
+			assert.Equal(t, blockedUntil, blocked.RetryAt)
+			userRepo.Mock.AssertNotCalled(t, "GetByEmail", "locked@gmail.com")
+		})
+
+		t.Run("TwoFactorCodeCounted", func(t *testing.T) {
+			enabledAt := time.Now()
+			twoFactorUser := &model.User{Id: 10, Email: "TwoFactor@gmail.com", TotpSecret: "JBSWY3DPEHPK3PXP", TotpEnabledAt: &enabledAt}
+			challengeHash := token.Hash("challenge")
+			userTokenRepo.Mock.On("FindValid", model.UserTokenTwoFactor, challengeHash, mock.AnythingOfType("time.Time")).
+				Return(&model.UserToken{Id: 4, UserId: 10}, nil)
+			userRepo.Mock.On("FindById", 10).Return(twoFactorUser, nil)
+
+			// A wrong code count for the account like a wrong password, a new challenge does not reset it
+			signInAttemptRepo.Mock.On("FindBlocking", "TwoFactor@gmail.com", "10.0.0.9", mock.AnythingOfType("time.Time")).Return(nil, nil).Once()
+			twoFactorRepo.Mock.On("UseRecoveryCode", 10, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(false, nil).Once()
+			userTokenRepo.Mock.On("FailAttempt", 4, model.TwoFactorChallengeMaxAttempts).Return(nil).Once()
+			signInAttemptRepo.Mock.On("Fail", model.SignInThrottleAccount, "twofactor@gmail.com", model.AccountThrottlePolicy, mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{FailedCount: 5}, nil).Once()
+			signInAttemptRepo.Mock.On("Fail", model.SignInThrottleIp, "10.0.0.9", model.IpThrottlePolicy, mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{FailedCount: 5}, nil).Once()
+
+			_, _, err := userService.SignInWithTwoFactor("challenge", "wrong-code", device)
+			assert.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+			signInAttemptRepo.Mock.AssertCalled(t, "Record", mock.MatchedBy(func(attempt *model.SignInAttempt) bool {
+				return attempt.Result == model.SignInWrongCredential && *attempt.UserId == 10
+			}))
+
+			// Blocked, the code is not even checked
+			blockedUntil := time.Now().Add(time.Minute * 5)
+			signInAttemptRepo.Mock.On("FindBlocking", "TwoFactor@gmail.com", "10.0.0.9", mock.AnythingOfType("time.Time")).
+				Return(&model.SignInThrottle{Scope: model.SignInThrottleAccount, FailedCount: 5, BlockedUntil: &blockedUntil}, nil).Once()
+
+			_, _, err = userService.SignInWithTwoFactor("challenge", "wrong-code", device)
+			var blocked *SignInBlockedError
+			require.ErrorAs(t, err, &blocked)
+			assert.Equal(t, blockedUntil, blocked.RetryAt)
+			twoFactorRepo.Mock.AssertNumberOfCalls(t, "UseRecoveryCode", 1)
+			signInAttemptRepo.Mock.AssertNotCalled(t, "Clear", mock.Anything, mock.Anything)
+		})
+
+		t.Run("Unlock", func(t *testing.T) {
+			signInAttemptRepo.Mock.On("UnlockMember", 1, 2, 9).Return(nil).Once()
+			assert.Nil(t, userService.UnlockSignIn(1, 9, 2))
+
+			signInAttemptRepo.Mock.On("UnlockMember", 1, 3, 9).Return(repository.ErrUnlockAccessDenied).Once()
+			assert.ErrorIs(t, userService.UnlockSignIn(1, 9, 3), repository.ErrUnlockAccessDenied)
+		})
+	})
+}
+
+// No failed sign in, every attempt is accepted
+func newSignInAttemptRepositoryMock() *repository.SignInAttemptRepositoryMock {
+	signInAttemptRepo := repository.NewSignInAttemptRepositoryMock(&mock.Mock{}).(*repository.SignInAttemptRepositoryMock)
+	signInAttemptRepo.Mock.On("FindBlocking", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	signInAttemptRepo.Mock.On("Record", mock.Anything).Return(nil).Maybe()
+	signInAttemptRepo.Mock.On("Clear", mock.Anything, mock.Anything).Return(nil).Maybe()
+	signInAttemptRepo.Mock.On("Fail", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&model.SignInThrottle{FailedCount: 1}, nil).Maybe()
+	return signInAttemptRepo
 }
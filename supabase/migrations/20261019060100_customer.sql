@@ -0,0 +1,19 @@
+-- Customer directory per tenant, sales may be linked to a customer
+
+CREATE TABLE IF NOT EXISTS customer (
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    phone      TEXT        NOT NULL, -- Normalized, digits with optional leading '+'
+    email      TEXT        NOT NULL DEFAULT '',
+    notes      TEXT        NOT NULL DEFAULT '',
+    tenant_id  BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT customer_tenant_id_phone_key UNIQUE (tenant_id, phone)
+);
+
+-- NULL is an anonymous sale
+ALTER TABLE order_item
+    ADD COLUMN IF NOT EXISTS customer_id BIGINT REFERENCES customer (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS order_item_customer_id_idx ON order_item (customer_id) WHERE customer_id IS NOT NULL;
@@ -0,0 +1,35 @@
+-- Loyalty points programme, 1 setting per tenant and an append only points ledger
+
+CREATE TABLE IF NOT EXISTS loyalty_setting (
+    id            BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id     BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    earn_amount   INTEGER     NOT NULL DEFAULT 0,
+    earn_points   INTEGER     NOT NULL DEFAULT 0,
+    rounding      TEXT        NOT NULL DEFAULT 'FLOOR' CHECK (rounding IN ('FLOOR', 'ROUND', 'CEIL')),
+    redeem_value  INTEGER     NOT NULL DEFAULT 0,
+    expiry_months INTEGER     NOT NULL DEFAULT 0, -- 0 never expire
+    is_active     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ,
+    CONSTRAINT loyalty_setting_tenant_id_key UNIQUE (tenant_id)
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+    id               BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id        BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    customer_id      BIGINT      NOT NULL REFERENCES customer (id) ON DELETE CASCADE,
+    order_item_id    BIGINT      REFERENCES order_item (id) ON DELETE SET NULL,
+    type             TEXT        NOT NULL CHECK (type IN ('EARN', 'REDEEM', 'EXPIRE', 'REVERSAL')),
+    points           INTEGER     NOT NULL, -- Signed, credit (+) or debit (-)
+    remaining_points INTEGER     NOT NULL DEFAULT 0, -- Credit only, consumed FIFO by the debits
+    expires_at       TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS loyalty_ledger_customer_id_idx ON loyalty_ledger (customer_id, tenant_id);
+CREATE INDEX IF NOT EXISTS loyalty_ledger_order_item_id_idx ON loyalty_ledger (order_item_id) WHERE order_item_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS loyalty_ledger_expires_at_idx ON loyalty_ledger (expires_at) WHERE remaining_points > 0;
+
+-- Part of discount_amount paid by loyalty points, total_amount is already reduced by it
+ALTER TABLE order_item
+    ADD COLUMN IF NOT EXISTS points_discount INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,38 @@
+-- Gift cards and store credit share 1 table, every balance change is a movement
+
+CREATE TABLE IF NOT EXISTS gift_card (
+    id                 BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id          BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    code               TEXT        NOT NULL, -- Normalized, upper case without separator
+    type               TEXT        NOT NULL CHECK (type IN ('GIFT_CARD', 'STORE_CREDIT')),
+    status             TEXT        NOT NULL DEFAULT 'INACTIVE' CHECK (status IN ('INACTIVE', 'ACTIVE')),
+    initial_balance    INTEGER     NOT NULL DEFAULT 0,
+    balance            INTEGER     NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    customer_id        BIGINT      REFERENCES customer (id) ON DELETE SET NULL,
+    activated_store_id BIGINT      REFERENCES store (id) ON DELETE SET NULL,
+    activated_at       TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ,
+    CONSTRAINT gift_card_tenant_id_code_key UNIQUE (tenant_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS gift_card_movement (
+    id            BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id     BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    gift_card_id  BIGINT      NOT NULL REFERENCES gift_card (id) ON DELETE CASCADE,
+    type          TEXT        NOT NULL CHECK (type IN ('ACTIVATE', 'ISSUE', 'REDEEM', 'REVERSAL')),
+    amount        INTEGER     NOT NULL, -- Signed, credit (+) or debit (-)
+    balance_after INTEGER     NOT NULL,
+    order_item_id BIGINT      REFERENCES order_item (id) ON DELETE SET NULL,
+    store_id      BIGINT      REFERENCES store (id) ON DELETE SET NULL,
+    user_id       BIGINT      NOT NULL, -- Who moved it, kept when the user is deleted
+    note          TEXT        NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS gift_card_movement_gift_card_id_idx ON gift_card_movement (gift_card_id);
+CREATE INDEX IF NOT EXISTS gift_card_movement_order_item_id_idx ON gift_card_movement (order_item_id) WHERE order_item_id IS NOT NULL;
+
+-- Part of total_amount paid by gift card or store credit
+ALTER TABLE order_item
+    ADD COLUMN IF NOT EXISTS gift_card_amount INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,31 @@
+-- Parked orders, resumed later and converted into a sale
+
+CREATE TABLE IF NOT EXISTS parked_order (
+    id                BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id         BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    store_id          BIGINT      NOT NULL REFERENCES store (id) ON DELETE CASCADE,
+    customer_id       BIGINT      REFERENCES customer (id) ON DELETE SET NULL,
+    notes             TEXT        NOT NULL DEFAULT '',
+    status            TEXT        NOT NULL DEFAULT 'PARKED' CHECK (status IN ('PARKED', 'CONVERTED', 'CANCELLED', 'EXPIRED')),
+    version           INTEGER     NOT NULL DEFAULT 1, -- Optimistic lock
+    parked_by_user_id BIGINT      NOT NULL,
+    order_item_id     BIGINT      REFERENCES order_item (id) ON DELETE SET NULL, -- Sale it was converted into
+    expires_at        TIMESTAMPTZ NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS parked_order_tenant_id_store_id_idx ON parked_order (tenant_id, store_id, status);
+CREATE INDEX IF NOT EXISTS parked_order_expires_at_idx ON parked_order (expires_at) WHERE status = 'PARKED';
+
+CREATE TABLE IF NOT EXISTS parked_order_line (
+    id                   BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    parked_order_id      BIGINT  NOT NULL REFERENCES parked_order (id) ON DELETE CASCADE,
+    item_id              BIGINT  NOT NULL REFERENCES warehouse (item_id) ON DELETE CASCADE,
+    quantity             INTEGER NOT NULL CHECK (quantity > 0),
+    store_price_snapshot INTEGER NOT NULL DEFAULT 0,
+    discount_amount      INTEGER NOT NULL DEFAULT 0,
+    item_name_snapshot   TEXT    NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS parked_order_line_parked_order_id_idx ON parked_order_line (parked_order_id);
@@ -0,0 +1,19 @@
+-- Order lifecycle, only a COMPLETED order is a sale. Existing orders were sales, so they are COMPLETED
+
+ALTER TABLE order_item
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'COMPLETED'
+        CHECK (status IN ('PLACED', 'IN_PREPARATION', 'READY', 'COMPLETED', 'CANCELLED'));
+
+CREATE INDEX IF NOT EXISTS order_item_tenant_id_status_idx ON order_item (tenant_id, status, created_at);
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id            BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id     BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    order_item_id BIGINT      NOT NULL REFERENCES order_item (id) ON DELETE CASCADE,
+    from_status   TEXT        NOT NULL,
+    to_status     TEXT        NOT NULL,
+    user_id       BIGINT      NOT NULL, -- Who changed it, kept when the user is deleted
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_item_id_idx ON order_status_history (order_item_id);
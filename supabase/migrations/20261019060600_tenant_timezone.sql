@@ -0,0 +1,4 @@
+-- Sales series are bucketed in the tenant timezone, IANA name e.g. Asia/Jakarta. '' means UTC
+
+ALTER TABLE tenant
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Who voided (soft deleted) the invoice, for the cashier performance report
+
+ALTER TABLE order_item
+    ADD COLUMN IF NOT EXISTS voided_by_user_id BIGINT;
@@ -0,0 +1,42 @@
+-- Stored X and Z closing reports per store, append only
+
+CREATE TABLE IF NOT EXISTS closing_report (
+    id                BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id         BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    store_id          BIGINT      NOT NULL REFERENCES store (id) ON DELETE CASCADE,
+    type              TEXT        NOT NULL CHECK (type IN ('X', 'Z')),
+    z_number          INTEGER, -- NULL for X-report
+    business_date     TEXT        NOT NULL, -- YYYY-MM-DD at the tenant timezone
+    timezone          TEXT        NOT NULL DEFAULT '',
+    period_start      TIMESTAMPTZ NOT NULL,
+    period_end        TIMESTAMPTZ NOT NULL,
+
+    transaction_count INTEGER     NOT NULL DEFAULT 0,
+    total_quantity    INTEGER     NOT NULL DEFAULT 0,
+    subtotal          INTEGER     NOT NULL DEFAULT 0,
+    discount_amount   INTEGER     NOT NULL DEFAULT 0,
+    points_discount   INTEGER     NOT NULL DEFAULT 0,
+    total_amount      INTEGER     NOT NULL DEFAULT 0,
+    tax_amount        INTEGER     NOT NULL DEFAULT 0,
+    profit            INTEGER     NOT NULL DEFAULT 0,
+
+    cash_amount       INTEGER     NOT NULL DEFAULT 0,
+    cash_tendered     INTEGER     NOT NULL DEFAULT 0,
+    gift_card_amount  INTEGER     NOT NULL DEFAULT 0,
+
+    void_count        INTEGER     NOT NULL DEFAULT 0,
+    void_amount       INTEGER     NOT NULL DEFAULT 0,
+    refund_count      INTEGER     NOT NULL DEFAULT 0,
+    refund_amount     INTEGER     NOT NULL DEFAULT 0,
+
+    first_invoice_id  BIGINT,
+    last_invoice_id   BIGINT,
+
+    user_id           BIGINT      NOT NULL, -- Who printed the report, kept when the user is deleted
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT closing_report_z_number_check CHECK ((type = 'Z') = (z_number IS NOT NULL)),
+    -- Z-report is numbered per store, the same number is never given twice
+    CONSTRAINT closing_report_store_id_z_number_key UNIQUE (store_id, z_number)
+);
+
+CREATE INDEX IF NOT EXISTS closing_report_store_id_created_at_idx ON closing_report (store_id, created_at DESC);
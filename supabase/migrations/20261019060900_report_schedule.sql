@@ -0,0 +1,47 @@
+-- Scheduled report delivery by email or webhook, every delivery is a run with its retries
+
+CREATE TABLE IF NOT EXISTS report_schedule (
+    id                 BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id          BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    store_id           BIGINT      NOT NULL DEFAULT 0, -- 0 means all store
+    name               TEXT        NOT NULL,
+    resource           TEXT        NOT NULL,
+    format             TEXT        NOT NULL,
+    frequency          TEXT        NOT NULL CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY')),
+    time_of_day        TEXT        NOT NULL, -- HH:MM at the tenant timezone
+    day_of_week        INTEGER     NOT NULL DEFAULT 0 CHECK (day_of_week BETWEEN 0 AND 6),
+    day_of_month       INTEGER     NOT NULL DEFAULT 0 CHECK (day_of_month BETWEEN 0 AND 28),
+    channel            TEXT        NOT NULL CHECK (channel IN ('EMAIL', 'WEBHOOK')),
+    recipients         TEXT        NOT NULL DEFAULT '', -- EMAIL only, separated by comma
+    webhook_url        TEXT        NOT NULL DEFAULT '',
+    webhook_secret     TEXT        NOT NULL DEFAULT '', -- Optional, sign the webhook body
+    is_active          BOOLEAN     NOT NULL DEFAULT TRUE,
+    next_run_at        TIMESTAMPTZ,
+    last_run_at        TIMESTAMPTZ,
+    created_by_user_id BIGINT      NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS report_schedule_tenant_id_idx ON report_schedule (tenant_id);
+CREATE INDEX IF NOT EXISTS report_schedule_next_run_at_idx ON report_schedule (next_run_at) WHERE is_active;
+
+CREATE TABLE IF NOT EXISTS report_schedule_run (
+    id              BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    schedule_id     BIGINT      NOT NULL REFERENCES report_schedule (id) ON DELETE CASCADE,
+    tenant_id       BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    status          TEXT        NOT NULL CHECK (status IN ('RUNNING', 'SUCCESS', 'RETRYING', 'FAILED')),
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    scheduled_at    TIMESTAMPTZ NOT NULL,
+    period_start    TIMESTAMPTZ NOT NULL,
+    period_end      TIMESTAMPTZ NOT NULL,
+    timezone        TEXT        NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    file_name       TEXT        NOT NULL DEFAULT '',
+    finished_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS report_schedule_run_schedule_id_idx ON report_schedule_run (schedule_id, scheduled_at DESC);
+CREATE INDEX IF NOT EXISTS report_schedule_run_next_attempt_at_idx ON report_schedule_run (next_attempt_at) WHERE next_attempt_at IS NOT NULL;
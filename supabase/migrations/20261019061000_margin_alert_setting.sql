@@ -0,0 +1,14 @@
+-- Low margin alert of a tenant, 1 row per tenant
+
+CREATE TABLE IF NOT EXISTS margin_alert_setting (
+    id                 BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id          BIGINT           NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    min_margin_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    window_days        INTEGER          NOT NULL DEFAULT 0,
+    is_active          BOOLEAN          NOT NULL DEFAULT FALSE,
+    recipients         TEXT             NOT NULL DEFAULT '', -- Separated by comma
+    last_alerted_at    TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ      NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ,
+    CONSTRAINT margin_alert_setting_tenant_id_key UNIQUE (tenant_id)
+);
@@ -0,0 +1,24 @@
+-- Revocable sign in sessions, the refresh token is rotated on every use and only its hash is stored
+
+CREATE TABLE IF NOT EXISTS user_session (
+    id             BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id        BIGINT      NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    revoked_at     TIMESTAMPTZ,
+    revoked_reason TEXT        NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_session_user_id_idx ON user_session (user_id) WHERE revoked_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_refresh_token (
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    session_id BIGINT      NOT NULL REFERENCES user_session (id) ON DELETE CASCADE,
+    token_hash TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    rotated_at TIMESTAMPTZ, -- A rotated token used again revoke the session
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT user_refresh_token_token_hash_key UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS user_refresh_token_session_id_idx ON user_refresh_token (session_id);
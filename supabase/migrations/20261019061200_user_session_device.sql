@@ -0,0 +1,7 @@
+-- Device of the session, listed to the user so a lost device can be revoked
+
+ALTER TABLE user_session
+    ADD COLUMN IF NOT EXISTS device_name  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent   TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address   TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
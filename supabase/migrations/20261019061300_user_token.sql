@@ -0,0 +1,17 @@
+-- Single use tokens for the password reset and email verification links, only the hash is stored
+
+ALTER TABLE "user"
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ; -- NULL until the verification link is opened
+
+CREATE TABLE IF NOT EXISTS user_token (
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    purpose    TEXT        NOT NULL CHECK (purpose IN ('PASSWORD_RESET', 'EMAIL_VERIFICATION')),
+    token_hash TEXT        NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT user_token_token_hash_key UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS user_token_user_id_purpose_idx ON user_token (user_id, purpose) WHERE used_at IS NULL;
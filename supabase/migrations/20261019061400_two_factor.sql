@@ -0,0 +1,30 @@
+-- TOTP two factor authentication with single use recovery codes, and the tenant policy
+
+ALTER TABLE "user"
+    ADD COLUMN IF NOT EXISTS totp_secret     TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS totp_last_step  BIGINT NOT NULL DEFAULT 0; -- Last accepted time step, a code is used once
+
+CREATE TABLE IF NOT EXISTS user_recovery_code (
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    code_hash  TEXT        NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_recovery_code_user_id_idx ON user_recovery_code (user_id);
+
+-- Password checked, waiting for the TOTP code. Wrong answers are counted
+ALTER TABLE user_token
+    ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0,
+    DROP CONSTRAINT IF EXISTS user_token_purpose_check,
+    ADD CONSTRAINT user_token_purpose_check CHECK (purpose IN ('PASSWORD_RESET', 'EMAIL_VERIFICATION', 'TWO_FACTOR'));
+
+-- Owner and managers must enable TOTP to access the tenant
+ALTER TABLE tenant
+    ADD COLUMN IF NOT EXISTS require_two_factor BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- '' (members added before the roles) is a cashier
+ALTER TABLE user_mtm_tenant
+    ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT '' CHECK (role IN ('', 'MANAGER', 'CASHIER'));
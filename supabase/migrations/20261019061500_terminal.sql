@@ -0,0 +1,31 @@
+-- Shared terminals of a store and the cashier PIN, a PIN session only reach the terminal sales routes
+
+CREATE TABLE IF NOT EXISTS terminal (
+    id                 BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id          BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    store_id           BIGINT      NOT NULL REFERENCES store (id) ON DELETE CASCADE,
+    name               TEXT        NOT NULL,
+    token_hash         TEXT        NOT NULL,
+    created_by_user_id BIGINT      NOT NULL,
+    last_used_at       TIMESTAMPTZ,
+    revoked_at         TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT terminal_token_hash_key UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS terminal_tenant_id_idx ON terminal (tenant_id);
+
+CREATE TABLE IF NOT EXISTS tenant_member_pin (
+    id              BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id       BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    user_id         BIGINT      NOT NULL REFERENCES "user" (id) ON DELETE CASCADE,
+    pin_hash        TEXT        NOT NULL,
+    failed_attempts INTEGER     NOT NULL DEFAULT 0,
+    locked_until    TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ,
+    CONSTRAINT tenant_member_pin_tenant_id_user_id_key UNIQUE (tenant_id, user_id)
+);
+
+-- PIN sign in, the session is limited to its terminal
+ALTER TABLE user_session
+    ADD COLUMN IF NOT EXISTS terminal_id BIGINT REFERENCES terminal (id) ON DELETE CASCADE;
@@ -0,0 +1,23 @@
+-- Sign in attempt audit, and the throttle per account and per IP
+
+CREATE TABLE IF NOT EXISTS sign_in_attempt (
+    id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id    BIGINT      REFERENCES "user" (id) ON DELETE CASCADE, -- NULL for an unknown email
+    email      TEXT        NOT NULL,
+    ip_address TEXT        NOT NULL DEFAULT '',
+    user_agent TEXT        NOT NULL DEFAULT '',
+    result     TEXT        NOT NULL CHECK (result IN ('SUCCEEDED', 'WRONG_CREDENTIAL', 'BLOCKED', 'TWO_FACTOR_REQUIRED')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS sign_in_attempt_user_id_idx ON sign_in_attempt (user_id, created_at DESC) WHERE user_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS sign_in_throttle (
+    id             BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    scope          TEXT        NOT NULL CHECK (scope IN ('ACCOUNT', 'IP')),
+    key            TEXT        NOT NULL, -- Normalized email or IP address
+    failed_count   INTEGER     NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL,
+    blocked_until  TIMESTAMPTZ,
+    CONSTRAINT sign_in_throttle_scope_key_key UNIQUE (scope, key)
+);
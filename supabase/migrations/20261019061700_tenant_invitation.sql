@@ -0,0 +1,21 @@
+-- Email invitations to join a tenant, only the token hash is stored
+
+CREATE TABLE IF NOT EXISTS tenant_invitation (
+    id                  BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id           BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    email               TEXT        NOT NULL, -- Normalized
+    role                TEXT        NOT NULL CHECK (role IN ('MANAGER', 'CASHIER')),
+    token_hash          TEXT        NOT NULL,
+    invited_by_user_id  BIGINT      NOT NULL,
+    expires_at          TIMESTAMPTZ NOT NULL,
+    sent_at             TIMESTAMPTZ NOT NULL, -- Last time the email was sent
+    accepted_at         TIMESTAMPTZ,
+    accepted_by_user_id BIGINT,
+    revoked_at          TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT tenant_invitation_token_hash_key UNIQUE (token_hash)
+);
+
+-- Only the latest invitation of an email is pending
+CREATE UNIQUE INDEX IF NOT EXISTS tenant_invitation_pending_key ON tenant_invitation (tenant_id, email)
+    WHERE accepted_at IS NULL AND revoked_at IS NULL;
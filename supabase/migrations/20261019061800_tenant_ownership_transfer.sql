@@ -0,0 +1,21 @@
+-- Ownership transfer confirmed by the new owner, and tenant archival before the delayed hard delete
+
+ALTER TABLE tenant
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ; -- Hard deleted after the grace period
+
+CREATE INDEX IF NOT EXISTS tenant_archived_at_idx ON tenant (archived_at) WHERE archived_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS tenant_ownership_transfer (
+    id           BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id    BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    from_user_id BIGINT      NOT NULL,
+    to_user_id   BIGINT      NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    accepted_at  TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ, -- By the owner, or declined by the member
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- A new request cancel the pending one
+CREATE UNIQUE INDEX IF NOT EXISTS tenant_ownership_transfer_pending_key ON tenant_ownership_transfer (tenant_id)
+    WHERE accepted_at IS NULL AND cancelled_at IS NULL;
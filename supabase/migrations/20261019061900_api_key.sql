@@ -0,0 +1,18 @@
+-- API keys of a tenant for the integration routes, only the key hash is stored
+
+CREATE TABLE IF NOT EXISTS api_key (
+    id                 BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id          BIGINT      NOT NULL REFERENCES tenant (id) ON DELETE CASCADE,
+    name               TEXT        NOT NULL,
+    prefix             TEXT        NOT NULL, -- Start of the key, shown to tell the keys apart
+    key_hash           TEXT        NOT NULL,
+    scopes             TEXT        NOT NULL DEFAULT '', -- Separated by comma
+    created_by_user_id BIGINT      NOT NULL, -- The key act as this member
+    expires_at         TIMESTAMPTZ, -- NULL never expire
+    last_used_at       TIMESTAMPTZ,
+    revoked_at         TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT api_key_key_hash_key UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS api_key_tenant_id_idx ON api_key (tenant_id);
@@ -0,0 +1,33 @@
+-- Append only audit log of the mutating requests. It is kept when the tenant is deleted, so no foreign key
+
+CREATE TABLE IF NOT EXISTS audit_event (
+    id           BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    tenant_id    BIGINT, -- NULL for a request outside of a tenant
+    user_id      BIGINT      NOT NULL,
+    terminal_id  BIGINT, -- PIN sign in on a shared terminal
+    api_key_id   BIGINT,
+    method       TEXT        NOT NULL,
+    route        TEXT        NOT NULL, -- The route path, e.g. /api/v1/stores/:tenantId
+    status_code  INTEGER     NOT NULL,
+    entity_type  TEXT        NOT NULL DEFAULT '',
+    entity_id    BIGINT,
+    before_value TEXT        NOT NULL DEFAULT '', -- JSON document, '' when there is none
+    after_value  TEXT        NOT NULL DEFAULT '',
+    ip_address   TEXT        NOT NULL DEFAULT '',
+    request_id   TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_event_tenant_id_idx ON audit_event (tenant_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS audit_event_entity_idx ON audit_event (tenant_id, entity_type, entity_id);
+
+CREATE OR REPLACE FUNCTION audit_event_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_event is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_event_append_only ON audit_event;
+CREATE TRIGGER audit_event_append_only
+    BEFORE UPDATE OR DELETE ON audit_event
+    FOR EACH ROW EXECUTE FUNCTION audit_event_append_only();
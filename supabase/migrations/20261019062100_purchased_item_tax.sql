@@ -0,0 +1,4 @@
+-- Tax of the line, included in total_amount. The closing report sum it from the lines
+
+ALTER TABLE purchased_item_list
+    ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0;
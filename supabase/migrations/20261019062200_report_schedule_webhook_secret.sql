@@ -0,0 +1,7 @@
+-- The webhook secret is write only, NULL does not sign the webhook body
+
+ALTER TABLE report_schedule
+    ALTER COLUMN webhook_secret DROP NOT NULL,
+    ALTER COLUMN webhook_secret DROP DEFAULT;
+
+UPDATE report_schedule SET webhook_secret = NULL WHERE webhook_secret = '';
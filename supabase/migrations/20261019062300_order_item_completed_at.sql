@@ -0,0 +1,15 @@
+-- Time of the sale, a placed order is dated when it is COMPLETED. Reports are bucketed by it
+
+ALTER TABLE order_item
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ DEFAULT now(); -- transactions() create a COMPLETED sale
+
+UPDATE order_item oi
+SET completed_at = CASE
+    WHEN oi.status <> 'COMPLETED' THEN NULL
+    ELSE COALESCE((
+        SELECT MIN(h.created_at) FROM order_status_history h
+        WHERE h.order_item_id = oi.id AND h.to_status = 'COMPLETED'
+    ), oi.created_at)
+END;
+
+CREATE INDEX IF NOT EXISTS order_item_tenant_id_completed_at_idx ON order_item (tenant_id, completed_at) WHERE status = 'COMPLETED';
@@ -0,0 +1,4 @@
+-- Tax of the parked line, carried to purchased_item_list when the parked order is converted
+
+ALTER TABLE parked_order_line
+    ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0;